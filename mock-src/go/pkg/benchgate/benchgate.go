@@ -0,0 +1,107 @@
+// Package benchgate checks benchmarks against a committed baseline from
+// ordinary tests, so allocation regressions fail go test.
+//
+// Timings depend on the machine and are left to benchstat, but the
+// allocations a benchmark makes per operation do not, so they can be
+// gated on every run.
+package benchgate
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Baseline maps "pkg/Benchmark/sub" names, without the -N GOMAXPROCS
+// suffix, to the most allocations per operation any of their baseline
+// runs made.
+type Baseline map[string]int64
+
+// Load reads a baseline in the go test -bench format, as written with
+// -benchmem. Benchmarks are keyed by the package of the pkg: line
+// preceding them.
+func Load(path string) (Baseline, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	baseline := make(Baseline)
+	pkg := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "pkg:" {
+			pkg = fields[1]
+			continue
+		}
+		if len(fields) < 2 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		for i := 2; i < len(fields); i++ {
+			if fields[i] != "allocs/op" {
+				continue
+			}
+			allocs, err := strconv.ParseInt(fields[i-1], 10, 64)
+			if err != nil {
+				continue
+			}
+			key := pkg + "/" + trimProcs(fields[0])
+			if current, ok := baseline[key]; !ok || allocs > current {
+				baseline[key] = allocs
+			}
+		}
+	}
+	return baseline, scanner.Err()
+}
+
+// trimProcs drops the -N GOMAXPROCS suffix go test adds to names.
+func trimProcs(name string) string {
+	i := strings.LastIndex(name, "-")
+	if i < 0 {
+		return name
+	}
+	if _, err := strconv.Atoi(name[i+1:]); err != nil {
+		return name
+	}
+	return name[:i]
+}
+
+// Check runs each benchmark once through testing.Benchmark and fails t
+// for any that has no baseline or allocates more per operation than its
+// baseline allows. benchmarks is keyed by name as in the baseline, such
+// as "BenchmarkInMemoryTaskStore/Get", and pkg is their package's import
+// path. Check is skipped with -short.
+//
+// Averages vary a little with the number of iterations and with
+// scheduling in parallel benchmarks, so a benchmark may exceed its
+// baseline by 5%, or by one allocation if that is more.
+func Check(t *testing.T, path, pkg string, benchmarks map[string]func(*testing.B)) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping benchmark gate in short mode")
+	}
+	baseline, err := Load(path)
+	if err != nil {
+		t.Fatalf("loading baseline: %v", err)
+	}
+
+	for name, bench := range benchmarks {
+		want, ok := baseline[pkg+"/"+name]
+		if !ok {
+			t.Errorf("%s: no baseline in %s", name, path)
+			continue
+		}
+		result := testing.Benchmark(bench)
+		if result.N == 0 {
+			t.Errorf("%s: benchmark failed", name)
+			continue
+		}
+		if got := result.AllocsPerOp(); got > want+max(want/20, 1) {
+			t.Errorf("%s: %d allocs/op, baseline %d", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// Package encryption provides envelope encryption for sensitive fields
+// stored by the TaskTracker application.
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// prefix marks a value produced by Envelope.Encrypt.
+const prefix = "enc:v1:"
+
+// dataKeySize is the size in bytes of each generated data key.
+const dataKeySize = 32
+
+// ErrMalformedCiphertext is returned when a value cannot be parsed as an
+// encrypted envelope.
+var ErrMalformedCiphertext = errors.New("malformed ciphertext")
+
+// KMS wraps and unwraps data keys with a key-encryption key.
+//
+// Implementations may keep keys locally (see KeyfileKMS) or delegate to
+// an external key management service.
+type KMS interface {
+	// WrapKey encrypts a data key with the current key-encryption key
+	// and returns the ID of the key used.
+	WrapKey(ctx context.Context, dataKey []byte) (keyID string, wrapped []byte, err error)
+	// UnwrapKey decrypts a data key with the identified key-encryption key.
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+	// CurrentKeyID returns the ID of the key used for new wraps.
+	CurrentKeyID() string
+}
+
+// Envelope encrypts values with a fresh data key per value, storing the
+// data key wrapped by the KMS alongside the ciphertext.
+type Envelope struct {
+	kms KMS
+}
+
+// NewEnvelope creates a new envelope encrypter backed by kms.
+func NewEnvelope(kms KMS) *Envelope {
+	return &Envelope{kms: kms}
+}
+
+// IsEncrypted reports whether value was produced by Encrypt.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// Encrypt seals plaintext into a self-describing string.
+//
+// Empty strings are returned unchanged.
+func (e *Envelope) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+
+	sealed, err := seal(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+
+	keyID, wrapped, err := e.kms.WrapKey(ctx, dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawStdEncoding
+	return prefix + keyID + ":" + enc.EncodeToString(wrapped) + ":" + enc.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value produced by Encrypt.
+//
+// Values that are not encrypted are returned unchanged, so data written
+// before encryption was enabled remains readable.
+func (e *Envelope) Decrypt(ctx context.Context, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	keyID, wrapped, sealed, err := parse(value)
+	if err != nil {
+		return "", err
+	}
+
+	dataKey, err := e.kms.UnwrapKey(ctx, keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(dataKey, sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Rewrap re-encrypts the data key of value with the KMS's current key.
+//
+// The ciphertext itself is unchanged. Values that are already wrapped
+// with the current key, or not encrypted, are returned as is.
+func (e *Envelope) Rewrap(ctx context.Context, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	keyID, wrapped, sealed, err := parse(value)
+	if err != nil {
+		return "", err
+	}
+	if keyID == e.kms.CurrentKeyID() {
+		return value, nil
+	}
+
+	dataKey, err := e.kms.UnwrapKey(ctx, keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+
+	newKeyID, rewrapped, err := e.kms.WrapKey(ctx, dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawStdEncoding
+	return prefix + newKeyID + ":" + enc.EncodeToString(rewrapped) + ":" + enc.EncodeToString(sealed), nil
+}
+
+// parse splits an encrypted value into its key ID, wrapped key, and sealed data.
+func parse(value string) (string, []byte, []byte, error) {
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", nil, nil, ErrMalformedCiphertext
+	}
+
+	enc := base64.RawStdEncoding
+	wrapped, err := enc.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, nil, ErrMalformedCiphertext
+	}
+	sealed, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, ErrMalformedCiphertext
+	}
+	return parts[0], wrapped, sealed, nil
+}
+
+// seal encrypts plaintext with AES-GCM, prefixing the random nonce.
+func seal(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts data produced by seal.
+func open(key, sealed []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrMalformedCiphertext
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
@@ -0,0 +1,135 @@
+// Package encryption provides envelope encryption for sensitive fields
+// stored by the TaskTracker application.
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownKey is returned when a key ID is not present in the key set.
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// ErrInvalidKey is returned when a key is not 16, 24, or 32 bytes long,
+// or its ID is empty or contains a colon.
+var ErrInvalidKey = errors.New("invalid encryption key")
+
+// keyfile is the on-disk format read by LoadKeyfile.
+//
+// Keys are base64-encoded AES keys indexed by ID; Current names the key
+// used for new values.
+type keyfile struct {
+	Current string            `json:"current"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// KeyfileKMS is a KMS backed by a local set of AES key-encryption keys.
+//
+// Old keys stay in the set after rotation so existing values can still
+// be decrypted until they are rewrapped.
+type KeyfileKMS struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string][]byte
+}
+
+// NewKeyfileKMS creates a KMS with a single current key.
+func NewKeyfileKMS(keyID string, key []byte) (*KeyfileKMS, error) {
+	if !validKeyID(keyID) || !validKeySize(key) {
+		return nil, ErrInvalidKey
+	}
+	return &KeyfileKMS{current: keyID, keys: map[string][]byte{keyID: key}}, nil
+}
+
+// LoadKeyfile reads a JSON key file of the form
+// {"current": "k2", "keys": {"k1": "<base64>", "k2": "<base64>"}}.
+func LoadKeyfile(path string) (*KeyfileKMS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var kf keyfile
+	if err := json.Unmarshal(data, &kf); err != nil {
+		return nil, err
+	}
+
+	kms := &KeyfileKMS{current: kf.Current, keys: make(map[string][]byte, len(kf.Keys))}
+	for id, encoded := range kf.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || !validKeyID(id) || !validKeySize(key) {
+			return nil, ErrInvalidKey
+		}
+		kms.keys[id] = key
+	}
+	if _, ok := kms.keys[kf.Current]; !ok {
+		return nil, ErrUnknownKey
+	}
+	return kms, nil
+}
+
+// Rotate adds a new key and makes it current.
+func (k *KeyfileKMS) Rotate(keyID string, key []byte) error {
+	if !validKeyID(keyID) || !validKeySize(key) {
+		return ErrInvalidKey
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.keys[keyID] = key
+	k.current = keyID
+	return nil
+}
+
+// CurrentKeyID returns the ID of the key used for new wraps.
+func (k *KeyfileKMS) CurrentKeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.current
+}
+
+// WrapKey encrypts a data key with the current key.
+func (k *KeyfileKMS) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	k.mu.RLock()
+	id, key := k.current, k.keys[k.current]
+	k.mu.RUnlock()
+
+	wrapped, err := seal(key, dataKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return id, wrapped, nil
+}
+
+// UnwrapKey decrypts a data key with the identified key.
+func (k *KeyfileKMS) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	k.mu.RLock()
+	key, ok := k.keys[keyID]
+	k.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return open(key, wrapped)
+}
+
+// validKeySize reports whether key has a valid AES key length.
+func validKeySize(key []byte) bool {
+	switch len(key) {
+	case 16, 24, 32:
+		return true
+	}
+	return false
+}
+
+// validKeyID reports whether id can be embedded in an encrypted value.
+func validKeyID(id string) bool {
+	return id != "" && !strings.Contains(id, ":")
+}
@@ -0,0 +1,225 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccessLogFormat selects how access log lines are written.
+type AccessLogFormat string
+
+const (
+	// AccessLogCommon writes the Common Log Format, followed by the
+	// latency in microseconds and the request ID.
+	AccessLogCommon AccessLogFormat = "common"
+	// AccessLogJSON writes one JSON object per line.
+	AccessLogJSON AccessLogFormat = "json"
+)
+
+// maxRequestIDLength caps client-supplied X-Request-ID values.
+const maxRequestIDLength = 128
+
+// ErrUnknownLogFormat is returned for an unsupported access log format.
+var ErrUnknownLogFormat = errors.New("unknown access log format")
+
+// AccessLogEntry describes one served request.
+type AccessLogEntry struct {
+	Time       time.Time     `json:"time"`
+	RequestID  string        `json:"request_id"`
+	RemoteAddr string        `json:"remote_addr"`
+	UserID     string        `json:"user_id,omitempty"`
+	Method     string        `json:"method"`
+	Path       string        `json:"path"`
+	Proto      string        `json:"proto"`
+	Status     int           `json:"status"`
+	Bytes      int64         `json:"bytes"`
+	Latency    time.Duration `json:"latency_ns"`
+}
+
+// AccessLog writes a line for every request and records request
+// latency in a metrics collector, keyed by method and status class.
+//
+// Every request is given an ID, taken from a well-formed X-Request-ID
+// header or generated, which is echoed in the response and carried in
+// the request context. Logging can be switched off per route prefix,
+// for example for health checks; metrics are still recorded. Place it
+// inside authentication so the user is known.
+type AccessLog struct {
+	mu       sync.RWMutex
+	out      io.Writer
+	format   AccessLogFormat
+	metrics  *StoreMetrics
+	disabled map[string]bool
+	writeMu  sync.Mutex
+}
+
+// NewAccessLog creates an access log writing to out in the given format.
+// metrics may be nil.
+//
+// Returns ErrUnknownLogFormat for an unsupported format.
+func NewAccessLog(out io.Writer, format AccessLogFormat, metrics *StoreMetrics) (*AccessLog, error) {
+	if format != AccessLogCommon && format != AccessLogJSON {
+		return nil, ErrUnknownLogFormat
+	}
+	return &AccessLog{
+		out:      out,
+		format:   format,
+		metrics:  metrics,
+		disabled: make(map[string]bool),
+	}, nil
+}
+
+// SetEnabled turns logging on or off for paths starting with prefix.
+// The longest matching prefix wins.
+func (a *AccessLog) SetEnabled(prefix string, enabled bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.disabled[prefix] = !enabled
+}
+
+// enabled reports whether requests to path are logged.
+func (a *AccessLog) enabled(path string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	best, disabled := -1, false
+	for prefix, off := range a.disabled {
+		if len(prefix) > best && strings.HasPrefix(path, prefix) {
+			best, disabled = len(prefix), off
+		}
+	}
+	return !disabled
+}
+
+// Middleware logs each request once it has been served.
+func (a *AccessLog) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := r.Header.Get("X-Request-ID")
+		if !validRequestID(id) {
+			id = uuid.New().String()
+		}
+		w.Header().Set("X-Request-ID", id)
+
+		cw := &countingWriter{ResponseWriter: w}
+		next.ServeHTTP(cw, r.WithContext(WithRequestID(r.Context(), id)))
+
+		status := cw.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		entry := &AccessLogEntry{
+			Time:       start,
+			RequestID:  id,
+			RemoteAddr: r.RemoteAddr,
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			Proto:      r.Proto,
+			Status:     status,
+			Bytes:      cw.bytes,
+			Latency:    time.Since(start),
+		}
+		if user, ok := UserFromContext(r.Context()); ok {
+			entry.UserID = string(user.ID)
+		}
+
+		if a.metrics != nil {
+			var err error
+			if status >= http.StatusInternalServerError {
+				err = errors.New(http.StatusText(status))
+			}
+			a.metrics.Observe(fmt.Sprintf("http %s %dxx", r.Method, status/100), entry.Latency, err)
+		}
+		if a.enabled(r.URL.Path) {
+			a.write(entry)
+		}
+	})
+}
+
+// write formats and writes a single entry.
+func (a *AccessLog) write(entry *AccessLogEntry) {
+	var line []byte
+	switch a.format {
+	case AccessLogJSON:
+		b, err := json.Marshal(entry)
+		if err != nil {
+			log.Printf("access log: %v", err)
+			return
+		}
+		line = append(b, '\n')
+	default:
+		user := entry.UserID
+		if user == "" {
+			user = "-"
+		}
+		host := entry.RemoteAddr
+		if i := strings.LastIndexByte(host, ':'); i > 0 {
+			host = host[:i]
+		}
+		line = []byte(fmt.Sprintf("%s - %s [%s] %q %d %d %d %s\n",
+			host, user, entry.Time.Format("02/Jan/2006:15:04:05 -0700"),
+			entry.Method+" "+entry.Path+" "+entry.Proto,
+			entry.Status, entry.Bytes, entry.Latency.Microseconds(), entry.RequestID))
+	}
+
+	a.writeMu.Lock()
+	defer a.writeMu.Unlock()
+	if _, err := a.out.Write(line); err != nil {
+		log.Printf("access log: %v", err)
+	}
+}
+
+// validRequestID reports whether a client-supplied request ID is safe
+// to log and echo: short, and printable ASCII without spaces or quotes.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if c := id[i]; c <= ' ' || c > '~' || c == '"' {
+			return false
+		}
+	}
+	return true
+}
+
+// countingWriter records the status and size of a response.
+type countingWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+// WriteHeader records the response status.
+func (w *countingWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write counts the bytes written.
+func (w *countingWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (w *countingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
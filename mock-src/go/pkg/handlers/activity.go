@@ -0,0 +1,248 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+const (
+	// defaultActivityPageSize is the number of activities returned when no limit is given.
+	defaultActivityPageSize = 50
+	// maxActivityPageSize caps the limit query parameter.
+	maxActivityPageSize = 500
+)
+
+// TaskEventLog is implemented by task stores that keep the event history
+// of every task, such as EventSourcedTaskStore.
+type TaskEventLog interface {
+	// AllEvents returns every event recorded for every task, including
+	// deleted tasks, ordered by when they occurred.
+	AllEvents(ctx context.Context) ([]*models.TaskEvent, error)
+}
+
+// errInvalidCursor is returned when an activity cursor is malformed.
+var errInvalidCursor = errors.New("invalid cursor")
+
+// ActivityPage is the response body for an activity feed.
+//
+// Activities are newest first. NextCursor is set when older activities
+// remain; pass it as ?cursor= to fetch them.
+type ActivityPage struct {
+	Activities []*models.Activity `json:"activities"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+// activityFilter selects activities from a feed.
+type activityFilter struct {
+	projectID  models.ProjectID
+	actorID    models.UserID
+	verb       string
+	targetType string
+	since      time.Time
+}
+
+// matches reports whether an activity passes the filter. A verb ending
+// in a dot, such as "task.", matches every verb with that prefix.
+func (f *activityFilter) matches(a *models.Activity) bool {
+	if f.projectID != "" && a.ProjectID != f.projectID {
+		return false
+	}
+	if f.actorID != "" && a.ActorID != f.actorID {
+		return false
+	}
+	if f.verb != "" {
+		if strings.HasSuffix(f.verb, ".") {
+			if !strings.HasPrefix(a.Verb, f.verb) {
+				return false
+			}
+		} else if a.Verb != f.verb {
+			return false
+		}
+	}
+	if f.targetType != "" && a.TargetType != f.targetType {
+		return false
+	}
+	return f.since.IsZero() || a.OccurredAt.After(f.since)
+}
+
+// ActivityHandler serves activity feeds aggregated from task history,
+// comments and the audit log.
+//
+// Task changes appear only when the task store keeps history (see
+// TaskEventLog).
+type ActivityHandler struct {
+	tasks    TaskStore
+	comments CommentStore
+	audit    AuditStore
+}
+
+// NewActivityHandler creates a new activity feed handler.
+func NewActivityHandler(tasks TaskStore, comments CommentStore, audit AuditStore) *ActivityHandler {
+	return &ActivityHandler{tasks: tasks, comments: comments, audit: audit}
+}
+
+// ProjectActivity handles GET /projects/{id}/activity requests.
+func (h *ActivityHandler) ProjectActivity(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	h.serve(w, r, activityFilter{projectID: projectID})
+}
+
+// UserActivity handles GET /users/{id}/activity requests, listing what
+// the user did.
+func (h *ActivityHandler) UserActivity(w http.ResponseWriter, r *http.Request, userID models.UserID) {
+	h.serve(w, r, activityFilter{actorID: userID})
+}
+
+// serve writes a page of the activities matching filter and the query
+// parameters verb, target_type, since, limit and cursor.
+func (h *ActivityHandler) serve(w http.ResponseWriter, r *http.Request, filter activityFilter) {
+	query := r.URL.Query()
+	filter.verb = query.Get("verb")
+	filter.targetType = query.Get("target_type")
+	if raw := query.Get("since"); raw != "" {
+		since, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			http.Error(w, "since must be an RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		filter.since = since
+	}
+
+	limit := defaultActivityPageSize
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > maxActivityPageSize {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	var after *models.Activity
+	if raw := query.Get("cursor"); raw != "" {
+		var err error
+		if after, err = decodeActivityCursor(raw); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	activities, err := collectActivities(r.Context(), h.tasks, h.comments, h.audit)
+	if err != nil {
+		writeServerError(w, r, "failed to load activity", err)
+		return
+	}
+
+	page := &ActivityPage{Activities: make([]*models.Activity, 0, limit)}
+	for _, a := range activities {
+		if after != nil && !activityBefore(after, a) {
+			continue
+		}
+		if !filter.matches(a) {
+			continue
+		}
+		if len(page.Activities) == limit {
+			page.NextCursor = encodeActivityCursor(page.Activities[limit-1])
+			break
+		}
+		page.Activities = append(page.Activities, a)
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+// collectActivities gathers every activity, newest first.
+func collectActivities(ctx context.Context, taskStore TaskStore, commentStore CommentStore, audit AuditStore) ([]*models.Activity, error) {
+	tasks, err := taskStore.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	projects := make(map[models.TaskID]models.ProjectID, len(tasks))
+	for _, t := range tasks {
+		projects[t.ID] = t.ProjectID
+	}
+
+	activities := make([]*models.Activity, 0)
+	if log, ok := taskStore.(TaskEventLog); ok {
+		events, err := log.AllEvents(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// Deleted tasks are placed in the project of their last full
+		// state, recorded when they were created or rewritten.
+		deleted := make(map[models.TaskID]models.ProjectID)
+		for _, e := range events {
+			if _, live := projects[e.TaskID]; !live && e.State != nil {
+				deleted[e.TaskID] = e.State.ProjectID
+			}
+		}
+		for id, projectID := range deleted {
+			projects[id] = projectID
+		}
+		for _, e := range events {
+			activities = append(activities, models.ActivityFromTaskEvent(e, projects[e.TaskID]))
+		}
+	}
+
+	comments, err := commentStore.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range comments {
+		activities = append(activities, models.ActivityFromComment(c, projects[c.TaskID]))
+	}
+
+	entries, err := audit.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		activities = append(activities, models.ActivityFromAudit(e))
+	}
+
+	sort.Slice(activities, func(i, j int) bool {
+		return activityBefore(activities[i], activities[j])
+	})
+	return activities, nil
+}
+
+// activityBefore reports whether a comes before b in a feed: newer
+// first, ties broken by ID.
+func activityBefore(a, b *models.Activity) bool {
+	if !a.OccurredAt.Equal(b.OccurredAt) {
+		return a.OccurredAt.After(b.OccurredAt)
+	}
+	return a.ID > b.ID
+}
+
+// encodeActivityCursor returns an opaque cursor positioned after a.
+func encodeActivityCursor(a *models.Activity) string {
+	raw := strconv.FormatInt(a.OccurredAt.UnixNano(), 10) + "|" + a.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeActivityCursor returns the position a cursor points after, as an
+// activity with only its time and ID set.
+func decodeActivityCursor(cursor string) (*models.Activity, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, errInvalidCursor
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	return &models.Activity{ID: id, OccurredAt: time.Unix(0, n)}, nil
+}
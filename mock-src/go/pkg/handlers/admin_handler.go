@@ -0,0 +1,389 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// ErrLastOwner is returned when a change would leave no owner.
+var ErrLastOwner = errors.New("cannot demote the last owner")
+
+// AdminHandler handles HTTP requests for user administration.
+type AdminHandler struct {
+	users UserStore
+	tasks TaskStore
+	audit AuditStore
+
+	// roleMu serializes role changes so the last-owner check cannot race.
+	roleMu sync.Mutex
+}
+
+// NewAdminHandler creates a new admin handler.
+func NewAdminHandler(users UserStore, tasks TaskStore, audit AuditStore) *AdminHandler {
+	return &AdminHandler{users: users, tasks: tasks, audit: audit}
+}
+
+// ChangeRoleRequest is the request body for changing a user's role.
+type ChangeRoleRequest struct {
+	Role models.UserRole `json:"role"`
+}
+
+// ChangeRole handles PUT /admin/users/{id}/role requests.
+//
+// The caller needs the manage permission, and only owners may grant or
+// revoke the owner role. The last remaining owner cannot be demoted.
+func (h *AdminHandler) ChangeRole(w http.ResponseWriter, r *http.Request, id models.UserID) {
+	actor, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+	if !actor.HasPermission("manage") {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var req ChangeRoleRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if !models.ValidRole(req.Role) {
+		http.Error(w, "invalid role", http.StatusBadRequest)
+		return
+	}
+
+	h.roleMu.Lock()
+	defer h.roleMu.Unlock()
+
+	user, err := h.users.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get user", err)
+		return
+	}
+
+	if (user.Role == models.UserRoleOwner || req.Role == models.UserRoleOwner) && actor.Role != models.UserRoleOwner {
+		http.Error(w, "only owners can grant or revoke the owner role", http.StatusForbidden)
+		return
+	}
+
+	previous := user.Role
+	if previous == req.Role {
+		writeJSON(w, http.StatusOK, user)
+		return
+	}
+
+	if previous == models.UserRoleOwner {
+		if err := h.ensureAnotherOwner(r.Context(), user.ID); err != nil {
+			if errors.Is(err, ErrLastOwner) {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			writeServerError(w, r, "failed to list users", err)
+			return
+		}
+	}
+
+	if err := user.ChangeRole(req.Role); err != nil {
+		http.Error(w, "invalid role", http.StatusBadRequest)
+		return
+	}
+	if err := h.users.Update(r.Context(), user); err != nil {
+		writeServerError(w, r, "failed to update user", err)
+		return
+	}
+
+	entry := models.NewAuditEntry(actor.ID, models.AuditActionRoleChanged, "user", string(user.ID))
+	entry.Details["from"] = string(previous)
+	entry.Details["to"] = string(req.Role)
+	if err := h.audit.Append(r.Context(), entry); err != nil {
+		writeServerError(w, r, "failed to record audit entry", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+// RoleHistory handles GET /admin/users/{id}/role-history requests.
+func (h *AdminHandler) RoleHistory(w http.ResponseWriter, r *http.Request, id models.UserID) {
+	actor, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+	if !actor.HasPermission("manage") {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	entries, err := h.audit.List(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list audit entries", err)
+		return
+	}
+
+	history := make([]*models.AuditEntry, 0)
+	for _, entry := range entries {
+		if entry.Action == models.AuditActionRoleChanged && entry.TargetID == string(id) {
+			history = append(history, entry)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, history)
+}
+
+// DeactivateUserRequest is the request body for deactivating a user.
+//
+// When ReassignTo is set the user's open tasks are moved to that user;
+// otherwise they are left unassigned.
+type DeactivateUserRequest struct {
+	ReassignTo models.UserID `json:"reassign_to,omitempty"`
+}
+
+// DeactivateUserResponse is the response body for a deactivation.
+type DeactivateUserResponse struct {
+	User          *models.User    `json:"user"`
+	AffectedTasks []models.TaskID `json:"affected_tasks"`
+}
+
+// Deactivate handles POST /admin/users/{id}/deactivate requests.
+//
+// The user is deactivated, which blocks further logins, and each of
+// their open tasks is unassigned or reassigned as requested. Only owners
+// may deactivate an owner, and the last owner cannot be deactivated.
+func (h *AdminHandler) Deactivate(w http.ResponseWriter, r *http.Request, id models.UserID) {
+	actor, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+	if !actor.HasPermission("manage") {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var req DeactivateUserRequest
+	if err := decodeJSON(w, r, &req); err != nil && !errors.Is(err, io.EOF) {
+		writeDecodeError(w, err)
+		return
+	}
+	if req.ReassignTo == id {
+		http.Error(w, "cannot reassign tasks to the deactivated user", http.StatusBadRequest)
+		return
+	}
+
+	h.roleMu.Lock()
+	defer h.roleMu.Unlock()
+
+	user, err := h.users.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get user", err)
+		return
+	}
+
+	if req.ReassignTo != "" {
+		assignee, err := h.users.Get(r.Context(), req.ReassignTo)
+		if err != nil {
+			if errors.Is(err, ErrUserNotFound) {
+				http.Error(w, "reassign_to user not found", http.StatusBadRequest)
+				return
+			}
+			writeServerError(w, r, "failed to get user", err)
+			return
+		}
+		if !assignee.IsActive {
+			http.Error(w, "reassign_to user is deactivated", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if user.Role == models.UserRoleOwner {
+		if actor.Role != models.UserRoleOwner {
+			http.Error(w, "only owners can deactivate an owner", http.StatusForbidden)
+			return
+		}
+		if err := h.ensureAnotherOwner(r.Context(), user.ID); err != nil {
+			if errors.Is(err, ErrLastOwner) {
+				http.Error(w, "cannot deactivate the last owner", http.StatusConflict)
+				return
+			}
+			writeServerError(w, r, "failed to list users", err)
+			return
+		}
+	}
+
+	user.Deactivate()
+	if err := h.users.Update(r.Context(), user); err != nil {
+		writeServerError(w, r, "failed to update user", err)
+		return
+	}
+
+	affected, err := h.releaseTasks(r.Context(), user.ID, req.ReassignTo)
+	if err != nil {
+		writeServerError(w, r, "failed to reassign tasks", err)
+		return
+	}
+
+	entry := models.NewAuditEntry(actor.ID, models.AuditActionUserDeactivated, "user", string(user.ID))
+	if req.ReassignTo != "" {
+		entry.Details["reassigned_to"] = string(req.ReassignTo)
+	}
+	if err := h.audit.Append(r.Context(), entry); err != nil {
+		writeServerError(w, r, "failed to record audit entry", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &DeactivateUserResponse{User: user, AffectedTasks: affected})
+}
+
+// releaseTasks unassigns or reassigns every open task held by userID.
+//
+// Returns the IDs of the tasks that were changed.
+func (h *AdminHandler) releaseTasks(ctx context.Context, userID, reassignTo models.UserID) ([]models.TaskID, error) {
+	tasks, err := h.tasks.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	affected := make([]models.TaskID, 0)
+	for _, task := range tasks {
+		if task.AssigneeID == nil || *task.AssigneeID != userID || !task.IsOpen() {
+			continue
+		}
+		if reassignTo != "" {
+			task.AssignTo(reassignTo)
+		} else {
+			task.Unassign()
+		}
+		if err := h.tasks.Update(ctx, task); err != nil {
+			return affected, err
+		}
+		affected = append(affected, task.ID)
+	}
+	return affected, nil
+}
+
+// ensureAnotherOwner returns ErrLastOwner unless an active owner other
+// than the given user exists.
+func (h *AdminHandler) ensureAnotherOwner(ctx context.Context, userID models.UserID) error {
+	users, err := h.users.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	if !hasOtherActiveOwner(users, userID) {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// hasOtherActiveOwner reports whether an active owner other than userID exists.
+func hasOtherActiveOwner(users []*models.User, userID models.UserID) bool {
+	for _, u := range users {
+		if u.ID != userID && u.Role == models.UserRoleOwner && u.IsActive {
+			return true
+		}
+	}
+	return false
+}
+
+// EvaluatePolicyRequest is the request body for a policy evaluation.
+//
+// When TaskID is set the resource is that task, as permission checks on
+// tasks see it, and Resource is ignored.
+type EvaluatePolicyRequest struct {
+	UserID   models.UserID    `json:"user_id"`
+	Action   string           `json:"action"`
+	TaskID   models.TaskID    `json:"task_id,omitempty"`
+	Resource *models.Resource `json:"resource,omitempty"`
+}
+
+// EvaluatePolicyResponse is the response body for a policy evaluation.
+type EvaluatePolicyResponse struct {
+	UserID   models.UserID    `json:"user_id"`
+	Role     models.UserRole  `json:"role"`
+	Action   string           `json:"action"`
+	Resource *models.Resource `json:"resource,omitempty"`
+	models.PolicyDecision
+}
+
+// EvaluatePolicy handles GET and POST /admin/policy/evaluate requests,
+// reporting whether the current policy lets a user perform an action and
+// which rule decided it.
+//
+// GET takes user_id, action and task_id, or resource_type, resource_id,
+// owner_id and project_id, as query parameters; POST takes an
+// EvaluatePolicyRequest body. The caller needs the manage permission.
+func (h *AdminHandler) EvaluatePolicy(w http.ResponseWriter, r *http.Request) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req EvaluatePolicyRequest
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		req.UserID = models.UserID(q.Get("user_id"))
+		req.Action = q.Get("action")
+		req.TaskID = models.TaskID(q.Get("task_id"))
+		if q.Get("resource_type") != "" {
+			req.Resource = &models.Resource{
+				Type:      q.Get("resource_type"),
+				ID:        q.Get("resource_id"),
+				OwnerID:   models.UserID(q.Get("owner_id")),
+				ProjectID: models.ProjectID(q.Get("project_id")),
+			}
+		}
+	} else if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if req.UserID == "" || req.Action == "" {
+		http.Error(w, "user_id and action are required", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.users.Get(r.Context(), req.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get user", err)
+		return
+	}
+
+	res := req.Resource
+	if req.TaskID != "" {
+		task, err := h.tasks.Get(r.Context(), req.TaskID)
+		if err != nil {
+			if errors.Is(err, ErrTaskNotFound) {
+				http.Error(w, "task not found", http.StatusNotFound)
+				return
+			}
+			writeServerError(w, r, "failed to get task", err)
+			return
+		}
+		res = models.TaskResource(task)
+	}
+
+	writeJSON(w, http.StatusOK, &EvaluatePolicyResponse{
+		UserID:         user.ID,
+		Role:           user.Role,
+		Action:         req.Action,
+		Resource:       res,
+		PolicyDecision: models.CurrentPolicy().Evaluate(user, req.Action, res),
+	})
+}
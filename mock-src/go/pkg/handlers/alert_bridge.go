@@ -0,0 +1,226 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// alertKeySource namespaces alert fingerprints in the webhook key store.
+const alertKeySource = "alert"
+
+// AlertBridgeConfig configures the monitoring alert bridge.
+//
+// AlertmanagerToken is the bearer token Alertmanager's webhook receiver
+// sends; PagerDutySecret is the signing secret of a PagerDuty V3
+// webhook subscription. An empty secret disables that source.
+// SeverityPriorities defaults to models.DefaultSeverityPriorities;
+// unmapped severities get high priority.
+type AlertBridgeConfig struct {
+	ProjectID          models.ProjectID
+	AlertmanagerToken  string
+	PagerDutySecret    string
+	SeverityPriorities map[string]models.TaskPriority
+	Tags               []string
+}
+
+// AlertBridge turns monitoring alerts into incident tasks.
+//
+// Alerts are deduplicated by fingerprint: while an alert's task is
+// open, repeat notifications update its priority and description, and
+// the recovery notification completes it as its project's workflow
+// allows, or submits it for review if the project requires approval. An
+// alert firing again after its task was closed opens a new one.
+type AlertBridge struct {
+	config   AlertBridgeConfig
+	keys     WebhookKeyStore
+	tasks    TaskStore
+	projects ProjectStore
+}
+
+// NewAlertBridge creates a new alert bridge. Fingerprints are kept in
+// keys, which may be shared with the webhook inbox. projects may be nil,
+// in which case no project requires approval.
+func NewAlertBridge(config AlertBridgeConfig, keys WebhookKeyStore, tasks TaskStore, projects ProjectStore) *AlertBridge {
+	if config.SeverityPriorities == nil {
+		config.SeverityPriorities = models.DefaultSeverityPriorities
+	}
+	if config.Tags == nil {
+		config.Tags = []string{"incident"}
+	}
+	return &AlertBridge{config: config, keys: keys, tasks: tasks, projects: projects}
+}
+
+// AlertBridgeResult is the response body for a processed notification.
+type AlertBridgeResult struct {
+	Created  []models.TaskID `json:"created"`
+	Updated  []models.TaskID `json:"updated"`
+	Resolved []models.TaskID `json:"resolved"`
+}
+
+// Alertmanager handles POST /integrations/alertmanager requests.
+func (b *AlertBridge) Alertmanager(w http.ResponseWriter, r *http.Request) {
+	body, ok := readIntegrationBody(w, r, b.config.AlertmanagerToken)
+	if !ok {
+		return
+	}
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(b.config.AlertmanagerToken)) != 1 {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	alerts, err := models.ParseAlertmanager(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	b.process(w, r, alerts)
+}
+
+// PagerDuty handles POST /integrations/pagerduty requests.
+//
+// The X-PagerDuty-Signature header may carry several v1= signatures
+// while a secret is rotated; any valid one is accepted.
+func (b *AlertBridge) PagerDuty(w http.ResponseWriter, r *http.Request) {
+	body, ok := readIntegrationBody(w, r, b.config.PagerDutySecret)
+	if !ok {
+		return
+	}
+	valid := false
+	for _, sig := range strings.Split(r.Header.Get("X-PagerDuty-Signature"), ",") {
+		if sig, ok := strings.CutPrefix(strings.TrimSpace(sig), "v1="); ok && validHMACSHA256(b.config.PagerDutySecret, sig, body) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	alert, err := models.ParsePagerDuty(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if alert == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	b.process(w, r, []*models.Alert{alert})
+}
+
+// process applies alerts to their incident tasks and writes the result.
+func (b *AlertBridge) process(w http.ResponseWriter, r *http.Request, alerts []*models.Alert) {
+	result := &AlertBridgeResult{
+		Created:  make([]models.TaskID, 0),
+		Updated:  make([]models.TaskID, 0),
+		Resolved: make([]models.TaskID, 0),
+	}
+	for _, alert := range alerts {
+		if err := b.apply(r.Context(), alert, result); err != nil {
+			if writeHookRejection(w, err) {
+				return
+			}
+			writeServerError(w, r, "failed to save incident task", err)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// apply creates, updates or resolves the incident task of one alert.
+func (b *AlertBridge) apply(ctx context.Context, alert *models.Alert, result *AlertBridgeResult) error {
+	task, err := openKeyedTask(ctx, b.keys, b.tasks, alertKeySource, alert.Key())
+	if err != nil {
+		return err
+	}
+
+	if alert.Resolved {
+		if task == nil {
+			return nil
+		}
+		_, resolved, err := moveTask(ctx, b.tasks, b.projects, task.ID, models.TaskStatusCompleted, func(t *models.Task) {
+			t.Description += "\n\nResolved by " + string(alert.Source) + "."
+		})
+		if errors.Is(err, models.ErrUnknownStatus) || errors.Is(err, models.ErrTransitionNotAllowed) || errors.Is(err, models.ErrWIPLimitReached) {
+			// The workflow keeps the task open; it is left for people
+			// to close.
+			return nil
+		}
+		if err == nil && resolved {
+			result.Resolved = append(result.Resolved, task.ID)
+		}
+		return err
+	}
+
+	priority := b.priority(alert.Severity)
+	description := alertDescription(alert)
+	if task != nil {
+		changed := false
+		_, err := updateTask(ctx, b.tasks, task.ID, func(t *models.Task) bool {
+			changed = false
+			if t.Priority != priority {
+				t.Priority = priority
+				changed = true
+			}
+			if t.Description != description {
+				t.Description = description
+				changed = true
+			}
+			return changed
+		})
+		if err == nil && changed {
+			result.Updated = append(result.Updated, task.ID)
+		}
+		return err
+	}
+
+	title := alert.Title
+	if strings.TrimSpace(title) == "" {
+		title = "Alert " + alert.Fingerprint
+	}
+	created := models.NewTaskWithOptions(title, b.config.ProjectID,
+		models.WithDescription(description),
+		models.WithPriority(priority),
+		models.WithTags(b.config.Tags),
+	)
+	if err := created.SanitizeContent(); err != nil {
+		return err
+	}
+	if err := b.tasks.Create(ctx, created); err != nil {
+		return err
+	}
+	result.Created = append(result.Created, created.ID)
+	return b.keys.Link(ctx, alertKeySource, alert.Key(), created.ID)
+}
+
+// priority maps an alert severity to a task priority.
+func (b *AlertBridge) priority(severity string) models.TaskPriority {
+	if p, ok := b.config.SeverityPriorities[severity]; ok {
+		return p
+	}
+	return models.TaskPriorityHigh
+}
+
+// alertDescription describes a firing alert, linking back to its source.
+func alertDescription(alert *models.Alert) string {
+	var b strings.Builder
+	if alert.Description != "" {
+		b.WriteString(alert.Description)
+		b.WriteString("\n\n")
+	}
+	fmt.Fprintf(&b, "Severity: %s\nSource: %s", alert.Severity, alert.Source)
+	if alert.URL != "" {
+		fmt.Fprintf(&b, "\nDetails: %s", alert.URL)
+	}
+	return b.String()
+}
@@ -0,0 +1,182 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// approvalRequired reports whether completing a task needs approval
+// under its project's settings. It writes an error and returns false
+// as its second result if the task or project cannot be loaded.
+func (h *TaskHandler) approvalRequired(w http.ResponseWriter, r *http.Request, id models.TaskID) (bool, bool) {
+	if h.projects == nil {
+		return false, true
+	}
+	task, err := h.store.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			http.Error(w, "task not found", http.StatusNotFound)
+			return false, false
+		}
+		writeServerError(w, r, "failed to get task", err)
+		return false, false
+	}
+	project, err := h.projects.Get(r.Context(), task.ProjectID)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			return false, true
+		}
+		writeServerError(w, r, "failed to get project", err)
+		return false, false
+	}
+	return project.RequiresApproval, true
+}
+
+// projectRequiresApproval reports whether completing a task of a
+// project needs approval. Without a project store, or for a project
+// that no longer exists, it does not.
+func projectRequiresApproval(ctx context.Context, projects ProjectStore, id models.ProjectID) (bool, error) {
+	if projects == nil {
+		return false, nil
+	}
+	project, err := projects.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return project.RequiresApproval, nil
+}
+
+// moveStatus moves a task to status, submitting it for review instead
+// when it is completed and approval is required. It reports whether the
+// task changed.
+func moveStatus(task *models.Task, status models.TaskStatus, approval bool) bool {
+	if status == models.TaskStatusCompleted && approval {
+		return task.SubmitForReview()
+	}
+	return task.SetStatus(status)
+}
+
+// moveTask moves an open task to status as moveStatus does, through the
+// store so that its project's workflow applies. also, if not nil, is
+// applied to the task when it moves. It returns the task and whether it
+// moved; a closed task is left as it is.
+func moveTask(ctx context.Context, tasks TaskStore, projects ProjectStore, id models.TaskID, status models.TaskStatus, also func(*models.Task)) (*models.Task, bool, error) {
+	task, err := tasks.Get(ctx, id)
+	if err != nil {
+		return nil, false, err
+	}
+	approval, err := projectRequiresApproval(ctx, projects, task.ProjectID)
+	if err != nil {
+		return nil, false, err
+	}
+	moved := false
+	task, err = updateTask(ctx, tasks, id, func(t *models.Task) bool {
+		moved = t.IsOpen() && moveStatus(t, status, approval)
+		if moved && also != nil {
+			also(t)
+		}
+		return moved
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return task, moved, nil
+}
+
+// RejectTaskRequest is the request body for rejecting a task's completion.
+type RejectTaskRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Approve handles POST /tasks/{id}/approve requests, completing a task
+// that awaits review. Only admins may approve. Approving a task that is
+// not awaiting review yields 409.
+func (h *TaskHandler) Approve(w http.ResponseWriter, r *http.Request, id models.TaskID) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	h.review(w, r, id, EventTaskApproved, "", func(task *models.Task) bool {
+		return task.Approve()
+	})
+}
+
+// Reject handles POST /tasks/{id}/reject requests, returning a task
+// that awaits review to the status it was submitted from. Only admins
+// may reject, and a reason is required. Rejecting a task that is not
+// awaiting review yields 409.
+func (h *TaskHandler) Reject(w http.ResponseWriter, r *http.Request, id models.TaskID) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	var req RejectTaskRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	reason, err := models.SanitizeDescription(req.Reason)
+	if err != nil {
+		writeContentError(w, err)
+		return
+	}
+	if strings.TrimSpace(reason) == "" {
+		http.Error(w, "reason is required", http.StatusBadRequest)
+		return
+	}
+
+	h.review(w, r, id, EventTaskRejected, reason, func(task *models.Task) bool {
+		return task.Reject(reason)
+	})
+}
+
+// review applies an approval decision and publishes it on the event bus.
+func (h *TaskHandler) review(w http.ResponseWriter, r *http.Request, id models.TaskID, event EventType, reason string, decide func(*models.Task) bool) {
+	reviewer, _ := UserFromContext(r.Context())
+
+	decided := false
+	task, err := updateTask(r.Context(), h.store, id, func(task *models.Task) bool {
+		decided = decide(task)
+		return decided
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrTaskNotFound):
+			http.Error(w, "task not found", http.StatusNotFound)
+		case errors.Is(err, ErrVersionConflict):
+			http.Error(w, "task was modified concurrently", http.StatusConflict)
+		default:
+			if !writeHookRejection(w, err) {
+				http.Error(w, "failed to update task", http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+	if !decided {
+		http.Error(w, "task is not awaiting review", http.StatusConflict)
+		return
+	}
+
+	if h.bus != nil {
+		data := map[string]any{"reviewer_id": reviewer.ID}
+		if reason != "" {
+			data["reason"] = reason
+		}
+		h.bus.Publish(r.Context(), &Event{
+			Type:      event,
+			TaskID:    task.ID,
+			ProjectID: task.ProjectID,
+			Data:      data,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, toResponse(task))
+}
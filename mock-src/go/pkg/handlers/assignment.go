@@ -0,0 +1,229 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// AssignmentPolicyStore defines the interface for per-project assignment
+// policy storage.
+type AssignmentPolicyStore interface {
+	// Get retrieves the assignment policy of a project.
+	Get(ctx context.Context, projectID models.ProjectID) (*models.AssignmentPolicy, error)
+	// Put sets a project's assignment policy, replacing any existing one
+	// and restarting its round-robin turn.
+	Put(ctx context.Context, policy *models.AssignmentPolicy) error
+	// Delete removes a project's assignment policy.
+	Delete(ctx context.Context, projectID models.ProjectID) error
+	// NextTurn returns the project's round-robin turn and advances it.
+	NextTurn(ctx context.Context, projectID models.ProjectID) (int, error)
+}
+
+// ErrAssignmentPolicyNotFound is returned when a project has no
+// assignment policy.
+var ErrAssignmentPolicyNotFound = errors.New("assignment policy not found")
+
+// InMemoryAssignmentPolicyStore is an in-memory implementation of
+// AssignmentPolicyStore.
+type InMemoryAssignmentPolicyStore struct {
+	mu       sync.RWMutex
+	policies map[models.ProjectID]*models.AssignmentPolicy
+	turns    map[models.ProjectID]int
+}
+
+// NewInMemoryAssignmentPolicyStore creates a new in-memory assignment
+// policy store.
+func NewInMemoryAssignmentPolicyStore() *InMemoryAssignmentPolicyStore {
+	return &InMemoryAssignmentPolicyStore{
+		policies: make(map[models.ProjectID]*models.AssignmentPolicy),
+		turns:    make(map[models.ProjectID]int),
+	}
+}
+
+// Get retrieves the assignment policy of a project.
+func (s *InMemoryAssignmentPolicyStore) Get(ctx context.Context, projectID models.ProjectID) (*models.AssignmentPolicy, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	policy, ok := s.policies[projectID]
+	if !ok {
+		return nil, ErrAssignmentPolicyNotFound
+	}
+	return policy, nil
+}
+
+// Put sets a project's assignment policy, replacing any existing one
+// and restarting its round-robin turn.
+func (s *InMemoryAssignmentPolicyStore) Put(ctx context.Context, policy *models.AssignmentPolicy) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.policies[policy.ProjectID] = policy
+	delete(s.turns, policy.ProjectID)
+	return nil
+}
+
+// Delete removes a project's assignment policy.
+func (s *InMemoryAssignmentPolicyStore) Delete(ctx context.Context, projectID models.ProjectID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.policies[projectID]; !ok {
+		return ErrAssignmentPolicyNotFound
+	}
+	delete(s.policies, projectID)
+	delete(s.turns, projectID)
+	return nil
+}
+
+// NextTurn returns the project's round-robin turn and advances it.
+func (s *InMemoryAssignmentPolicyStore) NextTurn(ctx context.Context, projectID models.ProjectID) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.policies[projectID]; !ok {
+		return 0, ErrAssignmentPolicyNotFound
+	}
+	turn := s.turns[projectID]
+	s.turns[projectID] = turn + 1
+	return turn, nil
+}
+
+// autoAssign assigns a new task according to its project's policy, if
+// any. It reports whether the task was assigned.
+func autoAssign(ctx context.Context, policies AssignmentPolicyStore, tasks TaskStore, task *models.Task) (bool, error) {
+	policy, err := policies.Get(ctx, task.ProjectID)
+	if err != nil {
+		if errors.Is(err, ErrAssignmentPolicyNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	var turn int
+	var openTasks map[models.UserID]int
+	switch policy.Strategy {
+	case models.AssignmentRoundRobin:
+		if turn, err = policies.NextTurn(ctx, task.ProjectID); err != nil {
+			return false, err
+		}
+	case models.AssignmentLeastLoaded:
+		all, err := tasks.GetAll(ctx)
+		if err != nil {
+			return false, err
+		}
+		openTasks = make(map[models.UserID]int)
+		for _, t := range all {
+			if t.AssigneeID != nil && t.IsOpen() {
+				openTasks[*t.AssigneeID]++
+			}
+		}
+	}
+
+	userID, ok := policy.Choose(task, turn, openTasks)
+	if !ok {
+		return false, nil
+	}
+	task.AssignTo(userID)
+	return true, nil
+}
+
+// AssignmentHandler handles HTTP requests for project assignment policies.
+type AssignmentHandler struct {
+	policies AssignmentPolicyStore
+	users    UserStore
+}
+
+// NewAssignmentHandler creates a new assignment policy handler.
+func NewAssignmentHandler(policies AssignmentPolicyStore, users UserStore) *AssignmentHandler {
+	return &AssignmentHandler{policies: policies, users: users}
+}
+
+// Get handles GET /projects/{id}/assignment-policy requests.
+func (h *AssignmentHandler) Get(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	policy, err := h.policies.Get(r.Context(), projectID)
+	if err != nil {
+		if errors.Is(err, ErrAssignmentPolicyNotFound) {
+			http.Error(w, "project has no assignment policy", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get assignment policy", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, policy)
+}
+
+// Put handles PUT /projects/{id}/assignment-policy requests.
+//
+// Every user named by the policy must exist and be active.
+func (h *AssignmentHandler) Put(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var policy models.AssignmentPolicy
+	if err := decodeJSON(w, r, &policy); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	policy.ProjectID = projectID
+	if len(policy.TagAssignees) > 0 {
+		tags := make(map[string]models.UserID, len(policy.TagAssignees))
+		for tag, id := range policy.TagAssignees {
+			tags[strings.ToLower(strings.TrimSpace(tag))] = id
+		}
+		policy.TagAssignees = tags
+	}
+	if err := policy.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, id := range policy.UserIDs() {
+		user, err := h.users.Get(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, ErrUserNotFound) {
+				http.Error(w, "unknown user "+string(id), http.StatusBadRequest)
+				return
+			}
+			writeServerError(w, r, "failed to get user", err)
+			return
+		}
+		if !user.IsActive {
+			http.Error(w, "user "+string(id)+" is deactivated", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := h.policies.Put(r.Context(), &policy); err != nil {
+		writeServerError(w, r, "failed to save assignment policy", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &policy)
+}
+
+// Delete handles DELETE /projects/{id}/assignment-policy requests.
+func (h *AssignmentHandler) Delete(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	if err := h.policies.Delete(r.Context(), projectID); err != nil {
+		if errors.Is(err, ErrAssignmentPolicyNotFound) {
+			http.Error(w, "project has no assignment policy", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to delete assignment policy", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
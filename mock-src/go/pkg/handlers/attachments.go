@@ -0,0 +1,37 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// AttachmentStore defines the interface for attachment storage.
+type AttachmentStore interface {
+	Store[string, *models.Attachment]
+	// ListByTask retrieves the attachments of a task, oldest first.
+	ListByTask(ctx context.Context, taskID models.TaskID) ([]*models.Attachment, error)
+}
+
+// ErrAttachmentNotFound is returned when an attachment is not found.
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+// InMemoryAttachmentStore is an in-memory implementation of AttachmentStore.
+type InMemoryAttachmentStore struct {
+	*InMemoryStore[string, *models.Attachment]
+}
+
+// NewInMemoryAttachmentStore creates a new in-memory attachment store.
+func NewInMemoryAttachmentStore() *InMemoryAttachmentStore {
+	return &InMemoryAttachmentStore{NewInMemoryStore[string, *models.Attachment](ErrAttachmentNotFound)}
+}
+
+// ListByTask retrieves the attachments of a task, oldest first.
+func (s *InMemoryAttachmentStore) ListByTask(ctx context.Context, taskID models.TaskID) ([]*models.Attachment, error) {
+	return s.List(ctx, ListOptions[*models.Attachment]{
+		Filter: func(a *models.Attachment) bool { return a.TaskID == taskID },
+		Less:   func(a, b *models.Attachment) bool { return a.CreatedAt.Before(b.CreatedAt) },
+	})
+}
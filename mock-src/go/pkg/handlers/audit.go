@@ -0,0 +1,69 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// AuditStore defines the interface for audit log storage.
+type AuditStore interface {
+	// Append records a new audit entry.
+	Append(ctx context.Context, entry *models.AuditEntry) error
+	// List retrieves all audit entries in the order they were recorded.
+	List(ctx context.Context) ([]*models.AuditEntry, error)
+	// DeleteBefore removes entries created before cutoff and returns how many were removed.
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int, error)
+}
+
+// InMemoryAuditStore is an in-memory implementation of AuditStore.
+type InMemoryAuditStore struct {
+	mu      sync.RWMutex
+	entries []*models.AuditEntry
+}
+
+// NewInMemoryAuditStore creates a new in-memory audit store.
+func NewInMemoryAuditStore() *InMemoryAuditStore {
+	return &InMemoryAuditStore{}
+}
+
+// Append records a new audit entry.
+func (s *InMemoryAuditStore) Append(ctx context.Context, entry *models.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+// List retrieves all audit entries in the order they were recorded.
+func (s *InMemoryAuditStore) List(ctx context.Context) ([]*models.AuditEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]*models.AuditEntry, len(s.entries))
+	copy(entries, s.entries)
+	return entries, nil
+}
+
+// DeleteBefore removes entries created before cutoff and returns how many were removed.
+func (s *InMemoryAuditStore) DeleteBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.entries[:0]
+	for _, entry := range s.entries {
+		if !entry.CreatedAt.Before(cutoff) {
+			kept = append(kept, entry)
+		}
+	}
+	removed := len(s.entries) - len(kept)
+	for i := len(kept); i < len(s.entries); i++ {
+		s.entries[i] = nil
+	}
+	s.entries = kept
+	return removed, nil
+}
@@ -0,0 +1,339 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// AutomationRuleStore defines the interface for automation rule storage.
+type AutomationRuleStore interface {
+	Store[string, *models.AutomationRule]
+	// ListByProject retrieves the rules of a project.
+	ListByProject(ctx context.Context, projectID models.ProjectID) ([]*models.AutomationRule, error)
+}
+
+// ErrAutomationRuleNotFound is returned when an automation rule is not found.
+var ErrAutomationRuleNotFound = errors.New("automation rule not found")
+
+// InMemoryAutomationRuleStore is an in-memory implementation of AutomationRuleStore.
+type InMemoryAutomationRuleStore struct {
+	*InMemoryStore[string, *models.AutomationRule]
+}
+
+// NewInMemoryAutomationRuleStore creates a new in-memory automation rule store.
+func NewInMemoryAutomationRuleStore() *InMemoryAutomationRuleStore {
+	return &InMemoryAutomationRuleStore{NewInMemoryStore[string, *models.AutomationRule](ErrAutomationRuleNotFound)}
+}
+
+// ListByProject retrieves the rules of a project, oldest first.
+func (s *InMemoryAutomationRuleStore) ListByProject(ctx context.Context, projectID models.ProjectID) ([]*models.AutomationRule, error) {
+	return s.List(ctx, ListOptions[*models.AutomationRule]{
+		Filter: func(r *models.AutomationRule) bool { return r.ProjectID == projectID },
+		Less:   func(a, b *models.AutomationRule) bool { return a.CreatedAt.Before(b.CreatedAt) },
+	})
+}
+
+// ChannelNotifier delivers automation messages to named channels, such
+// as a chat room. The channel name excludes the leading #.
+type ChannelNotifier interface {
+	NotifyChannel(ctx context.Context, channel, message string) error
+}
+
+// automationContextKey marks contexts of writes made by the automation
+// engine.
+type automationContextKey struct{}
+
+// AutomationEngine runs automation rules on task events.
+//
+// Writes made by a rule's actions publish events too, but those events
+// do not trigger rules again, so rules cannot loop.
+type AutomationEngine struct {
+	rules         AutomationRuleStore
+	tasks         TaskStore
+	notifications NotificationStore
+	users         UserStore
+	channels      ChannelNotifier
+}
+
+// NewAutomationEngine creates a new automation engine. User recipients
+// who may not see a task, or no longer exist, are not notified about it.
+//
+// channels may be nil, in which case channel recipients are skipped.
+func NewAutomationEngine(rules AutomationRuleStore, tasks TaskStore, notifications NotificationStore, users UserStore, channels ChannelNotifier) *AutomationEngine {
+	return &AutomationEngine{rules: rules, tasks: tasks, notifications: notifications, users: users, channels: channels}
+}
+
+// Subscribe runs the engine on every event published on bus.
+func (e *AutomationEngine) Subscribe(bus *EventBus) {
+	bus.SubscribeAll(e.Handle)
+}
+
+// Handle runs the rules of the event's project that match it. Failures
+// are logged, since the event has already happened.
+func (e *AutomationEngine) Handle(ctx context.Context, event *Event) {
+	if event.TaskID == "" || ctx.Value(automationContextKey{}) != nil {
+		return
+	}
+	if err := e.handle(context.WithValue(ctx, automationContextKey{}, true), event); err != nil {
+		log.Printf("automation: %s for task %s: %v", event.Type, event.TaskID, err)
+	}
+}
+
+// handle evaluates and applies the rules for one event.
+func (e *AutomationEngine) handle(ctx context.Context, event *Event) error {
+	task, err := e.tasks.Get(ctx, event.TaskID)
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			return nil
+		}
+		return err
+	}
+	rules, err := e.rules.ListByProject(ctx, task.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	previous := task.Status
+	if s, ok := event.Data["previous_status"].(models.TaskStatus); ok {
+		previous = s
+	}
+	for _, rule := range rules {
+		ok, err := rule.Matches(string(event.Type), task, previous)
+		if err != nil {
+			log.Printf("automation: rule %s: %v", rule.ID, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		for _, action := range rule.Actions {
+			if err := e.apply(ctx, rule, action, task); err != nil {
+				return fmt.Errorf("rule %s: %w", rule.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// apply runs one action of a matching rule on task.
+func (e *AutomationEngine) apply(ctx context.Context, rule *models.AutomationRule, action models.AutomationAction, task *models.Task) error {
+	switch action.Type {
+	case models.AutomationAddTag:
+		_, err := updateTask(ctx, e.tasks, task.ID, func(t *models.Task) bool { return t.AddTag(action.Tag) })
+		return err
+	case models.AutomationRemoveTag:
+		_, err := updateTask(ctx, e.tasks, task.ID, func(t *models.Task) bool { return t.RemoveTag(action.Tag) })
+		return err
+	case models.AutomationSetPriority:
+		_, err := updateTask(ctx, e.tasks, task.ID, func(t *models.Task) bool {
+			if t.Priority == action.Priority {
+				return false
+			}
+			t.Priority = action.Priority
+			return true
+		})
+		return err
+	case models.AutomationNotify:
+		message := action.Message
+		if message == "" {
+			message = fmt.Sprintf("automation rule %q matched task %q", rule.Name, task.Title)
+		}
+		for _, recipient := range action.Recipients {
+			if channel, ok := strings.CutPrefix(recipient, "#"); ok {
+				if e.channels == nil {
+					continue
+				}
+				if err := e.channels.NotifyChannel(ctx, channel, message); err != nil {
+					return err
+				}
+				continue
+			}
+			user, err := e.users.Get(ctx, models.UserID(recipient))
+			if errors.Is(err, ErrUserNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if !task.IsVisibleTo(user) {
+				continue
+			}
+			n := models.NewNotification(user.ID, models.NotificationAutomation, task.ID, "")
+			n.Message = message
+			if err := e.notifications.Create(ctx, n); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// AutomationHandler handles HTTP requests for automation rules.
+//
+// Rules must trigger on a known EventType, and their set_priority
+// actions must set a rank of the project's priority scheme.
+type AutomationHandler struct {
+	rules      AutomationRuleStore
+	priorities PrioritySchemeStore
+}
+
+// NewAutomationHandler creates a new automation handler. priorities may
+// be nil, in which case every project uses the default priority scheme.
+func NewAutomationHandler(rules AutomationRuleStore, priorities PrioritySchemeStore) *AutomationHandler {
+	return &AutomationHandler{rules: rules, priorities: priorities}
+}
+
+// validate checks a rule, writing an error and returning false if it is
+// invalid or its project's priority scheme cannot be read.
+func (h *AutomationHandler) validate(w http.ResponseWriter, r *http.Request, rule *models.AutomationRule) bool {
+	if err := rule.Validate(eventTypes); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	scheme := models.DefaultPriorityScheme(rule.ProjectID)
+	if h.priorities != nil {
+		var err error
+		if scheme, err = projectPriorityScheme(r.Context(), h.priorities, rule.ProjectID); err != nil {
+			writeServerError(w, r, "failed to get priority scheme", err)
+			return false
+		}
+	}
+	if err := rule.CheckPriorities(scheme); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// AutomationRuleRequest is the request body for creating or updating a rule.
+type AutomationRuleRequest struct {
+	Name      string                    `json:"name"`
+	Trigger   EventType                 `json:"trigger"`
+	Condition string                    `json:"condition,omitempty"`
+	Actions   []models.AutomationAction `json:"actions"`
+	Enabled   *bool                     `json:"enabled,omitempty"`
+}
+
+// apply copies the request's fields onto a rule.
+func (req *AutomationRuleRequest) apply(rule *models.AutomationRule) {
+	rule.Name = req.Name
+	rule.Trigger = string(req.Trigger)
+	rule.Condition = req.Condition
+	rule.Actions = req.Actions
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+}
+
+// List handles GET /projects/{id}/automation-rules requests.
+func (h *AutomationHandler) List(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	rules, err := h.rules.ListByProject(r.Context(), projectID)
+	if err != nil {
+		writeServerError(w, r, "failed to list automation rules", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rules)
+}
+
+// Get handles GET /automation-rules/{id} requests.
+func (h *AutomationHandler) Get(w http.ResponseWriter, r *http.Request, id string) {
+	rule, err := h.rules.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrAutomationRuleNotFound) {
+			http.Error(w, "automation rule not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get automation rule", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rule)
+}
+
+// Create handles POST /projects/{id}/automation-rules requests.
+func (h *AutomationHandler) Create(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req AutomationRuleRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	rule := models.NewAutomationRule(projectID, req.Name, string(req.Trigger), req.Condition)
+	req.apply(rule)
+	if !h.validate(w, r, rule) {
+		return
+	}
+
+	if err := h.rules.Create(r.Context(), rule); err != nil {
+		writeServerError(w, r, "failed to create automation rule", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, rule)
+}
+
+// Update handles PUT /automation-rules/{id} requests.
+func (h *AutomationHandler) Update(w http.ResponseWriter, r *http.Request, id string) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req AutomationRuleRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	existing, err := h.rules.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrAutomationRuleNotFound) {
+			http.Error(w, "automation rule not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get automation rule", err)
+		return
+	}
+
+	rule := *existing
+	req.apply(&rule)
+	if !h.validate(w, r, &rule) {
+		return
+	}
+
+	if err := h.rules.Update(r.Context(), &rule); err != nil {
+		writeServerError(w, r, "failed to update automation rule", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &rule)
+}
+
+// Delete handles DELETE /automation-rules/{id} requests.
+func (h *AutomationHandler) Delete(w http.ResponseWriter, r *http.Request, id string) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	if err := h.rules.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, ErrAutomationRuleNotFound) {
+			http.Error(w, "automation rule not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to delete automation rule", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,764 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// backupFormatVersion is written to every archive manifest.
+const backupFormatVersion = 1
+
+const (
+	// maxRestoreBodyBytes caps the size of an uploaded restore archive.
+	maxRestoreBodyBytes = 256 << 20
+	// maxArchiveBytes caps the decompressed size of an archive, so a small
+	// upload cannot expand without bound.
+	maxArchiveBytes = 2 << 30
+)
+
+// ErrUnsupportedBackup is returned when an archive has an unknown format version.
+var ErrUnsupportedBackup = errors.New("unsupported backup format")
+
+// ErrInvalidBackup is returned when a snapshot is malformed, such as
+// one with duplicate IDs. Nothing is restored from it.
+var ErrInvalidBackup = errors.New("invalid backup")
+
+// ErrBackupTooLarge is returned when an archive decompresses to more
+// than maxArchiveBytes.
+var ErrBackupTooLarge = errors.New("backup archive too large")
+
+// ErrInvalidBackupPart is returned when registering a backup part under
+// a name that is malformed or already used.
+var ErrInvalidBackupPart = errors.New("invalid backup part")
+
+// backupPartNameRegex matches the names of backup parts.
+var backupPartNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// coreBackupFiles are the archive files of the core stores.
+var coreBackupFiles = []string{"manifest", "tasks", "users", "comments", "audit"}
+
+// Snapshot is a point-in-time copy of every store. Parts holds the
+// exported contents of the registered backup parts by name.
+type Snapshot struct {
+	Version      int                        `json:"version"`
+	CreatedAt    time.Time                  `json:"created_at"`
+	Tasks        []*models.Task             `json:"tasks"`
+	Users        []*models.User             `json:"users"`
+	Comments     []*models.Comment          `json:"comments"`
+	AuditEntries []*models.AuditEntry       `json:"audit_entries"`
+	Parts        map[string]json.RawMessage `json:"parts,omitempty"`
+}
+
+// Validate checks the snapshot's format version and that every entity
+// is present and has a unique ID.
+func (snap *Snapshot) Validate() error {
+	if snap.Version != backupFormatVersion {
+		return ErrUnsupportedBackup
+	}
+	if err := checkBackupIDs("task", snap.Tasks, func(t *models.Task) string { return string(t.ID) }); err != nil {
+		return err
+	}
+	if err := checkBackupIDs("user", snap.Users, func(u *models.User) string { return string(u.ID) }); err != nil {
+		return err
+	}
+	if err := checkBackupIDs("comment", snap.Comments, func(c *models.Comment) string { return c.ID }); err != nil {
+		return err
+	}
+	return checkBackupIDs("audit entry", snap.AuditEntries, func(e *models.AuditEntry) string { return e.ID })
+}
+
+// checkBackupIDs checks that entities are present and have unique IDs.
+func checkBackupIDs[T any](kind string, entities []*T, id func(*T) string) error {
+	seen := make(map[string]bool, len(entities))
+	for _, entity := range entities {
+		if entity == nil {
+			return fmt.Errorf("%w: empty %s", ErrInvalidBackup, kind)
+		}
+		key := id(entity)
+		if key == "" || seen[key] {
+			return fmt.Errorf("%w: missing or duplicate %s id %q", ErrInvalidBackup, kind, key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// BackupPart is a store backed up alongside the core stores, such as
+// attachments or sprints. Its contents travel as one JSON document.
+type BackupPart interface {
+	// Export returns the store's contents.
+	Export(ctx context.Context) (json.RawMessage, error)
+	// Check reports whether exported contents can be imported.
+	Check(data json.RawMessage) error
+	// Import replaces the store's contents with exported ones.
+	Import(ctx context.Context, data json.RawMessage) error
+}
+
+// StoreBackup returns a BackupPart for an entity store.
+func StoreBackup[K ~string, T Entity[K, T]](store Store[K, T]) BackupPart {
+	return &storeBackup[K, T]{store: store}
+}
+
+// storeBackup backs up an entity store.
+type storeBackup[K ~string, T Entity[K, T]] struct {
+	store Store[K, T]
+}
+
+// Export returns every entity in the store.
+func (b *storeBackup[K, T]) Export(ctx context.Context) (json.RawMessage, error) {
+	entities, err := b.store.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(entities)
+}
+
+// decode reads exported entities, checking their IDs.
+func (b *storeBackup[K, T]) decode(data json.RawMessage) ([]T, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+	}
+	entities := make([]T, len(raw))
+	seen := make(map[K]bool, len(raw))
+	for i, r := range raw {
+		if string(r) == "null" {
+			return nil, fmt.Errorf("%w: empty entity", ErrInvalidBackup)
+		}
+		if err := json.Unmarshal(r, &entities[i]); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+		}
+		id := entities[i].EntityID()
+		if id == "" || seen[id] {
+			return nil, fmt.Errorf("%w: missing or duplicate id %q", ErrInvalidBackup, id)
+		}
+		seen[id] = true
+	}
+	return entities, nil
+}
+
+// Check reports whether exported entities can be decoded.
+func (b *storeBackup[K, T]) Check(data json.RawMessage) error {
+	_, err := b.decode(data)
+	return err
+}
+
+// Import replaces the store's entities with exported ones.
+func (b *storeBackup[K, T]) Import(ctx context.Context, data json.RawMessage) error {
+	entities, err := b.decode(data)
+	if err != nil {
+		return err
+	}
+	existing, err := b.store.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, entity := range existing {
+		if err := b.store.Delete(ctx, entity.EntityID()); err != nil {
+			return err
+		}
+	}
+	for _, entity := range entities {
+		if err := b.store.Create(ctx, entity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProjectSettingsBackup returns a BackupPart for project settings.
+func ProjectSettingsBackup(store ProjectSettingsStore) BackupPart {
+	return &settingsBackup{store: store}
+}
+
+// settingsBackup backs up project settings.
+type settingsBackup struct {
+	store ProjectSettingsStore
+}
+
+// Export returns the settings of every project.
+func (b *settingsBackup) Export(ctx context.Context) (json.RawMessage, error) {
+	all, err := b.store.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(all)
+}
+
+// decode reads exported settings, checking their projects.
+func (b *settingsBackup) decode(data json.RawMessage) ([]*models.ProjectSettings, error) {
+	var all []*models.ProjectSettings
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+	}
+	seen := make(map[models.ProjectID]bool, len(all))
+	for _, settings := range all {
+		if settings == nil || settings.ProjectID == "" || seen[settings.ProjectID] {
+			return nil, fmt.Errorf("%w: missing or duplicate project settings", ErrInvalidBackup)
+		}
+		seen[settings.ProjectID] = true
+	}
+	return all, nil
+}
+
+// Check reports whether exported settings can be decoded.
+func (b *settingsBackup) Check(data json.RawMessage) error {
+	_, err := b.decode(data)
+	return err
+}
+
+// Import replaces every project's settings with exported ones.
+func (b *settingsBackup) Import(ctx context.Context, data json.RawMessage) error {
+	all, err := b.decode(data)
+	if err != nil {
+		return err
+	}
+	existing, err := b.store.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, settings := range existing {
+		if err := b.store.Delete(ctx, settings.ProjectID); err != nil {
+			return err
+		}
+	}
+	for _, settings := range all {
+		if err := b.store.Put(ctx, settings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BackupService takes and restores snapshots across all stores.
+//
+// Consistency comes from a write gate rather than from the stores
+// themselves, so it works with any backend: mutating requests routed
+// through Guard hold the gate for reading, and snapshots and restores
+// hold it exclusively. No write is in flight while a snapshot is read.
+//
+// Stores beyond tasks, users, comments and the audit log are backed up
+// once registered as parts with AddPart.
+type BackupService struct {
+	tasks    TaskStore
+	users    UserStore
+	comments CommentStore
+	audit    AuditStore
+	parts    map[string]BackupPart
+
+	gate sync.RWMutex
+}
+
+// NewBackupService creates a new backup service.
+func NewBackupService(tasks TaskStore, users UserStore, comments CommentStore, audit AuditStore) *BackupService {
+	return &BackupService{tasks: tasks, users: users, comments: comments, audit: audit, parts: make(map[string]BackupPart)}
+}
+
+// AddPart registers a store to back up and restore under a name, which
+// names its file in archives. Names are lowercase letters, digits and
+// underscores, and must not clash with another part or a core store.
+func (s *BackupService) AddPart(name string, part BackupPart) error {
+	s.gate.Lock()
+	defer s.gate.Unlock()
+
+	if !backupPartNameRegex.MatchString(name) || slices.Contains(coreBackupFiles, name) || s.parts[name] != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidBackupPart, name)
+	}
+	s.parts[name] = part
+	return nil
+}
+
+// Guard wraps a handler so its mutating requests are excluded while a
+// snapshot or restore is running.
+//
+// The backup and restore routes themselves must not be wrapped, since
+// they take the gate exclusively.
+func (s *BackupService) Guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isMutation(r.Method) {
+			s.gate.RLock()
+			defer s.gate.RUnlock()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isMutation reports whether an HTTP method may change server state.
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+// Snapshot reads a consistent copy of every store. The copy shares no
+// data with the stores, so later writes do not change it.
+func (s *BackupService) Snapshot(ctx context.Context) (*Snapshot, error) {
+	s.gate.Lock()
+	defer s.gate.Unlock()
+
+	return s.snapshot(ctx)
+}
+
+// snapshot reads a copy of every store. The caller must hold the gate.
+func (s *BackupService) snapshot(ctx context.Context) (*Snapshot, error) {
+	snap := &Snapshot{Version: backupFormatVersion, CreatedAt: time.Now(), Parts: make(map[string]json.RawMessage, len(s.parts))}
+	tasks, err := s.tasks.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap.Tasks = make([]*models.Task, len(tasks))
+	for i, task := range tasks {
+		snap.Tasks[i] = task.Clone()
+	}
+	users, err := s.users.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap.Users = make([]*models.User, len(users))
+	for i, user := range users {
+		snap.Users[i] = user.Clone()
+	}
+	comments, err := s.comments.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap.Comments = make([]*models.Comment, len(comments))
+	for i, comment := range comments {
+		c := *comment
+		snap.Comments[i] = &c
+	}
+	entries, err := s.audit.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap.AuditEntries = make([]*models.AuditEntry, len(entries))
+	for i, entry := range entries {
+		e := *entry
+		e.Details = maps.Clone(entry.Details)
+		snap.AuditEntries[i] = &e
+	}
+	for name, part := range s.parts {
+		if snap.Parts[name], err = part.Export(ctx); err != nil {
+			return nil, fmt.Errorf("export %s: %w", name, err)
+		}
+	}
+	return snap, nil
+}
+
+// Restore replaces the contents of the task, user and comment stores,
+// and of the registered parts, with the snapshot.
+//
+// The snapshot is validated in full before anything changes. If a store
+// then fails, the contents from before the restore are put back, so a
+// restore either completes or leaves the stores as they were. Parts the
+// snapshot lacks, as in archives taken before they were registered, are
+// left as they are.
+//
+// The audit log is append-only: entries from the snapshot that are not
+// already present are appended, once everything else is restored, and
+// existing entries are kept.
+func (s *BackupService) Restore(ctx context.Context, snap *Snapshot) error {
+	if err := snap.Validate(); err != nil {
+		return err
+	}
+
+	s.gate.Lock()
+	defer s.gate.Unlock()
+
+	for name, part := range s.parts {
+		if data, ok := snap.Parts[name]; ok {
+			if err := part.Check(data); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+
+	previous, err := s.snapshot(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.replace(ctx, snap); err != nil {
+		if rollbackErr := s.replace(ctx, previous); rollbackErr != nil {
+			return fmt.Errorf("%w (rolling back failed: %v)", err, rollbackErr)
+		}
+		return err
+	}
+
+	entries, err := s.audit.List(ctx)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		seen[entry.ID] = true
+	}
+	for _, entry := range snap.AuditEntries {
+		if seen[entry.ID] {
+			continue
+		}
+		if err := s.audit.Append(ctx, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replace replaces the contents of the task, user and comment stores
+// and of the parts the snapshot has. The caller must hold the gate.
+func (s *BackupService) replace(ctx context.Context, snap *Snapshot) error {
+	tasks, err := s.tasks.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if err := s.tasks.Delete(ctx, task.ID); err != nil {
+			return err
+		}
+	}
+	for _, task := range snap.Tasks {
+		if err := s.tasks.Create(ctx, task.Clone()); err != nil {
+			return err
+		}
+	}
+
+	users, err := s.users.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		if err := s.users.Delete(ctx, user.ID); err != nil {
+			return err
+		}
+	}
+	for _, user := range snap.Users {
+		if err := s.users.Create(ctx, user.Clone()); err != nil {
+			return err
+		}
+	}
+
+	comments, err := s.comments.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, comment := range comments {
+		if err := s.comments.Delete(ctx, comment.ID); err != nil {
+			return err
+		}
+	}
+	for _, comment := range snap.Comments {
+		c := *comment
+		if err := s.comments.Create(ctx, &c); err != nil {
+			return err
+		}
+	}
+
+	for name, part := range s.parts {
+		data, ok := snap.Parts[name]
+		if !ok {
+			continue
+		}
+		if err := part.Import(ctx, data); err != nil {
+			return fmt.Errorf("import %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// WriteArchive writes the snapshot as a gzipped tar archive with one
+// JSON document per store plus a manifest.
+func WriteArchive(w io.Writer, snap *Snapshot) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifest := map[string]any{"version": snap.Version, "created_at": snap.CreatedAt}
+	files := []struct {
+		name string
+		v    any
+	}{
+		{"manifest.json", manifest},
+		{"tasks.json", snap.Tasks},
+		{"users.json", snap.Users},
+		{"comments.json", snap.Comments},
+		{"audit.json", snap.AuditEntries},
+	}
+	names := make([]string, 0, len(snap.Parts))
+	for name := range snap.Parts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		files = append(files, struct {
+			name string
+			v    any
+		}{name + ".json", snap.Parts[name]})
+	}
+
+	for _, f := range files {
+		data, err := json.Marshal(f.v)
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{Name: f.name, Mode: 0o600, Size: int64(len(data)), ModTime: snap.CreatedAt}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// ReadArchive reads a snapshot written by WriteArchive.
+//
+// Returns ErrBackupTooLarge if the archive decompresses to more than
+// maxArchiveBytes.
+func ReadArchive(r io.Reader) (*Snapshot, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	snap := &Snapshot{}
+	tr := tar.NewReader(&cappedReader{r: gz, n: maxArchiveBytes})
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var target any
+		switch hdr.Name {
+		case "manifest.json":
+			target = snap
+		case "tasks.json":
+			target = &snap.Tasks
+		case "users.json":
+			target = &snap.Users
+		case "comments.json":
+			target = &snap.Comments
+		case "audit.json":
+			target = &snap.AuditEntries
+		default:
+			name, ok := strings.CutSuffix(hdr.Name, ".json")
+			if !ok || !backupPartNameRegex.MatchString(name) {
+				continue
+			}
+			var data json.RawMessage
+			if err := json.NewDecoder(tr).Decode(&data); err != nil {
+				return nil, fmt.Errorf("decode %s: %w", hdr.Name, err)
+			}
+			if snap.Parts == nil {
+				snap.Parts = make(map[string]json.RawMessage)
+			}
+			snap.Parts[name] = data
+			continue
+		}
+		if err := json.NewDecoder(tr).Decode(target); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", hdr.Name, err)
+		}
+	}
+
+	if snap.Version != backupFormatVersion {
+		return nil, ErrUnsupportedBackup
+	}
+	return snap, nil
+}
+
+// cappedReader reads from r until n bytes have been read, then fails
+// with ErrBackupTooLarge.
+type cappedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *cappedReader) Read(p []byte) (int, error) {
+	if c.n <= 0 {
+		return 0, ErrBackupTooLarge
+	}
+	if int64(len(p)) > c.n {
+		p = p[:c.n]
+	}
+	n, err := c.r.Read(p)
+	c.n -= int64(n)
+	return n, err
+}
+
+// BackupDestination receives scheduled backup archives.
+type BackupDestination interface {
+	// Write stores an archive under the given name.
+	Write(ctx context.Context, name string, archive io.Reader) error
+}
+
+// DirectoryDestination writes backup archives to a local directory.
+type DirectoryDestination struct {
+	Dir string
+}
+
+// Write stores an archive as a file in the directory.
+func (d DirectoryDestination) Write(ctx context.Context, name string, archive io.Reader) error {
+	if err := os.MkdirAll(d.Dir, 0o700); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(d.Dir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, archive); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// BackupTo takes a snapshot and writes it to dest.
+func (s *BackupService) BackupTo(ctx context.Context, dest BackupDestination) error {
+	snap, err := s.Snapshot(ctx)
+	if err != nil {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(WriteArchive(pw, snap))
+	}()
+
+	name := fmt.Sprintf("tasktracker-%s.tar.gz", snap.CreatedAt.UTC().Format("20060102T150405Z"))
+	err = dest.Write(ctx, name, pr)
+	pr.CloseWithError(err)
+	return err
+}
+
+// StartSchedule backs up to dest every interval until ctx is cancelled,
+// skipping backups while mode, which may be nil, is enabled. It returns
+// ErrInvalidInterval, without starting, if interval is not positive.
+func (s *BackupService) StartSchedule(ctx context.Context, interval time.Duration, dest BackupDestination, mode *MaintenanceMode) error {
+	if interval <= 0 {
+		return ErrInvalidInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if mode.Enabled() {
+					continue
+				}
+				if err := s.BackupTo(ctx, dest); err != nil {
+					log.Printf("backup: scheduled backup failed: %v", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// BackupHandler handles HTTP requests for backup and restore.
+type BackupHandler struct {
+	service *BackupService
+}
+
+// NewBackupHandler creates a new backup handler.
+func NewBackupHandler(service *BackupService) *BackupHandler {
+	return &BackupHandler{service: service}
+}
+
+// authorize checks that the caller may manage the server.
+func (h *BackupHandler) authorize(w http.ResponseWriter, r *http.Request) bool {
+	caller, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return false
+	}
+	if !caller.HasPermission("manage") {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// Backup handles POST /admin/backup requests by streaming an archive.
+func (h *BackupHandler) Backup(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+
+	snap, err := h.service.Snapshot(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to take snapshot", err)
+		return
+	}
+
+	name := fmt.Sprintf("tasktracker-%s.tar.gz", snap.CreatedAt.UTC().Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	w.WriteHeader(http.StatusOK)
+	if err := WriteArchive(w, snap); err != nil {
+		log.Printf("backup: failed to stream archive: %v", err)
+	}
+}
+
+// Restore handles POST /admin/restore requests with an archive body.
+//
+// Archives over maxRestoreBodyBytes, or decompressing to over
+// maxArchiveBytes, yield 413.
+func (h *BackupHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r) {
+		return
+	}
+
+	snap, err := ReadArchive(http.MaxBytesReader(w, r.Body, maxRestoreBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) || errors.Is(err, ErrBackupTooLarge) {
+			http.Error(w, "backup archive too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "invalid backup archive", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Restore(r.Context(), snap); err != nil {
+		if errors.Is(err, ErrInvalidBackup) || errors.Is(err, ErrUnsupportedBackup) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeServerError(w, r, "failed to restore backup", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/example/tasktracker/pkg/benchgate"
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// benchStore returns an in-memory store holding n tasks and their IDs.
+func benchStore(b *testing.B, n int) (*InMemoryTaskStore, []models.TaskID) {
+	b.Helper()
+	store := NewInMemoryTaskStore()
+	ids := make([]models.TaskID, n)
+	for i := range ids {
+		task := models.NewTask(fmt.Sprintf("task %d", i), "project")
+		task.Description = "A description long enough to be representative of a real task."
+		task.Tags = []string{"backend", "bench"}
+		if err := store.Create(context.Background(), task); err != nil {
+			b.Fatal(err)
+		}
+		ids[i] = task.ID
+	}
+	return store, ids
+}
+
+func BenchmarkInMemoryTaskStore(b *testing.B) {
+	b.Run("Get", benchmarkInMemoryTaskStoreGet)
+	b.Run("GetAll", benchmarkInMemoryTaskStoreGetAll)
+	b.Run("Create", benchmarkInMemoryTaskStoreCreate)
+	b.Run("Update", benchmarkInMemoryTaskStoreUpdate)
+}
+
+func benchmarkInMemoryTaskStoreGet(b *testing.B) {
+	ctx := context.Background()
+	store, ids := benchStore(b, 1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := store.Get(ctx, ids[i%len(ids)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkInMemoryTaskStoreGetAll(b *testing.B) {
+	ctx := context.Background()
+	store, _ := benchStore(b, 1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := store.GetAll(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkInMemoryTaskStoreCreate(b *testing.B) {
+	ctx := context.Background()
+	store := NewInMemoryTaskStore()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := store.Create(ctx, models.NewTask("task", "project")); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkInMemoryTaskStoreUpdate(b *testing.B) {
+	ctx := context.Background()
+	store, ids := benchStore(b, 1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		task, err := store.Get(ctx, ids[i%len(ids)])
+		if err != nil {
+			b.Fatal(err)
+		}
+		task.Title = fmt.Sprintf("task %d", i)
+		if err := store.Update(ctx, task); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEventSourcedTaskStoreGet(b *testing.B) {
+	ctx := context.Background()
+	store := NewEventSourcedTaskStore(0)
+	task := models.NewTask("task", "project")
+	if err := store.Create(ctx, task); err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < 120; i++ {
+		task.Title = fmt.Sprintf("task %d", i)
+		if err := store.Update(ctx, task); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := store.Get(ctx, task.ID); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCoalescingTaskStoreGetParallel(b *testing.B) {
+	next, ids := benchStore(b, 10)
+	store := NewCoalescingTaskStore(next)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		ctx := context.Background()
+		for i := 0; pb.Next(); i++ {
+			if _, err := store.Get(ctx, ids[i%len(ids)]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkTaskHandlerList(b *testing.B) {
+	for _, n := range []int{100, 1000} {
+		b.Run(fmt.Sprintf("tasks=%d", n), benchmarkTaskHandlerList(n))
+	}
+}
+
+// benchmarkTaskHandlerList benchmarks listing a store of n tasks.
+func benchmarkTaskHandlerList(n int) func(*testing.B) {
+	return func(b *testing.B) {
+		store, _ := benchStore(b, n)
+		h := NewTaskHandler(store)
+		user, err := models.NewUser("bench", "bench@example.com")
+		if err != nil {
+			b.Fatal(err)
+		}
+		req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		req = req.WithContext(WithUser(req.Context(), user))
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			w := httptest.NewRecorder()
+			h.List(w, req)
+			if w.Code != http.StatusOK {
+				b.Fatalf("status = %d", w.Code)
+			}
+		}
+	}
+}
+
+func BenchmarkVisibleTasks(b *testing.B) {
+	store, _ := benchStore(b, 1000)
+	tasks, err := store.GetAll(context.Background())
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i, task := range tasks {
+		if i%3 == 0 {
+			if err := task.SetVisibility(models.TaskVisibilityRestricted, []models.UserID{"someone-else"}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	user, err := models.NewUser("bench", "bench@example.com")
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := WithUser(context.Background(), user)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		visibleTasks(ctx, tasks)
+	}
+}
+
+// TestBenchmarkBaseline fails when a benchmark allocates more per
+// operation than testdata/bench/baseline.txt records.
+func TestBenchmarkBaseline(t *testing.T) {
+	benchgate.Check(t, "../../testdata/bench/baseline.txt", "github.com/example/tasktracker/pkg/handlers", map[string]func(*testing.B){
+		"BenchmarkInMemoryTaskStore/Get":          benchmarkInMemoryTaskStoreGet,
+		"BenchmarkInMemoryTaskStore/GetAll":       benchmarkInMemoryTaskStoreGetAll,
+		"BenchmarkInMemoryTaskStore/Create":       benchmarkInMemoryTaskStoreCreate,
+		"BenchmarkInMemoryTaskStore/Update":       benchmarkInMemoryTaskStoreUpdate,
+		"BenchmarkEventSourcedTaskStoreGet":       BenchmarkEventSourcedTaskStoreGet,
+		"BenchmarkCoalescingTaskStoreGetParallel": BenchmarkCoalescingTaskStoreGetParallel,
+		"BenchmarkTaskHandlerList/tasks=100":      benchmarkTaskHandlerList(100),
+		"BenchmarkTaskHandlerList/tasks=1000":     benchmarkTaskHandlerList(1000),
+		"BenchmarkVisibleTasks":                   BenchmarkVisibleTasks,
+	})
+}
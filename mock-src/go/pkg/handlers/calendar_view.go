@@ -0,0 +1,135 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"time"
+)
+
+const (
+	// calendarDateLayout is the date format of calendar bounds and buckets.
+	calendarDateLayout = "2006-01-02"
+	// maxCalendarRangeDays caps the span of a calendar view request.
+	maxCalendarRangeDays = 366
+)
+
+// CalendarBucket holds the tasks due within one day or week.
+//
+// Date is the day, or the Monday starting the week, in the requester's
+// timezone.
+type CalendarBucket struct {
+	Date  string          `json:"date"`
+	Tasks []*TaskResponse `json:"tasks"`
+}
+
+// CalendarView is the response body for the task calendar.
+type CalendarView struct {
+	Timezone string            `json:"timezone"`
+	GroupBy  string            `json:"group_by"`
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Buckets  []*CalendarBucket `json:"buckets"`
+}
+
+// Calendar handles GET /tasks/calendar?from=&to=&group_by=day|week requests.
+//
+// from and to are inclusive dates (YYYY-MM-DD) interpreted in the
+// requester's timezone, taken from the tz parameter, the authenticated
+// user's preferences, or UTC. Only published tasks the caller may see
+// with a due date in range are returned; empty buckets are omitted.
+// Tasks of archived projects are left out unless include_archived=true.
+func (h *TaskHandler) Calendar(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	loc := time.UTC
+	if user, ok := UserFromContext(r.Context()); ok {
+		loc = user.Preferences.Location()
+	}
+	if tz := query.Get("tz"); tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			http.Error(w, "invalid tz", http.StatusBadRequest)
+			return
+		}
+	}
+
+	groupBy := query.Get("group_by")
+	if groupBy == "" {
+		groupBy = "day"
+	}
+	if groupBy != "day" && groupBy != "week" {
+		http.Error(w, "group_by must be day or week", http.StatusBadRequest)
+		return
+	}
+
+	from, err := time.ParseInLocation(calendarDateLayout, query.Get("from"), loc)
+	if err != nil {
+		http.Error(w, "from must be a date (YYYY-MM-DD)", http.StatusBadRequest)
+		return
+	}
+	to, err := time.ParseInLocation(calendarDateLayout, query.Get("to"), loc)
+	if err != nil {
+		http.Error(w, "to must be a date (YYYY-MM-DD)", http.StatusBadRequest)
+		return
+	}
+	end := to.AddDate(0, 0, 1)
+	if !end.After(from) || end.After(from.AddDate(0, 0, maxCalendarRangeDays)) {
+		http.Error(w, "to must not be before from, and the range is limited to a year", http.StatusBadRequest)
+		return
+	}
+
+	archived, ok := h.archivedFilter(w, r)
+	if !ok {
+		return
+	}
+	tasks, err := h.store.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+	tasks = visibleTasks(r.Context(), publishedTasks(tasks))
+
+	buckets := make(map[string]*CalendarBucket)
+	for _, task := range tasks {
+		if archived[task.ProjectID] || task.DueDate == nil || task.DueDate.Before(from) || !task.DueDate.Before(end) {
+			continue
+		}
+		key := calendarBucketDate(task.DueDate.In(loc), groupBy)
+		bucket, ok := buckets[key]
+		if !ok {
+			bucket = &CalendarBucket{Date: key, Tasks: make([]*TaskResponse, 0)}
+			buckets[key] = bucket
+		}
+		bucket.Tasks = append(bucket.Tasks, toResponse(task))
+	}
+
+	view := &CalendarView{
+		Timezone: loc.String(),
+		GroupBy:  groupBy,
+		From:     from.Format(calendarDateLayout),
+		To:       to.Format(calendarDateLayout),
+		Buckets:  make([]*CalendarBucket, 0, len(buckets)),
+	}
+	for _, bucket := range buckets {
+		sort.Slice(bucket.Tasks, func(i, j int) bool {
+			return bucket.Tasks[i].DueDate.Before(*bucket.Tasks[j].DueDate)
+		})
+		view.Buckets = append(view.Buckets, bucket)
+	}
+	sort.Slice(view.Buckets, func(i, j int) bool {
+		return view.Buckets[i].Date < view.Buckets[j].Date
+	})
+
+	writeJSON(w, http.StatusOK, view)
+}
+
+// calendarBucketDate returns the bucket key for a local time: its date,
+// or the date of the Monday of its week.
+func calendarBucketDate(t time.Time, groupBy string) string {
+	if groupBy == "week" {
+		offset := (int(t.Weekday()) + 6) % 7
+		t = t.AddDate(0, 0, -offset)
+	}
+	return t.Format(calendarDateLayout)
+}
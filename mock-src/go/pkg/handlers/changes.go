@@ -0,0 +1,260 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// ChangeType identifies the kind of change recorded in the change feed.
+type ChangeType string
+
+const (
+	// ChangeTypeUpsert records a created or updated entity.
+	ChangeTypeUpsert ChangeType = "upsert"
+	// ChangeTypeDelete records a deleted entity.
+	ChangeTypeDelete ChangeType = "delete"
+)
+
+const (
+	// defaultChangePageSize is the number of changes returned when no limit is given.
+	defaultChangePageSize = 100
+	// maxChangePageSize caps the limit query parameter.
+	maxChangePageSize = 1000
+)
+
+// ErrCursorExpired is returned when a cursor points before the oldest
+// retained change; the client must resynchronize from scratch.
+var ErrCursorExpired = errors.New("change cursor has expired")
+
+// Change is a single entry in the change feed.
+//
+// Seq is strictly increasing across all entities. Upserts carry the
+// entity state at the time of the change.
+type Change struct {
+	Seq        int64        `json:"seq"`
+	Type       ChangeType   `json:"type"`
+	EntityType string       `json:"entity_type"`
+	EntityID   string       `json:"entity_id"`
+	Task       *models.Task `json:"task,omitempty"`
+	At         time.Time    `json:"at"`
+}
+
+// ChangeLog is an in-memory, ordered log of entity changes.
+//
+// When maxEntries is positive the oldest changes are discarded once the
+// log grows beyond it.
+type ChangeLog struct {
+	mu         sync.RWMutex
+	changes    []*Change
+	nextSeq    int64
+	maxEntries int
+}
+
+// NewChangeLog creates an empty change log.
+func NewChangeLog(maxEntries int) *ChangeLog {
+	return &ChangeLog{nextSeq: 1, maxEntries: maxEntries}
+}
+
+// Record appends a change, assigning its sequence number and time.
+func (l *ChangeLog) Record(change *Change) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	change.Seq = l.nextSeq
+	change.At = time.Now()
+	l.nextSeq++
+	l.changes = append(l.changes, change)
+	if l.maxEntries > 0 && len(l.changes) > l.maxEntries {
+		drop := len(l.changes) - l.maxEntries
+		l.changes = append([]*Change(nil), l.changes[drop:]...)
+	}
+}
+
+// Since returns up to limit changes with a sequence number greater than
+// since, and whether more are available. A negative since starts from
+// the oldest retained change.
+//
+// Returns ErrCursorExpired if changes after since have been discarded.
+func (l *ChangeLog) Since(since int64, limit int) ([]*Change, bool, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if len(l.changes) > 0 && since >= 0 && since < l.changes[0].Seq-1 {
+		return nil, false, ErrCursorExpired
+	}
+
+	result := make([]*Change, 0)
+	for _, change := range l.changes {
+		if change.Seq <= since {
+			continue
+		}
+		if len(result) == limit {
+			return result, true, nil
+		}
+		result = append(result, change)
+	}
+	return result, false, nil
+}
+
+// Latest returns the sequence number of the most recent change, or 0.
+func (l *ChangeLog) Latest() int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.nextSeq - 1
+}
+
+// ChangeTrackingTaskStore is a TaskStore decorator that records every
+// successful mutation in a change log.
+//
+// Mutations are serialized so the log order matches the order in which
+// the wrapped store applied them.
+type ChangeTrackingTaskStore struct {
+	mu   sync.Mutex
+	next TaskStore
+	log  *ChangeLog
+}
+
+// NewChangeTrackingTaskStore wraps a task store so its mutations feed log.
+func NewChangeTrackingTaskStore(next TaskStore, log *ChangeLog) *ChangeTrackingTaskStore {
+	return &ChangeTrackingTaskStore{next: next, log: log}
+}
+
+// Get retrieves a task by ID.
+func (s *ChangeTrackingTaskStore) Get(ctx context.Context, id models.TaskID) (*models.Task, error) {
+	return s.next.Get(ctx, id)
+}
+
+// GetAll retrieves all tasks.
+func (s *ChangeTrackingTaskStore) GetAll(ctx context.Context) ([]*models.Task, error) {
+	return s.next.GetAll(ctx)
+}
+
+// Create stores a new task and records an upsert.
+func (s *ChangeTrackingTaskStore) Create(ctx context.Context, task *models.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.next.Create(ctx, task); err != nil {
+		return err
+	}
+	s.recordUpsert(task)
+	return nil
+}
+
+// Update updates an existing task and records an upsert.
+func (s *ChangeTrackingTaskStore) Update(ctx context.Context, task *models.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.next.Update(ctx, task); err != nil {
+		return err
+	}
+	s.recordUpsert(task)
+	return nil
+}
+
+// Delete removes a task by ID and records a delete.
+func (s *ChangeTrackingTaskStore) Delete(ctx context.Context, id models.TaskID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.next.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.log.Record(&Change{Type: ChangeTypeDelete, EntityType: "task", EntityID: string(id)})
+	return nil
+}
+
+// recordUpsert records a copy of the task's current state.
+func (s *ChangeTrackingTaskStore) recordUpsert(task *models.Task) {
+	s.log.Record(&Change{Type: ChangeTypeUpsert, EntityType: "task", EntityID: string(task.ID), Task: task.Clone()})
+}
+
+// ChangesHandler handles HTTP requests for the change feed.
+type ChangesHandler struct {
+	log *ChangeLog
+}
+
+// NewChangesHandler creates a new change feed handler.
+func NewChangesHandler(log *ChangeLog) *ChangesHandler {
+	return &ChangesHandler{log: log}
+}
+
+// ChangesResponse is the response body for the change feed.
+//
+// Clients pass NextCursor as the since parameter of their next request.
+type ChangesResponse struct {
+	Changes    []*Change `json:"changes"`
+	NextCursor string    `json:"next_cursor"`
+	HasMore    bool      `json:"has_more"`
+}
+
+// List handles GET /changes?since=<cursor>&limit=<n> requests.
+//
+// An empty cursor starts from the beginning of the retained log. An
+// expired cursor yields 410 Gone, signalling a full resync. Upserts of
+// tasks the caller may not see are left out.
+func (h *ChangesHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	since := int64(-1)
+	if raw := query.Get("since"); raw != "" {
+		var err error
+		since, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || since < 0 {
+			http.Error(w, "invalid since cursor", http.StatusBadRequest)
+			return
+		}
+	}
+
+	limit := defaultChangePageSize
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		if n > maxChangePageSize {
+			n = maxChangePageSize
+		}
+		limit = n
+	}
+
+	changes, more, err := h.log.Since(since, limit)
+	if err != nil {
+		if errors.Is(err, ErrCursorExpired) {
+			http.Error(w, err.Error(), http.StatusGone)
+			return
+		}
+		writeServerError(w, r, "failed to read changes", err)
+		return
+	}
+
+	next := since
+	if len(changes) > 0 {
+		next = changes[len(changes)-1].Seq
+	} else if next < 0 {
+		next = h.log.Latest()
+	}
+
+	visible := make([]*Change, 0, len(changes))
+	for _, change := range changes {
+		if change.Task == nil || visibleTo(r.Context(), change.Task) {
+			visible = append(visible, change)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, &ChangesResponse{
+		Changes:    visible,
+		NextCursor: strconv.FormatInt(next, 10),
+		HasMore:    more,
+	})
+}
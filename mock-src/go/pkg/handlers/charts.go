@@ -0,0 +1,150 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// Chart ranges.
+const (
+	defaultChartDays = 30
+	maxChartDays     = 366
+)
+
+// Chart data sources, reported so clients can flag approximate charts.
+const (
+	// chartSourceHistory means the data was replayed from task events.
+	chartSourceHistory = "history"
+	// chartSourceSnapshot means the data was estimated from current
+	// task state because the store keeps no history.
+	chartSourceSnapshot = "snapshot"
+)
+
+// BurndownChart is the response body for a project's burndown chart.
+type BurndownChart struct {
+	ProjectID models.ProjectID       `json:"project_id"`
+	Source    string                 `json:"source"`
+	Points    []models.BurndownPoint `json:"points"`
+}
+
+// FlowChart is the response body for a project's cumulative flow
+// diagram. Statuses lists the bands bottom to top.
+type FlowChart struct {
+	ProjectID models.ProjectID    `json:"project_id"`
+	Statuses  []models.TaskStatus `json:"statuses"`
+	Points    []models.FlowPoint  `json:"points"`
+}
+
+// ChartHandler serves chart datasets computed from task history, so
+// clients only need a plotting library to draw them.
+type ChartHandler struct {
+	tasks TaskStore
+}
+
+// NewChartHandler creates a new chart handler. Exact charts need a task
+// store that implements TaskEventLog, such as EventSourcedTaskStore.
+func NewChartHandler(tasks TaskStore) *ChartHandler {
+	return &ChartHandler{tasks: tasks}
+}
+
+// parseChartRange reads the from and to query parameters, dates in the
+// caller's time zone, defaulting to the last 30 days.
+func parseChartRange(r *http.Request, loc *time.Location) (from, to time.Time, err error) {
+	q := r.URL.Query()
+	to = time.Now().In(loc)
+	if raw := q.Get("to"); raw != "" {
+		if to, err = time.ParseInLocation("2006-01-02", raw, loc); err != nil {
+			return from, to, errors.New("to must be a date in YYYY-MM-DD format")
+		}
+	}
+	from = to.AddDate(0, 0, -(defaultChartDays - 1))
+	if raw := q.Get("from"); raw != "" {
+		if from, err = time.ParseInLocation("2006-01-02", raw, loc); err != nil {
+			return from, to, errors.New("from must be a date in YYYY-MM-DD format")
+		}
+	}
+	if to.Before(from) {
+		return from, to, errors.New("from must not be after to")
+	}
+	if to.Sub(from) >= maxChartDays*24*time.Hour {
+		return from, to, errors.New("chart range is limited to 366 days")
+	}
+	return from, to, nil
+}
+
+// Burndown handles GET /projects/{id}/charts/burndown?from=&to= requests.
+//
+// Without task history the chart is estimated from current state and
+// its source is "snapshot".
+func (h *ChartHandler) Burndown(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	loc := DateFormatterFromContext(r.Context()).Location()
+	from, to, err := parseChartRange(r, loc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	points, source, err := projectBurndown(r.Context(), h.tasks, projectID, from, to, loc)
+	if err != nil {
+		writeServerError(w, r, "failed to compute burndown", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &BurndownChart{ProjectID: projectID, Source: source, Points: points})
+}
+
+// CumulativeFlow handles GET /projects/{id}/charts/cfd?from=&to= requests.
+//
+// Returns 501 if the task store keeps no history.
+func (h *ChartHandler) CumulativeFlow(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	loc := DateFormatterFromContext(r.Context()).Location()
+	from, to, err := parseChartRange(r, loc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	log, ok := h.tasks.(TaskEventLog)
+	if !ok {
+		http.Error(w, "cumulative flow is not supported by this store", http.StatusNotImplemented)
+		return
+	}
+
+	events, err := log.AllEvents(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to load task history", err)
+		return
+	}
+	points := models.CumulativeFlow(events, projectID, from, to, loc)
+
+	writeJSON(w, http.StatusOK, &FlowChart{ProjectID: projectID, Statuses: models.FlowStatuses(points), Points: points})
+}
+
+// projectBurndown computes a project's burndown, replaying history when
+// the store keeps it and estimating from current state otherwise. It
+// returns the chart source along with the points.
+func projectBurndown(ctx context.Context, tasks TaskStore, projectID models.ProjectID, from, to time.Time, loc *time.Location) ([]models.BurndownPoint, string, error) {
+	if log, ok := tasks.(TaskEventLog); ok {
+		events, err := log.AllEvents(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		return models.BurndownFromFlow(models.CumulativeFlow(events, projectID, from, to, loc)), chartSourceHistory, nil
+	}
+
+	all, err := tasks.GetAll(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	inProject := make([]*models.Task, 0, len(all))
+	for _, task := range publishedTasks(all) {
+		if task.ProjectID == projectID {
+			inProject = append(inProject, task)
+		}
+	}
+	return models.Burndown(inProject, from, to, loc), chartSourceSnapshot, nil
+}
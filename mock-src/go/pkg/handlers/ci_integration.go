@@ -0,0 +1,216 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// ciKeySource namespaces CI pipeline keys in the webhook key store.
+const ciKeySource = "ci"
+
+// CIConfig configures the CI integration.
+//
+// GitHubSecret is the webhook secret of a GitHub repository or
+// organization webhook sending workflow_run events; GitLabToken is the
+// secret token of a GitLab pipeline hook. An empty secret disables that
+// provider. Tasks are opened in ProjectID with Tags.
+type CIConfig struct {
+	ProjectID    models.ProjectID
+	GitHubSecret string
+	GitLabToken  string
+	Tags         []string
+}
+
+// CIHandler opens a task when a CI pipeline fails and closes it when
+// the pipeline passes again.
+//
+// Failures are deduplicated per pipeline and branch: while the task for
+// a failing branch is open, further failures only update its link to
+// the latest run. Once closed, the next failure opens a new task.
+type CIHandler struct {
+	config   CIConfig
+	keys     WebhookKeyStore
+	tasks    TaskStore
+	projects ProjectStore
+}
+
+// NewCIHandler creates a new CI integration handler. Pipeline keys are
+// kept in keys, which may be shared with the webhook inbox. projects
+// may be nil, in which case no project requires approval.
+func NewCIHandler(config CIConfig, keys WebhookKeyStore, tasks TaskStore, projects ProjectStore) *CIHandler {
+	if config.Tags == nil {
+		config.Tags = []string{"ci"}
+	}
+	return &CIHandler{config: config, keys: keys, tasks: tasks, projects: projects}
+}
+
+// GitHub handles POST /integrations/github requests.
+//
+// Events other than workflow_run, such as ping, are acknowledged with
+// 204 and ignored.
+func (h *CIHandler) GitHub(w http.ResponseWriter, r *http.Request) {
+	body, ok := readIntegrationBody(w, r, h.config.GitHubSecret)
+	if !ok {
+		return
+	}
+	sig, _ := strings.CutPrefix(r.Header.Get("X-Hub-Signature-256"), "sha256=")
+	if !validHMACSHA256(h.config.GitHubSecret, sig, body) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	if r.Header.Get("X-GitHub-Event") != "workflow_run" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	event, err := models.ParseGitHubWorkflowRun(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.handle(w, r, event)
+}
+
+// GitLab handles POST /integrations/gitlab requests.
+//
+// Events other than pipeline hooks are acknowledged with 204 and ignored.
+func (h *CIHandler) GitLab(w http.ResponseWriter, r *http.Request) {
+	body, ok := readIntegrationBody(w, r, h.config.GitLabToken)
+	if !ok {
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Gitlab-Token")), []byte(h.config.GitLabToken)) != 1 {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+	if r.Header.Get("X-Gitlab-Event") != "Pipeline Hook" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	event, err := models.ParseGitLabPipeline(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.handle(w, r, event)
+}
+
+// readIntegrationBody reads an integration webhook body, writing an
+// error and returning false if the integration has no secret configured
+// or the body cannot be read.
+func readIntegrationBody(w http.ResponseWriter, r *http.Request, secret string) ([]byte, bool) {
+	if secret == "" {
+		http.Error(w, "integration not configured", http.StatusNotFound)
+		return nil, false
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBulkRequestBodyBytes))
+	if err != nil {
+		writeDecodeError(w, err)
+		return nil, false
+	}
+	return body, true
+}
+
+// handle opens, updates or closes the task of a pipeline run's branch.
+// Runs that neither failed nor passed are acknowledged with 204. A
+// passing run completes the task through its project's workflow, or
+// submits it for review if the project requires approval.
+func (h *CIHandler) handle(w http.ResponseWriter, r *http.Request, event *models.PipelineEvent) {
+	if event.Outcome == models.CIOutcomeOther {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	task, err := openKeyedTask(r.Context(), h.keys, h.tasks, ciKeySource, event.Key())
+	if err != nil {
+		writeServerError(w, r, "failed to look up pipeline task", err)
+		return
+	}
+
+	if event.Outcome == models.CIOutcomePassed {
+		if task == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		updated, _, err := moveTask(r.Context(), h.tasks, h.projects, task.ID, models.TaskStatusCompleted, func(t *models.Task) {
+			t.Description += fmt.Sprintf("\n\nFixed by run %d: %s", event.RunID, event.URL)
+		})
+		h.respond(w, updated, err, http.StatusOK)
+		return
+	}
+
+	if task != nil {
+		updated, err := updateTask(r.Context(), h.tasks, task.ID, func(t *models.Task) bool {
+			t.Description = ciDescription(event)
+			return true
+		})
+		h.respond(w, updated, err, http.StatusOK)
+		return
+	}
+
+	created := models.NewTaskWithOptions(event.Title(), h.config.ProjectID,
+		models.WithDescription(ciDescription(event)),
+		models.WithTags(h.config.Tags),
+		models.WithPriority(models.TaskPriorityHigh),
+	)
+	if err := created.SanitizeContent(); err != nil {
+		writeContentError(w, err)
+		return
+	}
+	err = h.tasks.Create(r.Context(), created)
+	if err == nil {
+		err = h.keys.Link(r.Context(), ciKeySource, event.Key(), created.ID)
+	}
+	h.respond(w, created, err, http.StatusCreated)
+}
+
+// openKeyedTask returns the open task a webhook key store links to a
+// source's key, or nil if there is none.
+func openKeyedTask(ctx context.Context, keys WebhookKeyStore, tasks TaskStore, sourceID, key string) (*models.Task, error) {
+	taskID, ok, err := keys.Lookup(ctx, sourceID, key)
+	if err != nil || !ok {
+		return nil, err
+	}
+	task, err := tasks.Get(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !task.IsOpen() {
+		return nil, nil
+	}
+	return task, nil
+}
+
+// respond writes the result of a task write.
+func (h *CIHandler) respond(w http.ResponseWriter, task *models.Task, err error, status int) {
+	if err != nil {
+		if writeHookRejection(w, err) {
+			return
+		}
+		if errors.Is(err, models.ErrUnknownStatus) || errors.Is(err, models.ErrTransitionNotAllowed) || errors.Is(err, models.ErrWIPLimitReached) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "failed to save pipeline task", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, status, toResponse(task))
+}
+
+// ciDescription describes a failing run, linking back to it.
+func ciDescription(event *models.PipelineEvent) string {
+	return fmt.Sprintf("Pipeline %s failed on branch %s of %s.\n\nLatest failing run %d: %s",
+		event.Pipeline, event.Branch, event.Repository, event.RunID, event.URL)
+}
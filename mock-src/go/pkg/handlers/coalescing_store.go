@@ -0,0 +1,148 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// errReadPanicked is what callers waiting on a read that panicked get.
+var errReadPanicked = errors.New("coalesced task read panicked")
+
+// flight is a read in progress that concurrent callers wait on.
+type flight struct {
+	done  chan struct{}
+	task  *models.Task
+	tasks []*models.Task
+	err   error
+}
+
+// CoalescingStats counts reads that reached the store and reads that
+// were served by joining one already in progress.
+type CoalescingStats struct {
+	Reads     int64 `json:"reads"`
+	Coalesced int64 `json:"coalesced"`
+}
+
+// CoalescingTaskStore is a TaskStore decorator that merges identical
+// concurrent reads into a single call to the wrapped store.
+//
+// Every caller receives its own copy of the result. Reads run detached
+// from the first caller's cancellation so one client going away does
+// not fail the others. A write through the store ends the sharing of
+// reads in progress that it affects, so a read started after a write
+// never gets data from before it. Flights are keyed by task ID only, so
+// the store must sit beneath decorators that filter by user, such as
+// VisibilityTaskStore.
+type CoalescingTaskStore struct {
+	next TaskStore
+
+	mu      sync.Mutex
+	flights map[string]*flight
+	stats   CoalescingStats
+}
+
+// NewCoalescingTaskStore wraps a task store with read coalescing.
+func NewCoalescingTaskStore(next TaskStore) *CoalescingTaskStore {
+	return &CoalescingTaskStore{next: next, flights: make(map[string]*flight)}
+}
+
+// Stats returns the read and coalesced counts so far.
+func (s *CoalescingTaskStore) Stats() CoalescingStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stats
+}
+
+// do runs read once for all concurrent callers using the same key.
+func (s *CoalescingTaskStore) do(ctx context.Context, key string, read func(ctx context.Context, f *flight)) (*flight, error) {
+	s.mu.Lock()
+	if f, ok := s.flights[key]; ok {
+		s.stats.Coalesced++
+		s.mu.Unlock()
+		select {
+		case <-f.done:
+			return f, f.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	f := &flight{done: make(chan struct{})}
+	s.flights[key] = f
+	s.stats.Reads++
+	s.mu.Unlock()
+
+	completed := false
+	defer func() {
+		if !completed {
+			f.err = errReadPanicked
+		}
+		s.mu.Lock()
+		if s.flights[key] == f {
+			delete(s.flights, key)
+		}
+		s.mu.Unlock()
+		close(f.done)
+	}()
+	read(context.WithoutCancel(ctx), f)
+	completed = true
+	return f, f.err
+}
+
+// forget stops later reads of a task, and of all tasks, from joining
+// reads already in progress. The reads finish for their callers.
+func (s *CoalescingTaskStore) forget(id models.TaskID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.flights, "get:"+string(id))
+	delete(s.flights, "all")
+}
+
+// Get retrieves a task by ID.
+func (s *CoalescingTaskStore) Get(ctx context.Context, id models.TaskID) (*models.Task, error) {
+	f, err := s.do(ctx, "get:"+string(id), func(ctx context.Context, f *flight) {
+		f.task, f.err = s.next.Get(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f.task.Clone(), nil
+}
+
+// GetAll retrieves all tasks.
+func (s *CoalescingTaskStore) GetAll(ctx context.Context) ([]*models.Task, error) {
+	f, err := s.do(ctx, "all", func(ctx context.Context, f *flight) {
+		f.tasks, f.err = s.next.GetAll(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]*models.Task, len(f.tasks))
+	for i, task := range f.tasks {
+		tasks[i] = task.Clone()
+	}
+	return tasks, nil
+}
+
+// Create stores a new task.
+func (s *CoalescingTaskStore) Create(ctx context.Context, task *models.Task) error {
+	defer s.forget(task.ID)
+	return s.next.Create(ctx, task)
+}
+
+// Update updates an existing task.
+func (s *CoalescingTaskStore) Update(ctx context.Context, task *models.Task) error {
+	defer s.forget(task.ID)
+	return s.next.Update(ctx, task)
+}
+
+// Delete removes a task by ID.
+func (s *CoalescingTaskStore) Delete(ctx context.Context, id models.TaskID) error {
+	defer s.forget(id)
+	return s.next.Delete(ctx, id)
+}
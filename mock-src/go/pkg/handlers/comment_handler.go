@@ -0,0 +1,279 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/example/tasktracker/pkg/markdown"
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// CommentStore defines the interface for comment storage.
+type CommentStore interface {
+	// Get retrieves a comment by ID.
+	Get(ctx context.Context, id string) (*models.Comment, error)
+	// GetAll retrieves all comments.
+	GetAll(ctx context.Context) ([]*models.Comment, error)
+	// ListByTask retrieves the comments on a task, oldest first.
+	ListByTask(ctx context.Context, taskID models.TaskID) ([]*models.Comment, error)
+	// Create stores a new comment.
+	Create(ctx context.Context, comment *models.Comment) error
+	// Update updates an existing comment.
+	Update(ctx context.Context, comment *models.Comment) error
+	// Delete removes a comment by ID.
+	Delete(ctx context.Context, id string) error
+}
+
+// ErrCommentNotFound is returned when a comment is not found.
+var ErrCommentNotFound = errors.New("comment not found")
+
+// InMemoryCommentStore is an in-memory implementation of CommentStore.
+type InMemoryCommentStore struct {
+	*InMemoryStore[string, *models.Comment]
+}
+
+// NewInMemoryCommentStore creates a new in-memory comment store.
+func NewInMemoryCommentStore() *InMemoryCommentStore {
+	return &InMemoryCommentStore{NewInMemoryStore[string, *models.Comment](ErrCommentNotFound)}
+}
+
+// ListByTask retrieves the comments on a task, oldest first.
+func (s *InMemoryCommentStore) ListByTask(ctx context.Context, taskID models.TaskID) ([]*models.Comment, error) {
+	return s.List(ctx, ListOptions[*models.Comment]{
+		Filter: func(c *models.Comment) bool { return c.TaskID == taskID },
+		Less:   func(a, b *models.Comment) bool { return a.CreatedAt.Before(b.CreatedAt) },
+	})
+}
+
+// CommentHandler handles HTTP requests for task comments.
+type CommentHandler struct {
+	comments      CommentStore
+	tasks         TaskStore
+	users         UserStore
+	notifications NotificationStore
+	reactions     ReactionStore
+}
+
+// NewCommentHandler creates a new comment handler.
+//
+// reactions may be nil, in which case comments are listed without
+// reaction counts.
+func NewCommentHandler(comments CommentStore, tasks TaskStore, users UserStore, notifications NotificationStore, reactions ReactionStore) *CommentHandler {
+	return &CommentHandler{comments: comments, tasks: tasks, users: users, notifications: notifications, reactions: reactions}
+}
+
+// CreateCommentRequest is the request body for creating a comment.
+type CreateCommentRequest struct {
+	Body string `json:"body"`
+}
+
+// Create handles POST /tasks/{id}/comments requests.
+//
+// The author and any @mentioned users start watching the task.
+// Mentioned users are notified of the mention and other watchers of the
+// new comment. Subscriptions and notifications are best effort: once
+// the comment is stored, failures are logged rather than returned.
+func (h *CommentHandler) Create(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	author, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req CreateCommentRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	task, err := h.tasks.Get(r.Context(), taskID)
+	if err == nil && !visibleTo(r.Context(), task) {
+		err = ErrTaskNotFound
+	}
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			http.Error(w, "task not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get task", err)
+		return
+	}
+	if !author.Can("comment", models.TaskResource(task)) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	comment, err := models.NewComment(taskID, author.ID, req.Body)
+	if err != nil {
+		writeContentError(w, err)
+		return
+	}
+
+	if err := h.comments.Create(r.Context(), comment); err != nil {
+		writeServerError(w, r, "failed to create comment", err)
+		return
+	}
+
+	if err := h.subscribeAndNotify(r.Context(), task, comment); err != nil {
+		log.Printf("comments: notifying watchers of %s: %v", comment.ID, err)
+	}
+
+	writeJSON(w, http.StatusCreated, comment)
+}
+
+// List handles GET /tasks/{id}/comments requests.
+//
+// With ?render=html each comment also carries its body rendered as
+// sanitized HTML. Comments that have reactions carry their counts. The
+// comments of a task the caller may not see yield 404.
+func (h *CommentHandler) List(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	html, err := renderHTML(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.tasks.Get(r.Context(), taskID)
+	if err == nil && !visibleTo(r.Context(), task) {
+		err = ErrTaskNotFound
+	}
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			http.Error(w, "task not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get task", err)
+		return
+	}
+
+	comments, err := h.comments.ListByTask(r.Context(), taskID)
+	if err != nil {
+		writeServerError(w, r, "failed to list comments", err)
+		return
+	}
+
+	responses := make([]*CommentResponse, len(comments))
+	for i, c := range comments {
+		responses[i] = &CommentResponse{Comment: c}
+		if html {
+			responses[i].BodyHTML = markdown.Render(c.Body)
+		}
+		if h.reactions == nil {
+			continue
+		}
+		counts, err := reactionCounts(r.Context(), h.reactions, models.ReactionTargetComment, c.ID)
+		if err != nil {
+			writeServerError(w, r, "failed to list reactions", err)
+			return
+		}
+		if len(counts) > 0 {
+			responses[i].Reactions = counts
+		}
+	}
+	writeJSON(w, http.StatusOK, responses)
+}
+
+// subscribeAndNotify adds the author and mentioned users as watchers of
+// the task, then notifies mentioned users and the existing watchers.
+// On a draft only the author is subscribed and nobody is notified, and
+// users who may not see the task are neither subscribed nor notified.
+func (h *CommentHandler) subscribeAndNotify(ctx context.Context, task *models.Task, comment *models.Comment) error {
+	var mentioned []*models.User
+	if !task.Draft {
+		var err error
+		if mentioned, err = h.resolveMentions(ctx, task, comment); err != nil {
+			return err
+		}
+	}
+
+	watcherIDs := []models.UserID{comment.AuthorID}
+	for _, user := range mentioned {
+		watcherIDs = append(watcherIDs, user.ID)
+	}
+	_, err := updateTask(ctx, h.tasks, task.ID, func(t *models.Task) bool {
+		changed := t.MarkResponded(comment.CreatedAt)
+		for _, id := range watcherIDs {
+			if t.Watch(id) {
+				changed = true
+			}
+		}
+		return changed
+	})
+	if err != nil {
+		return err
+	}
+	if task.Draft {
+		return nil
+	}
+
+	notified := map[models.UserID]bool{comment.AuthorID: true}
+	for _, user := range mentioned {
+		if notified[user.ID] {
+			continue
+		}
+		notified[user.ID] = true
+		if !user.Preferences.Notifications.Mentions {
+			continue
+		}
+		if err := h.notify(ctx, user.ID, models.NotificationMention, comment); err != nil {
+			return err
+		}
+	}
+	for _, id := range task.Watchers {
+		if notified[id] {
+			continue
+		}
+		notified[id] = true
+		watcher, err := h.users.Get(ctx, id)
+		if errors.Is(err, ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !task.IsVisibleTo(watcher) {
+			continue
+		}
+		if err := h.notify(ctx, id, models.NotificationComment, comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveMentions returns the active users @mentioned in a comment who
+// may see its task.
+func (h *CommentHandler) resolveMentions(ctx context.Context, task *models.Task, comment *models.Comment) ([]*models.User, error) {
+	names := comment.Mentions()
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	users, err := h.users.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mentioned := make([]*models.User, 0, len(names))
+	for _, name := range names {
+		for _, user := range users {
+			if user.IsActive && strings.EqualFold(user.Username, name) {
+				if task.IsVisibleTo(user) {
+					mentioned = append(mentioned, user)
+				}
+				break
+			}
+		}
+	}
+	return mentioned, nil
+}
+
+// notify stores a notification about a comment for a user.
+func (h *CommentHandler) notify(ctx context.Context, userID models.UserID, typ models.NotificationType, comment *models.Comment) error {
+	n := models.NewNotification(userID, typ, comment.TaskID, comment.AuthorID)
+	n.CommentID = comment.ID
+	return h.notifications.Create(ctx, n)
+}
@@ -0,0 +1,98 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// contextKey is the type of keys stored in request contexts by this package.
+type contextKey int
+
+const (
+	// userContextKey holds the authenticated user for a request.
+	userContextKey contextKey = iota
+	// requestIDContextKey holds the request ID assigned by AccessLog.
+	requestIDContextKey
+	// demoFakerContextKey holds the faker of a request DemoMode anonymizes.
+	demoFakerContextKey
+)
+
+// WithUser returns a copy of ctx carrying the authenticated user.
+func WithUser(ctx context.Context, user *models.User) context.Context {
+	return context.WithValue(ctx, userContextKey, user)
+}
+
+// UserFromContext returns the authenticated user carried by ctx, if any.
+func UserFromContext(ctx context.Context) (*models.User, bool) {
+	user, ok := ctx.Value(userContextKey).(*models.User)
+	return user, ok && user != nil
+}
+
+// DateFormatterFromContext returns a formatter for dates in the
+// authenticated user's time zone and format, or the defaults for
+// anonymous requests. Exporters use it for every date they write.
+func DateFormatterFromContext(ctx context.Context) *models.DateFormatter {
+	if user, ok := UserFromContext(ctx); ok {
+		return models.NewDateFormatter(user.Preferences)
+	}
+	return models.NewDateFormatter(models.DefaultUserPreferences())
+}
+
+// WithRequestID returns a copy of ctx carrying a request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or "".
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
+
+// requireManage writes an error and returns false unless the request
+// carries a user with the manage permission.
+func requireManage(w http.ResponseWriter, r *http.Request) bool {
+	caller, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return false
+	}
+	if !caller.HasPermission("manage") {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// requireAdmin writes an error and returns false unless the request
+// carries an admin or owner.
+func requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	caller, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return false
+	}
+	if !caller.IsAdmin() {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// requireTaskAction writes an error and returns false unless the request
+// carries a user the policy allows to perform action on the task.
+func requireTaskAction(w http.ResponseWriter, r *http.Request, action string, task *models.Task) bool {
+	caller, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return false
+	}
+	if !caller.Can(action, models.TaskResource(task)) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,225 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// Dashboard defaults and limits.
+const (
+	defaultDashboardRecentDays = 7
+	defaultDashboardLimit      = 10
+	maxDashboardLimit          = 100
+)
+
+// Project health levels, from a project's health score.
+const (
+	healthHealthy  = "healthy"
+	healthAtRisk   = "at_risk"
+	healthCritical = "critical"
+)
+
+// ProjectHealth summarizes one project on the dashboard. DueThisWeek
+// counts open tasks due by the end of the week that are not yet overdue.
+//
+// Health is a score from 0 to 100: it starts at 100 and loses up to 60
+// points for the share of open tasks that are overdue and up to 40 for
+// the share that are blocked. Projects without open tasks score 100.
+type ProjectHealth struct {
+	ProjectID         models.ProjectID `json:"project_id"`
+	Name              string           `json:"name"`
+	Open              int              `json:"open"`
+	Overdue           int              `json:"overdue"`
+	DueThisWeek       int              `json:"due_this_week"`
+	Blocked           int              `json:"blocked"`
+	CompletedRecently int              `json:"completed_recently"`
+	Health            int              `json:"health"`
+	Level             string           `json:"level"`
+}
+
+// DashboardResponse is the response body for the cross-project
+// dashboard. Blocked and RecentCompletions list the most relevant tasks
+// up to the limit; the counts cover all of them.
+type DashboardResponse struct {
+	AsOf              time.Time        `json:"as_of"`
+	Overdue           int              `json:"overdue"`
+	DueThisWeek       int              `json:"due_this_week"`
+	BlockedCount      int              `json:"blocked_count"`
+	CompletedRecently int              `json:"completed_recently"`
+	Blocked           []*TaskResponse  `json:"blocked"`
+	RecentCompletions []*TaskResponse  `json:"recent_completions"`
+	Projects          []*ProjectHealth `json:"projects"`
+}
+
+// healthScore scores a project from its open, overdue and blocked counts.
+func healthScore(open, overdue, blocked int) (int, string) {
+	score := 100
+	if open > 0 {
+		score = int(100 - 60*float64(overdue)/float64(open) - 40*float64(blocked)/float64(open) + 0.5)
+	}
+	score = max(0, min(100, score))
+	switch {
+	case score >= 75:
+		return score, healthHealthy
+	case score >= 50:
+		return score, healthAtRisk
+	default:
+		return score, healthCritical
+	}
+}
+
+// healthWindow holds the times a ProjectHealth is counted against.
+type healthWindow struct {
+	now         time.Time
+	weekEnd     time.Time
+	recentSince time.Time
+}
+
+// newHealthWindow returns the window for a caller's week, in loc, and
+// completions in the last recentDays.
+func newHealthWindow(now time.Time, loc *time.Location, recentDays int) *healthWindow {
+	return &healthWindow{
+		now:         now,
+		weekEnd:     startOfWeek(now, loc).AddDate(0, 0, 7),
+		recentSince: now.AddDate(0, 0, -recentDays),
+	}
+}
+
+// count adds a task to a project's health figures, reporting whether it
+// was completed recently and whether it is blocked.
+func (win *healthWindow) count(health *ProjectHealth, task *models.Task) (completedRecently, blocked bool) {
+	if task.Status == models.TaskStatusCompleted && task.StatusSince().After(win.recentSince) {
+		health.CompletedRecently++
+		return true, false
+	}
+	if !task.IsOpen() {
+		return false, false
+	}
+	health.Open++
+	switch {
+	case task.IsOverdueAt(win.now):
+		health.Overdue++
+	case task.DueDate != nil && task.DueDate.Before(win.weekEnd):
+		health.DueThisWeek++
+	}
+	if task.Status == models.TaskStatusBlocked {
+		health.Blocked++
+		return false, true
+	}
+	return false, false
+}
+
+// DashboardHandler handles HTTP requests for the cross-project dashboard.
+type DashboardHandler struct {
+	projects ProjectStore
+	tasks    TaskStore
+}
+
+// NewDashboardHandler creates a new dashboard handler.
+func NewDashboardHandler(projects ProjectStore, tasks TaskStore) *DashboardHandler {
+	return &DashboardHandler{projects: projects, tasks: tasks}
+}
+
+// Get handles GET /dashboard requests, rolling up every project's tasks
+// the caller can see.
+//
+// "This week" runs Monday to Sunday in the caller's time zone. Optional
+// recent_days (1 to 90) and limit (1 to 100) parameters set how far back
+// completions count as recent and how many tasks are listed. Drafts,
+// template projects and archived projects are left out.
+func (h *DashboardHandler) Get(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	recentDays := defaultDashboardRecentDays
+	if raw := q.Get("recent_days"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > 90 {
+			http.Error(w, "recent_days must be between 1 and 90", http.StatusBadRequest)
+			return
+		}
+		recentDays = n
+	}
+	limit := defaultDashboardLimit
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxDashboardLimit {
+			http.Error(w, "limit must be between 1 and 100", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	projects, err := h.projects.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list projects", err)
+		return
+	}
+	tasks, err := h.tasks.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+
+	now := time.Now()
+	win := newHealthWindow(now, DateFormatterFromContext(r.Context()).Location(), recentDays)
+
+	resp := &DashboardResponse{
+		AsOf:              now,
+		Blocked:           make([]*TaskResponse, 0),
+		RecentCompletions: make([]*TaskResponse, 0),
+		Projects:          make([]*ProjectHealth, 0, len(projects)),
+	}
+	byProject := make(map[models.ProjectID]*ProjectHealth, len(projects))
+	for _, project := range projects {
+		if project.IsTemplate || project.IsArchived() {
+			continue
+		}
+		health := &ProjectHealth{ProjectID: project.ID, Name: project.Name}
+		byProject[project.ID] = health
+		resp.Projects = append(resp.Projects, health)
+	}
+
+	var blocked, completed []*models.Task
+	for _, task := range publishedTasks(tasks) {
+		health, ok := byProject[task.ProjectID]
+		if !ok || !visibleTo(r.Context(), task) {
+			continue
+		}
+		isCompleted, isBlocked := win.count(health, task)
+		if isCompleted {
+			completed = append(completed, task)
+		}
+		if isBlocked {
+			blocked = append(blocked, task)
+		}
+	}
+
+	for _, health := range resp.Projects {
+		health.Health, health.Level = healthScore(health.Open, health.Overdue, health.Blocked)
+		resp.Overdue += health.Overdue
+		resp.DueThisWeek += health.DueThisWeek
+		resp.BlockedCount += health.Blocked
+		resp.CompletedRecently += health.CompletedRecently
+	}
+	sort.Slice(resp.Projects, func(i, j int) bool {
+		if resp.Projects[i].Health != resp.Projects[j].Health {
+			return resp.Projects[i].Health < resp.Projects[j].Health
+		}
+		return resp.Projects[i].Name < resp.Projects[j].Name
+	})
+
+	sort.Slice(blocked, func(i, j int) bool { return blocked[i].StatusSince().Before(blocked[j].StatusSince()) })
+	for _, task := range blocked[:min(limit, len(blocked))] {
+		resp.Blocked = append(resp.Blocked, toResponse(task))
+	}
+	sort.Slice(completed, func(i, j int) bool { return completed[i].StatusSince().After(completed[j].StatusSince()) })
+	for _, task := range completed[:min(limit, len(completed))] {
+		resp.RecentCompletions = append(resp.RecentCompletions, toResponse(task))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,176 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// demoModeHeader requests anonymized data for a single request.
+const demoModeHeader = "X-Demo-Mode"
+
+// demoFields maps JSON field names to the fake that replaces their
+// string values.
+var demoFields = map[string]func(*models.DemoFaker, string) string{
+	"name":             (*models.DemoFaker).Name,
+	"display_name":     (*models.DemoFaker).Name,
+	"author_name":      (*models.DemoFaker).Name,
+	"username":         (*models.DemoFaker).Username,
+	"email":            (*models.DemoFaker).Email,
+	"description":      (*models.DemoFaker).Text,
+	"description_html": (*models.DemoFaker).Text,
+	"body":             (*models.DemoFaker).Text,
+	"body_html":        (*models.DemoFaker).Text,
+	"message":          (*models.DemoFaker).Text,
+}
+
+// DemoMode anonymizes JSON responses for demos and screenshots:
+// names, usernames, emails, descriptions and comment bodies are replaced
+// with deterministic fake values, wherever they appear in the body, as
+// are their rendered HTML. Numbers are kept exactly as written. Handlers
+// writing other formats, such as reports and exports, anonymize their
+// data at the source with the faker demoFakerFromContext returns.
+//
+// With always set every response is anonymized, for a dedicated demo
+// deployment; otherwise only GET requests carrying an X-Demo-Mode: 1
+// header are. IDs, dates and statuses are untouched, so the data keeps
+// its shape. Place it outside compression.
+type DemoMode struct {
+	faker  *models.DemoFaker
+	always bool
+}
+
+// NewDemoMode creates demo mode middleware whose fakes are keyed by salt.
+func NewDemoMode(salt []byte, always bool) *DemoMode {
+	return &DemoMode{faker: models.NewDemoFaker(salt), always: always}
+}
+
+// Middleware anonymizes the JSON responses of demo requests.
+func (d *DemoMode) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !d.always && (r.Method != http.MethodGet || r.Header.Get(demoModeHeader) != "1") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buf := &demoWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(buf, r.WithContext(context.WithValue(r.Context(), demoFakerContextKey, d.faker)))
+
+		body := buf.body.Bytes()
+		if isJSONContent(w.Header().Get("Content-Type")) {
+			var v any
+			dec := json.NewDecoder(bytes.NewReader(body))
+			dec.UseNumber()
+			if err := dec.Decode(&v); err == nil {
+				if out, err := json.Marshal(d.anonymize("", v)); err == nil {
+					body = append(out, '\n')
+				}
+			}
+		}
+		w.Header().Del("ETag")
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(buf.status)
+		w.Write(body)
+	})
+}
+
+// demoFakerFromContext returns the faker of a request DemoMode
+// anonymizes, or nil if it is not a demo request.
+func demoFakerFromContext(ctx context.Context) *models.DemoFaker {
+	faker, _ := ctx.Value(demoFakerContextKey).(*models.DemoFaker)
+	return faker
+}
+
+// demoProject returns a copy of project with its name and description
+// faked, as DemoMode fakes them in JSON.
+func demoProject(faker *models.DemoFaker, project *models.Project) *models.Project {
+	project = project.Clone()
+	project.Name = faker.Name(project.Name)
+	project.Description = faker.Text(project.Description)
+	return project
+}
+
+// demoUsers returns copies of users with their names and emails faked.
+func demoUsers(faker *models.DemoFaker, users []*models.User) []*models.User {
+	faked := make([]*models.User, len(users))
+	for i, user := range users {
+		user = user.Clone()
+		user.DisplayName = faker.Name(user.DisplayName)
+		user.Username = faker.Username(user.Username)
+		user.Email = faker.Email(user.Email)
+		faked[i] = user
+	}
+	return faked
+}
+
+// demoTasks returns copies of tasks with their free text faked.
+// Titles are kept, as DemoMode keeps them in JSON.
+func demoTasks(faker *models.DemoFaker, tasks []*models.Task) []*models.Task {
+	faked := make([]*models.Task, len(tasks))
+	for i, task := range tasks {
+		task = task.Clone()
+		task.Description = faker.Text(task.Description)
+		task.BlockedReason = faker.Text(task.BlockedReason)
+		task.RejectionReason = faker.Text(task.RejectionReason)
+		faked[i] = task
+	}
+	return faked
+}
+
+// anonymize returns v with the values of demo fields replaced. key is
+// the field name v was found under.
+func (d *DemoMode) anonymize(key string, v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			v[k] = d.anonymize(k, child)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = d.anonymize(key, child)
+		}
+	case string:
+		if fake, ok := demoFields[key]; ok {
+			return fake(d.faker, v)
+		}
+	}
+	return v
+}
+
+// isJSONContent reports whether a Content-Type is JSON, including
+// problem details.
+func isJSONContent(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || mediaType == "application/problem+json"
+}
+
+// demoWriter buffers a response so it can be anonymized.
+type demoWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+// WriteHeader records the response status.
+func (w *demoWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.status = status
+	}
+}
+
+// Write buffers the response body.
+func (w *demoWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.body.Write(b)
+}
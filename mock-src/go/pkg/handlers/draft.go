@@ -0,0 +1,68 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// publishedTasks returns the tasks that are not drafts, for boards and
+// other shared views that exclude drafts even for their creator.
+func publishedTasks(tasks []*models.Task) []*models.Task {
+	published := make([]*models.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if !task.Draft {
+			published = append(published, task)
+		}
+	}
+	return published
+}
+
+// Publish handles POST /tasks/{id}/publish requests, making a draft
+// visible to everyone. Only the draft's creator can see, and so publish,
+// it. Publishing assigns the task under its project's assignment policy
+// if it is still unassigned. Publishing a task that is not a draft
+// yields 409.
+func (h *TaskHandler) Publish(w http.ResponseWriter, r *http.Request, id models.TaskID) {
+	task, err := h.store.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			http.Error(w, "task not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get task", err)
+		return
+	}
+	if !visibleTo(r.Context(), task) {
+		http.Error(w, "task not found", http.StatusNotFound)
+		return
+	}
+
+	task = task.Clone()
+	if !task.Publish() {
+		http.Error(w, "task is not a draft", http.StatusConflict)
+		return
+	}
+	if h.policies != nil && task.AssigneeID == nil {
+		if _, err := autoAssign(r.Context(), h.policies, h.store, task); err != nil {
+			writeServerError(w, r, "failed to assign task", err)
+			return
+		}
+	}
+
+	if err := h.store.Update(r.Context(), task); err != nil {
+		if errors.Is(err, ErrVersionConflict) {
+			http.Error(w, "task was modified concurrently", http.StatusConflict)
+			return
+		}
+		if writeHookRejection(w, err) {
+			return
+		}
+		writeServerError(w, r, "failed to update task", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toResponse(task))
+}
@@ -0,0 +1,222 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// EditLockStore defines the interface for task edit lock storage.
+type EditLockStore interface {
+	// Get retrieves the unexpired lock on a task.
+	//
+	// Returns ErrLockNotFound if the task is not locked.
+	Get(ctx context.Context, taskID models.TaskID) (*models.EditLock, error)
+	// Acquire stores a lock, replacing an expired lock or one held by
+	// the same user. Renewing keeps the original AcquiredAt.
+	//
+	// Returns ErrTaskLocked if another user holds an unexpired lock.
+	Acquire(ctx context.Context, lock *models.EditLock) error
+	// Release removes a user's lock on a task.
+	//
+	// Returns ErrLockNotFound if the user does not hold the lock.
+	Release(ctx context.Context, taskID models.TaskID, userID models.UserID) error
+}
+
+var (
+	// ErrLockNotFound is returned when a task has no unexpired lock.
+	ErrLockNotFound = errors.New("lock not found")
+	// ErrTaskLocked is returned when another user holds the lock on a task.
+	ErrTaskLocked = errors.New("task is locked by another user")
+)
+
+// InMemoryEditLockStore is an in-memory implementation of EditLockStore.
+type InMemoryEditLockStore struct {
+	mu    sync.Mutex
+	locks map[models.TaskID]*models.EditLock
+}
+
+// NewInMemoryEditLockStore creates a new in-memory edit lock store.
+func NewInMemoryEditLockStore() *InMemoryEditLockStore {
+	return &InMemoryEditLockStore{
+		locks: make(map[models.TaskID]*models.EditLock),
+	}
+}
+
+// current returns the unexpired lock on a task, discarding an expired
+// one. The caller must hold the lock.
+func (s *InMemoryEditLockStore) current(taskID models.TaskID) *models.EditLock {
+	lock, ok := s.locks[taskID]
+	if !ok {
+		return nil
+	}
+	if lock.IsExpiredAt(time.Now()) {
+		delete(s.locks, taskID)
+		return nil
+	}
+	return lock
+}
+
+// Get retrieves the unexpired lock on a task.
+func (s *InMemoryEditLockStore) Get(ctx context.Context, taskID models.TaskID) (*models.EditLock, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock := s.current(taskID)
+	if lock == nil {
+		return nil, ErrLockNotFound
+	}
+	l := *lock
+	return &l, nil
+}
+
+// Acquire stores a lock unless another user holds an unexpired one.
+func (s *InMemoryEditLockStore) Acquire(ctx context.Context, lock *models.EditLock) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if held := s.current(lock.TaskID); held != nil {
+		if held.UserID != lock.UserID {
+			return ErrTaskLocked
+		}
+		lock.AcquiredAt = held.AcquiredAt
+	}
+	l := *lock
+	s.locks[lock.TaskID] = &l
+	return nil
+}
+
+// Release removes a user's lock on a task.
+func (s *InMemoryEditLockStore) Release(ctx context.Context, taskID models.TaskID, userID models.UserID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	held := s.current(taskID)
+	if held == nil || held.UserID != userID {
+		return ErrLockNotFound
+	}
+	delete(s.locks, taskID)
+	return nil
+}
+
+// taskLock returns the unexpired lock on a task, or nil.
+func taskLock(ctx context.Context, locks EditLockStore, taskID models.TaskID) (*models.EditLock, error) {
+	lock, err := locks.Get(ctx, taskID)
+	if errors.Is(err, ErrLockNotFound) {
+		return nil, nil
+	}
+	return lock, err
+}
+
+// EditLockHandler handles HTTP requests for task edit locks.
+type EditLockHandler struct {
+	locks EditLockStore
+	tasks TaskStore
+}
+
+// NewEditLockHandler creates a new edit lock handler.
+func NewEditLockHandler(locks EditLockStore, tasks TaskStore) *EditLockHandler {
+	return &EditLockHandler{locks: locks, tasks: tasks}
+}
+
+// LockRequest is the request body for acquiring an edit lock.
+//
+// TTLSeconds defaults to five minutes and may be at most one hour.
+type LockRequest struct {
+	TTLSeconds int `json:"ttl_seconds,omitempty"`
+}
+
+// LockConflict is the response body when another user holds the lock.
+type LockConflict struct {
+	Error string           `json:"error"`
+	Lock  *models.EditLock `json:"lock"`
+}
+
+// Lock handles POST /tasks/{id}/lock requests, acquiring or renewing
+// the current user's lease on a task. If another user holds an
+// unexpired lock the response is 409 with that lock.
+func (h *EditLockHandler) Lock(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req LockRequest
+	if err := decodeJSON(w, r, &req); err != nil && !errors.Is(err, io.EOF) {
+		writeDecodeError(w, err)
+		return
+	}
+	ttl := models.DefaultEditLockTTL
+	if req.TTLSeconds != 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+
+	if _, err := h.tasks.Get(r.Context(), taskID); err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			http.Error(w, "task not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get task", err)
+		return
+	}
+
+	lock, err := models.NewEditLock(taskID, user.ID, ttl)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.locks.Acquire(r.Context(), lock); err != nil {
+		if errors.Is(err, ErrTaskLocked) {
+			held, herr := taskLock(r.Context(), h.locks, taskID)
+			if herr == nil {
+				writeJSON(w, http.StatusConflict, &LockConflict{Error: err.Error(), Lock: held})
+				return
+			}
+		}
+		writeServerError(w, r, "failed to acquire lock", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, lock)
+}
+
+// Unlock handles DELETE /tasks/{id}/lock requests, releasing the
+// current user's lock. Users with the manage permission may break
+// another user's lock.
+func (h *EditLockHandler) Unlock(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	holderID := user.ID
+	if user.HasPermission("manage") {
+		held, err := taskLock(r.Context(), h.locks, taskID)
+		if err != nil {
+			writeServerError(w, r, "failed to release lock", err)
+			return
+		}
+		if held != nil {
+			holderID = held.UserID
+		}
+	}
+
+	if err := h.locks.Release(r.Context(), taskID, holderID); err != nil {
+		if errors.Is(err, ErrLockNotFound) {
+			http.Error(w, "lock not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to release lock", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,381 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+
+	"github.com/example/tasktracker/pkg/encryption"
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// EncryptedTaskStore is a TaskStore decorator that encrypts task
+// descriptions before they reach the underlying store.
+//
+// The wrapped store receives encrypted copies of tasks passed to Create
+// and Update; only the Version assigned by the store is copied back.
+// Tasks returned by Get and GetAll are decrypted copies.
+type EncryptedTaskStore struct {
+	next     TaskStore
+	envelope *encryption.Envelope
+}
+
+// NewEncryptedTaskStore wraps a task store with field-level encryption.
+func NewEncryptedTaskStore(next TaskStore, envelope *encryption.Envelope) *EncryptedTaskStore {
+	return &EncryptedTaskStore{next: next, envelope: envelope}
+}
+
+// Get retrieves and decrypts a task by ID.
+func (s *EncryptedTaskStore) Get(ctx context.Context, id models.TaskID) (*models.Task, error) {
+	task, err := s.next.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(ctx, task)
+}
+
+// GetAll retrieves and decrypts all tasks.
+func (s *EncryptedTaskStore) GetAll(ctx context.Context) ([]*models.Task, error) {
+	tasks, err := s.next.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.Task, len(tasks))
+	for i, task := range tasks {
+		if result[i], err = s.decrypt(ctx, task); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Create encrypts and stores a new task.
+func (s *EncryptedTaskStore) Create(ctx context.Context, task *models.Task) error {
+	encrypted, err := s.encrypt(ctx, task)
+	if err != nil {
+		return err
+	}
+	if err := s.next.Create(ctx, encrypted); err != nil {
+		return err
+	}
+	task.Version = encrypted.Version
+	return nil
+}
+
+// Update encrypts and updates an existing task.
+func (s *EncryptedTaskStore) Update(ctx context.Context, task *models.Task) error {
+	encrypted, err := s.encrypt(ctx, task)
+	if err != nil {
+		return err
+	}
+	if err := s.next.Update(ctx, encrypted); err != nil {
+		return err
+	}
+	task.Version = encrypted.Version
+	return nil
+}
+
+// Delete removes a task by ID.
+func (s *EncryptedTaskStore) Delete(ctx context.Context, id models.TaskID) error {
+	return s.next.Delete(ctx, id)
+}
+
+// RotateKeys rewraps every stored description with the current key.
+func (s *EncryptedTaskStore) RotateKeys(ctx context.Context) error {
+	tasks, err := s.next.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		rewrapped, err := s.envelope.Rewrap(ctx, task.Description)
+		if err != nil {
+			return err
+		}
+		if rewrapped == task.Description {
+			continue
+		}
+		updated := *task
+		updated.Description = rewrapped
+		if err := s.next.Update(ctx, &updated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encrypt returns a copy of task with its description encrypted.
+func (s *EncryptedTaskStore) encrypt(ctx context.Context, task *models.Task) (*models.Task, error) {
+	description, err := s.envelope.Encrypt(ctx, task.Description)
+	if err != nil {
+		return nil, err
+	}
+	encrypted := *task
+	encrypted.Description = description
+	return &encrypted, nil
+}
+
+// decrypt returns a copy of task with its description decrypted.
+func (s *EncryptedTaskStore) decrypt(ctx context.Context, task *models.Task) (*models.Task, error) {
+	description, err := s.envelope.Decrypt(ctx, task.Description)
+	if err != nil {
+		return nil, err
+	}
+	decrypted := *task
+	decrypted.Description = description
+	return &decrypted, nil
+}
+
+// EncryptedCommentStore is a CommentStore decorator that encrypts
+// comment bodies before they reach the underlying store.
+type EncryptedCommentStore struct {
+	next     CommentStore
+	envelope *encryption.Envelope
+}
+
+// NewEncryptedCommentStore wraps a comment store with field-level encryption.
+func NewEncryptedCommentStore(next CommentStore, envelope *encryption.Envelope) *EncryptedCommentStore {
+	return &EncryptedCommentStore{next: next, envelope: envelope}
+}
+
+// Get retrieves and decrypts a comment by ID.
+func (s *EncryptedCommentStore) Get(ctx context.Context, id string) (*models.Comment, error) {
+	comment, err := s.next.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(ctx, comment)
+}
+
+// GetAll retrieves and decrypts all comments.
+func (s *EncryptedCommentStore) GetAll(ctx context.Context) ([]*models.Comment, error) {
+	comments, err := s.next.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.decryptAll(ctx, comments)
+}
+
+// ListByTask retrieves and decrypts the comments on a task.
+func (s *EncryptedCommentStore) ListByTask(ctx context.Context, taskID models.TaskID) ([]*models.Comment, error) {
+	comments, err := s.next.ListByTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return s.decryptAll(ctx, comments)
+}
+
+// Create encrypts and stores a new comment.
+func (s *EncryptedCommentStore) Create(ctx context.Context, comment *models.Comment) error {
+	encrypted, err := s.encrypt(ctx, comment)
+	if err != nil {
+		return err
+	}
+	return s.next.Create(ctx, encrypted)
+}
+
+// Update encrypts and updates an existing comment.
+func (s *EncryptedCommentStore) Update(ctx context.Context, comment *models.Comment) error {
+	encrypted, err := s.encrypt(ctx, comment)
+	if err != nil {
+		return err
+	}
+	return s.next.Update(ctx, encrypted)
+}
+
+// Delete removes a comment by ID.
+func (s *EncryptedCommentStore) Delete(ctx context.Context, id string) error {
+	return s.next.Delete(ctx, id)
+}
+
+// RotateKeys rewraps every stored comment body with the current key.
+func (s *EncryptedCommentStore) RotateKeys(ctx context.Context) error {
+	comments, err := s.next.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, comment := range comments {
+		rewrapped, err := s.envelope.Rewrap(ctx, comment.Body)
+		if err != nil {
+			return err
+		}
+		if rewrapped == comment.Body {
+			continue
+		}
+		updated := *comment
+		updated.Body = rewrapped
+		if err := s.next.Update(ctx, &updated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encrypt returns a copy of comment with its body encrypted.
+func (s *EncryptedCommentStore) encrypt(ctx context.Context, comment *models.Comment) (*models.Comment, error) {
+	body, err := s.envelope.Encrypt(ctx, comment.Body)
+	if err != nil {
+		return nil, err
+	}
+	encrypted := *comment
+	encrypted.Body = body
+	return &encrypted, nil
+}
+
+// decrypt returns a copy of comment with its body decrypted.
+func (s *EncryptedCommentStore) decrypt(ctx context.Context, comment *models.Comment) (*models.Comment, error) {
+	body, err := s.envelope.Decrypt(ctx, comment.Body)
+	if err != nil {
+		return nil, err
+	}
+	decrypted := *comment
+	decrypted.Body = body
+	return &decrypted, nil
+}
+
+// decryptAll returns decrypted copies of comments.
+func (s *EncryptedCommentStore) decryptAll(ctx context.Context, comments []*models.Comment) ([]*models.Comment, error) {
+	result := make([]*models.Comment, len(comments))
+	for i, comment := range comments {
+		var err error
+		if result[i], err = s.decrypt(ctx, comment); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// EncryptedAttachmentStore is an AttachmentStore decorator that encrypts
+// attachment contents before they reach the underlying store.
+//
+// Only Data is encrypted; filenames, content types and sizes stay in
+// the clear so attachments can be listed without decrypting them. The
+// Filter and Less functions of List see the stored, encrypted Data.
+type EncryptedAttachmentStore struct {
+	next     AttachmentStore
+	envelope *encryption.Envelope
+}
+
+// NewEncryptedAttachmentStore wraps an attachment store with encryption.
+func NewEncryptedAttachmentStore(next AttachmentStore, envelope *encryption.Envelope) *EncryptedAttachmentStore {
+	return &EncryptedAttachmentStore{next: next, envelope: envelope}
+}
+
+// Get retrieves and decrypts an attachment by ID.
+func (s *EncryptedAttachmentStore) Get(ctx context.Context, id string) (*models.Attachment, error) {
+	attachment, err := s.next.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(ctx, attachment)
+}
+
+// GetAll retrieves and decrypts all attachments.
+func (s *EncryptedAttachmentStore) GetAll(ctx context.Context) ([]*models.Attachment, error) {
+	attachments, err := s.next.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.decryptAll(ctx, attachments)
+}
+
+// List retrieves and decrypts the attachments selected by opts.
+func (s *EncryptedAttachmentStore) List(ctx context.Context, opts ListOptions[*models.Attachment]) ([]*models.Attachment, error) {
+	attachments, err := s.next.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.decryptAll(ctx, attachments)
+}
+
+// ListByTask retrieves and decrypts the attachments of a task.
+func (s *EncryptedAttachmentStore) ListByTask(ctx context.Context, taskID models.TaskID) ([]*models.Attachment, error) {
+	attachments, err := s.next.ListByTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return s.decryptAll(ctx, attachments)
+}
+
+// Create encrypts and stores a new attachment.
+func (s *EncryptedAttachmentStore) Create(ctx context.Context, attachment *models.Attachment) error {
+	encrypted, err := s.encrypt(ctx, attachment)
+	if err != nil {
+		return err
+	}
+	return s.next.Create(ctx, encrypted)
+}
+
+// Update encrypts and updates an existing attachment.
+func (s *EncryptedAttachmentStore) Update(ctx context.Context, attachment *models.Attachment) error {
+	encrypted, err := s.encrypt(ctx, attachment)
+	if err != nil {
+		return err
+	}
+	return s.next.Update(ctx, encrypted)
+}
+
+// Delete removes an attachment by ID.
+func (s *EncryptedAttachmentStore) Delete(ctx context.Context, id string) error {
+	return s.next.Delete(ctx, id)
+}
+
+// RotateKeys rewraps the data key of every stored attachment with the
+// current key.
+func (s *EncryptedAttachmentStore) RotateKeys(ctx context.Context) error {
+	attachments, err := s.next.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, attachment := range attachments {
+		rewrapped, err := s.envelope.Rewrap(ctx, string(attachment.Data))
+		if err != nil {
+			return err
+		}
+		if rewrapped == string(attachment.Data) {
+			continue
+		}
+		updated := *attachment
+		updated.Data = []byte(rewrapped)
+		if err := s.next.Update(ctx, &updated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encrypt returns a copy of attachment with its data encrypted.
+func (s *EncryptedAttachmentStore) encrypt(ctx context.Context, attachment *models.Attachment) (*models.Attachment, error) {
+	data, err := s.envelope.Encrypt(ctx, string(attachment.Data))
+	if err != nil {
+		return nil, err
+	}
+	encrypted := *attachment
+	encrypted.Data = []byte(data)
+	return &encrypted, nil
+}
+
+// decrypt returns a copy of attachment with its data decrypted.
+func (s *EncryptedAttachmentStore) decrypt(ctx context.Context, attachment *models.Attachment) (*models.Attachment, error) {
+	data, err := s.envelope.Decrypt(ctx, string(attachment.Data))
+	if err != nil {
+		return nil, err
+	}
+	decrypted := *attachment
+	decrypted.Data = []byte(data)
+	return &decrypted, nil
+}
+
+// decryptAll returns decrypted copies of attachments.
+func (s *EncryptedAttachmentStore) decryptAll(ctx context.Context, attachments []*models.Attachment) ([]*models.Attachment, error) {
+	result := make([]*models.Attachment, len(attachments))
+	for i, attachment := range attachments {
+		var err error
+		if result[i], err = s.decrypt(ctx, attachment); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
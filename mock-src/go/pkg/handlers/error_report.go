@@ -0,0 +1,183 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxReportedMessage caps how much of a 5xx response body is reported.
+const maxReportedMessage = 512
+
+// ErrorReport describes an unexpected failure while serving a request.
+//
+// Stack is set only for panics. UserID is empty for anonymous requests.
+type ErrorReport struct {
+	ErrorID   string
+	RequestID string
+	Err       error
+	Panic     bool
+	Stack     []byte
+	Method    string
+	Path      string
+	Status    int
+	UserID    string
+	At        time.Time
+}
+
+// ErrorReporter receives reports of unexpected failures, for example
+// to forward them to an error tracking service.
+//
+// Report is called on the request goroutine and must not block for
+// long; implementations that do I/O should hand the report off.
+type ErrorReporter interface {
+	Report(ctx context.Context, report *ErrorReport)
+}
+
+// newErrorReport creates a report of err for a request, capturing the
+// method, path and authenticated user.
+func newErrorReport(r *http.Request, errorID string, err error) *ErrorReport {
+	report := &ErrorReport{
+		ErrorID:   errorID,
+		RequestID: RequestIDFromContext(r.Context()),
+		Err:       err,
+		Method:    r.Method,
+		Path:      r.URL.Path,
+		Status:    http.StatusInternalServerError,
+		At:        time.Now(),
+	}
+	if user, ok := UserFromContext(r.Context()); ok {
+		report.UserID = string(user.ID)
+	}
+	return report
+}
+
+// ErrorReporting reports every 5xx response written by downstream
+// handlers, tagging it with an X-Error-ID header.
+//
+// A report's error is the response message, wrapping the error the
+// handler recorded with writeServerError, if any. Panics are reported
+// by Recoverer instead; place ErrorReporting inside it so each failure
+// is reported once.
+type ErrorReporting struct {
+	reporter ErrorReporter
+}
+
+// NewErrorReporting creates error reporting middleware sending to reporter.
+func NewErrorReporting(reporter ErrorReporter) *ErrorReporting {
+	return &ErrorReporting{reporter: reporter}
+}
+
+// Middleware reports 5xx responses.
+func (e *ErrorReporting) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &reportingWriter{ResponseWriter: w}
+		cause := &errorCause{}
+		next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), errorCauseContextKey{}, cause)))
+		if rw.errorID == "" {
+			return
+		}
+
+		message := strings.TrimSpace(rw.body.String())
+		if message == "" {
+			message = http.StatusText(rw.status)
+		}
+		err := errors.New(message)
+		if cause := cause.get(); cause != nil {
+			err = fmt.Errorf("%s: %w", message, cause)
+		}
+		report := newErrorReport(r, rw.errorID, err)
+		report.Status = rw.status
+		e.reporter.Report(r.Context(), report)
+	})
+}
+
+// errorCauseContextKey marks contexts that record the error behind a
+// 5xx response for ErrorReporting.
+type errorCauseContextKey struct{}
+
+// errorCause holds the error behind a request's 5xx response.
+type errorCause struct {
+	mu  sync.Mutex
+	err error
+}
+
+// get returns the recorded error, or nil if there is none.
+func (c *errorCause) get() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// recordErrorCause records err as the cause of the 5xx response being
+// written for ctx's request, if it is being reported. The first cause
+// recorded wins.
+func recordErrorCause(ctx context.Context, err error) {
+	if cause, ok := ctx.Value(errorCauseContextKey{}).(*errorCause); ok && err != nil {
+		cause.mu.Lock()
+		if cause.err == nil {
+			cause.err = err
+		}
+		cause.mu.Unlock()
+	}
+}
+
+// writeServerError writes a 500 response with message, recording err as
+// its cause so ErrorReporting reports the underlying error rather than
+// only the message.
+func writeServerError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	recordErrorCause(r.Context(), err)
+	http.Error(w, message, http.StatusInternalServerError)
+}
+
+// reportingWriter assigns an error ID to a 5xx response and keeps the
+// start of its body.
+type reportingWriter struct {
+	http.ResponseWriter
+	status  int
+	errorID string
+	body    bytes.Buffer
+}
+
+// WriteHeader tags 5xx responses with an error ID.
+func (w *reportingWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+		if status >= http.StatusInternalServerError {
+			w.errorID = w.Header().Get("X-Error-ID")
+			if w.errorID == "" {
+				w.errorID = uuid.New().String()
+				w.Header().Set("X-Error-ID", w.errorID)
+			}
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write keeps the start of a 5xx response body.
+func (w *reportingWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.errorID != "" && w.body.Len() < maxReportedMessage {
+		rest := maxReportedMessage - w.body.Len()
+		if len(b) < rest {
+			rest = len(b)
+		}
+		w.body.Write(b[:rest])
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (w *reportingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,340 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// EscalationRuleStore defines the interface for escalation rule storage.
+type EscalationRuleStore interface {
+	// Get retrieves a rule by ID.
+	Get(ctx context.Context, id string) (*models.EscalationRule, error)
+	// GetAll retrieves all rules.
+	GetAll(ctx context.Context) ([]*models.EscalationRule, error)
+	// ListByProject retrieves the rules of a project.
+	ListByProject(ctx context.Context, projectID models.ProjectID) ([]*models.EscalationRule, error)
+	// Create stores a new rule.
+	Create(ctx context.Context, rule *models.EscalationRule) error
+	// Update updates an existing rule.
+	Update(ctx context.Context, rule *models.EscalationRule) error
+	// Delete removes a rule by ID.
+	Delete(ctx context.Context, id string) error
+}
+
+// ErrEscalationRuleNotFound is returned when an escalation rule is not found.
+var ErrEscalationRuleNotFound = errors.New("escalation rule not found")
+
+// InMemoryEscalationRuleStore is an in-memory implementation of EscalationRuleStore.
+type InMemoryEscalationRuleStore struct {
+	*InMemoryStore[string, *models.EscalationRule]
+}
+
+// NewInMemoryEscalationRuleStore creates a new in-memory escalation rule store.
+func NewInMemoryEscalationRuleStore() *InMemoryEscalationRuleStore {
+	return &InMemoryEscalationRuleStore{NewInMemoryStore[string, *models.EscalationRule](ErrEscalationRuleNotFound)}
+}
+
+// ListByProject retrieves the rules of a project, oldest first.
+func (s *InMemoryEscalationRuleStore) ListByProject(ctx context.Context, projectID models.ProjectID) ([]*models.EscalationRule, error) {
+	return s.List(ctx, ListOptions[*models.EscalationRule]{
+		Filter: func(r *models.EscalationRule) bool { return r.ProjectID == projectID },
+		Less:   func(a, b *models.EscalationRule) bool { return a.CreatedAt.Before(b.CreatedAt) },
+	})
+}
+
+// EscalationReport summarizes one evaluation of the escalation rules.
+type EscalationReport struct {
+	RunAt          time.Time       `json:"run_at"`
+	RulesEvaluated int             `json:"rules_evaluated"`
+	Escalated      []models.TaskID `json:"escalated"`
+	Notified       int             `json:"notified"`
+}
+
+// EscalationJob periodically evaluates escalation rules against open tasks.
+//
+// Notify rules fire once per task; the job remembers which rules have
+// notified about which tasks for as long as it runs.
+type EscalationJob struct {
+	rules         EscalationRuleStore
+	tasks         TaskStore
+	notifications NotificationStore
+	now           func() time.Time
+
+	mu    sync.Mutex
+	fired map[string]bool
+}
+
+// NewEscalationJob creates a new escalation job.
+func NewEscalationJob(rules EscalationRuleStore, tasks TaskStore, notifications NotificationStore) *EscalationJob {
+	return &EscalationJob{
+		rules:         rules,
+		tasks:         tasks,
+		notifications: notifications,
+		now:           time.Now,
+		fired:         make(map[string]bool),
+	}
+}
+
+// Run evaluates every enabled rule once. Drafts are never escalated.
+func (j *EscalationJob) Run(ctx context.Context) (*EscalationReport, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := j.now()
+	report := &EscalationReport{RunAt: now, Escalated: make([]models.TaskID, 0)}
+
+	rules, err := j.rules.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := j.tasks.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		report.RulesEvaluated++
+		for i, task := range tasks {
+			if task.Draft || !rule.Matches(task, now) {
+				continue
+			}
+			switch rule.Action {
+			case models.EscalationRaisePriority:
+				if task.Priority >= rule.TargetPriority {
+					continue
+				}
+				updated := task.Clone()
+				updated.Priority = rule.TargetPriority
+				updated.UpdatedAt = now
+				if err := j.tasks.Update(ctx, updated); err != nil {
+					if errors.Is(err, ErrVersionConflict) || errors.Is(err, ErrTaskNotFound) {
+						continue
+					}
+					return report, err
+				}
+				tasks[i] = updated
+				report.Escalated = append(report.Escalated, task.ID)
+			case models.EscalationNotify:
+				key := rule.ID + "/" + string(task.ID)
+				if j.fired[key] {
+					continue
+				}
+				n, err := j.notify(ctx, rule, task)
+				if err != nil {
+					return report, err
+				}
+				j.fired[key] = true
+				report.Notified += n
+			}
+		}
+	}
+	return report, nil
+}
+
+// notify notifies a rule's recipients and the task's assignee, returning
+// how many notifications were sent.
+func (j *EscalationJob) notify(ctx context.Context, rule *models.EscalationRule, task *models.Task) (int, error) {
+	recipients := append([]models.UserID(nil), rule.NotifyUserIDs...)
+	if task.AssigneeID != nil {
+		recipients = append(recipients, *task.AssigneeID)
+	}
+
+	sent := make(map[models.UserID]bool, len(recipients))
+	for _, userID := range recipients {
+		if sent[userID] {
+			continue
+		}
+		n := models.NewNotification(userID, models.NotificationEscalation, task.ID, "")
+		n.Message = fmt.Sprintf("escalation rule %q matched task %q", rule.Name, task.Title)
+		if err := j.notifications.Create(ctx, n); err != nil {
+			return len(sent), err
+		}
+		sent[userID] = true
+	}
+	return len(sent), nil
+}
+
+// Start runs the job every interval until ctx is cancelled, skipping runs
+// while mode, which may be nil, is enabled. It returns
+// ErrInvalidInterval, without starting, if interval is not positive.
+func (j *EscalationJob) Start(ctx context.Context, interval time.Duration, mode *MaintenanceMode) error {
+	if interval <= 0 {
+		return ErrInvalidInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if mode.Enabled() {
+					continue
+				}
+				report, err := j.Run(ctx)
+				if err != nil {
+					log.Printf("escalation: run failed: %v", err)
+					continue
+				}
+				log.Printf("escalation: escalated %d tasks and sent %d notifications",
+					len(report.Escalated), report.Notified)
+			}
+		}
+	}()
+	return nil
+}
+
+// EscalationHandler handles HTTP requests for escalation rules.
+type EscalationHandler struct {
+	rules EscalationRuleStore
+	job   *EscalationJob
+}
+
+// NewEscalationHandler creates a new escalation handler.
+func NewEscalationHandler(rules EscalationRuleStore, job *EscalationJob) *EscalationHandler {
+	return &EscalationHandler{rules: rules, job: job}
+}
+
+// EscalationRuleRequest is the request body for creating or updating a rule.
+type EscalationRuleRequest struct {
+	Name           string                     `json:"name"`
+	Condition      models.EscalationCondition `json:"condition"`
+	ThresholdHours int                        `json:"threshold_hours"`
+	Action         models.EscalationAction    `json:"action"`
+	TargetPriority models.TaskPriority        `json:"target_priority,omitempty"`
+	NotifyUserIDs  []models.UserID            `json:"notify_user_ids,omitempty"`
+	Enabled        *bool                      `json:"enabled,omitempty"`
+}
+
+// apply copies the request's fields onto a rule.
+func (req *EscalationRuleRequest) apply(rule *models.EscalationRule) {
+	rule.Name = req.Name
+	rule.Condition = req.Condition
+	rule.ThresholdHours = req.ThresholdHours
+	rule.Action = req.Action
+	rule.TargetPriority = req.TargetPriority
+	rule.NotifyUserIDs = req.NotifyUserIDs
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+}
+
+// List handles GET /projects/{id}/escalation-rules requests.
+func (h *EscalationHandler) List(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	rules, err := h.rules.ListByProject(r.Context(), projectID)
+	if err != nil {
+		writeServerError(w, r, "failed to list escalation rules", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rules)
+}
+
+// Create handles POST /projects/{id}/escalation-rules requests.
+func (h *EscalationHandler) Create(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req EscalationRuleRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	rule := models.NewEscalationRule(projectID, req.Name, req.Condition, req.ThresholdHours, req.Action)
+	req.apply(rule)
+	if err := rule.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.rules.Create(r.Context(), rule); err != nil {
+		writeServerError(w, r, "failed to create escalation rule", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, rule)
+}
+
+// Update handles PUT /escalation-rules/{id} requests.
+func (h *EscalationHandler) Update(w http.ResponseWriter, r *http.Request, id string) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req EscalationRuleRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	existing, err := h.rules.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrEscalationRuleNotFound) {
+			http.Error(w, "escalation rule not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get escalation rule", err)
+		return
+	}
+
+	rule := *existing
+	req.apply(&rule)
+	if err := rule.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.rules.Update(r.Context(), &rule); err != nil {
+		writeServerError(w, r, "failed to update escalation rule", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &rule)
+}
+
+// Delete handles DELETE /escalation-rules/{id} requests.
+func (h *EscalationHandler) Delete(w http.ResponseWriter, r *http.Request, id string) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	if err := h.rules.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, ErrEscalationRuleNotFound) {
+			http.Error(w, "escalation rule not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to delete escalation rule", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Run handles POST /admin/escalation/run requests.
+func (h *EscalationHandler) Run(w http.ResponseWriter, r *http.Request) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	report, err := h.job.Run(r.Context())
+	if err != nil {
+		writeServerError(w, r, "escalation run failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,359 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// EstimationRoundStore defines the interface for estimation round storage.
+type EstimationRoundStore interface {
+	Store[string, *models.EstimationRound]
+	// ListByTask retrieves the rounds of a task.
+	ListByTask(ctx context.Context, taskID models.TaskID) ([]*models.EstimationRound, error)
+}
+
+// ErrEstimationRoundNotFound is returned when an estimation round is not found.
+var ErrEstimationRoundNotFound = errors.New("estimation round not found")
+
+// InMemoryEstimationRoundStore is an in-memory implementation of EstimationRoundStore.
+type InMemoryEstimationRoundStore struct {
+	*InMemoryStore[string, *models.EstimationRound]
+}
+
+// NewInMemoryEstimationRoundStore creates a new in-memory estimation round store.
+func NewInMemoryEstimationRoundStore() *InMemoryEstimationRoundStore {
+	return &InMemoryEstimationRoundStore{NewInMemoryStore[string, *models.EstimationRound](ErrEstimationRoundNotFound)}
+}
+
+// ListByTask retrieves the rounds of a task, newest first.
+func (s *InMemoryEstimationRoundStore) ListByTask(ctx context.Context, taskID models.TaskID) ([]*models.EstimationRound, error) {
+	return s.List(ctx, ListOptions[*models.EstimationRound]{
+		Filter: func(r *models.EstimationRound) bool { return r.TaskID == taskID },
+		Less:   func(a, b *models.EstimationRound) bool { return a.CreatedAt.After(b.CreatedAt) },
+	})
+}
+
+// EstimationRoundResponse is the response body for an estimation round.
+//
+// Until the round is revealed only Voters is shown, along with the
+// caller's own estimate in Mine; Estimates and Summary appear after.
+type EstimationRoundResponse struct {
+	ID         string                    `json:"id"`
+	TaskID     models.TaskID             `json:"task_id"`
+	OpenedBy   models.UserID             `json:"opened_by"`
+	Voters     []models.UserID           `json:"voters"`
+	Mine       *float64                  `json:"mine,omitempty"`
+	Revealed   bool                      `json:"revealed"`
+	Estimates  map[models.UserID]float64 `json:"estimates,omitempty"`
+	Summary    *models.EstimateSummary   `json:"summary,omitempty"`
+	Consensus  *float64                  `json:"consensus,omitempty"`
+	CreatedAt  time.Time                 `json:"created_at"`
+	RevealedAt *time.Time                `json:"revealed_at,omitempty"`
+	ClosedAt   *time.Time                `json:"closed_at,omitempty"`
+}
+
+// toEstimationRoundResponse converts a round for the caller, hiding
+// other members' estimates until it is revealed.
+func toEstimationRoundResponse(round *models.EstimationRound, caller *models.User) *EstimationRoundResponse {
+	resp := &EstimationRoundResponse{
+		ID:         round.ID,
+		TaskID:     round.TaskID,
+		OpenedBy:   round.OpenedBy,
+		Voters:     make([]models.UserID, 0, len(round.Estimates)),
+		Revealed:   round.Revealed,
+		Consensus:  round.Consensus,
+		CreatedAt:  round.CreatedAt,
+		RevealedAt: round.RevealedAt,
+		ClosedAt:   round.ClosedAt,
+	}
+	for user := range round.Estimates {
+		resp.Voters = append(resp.Voters, user)
+	}
+	sort.Slice(resp.Voters, func(i, j int) bool { return resp.Voters[i] < resp.Voters[j] })
+	if caller != nil {
+		if value, ok := round.Estimates[caller.ID]; ok {
+			resp.Mine = &value
+		}
+	}
+	if round.Revealed {
+		resp.Estimates = round.Estimates
+		resp.Summary = round.Summary()
+	}
+	return resp
+}
+
+// EstimationHandler handles HTTP requests for planning poker rounds.
+//
+// Round changes are serialized by the handler, so concurrent estimates
+// are not lost.
+type EstimationHandler struct {
+	rounds EstimationRoundStore
+	tasks  TaskStore
+	mu     sync.Mutex
+}
+
+// NewEstimationHandler creates a new estimation handler.
+func NewEstimationHandler(rounds EstimationRoundStore, tasks TaskStore) *EstimationHandler {
+	return &EstimationHandler{rounds: rounds, tasks: tasks}
+}
+
+// getTask writes an error and returns nil unless the task exists and
+// the caller can see it.
+func (h *EstimationHandler) getTask(w http.ResponseWriter, r *http.Request, id models.TaskID) *models.Task {
+	task, err := h.tasks.Get(r.Context(), id)
+	if err != nil || !visibleTo(r.Context(), task) {
+		if err == nil || errors.Is(err, ErrTaskNotFound) {
+			http.Error(w, "task not found", http.StatusNotFound)
+			return nil
+		}
+		http.Error(w, "failed to get task", http.StatusInternalServerError)
+		return nil
+	}
+	return task
+}
+
+// getRound writes an error and returns nil unless the round exists and
+// the caller can see its task.
+func (h *EstimationHandler) getRound(w http.ResponseWriter, r *http.Request, id string) *models.EstimationRound {
+	round, err := h.rounds.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrEstimationRoundNotFound) {
+			http.Error(w, "estimation round not found", http.StatusNotFound)
+			return nil
+		}
+		writeServerError(w, r, "failed to get estimation round", err)
+		return nil
+	}
+	if h.getTask(w, r, round.TaskID) == nil {
+		return nil
+	}
+	return round
+}
+
+// writeRoundError maps an estimation round state error to a status code.
+func writeRoundError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidEstimate):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, models.ErrRoundRevealed), errors.Is(err, models.ErrRoundNotRevealed),
+		errors.Is(err, models.ErrRoundClosed), errors.Is(err, models.ErrNoEstimates):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, "failed to update estimation round", http.StatusInternalServerError)
+	}
+}
+
+// Open handles POST /tasks/{id}/estimation-rounds requests, opening a
+// round on an open task. A task has at most one round in progress.
+func (h *EstimationHandler) Open(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	caller, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+	task := h.getTask(w, r, taskID)
+	if task == nil {
+		return
+	}
+	if !task.IsOpen() {
+		http.Error(w, "cannot estimate a closed task", http.StatusConflict)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	rounds, err := h.rounds.ListByTask(r.Context(), taskID)
+	if err != nil {
+		writeServerError(w, r, "failed to list estimation rounds", err)
+		return
+	}
+	for _, round := range rounds {
+		if !round.IsClosed() {
+			http.Error(w, "task already has an estimation round in progress", http.StatusConflict)
+			return
+		}
+	}
+
+	round := models.NewEstimationRound(taskID, caller.ID)
+	if err := h.rounds.Create(r.Context(), round); err != nil {
+		writeServerError(w, r, "failed to create estimation round", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toEstimationRoundResponse(round, caller))
+}
+
+// List handles GET /tasks/{id}/estimation-rounds requests, newest first.
+func (h *EstimationHandler) List(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	if h.getTask(w, r, taskID) == nil {
+		return
+	}
+	rounds, err := h.rounds.ListByTask(r.Context(), taskID)
+	if err != nil {
+		writeServerError(w, r, "failed to list estimation rounds", err)
+		return
+	}
+
+	caller, _ := UserFromContext(r.Context())
+	resp := make([]*EstimationRoundResponse, 0, len(rounds))
+	for _, round := range rounds {
+		resp = append(resp, toEstimationRoundResponse(round, caller))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Get handles GET /estimation-rounds/{id} requests.
+func (h *EstimationHandler) Get(w http.ResponseWriter, r *http.Request, id string) {
+	round := h.getRound(w, r, id)
+	if round == nil {
+		return
+	}
+
+	caller, _ := UserFromContext(r.Context())
+	writeJSON(w, http.StatusOK, toEstimationRoundResponse(round, caller))
+}
+
+// EstimateRequest is the request body for submitting an estimate or
+// recording a consensus.
+type EstimateRequest struct {
+	Estimate *float64 `json:"estimate"`
+}
+
+// decodeEstimate decodes an EstimateRequest, writing an error and
+// returning false if it has no estimate.
+func decodeEstimate(w http.ResponseWriter, r *http.Request) (float64, bool) {
+	var req EstimateRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return 0, false
+	}
+	if req.Estimate == nil {
+		http.Error(w, "estimate is required", http.StatusBadRequest)
+		return 0, false
+	}
+	return *req.Estimate, true
+}
+
+// Submit handles PUT /estimation-rounds/{id}/estimate requests, setting
+// the caller's hidden estimate. It may be changed until the round is
+// revealed.
+func (h *EstimationHandler) Submit(w http.ResponseWriter, r *http.Request, id string) {
+	caller, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+	value, ok := decodeEstimate(w, r)
+	if !ok {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	round := h.getRound(w, r, id)
+	if round == nil {
+		return
+	}
+	round = round.Clone()
+	if err := round.Submit(caller.ID, value); err != nil {
+		writeRoundError(w, err)
+		return
+	}
+	if err := h.rounds.Update(r.Context(), round); err != nil {
+		writeServerError(w, r, "failed to update estimation round", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toEstimationRoundResponse(round, caller))
+}
+
+// requireFacilitator writes an error and returns false unless the
+// caller opened the round or has the manage permission.
+func requireFacilitator(w http.ResponseWriter, r *http.Request, round *models.EstimationRound) bool {
+	caller, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return false
+	}
+	if caller.ID != round.OpenedBy && !caller.HasPermission("manage") {
+		http.Error(w, "only the member who opened the round can do this", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// Reveal handles POST /estimation-rounds/{id}/reveal requests, showing
+// every estimate. Only the member who opened the round or a manager may
+// reveal it.
+func (h *EstimationHandler) Reveal(w http.ResponseWriter, r *http.Request, id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	round := h.getRound(w, r, id)
+	if round == nil || !requireFacilitator(w, r, round) {
+		return
+	}
+	round = round.Clone()
+	if err := round.Reveal(); err != nil {
+		writeRoundError(w, err)
+		return
+	}
+	if err := h.rounds.Update(r.Context(), round); err != nil {
+		writeServerError(w, r, "failed to update estimation round", err)
+		return
+	}
+
+	caller, _ := UserFromContext(r.Context())
+	writeJSON(w, http.StatusOK, toEstimationRoundResponse(round, caller))
+}
+
+// Consensus handles POST /estimation-rounds/{id}/consensus requests,
+// recording the agreed estimate on the task and closing the round. Only
+// the member who opened the round or a manager may record it.
+func (h *EstimationHandler) Consensus(w http.ResponseWriter, r *http.Request, id string) {
+	value, ok := decodeEstimate(w, r)
+	if !ok {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	round := h.getRound(w, r, id)
+	if round == nil || !requireFacilitator(w, r, round) {
+		return
+	}
+	round = round.Clone()
+	if err := round.Close(value); err != nil {
+		writeRoundError(w, err)
+		return
+	}
+
+	_, err := updateTask(r.Context(), h.tasks, round.TaskID, func(t *models.Task) bool {
+		if t.Estimate == value {
+			return false
+		}
+		t.Estimate = value
+		t.UpdatedAt = time.Now()
+		return true
+	})
+	if err != nil {
+		if writeHookRejection(w, err) {
+			return
+		}
+		writeServerError(w, r, "failed to update task", err)
+		return
+	}
+	if err := h.rounds.Update(r.Context(), round); err != nil {
+		writeServerError(w, r, "failed to update estimation round", err)
+		return
+	}
+
+	caller, _ := UserFromContext(r.Context())
+	writeJSON(w, http.StatusOK, toEstimationRoundResponse(round, caller))
+}
@@ -0,0 +1,249 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// TemporalTaskStore is implemented by task stores that keep enough
+// history to answer point-in-time queries.
+type TemporalTaskStore interface {
+	TaskStore
+	// AsOf reconstructs a task as it was at the given time.
+	AsOf(ctx context.Context, id models.TaskID, at time.Time) (*models.Task, error)
+	// GetAllAsOf reconstructs every task that existed at the given time.
+	GetAllAsOf(ctx context.Context, at time.Time) ([]*models.Task, error)
+}
+
+// defaultSnapshotInterval is how many events are replayed before a snapshot is taken.
+const defaultSnapshotInterval = 50
+
+// taskSnapshot caches the state of a task as of a given event version.
+type taskSnapshot struct {
+	version int
+	task    *models.Task
+}
+
+// EventSourcedTaskStore is a TaskStore that persists an append-only event
+// log per task and reconstructs current state by replaying it.
+//
+// Snapshots are taken every snapshotInterval events so reads replay
+// only the events recorded since the latest snapshot. The full log is
+// kept, enabling History and AsOf queries.
+type EventSourcedTaskStore struct {
+	mu               sync.RWMutex
+	events           map[models.TaskID][]*models.TaskEvent
+	snapshots        map[models.TaskID]*taskSnapshot
+	snapshotInterval int
+}
+
+// NewEventSourcedTaskStore creates a new event-sourced task store.
+//
+// A non-positive snapshotInterval uses the default.
+func NewEventSourcedTaskStore(snapshotInterval int) *EventSourcedTaskStore {
+	if snapshotInterval <= 0 {
+		snapshotInterval = defaultSnapshotInterval
+	}
+	return &EventSourcedTaskStore{
+		events:           make(map[models.TaskID][]*models.TaskEvent),
+		snapshots:        make(map[models.TaskID]*taskSnapshot),
+		snapshotInterval: snapshotInterval,
+	}
+}
+
+// replay rebuilds the current state of a task. The caller must hold the lock.
+func (s *EventSourcedTaskStore) replay(id models.TaskID) *models.Task {
+	events := s.events[id]
+	var task *models.Task
+	from := 0
+	if snap, ok := s.snapshots[id]; ok {
+		task, from = snap.task.Clone(), snap.version
+	}
+	for _, e := range events[from:] {
+		task = e.Apply(task)
+	}
+	return task
+}
+
+// append records events for a task, stamped with the user in ctx, and
+// refreshes its snapshot when due. The caller must hold the write lock.
+func (s *EventSourcedTaskStore) append(ctx context.Context, id models.TaskID, events ...*models.TaskEvent) {
+	var actorID models.UserID
+	if user, ok := UserFromContext(ctx); ok {
+		actorID = user.ID
+	}
+
+	history := s.events[id]
+	for _, e := range events {
+		e.Version = len(history) + 1
+		e.ActorID = actorID
+		history = append(history, e)
+	}
+	s.events[id] = history
+
+	snap := s.snapshots[id]
+	since := len(history)
+	if snap != nil {
+		since -= snap.version
+	}
+	if since >= s.snapshotInterval {
+		if task := s.replay(id); task != nil {
+			s.snapshots[id] = &taskSnapshot{version: len(history), task: task}
+		} else {
+			delete(s.snapshots, id)
+		}
+	}
+}
+
+// Get retrieves a task by ID.
+func (s *EventSourcedTaskStore) Get(ctx context.Context, id models.TaskID) (*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	task := s.replay(id)
+	if task == nil {
+		return nil, ErrTaskNotFound
+	}
+	return task, nil
+}
+
+// GetAll retrieves all tasks.
+func (s *EventSourcedTaskStore) GetAll(ctx context.Context) ([]*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := make([]*models.Task, 0, len(s.events))
+	for id := range s.events {
+		if task := s.replay(id); task != nil {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// Create stores a new task.
+func (s *EventSourcedTaskStore) Create(ctx context.Context, task *models.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.replay(task.ID) != nil {
+		return ErrTaskExists
+	}
+	if task.Version == 0 {
+		task.Version = 1
+	}
+	s.append(ctx, task.ID, &models.TaskEvent{
+		TaskID:     task.ID,
+		Type:       models.TaskEventCreated,
+		OccurredAt: task.CreatedAt,
+		State:      task.Clone(),
+	})
+	return nil
+}
+
+// Update records the changes between the stored and given task.
+func (s *EventSourcedTaskStore) Update(ctx context.Context, task *models.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.replay(task.ID)
+	if current == nil {
+		return ErrTaskNotFound
+	}
+	if current.Version != task.Version {
+		return ErrVersionConflict
+	}
+	task.Version++
+	if events := models.DiffTaskEvents(current, task); len(events) > 0 {
+		s.append(ctx, task.ID, events...)
+	}
+	return nil
+}
+
+// Delete records the deletion of a task.
+func (s *EventSourcedTaskStore) Delete(ctx context.Context, id models.TaskID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.replay(id) == nil {
+		return ErrTaskNotFound
+	}
+	s.append(ctx, id, &models.TaskEvent{TaskID: id, Type: models.TaskEventDeleted, OccurredAt: time.Now()})
+	return nil
+}
+
+// History returns every event recorded for a task, oldest first.
+func (s *EventSourcedTaskStore) History(ctx context.Context, id models.TaskID) ([]*models.TaskEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	history, ok := s.events[id]
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+	events := make([]*models.TaskEvent, len(history))
+	copy(events, history)
+	return events, nil
+}
+
+// AllEvents returns every event recorded for every task, including
+// deleted tasks, ordered by when they occurred.
+func (s *EventSourcedTaskStore) AllEvents(ctx context.Context) ([]*models.TaskEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := make([]*models.TaskEvent, 0)
+	for _, history := range s.events {
+		events = append(events, history...)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].OccurredAt.Before(events[j].OccurredAt)
+	})
+	return events, nil
+}
+
+// AsOf reconstructs a task as it was at the given time.
+//
+// Returns ErrTaskNotFound if the task did not exist or was deleted at that time.
+func (s *EventSourcedTaskStore) AsOf(ctx context.Context, id models.TaskID, at time.Time) (*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	task := s.replayUntil(id, at)
+	if task == nil {
+		return nil, ErrTaskNotFound
+	}
+	return task, nil
+}
+
+// GetAllAsOf reconstructs every task that existed at the given time.
+func (s *EventSourcedTaskStore) GetAllAsOf(ctx context.Context, at time.Time) ([]*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := make([]*models.Task, 0, len(s.events))
+	for id := range s.events {
+		if task := s.replayUntil(id, at); task != nil {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// replayUntil rebuilds a task from the events that occurred at or before
+// the given time. The caller must hold the lock.
+func (s *EventSourcedTaskStore) replayUntil(id models.TaskID, at time.Time) *models.Task {
+	var task *models.Task
+	for _, e := range s.events[id] {
+		if e.OccurredAt.After(at) {
+			break
+		}
+		task = e.Apply(task)
+	}
+	return task
+}
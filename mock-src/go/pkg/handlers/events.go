@@ -0,0 +1,156 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// EventType identifies a domain event published on the event bus.
+type EventType string
+
+const (
+	// EventSLABreached is published when a task misses an SLA target.
+	EventSLABreached EventType = "sla.breached"
+	// EventTaskApproved is published when an admin approves a task's
+	// completion.
+	EventTaskApproved EventType = "task.approved"
+	// EventTaskRejected is published when an admin rejects a task's
+	// completion.
+	EventTaskRejected EventType = "task.rejected"
+	// EventTaskCreated is published when a task is created.
+	EventTaskCreated EventType = "task.created"
+	// EventTaskUpdated is published when a task is updated. Its data
+	// holds the changed fields and the previous status.
+	EventTaskUpdated EventType = "task.updated"
+)
+
+// eventTypes lists every EventType, for validating names from requests.
+var eventTypes = []string{
+	string(EventSLABreached),
+	string(EventTaskApproved),
+	string(EventTaskRejected),
+	string(EventTaskCreated),
+	string(EventTaskUpdated),
+}
+
+// Event is a domain event published on the event bus.
+type Event struct {
+	Type      EventType        `json:"type"`
+	TaskID    models.TaskID    `json:"task_id,omitempty"`
+	ProjectID models.ProjectID `json:"project_id,omitempty"`
+	At        time.Time        `json:"at"`
+	Data      map[string]any   `json:"data,omitempty"`
+}
+
+// EventHandler receives events from the event bus.
+type EventHandler func(ctx context.Context, event *Event)
+
+// EventBus delivers published events to subscribers in-process.
+//
+// Handlers run synchronously on the publishing goroutine, in the order
+// they subscribed; slow work should be handed off by the handler.
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers map[EventType][]EventHandler
+	all      []EventHandler
+}
+
+// NewEventBus creates an event bus with no subscribers.
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: make(map[EventType][]EventHandler)}
+}
+
+// Subscribe registers a handler for events of the given type.
+func (b *EventBus) Subscribe(typ EventType, handler EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[typ] = append(b.handlers[typ], handler)
+}
+
+// SubscribeAll registers a handler for every event.
+func (b *EventBus) SubscribeAll(handler EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.all = append(b.all, handler)
+}
+
+// Publish delivers an event to its subscribers, stamping its time if unset.
+func (b *EventBus) Publish(ctx context.Context, event *Event) {
+	if event.At.IsZero() {
+		event.At = time.Now()
+	}
+
+	b.mu.RLock()
+	handlers := append(append([]EventHandler(nil), b.handlers[event.Type]...), b.all...)
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(ctx, event)
+	}
+}
+
+// EventPublishingTaskStore is a TaskStore decorator that publishes
+// task.created and task.updated events for writes through it.
+type EventPublishingTaskStore struct {
+	next TaskStore
+	bus  *EventBus
+}
+
+// NewEventPublishingTaskStore wraps next, publishing its writes on bus.
+func NewEventPublishingTaskStore(next TaskStore, bus *EventBus) *EventPublishingTaskStore {
+	return &EventPublishingTaskStore{next: next, bus: bus}
+}
+
+// Get retrieves a task by ID.
+func (s *EventPublishingTaskStore) Get(ctx context.Context, id models.TaskID) (*models.Task, error) {
+	return s.next.Get(ctx, id)
+}
+
+// GetAll retrieves all tasks.
+func (s *EventPublishingTaskStore) GetAll(ctx context.Context) ([]*models.Task, error) {
+	return s.next.GetAll(ctx)
+}
+
+// Create stores a new task and publishes task.created.
+func (s *EventPublishingTaskStore) Create(ctx context.Context, task *models.Task) error {
+	if err := s.next.Create(ctx, task); err != nil {
+		return err
+	}
+	s.bus.Publish(ctx, &Event{Type: EventTaskCreated, TaskID: task.ID, ProjectID: task.ProjectID})
+	return nil
+}
+
+// Update updates a task and publishes task.updated with the fields that
+// changed. Nothing is published if no field changed.
+func (s *EventPublishingTaskStore) Update(ctx context.Context, task *models.Task) error {
+	before, err := s.next.Get(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+	before = before.Clone()
+	if err := s.next.Update(ctx, task); err != nil {
+		return err
+	}
+	changes := models.DiffTasks(before, task)
+	if len(changes) == 0 {
+		return nil
+	}
+	s.bus.Publish(ctx, &Event{
+		Type:      EventTaskUpdated,
+		TaskID:    task.ID,
+		ProjectID: task.ProjectID,
+		Data:      map[string]any{"changes": changes.Fields(), "previous_status": before.Status},
+	})
+	return nil
+}
+
+// Delete removes a task by ID.
+func (s *EventPublishingTaskStore) Delete(ctx context.Context, id models.TaskID) error {
+	return s.next.Delete(ctx, id)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// fuzzUser returns the user fuzzed requests are made as.
+func fuzzUser(t *testing.T) *models.User {
+	t.Helper()
+	user, err := models.NewUser("fuzz", "fuzz@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+// FuzzCreateTask sends arbitrary bodies to POST /tasks. Malformed input
+// must be rejected with a 4xx, never a panic or a 5xx.
+func FuzzCreateTask(f *testing.F) {
+	f.Add(`{"title":"Fix login","project_id":"web"}`)
+	f.Add(`{"title":"x","project_id":"p","priority":4,"tags":["a","b"],"due_date":"2026-01-02T15:04:05Z"}`)
+	f.Add(`{"title":"x","project_id":"p","due_in_working_days":-3}`)
+	f.Add(`{"title":"x","project_id":"p","draft":true,"visibility":"restricted","allowed_user_ids":[""]}`)
+	f.Add(`{"id":"NOT-A-UUID","title":"x","project_id":"p"}`)
+	f.Add(`{"title":"x","project_id":"p","unknown":1}`)
+	f.Add(`[[[[[[[[[[[[[[[[[[[[[[]]]]]]]]]]]]]]]]]]]]]]`)
+	f.Add(`{"title":"\u0000<script>","project_id":"p"}`)
+	f.Add(``)
+
+	f.Fuzz(func(t *testing.T, body string) {
+		h := NewTaskHandler(NewInMemoryTaskStore())
+		req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(body))
+		req = req.WithContext(WithUser(req.Context(), fuzzUser(t)))
+		w := httptest.NewRecorder()
+
+		h.Create(w, req)
+		if w.Code >= http.StatusInternalServerError {
+			t.Fatalf("body %q: status %d: %s", body, w.Code, w.Body)
+		}
+	})
+}
+
+// FuzzListQuery sends arbitrary query strings to GET /tasks and GET
+// /changes. Invalid parameters must yield a 4xx, never a panic or a 5xx.
+func FuzzListQuery(f *testing.F) {
+	f.Add("sort=votes")
+	f.Add("sort=rank&render=html")
+	f.Add("sla=breached")
+	f.Add("since=0&limit=10")
+	f.Add("since=-1&limit=99999999999999999999")
+	f.Add("limit=0&sort=%zz")
+	f.Add("render=html&render=text&sort=")
+
+	f.Fuzz(func(t *testing.T, query string) {
+		ctx := WithUser(context.Background(), fuzzUser(t))
+		store := NewInMemoryTaskStore()
+		if err := store.Create(ctx, models.NewTask("task", "project")); err != nil {
+			t.Fatal(err)
+		}
+		changes := NewChangeLog(10)
+		changes.Record(&Change{Type: ChangeTypeDelete, EntityType: "task", EntityID: "gone"})
+
+		for name, serve := range map[string]http.HandlerFunc{
+			"/tasks":   NewTaskHandler(store, WithSLAs(NewInMemorySLAStore())).List,
+			"/changes": NewChangesHandler(changes).List,
+		} {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.URL.Path, req.URL.RawQuery = name, query
+			w := httptest.NewRecorder()
+
+			serve(w, req.WithContext(ctx))
+			if w.Code >= http.StatusInternalServerError {
+				t.Fatalf("%s?%s: status %d: %s", name, query, w.Code, w.Body)
+			}
+		}
+	})
+}
+
+// FuzzDecodeJSON checks that the shared request decoder rejects bodies
+// it cannot decode with an error rather than panicking, whatever their
+// shape or nesting.
+func FuzzDecodeJSON(f *testing.F) {
+	f.Add(`{"a":[1,2,{"b":null}]}`)
+	f.Add(strings.Repeat("[", 100))
+	f.Add(`{"a":"\ud800"}`)
+	f.Add(`{} {}`)
+
+	f.Fuzz(func(t *testing.T, body string) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		var v any
+		if err := decodeJSON(httptest.NewRecorder(), req, &v); err != nil {
+			w := httptest.NewRecorder()
+			writeDecodeError(w, err)
+			if w.Code >= http.StatusInternalServerError {
+				t.Fatalf("body %q: status %d", body, w.Code)
+			}
+		}
+	})
+}
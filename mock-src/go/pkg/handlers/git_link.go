@@ -0,0 +1,297 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// TaskLinkStore defines the interface for task link storage.
+type TaskLinkStore interface {
+	Store[string, *models.TaskLink]
+	// ListByTask retrieves the links of a task.
+	ListByTask(ctx context.Context, taskID models.TaskID) ([]*models.TaskLink, error)
+}
+
+// ErrTaskLinkNotFound is returned when a task link is not found.
+var ErrTaskLinkNotFound = errors.New("task link not found")
+
+// InMemoryTaskLinkStore is an in-memory implementation of TaskLinkStore.
+type InMemoryTaskLinkStore struct {
+	*InMemoryStore[string, *models.TaskLink]
+}
+
+// NewInMemoryTaskLinkStore creates a new in-memory task link store.
+func NewInMemoryTaskLinkStore() *InMemoryTaskLinkStore {
+	return &InMemoryTaskLinkStore{NewInMemoryStore[string, *models.TaskLink](ErrTaskLinkNotFound)}
+}
+
+// ListByTask retrieves the links of a task, oldest first.
+func (s *InMemoryTaskLinkStore) ListByTask(ctx context.Context, taskID models.TaskID) ([]*models.TaskLink, error) {
+	return s.List(ctx, ListOptions[*models.TaskLink]{
+		Filter: func(l *models.TaskLink) bool { return l.TaskID == taskID },
+		Less:   func(a, b *models.TaskLink) bool { return a.CreatedAt.Before(b.CreatedAt) },
+	})
+}
+
+// GitLinkConfig configures commit and pull request linking.
+//
+// GitHubSecret is the secret of a GitHub webhook sending push and
+// pull_request events. With AutoTransition set, tasks referenced with a
+// closing keyword such as "fixes TT-1a2b3c4d" are completed when the
+// commit lands on the default branch or the pull request is merged, as
+// their project's workflow allows; tasks of projects that require
+// approval are submitted for review instead.
+type GitLinkConfig struct {
+	GitHubSecret   string
+	AutoTransition bool
+}
+
+// GitLinkHandler links commits and pull requests to the tasks their
+// messages and titles reference.
+type GitLinkHandler struct {
+	config   GitLinkConfig
+	links    TaskLinkStore
+	tasks    TaskStore
+	projects ProjectStore
+}
+
+// NewGitLinkHandler creates a new git link handler. projects may be nil,
+// in which case no project requires approval.
+func NewGitLinkHandler(config GitLinkConfig, links TaskLinkStore, tasks TaskStore, projects ProjectStore) *GitLinkHandler {
+	return &GitLinkHandler{config: config, links: links, tasks: tasks, projects: projects}
+}
+
+// GitLinkResult is the response body for an ingested git event.
+type GitLinkResult struct {
+	Linked    int             `json:"linked"`
+	Completed []models.TaskID `json:"completed"`
+	Submitted []models.TaskID `json:"submitted"`
+}
+
+// gitMention is a commit or pull request that references tasks.
+type gitMention struct {
+	kind       models.TaskLinkKind
+	url        string
+	title      string
+	text       string
+	repository string
+	author     string
+	// landed is set when the change reached the default branch, so
+	// closing references complete their tasks.
+	landed bool
+}
+
+// githubPushEvent is the part of a GitHub push event that is used.
+type githubPushEvent struct {
+	Ref        string `json:"ref"`
+	Repository struct {
+		FullName      string `json:"full_name"`
+		DefaultBranch string `json:"default_branch"`
+	} `json:"repository"`
+	Commits []struct {
+		Message string `json:"message"`
+		URL     string `json:"url"`
+		Author  struct {
+			Name string `json:"name"`
+		} `json:"author"`
+	} `json:"commits"`
+}
+
+// githubPullRequestEvent is the part of a GitHub pull_request event that is used.
+type githubPullRequestEvent struct {
+	Action      string `json:"action"`
+	PullRequest struct {
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+		Merged  bool   `json:"merged"`
+		User    struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// GitHub handles POST /integrations/github/links requests.
+//
+// Push and pull_request events are ingested; other events are
+// acknowledged with 204 and ignored.
+func (h *GitLinkHandler) GitHub(w http.ResponseWriter, r *http.Request) {
+	body, ok := readIntegrationBody(w, r, h.config.GitHubSecret)
+	if !ok {
+		return
+	}
+	sig, _ := strings.CutPrefix(r.Header.Get("X-Hub-Signature-256"), "sha256=")
+	if !validHMACSHA256(h.config.GitHubSecret, sig, body) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var mentions []gitMention
+	switch r.Header.Get("X-GitHub-Event") {
+	case "push":
+		var event githubPushEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		landed := event.Repository.DefaultBranch != "" && event.Ref == "refs/heads/"+event.Repository.DefaultBranch
+		for _, c := range event.Commits {
+			title, _, _ := strings.Cut(c.Message, "\n")
+			mentions = append(mentions, gitMention{
+				kind:       models.TaskLinkCommit,
+				url:        c.URL,
+				title:      title,
+				text:       c.Message,
+				repository: event.Repository.FullName,
+				author:     c.Author.Name,
+				landed:     landed,
+			})
+		}
+	case "pull_request":
+		var event githubPullRequestEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		pr := event.PullRequest
+		mentions = append(mentions, gitMention{
+			kind:       models.TaskLinkPullRequest,
+			url:        pr.HTMLURL,
+			title:      pr.Title,
+			text:       pr.Title,
+			repository: event.Repository.FullName,
+			author:     pr.User.Login,
+			landed:     event.Action == "closed" && pr.Merged,
+		})
+	default:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	result, err := h.ingest(r.Context(), mentions)
+	if err != nil {
+		if writeHookRejection(w, err) {
+			return
+		}
+		writeServerError(w, r, "failed to link tasks", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// ingest links the tasks referenced by mentions and completes those
+// closed by landed changes. Tasks their workflow does not let complete
+// are left as they are.
+func (h *GitLinkHandler) ingest(ctx context.Context, mentions []gitMention) (*GitLinkResult, error) {
+	result := &GitLinkResult{Completed: make([]models.TaskID, 0), Submitted: make([]models.TaskID, 0)}
+	var tasks []*models.Task
+	for _, m := range mentions {
+		refs := models.ParseTaskReferences(m.text)
+		if len(refs) == 0 || m.url == "" {
+			continue
+		}
+		if tasks == nil {
+			var err error
+			if tasks, err = h.tasks.GetAll(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, ref := range refs {
+			task := resolveTaskReference(tasks, ref)
+			if task == nil {
+				continue
+			}
+			linked, err := h.link(ctx, task.ID, m)
+			if err != nil {
+				return nil, err
+			}
+			if linked {
+				result.Linked++
+			}
+			if ref.Closes && m.landed && h.config.AutoTransition {
+				moved, ok, err := moveTask(ctx, h.tasks, h.projects, task.ID, models.TaskStatusCompleted, nil)
+				if errors.Is(err, models.ErrUnknownStatus) || errors.Is(err, models.ErrTransitionNotAllowed) || errors.Is(err, models.ErrWIPLimitReached) {
+					continue
+				}
+				if err != nil {
+					return nil, err
+				}
+				switch {
+				case !ok:
+				case moved.Status == models.TaskStatusAwaitingReview:
+					result.Submitted = append(result.Submitted, task.ID)
+				default:
+					result.Completed = append(result.Completed, task.ID)
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// link records a mention on a task, returning false if the task already
+// links to its URL.
+func (h *GitLinkHandler) link(ctx context.Context, taskID models.TaskID, m gitMention) (bool, error) {
+	existing, err := h.links.ListByTask(ctx, taskID)
+	if err != nil {
+		return false, err
+	}
+	for _, l := range existing {
+		if l.URL == m.url {
+			return false, nil
+		}
+	}
+	link := models.NewTaskLink(taskID, m.kind, m.url, m.title, m.repository)
+	link.Author = m.author
+	return true, h.links.Create(ctx, link)
+}
+
+// resolveTaskReference returns the one task a reference names, or nil
+// if it names none or is ambiguous.
+func resolveTaskReference(tasks []*models.Task, ref models.TaskReference) *models.Task {
+	var found *models.Task
+	for _, task := range tasks {
+		if ref.Matches(task) {
+			if found != nil {
+				return nil
+			}
+			found = task
+		}
+	}
+	return found
+}
+
+// ListForTask handles GET /tasks/{id}/links requests. The links of a
+// task the caller may not see yield 404.
+func (h *GitLinkHandler) ListForTask(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	task, err := h.tasks.Get(r.Context(), taskID)
+	if err == nil && !visibleTo(r.Context(), task) {
+		err = ErrTaskNotFound
+	}
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			http.Error(w, "task not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get task", err)
+		return
+	}
+
+	links, err := h.links.ListByTask(r.Context(), task.ID)
+	if err != nil {
+		writeServerError(w, r, "failed to list links", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, links)
+}
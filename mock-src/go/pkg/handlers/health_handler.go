@@ -0,0 +1,51 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"net/http"
+)
+
+// HealthChecker reports whether a dependency is ready to serve traffic.
+type HealthChecker interface {
+	// Healthy returns nil if the dependency is available.
+	Healthy(ctx context.Context) error
+}
+
+// HealthHandler handles liveness and readiness probes.
+type HealthHandler struct {
+	checks map[string]HealthChecker
+}
+
+// NewHealthHandler creates a new health handler with no checks.
+func NewHealthHandler() *HealthHandler {
+	return &HealthHandler{checks: make(map[string]HealthChecker)}
+}
+
+// Register adds a named readiness check.
+func (h *HealthHandler) Register(name string, check HealthChecker) {
+	h.checks[name] = check
+}
+
+// Livez handles GET /livez requests.
+func (h *HealthHandler) Livez(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Readyz handles GET /readyz requests.
+//
+// Responds 503 if any registered check fails, listing each result.
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	status := http.StatusOK
+	results := make(map[string]string, len(h.checks))
+	for name, check := range h.checks {
+		if err := check.Healthy(r.Context()); err != nil {
+			results[name] = err.Error()
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		results[name] = "ok"
+	}
+
+	writeJSON(w, status, results)
+}
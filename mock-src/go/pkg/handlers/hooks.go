@@ -0,0 +1,232 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// ErrHookRejected wraps the error of a before hook that rejected a
+// mutation. Handlers report it as 422 Unprocessable Entity.
+var ErrHookRejected = errors.New("rejected")
+
+// BeforeHook runs before an entity is created or updated. It may modify
+// the entity; returning an error aborts the mutation.
+type BeforeHook[T any] func(ctx context.Context, entity T) error
+
+// AfterHook runs after an entity was created or updated.
+type AfterHook[T any] func(ctx context.Context, entity T)
+
+// BeforeDeleteHook runs before an entity is deleted; returning an error
+// aborts the deletion.
+type BeforeDeleteHook[K ~string] func(ctx context.Context, id K) error
+
+// AfterDeleteHook runs after an entity was deleted.
+type AfterDeleteHook[K ~string] func(ctx context.Context, id K)
+
+// Hooks holds the functions run around a store's mutations, so
+// extensions such as validation, enrichment, event publication or
+// denormalized counters need no changes to handlers.
+//
+// Hooks run in registration order on the caller's goroutine. After
+// hooks only run when the mutation succeeded.
+type Hooks[K ~string, T any] struct {
+	mu           sync.RWMutex
+	beforeCreate []BeforeHook[T]
+	afterCreate  []AfterHook[T]
+	beforeUpdate []BeforeHook[T]
+	afterUpdate  []AfterHook[T]
+	beforeDelete []BeforeDeleteHook[K]
+	afterDelete  []AfterDeleteHook[K]
+}
+
+// NewHooks creates an empty set of hooks.
+func NewHooks[K ~string, T any]() *Hooks[K, T] {
+	return &Hooks[K, T]{}
+}
+
+// BeforeCreate registers a hook run before an entity is created.
+func (h *Hooks[K, T]) BeforeCreate(hook BeforeHook[T]) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.beforeCreate = append(h.beforeCreate, hook)
+}
+
+// AfterCreate registers a hook run after an entity was created.
+func (h *Hooks[K, T]) AfterCreate(hook AfterHook[T]) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.afterCreate = append(h.afterCreate, hook)
+}
+
+// BeforeUpdate registers a hook run before an entity is updated.
+func (h *Hooks[K, T]) BeforeUpdate(hook BeforeHook[T]) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.beforeUpdate = append(h.beforeUpdate, hook)
+}
+
+// AfterUpdate registers a hook run after an entity was updated.
+func (h *Hooks[K, T]) AfterUpdate(hook AfterHook[T]) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.afterUpdate = append(h.afterUpdate, hook)
+}
+
+// BeforeDelete registers a hook run before an entity is deleted.
+func (h *Hooks[K, T]) BeforeDelete(hook BeforeDeleteHook[K]) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.beforeDelete = append(h.beforeDelete, hook)
+}
+
+// AfterDelete registers a hook run after an entity was deleted.
+func (h *Hooks[K, T]) AfterDelete(hook AfterDeleteHook[K]) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.afterDelete = append(h.afterDelete, hook)
+}
+
+// create runs write between the create hooks.
+func (h *Hooks[K, T]) create(ctx context.Context, entity T, write func() error) error {
+	h.mu.RLock()
+	before, after := h.beforeCreate, h.afterCreate
+	h.mu.RUnlock()
+
+	return runHooks(ctx, entity, before, after, write)
+}
+
+// update runs write between the update hooks.
+func (h *Hooks[K, T]) update(ctx context.Context, entity T, write func() error) error {
+	h.mu.RLock()
+	before, after := h.beforeUpdate, h.afterUpdate
+	h.mu.RUnlock()
+
+	return runHooks(ctx, entity, before, after, write)
+}
+
+// delete runs write between the delete hooks.
+func (h *Hooks[K, T]) delete(ctx context.Context, id K, write func() error) error {
+	h.mu.RLock()
+	before, after := h.beforeDelete, h.afterDelete
+	h.mu.RUnlock()
+
+	for _, hook := range before {
+		if err := hook(ctx, id); err != nil {
+			return fmt.Errorf("%w: %w", ErrHookRejected, err)
+		}
+	}
+	if err := write(); err != nil {
+		return err
+	}
+	for _, hook := range after {
+		hook(ctx, id)
+	}
+	return nil
+}
+
+// runHooks runs write between before and after hooks for entity.
+func runHooks[T any](ctx context.Context, entity T, before []BeforeHook[T], after []AfterHook[T], write func() error) error {
+	for _, hook := range before {
+		if err := hook(ctx, entity); err != nil {
+			return fmt.Errorf("%w: %w", ErrHookRejected, err)
+		}
+	}
+	if err := write(); err != nil {
+		return err
+	}
+	for _, hook := range after {
+		hook(ctx, entity)
+	}
+	return nil
+}
+
+// writeHookRejection writes a 422 response and returns true if err is a
+// hook's rejection of a mutation. Writes refused because a project is
+// archived or a slug is taken get a 409 response instead.
+func writeHookRejection(w http.ResponseWriter, err error) bool {
+	if errors.Is(err, models.ErrProjectArchived) || errors.Is(err, models.ErrSlugTaken) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return true
+	}
+	if !errors.Is(err, ErrHookRejected) {
+		return false
+	}
+	http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	return true
+}
+
+// HookedStore is a Store decorator that runs hooks around mutations.
+type HookedStore[K ~string, T Entity[K, T]] struct {
+	Store[K, T]
+	hooks *Hooks[K, T]
+}
+
+// NewHookedStore wraps next, running hooks around its mutations.
+func NewHookedStore[K ~string, T Entity[K, T]](next Store[K, T], hooks *Hooks[K, T]) *HookedStore[K, T] {
+	return &HookedStore[K, T]{Store: next, hooks: hooks}
+}
+
+// Create runs the create hooks around storing a new entity.
+func (s *HookedStore[K, T]) Create(ctx context.Context, entity T) error {
+	return s.hooks.create(ctx, entity, func() error { return s.Store.Create(ctx, entity) })
+}
+
+// Update runs the update hooks around updating an entity.
+func (s *HookedStore[K, T]) Update(ctx context.Context, entity T) error {
+	return s.hooks.update(ctx, entity, func() error { return s.Store.Update(ctx, entity) })
+}
+
+// Delete runs the delete hooks around removing an entity.
+func (s *HookedStore[K, T]) Delete(ctx context.Context, id K) error {
+	return s.hooks.delete(ctx, id, func() error { return s.Store.Delete(ctx, id) })
+}
+
+// HookedTaskStore is a TaskStore decorator that runs hooks around
+// mutations. Update hooks run on every call, including retries after a
+// version conflict.
+type HookedTaskStore struct {
+	next  TaskStore
+	hooks *Hooks[models.TaskID, *models.Task]
+}
+
+// NewHookedTaskStore wraps next, running hooks around its mutations.
+func NewHookedTaskStore(next TaskStore, hooks *Hooks[models.TaskID, *models.Task]) *HookedTaskStore {
+	return &HookedTaskStore{next: next, hooks: hooks}
+}
+
+// Get retrieves a task by ID.
+func (s *HookedTaskStore) Get(ctx context.Context, id models.TaskID) (*models.Task, error) {
+	return s.next.Get(ctx, id)
+}
+
+// GetAll retrieves all tasks.
+func (s *HookedTaskStore) GetAll(ctx context.Context) ([]*models.Task, error) {
+	return s.next.GetAll(ctx)
+}
+
+// Create runs the create hooks around storing a new task.
+func (s *HookedTaskStore) Create(ctx context.Context, task *models.Task) error {
+	return s.hooks.create(ctx, task, func() error { return s.next.Create(ctx, task) })
+}
+
+// Update runs the update hooks around updating a task.
+func (s *HookedTaskStore) Update(ctx context.Context, task *models.Task) error {
+	return s.hooks.update(ctx, task, func() error { return s.next.Update(ctx, task) })
+}
+
+// Delete runs the delete hooks around removing a task.
+func (s *HookedTaskStore) Delete(ctx context.Context, id models.TaskID) error {
+	return s.hooks.delete(ctx, id, func() error { return s.next.Delete(ctx, id) })
+}
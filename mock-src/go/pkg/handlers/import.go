@@ -0,0 +1,226 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// ImportHandler imports projects from other trackers' exports.
+//
+// Each source is a models.Importer; parsing is source specific, while
+// validation, dry runs, assignee matching and creating the projects,
+// tasks and subtask relations are shared.
+type ImportHandler struct {
+	importers map[string]models.Importer
+	projects  ProjectStore
+	tasks     TaskStore
+	users     UserStore
+	relations RelationStore
+}
+
+// NewImportHandler creates a new import handler for the given sources.
+func NewImportHandler(importers []models.Importer, projects ProjectStore, tasks TaskStore, users UserStore, relations RelationStore) *ImportHandler {
+	byName := make(map[string]models.Importer, len(importers))
+	for _, imp := range importers {
+		byName[imp.Source()] = imp
+	}
+	return &ImportHandler{importers: byName, projects: projects, tasks: tasks, users: users, relations: relations}
+}
+
+// DefaultImporters returns the built-in import sources.
+func DefaultImporters() []models.Importer {
+	return []models.Importer{models.TodoistImporter{}, models.AsanaImporter{}}
+}
+
+// ImportReport is the response body for an import.
+//
+// On a dry run nothing is created and Projects lists no IDs. Warnings
+// note data that is dropped, such as assignees with no matching user.
+type ImportReport struct {
+	Source   string               `json:"source"`
+	DryRun   bool                 `json:"dry_run"`
+	Projects []ImportedProject    `json:"projects"`
+	Tasks    int                  `json:"tasks"`
+	Subtasks int                  `json:"subtasks"`
+	Warnings []string             `json:"warnings"`
+	Issues   []models.ImportIssue `json:"issues,omitempty"`
+}
+
+// ImportedProject maps an imported project to the one created for it.
+type ImportedProject struct {
+	ExternalID string           `json:"external_id"`
+	Name       string           `json:"name"`
+	ID         models.ProjectID `json:"id,omitempty"`
+	Tasks      int              `json:"tasks"`
+}
+
+// Import handles POST /imports/{source} requests with the export as the
+// request body. With ?dry_run=true the export is only validated.
+//
+// An export that fails validation is rejected as a whole with 422 and
+// the issues found, so a partial import never has to be cleaned up.
+func (h *ImportHandler) Import(w http.ResponseWriter, r *http.Request, source string) {
+	if !requireManage(w, r) {
+		return
+	}
+	importer, ok := h.importers[strings.ToLower(source)]
+	if !ok {
+		http.Error(w, "unknown import source", http.StatusNotFound)
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBulkRequestBodyBytes))
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	plan, err := importer.Parse(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report := &ImportReport{
+		Source:   importer.Source(),
+		DryRun:   r.URL.Query().Get("dry_run") == "true",
+		Projects: make([]ImportedProject, 0, len(plan.Projects)),
+		Warnings: make([]string, 0),
+	}
+	report.Tasks, report.Subtasks = plan.TaskCount()
+	if report.Issues = plan.Validate(); len(report.Issues) > 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, report)
+		return
+	}
+
+	assignees, err := h.matchAssignees(r.Context(), plan, report)
+	if err != nil {
+		writeServerError(w, r, "failed to look up users", err)
+		return
+	}
+	if report.DryRun {
+		for _, p := range plan.Projects {
+			report.Projects = append(report.Projects, ImportedProject{ExternalID: p.ExternalID, Name: p.Name, Tasks: len(p.Tasks)})
+		}
+		writeJSON(w, http.StatusOK, report)
+		return
+	}
+
+	var createdBy models.UserID
+	if caller, ok := UserFromContext(r.Context()); ok {
+		createdBy = caller.ID
+	}
+	for _, p := range plan.Projects {
+		id, err := h.apply(r.Context(), p, assignees, createdBy)
+		if err != nil {
+			if writeHookRejection(w, err) {
+				return
+			}
+			writeServerError(w, r, fmt.Sprintf("failed to import project %s", p.ExternalID), err)
+			return
+		}
+		report.Projects = append(report.Projects, ImportedProject{ExternalID: p.ExternalID, Name: p.Name, ID: id, Tasks: len(p.Tasks)})
+	}
+
+	writeJSON(w, http.StatusCreated, report)
+}
+
+// matchAssignees maps the plan's assignee emails to active users,
+// warning about each address that matches none.
+func (h *ImportHandler) matchAssignees(ctx context.Context, plan *models.ImportPlan, report *ImportReport) (map[string]models.UserID, error) {
+	users, err := h.users.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byEmail := make(map[string]models.UserID, len(users))
+	for _, user := range users {
+		if user.IsActive {
+			byEmail[strings.ToLower(user.Email)] = user.ID
+		}
+	}
+
+	matched := make(map[string]models.UserID)
+	warned := make(map[string]bool)
+	for _, p := range plan.Projects {
+		for _, t := range p.Tasks {
+			email := strings.ToLower(t.AssigneeEmail)
+			if email == "" {
+				continue
+			}
+			if id, ok := byEmail[email]; ok {
+				matched[email] = id
+			} else if !warned[email] {
+				warned[email] = true
+				report.Warnings = append(report.Warnings, fmt.Sprintf("no user with email %s; tasks left unassigned", t.AssigneeEmail))
+			}
+		}
+	}
+	return matched, nil
+}
+
+// apply creates one validated project with its tasks, then links each
+// subtask to its parent.
+func (h *ImportHandler) apply(ctx context.Context, p *models.ImportProject, assignees map[string]models.UserID, createdBy models.UserID) (models.ProjectID, error) {
+	project, err := models.NewProject(p.Name)
+	if err != nil {
+		return "", err
+	}
+	project.Description = p.Description
+	project.OwnerID = createdBy
+	for _, section := range p.Sections {
+		project.Labels = append(project.Labels, models.Label{Name: section})
+	}
+	if err := h.projects.Create(ctx, project); err != nil {
+		return "", err
+	}
+
+	ids := make(map[string]models.TaskID, len(p.Tasks))
+	for _, t := range p.Tasks {
+		opts := []models.TaskOption{
+			models.WithDescription(t.Description),
+			models.WithPriority(t.Priority),
+			models.WithTags(t.Tags),
+		}
+		if id, ok := assignees[strings.ToLower(t.AssigneeEmail)]; ok {
+			opts = append(opts, models.WithAssignee(id))
+		}
+		if t.DueDate != nil {
+			opts = append(opts, models.WithDueDate(*t.DueDate))
+		}
+		task := models.NewTaskWithOptions(t.Title, project.ID, opts...)
+		if t.Section != "" {
+			task.AddTag(t.Section)
+		}
+		if t.Completed {
+			task.SetStatus(models.TaskStatusCompleted)
+		}
+		task.CreatedBy = createdBy
+		if err := task.SanitizeContent(); err != nil {
+			return "", err
+		}
+		if err := h.tasks.Create(ctx, task); err != nil {
+			return "", err
+		}
+		ids[t.ExternalID] = task.ID
+	}
+
+	for _, t := range p.Tasks {
+		if t.ParentID == "" {
+			continue
+		}
+		relation, err := models.NewTaskRelation(models.RelationSubtaskOf, ids[t.ExternalID], ids[t.ParentID])
+		if err != nil {
+			return "", err
+		}
+		if err := h.relations.Create(ctx, relation); err != nil && !errors.Is(err, ErrRelationExists) {
+			return "", err
+		}
+	}
+	return project.ID, nil
+}
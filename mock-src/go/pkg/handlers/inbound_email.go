@@ -0,0 +1,386 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+const (
+	// maxInboundEmailBytes caps the size of an inbound email webhook body,
+	// leaving room for one maximum-size attachment and the message. The
+	// signature fields are part of the form, so the body is parsed before
+	// it can be verified and the cap bounds what an unsigned request can
+	// make the server buffer.
+	maxInboundEmailBytes = models.MaxAttachmentSize + 2<<20
+	// inboundEmailMaxAge is how old a signed webhook may be.
+	inboundEmailMaxAge = 5 * time.Minute
+	// maxEmailSubjectLength caps the title of a task created from an email.
+	maxEmailSubjectLength = 200
+)
+
+// EmailThreadStore maps email Message-IDs to the tasks they belong to,
+// so replies can be threaded into comments.
+type EmailThreadStore interface {
+	// Lookup returns the task a message belongs to.
+	Lookup(ctx context.Context, messageID string) (models.TaskID, bool, error)
+	// Link records that a message belongs to a task.
+	Link(ctx context.Context, messageID string, taskID models.TaskID) error
+}
+
+// InMemoryEmailThreadStore is an in-memory implementation of EmailThreadStore.
+type InMemoryEmailThreadStore struct {
+	mu      sync.RWMutex
+	threads map[string]models.TaskID
+}
+
+// NewInMemoryEmailThreadStore creates a new in-memory email thread store.
+func NewInMemoryEmailThreadStore() *InMemoryEmailThreadStore {
+	return &InMemoryEmailThreadStore{threads: make(map[string]models.TaskID)}
+}
+
+// Lookup returns the task a message belongs to.
+func (s *InMemoryEmailThreadStore) Lookup(ctx context.Context, messageID string) (models.TaskID, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	taskID, ok := s.threads[messageID]
+	return taskID, ok, nil
+}
+
+// Link records that a message belongs to a task.
+func (s *InMemoryEmailThreadStore) Link(ctx context.Context, messageID string, taskID models.TaskID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.threads[messageID] = taskID
+	return nil
+}
+
+// InboundEmailConfig configures email-to-task ingestion.
+//
+// SigningKey is the webhook signing key of the mail provider. Requests
+// must carry a valid timestamp, token and signature; without a key the
+// endpoint is disabled.
+type InboundEmailConfig struct {
+	ProjectID  models.ProjectID
+	SigningKey string
+}
+
+// InboundEmailHandler turns emails forwarded by a mail provider's
+// inbound webhook into tasks.
+//
+// The webhook posts multipart form data with the Mailgun field names:
+// sender, subject, body-plain, stripped-text, Message-Id, In-Reply-To,
+// References and attachment-N files. An email starts a task in the
+// configured project, with the body as description and files as
+// attachments; a reply to a known message becomes a comment on its
+// task instead. Senders must be active users, matched by email address.
+//
+// Rejected emails, including those whose content is unsafe or over the
+// content limits, get 406 Not Acceptable, which tells the provider not
+// to retry. A signed request is accepted once: replayed tokens are
+// refused with 401 and messages already ingested with 406.
+type InboundEmailHandler struct {
+	config      InboundEmailConfig
+	tasks       TaskStore
+	comments    CommentStore
+	attachments AttachmentStore
+	users       UserStore
+	threads     EmailThreadStore
+	now         func() time.Time
+
+	mu     sync.Mutex
+	tokens map[string]time.Time
+}
+
+// NewInboundEmailHandler creates a new inbound email handler.
+func NewInboundEmailHandler(config InboundEmailConfig, tasks TaskStore, comments CommentStore, attachments AttachmentStore, users UserStore, threads EmailThreadStore) *InboundEmailHandler {
+	return &InboundEmailHandler{
+		config:      config,
+		tasks:       tasks,
+		comments:    comments,
+		attachments: attachments,
+		users:       users,
+		threads:     threads,
+		now:         time.Now,
+		tokens:      make(map[string]time.Time),
+	}
+}
+
+// InboundEmailResponse is the response body for an ingested email.
+// CommentID is set when the email was threaded into an existing task.
+type InboundEmailResponse struct {
+	TaskID      models.TaskID `json:"task_id"`
+	CommentID   string        `json:"comment_id,omitempty"`
+	Attachments int           `json:"attachments"`
+}
+
+// Receive handles POST /inbound/email requests.
+func (h *InboundEmailHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	if h.config.SigningKey == "" {
+		http.Error(w, "integration not configured", http.StatusNotFound)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxInboundEmailBytes)
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		http.Error(w, "invalid email payload", http.StatusBadRequest)
+		return
+	}
+	if !h.verify(r) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	sender, err := h.sender(r.Context(), r.FormValue("sender"))
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			http.Error(w, "unknown sender", http.StatusNotAcceptable)
+			return
+		}
+		writeServerError(w, r, "failed to look up sender", err)
+		return
+	}
+
+	body := strings.TrimSpace(r.FormValue("stripped-text"))
+	if body == "" {
+		body = strings.TrimSpace(r.FormValue("body-plain"))
+	}
+	messageID := r.FormValue("Message-Id")
+	if messageID != "" {
+		_, seen, err := h.threads.Lookup(r.Context(), messageID)
+		if err != nil {
+			writeServerError(w, r, "failed to look up thread", err)
+			return
+		}
+		if seen {
+			http.Error(w, "message already received", http.StatusNotAcceptable)
+			return
+		}
+	}
+
+	resp := &InboundEmailResponse{}
+	status := http.StatusCreated
+	taskID, threaded, err := h.thread(r.Context(), sender, r.FormValue("In-Reply-To"), r.FormValue("References"))
+	if err != nil {
+		writeServerError(w, r, "failed to look up thread", err)
+		return
+	}
+	if threaded {
+		comment, err := models.NewComment(taskID, sender.ID, body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotAcceptable)
+			return
+		}
+		if err := h.comments.Create(r.Context(), comment); err != nil {
+			writeServerError(w, r, "failed to create comment", err)
+			return
+		}
+		resp.CommentID = comment.ID
+		status = http.StatusOK
+	} else {
+		task := models.NewTask(emailTitle(r.FormValue("subject")), h.config.ProjectID)
+		task.Description = body
+		// Email cannot set a priority, so it is left to the task defaults.
+		task.Priority = 0
+		task.CreatedBy = sender.ID
+		if err := task.SanitizeContent(); err != nil {
+			http.Error(w, err.Error(), http.StatusNotAcceptable)
+			return
+		}
+		if err := h.tasks.Create(r.Context(), task); err != nil {
+			if writeHookRejection(w, err) {
+				return
+			}
+			writeServerError(w, r, "failed to create task", err)
+			return
+		}
+		taskID = task.ID
+	}
+	resp.TaskID = taskID
+
+	if messageID != "" {
+		if err := h.threads.Link(r.Context(), messageID, taskID); err != nil {
+			log.Printf("inbound email: linking %s: %v", messageID, err)
+		}
+	}
+
+	n, err := h.attach(r.Context(), taskID, sender.ID, r.MultipartForm)
+	if err != nil {
+		writeServerError(w, r, "failed to store attachments", err)
+		return
+	}
+	resp.Attachments = n
+
+	writeJSON(w, status, resp)
+}
+
+// verify checks the webhook signature, an HMAC-SHA256 of timestamp and
+// token, hex encoded, and that the token was not used before.
+func (h *InboundEmailHandler) verify(r *http.Request) bool {
+	timestamp, token := r.FormValue("timestamp"), r.FormValue("token")
+	sig, err := hex.DecodeString(r.FormValue("signature"))
+	if err != nil {
+		return false
+	}
+	secs, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := h.now().Sub(time.Unix(secs, 0)); age > inboundEmailMaxAge || age < -inboundEmailMaxAge {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(h.config.SigningKey))
+	io.WriteString(mac, timestamp+token)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return false
+	}
+	return h.claimToken(token)
+}
+
+// claimToken records a signature token, returning false if it was
+// already used. Tokens are forgotten once their signatures expire.
+func (h *InboundEmailHandler) claimToken(token string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	for t, at := range h.tokens {
+		if now.Sub(at) > 2*inboundEmailMaxAge {
+			delete(h.tokens, t)
+		}
+	}
+	if _, used := h.tokens[token]; used {
+		return false
+	}
+	h.tokens[token] = now
+	return true
+}
+
+// sender returns the active user whose email address matches from.
+//
+// Returns ErrUserNotFound for an unparseable address or one that is not
+// an active user's.
+func (h *InboundEmailHandler) sender(ctx context.Context, from string) (*models.User, error) {
+	addr, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	users, err := h.users.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if user.IsActive && strings.EqualFold(user.Email, addr.Address) {
+			return user, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+// thread returns the task of the first known message an email replies
+// to, checking In-Reply-To before References. Tasks the sender may not
+// see are skipped.
+func (h *InboundEmailHandler) thread(ctx context.Context, sender *models.User, inReplyTo, references string) (models.TaskID, bool, error) {
+	for _, id := range append(strings.Fields(inReplyTo), strings.Fields(references)...) {
+		taskID, ok, err := h.threads.Lookup(ctx, id)
+		if err != nil {
+			return "", false, err
+		}
+		if !ok {
+			continue
+		}
+		task, err := h.tasks.Get(ctx, taskID)
+		if err != nil {
+			if errors.Is(err, ErrTaskNotFound) {
+				continue
+			}
+			return "", false, err
+		}
+		if !task.IsVisibleTo(sender) {
+			continue
+		}
+		return taskID, true, nil
+	}
+	return "", false, nil
+}
+
+// attach stores the email's files as attachments of a task, returning
+// how many were stored. Empty or oversized files are skipped.
+func (h *InboundEmailHandler) attach(ctx context.Context, taskID models.TaskID, uploadedBy models.UserID, form *multipart.Form) (int, error) {
+	stored := 0
+	for field, files := range form.File {
+		if !strings.HasPrefix(field, "attachment-") {
+			continue
+		}
+		for _, fh := range files {
+			data, err := readFormFile(fh)
+			if err != nil {
+				return stored, err
+			}
+			attachment, err := models.NewAttachment(taskID, fh.Filename, fh.Header.Get("Content-Type"), data)
+			if err != nil {
+				log.Printf("inbound email: skipping attachment %q: %v", fh.Filename, err)
+				continue
+			}
+			attachment.UploadedBy = uploadedBy
+			if err := h.attachments.Create(ctx, attachment); err != nil {
+				return stored, err
+			}
+			stored++
+		}
+	}
+	return stored, nil
+}
+
+// readFormFile reads an uploaded file, up to one byte more than
+// models.MaxAttachmentSize so oversized files are detected.
+func readFormFile(fh *multipart.FileHeader) ([]byte, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return io.ReadAll(io.LimitReader(f, models.MaxAttachmentSize+1))
+}
+
+// emailTitle turns an email subject into a task title, dropping reply
+// and forward prefixes.
+func emailTitle(subject string) string {
+	title := strings.TrimSpace(subject)
+	for {
+		lower := strings.ToLower(title)
+		trimmed := false
+		for _, prefix := range []string{"re:", "fwd:", "fw:"} {
+			if strings.HasPrefix(lower, prefix) {
+				title = strings.TrimSpace(title[len(prefix):])
+				trimmed = true
+			}
+		}
+		if !trimmed {
+			break
+		}
+	}
+	if title == "" {
+		return "(no subject)"
+	}
+	if r := []rune(title); len(r) > maxEmailSubjectLength {
+		title = string(r[:maxEmailSubjectLength])
+	}
+	return title
+}
@@ -0,0 +1,188 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// defaultSlowThreshold is the latency above which operations are logged.
+const defaultSlowThreshold = 100 * time.Millisecond
+
+// OperationStats summarizes the calls made to a single store operation.
+type OperationStats struct {
+	Count         int64         `json:"count"`
+	Errors        int64         `json:"errors"`
+	TotalDuration time.Duration `json:"total_duration_ns"`
+	MaxDuration   time.Duration `json:"max_duration_ns"`
+}
+
+// ErrorRate returns the fraction of calls that failed.
+func (s OperationStats) ErrorRate() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Count)
+}
+
+// MeanDuration returns the average call latency.
+func (s OperationStats) MeanDuration() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.TotalDuration / time.Duration(s.Count)
+}
+
+// StoreMetrics collects per-operation statistics for a store.
+//
+// It is safe for concurrent use and may be shared between decorators.
+type StoreMetrics struct {
+	mu  sync.Mutex
+	ops map[string]*OperationStats
+}
+
+// NewStoreMetrics creates an empty metrics collector.
+func NewStoreMetrics() *StoreMetrics {
+	return &StoreMetrics{ops: make(map[string]*OperationStats)}
+}
+
+// Observe records one call to an operation.
+func (m *StoreMetrics) Observe(op string, d time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.ops[op]
+	if !ok {
+		stats = &OperationStats{}
+		m.ops[op] = stats
+	}
+	stats.Count++
+	stats.TotalDuration += d
+	if d > stats.MaxDuration {
+		stats.MaxDuration = d
+	}
+	if err != nil {
+		stats.Errors++
+	}
+}
+
+// Snapshot returns a copy of the statistics keyed by operation name.
+func (m *StoreMetrics) Snapshot() map[string]OperationStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snap := make(map[string]OperationStats, len(m.ops))
+	for op, stats := range m.ops {
+		snap[op] = *stats
+	}
+	return snap
+}
+
+// InstrumentedTaskStore is a TaskStore decorator that records latency
+// and error metrics and logs slow operations.
+//
+// It can wrap any TaskStore, including other decorators.
+type InstrumentedTaskStore struct {
+	next          TaskStore
+	metrics       *StoreMetrics
+	slowThreshold time.Duration
+	logger        *log.Logger
+}
+
+// InstrumentOption is a function that configures an InstrumentedTaskStore.
+type InstrumentOption func(*InstrumentedTaskStore)
+
+// WithSlowThreshold sets the latency above which operations are logged.
+//
+// A zero threshold disables slow-operation logging.
+func WithSlowThreshold(threshold time.Duration) InstrumentOption {
+	return func(s *InstrumentedTaskStore) {
+		s.slowThreshold = threshold
+	}
+}
+
+// WithStoreLogger sets the logger used for slow-operation messages.
+func WithStoreLogger(logger *log.Logger) InstrumentOption {
+	return func(s *InstrumentedTaskStore) {
+		s.logger = logger
+	}
+}
+
+// WithStoreMetrics sets the metrics collector, allowing it to be shared.
+func WithStoreMetrics(metrics *StoreMetrics) InstrumentOption {
+	return func(s *InstrumentedTaskStore) {
+		s.metrics = metrics
+	}
+}
+
+// NewInstrumentedTaskStore wraps a task store with instrumentation.
+func NewInstrumentedTaskStore(next TaskStore, opts ...InstrumentOption) *InstrumentedTaskStore {
+	s := &InstrumentedTaskStore{
+		next:          next,
+		metrics:       NewStoreMetrics(),
+		slowThreshold: defaultSlowThreshold,
+		logger:        log.Default(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Metrics returns the collector the store records into.
+func (s *InstrumentedTaskStore) Metrics() *StoreMetrics {
+	return s.metrics
+}
+
+// observe records the outcome of an operation that started at start.
+func (s *InstrumentedTaskStore) observe(op string, start time.Time, err error) {
+	d := time.Since(start)
+	s.metrics.Observe(op, d, err)
+	if s.slowThreshold > 0 && d > s.slowThreshold {
+		s.logger.Printf("store: slow %s took %s (threshold %s, err=%v)", op, d, s.slowThreshold, err)
+	}
+}
+
+// Get retrieves a task by ID.
+func (s *InstrumentedTaskStore) Get(ctx context.Context, id models.TaskID) (*models.Task, error) {
+	start := time.Now()
+	task, err := s.next.Get(ctx, id)
+	s.observe("get", start, err)
+	return task, err
+}
+
+// GetAll retrieves all tasks.
+func (s *InstrumentedTaskStore) GetAll(ctx context.Context) ([]*models.Task, error) {
+	start := time.Now()
+	tasks, err := s.next.GetAll(ctx)
+	s.observe("get_all", start, err)
+	return tasks, err
+}
+
+// Create stores a new task.
+func (s *InstrumentedTaskStore) Create(ctx context.Context, task *models.Task) error {
+	start := time.Now()
+	err := s.next.Create(ctx, task)
+	s.observe("create", start, err)
+	return err
+}
+
+// Update updates an existing task.
+func (s *InstrumentedTaskStore) Update(ctx context.Context, task *models.Task) error {
+	start := time.Now()
+	err := s.next.Update(ctx, task)
+	s.observe("update", start, err)
+	return err
+}
+
+// Delete removes a task by ID.
+func (s *InstrumentedTaskStore) Delete(ctx context.Context, id models.TaskID) error {
+	start := time.Now()
+	err := s.next.Delete(ctx, id)
+	s.observe("delete", start, err)
+	return err
+}
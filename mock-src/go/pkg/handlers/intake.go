@@ -0,0 +1,400 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// intakeTag is added to every task filed through an intake form.
+const intakeTag = "intake"
+
+// IntakeFormStore defines the interface for intake form storage.
+type IntakeFormStore interface {
+	Store[string, *models.IntakeForm]
+	// GetByToken retrieves a form by its public token.
+	GetByToken(ctx context.Context, token string) (*models.IntakeForm, error)
+}
+
+// ErrIntakeFormNotFound is returned when an intake form is not found.
+var ErrIntakeFormNotFound = errors.New("intake form not found")
+
+// InMemoryIntakeFormStore is an in-memory implementation of IntakeFormStore.
+type InMemoryIntakeFormStore struct {
+	*InMemoryStore[string, *models.IntakeForm]
+}
+
+// NewInMemoryIntakeFormStore creates a new in-memory intake form store.
+func NewInMemoryIntakeFormStore() *InMemoryIntakeFormStore {
+	return &InMemoryIntakeFormStore{NewInMemoryStore[string, *models.IntakeForm](ErrIntakeFormNotFound)}
+}
+
+// GetByToken retrieves a form by its public token.
+func (s *InMemoryIntakeFormStore) GetByToken(ctx context.Context, token string) (*models.IntakeForm, error) {
+	forms, err := s.List(ctx, ListOptions[*models.IntakeForm]{
+		Filter: func(f *models.IntakeForm) bool {
+			return subtle.ConstantTimeCompare([]byte(f.Token), []byte(token)) == 1
+		},
+		Limit: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(forms) == 0 {
+		return nil, ErrIntakeFormNotFound
+	}
+	return forms[0], nil
+}
+
+// CaptchaVerifier checks a captcha response solved by a submitter, such
+// as a reCAPTCHA or hCaptcha token.
+type CaptchaVerifier interface {
+	Verify(ctx context.Context, response, remoteIP string) (bool, error)
+}
+
+// IntakeConfig configures public intake submissions.
+//
+// Each client address may submit Limit times per Window to each form. A
+// non-positive Limit defaults to 5, and a non-positive Window to an hour.
+type IntakeConfig struct {
+	Limit  int
+	Window time.Duration
+}
+
+// intakeWindow counts one client's submissions in the current window.
+type intakeWindow struct {
+	start time.Time
+	count int
+}
+
+// IntakeHandler handles public intake submissions and the management
+// of intake forms.
+type IntakeHandler struct {
+	mu        sync.Mutex
+	config    IntakeConfig
+	forms     IntakeFormStore
+	templates TemplateStore
+	tasks     TaskStore
+	captcha   CaptchaVerifier
+	clients   *IPAllowlists
+	windows   map[string]*intakeWindow
+	now       func() time.Time
+}
+
+// NewIntakeHandler creates a new intake handler.
+//
+// captcha may be nil, in which case submissions are only rate limited.
+// Clients are told apart by the address allowlists resolves, honoring
+// its trusted proxies; allowlists may be nil, in which case the direct
+// peer's address is used.
+func NewIntakeHandler(config IntakeConfig, forms IntakeFormStore, templates TemplateStore, tasks TaskStore, captcha CaptchaVerifier, allowlists *IPAllowlists) *IntakeHandler {
+	if config.Limit <= 0 {
+		config.Limit = 5
+	}
+	if config.Window <= 0 {
+		config.Window = time.Hour
+	}
+	if allowlists == nil {
+		allowlists = &IPAllowlists{}
+	}
+	return &IntakeHandler{
+		config:    config,
+		forms:     forms,
+		templates: templates,
+		tasks:     tasks,
+		captcha:   captcha,
+		clients:   allowlists,
+		windows:   make(map[string]*intakeWindow),
+		now:       time.Now,
+	}
+}
+
+// IntakeSubmission is the request body for a public intake submission.
+//
+// Variables fill in the title variables of the form's template. The
+// submitter's name and email are recorded in the task's description.
+type IntakeSubmission struct {
+	Name        string            `json:"name,omitempty"`
+	Email       string            `json:"email"`
+	Variables   map[string]string `json:"variables,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Captcha     string            `json:"captcha,omitempty"`
+}
+
+// IntakeReceipt is the response body for an accepted submission. It
+// deliberately reveals nothing about the project.
+type IntakeReceipt struct {
+	TaskID models.TaskID `json:"task_id"`
+}
+
+// Submit handles POST /intake/{token} requests.
+//
+// Unknown and disabled tokens both get 404, so the endpoint does not
+// reveal which forms exist.
+func (h *IntakeHandler) Submit(w http.ResponseWriter, r *http.Request, token string) {
+	form, err := h.forms.GetByToken(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, ErrIntakeFormNotFound) {
+			http.Error(w, "intake form not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get intake form", err)
+		return
+	}
+	if !form.Enabled {
+		http.Error(w, "intake form not found", http.StatusNotFound)
+		return
+	}
+
+	remoteIP := h.clientIP(r)
+	if retryAfter, ok := h.allow(form.ID, remoteIP); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+		http.Error(w, "too many submissions", http.StatusTooManyRequests)
+		return
+	}
+
+	var req IntakeSubmission
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	addr, err := mail.ParseAddress(req.Email)
+	if err != nil {
+		http.Error(w, "a valid email is required", http.StatusBadRequest)
+		return
+	}
+	if h.captcha != nil {
+		ok, err := h.captcha.Verify(r.Context(), req.Captcha, remoteIP)
+		if err != nil {
+			http.Error(w, "failed to verify captcha", http.StatusBadGateway)
+			return
+		}
+		if !ok {
+			http.Error(w, "captcha verification failed", http.StatusForbidden)
+			return
+		}
+	}
+
+	template, err := h.templates.Get(r.Context(), form.TemplateID)
+	if err != nil {
+		writeServerError(w, r, "intake form is misconfigured", err)
+		return
+	}
+	task, err := template.Instantiate(form.ProjectID, req.Variables)
+	if err != nil {
+		writeContentError(w, err)
+		return
+	}
+	task.Description = intakeDescription(task.Description, req.Description, req.Name, addr.Address)
+	task.AddTag(intakeTag)
+	if err := task.SanitizeContent(); err != nil {
+		writeContentError(w, err)
+		return
+	}
+
+	if err := h.tasks.Create(r.Context(), task); err != nil {
+		if writeHookRejection(w, err) {
+			return
+		}
+		writeServerError(w, r, "failed to create task", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, &IntakeReceipt{TaskID: task.ID})
+}
+
+// allow records a submission to a form from a client, reporting whether
+// it is within the limit and otherwise how long until the window resets.
+func (h *IntakeHandler) allow(formID, client string) (time.Duration, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	for key, win := range h.windows {
+		if now.Sub(win.start) >= h.config.Window {
+			delete(h.windows, key)
+		}
+	}
+
+	key := formID + "|" + client
+	win, ok := h.windows[key]
+	if !ok {
+		win = &intakeWindow{start: now}
+		h.windows[key] = win
+	}
+	if win.count >= h.config.Limit {
+		return h.config.Window - now.Sub(win.start), false
+	}
+	win.count++
+	return 0, true
+}
+
+// clientIP returns the address a submission came from, or the raw
+// remote address if it cannot be parsed.
+func (h *IntakeHandler) clientIP(r *http.Request) string {
+	addr, ok := h.clients.clientAddr(r)
+	if !ok {
+		return r.RemoteAddr
+	}
+	return addr.String()
+}
+
+// intakeDescription combines the template's description, the
+// submitter's text and who submitted it.
+func intakeDescription(template, text, name, email string) string {
+	var parts []string
+	if template != "" {
+		parts = append(parts, template)
+	}
+	if text = strings.TrimSpace(text); text != "" {
+		parts = append(parts, text)
+	}
+	submitter := email
+	if name = strings.TrimSpace(name); name != "" {
+		submitter = fmt.Sprintf("%s <%s>", name, email)
+	}
+	parts = append(parts, "Submitted by "+submitter)
+	return strings.Join(parts, "\n\n")
+}
+
+// CreateIntakeFormRequest is the request body for creating an intake form.
+type CreateIntakeFormRequest struct {
+	TemplateID string `json:"template_id"`
+}
+
+// ListForms handles GET /projects/{id}/intake-forms requests.
+func (h *IntakeHandler) ListForms(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	forms, err := h.forms.List(r.Context(), ListOptions[*models.IntakeForm]{
+		Filter: func(f *models.IntakeForm) bool { return f.ProjectID == projectID },
+		Less:   func(a, b *models.IntakeForm) bool { return a.CreatedAt.Before(b.CreatedAt) },
+	})
+	if err != nil {
+		writeServerError(w, r, "failed to list intake forms", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, forms)
+}
+
+// CreateForm handles POST /projects/{id}/intake-forms requests.
+func (h *IntakeHandler) CreateForm(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req CreateIntakeFormRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	template, err := h.templates.Get(r.Context(), req.TemplateID)
+	if err != nil {
+		if errors.Is(err, ErrTemplateNotFound) {
+			http.Error(w, "template not found", http.StatusBadRequest)
+			return
+		}
+		writeServerError(w, r, "failed to get template", err)
+		return
+	}
+	if !template.AppliesTo(projectID) {
+		http.Error(w, "template belongs to another project", http.StatusBadRequest)
+		return
+	}
+
+	form, err := models.NewIntakeForm(projectID, template.ID)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidIntakeForm) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeServerError(w, r, "failed to create intake form", err)
+		return
+	}
+	if err := h.forms.Create(r.Context(), form); err != nil {
+		writeServerError(w, r, "failed to create intake form", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, form)
+}
+
+// UpdateIntakeFormRequest is the request body for updating an intake
+// form. RotateToken replaces the form's token, revoking the old link.
+type UpdateIntakeFormRequest struct {
+	Enabled     *bool `json:"enabled,omitempty"`
+	RotateToken bool  `json:"rotate_token,omitempty"`
+}
+
+// UpdateForm handles PATCH /intake-forms/{id} requests.
+func (h *IntakeHandler) UpdateForm(w http.ResponseWriter, r *http.Request, id string) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req UpdateIntakeFormRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	existing, err := h.forms.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrIntakeFormNotFound) {
+			http.Error(w, "intake form not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get intake form", err)
+		return
+	}
+
+	form := *existing
+	if req.Enabled != nil {
+		form.Enabled = *req.Enabled
+	}
+	if req.RotateToken {
+		if form.Token, err = models.NewIntakeToken(); err != nil {
+			writeServerError(w, r, "failed to rotate token", err)
+			return
+		}
+	}
+
+	if err := h.forms.Update(r.Context(), &form); err != nil {
+		writeServerError(w, r, "failed to update intake form", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &form)
+}
+
+// DeleteForm handles DELETE /intake-forms/{id} requests.
+func (h *IntakeHandler) DeleteForm(w http.ResponseWriter, r *http.Request, id string) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	if err := h.forms.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, ErrIntakeFormNotFound) {
+			http.Error(w, "intake form not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to delete intake form", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
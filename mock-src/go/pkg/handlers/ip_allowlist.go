@@ -0,0 +1,256 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// IPAllowlists restricts which addresses may call the API.
+//
+// A global allowlist applies to every request; a per-key allowlist
+// additionally applies to requests made with that API key. Empty
+// allowlists allow every address. Requests routed through Middleware
+// from other addresses are rejected with 403 and audited.
+type IPAllowlists struct {
+	mu             sync.RWMutex
+	global         *models.IPAllowlist
+	keys           map[string]*models.IPAllowlist
+	trustedProxies *models.IPAllowlist
+	keyID          func(*http.Request) string
+	audit          AuditStore
+}
+
+// NewIPAllowlists creates empty allowlists recording rejections in audit.
+//
+// keyID returns the ID of the API key a request was made with, or ""; a
+// nil keyID disables per-key allowlists. X-Forwarded-For is honored only
+// for requests from trustedProxies, which may be nil.
+func NewIPAllowlists(audit AuditStore, keyID func(*http.Request) string, trustedProxies *models.IPAllowlist) *IPAllowlists {
+	return &IPAllowlists{
+		keys:           make(map[string]*models.IPAllowlist),
+		trustedProxies: trustedProxies,
+		keyID:          keyID,
+		audit:          audit,
+	}
+}
+
+// Global returns the global allowlist, or nil if none is set.
+func (a *IPAllowlists) Global() *models.IPAllowlist {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.global
+}
+
+// SetGlobal replaces the global allowlist. An empty or nil list allows
+// every address.
+func (a *IPAllowlists) SetGlobal(list *models.IPAllowlist) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if list.IsEmpty() {
+		list = nil
+	}
+	a.global = list
+}
+
+// ForKey returns an API key's allowlist, or nil if none is set.
+func (a *IPAllowlists) ForKey(keyID string) *models.IPAllowlist {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.keys[keyID]
+}
+
+// SetForKey replaces an API key's allowlist. An empty or nil list
+// removes it.
+func (a *IPAllowlists) SetForKey(keyID string, list *models.IPAllowlist) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if list.IsEmpty() {
+		delete(a.keys, keyID)
+		return
+	}
+	a.keys[keyID] = list
+}
+
+// clientAddr returns the address a request came from, taking the last
+// X-Forwarded-For hop when the direct peer is a trusted proxy.
+func (a *IPAllowlists) clientAddr(r *http.Request) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	addr = addr.Unmap()
+
+	if !a.trustedProxies.IsEmpty() && a.trustedProxies.Allows(addr) {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			hops := strings.Split(forwarded, ",")
+			if hop, err := netip.ParseAddr(strings.TrimSpace(hops[len(hops)-1])); err == nil {
+				return hop.Unmap(), true
+			}
+		}
+	}
+	return addr, true
+}
+
+// allows reports whether the request's origin passes the global and
+// per-key allowlists, and returns the address and key it checked.
+func (a *IPAllowlists) allows(r *http.Request) (bool, netip.Addr, string) {
+	keyID := ""
+	if a.keyID != nil {
+		keyID = a.keyID(r)
+	}
+
+	a.mu.RLock()
+	global, perKey := a.global, a.keys[keyID]
+	a.mu.RUnlock()
+
+	if global.IsEmpty() && perKey.IsEmpty() {
+		return true, netip.Addr{}, keyID
+	}
+	addr, ok := a.clientAddr(r)
+	if !ok {
+		return false, addr, keyID
+	}
+	return global.Allows(addr) && (keyID == "" || perKey.Allows(addr)), addr, keyID
+}
+
+// Middleware rejects requests whose origin is not allowed and records
+// an audit entry for each rejection.
+func (a *IPAllowlists) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ok, addr, keyID := a.allows(r)
+		if ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var actorID models.UserID
+		if user, ok := UserFromContext(r.Context()); ok {
+			actorID = user.ID
+		}
+		target := r.RemoteAddr
+		if addr.IsValid() {
+			target = addr.String()
+		}
+		entry := models.NewAuditEntry(actorID, models.AuditActionRequestRejected, "ip", target)
+		entry.Details["method"] = r.Method
+		entry.Details["path"] = r.URL.Path
+		if keyID != "" {
+			entry.Details["api_key_id"] = keyID
+		}
+		if err := a.audit.Append(r.Context(), entry); err != nil {
+			log.Printf("ip allowlist: recording rejection of %s: %v", target, err)
+		}
+
+		http.Error(w, "request origin not allowed", http.StatusForbidden)
+	})
+}
+
+// IPAllowlistHandler handles HTTP requests for managing IP allowlists.
+type IPAllowlistHandler struct {
+	allowlists *IPAllowlists
+}
+
+// NewIPAllowlistHandler creates a new IP allowlist handler.
+func NewIPAllowlistHandler(allowlists *IPAllowlists) *IPAllowlistHandler {
+	return &IPAllowlistHandler{allowlists: allowlists}
+}
+
+// SetAllowlistRequest is the request body for replacing an allowlist.
+//
+// An empty entries list removes the restriction.
+type SetAllowlistRequest struct {
+	Entries []string `json:"entries"`
+}
+
+// allowlistResponse returns list for a response, never nil.
+func allowlistResponse(list *models.IPAllowlist) *models.IPAllowlist {
+	if list == nil {
+		return &models.IPAllowlist{Entries: []string{}}
+	}
+	return list
+}
+
+// GetGlobal handles GET /admin/ip-allowlist requests.
+func (h *IPAllowlistHandler) GetGlobal(w http.ResponseWriter, r *http.Request) {
+	if !requireManage(w, r) {
+		return
+	}
+	writeJSON(w, http.StatusOK, allowlistResponse(h.allowlists.Global()))
+}
+
+// SetGlobal handles PUT /admin/ip-allowlist requests.
+//
+// An allowlist that would reject the caller's own address yields 409,
+// so an administrator cannot lock themselves out.
+func (h *IPAllowlistHandler) SetGlobal(w http.ResponseWriter, r *http.Request) {
+	if !requireManage(w, r) {
+		return
+	}
+	list, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+
+	if !list.IsEmpty() {
+		addr, ok := h.allowlists.clientAddr(r)
+		if !ok || !list.Allows(addr) {
+			http.Error(w, "allowlist would reject your own address", http.StatusConflict)
+			return
+		}
+	}
+
+	h.allowlists.SetGlobal(list)
+	writeJSON(w, http.StatusOK, allowlistResponse(h.allowlists.Global()))
+}
+
+// GetForKey handles GET /admin/api-keys/{id}/ip-allowlist requests.
+func (h *IPAllowlistHandler) GetForKey(w http.ResponseWriter, r *http.Request, keyID string) {
+	if !requireManage(w, r) {
+		return
+	}
+	writeJSON(w, http.StatusOK, allowlistResponse(h.allowlists.ForKey(keyID)))
+}
+
+// SetForKey handles PUT /admin/api-keys/{id}/ip-allowlist requests.
+func (h *IPAllowlistHandler) SetForKey(w http.ResponseWriter, r *http.Request, keyID string) {
+	if !requireManage(w, r) {
+		return
+	}
+	list, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+
+	h.allowlists.SetForKey(keyID, list)
+	writeJSON(w, http.StatusOK, allowlistResponse(h.allowlists.ForKey(keyID)))
+}
+
+// decode reads and parses an allowlist from the request body.
+func (h *IPAllowlistHandler) decode(w http.ResponseWriter, r *http.Request) (*models.IPAllowlist, bool) {
+	var req SetAllowlistRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return nil, false
+	}
+	list, err := models.NewIPAllowlist(req.Entries)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	return list, true
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// mustAllowlist parses allowlist entries or fails the test.
+func mustAllowlist(t *testing.T, entries ...string) *models.IPAllowlist {
+	t.Helper()
+	list, err := models.NewIPAllowlist(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return list
+}
+
+func TestIPAllowlistsMiddleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		proxies   []string
+		global    []string
+		perKey    []string
+		key       string
+		remote    string
+		forwarded string
+		want      int
+	}{
+		{name: "no allowlist", remote: "203.0.113.9:1234", want: http.StatusOK},
+		{name: "allowed address", global: []string{"10.0.0.0/8"}, remote: "10.1.2.3:1234", want: http.StatusOK},
+		{name: "rejected address", global: []string{"10.0.0.0/8"}, remote: "203.0.113.9:1234", want: http.StatusForbidden},
+		{name: "mapped ipv4 address", global: []string{"10.0.0.0/8"}, remote: "[::ffff:10.1.2.3]:1234", want: http.StatusOK},
+		{name: "unparseable remote address", global: []string{"10.0.0.0/8"}, remote: "garbage", want: http.StatusForbidden},
+		{name: "forwarded header without trusted proxies", global: []string{"10.0.0.0/8"}, remote: "203.0.113.9:1234", forwarded: "10.1.2.3", want: http.StatusForbidden},
+		{name: "forwarded header from untrusted proxy", proxies: []string{"192.0.2.1"}, global: []string{"10.0.0.0/8"}, remote: "203.0.113.9:1234", forwarded: "10.1.2.3", want: http.StatusForbidden},
+		{name: "untrusted proxy cannot hide its address", proxies: []string{"192.0.2.1"}, global: []string{"203.0.113.0/24"}, remote: "203.0.113.9:1234", forwarded: "198.51.100.7", want: http.StatusOK},
+		{name: "forwarded header from trusted proxy", proxies: []string{"192.0.2.1"}, global: []string{"10.0.0.0/8"}, remote: "192.0.2.1:1234", forwarded: "10.1.2.3", want: http.StatusOK},
+		{name: "trusted proxy uses last hop", proxies: []string{"192.0.2.1"}, global: []string{"10.0.0.0/8"}, remote: "192.0.2.1:1234", forwarded: "10.1.2.3, 203.0.113.9", want: http.StatusForbidden},
+		{name: "trusted proxy ignores spoofed first hop", proxies: []string{"192.0.2.1"}, global: []string{"10.0.0.0/8"}, remote: "192.0.2.1:1234", forwarded: "203.0.113.9, 10.1.2.3", want: http.StatusOK},
+		{name: "per-key allowlist allows", perKey: []string{"10.0.0.0/8"}, key: "k1", remote: "10.1.2.3:1234", want: http.StatusOK},
+		{name: "per-key allowlist rejects", perKey: []string{"10.0.0.0/8"}, key: "k1", remote: "203.0.113.9:1234", want: http.StatusForbidden},
+		{name: "per-key allowlist ignores other keys", perKey: []string{"10.0.0.0/8"}, key: "k2", remote: "203.0.113.9:1234", want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var proxies *models.IPAllowlist
+			if tt.proxies != nil {
+				proxies = mustAllowlist(t, tt.proxies...)
+			}
+			audit := NewInMemoryAuditStore()
+			keyID := func(r *http.Request) string { return r.Header.Get("X-API-Key") }
+			allowlists := NewIPAllowlists(audit, keyID, proxies)
+			if tt.global != nil {
+				allowlists.SetGlobal(mustAllowlist(t, tt.global...))
+			}
+			if tt.perKey != nil {
+				allowlists.SetForKey("k1", mustAllowlist(t, tt.perKey...))
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+			req.RemoteAddr = tt.remote
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+			w := httptest.NewRecorder()
+
+			allowlists.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+
+			entries, err := audit.List(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rejected := tt.want == http.StatusForbidden; rejected != (len(entries) == 1) {
+				t.Fatalf("%d audit entries for status %d", len(entries), w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// writeJSON writes v as a JSON response body with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Problem is an RFC 7807 problem details response body.
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// writeProblem writes p as an application/problem+json response.
+func writeProblem(w http.ResponseWriter, p *Problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// writeContentError writes a validation error for user-supplied text:
+// 413 for oversized content, 422 for unsafe content and 400 otherwise.
+func writeContentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, models.ErrContentTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, models.ErrUnsafeContent):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
+
+const (
+	// maxRequestBodyBytes caps the size of a JSON request body.
+	maxRequestBodyBytes = 1 << 20
+	// maxBulkRequestBodyBytes caps the size of a bulk endpoint's request body.
+	maxBulkRequestBodyBytes = 16 << 20
+	// maxJSONDepth caps how deeply objects and arrays may nest in a request body.
+	maxJSONDepth = 16
+	// maxJSONArrayLength caps the number of elements of any array in a request body.
+	maxJSONArrayLength = 1000
+)
+
+var (
+	// errJSONTooDeep is returned for a request body nested beyond maxJSONDepth.
+	errJSONTooDeep = fmt.Errorf("request body is nested more than %d levels deep", maxJSONDepth)
+	// errJSONArrayTooLong is returned for a request body with an array
+	// longer than maxJSONArrayLength.
+	errJSONArrayTooLong = fmt.Errorf("request body contains an array of more than %d elements", maxJSONArrayLength)
+	// errTrailingJSON is returned for a request body with data after its JSON value.
+	errTrailingJSON = errors.New("request body must contain a single JSON value")
+)
+
+// decodeJSON decodes a request body of at most maxRequestBodyBytes into
+// v. See decodeJSONLimit.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	return decodeJSONLimit(w, r, v, maxRequestBodyBytes)
+}
+
+// decodeJSONLimit decodes a request body of at most limit bytes into v,
+// rejecting unknown fields, trailing data, and arrays or nesting beyond
+// the package limits. An empty body yields io.EOF, so callers can treat
+// the body as optional. Errors are meant for writeDecodeError.
+func decodeJSONLimit(w http.ResponseWriter, r *http.Request, v any, limit int64) error {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return io.EOF
+	}
+	if err := checkJSONShape(data); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return errTrailingJSON
+	}
+	return nil
+}
+
+// checkJSONShape scans a JSON document, returning errJSONTooDeep or
+// errJSONArrayTooLong if it exceeds the nesting or array limits.
+// Malformed input is left for the decoder to report.
+func checkJSONShape(data []byte) error {
+	type level struct {
+		array   bool
+		n       int
+		inValue bool
+	}
+	var stack []level
+	inString, escaped := false, false
+	for _, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		if n := len(stack); n > 0 && stack[n-1].array && !stack[n-1].inValue {
+			switch c {
+			case ' ', '\t', '\r', '\n', ',', ']':
+			default:
+				stack[n-1].inValue = true
+				stack[n-1].n++
+				if stack[n-1].n > maxJSONArrayLength {
+					return errJSONArrayTooLong
+				}
+			}
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '[', '{':
+			if len(stack) >= maxJSONDepth {
+				return errJSONTooDeep
+			}
+			stack = append(stack, level{array: c == '['})
+		case ']', '}':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case ',':
+			if n := len(stack); n > 0 && stack[n-1].array {
+				stack[n-1].inValue = false
+			}
+		}
+	}
+	return nil
+}
+
+// writeDecodeError writes a request body decoding error: 413 for an
+// oversized body and 400 otherwise, naming the offending field or
+// position where possible.
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &tooLarge):
+		http.Error(w, fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, errJSONTooDeep), errors.Is(err, errJSONArrayTooLong):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, errTrailingJSON):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, io.EOF):
+		http.Error(w, "request body is required", http.StatusBadRequest)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		http.Error(w, "request body is truncated", http.StatusBadRequest)
+	case errors.As(err, &syntaxErr):
+		http.Error(w, fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset), http.StatusBadRequest)
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		http.Error(w, fmt.Sprintf("field %q must be %s", typeErr.Field, jsonKind(typeErr.Type)), http.StatusBadRequest)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		http.Error(w, "unknown field "+strings.TrimPrefix(err.Error(), "json: unknown field "), http.StatusBadRequest)
+	default:
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+	}
+}
+
+// jsonKind describes the JSON type expected for a Go type.
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	case reflect.Pointer:
+		return jsonKind(t.Elem())
+	}
+	return "a valid value"
+}
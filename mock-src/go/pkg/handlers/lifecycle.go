@@ -0,0 +1,220 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// archivedProjects returns the IDs of the archived projects, or nil if
+// projects is nil.
+func archivedProjects(ctx context.Context, projects ProjectStore) (map[models.ProjectID]bool, error) {
+	if projects == nil {
+		return nil, nil
+	}
+	all, err := projects.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	archived := make(map[models.ProjectID]bool)
+	for _, project := range all {
+		if project.IsArchived() {
+			archived[project.ID] = true
+		}
+	}
+	return archived, nil
+}
+
+// projectArchived reports whether a project is archived. Unknown
+// projects are not.
+func projectArchived(ctx context.Context, projects ProjectStore, projectID models.ProjectID) (bool, error) {
+	project, err := projects.Get(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return project.IsArchived(), nil
+}
+
+// archivedFilter reads the include_archived parameter of a task query,
+// returning the archived projects whose tasks to leave out. It writes
+// an error and returns false if the parameter is invalid.
+func (h *TaskHandler) archivedFilter(w http.ResponseWriter, r *http.Request) (map[models.ProjectID]bool, bool) {
+	switch r.URL.Query().Get("include_archived") {
+	case "", "false":
+		archived, err := archivedProjects(r.Context(), h.projects)
+		if err != nil {
+			writeServerError(w, r, "failed to list projects", err)
+			return nil, false
+		}
+		return archived, true
+	case "true":
+		return nil, true
+	default:
+		http.Error(w, "invalid include_archived", http.StatusBadRequest)
+		return nil, false
+	}
+}
+
+// LifecycleTaskStore is a TaskStore decorator that keeps the tasks of
+// archived projects read-only: creating, changing, moving or deleting
+// them fails with models.ErrProjectArchived.
+type LifecycleTaskStore struct {
+	next     TaskStore
+	projects ProjectStore
+}
+
+// NewLifecycleTaskStore wraps a task store with archive protection.
+func NewLifecycleTaskStore(next TaskStore, projects ProjectStore) *LifecycleTaskStore {
+	return &LifecycleTaskStore{next: next, projects: projects}
+}
+
+// check returns models.ErrProjectArchived if any of the projects is archived.
+func (s *LifecycleTaskStore) check(ctx context.Context, projectIDs ...models.ProjectID) error {
+	for _, id := range projectIDs {
+		archived, err := projectArchived(ctx, s.projects, id)
+		if err != nil {
+			return err
+		}
+		if archived {
+			return models.ErrProjectArchived
+		}
+	}
+	return nil
+}
+
+// Get retrieves a task by ID.
+func (s *LifecycleTaskStore) Get(ctx context.Context, id models.TaskID) (*models.Task, error) {
+	return s.next.Get(ctx, id)
+}
+
+// GetAll retrieves all tasks.
+func (s *LifecycleTaskStore) GetAll(ctx context.Context) ([]*models.Task, error) {
+	return s.next.GetAll(ctx)
+}
+
+// Create stores a new task unless its project is archived.
+func (s *LifecycleTaskStore) Create(ctx context.Context, task *models.Task) error {
+	if err := s.check(ctx, task.ProjectID); err != nil {
+		return err
+	}
+	return s.next.Create(ctx, task)
+}
+
+// Update updates an existing task unless it is in, or would move to,
+// an archived project.
+func (s *LifecycleTaskStore) Update(ctx context.Context, task *models.Task) error {
+	current, err := s.next.Get(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.check(ctx, current.ProjectID, task.ProjectID); err != nil {
+		return err
+	}
+	return s.next.Update(ctx, task)
+}
+
+// Delete removes a task unless its project is archived.
+func (s *LifecycleTaskStore) Delete(ctx context.Context, id models.TaskID) error {
+	current, err := s.next.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.check(ctx, current.ProjectID); err != nil {
+		return err
+	}
+	return s.next.Delete(ctx, id)
+}
+
+// LifecycleProjectStore is a ProjectStore decorator that keeps archived
+// projects read-only: the only update allowed is one that takes the
+// project out of the archived state.
+type LifecycleProjectStore struct {
+	ProjectStore
+}
+
+// NewLifecycleProjectStore wraps a project store with archive protection.
+func NewLifecycleProjectStore(next ProjectStore) *LifecycleProjectStore {
+	return &LifecycleProjectStore{ProjectStore: next}
+}
+
+// Update updates a project unless it is archived and stays archived.
+func (s *LifecycleProjectStore) Update(ctx context.Context, project *models.Project) error {
+	current, err := s.ProjectStore.Get(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+	if current.IsArchived() && project.IsArchived() {
+		return models.ErrProjectArchived
+	}
+	return s.ProjectStore.Update(ctx, project)
+}
+
+// ProjectStateRequest is the request body for changing a project's state.
+type ProjectStateRequest struct {
+	State models.ProjectState `json:"state"`
+}
+
+// SetState handles PUT /projects/{id}/state requests.
+func (h *ProjectHandler) SetState(w http.ResponseWriter, r *http.Request, id models.ProjectID) {
+	var req ProjectStateRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	h.setState(w, r, id, req.State)
+}
+
+// Archive handles POST /projects/{id}/archive requests. The project and
+// its tasks become read-only, and its tasks are left out of task
+// listings and the dashboard unless asked for.
+func (h *ProjectHandler) Archive(w http.ResponseWriter, r *http.Request, id models.ProjectID) {
+	h.setState(w, r, id, models.ProjectArchived)
+}
+
+// Unarchive handles POST /projects/{id}/unarchive requests, making the
+// project active again.
+func (h *ProjectHandler) Unarchive(w http.ResponseWriter, r *http.Request, id models.ProjectID) {
+	h.setState(w, r, id, models.ProjectActive)
+}
+
+// setState moves a project to a lifecycle state and writes it. Setting
+// the state a project is already in succeeds without changes.
+func (h *ProjectHandler) setState(w http.ResponseWriter, r *http.Request, id models.ProjectID, state models.ProjectState) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	project, err := h.projects.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			http.Error(w, "project not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get project", err)
+		return
+	}
+
+	updated := *project
+	changed, err := updated.SetState(state)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if changed {
+		if err := h.projects.Update(r.Context(), &updated); err != nil {
+			if writeHookRejection(w, err) {
+				return
+			}
+			writeServerError(w, r, "failed to update project", err)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, &updated)
+}
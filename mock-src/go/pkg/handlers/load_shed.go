@@ -0,0 +1,120 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// defaultShedRetryAfterSeconds is advertised to clients whose request was shed.
+const defaultShedRetryAfterSeconds = 1
+
+// LoadSheddingConfig configures admission limits.
+//
+// MaxInFlight caps concurrent requests of any kind. MaxWritesInFlight
+// caps concurrent mutations and should be lower, so bursts of writes
+// leave headroom for reads. A non-positive MaxInFlight is unlimited. A
+// non-positive MaxWritesInFlight defaults to three quarters of a
+// positive MaxInFlight, but at least one, and is otherwise unlimited.
+type LoadSheddingConfig struct {
+	MaxInFlight       int
+	MaxWritesInFlight int
+}
+
+// LoadSheddingStats reports current load and how many requests were shed.
+type LoadSheddingStats struct {
+	InFlight       int   `json:"in_flight"`
+	WritesInFlight int   `json:"writes_in_flight"`
+	ShedReads      int64 `json:"shed_reads"`
+	ShedWrites     int64 `json:"shed_writes"`
+}
+
+// LoadShedder rejects requests beyond the configured concurrency.
+//
+// Writes over their own limit get 429 so clients back off; any request
+// over the overall limit gets 503. Both carry a Retry-After header.
+// Reads are preferred: they are only turned away once the server is
+// completely full.
+type LoadShedder struct {
+	mu     sync.Mutex
+	config LoadSheddingConfig
+	stats  LoadSheddingStats
+	exempt map[string]bool
+}
+
+// NewLoadShedder creates a load shedder. Requests to the exempt paths,
+// such as health checks, are always admitted and not counted.
+func NewLoadShedder(config LoadSheddingConfig, exempt ...string) *LoadShedder {
+	if config.MaxWritesInFlight <= 0 && config.MaxInFlight > 0 {
+		config.MaxWritesInFlight = max(config.MaxInFlight*3/4, 1)
+	}
+	l := &LoadShedder{config: config, exempt: make(map[string]bool, len(exempt))}
+	for _, path := range exempt {
+		l.exempt[path] = true
+	}
+	return l
+}
+
+// Stats returns the current load and shed counts.
+func (l *LoadShedder) Stats() LoadSheddingStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.stats
+}
+
+// admit reserves a slot for a request, returning 0 on success or the
+// status to reject it with.
+func (l *LoadShedder) admit(write bool) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.config.MaxInFlight > 0 && l.stats.InFlight >= l.config.MaxInFlight {
+		if write {
+			l.stats.ShedWrites++
+		} else {
+			l.stats.ShedReads++
+		}
+		return http.StatusServiceUnavailable
+	}
+	if write {
+		if l.config.MaxWritesInFlight > 0 && l.stats.WritesInFlight >= l.config.MaxWritesInFlight {
+			l.stats.ShedWrites++
+			return http.StatusTooManyRequests
+		}
+		l.stats.WritesInFlight++
+	}
+	l.stats.InFlight++
+	return 0
+}
+
+// release frees a slot reserved by admit.
+func (l *LoadShedder) release(write bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stats.InFlight--
+	if write {
+		l.stats.WritesInFlight--
+	}
+}
+
+// Middleware admits or sheds each request.
+func (l *LoadShedder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l.exempt[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		write := isMutation(r.Method)
+		if status := l.admit(write); status != 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(defaultShedRetryAfterSeconds))
+			http.Error(w, "server is busy, retry later", status)
+			return
+		}
+		defer l.release(write)
+		next.ServeHTTP(w, r)
+	})
+}
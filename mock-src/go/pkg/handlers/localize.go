@@ -0,0 +1,130 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/example/tasktracker/pkg/i18n"
+)
+
+// Localizer translates error responses into the client's language.
+//
+// The language is negotiated from the Accept-Language header, falling
+// back to the authenticated user's locale preference and then English.
+// Plain-text error bodies, as written by http.Error, and the title and
+// detail of problem responses are translated; other responses pass
+// through untouched. Place it inside authentication so the user's
+// preference is known.
+type Localizer struct {
+	catalog *i18n.Catalog
+}
+
+// NewLocalizer creates localization middleware using catalog.
+func NewLocalizer(catalog *i18n.Catalog) *Localizer {
+	return &Localizer{catalog: catalog}
+}
+
+// Middleware translates the error responses of requests.
+func (l *Localizer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Language")
+		lang := l.language(r)
+		if lang == i18n.DefaultLanguage {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		lw := &localizingWriter{ResponseWriter: w}
+		next.ServeHTTP(lw, r)
+		if lw.buffering {
+			l.flush(w, lw, lang)
+		}
+	})
+}
+
+// language picks the language for a request's responses.
+func (l *Localizer) language(r *http.Request) string {
+	supported := l.catalog.Languages()
+	if lang := i18n.Negotiate(r.Header.Get("Accept-Language"), supported); lang != "" {
+		return lang
+	}
+	if user, ok := UserFromContext(r.Context()); ok && user.Preferences.Locale != "" {
+		if lang := i18n.Negotiate(user.Preferences.Locale, supported); lang != "" {
+			return lang
+		}
+	}
+	return i18n.DefaultLanguage
+}
+
+// flush writes a buffered error response translated into lang.
+func (l *Localizer) flush(w http.ResponseWriter, lw *localizingWriter, lang string) {
+	body := lw.body.Bytes()
+	if lw.problem {
+		var p Problem
+		if err := json.Unmarshal(body, &p); err == nil {
+			p.Title = l.catalog.Translate(lang, p.Title)
+			p.Detail = l.catalog.Translate(lang, p.Detail)
+			if out, err := json.Marshal(&p); err == nil {
+				body = append(out, '\n')
+			}
+		}
+	} else {
+		body = []byte(l.catalog.Translate(lang, strings.TrimSuffix(string(body), "\n")) + "\n")
+	}
+	w.Header().Set("Content-Language", lang)
+	w.Header().Del("Content-Length")
+	w.WriteHeader(lw.status)
+	w.Write(body)
+}
+
+// localizingWriter buffers error responses so they can be translated,
+// and passes everything else through.
+type localizingWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	buffering   bool
+	problem     bool
+	body        bytes.Buffer
+}
+
+// WriteHeader starts buffering for plain-text and problem error responses.
+func (w *localizingWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = status
+	if status >= http.StatusBadRequest {
+		mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+		switch mediaType {
+		case "text/plain":
+			w.buffering = true
+		case "application/problem+json":
+			w.buffering, w.problem = true, true
+		}
+	}
+	if !w.buffering {
+		w.ResponseWriter.WriteHeader(status)
+	}
+}
+
+// Write buffers error responses and passes others through.
+func (w *localizingWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.buffering {
+		return w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (w *localizingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,162 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultRetryAfter is advertised to clients when no estimate is set.
+const defaultRetryAfter = 60 * time.Second
+
+// MaintenanceStatus describes the current maintenance mode.
+type MaintenanceStatus struct {
+	Enabled           bool      `json:"enabled"`
+	Message           string    `json:"message,omitempty"`
+	RetryAfterSeconds int       `json:"retry_after_seconds"`
+	Since             time.Time `json:"since,omitempty"`
+}
+
+// MaintenanceMode puts the server into a read-only state at runtime.
+//
+// While enabled, mutating requests routed through Middleware are
+// rejected with 503 and a Retry-After header; reads are still served.
+// Background jobs started with the mode skip their runs.
+type MaintenanceMode struct {
+	mu     sync.RWMutex
+	status MaintenanceStatus
+	exempt map[string]bool
+}
+
+// NewMaintenanceMode creates a maintenance mode switch, initially off.
+//
+// Requests to the exempt paths are never rejected, so the toggle route
+// itself stays reachable.
+func NewMaintenanceMode(exempt ...string) *MaintenanceMode {
+	m := &MaintenanceMode{exempt: make(map[string]bool, len(exempt))}
+	m.status.RetryAfterSeconds = int(defaultRetryAfter / time.Second)
+	for _, path := range exempt {
+		m.exempt[path] = true
+	}
+	return m
+}
+
+// Enable turns on read-only mode.
+//
+// A non-positive retryAfter falls back to the default estimate.
+func (m *MaintenanceMode) Enable(message string, retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		retryAfter = defaultRetryAfter
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.status = MaintenanceStatus{
+		Enabled:           true,
+		Message:           message,
+		RetryAfterSeconds: int(retryAfter / time.Second),
+		Since:             time.Now(),
+	}
+}
+
+// Disable turns off read-only mode.
+func (m *MaintenanceMode) Disable() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.status = MaintenanceStatus{RetryAfterSeconds: int(defaultRetryAfter / time.Second)}
+}
+
+// Status returns the current maintenance status.
+func (m *MaintenanceMode) Status() MaintenanceStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.status
+}
+
+// Enabled reports whether read-only mode is on. A nil mode is never on.
+func (m *MaintenanceMode) Enabled() bool {
+	if m == nil {
+		return false
+	}
+	return m.Status().Enabled
+}
+
+// Middleware rejects mutating requests while maintenance mode is on.
+func (m *MaintenanceMode) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isMutation(r.Method) && !m.exempt[r.URL.Path] {
+			status := m.Status()
+			if status.Enabled {
+				message := status.Message
+				if message == "" {
+					message = "server is in read-only maintenance mode"
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(status.RetryAfterSeconds))
+				http.Error(w, message, http.StatusServiceUnavailable)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// MaintenanceHandler handles HTTP requests for toggling maintenance mode.
+type MaintenanceHandler struct {
+	mode *MaintenanceMode
+}
+
+// NewMaintenanceHandler creates a new maintenance handler.
+func NewMaintenanceHandler(mode *MaintenanceMode) *MaintenanceHandler {
+	return &MaintenanceHandler{mode: mode}
+}
+
+// SetMaintenanceRequest is the request body for toggling maintenance mode.
+type SetMaintenanceRequest struct {
+	Enabled           bool   `json:"enabled"`
+	Message           string `json:"message,omitempty"`
+	RetryAfterSeconds int    `json:"retry_after_seconds,omitempty"`
+}
+
+// Get handles GET /admin/maintenance requests.
+func (h *MaintenanceHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if !requireManage(w, r) {
+		return
+	}
+	writeJSON(w, http.StatusOK, h.mode.Status())
+}
+
+// Set handles PUT /admin/maintenance requests.
+func (h *MaintenanceHandler) Set(w http.ResponseWriter, r *http.Request) {
+	caller, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+	if !caller.HasPermission("manage") {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var req SetMaintenanceRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if req.RetryAfterSeconds < 0 {
+		http.Error(w, "retry_after_seconds cannot be negative", http.StatusBadRequest)
+		return
+	}
+
+	if req.Enabled {
+		h.mode.Enable(req.Message, time.Duration(req.RetryAfterSeconds)*time.Second)
+	} else {
+		h.mode.Disable()
+	}
+
+	writeJSON(w, http.StatusOK, h.mode.Status())
+}
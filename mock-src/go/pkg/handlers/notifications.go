@@ -0,0 +1,124 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// NotificationStore defines the interface for notification storage.
+type NotificationStore interface {
+	// Create stores a new notification.
+	Create(ctx context.Context, notification *models.Notification) error
+	// ListByUser retrieves a user's notifications, newest first.
+	ListByUser(ctx context.Context, userID models.UserID) ([]*models.Notification, error)
+	// MarkRead marks one of a user's notifications as read.
+	MarkRead(ctx context.Context, userID models.UserID, id string) error
+}
+
+// ErrNotificationNotFound is returned when a notification is not found.
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// InMemoryNotificationStore is an in-memory implementation of NotificationStore.
+type InMemoryNotificationStore struct {
+	mu            sync.RWMutex
+	notifications map[string]*models.Notification
+}
+
+// NewInMemoryNotificationStore creates a new in-memory notification store.
+func NewInMemoryNotificationStore() *InMemoryNotificationStore {
+	return &InMemoryNotificationStore{
+		notifications: make(map[string]*models.Notification),
+	}
+}
+
+// Create stores a new notification.
+func (s *InMemoryNotificationStore) Create(ctx context.Context, notification *models.Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.notifications[notification.ID] = notification
+	return nil
+}
+
+// ListByUser retrieves a user's notifications, newest first.
+func (s *InMemoryNotificationStore) ListByUser(ctx context.Context, userID models.UserID) ([]*models.Notification, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	notifications := make([]*models.Notification, 0)
+	for _, n := range s.notifications {
+		if n.UserID == userID {
+			notifications = append(notifications, n)
+		}
+	}
+	sort.Slice(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt.After(notifications[j].CreatedAt)
+	})
+	return notifications, nil
+}
+
+// MarkRead marks one of a user's notifications as read.
+func (s *InMemoryNotificationStore) MarkRead(ctx context.Context, userID models.UserID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, ok := s.notifications[id]
+	if !ok || n.UserID != userID {
+		return ErrNotificationNotFound
+	}
+	n.Read = true
+	return nil
+}
+
+// NotificationHandler handles HTTP requests for the current user's notifications.
+type NotificationHandler struct {
+	notifications NotificationStore
+}
+
+// NewNotificationHandler creates a new notification handler.
+func NewNotificationHandler(notifications NotificationStore) *NotificationHandler {
+	return &NotificationHandler{notifications: notifications}
+}
+
+// List handles GET /me/notifications requests.
+func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	notifications, err := h.notifications.ListByUser(r.Context(), user.ID)
+	if err != nil {
+		writeServerError(w, r, "failed to list notifications", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, notifications)
+}
+
+// MarkRead handles POST /me/notifications/{id}/read requests.
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request, id string) {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.notifications.MarkRead(r.Context(), user.ID, id); err != nil {
+		if errors.Is(err, ErrNotificationNotFound) {
+			http.Error(w, "notification not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to update notification", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
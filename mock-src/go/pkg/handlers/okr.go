@@ -0,0 +1,458 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// ObjectiveStore defines the interface for objective storage.
+type ObjectiveStore interface {
+	Store[string, *models.Objective]
+}
+
+// ErrObjectiveNotFound is returned when an objective is not found.
+var ErrObjectiveNotFound = errors.New("objective not found")
+
+// InMemoryObjectiveStore is an in-memory implementation of ObjectiveStore.
+type InMemoryObjectiveStore struct {
+	*InMemoryStore[string, *models.Objective]
+}
+
+// NewInMemoryObjectiveStore creates a new in-memory objective store.
+func NewInMemoryObjectiveStore() *InMemoryObjectiveStore {
+	return &InMemoryObjectiveStore{NewInMemoryStore[string, *models.Objective](ErrObjectiveNotFound)}
+}
+
+// KeyResultNode is a key result in the OKR tree with its progress.
+type KeyResultNode struct {
+	models.KeyResult
+	models.KeyResultProgress
+}
+
+// ObjectiveNode is an objective in the OKR tree with its progress and
+// the objectives that contribute to it.
+//
+// Progress is the mean progress of the key results with linked work,
+// or zero if none has any.
+type ObjectiveNode struct {
+	ID          string           `json:"id"`
+	ParentID    string           `json:"parent_id,omitempty"`
+	Title       string           `json:"title"`
+	Description string           `json:"description,omitempty"`
+	OwnerID     *models.UserID   `json:"owner_id,omitempty"`
+	Period      string           `json:"period,omitempty"`
+	Progress    float64          `json:"progress"`
+	KeyResults  []*KeyResultNode `json:"key_results"`
+	Children    []*ObjectiveNode `json:"children"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// buildOKRTree returns the nodes of the objectives under parentID, each
+// with its subtree, oldest first. Each objective appears once, so stored
+// data with a cycle of parents cannot make it recurse forever.
+func buildOKRTree(objectives []*models.Objective, tasks []*models.Task, parentID string) []*ObjectiveNode {
+	children := make(map[string][]*models.Objective)
+	for _, o := range objectives {
+		children[o.ParentID] = append(children[o.ParentID], o)
+	}
+
+	visited := map[string]bool{parentID: true}
+	var build func(parentID string) []*ObjectiveNode
+	build = func(parentID string) []*ObjectiveNode {
+		list := children[parentID]
+		sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+		nodes := make([]*ObjectiveNode, 0, len(list))
+		for _, o := range list {
+			if visited[o.ID] {
+				continue
+			}
+			visited[o.ID] = true
+			node := objectiveNode(o, tasks)
+			node.Children = build(o.ID)
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+	return build(parentID)
+}
+
+// objectiveNode computes an objective's node without its children.
+func objectiveNode(o *models.Objective, tasks []*models.Task) *ObjectiveNode {
+	node := &ObjectiveNode{
+		ID:          o.ID,
+		ParentID:    o.ParentID,
+		Title:       o.Title,
+		Description: o.Description,
+		OwnerID:     o.OwnerID,
+		Period:      o.Period,
+		KeyResults:  make([]*KeyResultNode, 0, len(o.KeyResults)),
+		Children:    make([]*ObjectiveNode, 0),
+		CreatedAt:   o.CreatedAt,
+		UpdatedAt:   o.UpdatedAt,
+	}
+	measured := 0
+	for i := range o.KeyResults {
+		kr := &o.KeyResults[i]
+		progress := kr.Progress(tasks)
+		node.KeyResults = append(node.KeyResults, &KeyResultNode{KeyResult: *kr, KeyResultProgress: progress})
+		if progress.Total > 0 {
+			node.Progress += progress.Progress
+			measured++
+		}
+	}
+	if measured > 0 {
+		node.Progress /= float64(measured)
+	}
+	return node
+}
+
+// OKRHandler handles HTTP requests for objectives and key results.
+type OKRHandler struct {
+	objectives ObjectiveStore
+	tasks      TaskStore
+	projects   ProjectStore
+
+	// writeMu serializes objective writes so the parent and contributor
+	// checks cannot race with the writes they guard.
+	writeMu sync.Mutex
+}
+
+// NewOKRHandler creates a new OKR handler.
+func NewOKRHandler(objectives ObjectiveStore, tasks TaskStore, projects ProjectStore) *OKRHandler {
+	return &OKRHandler{objectives: objectives, tasks: tasks, projects: projects}
+}
+
+// loadTree loads the objectives and published tasks the tree is built from.
+func (h *OKRHandler) loadTree(ctx context.Context) ([]*models.Objective, []*models.Task, error) {
+	objectives, err := h.objectives.GetAll(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	tasks, err := h.tasks.GetAll(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return objectives, publishedTasks(tasks), nil
+}
+
+// Tree handles GET /okrs requests, returning the top-level objectives
+// with their key results, progress and contributing objectives.
+//
+// An optional period parameter keeps only top-level objectives of that
+// period.
+func (h *OKRHandler) Tree(w http.ResponseWriter, r *http.Request) {
+	objectives, tasks, err := h.loadTree(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to load objectives", err)
+		return
+	}
+
+	roots := buildOKRTree(objectives, tasks, "")
+	if period := r.URL.Query().Get("period"); period != "" {
+		filtered := make([]*ObjectiveNode, 0, len(roots))
+		for _, node := range roots {
+			if node.Period == period {
+				filtered = append(filtered, node)
+			}
+		}
+		roots = filtered
+	}
+
+	writeJSON(w, http.StatusOK, roots)
+}
+
+// Get handles GET /objectives/{id} requests, returning the objective
+// with its subtree.
+func (h *OKRHandler) Get(w http.ResponseWriter, r *http.Request, id string) {
+	objectives, tasks, err := h.loadTree(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to load objectives", err)
+		return
+	}
+	for _, o := range objectives {
+		if o.ID == id {
+			node := objectiveNode(o, tasks)
+			node.Children = buildOKRTree(objectives, tasks, o.ID)
+			writeJSON(w, http.StatusOK, node)
+			return
+		}
+	}
+
+	http.Error(w, "objective not found", http.StatusNotFound)
+}
+
+// KeyResultRequest is a key result in an objective request. A missing
+// ID adds a new key result; an existing ID keeps its links.
+type KeyResultRequest struct {
+	ID    string `json:"id,omitempty"`
+	Title string `json:"title"`
+}
+
+// ObjectiveRequest is the request body for creating or updating an
+// objective. Key results missing from an update are removed.
+type ObjectiveRequest struct {
+	ParentID    string             `json:"parent_id,omitempty"`
+	Title       string             `json:"title"`
+	Description string             `json:"description,omitempty"`
+	OwnerID     *models.UserID     `json:"owner_id,omitempty"`
+	Period      string             `json:"period,omitempty"`
+	KeyResults  []KeyResultRequest `json:"key_results"`
+}
+
+// apply copies the request's fields onto an objective.
+func (req *ObjectiveRequest) apply(o *models.Objective) error {
+	existing := o.KeyResults
+	o.ParentID = req.ParentID
+	o.Title = req.Title
+	o.Description = req.Description
+	o.OwnerID = req.OwnerID
+	o.Period = req.Period
+	o.KeyResults = make([]models.KeyResult, 0, len(req.KeyResults))
+	for _, kr := range req.KeyResults {
+		if kr.ID == "" {
+			next := models.NewKeyResult(kr.Title)
+			o.KeyResults = append(o.KeyResults, next)
+			continue
+		}
+		found := false
+		for _, current := range existing {
+			if current.ID == kr.ID {
+				current.Title = kr.Title
+				o.KeyResults = append(o.KeyResults, current)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.New("unknown key result " + kr.ID)
+		}
+	}
+	o.UpdatedAt = time.Now()
+	return nil
+}
+
+// checkParent returns an error unless the objective's parent exists and
+// is not the objective itself or one of its descendants.
+func (h *OKRHandler) checkParent(ctx context.Context, o *models.Objective) error {
+	for id, depth := o.ParentID, 0; id != ""; depth++ {
+		if id == o.ID || depth > 100 {
+			return errors.New("parent would create a cycle")
+		}
+		parent, err := h.objectives.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, ErrObjectiveNotFound) {
+				return errors.New("parent objective not found")
+			}
+			return err
+		}
+		id = parent.ParentID
+	}
+	return nil
+}
+
+// save validates an objective from a request and checks its parent,
+// writing an error and returning false if either fails.
+func (h *OKRHandler) save(w http.ResponseWriter, r *http.Request, o *models.Objective, req *ObjectiveRequest) bool {
+	if err := req.apply(o); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	if err := o.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	if err := h.checkParent(r.Context(), o); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// Create handles POST /objectives requests.
+func (h *OKRHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req ObjectiveRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+
+	objective := models.NewObjective(req.Title)
+	if !h.save(w, r, objective, &req) {
+		return
+	}
+	if err := h.objectives.Create(r.Context(), objective); err != nil {
+		writeServerError(w, r, "failed to create objective", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, objective)
+}
+
+// getObjective writes an error and returns nil unless the objective exists.
+func (h *OKRHandler) getObjective(w http.ResponseWriter, r *http.Request, id string) *models.Objective {
+	objective, err := h.objectives.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrObjectiveNotFound) {
+			http.Error(w, "objective not found", http.StatusNotFound)
+			return nil
+		}
+		writeServerError(w, r, "failed to get objective", err)
+		return nil
+	}
+	return objective
+}
+
+// Update handles PUT /objectives/{id} requests.
+func (h *OKRHandler) Update(w http.ResponseWriter, r *http.Request, id string) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req ObjectiveRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+
+	existing := h.getObjective(w, r, id)
+	if existing == nil {
+		return
+	}
+	objective := existing.Clone()
+	if !h.save(w, r, objective, &req) {
+		return
+	}
+	if err := h.objectives.Update(r.Context(), objective); err != nil {
+		writeServerError(w, r, "failed to update objective", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, objective)
+}
+
+// Delete handles DELETE /objectives/{id} requests. Objectives that
+// others contribute to cannot be deleted until those are moved or
+// deleted.
+func (h *OKRHandler) Delete(w http.ResponseWriter, r *http.Request, id string) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+
+	objectives, err := h.objectives.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list objectives", err)
+		return
+	}
+	for _, o := range objectives {
+		if o.ParentID == id {
+			http.Error(w, "objective has contributing objectives", http.StatusConflict)
+			return
+		}
+	}
+
+	if err := h.objectives.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, ErrObjectiveNotFound) {
+			http.Error(w, "objective not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to delete objective", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// KeyResultLinksRequest is the request body for linking work to a key
+// result. It replaces the current links.
+type KeyResultLinksRequest struct {
+	TaskIDs    []models.TaskID    `json:"task_ids"`
+	ProjectIDs []models.ProjectID `json:"project_ids"`
+}
+
+// SetLinks handles PUT /objectives/{id}/key-results/{kr}/links requests,
+// returning the key result with its new progress. Every linked task and
+// project must exist.
+func (h *OKRHandler) SetLinks(w http.ResponseWriter, r *http.Request, id, keyResultID string) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req KeyResultLinksRequest
+	if err := decodeJSONLimit(w, r, &req, maxBulkRequestBodyBytes); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	for _, taskID := range req.TaskIDs {
+		if _, err := h.tasks.Get(r.Context(), taskID); err != nil {
+			if errors.Is(err, ErrTaskNotFound) {
+				http.Error(w, "task "+string(taskID)+" not found", http.StatusBadRequest)
+				return
+			}
+			writeServerError(w, r, "failed to get task", err)
+			return
+		}
+	}
+	for _, projectID := range req.ProjectIDs {
+		if _, err := h.projects.Get(r.Context(), projectID); err != nil {
+			if errors.Is(err, ErrProjectNotFound) {
+				http.Error(w, "project "+string(projectID)+" not found", http.StatusBadRequest)
+				return
+			}
+			writeServerError(w, r, "failed to get project", err)
+			return
+		}
+	}
+
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+
+	existing := h.getObjective(w, r, id)
+	if existing == nil {
+		return
+	}
+	objective := existing.Clone()
+	kr := objective.KeyResult(keyResultID)
+	if kr == nil {
+		http.Error(w, "key result not found", http.StatusNotFound)
+		return
+	}
+	kr.TaskIDs = append(make([]models.TaskID, 0, len(req.TaskIDs)), req.TaskIDs...)
+	kr.ProjectIDs = append(make([]models.ProjectID, 0, len(req.ProjectIDs)), req.ProjectIDs...)
+	objective.UpdatedAt = time.Now()
+	if err := objective.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.objectives.Update(r.Context(), objective); err != nil {
+		writeServerError(w, r, "failed to update objective", err)
+		return
+	}
+
+	tasks, err := h.tasks.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &KeyResultNode{KeyResult: *kr, KeyResultProgress: kr.Progress(publishedTasks(tasks))})
+}
@@ -0,0 +1,244 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+	"github.com/example/tasktracker/pkg/pdf"
+)
+
+// defaultReportDays is the burndown range of a PDF report when none is given.
+const defaultReportDays = 30
+
+// ProjectReportData is what a PDF report template lays out.
+type ProjectReportData struct {
+	Project     *models.Project
+	Stats       *ProjectStats
+	Burndown    []models.BurndownPoint
+	Overdue     []*models.Task
+	GeneratedAt time.Time
+	Dates       *models.DateFormatter
+}
+
+// PDFReportTemplate lays out a project report. Deployments with their
+// own branding or sections supply their own template.
+type PDFReportTemplate interface {
+	Render(doc *pdf.Document, data *ProjectReportData) error
+}
+
+// PDFReportHandler renders project status reports as PDF.
+type PDFReportHandler struct {
+	projects ProjectStore
+	tasks    TaskStore
+	template PDFReportTemplate
+}
+
+// NewPDFReportHandler creates a new PDF report handler. A nil template
+// uses DefaultPDFReportTemplate.
+func NewPDFReportHandler(projects ProjectStore, tasks TaskStore, template PDFReportTemplate) *PDFReportHandler {
+	if template == nil {
+		template = DefaultPDFReportTemplate{}
+	}
+	return &PDFReportHandler{projects: projects, tasks: tasks, template: template}
+}
+
+// Report handles GET /projects/{id}/report.pdf requests.
+//
+// An optional days parameter, from 1 to 365, sets the burndown range.
+// Dates are in the caller's time zone and format; drafts are left out.
+// Demo requests get the project's and tasks' free text faked, so custom
+// templates cannot leak it.
+func (h *PDFReportHandler) Report(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	days := defaultReportDays
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > 365 {
+			http.Error(w, "days must be between 1 and 365", http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+
+	project, err := h.projects.Get(r.Context(), projectID)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			http.Error(w, "project not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get project", err)
+		return
+	}
+	all, err := h.tasks.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+	tasks := make([]*models.Task, 0, len(all))
+	for _, task := range publishedTasks(all) {
+		if task.ProjectID == projectID {
+			tasks = append(tasks, task)
+		}
+	}
+	if faker := demoFakerFromContext(r.Context()); faker != nil {
+		project, tasks = demoProject(faker, project), demoTasks(faker, tasks)
+	}
+
+	now := time.Now()
+	dates := DateFormatterFromContext(r.Context())
+	burndown, _, err := projectBurndown(r.Context(), h.tasks, projectID, now.AddDate(0, 0, -(days-1)), now, dates.Location())
+	if err != nil {
+		writeServerError(w, r, "failed to compute burndown", err)
+		return
+	}
+	data := &ProjectReportData{
+		Project:     project,
+		Stats:       computeProjectStats(projectID, tasks, now, project.Calendar),
+		Burndown:    burndown,
+		GeneratedAt: now,
+		Dates:       dates,
+	}
+	for _, task := range tasks {
+		if task.IsOverdueAt(now) {
+			data.Overdue = append(data.Overdue, task)
+		}
+	}
+	sort.Slice(data.Overdue, func(i, j int) bool { return data.Overdue[i].DueDate.Before(*data.Overdue[j].DueDate) })
+
+	doc := pdf.New(pdf.A4Width, pdf.A4Height)
+	doc.Title = project.Name + " status report"
+	if err := h.template.Render(doc, data); err != nil {
+		writeServerError(w, r, "failed to render report", err)
+		return
+	}
+
+	name := fmt.Sprintf("%s-report-%s.pdf", exportFileName(project.Name), now.UTC().Format("20060102"))
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	w.WriteHeader(http.StatusOK)
+	if err := doc.Write(w); err != nil {
+		log.Printf("report: failed to write pdf: %v", err)
+	}
+}
+
+// DefaultPDFReportTemplate lays out a project overview, a burndown
+// chart and the overdue tasks, continuing the list onto further pages.
+type DefaultPDFReportTemplate struct{}
+
+// Page layout of the default template, in points.
+const (
+	reportMargin      = 50.0
+	reportLineHeight  = 16.0
+	reportChartHeight = 180.0
+)
+
+// Render lays out the report.
+func (DefaultPDFReportTemplate) Render(doc *pdf.Document, data *ProjectReportData) error {
+	width, height := doc.Size()
+	content := width - 2*reportMargin
+	page := doc.AddPage()
+	y := height - reportMargin - 10
+
+	page.Text(reportMargin, y, pdf.Bold, 20, pdf.Black, pdf.Truncate(data.Project.Name, pdf.Bold, 20, content))
+	y -= 18
+	page.Text(reportMargin, y, pdf.Regular, 10, pdf.Gray, "Status report, generated "+data.Dates.DateTime(data.GeneratedAt))
+	y -= 36
+
+	stats := data.Stats
+	open := stats.Total - stats.ByStatus[models.TaskStatusCompleted] - stats.ByStatus[models.TaskStatusCancelled]
+	page.Text(reportMargin, y, pdf.Bold, 14, pdf.Black, "Overview")
+	y -= 22
+	overview := [][2]string{
+		{"Total tasks", strconv.Itoa(stats.Total)},
+		{"Open", strconv.Itoa(open)},
+		{"Completed", strconv.Itoa(stats.ByStatus[models.TaskStatusCompleted])},
+		{"Blocked", strconv.Itoa(stats.ByStatus[models.TaskStatusBlocked])},
+		{"Overdue", strconv.Itoa(stats.Overdue)},
+		{"Completion", fmt.Sprintf("%.0f%%", stats.CompletionRate*100)},
+		{"Mean cycle time", fmt.Sprintf("%.1f h", stats.MeanCycleTimeHours)},
+	}
+	for i, row := range overview {
+		x := reportMargin + float64(i%2)*content/2
+		page.Text(x, y, pdf.Regular, 11, pdf.Gray, row[0])
+		page.Text(x+130, y, pdf.Bold, 11, pdf.Black, row[1])
+		if i%2 == 1 || i == len(overview)-1 {
+			y -= reportLineHeight
+		}
+	}
+	y -= 24
+
+	page.Text(reportMargin, y, pdf.Bold, 14, pdf.Black, fmt.Sprintf("Burndown (last %d days)", len(data.Burndown)))
+	y -= 14 + reportChartHeight
+	drawBurndown(page, data, reportMargin+30, y, content-30, reportChartHeight)
+	y -= 48
+
+	page.Text(reportMargin, y, pdf.Bold, 14, pdf.Black, fmt.Sprintf("Overdue tasks (%d)", len(data.Overdue)))
+	y -= 22
+	if len(data.Overdue) == 0 {
+		page.Text(reportMargin, y, pdf.Regular, 11, pdf.Gray, "None.")
+	}
+	for _, task := range data.Overdue {
+		if y < reportMargin {
+			page = doc.AddPage()
+			y = height - reportMargin - 10
+		}
+		due := "due " + data.Dates.Date(*task.DueDate)
+		dueWidth := pdf.TextWidth(due, pdf.Regular, 10)
+		page.Text(reportMargin, y, pdf.Bold, 10, pdf.Black, task.Reference())
+		page.Text(reportMargin+70, y, pdf.Regular, 10, pdf.Black, pdf.Truncate(task.Title, pdf.Regular, 10, content-80-dueWidth))
+		page.Text(width-reportMargin-dueWidth, y, pdf.Regular, 10, pdf.Red, due)
+		y -= reportLineHeight
+	}
+	return nil
+}
+
+// drawBurndown draws the burndown chart in the box whose bottom-left
+// corner is at x, y: the ideal line in gray, the remaining tasks in blue.
+func drawBurndown(page *pdf.Page, data *ProjectReportData, x, y, width, height float64) {
+	points := data.Burndown
+	maxValue := 1
+	for _, p := range points {
+		maxValue = max(maxValue, p.Remaining)
+	}
+
+	for _, v := range []int{0, maxValue / 2, maxValue} {
+		gy := y + height*float64(v)/float64(maxValue)
+		page.Line(x, gy, x+width, gy, 0.5, pdf.Color{R: 0.88, G: 0.88, B: 0.88})
+		label := strconv.Itoa(v)
+		page.Text(x-6-pdf.TextWidth(label, pdf.Regular, 8), gy-3, pdf.Regular, 8, pdf.Gray, label)
+	}
+	page.Line(x, y, x, y+height, 0.75, pdf.Gray)
+	page.Line(x, y, x+width, y, 0.75, pdf.Gray)
+	if len(points) == 0 {
+		return
+	}
+
+	step := 0.0
+	if len(points) > 1 {
+		step = width / float64(len(points)-1)
+	}
+	ideal := make([][2]float64, len(points))
+	actual := make([][2]float64, len(points))
+	for i, p := range points {
+		px := x + float64(i)*step
+		ideal[i] = [2]float64{px, y + height*p.Ideal/float64(maxValue)}
+		actual[i] = [2]float64{px, y + height*float64(p.Remaining)/float64(maxValue)}
+	}
+	page.Polyline(ideal, 1, pdf.Gray)
+	page.Polyline(actual, 2, pdf.Blue)
+
+	first := data.Dates.Date(points[0].Date)
+	last := data.Dates.Date(points[len(points)-1].Date)
+	page.Text(x, y-14, pdf.Regular, 8, pdf.Gray, first)
+	page.Text(x+width-pdf.TextWidth(last, pdf.Regular, 8), y-14, pdf.Regular, 8, pdf.Gray, last)
+	page.Rect(x, y-30, 10, 3, pdf.Blue)
+	page.Text(x+14, y-32, pdf.Regular, 8, pdf.Gray, "Remaining")
+	page.Rect(x+70, y-30, 10, 3, pdf.Gray)
+	page.Text(x+84, y-32, pdf.Regular, 8, pdf.Gray, "Ideal")
+}
@@ -0,0 +1,206 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// ErrDuplicatePlugin is returned when loading a plugin whose name is
+// already loaded.
+var ErrDuplicatePlugin = errors.New("plugin already loaded")
+
+// ErrRouteConflict is returned when a plugin registers a route pattern
+// that is already taken.
+var ErrRouteConflict = errors.New("route already registered")
+
+// ErrNoEventBus is returned when a plugin subscribes to events but the
+// loader has no event bus.
+var ErrNoEventBus = errors.New("no event bus configured")
+
+// Plugin extends the tracker with routes, event consumers and store
+// decorators without changes to this package.
+type Plugin interface {
+	// Name identifies the plugin; it must be unique.
+	Name() string
+	// Register declares the plugin's extensions. Nothing is applied if
+	// it returns an error.
+	Register(reg *PluginRegistrar) error
+}
+
+// PluginRegistrar collects the extensions declared by one plugin.
+type PluginRegistrar struct {
+	routes      map[string]http.Handler
+	subscribers []pluginSubscriber
+	tasks       []func(TaskStore) TaskStore
+	projects    []func(ProjectStore) ProjectStore
+	users       []func(UserStore) UserStore
+}
+
+// pluginSubscriber is an event handler and the type it subscribes to.
+// An empty type subscribes to all events.
+type pluginSubscriber struct {
+	typ     EventType
+	handler EventHandler
+}
+
+// Handle registers a handler for a route pattern, as for http.ServeMux.
+func (r *PluginRegistrar) Handle(pattern string, handler http.Handler) {
+	r.routes[pattern] = handler
+}
+
+// HandleFunc registers a handler function for a route pattern.
+func (r *PluginRegistrar) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	r.Handle(pattern, http.HandlerFunc(handler))
+}
+
+// Subscribe registers an event handler for one event type.
+func (r *PluginRegistrar) Subscribe(typ EventType, handler EventHandler) {
+	r.subscribers = append(r.subscribers, pluginSubscriber{typ: typ, handler: handler})
+}
+
+// SubscribeAll registers an event handler for every event type.
+func (r *PluginRegistrar) SubscribeAll(handler EventHandler) {
+	r.subscribers = append(r.subscribers, pluginSubscriber{handler: handler})
+}
+
+// DecorateTaskStore registers a decorator wrapping the task store.
+func (r *PluginRegistrar) DecorateTaskStore(decorate func(TaskStore) TaskStore) {
+	r.tasks = append(r.tasks, decorate)
+}
+
+// DecorateProjectStore registers a decorator wrapping the project store.
+func (r *PluginRegistrar) DecorateProjectStore(decorate func(ProjectStore) ProjectStore) {
+	r.projects = append(r.projects, decorate)
+}
+
+// DecorateUserStore registers a decorator wrapping the user store.
+func (r *PluginRegistrar) DecorateUserStore(decorate func(UserStore) UserStore) {
+	r.users = append(r.users, decorate)
+}
+
+// PluginLoader loads plugins while a server is constructed, mounting
+// their routes on a mux and subscribing their consumers to an event bus.
+//
+// Store decorators are applied by TaskStore, ProjectStore and UserStore,
+// which the server calls with its base stores once every plugin is
+// loaded. Decorators wrap in load order, so the last one loaded is
+// outermost.
+type PluginLoader struct {
+	mu       sync.Mutex
+	mux      *http.ServeMux
+	bus      *EventBus
+	loaded   []string
+	routes   map[string]string
+	tasks    []func(TaskStore) TaskStore
+	projects []func(ProjectStore) ProjectStore
+	users    []func(UserStore) UserStore
+}
+
+// NewPluginLoader creates a loader mounting routes on mux and
+// subscribing to bus. bus may be nil if plugins do not consume events.
+//
+// Only clashes between plugins are detected; a plugin route duplicating
+// one the server registered on mux itself makes ServeMux panic.
+func NewPluginLoader(mux *http.ServeMux, bus *EventBus) *PluginLoader {
+	return &PluginLoader{mux: mux, bus: bus, routes: make(map[string]string)}
+}
+
+// Load registers plugins in order, stopping at the first failure.
+//
+// Returns ErrDuplicatePlugin or ErrRouteConflict on a clash,
+// ErrNoEventBus, or the plugin's own registration error. A plugin that
+// fails leaves no trace; those loaded before it stay loaded.
+func (l *PluginLoader) Load(plugins ...Plugin) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, p := range plugins {
+		if err := l.load(p); err != nil {
+			return fmt.Errorf("plugin %s: %w", p.Name(), err)
+		}
+	}
+	return nil
+}
+
+// load registers one plugin. The caller must hold l.mu.
+func (l *PluginLoader) load(p Plugin) error {
+	name := p.Name()
+	for _, loaded := range l.loaded {
+		if loaded == name {
+			return ErrDuplicatePlugin
+		}
+	}
+
+	reg := &PluginRegistrar{routes: make(map[string]http.Handler)}
+	if err := p.Register(reg); err != nil {
+		return err
+	}
+	for pattern := range reg.routes {
+		if owner, ok := l.routes[pattern]; ok {
+			return fmt.Errorf("%w: %s by %s", ErrRouteConflict, pattern, owner)
+		}
+	}
+	if len(reg.subscribers) > 0 && l.bus == nil {
+		return ErrNoEventBus
+	}
+
+	for pattern, handler := range reg.routes {
+		l.mux.Handle(pattern, handler)
+		l.routes[pattern] = name
+	}
+	for _, s := range reg.subscribers {
+		if s.typ == "" {
+			l.bus.SubscribeAll(s.handler)
+		} else {
+			l.bus.Subscribe(s.typ, s.handler)
+		}
+	}
+	l.tasks = append(l.tasks, reg.tasks...)
+	l.projects = append(l.projects, reg.projects...)
+	l.users = append(l.users, reg.users...)
+	l.loaded = append(l.loaded, name)
+	return nil
+}
+
+// Loaded returns the names of the loaded plugins, in load order.
+func (l *PluginLoader) Loaded() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]string(nil), l.loaded...)
+}
+
+// TaskStore wraps base in the plugins' task store decorators.
+func (l *PluginLoader) TaskStore(base TaskStore) TaskStore {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return decorate(base, l.tasks)
+}
+
+// ProjectStore wraps base in the plugins' project store decorators.
+func (l *PluginLoader) ProjectStore(base ProjectStore) ProjectStore {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return decorate(base, l.projects)
+}
+
+// UserStore wraps base in the plugins' user store decorators.
+func (l *PluginLoader) UserStore(base UserStore) UserStore {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return decorate(base, l.users)
+}
+
+// decorate applies decorators to store in order.
+func decorate[S any](store S, decorators []func(S) S) S {
+	for _, d := range decorators {
+		store = d(store)
+	}
+	return store
+}
@@ -0,0 +1,492 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// PortfolioStore defines the interface for portfolio storage.
+type PortfolioStore interface {
+	Store[string, *models.Portfolio]
+}
+
+// ErrPortfolioNotFound is returned when a portfolio is not found.
+var ErrPortfolioNotFound = errors.New("portfolio not found")
+
+// InMemoryPortfolioStore is an in-memory implementation of PortfolioStore.
+type InMemoryPortfolioStore struct {
+	*InMemoryStore[string, *models.Portfolio]
+}
+
+// NewInMemoryPortfolioStore creates a new in-memory portfolio store.
+func NewInMemoryPortfolioStore() *InMemoryPortfolioStore {
+	return &InMemoryPortfolioStore{NewInMemoryStore[string, *models.Portfolio](ErrPortfolioNotFound)}
+}
+
+// maxPortfolioDepth bounds how deeply programs nest, guarding walks up
+// and down the tree.
+const maxPortfolioDepth = 16
+
+// Sources of a portfolio member.
+const (
+	memberSourceDirect       = "direct"
+	memberSourceInherited    = "inherited"
+	memberSourceProjectOwner = "project_owner"
+)
+
+// EffectiveMember is a member of a portfolio with where the membership
+// comes from: direct, inherited from the parent, or project_owner.
+type EffectiveMember struct {
+	UserID models.UserID        `json:"user_id"`
+	Role   models.PortfolioRole `json:"role"`
+	Source string               `json:"source"`
+}
+
+// portfolioTree indexes every portfolio and project for membership and
+// roll-up walks.
+type portfolioTree struct {
+	byID     map[string]*models.Portfolio
+	children map[string][]*models.Portfolio
+	projects map[models.ProjectID]*models.Project
+}
+
+// loadPortfolioTree loads the portfolios and projects.
+func (h *PortfolioHandler) loadPortfolioTree(ctx context.Context) (*portfolioTree, error) {
+	portfolios, err := h.portfolios.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := h.projects.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tree := &portfolioTree{
+		byID:     make(map[string]*models.Portfolio, len(portfolios)),
+		children: make(map[string][]*models.Portfolio),
+		projects: make(map[models.ProjectID]*models.Project, len(projects)),
+	}
+	for _, p := range portfolios {
+		tree.byID[p.ID] = p
+		tree.children[p.ParentID] = append(tree.children[p.ParentID], p)
+	}
+	for _, list := range tree.children {
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	}
+	for _, project := range projects {
+		tree.projects[project.ID] = project
+	}
+	return tree, nil
+}
+
+// members returns a portfolio's effective members. A user listed
+// directly keeps that role; otherwise inherited roles win over project
+// ownership.
+func (t *portfolioTree) members(p *models.Portfolio) []*EffectiveMember {
+	members := make([]*EffectiveMember, 0, len(p.Members))
+	seen := make(map[models.UserID]bool)
+	add := func(userID models.UserID, role models.PortfolioRole, source string) {
+		if userID != "" && !seen[userID] {
+			seen[userID] = true
+			members = append(members, &EffectiveMember{UserID: userID, Role: role, Source: source})
+		}
+	}
+
+	for depth, current := 0, p; current != nil && depth < maxPortfolioDepth; depth++ {
+		source := memberSourceDirect
+		if current != p {
+			source = memberSourceInherited
+		}
+		for _, m := range current.Members {
+			add(m.UserID, m.Role, source)
+		}
+		if !current.InheritMembers {
+			break
+		}
+		current = t.byID[current.ParentID]
+	}
+	if p.IncludeProjectOwners {
+		for _, id := range p.ProjectIDs {
+			if project, ok := t.projects[id]; ok {
+				add(project.OwnerID, models.PortfolioViewer, memberSourceProjectOwner)
+			}
+		}
+	}
+	return members
+}
+
+// role returns a user's effective role in a portfolio, or "" if the
+// user is not a member.
+func (t *portfolioTree) role(p *models.Portfolio, userID models.UserID) models.PortfolioRole {
+	for _, m := range t.members(p) {
+		if m.UserID == userID {
+			return m.Role
+		}
+	}
+	return ""
+}
+
+// canView reports whether a caller may see a portfolio: managers see
+// every portfolio, other users those they are members of.
+func (t *portfolioTree) canView(ctx context.Context, p *models.Portfolio) bool {
+	caller, ok := UserFromContext(ctx)
+	if !ok {
+		return false
+	}
+	return caller.HasPermission("manage") || t.role(p, caller.ID) != ""
+}
+
+// canEdit reports whether a caller may change a portfolio: managers and
+// the portfolio's leads.
+func (t *portfolioTree) canEdit(ctx context.Context, p *models.Portfolio) bool {
+	caller, ok := UserFromContext(ctx)
+	if !ok {
+		return false
+	}
+	return caller.HasPermission("manage") || t.role(p, caller.ID) == models.PortfolioLead
+}
+
+// checkParent returns an error unless a portfolio's parent exists and
+// is not the portfolio itself or one of its programs.
+func (t *portfolioTree) checkParent(p *models.Portfolio) error {
+	for id, depth := p.ParentID, 0; id != ""; depth++ {
+		if id == p.ID {
+			return errors.New("parent would create a cycle")
+		}
+		if depth >= maxPortfolioDepth {
+			return errors.New("programs nest too deeply")
+		}
+		parent, ok := t.byID[id]
+		if !ok {
+			return errors.New("parent portfolio not found")
+		}
+		id = parent.ParentID
+	}
+	return nil
+}
+
+// PortfolioRollup is the leadership view of a portfolio: its figures
+// summed over every project in it and its programs, each project's
+// health, and the same view of each program.
+//
+// Health scores the summed figures the same way as a project's health.
+type PortfolioRollup struct {
+	PortfolioID       string             `json:"portfolio_id"`
+	Name              string             `json:"name"`
+	Projects          int                `json:"projects"`
+	Open              int                `json:"open"`
+	Overdue           int                `json:"overdue"`
+	DueThisWeek       int                `json:"due_this_week"`
+	Blocked           int                `json:"blocked"`
+	CompletedRecently int                `json:"completed_recently"`
+	Health            int                `json:"health"`
+	Level             string             `json:"level"`
+	ProjectHealth     []*ProjectHealth   `json:"project_health"`
+	Programs          []*PortfolioRollup `json:"programs"`
+}
+
+// rollup builds a portfolio's roll-up from per-project health figures.
+// A project in several programs is counted once in the totals.
+func (t *portfolioTree) rollup(p *models.Portfolio, health map[models.ProjectID]*ProjectHealth, depth int) (*PortfolioRollup, map[models.ProjectID]bool) {
+	r := &PortfolioRollup{
+		PortfolioID:   p.ID,
+		Name:          p.Name,
+		ProjectHealth: make([]*ProjectHealth, 0, len(p.ProjectIDs)),
+		Programs:      make([]*PortfolioRollup, 0),
+	}
+	included := make(map[models.ProjectID]bool)
+	for _, id := range p.ProjectIDs {
+		if h, ok := health[id]; ok {
+			r.ProjectHealth = append(r.ProjectHealth, h)
+			included[id] = true
+		}
+	}
+	sort.Slice(r.ProjectHealth, func(i, j int) bool { return r.ProjectHealth[i].Health < r.ProjectHealth[j].Health })
+	if depth < maxPortfolioDepth {
+		for _, child := range t.children[p.ID] {
+			program, projects := t.rollup(child, health, depth+1)
+			r.Programs = append(r.Programs, program)
+			for id := range projects {
+				included[id] = true
+			}
+		}
+	}
+
+	for id := range included {
+		h := health[id]
+		r.Projects++
+		r.Open += h.Open
+		r.Overdue += h.Overdue
+		r.DueThisWeek += h.DueThisWeek
+		r.Blocked += h.Blocked
+		r.CompletedRecently += h.CompletedRecently
+	}
+	r.Health, r.Level = healthScore(r.Open, r.Overdue, r.Blocked)
+	return r, included
+}
+
+// PortfolioHandler handles HTTP requests for portfolios and programs.
+type PortfolioHandler struct {
+	portfolios PortfolioStore
+	projects   ProjectStore
+	tasks      TaskStore
+}
+
+// NewPortfolioHandler creates a new portfolio handler.
+func NewPortfolioHandler(portfolios PortfolioStore, projects ProjectStore, tasks TaskStore) *PortfolioHandler {
+	return &PortfolioHandler{portfolios: portfolios, projects: projects, tasks: tasks}
+}
+
+// viewable loads the tree and writes an error and returns nil unless the
+// portfolio exists and the caller may see it.
+func (h *PortfolioHandler) viewable(w http.ResponseWriter, r *http.Request, id string) (*portfolioTree, *models.Portfolio) {
+	if _, ok := UserFromContext(r.Context()); !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return nil, nil
+	}
+	tree, err := h.loadPortfolioTree(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to load portfolios", err)
+		return nil, nil
+	}
+	portfolio, ok := tree.byID[id]
+	if !ok || !tree.canView(r.Context(), portfolio) {
+		http.Error(w, "portfolio not found", http.StatusNotFound)
+		return nil, nil
+	}
+	return tree, portfolio
+}
+
+// List handles GET /portfolios requests, listing the portfolios and
+// programs the caller may see by name.
+func (h *PortfolioHandler) List(w http.ResponseWriter, r *http.Request) {
+	if _, ok := UserFromContext(r.Context()); !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+	tree, err := h.loadPortfolioTree(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to load portfolios", err)
+		return
+	}
+
+	visible := make([]*models.Portfolio, 0, len(tree.byID))
+	for _, p := range tree.byID {
+		if tree.canView(r.Context(), p) {
+			visible = append(visible, p)
+		}
+	}
+	sort.Slice(visible, func(i, j int) bool { return visible[i].Name < visible[j].Name })
+	writeJSON(w, http.StatusOK, visible)
+}
+
+// Get handles GET /portfolios/{id} requests.
+func (h *PortfolioHandler) Get(w http.ResponseWriter, r *http.Request, id string) {
+	_, portfolio := h.viewable(w, r, id)
+	if portfolio == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, portfolio)
+}
+
+// Members handles GET /portfolios/{id}/members requests, listing the
+// portfolio's effective members.
+func (h *PortfolioHandler) Members(w http.ResponseWriter, r *http.Request, id string) {
+	tree, portfolio := h.viewable(w, r, id)
+	if portfolio == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tree.members(portfolio))
+}
+
+// Rollup handles GET /portfolios/{id}/rollup requests.
+//
+// Figures count the tasks the caller can see; "this week" and the
+// optional recent_days parameter (1 to 90) work as on the dashboard.
+func (h *PortfolioHandler) Rollup(w http.ResponseWriter, r *http.Request, id string) {
+	recentDays := defaultDashboardRecentDays
+	if raw := r.URL.Query().Get("recent_days"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > 90 {
+			http.Error(w, "recent_days must be between 1 and 90", http.StatusBadRequest)
+			return
+		}
+		recentDays = n
+	}
+
+	tree, portfolio := h.viewable(w, r, id)
+	if portfolio == nil {
+		return
+	}
+	tasks, err := h.tasks.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+
+	win := newHealthWindow(time.Now(), DateFormatterFromContext(r.Context()).Location(), recentDays)
+	health := make(map[models.ProjectID]*ProjectHealth, len(tree.projects))
+	for _, project := range tree.projects {
+		health[project.ID] = &ProjectHealth{ProjectID: project.ID, Name: project.Name}
+	}
+	for _, task := range publishedTasks(tasks) {
+		if ph, ok := health[task.ProjectID]; ok && visibleTo(r.Context(), task) {
+			win.count(ph, task)
+		}
+	}
+	for _, ph := range health {
+		ph.Health, ph.Level = healthScore(ph.Open, ph.Overdue, ph.Blocked)
+	}
+
+	rollup, _ := tree.rollup(portfolio, health, 0)
+	writeJSON(w, http.StatusOK, rollup)
+}
+
+// PortfolioRequest is the request body for creating or updating a
+// portfolio. A ParentID makes it a program within that portfolio.
+type PortfolioRequest struct {
+	ParentID             string                   `json:"parent_id,omitempty"`
+	Name                 string                   `json:"name"`
+	Description          string                   `json:"description,omitempty"`
+	ProjectIDs           []models.ProjectID       `json:"project_ids"`
+	Members              []models.PortfolioMember `json:"members"`
+	InheritMembers       bool                     `json:"inherit_members"`
+	IncludeProjectOwners bool                     `json:"include_project_owners"`
+}
+
+// apply copies the request's fields onto a portfolio.
+func (req *PortfolioRequest) apply(p *models.Portfolio) {
+	p.ParentID = req.ParentID
+	p.Name = req.Name
+	p.Description = req.Description
+	p.ProjectIDs = append(make([]models.ProjectID, 0, len(req.ProjectIDs)), req.ProjectIDs...)
+	p.Members = append(make([]models.PortfolioMember, 0, len(req.Members)), req.Members...)
+	p.InheritMembers = req.InheritMembers
+	p.IncludeProjectOwners = req.IncludeProjectOwners
+	p.UpdatedAt = time.Now()
+}
+
+// check validates a portfolio against the tree, writing an error and
+// returning false if its fields, projects or parent are not valid.
+func (t *portfolioTree) check(w http.ResponseWriter, p *models.Portfolio) bool {
+	if err := p.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	for _, id := range p.ProjectIDs {
+		if _, ok := t.projects[id]; !ok {
+			http.Error(w, "project "+string(id)+" not found", http.StatusBadRequest)
+			return false
+		}
+	}
+	if err := t.checkParent(p); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// Create handles POST /portfolios requests.
+func (h *PortfolioHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req PortfolioRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	tree, err := h.loadPortfolioTree(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to load portfolios", err)
+		return
+	}
+	portfolio := models.NewPortfolio(req.Name)
+	req.apply(portfolio)
+	if !tree.check(w, portfolio) {
+		return
+	}
+
+	if err := h.portfolios.Create(r.Context(), portfolio); err != nil {
+		writeServerError(w, r, "failed to create portfolio", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, portfolio)
+}
+
+// Update handles PUT /portfolios/{id} requests. Managers and the
+// portfolio's leads may update it; only managers may move it to another
+// parent.
+func (h *PortfolioHandler) Update(w http.ResponseWriter, r *http.Request, id string) {
+	var req PortfolioRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	tree, existing := h.viewable(w, r, id)
+	if existing == nil {
+		return
+	}
+	if !tree.canEdit(r.Context(), existing) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	if caller, _ := UserFromContext(r.Context()); req.ParentID != existing.ParentID && !caller.HasPermission("manage") {
+		http.Error(w, "only managers can move a portfolio", http.StatusForbidden)
+		return
+	}
+
+	portfolio := *existing
+	req.apply(&portfolio)
+	if !tree.check(w, &portfolio) {
+		return
+	}
+
+	if err := h.portfolios.Update(r.Context(), &portfolio); err != nil {
+		writeServerError(w, r, "failed to update portfolio", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &portfolio)
+}
+
+// Delete handles DELETE /portfolios/{id} requests. A portfolio with
+// programs cannot be deleted until they are moved or deleted.
+func (h *PortfolioHandler) Delete(w http.ResponseWriter, r *http.Request, id string) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	tree, err := h.loadPortfolioTree(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to load portfolios", err)
+		return
+	}
+	if len(tree.children[id]) > 0 {
+		http.Error(w, "portfolio has programs", http.StatusConflict)
+		return
+	}
+
+	if err := h.portfolios.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, ErrPortfolioNotFound) {
+			http.Error(w, "portfolio not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to delete portfolio", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
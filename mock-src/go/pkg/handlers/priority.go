@@ -0,0 +1,249 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// PrioritySchemeStore defines the interface for per-project priority
+// scheme storage.
+type PrioritySchemeStore interface {
+	// Get retrieves the custom priority scheme of a project.
+	Get(ctx context.Context, projectID models.ProjectID) (*models.PriorityScheme, error)
+	// Put sets a project's priority scheme, replacing any existing one.
+	Put(ctx context.Context, scheme *models.PriorityScheme) error
+	// Delete removes a project's custom priority scheme.
+	Delete(ctx context.Context, projectID models.ProjectID) error
+}
+
+// ErrPrioritySchemeNotFound is returned when a project has no custom
+// priority scheme.
+var ErrPrioritySchemeNotFound = errors.New("priority scheme not found")
+
+// InMemoryPrioritySchemeStore is an in-memory implementation of
+// PrioritySchemeStore.
+type InMemoryPrioritySchemeStore struct {
+	mu      sync.RWMutex
+	schemes map[models.ProjectID]*models.PriorityScheme
+}
+
+// NewInMemoryPrioritySchemeStore creates a new in-memory priority scheme
+// store.
+func NewInMemoryPrioritySchemeStore() *InMemoryPrioritySchemeStore {
+	return &InMemoryPrioritySchemeStore{
+		schemes: make(map[models.ProjectID]*models.PriorityScheme),
+	}
+}
+
+// Get retrieves the custom priority scheme of a project.
+func (s *InMemoryPrioritySchemeStore) Get(ctx context.Context, projectID models.ProjectID) (*models.PriorityScheme, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	scheme, ok := s.schemes[projectID]
+	if !ok {
+		return nil, ErrPrioritySchemeNotFound
+	}
+	return scheme, nil
+}
+
+// Put sets a project's priority scheme, replacing any existing one.
+func (s *InMemoryPrioritySchemeStore) Put(ctx context.Context, scheme *models.PriorityScheme) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.schemes[scheme.ProjectID] = scheme
+	return nil
+}
+
+// Delete removes a project's custom priority scheme.
+func (s *InMemoryPrioritySchemeStore) Delete(ctx context.Context, projectID models.ProjectID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.schemes[projectID]; !ok {
+		return ErrPrioritySchemeNotFound
+	}
+	delete(s.schemes, projectID)
+	return nil
+}
+
+// projectPriorityScheme returns a project's custom priority scheme, or
+// the default scheme if it has none.
+func projectPriorityScheme(ctx context.Context, schemes PrioritySchemeStore, projectID models.ProjectID) (*models.PriorityScheme, error) {
+	scheme, err := schemes.Get(ctx, projectID)
+	if errors.Is(err, ErrPrioritySchemeNotFound) {
+		return models.DefaultPriorityScheme(projectID), nil
+	}
+	return scheme, err
+}
+
+// PriorityHandler handles HTTP requests for project priority schemes.
+type PriorityHandler struct {
+	schemes PrioritySchemeStore
+	tasks   TaskStore
+}
+
+// NewPriorityHandler creates a new priority scheme handler.
+func NewPriorityHandler(schemes PrioritySchemeStore, tasks TaskStore) *PriorityHandler {
+	return &PriorityHandler{schemes: schemes, tasks: tasks}
+}
+
+// PriorityDefinition is the response body for a project's priority
+// scheme. Custom is false when the project uses the default scheme.
+type PriorityDefinition struct {
+	*models.PriorityScheme
+	Custom bool `json:"custom"`
+}
+
+// PutPrioritySchemeRequest is the request body for setting a project's
+// priority scheme.
+//
+// Mapping maps ranks of the current scheme to ranks of the new one for
+// migrating existing tasks; unmapped ranks are migrated by
+// models.PriorityScheme.Migrate.
+type PutPrioritySchemeRequest struct {
+	Levels  []models.PriorityLevel                      `json:"levels"`
+	Default models.TaskPriority                         `json:"default"`
+	Mapping map[models.TaskPriority]models.TaskPriority `json:"mapping,omitempty"`
+}
+
+// PrioritySchemeResponse is the response body after setting a project's
+// priority scheme, reporting how many tasks were migrated.
+type PrioritySchemeResponse struct {
+	Scheme   *models.PriorityScheme `json:"scheme"`
+	Migrated int                    `json:"migrated"`
+}
+
+// Get handles GET /projects/{id}/priorities requests.
+func (h *PriorityHandler) Get(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	scheme, err := h.schemes.Get(r.Context(), projectID)
+	if err != nil {
+		if errors.Is(err, ErrPrioritySchemeNotFound) {
+			writeJSON(w, http.StatusOK, &PriorityDefinition{PriorityScheme: models.DefaultPriorityScheme(projectID)})
+			return
+		}
+		writeServerError(w, r, "failed to get priority scheme", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &PriorityDefinition{PriorityScheme: scheme, Custom: true})
+}
+
+// Put handles PUT /projects/{id}/priorities requests.
+//
+// The project's tasks are migrated to the new scheme before it is
+// saved.
+func (h *PriorityHandler) Put(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req PutPrioritySchemeRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	scheme := &models.PriorityScheme{
+		ProjectID: projectID,
+		Levels:    req.Levels,
+		Default:   req.Default,
+		UpdatedAt: time.Now(),
+	}
+	if err := scheme.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, to := range req.Mapping {
+		if !scheme.Has(to) {
+			http.Error(w, models.ErrUnknownPriority.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	h.replace(w, r, scheme, req.Mapping, func(ctx context.Context) error {
+		return h.schemes.Put(ctx, scheme)
+	})
+}
+
+// Delete handles DELETE /projects/{id}/priorities requests, migrating
+// the project's tasks back to the default scheme.
+func (h *PriorityHandler) Delete(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	if _, err := h.schemes.Get(r.Context(), projectID); err != nil {
+		if errors.Is(err, ErrPrioritySchemeNotFound) {
+			http.Error(w, "project has no custom priority scheme", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get priority scheme", err)
+		return
+	}
+
+	h.replace(w, r, models.DefaultPriorityScheme(projectID), nil, func(ctx context.Context) error {
+		return h.schemes.Delete(ctx, projectID)
+	})
+}
+
+// replace migrates the project's tasks from its current scheme to next,
+// then saves the change with store.
+func (h *PriorityHandler) replace(w http.ResponseWriter, r *http.Request, next *models.PriorityScheme, mapping map[models.TaskPriority]models.TaskPriority, store func(context.Context) error) {
+	current, err := projectPriorityScheme(r.Context(), h.schemes, next.ProjectID)
+	if err != nil {
+		writeServerError(w, r, "failed to get priority scheme", err)
+		return
+	}
+
+	migrated, err := h.migrateTasks(r.Context(), current, next, mapping)
+	if err != nil {
+		writeServerError(w, r, "failed to migrate task priorities", err)
+		return
+	}
+	if err := store(r.Context()); err != nil {
+		writeServerError(w, r, "failed to save priority scheme", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &PrioritySchemeResponse{Scheme: next, Migrated: migrated})
+}
+
+// migrateTasks rewrites the priority of each of the project's tasks from
+// the scheme from to the scheme to, returning how many tasks changed.
+func (h *PriorityHandler) migrateTasks(ctx context.Context, from, to *models.PriorityScheme, mapping map[models.TaskPriority]models.TaskPriority) (int, error) {
+	tasks, err := h.tasks.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for _, task := range tasks {
+		if task.ProjectID != to.ProjectID {
+			continue
+		}
+		changed := false
+		_, err := updateTask(ctx, h.tasks, task.ID, func(t *models.Task) bool {
+			rank, ok := mapping[t.Priority]
+			if !ok {
+				rank = to.Migrate(t.Priority, from)
+			}
+			changed = rank != t.Priority
+			t.Priority = rank
+			return changed
+		})
+		if err != nil && !errors.Is(err, ErrTaskNotFound) {
+			return migrated, err
+		}
+		if err == nil && changed {
+			migrated++
+		}
+	}
+	return migrated, nil
+}
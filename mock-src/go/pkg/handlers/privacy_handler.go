@@ -0,0 +1,220 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// PrivacyHandler handles data export and erasure requests for users.
+type PrivacyHandler struct {
+	users    UserStore
+	tasks    TaskStore
+	comments CommentStore
+	audit    AuditStore
+}
+
+// NewPrivacyHandler creates a new privacy handler.
+func NewPrivacyHandler(users UserStore, tasks TaskStore, comments CommentStore, audit AuditStore) *PrivacyHandler {
+	return &PrivacyHandler{users: users, tasks: tasks, comments: comments, audit: audit}
+}
+
+// UserExport is the response body for a user data export.
+type UserExport struct {
+	ExportedAt   time.Time            `json:"exported_at"`
+	User         *models.User         `json:"user"`
+	Tasks        []*models.Task       `json:"tasks"`
+	Comments     []*models.Comment    `json:"comments"`
+	AuditEntries []*models.AuditEntry `json:"audit_entries"`
+}
+
+// authorize returns the caller if they are the user or hold the manage permission.
+func (h *PrivacyHandler) authorize(w http.ResponseWriter, r *http.Request, id models.UserID) (*models.User, bool) {
+	caller, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return nil, false
+	}
+	if caller.ID != id && !caller.HasPermission("manage") {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return nil, false
+	}
+	return caller, true
+}
+
+// Export handles GET /users/{id}/export requests.
+//
+// The export contains the user record, tasks assigned to the user,
+// comments they wrote, and audit entries where they were the actor or
+// the target.
+func (h *PrivacyHandler) Export(w http.ResponseWriter, r *http.Request, id models.UserID) {
+	if _, ok := h.authorize(w, r, id); !ok {
+		return
+	}
+
+	user, err := h.users.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get user", err)
+		return
+	}
+
+	export := &UserExport{
+		ExportedAt:   time.Now(),
+		User:         user,
+		Tasks:        make([]*models.Task, 0),
+		Comments:     make([]*models.Comment, 0),
+		AuditEntries: make([]*models.AuditEntry, 0),
+	}
+
+	tasks, err := h.tasks.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+	for _, task := range tasks {
+		if task.AssigneeID != nil && *task.AssigneeID == id {
+			export.Tasks = append(export.Tasks, task)
+		}
+	}
+
+	comments, err := h.comments.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list comments", err)
+		return
+	}
+	for _, comment := range comments {
+		if comment.AuthorID == id {
+			export.Comments = append(export.Comments, comment)
+		}
+	}
+
+	entries, err := h.audit.List(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list audit entries", err)
+		return
+	}
+	for _, entry := range entries {
+		if entry.ActorID == id || (entry.TargetType == "user" && entry.TargetID == string(id)) {
+			export.AuditEntries = append(export.AuditEntries, entry)
+		}
+	}
+
+	w.Header().Set("Content-Disposition", `attachment; filename="user-export.json"`)
+	writeJSON(w, http.StatusOK, export)
+}
+
+// Erase handles POST /users/{id}/erase requests.
+//
+// Personal data on the user record is anonymized while the user ID is
+// kept, so tasks, comments, and audit history remain intact but no
+// longer identify the person. The user's open tasks are unassigned,
+// they stop watching every task, and @mentions of them in comments are
+// rewritten to their anonymized username.
+func (h *PrivacyHandler) Erase(w http.ResponseWriter, r *http.Request, id models.UserID) {
+	caller, ok := h.authorize(w, r, id)
+	if !ok {
+		return
+	}
+
+	user, err := h.users.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get user", err)
+		return
+	}
+
+	if user.Role == models.UserRoleOwner {
+		users, err := h.users.GetAll(r.Context())
+		if err != nil {
+			writeServerError(w, r, "failed to list users", err)
+			return
+		}
+		if !hasOtherActiveOwner(users, user.ID) {
+			http.Error(w, "cannot erase the last owner", http.StatusConflict)
+			return
+		}
+	}
+
+	user = user.Clone()
+	username := user.Username
+	user.Anonymize()
+	if err := h.users.Update(r.Context(), user); err != nil {
+		writeServerError(w, r, "failed to update user", err)
+		return
+	}
+
+	if err := h.releaseTasks(r.Context(), user.ID); err != nil {
+		writeServerError(w, r, "failed to release tasks", err)
+		return
+	}
+	if err := h.scrubMentions(r.Context(), username, user.Username); err != nil {
+		writeServerError(w, r, "failed to update comments", err)
+		return
+	}
+
+	entry := models.NewAuditEntry(caller.ID, models.AuditActionUserErased, "user", string(user.ID))
+	if err := h.audit.Append(r.Context(), entry); err != nil {
+		writeServerError(w, r, "failed to record audit entry", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// releaseTasks unassigns the open tasks held by userID and removes them
+// from the watchers of every task.
+func (h *PrivacyHandler) releaseTasks(ctx context.Context, userID models.UserID) error {
+	tasks, err := h.tasks.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	release := func(task *models.Task) bool {
+		changed := task.Unwatch(userID)
+		if task.AssigneeID != nil && *task.AssigneeID == userID && task.IsOpen() {
+			task.Unassign()
+			changed = true
+		}
+		return changed
+	}
+	for _, task := range tasks {
+		if !release(task.Clone()) {
+			continue
+		}
+		if _, err := updateTask(ctx, h.tasks, task.ID, release); err != nil && !errors.Is(err, ErrTaskNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// scrubMentions rewrites @mentions of username in every comment to
+// mention replacement instead.
+func (h *PrivacyHandler) scrubMentions(ctx context.Context, username, replacement string) error {
+	comments, err := h.comments.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, comment := range comments {
+		comment = comment.Clone()
+		if !comment.ReplaceMention(username, replacement) {
+			continue
+		}
+		if err := h.comments.Update(ctx, comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,502 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+	"github.com/google/uuid"
+)
+
+// ProjectStore defines the interface for project storage.
+type ProjectStore interface {
+	Store[models.ProjectID, *models.Project]
+}
+
+// ErrProjectNotFound is returned when a project is not found.
+var ErrProjectNotFound = errors.New("project not found")
+
+// InMemoryProjectStore is an in-memory implementation of ProjectStore.
+type InMemoryProjectStore struct {
+	*InMemoryStore[models.ProjectID, *models.Project]
+}
+
+// NewInMemoryProjectStore creates a new in-memory project store.
+func NewInMemoryProjectStore() *InMemoryProjectStore {
+	return &InMemoryProjectStore{NewInMemoryStore[models.ProjectID, *models.Project](ErrProjectNotFound)}
+}
+
+// projectCalendar returns the business calendar of a project, or nil if
+// projects is nil, the project is unknown or it has no calendar.
+func projectCalendar(ctx context.Context, projects ProjectStore, projectID models.ProjectID) (*models.BusinessCalendar, error) {
+	if projects == nil {
+		return nil, nil
+	}
+	project, err := projects.Get(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return project.Calendar, nil
+}
+
+// ProjectHandler handles HTTP requests for projects.
+type ProjectHandler struct {
+	projects  ProjectStore
+	tasks     TaskStore
+	templates TemplateStore
+}
+
+// NewProjectHandler creates a new project handler.
+func NewProjectHandler(projects ProjectStore, tasks TaskStore, templates TemplateStore) *ProjectHandler {
+	return &ProjectHandler{projects: projects, tasks: tasks, templates: templates}
+}
+
+// CreateProjectRequest is the request body for creating a project.
+type CreateProjectRequest struct {
+	Name        string                   `json:"name"`
+	Key         string                   `json:"key,omitempty"`
+	Slug        string                   `json:"slug,omitempty"`
+	Description string                   `json:"description,omitempty"`
+	IsTemplate  bool                     `json:"is_template,omitempty"`
+	Labels      []models.Label           `json:"labels,omitempty"`
+	Milestones  []models.Milestone       `json:"milestones,omitempty"`
+	Views       []models.View            `json:"views,omitempty"`
+	Calendar    *models.BusinessCalendar `json:"calendar,omitempty"`
+}
+
+// Create handles POST /projects requests.
+func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateProjectRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	project, err := models.NewProject(req.Name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Key != "" {
+		if err := models.ValidateProjectKey(req.Key); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !h.checkKeyAvailable(w, r, req.Key) {
+			return
+		}
+		project.Key = req.Key
+	}
+	if req.Slug != "" {
+		if err := models.ValidateSlug(req.Slug); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		project.Slug = req.Slug
+	}
+	project.Description = req.Description
+	project.IsTemplate = req.IsTemplate
+	if req.Calendar != nil {
+		if err := req.Calendar.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		project.Calendar = req.Calendar
+	}
+	if user, ok := UserFromContext(r.Context()); ok {
+		project.OwnerID = user.ID
+	}
+	if req.Labels != nil {
+		project.Labels = req.Labels
+	}
+	if req.Views != nil {
+		project.Views = req.Views
+	}
+	for _, m := range req.Milestones {
+		if m.ID == "" {
+			m.ID = uuid.New().String()
+		}
+		project.Milestones = append(project.Milestones, m)
+	}
+
+	if err := h.projects.Create(r.Context(), project); err != nil {
+		if writeHookRejection(w, err) {
+			return
+		}
+		writeServerError(w, r, "failed to create project", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, project)
+}
+
+// Get handles GET /projects/{id} requests.
+func (h *ProjectHandler) Get(w http.ResponseWriter, r *http.Request, id models.ProjectID) {
+	project, err := h.projects.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			http.Error(w, "project not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get project", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, project)
+}
+
+// SetCalendar handles PUT /projects/{id}/calendar requests.
+//
+// An empty body or JSON null removes the project's calendar.
+func (h *ProjectHandler) SetCalendar(w http.ResponseWriter, r *http.Request, id models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var calendar *models.BusinessCalendar
+	if err := decodeJSON(w, r, &calendar); err != nil && !errors.Is(err, io.EOF) {
+		writeDecodeError(w, err)
+		return
+	}
+	if calendar != nil {
+		if err := calendar.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	project, err := h.projects.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			http.Error(w, "project not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get project", err)
+		return
+	}
+
+	updated := *project
+	updated.Calendar = calendar
+	updated.UpdatedAt = time.Now()
+	if err := h.projects.Update(r.Context(), &updated); err != nil {
+		if writeHookRejection(w, err) {
+			return
+		}
+		writeServerError(w, r, "failed to update project", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &updated)
+}
+
+// SetDuplicateCheck handles PUT /projects/{id}/duplicate-check requests.
+//
+// An empty body or JSON null removes the project's duplicate check.
+func (h *ProjectHandler) SetDuplicateCheck(w http.ResponseWriter, r *http.Request, id models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var check *models.DuplicateCheck
+	if err := decodeJSON(w, r, &check); err != nil && !errors.Is(err, io.EOF) {
+		writeDecodeError(w, err)
+		return
+	}
+	if check != nil {
+		if err := check.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	project, err := h.projects.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			http.Error(w, "project not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get project", err)
+		return
+	}
+
+	updated := *project
+	updated.Duplicates = check
+	updated.UpdatedAt = time.Now()
+	if err := h.projects.Update(r.Context(), &updated); err != nil {
+		if writeHookRejection(w, err) {
+			return
+		}
+		writeServerError(w, r, "failed to update project", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &updated)
+}
+
+// ApprovalSettingRequest is the request body for the approval setting.
+type ApprovalSettingRequest struct {
+	RequiresApproval bool `json:"requires_approval"`
+}
+
+// SetApproval handles PUT /projects/{id}/approval requests, turning
+// approval of task completion on or off. Turning it off leaves tasks
+// already awaiting review in review until decided.
+func (h *ProjectHandler) SetApproval(w http.ResponseWriter, r *http.Request, id models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req ApprovalSettingRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	project, err := h.projects.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			http.Error(w, "project not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get project", err)
+		return
+	}
+
+	updated := *project
+	updated.RequiresApproval = req.RequiresApproval
+	updated.UpdatedAt = time.Now()
+	if err := h.projects.Update(r.Context(), &updated); err != nil {
+		if writeHookRejection(w, err) {
+			return
+		}
+		writeServerError(w, r, "failed to update project", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &updated)
+}
+
+// ListTemplates handles GET /projects/templates requests.
+func (h *ProjectHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	projects, err := h.projects.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list projects", err)
+		return
+	}
+
+	templates := make([]*models.Project, 0)
+	for _, project := range projects {
+		if project.IsTemplate {
+			templates = append(templates, project)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+
+	writeJSON(w, http.StatusOK, templates)
+}
+
+// CloneProjectRequest is the request body for cloning a project.
+type CloneProjectRequest struct {
+	Name             string `json:"name"`
+	IncludeOpenTasks bool   `json:"include_open_tasks,omitempty"`
+}
+
+// CloneProjectResponse is the response body for a cloned project.
+type CloneProjectResponse struct {
+	Project         *models.Project `json:"project"`
+	TemplatesCopied int             `json:"templates_copied"`
+	TasksCopied     int             `json:"tasks_copied"`
+}
+
+// Clone handles POST /projects/{id}/clone requests.
+//
+// The new project copies the source's labels, milestones, views and
+// project-scoped task templates. Open tasks are copied, reset to
+// pending and unassigned, only if include_open_tasks is set. Cloning a
+// template project is how new projects are bootstrapped from it.
+func (h *ProjectHandler) Clone(w http.ResponseWriter, r *http.Request, id models.ProjectID) {
+	var req CloneProjectRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	source, err := h.projects.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			http.Error(w, "project not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get project", err)
+		return
+	}
+
+	clone, err := source.CloneStructure(req.Name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if user, ok := UserFromContext(r.Context()); ok {
+		clone.OwnerID = user.ID
+	}
+	if err := h.projects.Create(r.Context(), clone); err != nil {
+		if writeHookRejection(w, err) {
+			return
+		}
+		writeServerError(w, r, "failed to create project", err)
+		return
+	}
+
+	resp := &CloneProjectResponse{Project: clone}
+
+	templates, err := h.templates.ListForProject(r.Context(), source.ID)
+	if err != nil {
+		writeServerError(w, r, "failed to list templates", err)
+		return
+	}
+	for _, tmpl := range templates {
+		if tmpl.ProjectID != source.ID {
+			continue
+		}
+		copied := *tmpl
+		copied.ID = uuid.New().String()
+		copied.ProjectID = clone.ID
+		copied.Checklist = append([]string(nil), tmpl.Checklist...)
+		copied.Tags = append([]string{}, tmpl.Tags...)
+		copied.CreatedAt = time.Now()
+		if err := h.templates.Create(r.Context(), &copied); err != nil {
+			writeServerError(w, r, "failed to copy templates", err)
+			return
+		}
+		resp.TemplatesCopied++
+	}
+
+	if req.IncludeOpenTasks {
+		tasks, err := h.tasks.GetAll(r.Context())
+		if err != nil {
+			writeServerError(w, r, "failed to list tasks", err)
+			return
+		}
+		for _, task := range tasks {
+			if task.ProjectID != source.ID || !task.IsOpen() {
+				continue
+			}
+			copied := task.Duplicate(models.DuplicateOptions{
+				ProjectID:        clone.ID,
+				IncludeChecklist: true,
+				IncludeTags:      true,
+			})
+			if err := h.tasks.Create(r.Context(), copied); err != nil {
+				writeServerError(w, r, "failed to copy tasks", err)
+				return
+			}
+			resp.TasksCopied++
+		}
+	}
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// ProjectStats summarizes the tasks in a project.
+//
+// MeanCycleTimeHours is the mean time from creation to completion of
+// completed tasks, counting only working hours if the project has a
+// calendar.
+type ProjectStats struct {
+	ProjectID          models.ProjectID            `json:"project_id"`
+	AsOf               time.Time                   `json:"as_of"`
+	Total              int                         `json:"total"`
+	ByStatus           map[models.TaskStatus]int   `json:"by_status"`
+	ByPriority         map[models.TaskPriority]int `json:"by_priority"`
+	Overdue            int                         `json:"overdue"`
+	CompletionRate     float64                     `json:"completion_rate"`
+	MeanCycleTimeHours float64                     `json:"mean_cycle_time_hours"`
+}
+
+// computeProjectStats summarizes the project's tasks as of the given time.
+//
+// calendar may be nil, in which case cycle times use elapsed wall time.
+func computeProjectStats(projectID models.ProjectID, tasks []*models.Task, at time.Time, calendar *models.BusinessCalendar) *ProjectStats {
+	stats := &ProjectStats{
+		ProjectID:  projectID,
+		AsOf:       at,
+		ByStatus:   make(map[models.TaskStatus]int),
+		ByPriority: make(map[models.TaskPriority]int),
+	}
+
+	var cycleTotal time.Duration
+	for _, task := range tasks {
+		if task.ProjectID != projectID {
+			continue
+		}
+		if task.Status == models.TaskStatusCompleted {
+			completedAt := task.StatusSince()
+			if calendar != nil {
+				cycleTotal += calendar.WorkingTime(task.CreatedAt, completedAt)
+			} else {
+				cycleTotal += completedAt.Sub(task.CreatedAt)
+			}
+		}
+		stats.Total++
+		stats.ByStatus[task.Status]++
+		stats.ByPriority[task.Priority]++
+		if task.IsOverdueAt(at) {
+			stats.Overdue++
+		}
+	}
+
+	if stats.Total > 0 {
+		stats.CompletionRate = float64(stats.ByStatus[models.TaskStatusCompleted]) / float64(stats.Total)
+	}
+	if completed := stats.ByStatus[models.TaskStatusCompleted]; completed > 0 {
+		stats.MeanCycleTimeHours = cycleTotal.Hours() / float64(completed)
+	}
+	return stats
+}
+
+// Stats handles GET /projects/{id}/stats requests.
+//
+// An optional as_of query parameter (RFC 3339) computes the stats as
+// they were at that time, if the store keeps history.
+func (h *ProjectHandler) Stats(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	at := time.Now()
+	var tasks []*models.Task
+	var err error
+	if raw := r.URL.Query().Get("as_of"); raw != "" {
+		at, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			http.Error(w, "as_of must be an RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		temporal, ok := h.tasks.(TemporalTaskStore)
+		if !ok {
+			http.Error(w, "as_of is not supported by this store", http.StatusNotImplemented)
+			return
+		}
+		tasks, err = temporal.GetAllAsOf(r.Context(), at)
+	} else {
+		tasks, err = h.tasks.GetAll(r.Context())
+	}
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+
+	calendar, err := projectCalendar(r.Context(), h.projects, projectID)
+	if err != nil {
+		writeServerError(w, r, "failed to get project calendar", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, computeProjectStats(projectID, tasks, at, calendar))
+}
@@ -0,0 +1,444 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// ProjectSettingsStore defines the interface for per-project settings
+// storage.
+type ProjectSettingsStore interface {
+	// Get retrieves the settings of a project.
+	Get(ctx context.Context, projectID models.ProjectID) (*models.ProjectSettings, error)
+	// GetAll retrieves the settings of every project that has some.
+	GetAll(ctx context.Context) ([]*models.ProjectSettings, error)
+	// Put sets a project's settings, replacing any existing ones.
+	Put(ctx context.Context, settings *models.ProjectSettings) error
+	// Delete removes a project's settings.
+	Delete(ctx context.Context, projectID models.ProjectID) error
+}
+
+// ErrProjectSettingsNotFound is returned when a project has no settings.
+var ErrProjectSettingsNotFound = errors.New("project settings not found")
+
+// InMemoryProjectSettingsStore is an in-memory implementation of
+// ProjectSettingsStore.
+type InMemoryProjectSettingsStore struct {
+	mu       sync.RWMutex
+	settings map[models.ProjectID]*models.ProjectSettings
+}
+
+// NewInMemoryProjectSettingsStore creates a new in-memory project
+// settings store.
+func NewInMemoryProjectSettingsStore() *InMemoryProjectSettingsStore {
+	return &InMemoryProjectSettingsStore{
+		settings: make(map[models.ProjectID]*models.ProjectSettings),
+	}
+}
+
+// Get retrieves the settings of a project.
+func (s *InMemoryProjectSettingsStore) Get(ctx context.Context, projectID models.ProjectID) (*models.ProjectSettings, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	settings, ok := s.settings[projectID]
+	if !ok {
+		return nil, ErrProjectSettingsNotFound
+	}
+	return settings, nil
+}
+
+// GetAll retrieves the settings of every project that has some.
+func (s *InMemoryProjectSettingsStore) GetAll(ctx context.Context) ([]*models.ProjectSettings, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	all := make([]*models.ProjectSettings, 0, len(s.settings))
+	for _, settings := range s.settings {
+		all = append(all, settings)
+	}
+	return all, nil
+}
+
+// Put sets a project's settings, replacing any existing ones.
+func (s *InMemoryProjectSettingsStore) Put(ctx context.Context, settings *models.ProjectSettings) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.settings[settings.ProjectID] = settings
+	return nil
+}
+
+// Delete removes a project's settings.
+func (s *InMemoryProjectSettingsStore) Delete(ctx context.Context, projectID models.ProjectID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.settings[projectID]; !ok {
+		return ErrProjectSettingsNotFound
+	}
+	delete(s.settings, projectID)
+	return nil
+}
+
+// projectSettings returns a project's settings, or the default settings
+// if it has none.
+func projectSettings(ctx context.Context, settings ProjectSettingsStore, projectID models.ProjectID) (*models.ProjectSettings, error) {
+	s, err := settings.Get(ctx, projectID)
+	if errors.Is(err, ErrProjectSettingsNotFound) {
+		return models.DefaultProjectSettings(projectID), nil
+	}
+	return s, err
+}
+
+// notificationRuleEvents are the events a notification rule may name.
+var notificationRuleEvents = map[EventType]bool{
+	EventTaskCreated:  true,
+	EventTaskUpdated:  true,
+	EventTaskApproved: true,
+	EventTaskRejected: true,
+	EventSLABreached:  true,
+}
+
+// SettingsWorkflowStore is a WorkflowStore decorator that resolves each
+// project's workflow through its settings: a project with a WorkflowID
+// gets that project's workflow, and WIP limits in its settings replace
+// the workflow's. Put and Delete write the project's own workflow.
+//
+// Wrap the store given to NewWorkflowTaskStore and the board with it so
+// both enforce the effective workflow.
+type SettingsWorkflowStore struct {
+	WorkflowStore
+	settings ProjectSettingsStore
+}
+
+// NewSettingsWorkflowStore wraps a workflow store with project settings.
+func NewSettingsWorkflowStore(next WorkflowStore, settings ProjectSettingsStore) *SettingsWorkflowStore {
+	return &SettingsWorkflowStore{WorkflowStore: next, settings: settings}
+}
+
+// Get retrieves the effective workflow of a project. It returns
+// ErrWorkflowNotFound only if the project uses the default workflow
+// unchanged.
+func (s *SettingsWorkflowStore) Get(ctx context.Context, projectID models.ProjectID) (*models.Workflow, error) {
+	settings, err := projectSettings(ctx, s.settings, projectID)
+	if err != nil {
+		return nil, err
+	}
+	source := projectID
+	if settings.WorkflowID != "" {
+		source = settings.WorkflowID
+	}
+	workflow, err := s.WorkflowStore.Get(ctx, source)
+	if errors.Is(err, ErrWorkflowNotFound) {
+		if len(settings.WIPLimits) == 0 {
+			return nil, err
+		}
+		workflow, err = models.DefaultWorkflow(projectID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if source == projectID && len(settings.WIPLimits) == 0 {
+		return workflow, nil
+	}
+	return settings.Apply(workflow), nil
+}
+
+// ProjectSettingsHandler handles HTTP requests for project settings.
+type ProjectSettingsHandler struct {
+	settings   ProjectSettingsStore
+	projects   ProjectStore
+	workflows  WorkflowStore
+	priorities PrioritySchemeStore
+	users      UserStore
+}
+
+// NewProjectSettingsHandler creates a new project settings handler.
+//
+// workflows should be the project's own workflows, not a
+// SettingsWorkflowStore. priorities and users may be nil, in which case
+// the default priority is checked against the default scheme and user
+// IDs are not checked.
+func NewProjectSettingsHandler(settings ProjectSettingsStore, projects ProjectStore, workflows WorkflowStore, priorities PrioritySchemeStore, users UserStore) *ProjectSettingsHandler {
+	return &ProjectSettingsHandler{
+		settings:   settings,
+		projects:   projects,
+		workflows:  workflows,
+		priorities: priorities,
+		users:      users,
+	}
+}
+
+// ProjectSettingsResponse is the response body for a project's settings.
+// Custom is false when the project uses the defaults.
+type ProjectSettingsResponse struct {
+	*models.ProjectSettings
+	Custom bool `json:"custom"`
+}
+
+// Get handles GET /projects/{id}/settings requests.
+func (h *ProjectSettingsHandler) Get(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	if !h.projectExists(w, r, projectID) {
+		return
+	}
+	settings, err := h.settings.Get(r.Context(), projectID)
+	if err != nil {
+		if errors.Is(err, ErrProjectSettingsNotFound) {
+			writeJSON(w, http.StatusOK, &ProjectSettingsResponse{ProjectSettings: models.DefaultProjectSettings(projectID)})
+			return
+		}
+		writeServerError(w, r, "failed to get project settings", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &ProjectSettingsResponse{ProjectSettings: settings, Custom: true})
+}
+
+// Put handles PUT /projects/{id}/settings requests, replacing the
+// project's settings.
+//
+// Besides the document's own validation, the default assignee and
+// recipients must be known users, the default priority must be defined
+// by the project's priority scheme, the workflow project must exist,
+// WIP limits must name statuses of the workflow in use, and rules must
+// name known events. Unknown fields are rejected.
+func (h *ProjectSettingsHandler) Put(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var settings models.ProjectSettings
+	if err := decodeJSON(w, r, &settings); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if !h.projectExists(w, r, projectID) {
+		return
+	}
+	settings.ProjectID = projectID
+	settings.UpdatedAt = time.Now()
+	if settings.NotificationRules == nil {
+		settings.NotificationRules = make([]models.NotificationRule, 0)
+	}
+	if err := settings.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.check(r.Context(), &settings); err != nil {
+		if errors.Is(err, models.ErrInvalidProjectSettings) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeServerError(w, r, "failed to check project settings", err)
+		return
+	}
+
+	if err := h.settings.Put(r.Context(), &settings); err != nil {
+		writeServerError(w, r, "failed to save project settings", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &ProjectSettingsResponse{ProjectSettings: &settings, Custom: true})
+}
+
+// Delete handles DELETE /projects/{id}/settings requests, returning the
+// project to the defaults.
+func (h *ProjectSettingsHandler) Delete(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	if err := h.settings.Delete(r.Context(), projectID); err != nil {
+		if errors.Is(err, ErrProjectSettingsNotFound) {
+			http.Error(w, "project has no settings", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to delete project settings", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// projectExists writes 404 and returns false if the project does not
+// exist.
+func (h *ProjectSettingsHandler) projectExists(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) bool {
+	if _, err := h.projects.Get(r.Context(), projectID); err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			http.Error(w, "project not found", http.StatusNotFound)
+			return false
+		}
+		writeServerError(w, r, "failed to get project", err)
+		return false
+	}
+	return true
+}
+
+// check validates settings against the rest of the tree, returning an
+// error wrapping models.ErrInvalidProjectSettings if they refer to
+// something that does not exist.
+func (h *ProjectSettingsHandler) check(ctx context.Context, settings *models.ProjectSettings) error {
+	if settings.DefaultAssigneeID != nil {
+		if err := h.checkUser(ctx, *settings.DefaultAssigneeID); err != nil {
+			return err
+		}
+	}
+
+	if settings.DefaultPriority > 0 {
+		scheme := models.DefaultPriorityScheme(settings.ProjectID)
+		if h.priorities != nil {
+			var err error
+			if scheme, err = projectPriorityScheme(ctx, h.priorities, settings.ProjectID); err != nil {
+				return err
+			}
+		}
+		if !scheme.Has(settings.DefaultPriority) {
+			return fmt.Errorf("%w: %v", models.ErrInvalidProjectSettings, models.ErrUnknownPriority)
+		}
+	}
+
+	source := settings.ProjectID
+	if settings.WorkflowID != "" {
+		source = settings.WorkflowID
+		if _, err := h.projects.Get(ctx, source); err != nil {
+			if errors.Is(err, ErrProjectNotFound) {
+				return fmt.Errorf("%w: workflow project %s not found", models.ErrInvalidProjectSettings, source)
+			}
+			return err
+		}
+	}
+	if len(settings.WIPLimits) > 0 {
+		workflow, err := projectWorkflow(ctx, h.workflows, source)
+		if err != nil {
+			return err
+		}
+		for status := range settings.WIPLimits {
+			if !workflow.HasStatus(status) {
+				return fmt.Errorf("%w: wip limit for %q: %v", models.ErrInvalidProjectSettings, status, models.ErrUnknownStatus)
+			}
+		}
+	}
+
+	for i, rule := range settings.NotificationRules {
+		if !notificationRuleEvents[EventType(rule.Event)] {
+			return fmt.Errorf("%w: notification rule %d: unknown event %q", models.ErrInvalidProjectSettings, i, rule.Event)
+		}
+		for _, id := range rule.Recipients {
+			if err := h.checkUser(ctx, id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkUser returns an error wrapping models.ErrInvalidProjectSettings
+// if the user does not exist.
+func (h *ProjectSettingsHandler) checkUser(ctx context.Context, id models.UserID) error {
+	if h.users == nil {
+		return nil
+	}
+	if _, err := h.users.Get(ctx, id); err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return fmt.Errorf("%w: user %s not found", models.ErrInvalidProjectSettings, id)
+		}
+		return err
+	}
+	return nil
+}
+
+// ProjectRuleNotifier sends the notifications of each project's
+// notification rules as events are published.
+type ProjectRuleNotifier struct {
+	settings      ProjectSettingsStore
+	tasks         TaskStore
+	users         UserStore
+	notifications NotificationStore
+}
+
+// NewProjectRuleNotifier creates a new project rule notifier.
+func NewProjectRuleNotifier(settings ProjectSettingsStore, tasks TaskStore, users UserStore, notifications NotificationStore) *ProjectRuleNotifier {
+	return &ProjectRuleNotifier{settings: settings, tasks: tasks, users: users, notifications: notifications}
+}
+
+// Subscribe runs the notifier on every event published on bus.
+func (n *ProjectRuleNotifier) Subscribe(bus *EventBus) {
+	bus.SubscribeAll(n.Handle)
+}
+
+// Handle notifies the recipients of the rules of the event's project
+// that name its type. The user whose action caused the event is not
+// notified, nor are recipients who may not see the task. Failures are
+// logged, since the event has already happened.
+func (n *ProjectRuleNotifier) Handle(ctx context.Context, event *Event) {
+	if event.TaskID == "" {
+		return
+	}
+	if err := n.handle(ctx, event); err != nil {
+		log.Printf("project settings: %s for task %s: %v", event.Type, event.TaskID, err)
+	}
+}
+
+// handle sends the notifications for one event.
+func (n *ProjectRuleNotifier) handle(ctx context.Context, event *Event) error {
+	task, err := n.tasks.Get(ctx, event.TaskID)
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			return nil
+		}
+		return err
+	}
+	if task.Draft {
+		return nil
+	}
+	settings, err := projectSettings(ctx, n.settings, task.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	var actor models.UserID
+	if user, ok := UserFromContext(ctx); ok {
+		actor = user.ID
+	}
+	notified := map[models.UserID]bool{actor: true}
+	for _, rule := range settings.NotificationRules {
+		if rule.Event != string(event.Type) {
+			continue
+		}
+		recipients := rule.Recipients
+		if rule.NotifyAssignee && task.AssigneeID != nil {
+			recipients = append(recipients[:len(recipients):len(recipients)], *task.AssigneeID)
+		}
+		for _, id := range recipients {
+			if notified[id] {
+				continue
+			}
+			notified[id] = true
+			recipient, err := n.users.Get(ctx, id)
+			if errors.Is(err, ErrUserNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if !task.IsVisibleTo(recipient) {
+				continue
+			}
+			notification := models.NewNotification(id, models.NotificationProjectRule, task.ID, actor)
+			notification.Message = fmt.Sprintf("%s: %s", event.Type, task.Title)
+			if err := n.notifications.Create(ctx, notification); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// findBySlug returns the project a slug names, and whether it is the
+// project's current slug rather than a previous one. It returns
+// ErrProjectNotFound if no project has ever had the slug.
+func findBySlug(ctx context.Context, projects ProjectStore, slug string) (*models.Project, bool, error) {
+	all, err := projects.GetAll(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, project := range all {
+		if project.HasSlug(slug) {
+			return project, project.Slug == slug, nil
+		}
+	}
+	return nil, false, ErrProjectNotFound
+}
+
+// SlugProjectStore is a ProjectStore decorator that keeps project slugs
+// unique, counting previous slugs too so old links never change
+// meaning.
+//
+// Projects created without a slug get one from their name, with a
+// numeric suffix such as "-2" if it is taken. Creating or updating a
+// project with a slug another project has, or had, fails with
+// models.ErrSlugTaken.
+type SlugProjectStore struct {
+	ProjectStore
+	mu sync.Mutex
+}
+
+// NewSlugProjectStore wraps a project store with slug handling.
+func NewSlugProjectStore(next ProjectStore) *SlugProjectStore {
+	return &SlugProjectStore{ProjectStore: next}
+}
+
+// taken reports whether a project other than self has, or had, the slug.
+func (s *SlugProjectStore) taken(ctx context.Context, slug string, self models.ProjectID) (bool, error) {
+	project, _, err := findBySlug(ctx, s.ProjectStore, slug)
+	if errors.Is(err, ErrProjectNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return project.ID != self, nil
+}
+
+// uniqueSlug returns the first free slug of base, base-2, base-3 and so
+// on, shortening base so the slug stays within the length limit.
+func (s *SlugProjectStore) uniqueSlug(ctx context.Context, base string, self models.ProjectID) (string, error) {
+	for n := 1; ; n++ {
+		slug := base
+		if n > 1 {
+			suffix := fmt.Sprintf("-%d", n)
+			slug = strings.TrimRight(base[:min(len(base), models.MaxSlugLength-len(suffix))], "-") + suffix
+		}
+		taken, err := s.taken(ctx, slug, self)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return slug, nil
+		}
+	}
+}
+
+// Create stores a new project, giving it a unique slug if it has none.
+func (s *SlugProjectStore) Create(ctx context.Context, project *models.Project) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if project.Slug == "" {
+		slug, err := s.uniqueSlug(ctx, models.Slugify(project.Name), project.ID)
+		if err != nil {
+			return err
+		}
+		project.Slug = slug
+	} else if err := s.check(ctx, project); err != nil {
+		return err
+	}
+	return s.ProjectStore.Create(ctx, project)
+}
+
+// Update updates a project unless its slug, or one it keeps as
+// previous, belongs to another project.
+func (s *SlugProjectStore) Update(ctx context.Context, project *models.Project) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.check(ctx, project); err != nil {
+		return err
+	}
+	return s.ProjectStore.Update(ctx, project)
+}
+
+// check returns models.ErrSlugTaken if another project has, or had, any
+// of the project's slugs.
+func (s *SlugProjectStore) check(ctx context.Context, project *models.Project) error {
+	for _, slug := range append([]string{project.Slug}, project.PreviousSlugs...) {
+		if slug == "" {
+			continue
+		}
+		taken, err := s.taken(ctx, slug, project.ID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return fmt.Errorf("%w: %s", models.ErrSlugTaken, slug)
+		}
+	}
+	return nil
+}
+
+// BySlug handles GET /projects/by-slug/{slug} requests. A project's
+// previous slugs redirect permanently to its current one.
+func (h *ProjectHandler) BySlug(w http.ResponseWriter, r *http.Request, slug string) {
+	project, current, err := findBySlug(r.Context(), h.projects, slug)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			http.Error(w, "project not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get project", err)
+		return
+	}
+	if !current {
+		http.Redirect(w, r, "/projects/by-slug/"+url.PathEscape(project.Slug), http.StatusMovedPermanently)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, project)
+}
+
+// SetProjectSlugRequest is the request body for changing a project's slug.
+type SetProjectSlugRequest struct {
+	Slug string `json:"slug"`
+}
+
+// SetSlug handles PUT /projects/{id}/slug requests. The old slug keeps
+// redirecting to the project. A slug another project has, or had, is
+// refused with 409.
+func (h *ProjectHandler) SetSlug(w http.ResponseWriter, r *http.Request, id models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req SetProjectSlugRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	project, err := h.projects.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			http.Error(w, "project not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get project", err)
+		return
+	}
+
+	updated := *project
+	changed, err := updated.SetSlug(req.Slug)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if changed {
+		if err := h.projects.Update(r.Context(), &updated); err != nil {
+			if writeHookRejection(w, err) {
+				return
+			}
+			writeServerError(w, r, "failed to update project", err)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, &updated)
+}
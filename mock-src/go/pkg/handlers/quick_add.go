@@ -0,0 +1,107 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// QuickAddRequest is the request body for quick-adding a task.
+//
+// ProjectID is used when the text names no +project shortcut. Unless
+// Confirm is set, the task is only previewed and not created.
+type QuickAddRequest struct {
+	Text           string           `json:"text"`
+	ProjectID      models.ProjectID `json:"project_id,omitempty"`
+	Confirm        bool             `json:"confirm,omitempty"`
+	SkipAutoAssign bool             `json:"skip_auto_assign,omitempty"`
+}
+
+// QuickAddPreview is the response body for an unconfirmed quick-add.
+type QuickAddPreview struct {
+	Parsed *models.QuickAdd `json:"parsed"`
+	Task   *TaskResponse    `json:"task"`
+}
+
+// errUnknownProjectShortcut is returned when a +project shortcut names
+// no project.
+var errUnknownProjectShortcut = errors.New("unknown project shortcut")
+
+// QuickAdd handles POST /tasks/quick requests.
+//
+// The text is parsed with models.ParseQuickAdd, interpreting due dates
+// in the authenticated user's timezone. A +project shortcut matches a
+// project ID or a project name compared case-insensitively with spaces
+// written as dashes.
+func (h *TaskHandler) QuickAdd(w http.ResponseWriter, r *http.Request) {
+	var req QuickAddRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	loc := time.UTC
+	if user, ok := UserFromContext(r.Context()); ok {
+		loc = user.Preferences.Location()
+	}
+	parsed, err := models.ParseQuickAdd(req.Text, time.Now(), loc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	projectID := req.ProjectID
+	if parsed.Project != "" {
+		projectID, err = h.resolveProjectShortcut(r.Context(), parsed.Project)
+		if err != nil {
+			if errors.Is(err, errUnknownProjectShortcut) {
+				http.Error(w, "unknown project +"+parsed.Project, http.StatusBadRequest)
+				return
+			}
+			writeServerError(w, r, "failed to resolve project", err)
+			return
+		}
+	}
+
+	create := &CreateTaskRequest{
+		Title:          parsed.Title,
+		ProjectID:      projectID,
+		Priority:       int(parsed.Priority),
+		DueDate:        parsed.DueDate,
+		Tags:           parsed.Tags,
+		SkipAutoAssign: req.SkipAutoAssign,
+	}
+	task, ok := h.newTask(w, r, create)
+	if !ok {
+		return
+	}
+
+	if !req.Confirm {
+		writeJSON(w, http.StatusOK, &QuickAddPreview{Parsed: parsed, Task: toResponse(task)})
+		return
+	}
+	h.createTask(w, r, task, create)
+}
+
+// resolveProjectShortcut returns the ID of the project a +project
+// shortcut refers to.
+func (h *TaskHandler) resolveProjectShortcut(ctx context.Context, shortcut string) (models.ProjectID, error) {
+	if h.projects == nil {
+		return "", errUnknownProjectShortcut
+	}
+	projects, err := h.projects.GetAll(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range projects {
+		if string(p.ID) == shortcut || strings.EqualFold(strings.ReplaceAll(p.Name, " ", "-"), shortcut) {
+			return p.ID, nil
+		}
+	}
+	return "", errUnknownProjectShortcut
+}
@@ -0,0 +1,330 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// MoveTaskRequest is the request body for moving a task. Exactly one of
+// BeforeID and AfterID must be set.
+type MoveTaskRequest struct {
+	BeforeID models.TaskID `json:"before_id,omitempty"`
+	AfterID  models.TaskID `json:"after_id,omitempty"`
+}
+
+// errRankTargetNotFound is returned when a move refers to a task that
+// is not in the moved task's project.
+var errRankTargetNotFound = errors.New("target task not found in project")
+
+// Move handles POST /tasks/{id}/move requests, placing a task directly
+// before or after another task of the same project.
+//
+// Only the moved task is re-ranked, except that unranked tasks in the
+// project are ranked first, in their current order.
+func (h *TaskHandler) Move(w http.ResponseWriter, r *http.Request, id models.TaskID) {
+	var req MoveTaskRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if (req.BeforeID == "") == (req.AfterID == "") {
+		http.Error(w, "exactly one of before_id and after_id is required", http.StatusBadRequest)
+		return
+	}
+	if req.BeforeID == id || req.AfterID == id {
+		http.Error(w, "a task cannot be moved relative to itself", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.store.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			http.Error(w, "task not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get task", err)
+		return
+	}
+
+	tasks, err := h.rankedProjectTasks(r.Context(), task.ProjectID)
+	if err != nil {
+		writeServerError(w, r, "failed to rank tasks", err)
+		return
+	}
+	rank, err := rankForMove(tasks, id, req)
+	if err != nil {
+		if errors.Is(err, errRankTargetNotFound) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, models.ErrInvalidRankRange) {
+			http.Error(w, "no room to rank the task there; reprioritize the project first", http.StatusConflict)
+			return
+		}
+		writeServerError(w, r, "failed to rank task", err)
+		return
+	}
+
+	h.transition(w, r, id, func(t *models.Task) bool {
+		t.Rank = rank
+		return true
+	})
+}
+
+// rankForMove returns the rank that places the task id directly before
+// or after the target in tasks, which must be ranked and sorted.
+func rankForMove(tasks []*models.Task, id models.TaskID, req MoveTaskRequest) (string, error) {
+	others := make([]*models.Task, 0, len(tasks))
+	for _, t := range tasks {
+		if t.ID != id {
+			others = append(others, t)
+		}
+	}
+
+	targetID := req.BeforeID
+	if targetID == "" {
+		targetID = req.AfterID
+	}
+	at := -1
+	for i, t := range others {
+		if t.ID == targetID {
+			at = i
+			break
+		}
+	}
+	if at < 0 {
+		return "", errRankTargetNotFound
+	}
+
+	var lo, hi string
+	if req.BeforeID != "" {
+		hi = others[at].Rank
+		if at > 0 {
+			lo = others[at-1].Rank
+		}
+	} else {
+		lo = others[at].Rank
+		if at+1 < len(others) {
+			hi = others[at+1].Rank
+		}
+	}
+	return models.RankBetween(lo, hi)
+}
+
+// rankedProjectTasks returns the project's tasks sorted by rank, first
+// ranking any unranked tasks after the ranked ones.
+func (h *TaskHandler) rankedProjectTasks(ctx context.Context, projectID models.ProjectID) ([]*models.Task, error) {
+	all, err := h.store.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]*models.Task, 0)
+	for _, t := range all {
+		if t.ProjectID == projectID {
+			tasks = append(tasks, t)
+		}
+	}
+	models.SortByRank(tasks)
+
+	last := ""
+	for i, t := range tasks {
+		if t.Rank != "" {
+			last = t.Rank
+			continue
+		}
+		rank, err := models.RankBetween(last, "")
+		if err != nil {
+			return nil, err
+		}
+		updated, err := updateTask(ctx, h.store, t.ID, func(t *models.Task) bool {
+			if t.Rank != "" {
+				return false
+			}
+			t.Rank = rank
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		tasks[i] = updated
+		last = updated.Rank
+	}
+	return tasks, nil
+}
+
+// lastRank returns a rank after every task in the project.
+func (h *TaskHandler) lastRank(ctx context.Context, projectID models.ProjectID) (string, error) {
+	tasks, err := h.store.GetAll(ctx)
+	if err != nil {
+		return "", err
+	}
+	last := ""
+	for _, t := range tasks {
+		if t.ProjectID == projectID && t.Rank > last {
+			last = t.Rank
+		}
+	}
+	return models.RankBetween(last, "")
+}
+
+// maxReprioritizeTasks caps the number of tasks in one reprioritization.
+const maxReprioritizeTasks = 1000
+
+// ReprioritizeRequest is the request body for reordering a project.
+//
+// TaskIDs lists tasks in their new order; the project's other tasks keep
+// their relative order after them. Priorities optionally sets new
+// priorities by task ID.
+type ReprioritizeRequest struct {
+	TaskIDs    []models.TaskID                       `json:"task_ids"`
+	Priorities map[models.TaskID]models.TaskPriority `json:"priorities,omitempty"`
+}
+
+// reprioritizeChange is a task's rank and priority before and after a
+// reprioritization.
+type reprioritizeChange struct {
+	id                    models.TaskID
+	oldRank, newRank      string
+	oldPriority, priority models.TaskPriority
+}
+
+// Reprioritize handles POST /projects/{id}/reprioritize requests.
+//
+// Every task in the project is re-ranked. The store has no
+// transactions, so if an update fails the tasks already changed are
+// restored to their previous ranks and priorities before the error is
+// returned.
+func (h *TaskHandler) Reprioritize(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	var req ReprioritizeRequest
+	if err := decodeJSONLimit(w, r, &req, maxBulkRequestBodyBytes); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if len(req.TaskIDs) == 0 {
+		http.Error(w, "task_ids is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.TaskIDs) > maxReprioritizeTasks {
+		http.Error(w, "too many tasks in one reprioritization", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if h.priorities != nil && len(req.Priorities) > 0 {
+		scheme, err := projectPriorityScheme(r.Context(), h.priorities, projectID)
+		if err != nil {
+			writeServerError(w, r, "failed to get priority scheme", err)
+			return
+		}
+		for _, p := range req.Priorities {
+			if !scheme.Has(p) {
+				http.Error(w, models.ErrUnknownPriority.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	all, err := h.store.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+	byID := make(map[models.TaskID]*models.Task)
+	rest := make([]*models.Task, 0)
+	for _, t := range all {
+		if t.ProjectID == projectID {
+			byID[t.ID] = t
+			rest = append(rest, t)
+		}
+	}
+
+	ordered := make([]*models.Task, 0, len(byID))
+	listed := make(map[models.TaskID]bool, len(req.TaskIDs))
+	for _, id := range req.TaskIDs {
+		t, ok := byID[id]
+		if !ok {
+			http.Error(w, "task "+string(id)+" is not in the project", http.StatusBadRequest)
+			return
+		}
+		if listed[id] {
+			http.Error(w, "task "+string(id)+" is listed twice", http.StatusBadRequest)
+			return
+		}
+		listed[id] = true
+		ordered = append(ordered, t)
+	}
+	for id := range req.Priorities {
+		if !listed[id] {
+			http.Error(w, "priorities may only be set for listed tasks", http.StatusBadRequest)
+			return
+		}
+	}
+	models.SortByRank(rest)
+	for _, t := range rest {
+		if !listed[t.ID] {
+			ordered = append(ordered, t)
+		}
+	}
+
+	ranks := models.SpreadRanks(len(ordered))
+	changes := make([]reprioritizeChange, 0, len(ordered))
+	for i, t := range ordered {
+		c := reprioritizeChange{id: t.ID, oldRank: t.Rank, newRank: ranks[i], oldPriority: t.Priority, priority: t.Priority}
+		if p, ok := req.Priorities[t.ID]; ok {
+			c.priority = p
+		}
+		if c.newRank != c.oldRank || c.priority != c.oldPriority {
+			changes = append(changes, c)
+		}
+	}
+
+	updated, err := h.applyReprioritization(r.Context(), changes)
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			http.Error(w, "tasks were modified concurrently", http.StatusConflict)
+			return
+		}
+		writeServerError(w, r, "failed to reprioritize tasks", err)
+		return
+	}
+
+	responses := make([]*TaskResponse, 0, len(ordered))
+	for _, t := range ordered {
+		if u, ok := updated[t.ID]; ok {
+			t = u
+		}
+		responses = append(responses, toResponse(t))
+	}
+	writeJSON(w, http.StatusOK, responses)
+}
+
+// applyReprioritization applies the changes in order, returning the
+// updated tasks by ID. If one fails, the changes already applied are
+// undone.
+func (h *TaskHandler) applyReprioritization(ctx context.Context, changes []reprioritizeChange) (map[models.TaskID]*models.Task, error) {
+	updated := make(map[models.TaskID]*models.Task, len(changes))
+	for i, c := range changes {
+		task, err := updateTask(ctx, h.store, c.id, func(t *models.Task) bool {
+			t.Rank, t.Priority = c.newRank, c.priority
+			return true
+		})
+		if err != nil {
+			for j := i - 1; j >= 0; j-- {
+				undo := changes[j]
+				if _, uerr := updateTask(ctx, h.store, undo.id, func(t *models.Task) bool {
+					t.Rank, t.Priority = undo.oldRank, undo.oldPriority
+					return true
+				}); uerr != nil {
+					log.Printf("reprioritize: restoring task %s: %v", undo.id, uerr)
+				}
+			}
+			return nil, err
+		}
+		updated[c.id] = task
+	}
+	return updated, nil
+}
@@ -0,0 +1,208 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// ReactionStore defines the interface for reaction storage.
+type ReactionStore interface {
+	// ListByTarget retrieves the reactions on a task or comment.
+	ListByTarget(ctx context.Context, targetType models.ReactionTarget, targetID string) ([]*models.Reaction, error)
+	// Add stores a reaction. Adding a reaction the user already made is
+	// a no-op.
+	Add(ctx context.Context, reaction *models.Reaction) error
+	// Remove deletes a user's reaction.
+	//
+	// Returns ErrReactionNotFound if the user had not reacted so.
+	Remove(ctx context.Context, targetType models.ReactionTarget, targetID string, userID models.UserID, emoji string) error
+}
+
+// ErrReactionNotFound is returned when removing a reaction that does not exist.
+var ErrReactionNotFound = errors.New("reaction not found")
+
+// reactionKey identifies a reaction within InMemoryReactionStore.
+type reactionKey struct {
+	targetType models.ReactionTarget
+	targetID   string
+	userID     models.UserID
+	emoji      string
+}
+
+// InMemoryReactionStore is an in-memory implementation of ReactionStore.
+type InMemoryReactionStore struct {
+	mu        sync.RWMutex
+	reactions map[reactionKey]*models.Reaction
+}
+
+// NewInMemoryReactionStore creates a new in-memory reaction store.
+func NewInMemoryReactionStore() *InMemoryReactionStore {
+	return &InMemoryReactionStore{
+		reactions: make(map[reactionKey]*models.Reaction),
+	}
+}
+
+// ListByTarget retrieves the reactions on a task or comment.
+func (s *InMemoryReactionStore) ListByTarget(ctx context.Context, targetType models.ReactionTarget, targetID string) ([]*models.Reaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	reactions := make([]*models.Reaction, 0)
+	for key, reaction := range s.reactions {
+		if key.targetType == targetType && key.targetID == targetID {
+			reactions = append(reactions, reaction)
+		}
+	}
+	return reactions, nil
+}
+
+// Add stores a reaction. Adding a reaction the user already made is a no-op.
+func (s *InMemoryReactionStore) Add(ctx context.Context, reaction *models.Reaction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := reactionKey{reaction.TargetType, reaction.TargetID, reaction.UserID, reaction.Emoji}
+	if _, ok := s.reactions[key]; !ok {
+		s.reactions[key] = reaction
+	}
+	return nil
+}
+
+// Remove deletes a user's reaction.
+func (s *InMemoryReactionStore) Remove(ctx context.Context, targetType models.ReactionTarget, targetID string, userID models.UserID, emoji string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := reactionKey{targetType, targetID, userID, emoji}
+	if _, ok := s.reactions[key]; !ok {
+		return ErrReactionNotFound
+	}
+	delete(s.reactions, key)
+	return nil
+}
+
+// reactionCounts returns the aggregated reactions on a target.
+func reactionCounts(ctx context.Context, reactions ReactionStore, targetType models.ReactionTarget, targetID string) ([]models.ReactionCount, error) {
+	list, err := reactions.ListByTarget(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return models.CountReactions(list), nil
+}
+
+// ReactionHandler handles HTTP requests for reactions on tasks and
+// comments.
+type ReactionHandler struct {
+	reactions ReactionStore
+	tasks     TaskStore
+	comments  CommentStore
+}
+
+// NewReactionHandler creates a new reaction handler.
+func NewReactionHandler(reactions ReactionStore, tasks TaskStore, comments CommentStore) *ReactionHandler {
+	return &ReactionHandler{reactions: reactions, tasks: tasks, comments: comments}
+}
+
+// AddToTask handles PUT /tasks/{id}/reactions/{emoji} requests.
+func (h *ReactionHandler) AddToTask(w http.ResponseWriter, r *http.Request, taskID models.TaskID, emoji string) {
+	if !h.requireTarget(w, r, models.ReactionTargetTask, string(taskID)) {
+		return
+	}
+	h.add(w, r, models.ReactionTargetTask, string(taskID), emoji)
+}
+
+// RemoveFromTask handles DELETE /tasks/{id}/reactions/{emoji} requests.
+func (h *ReactionHandler) RemoveFromTask(w http.ResponseWriter, r *http.Request, taskID models.TaskID, emoji string) {
+	h.remove(w, r, models.ReactionTargetTask, string(taskID), emoji)
+}
+
+// AddToComment handles PUT /comments/{id}/reactions/{emoji} requests.
+func (h *ReactionHandler) AddToComment(w http.ResponseWriter, r *http.Request, commentID, emoji string) {
+	if !h.requireTarget(w, r, models.ReactionTargetComment, commentID) {
+		return
+	}
+	h.add(w, r, models.ReactionTargetComment, commentID, emoji)
+}
+
+// RemoveFromComment handles DELETE /comments/{id}/reactions/{emoji} requests.
+func (h *ReactionHandler) RemoveFromComment(w http.ResponseWriter, r *http.Request, commentID, emoji string) {
+	h.remove(w, r, models.ReactionTargetComment, commentID, emoji)
+}
+
+// requireTarget writes a 404 and returns false if the task or comment
+// does not exist.
+func (h *ReactionHandler) requireTarget(w http.ResponseWriter, r *http.Request, targetType models.ReactionTarget, targetID string) bool {
+	var err error
+	if targetType == models.ReactionTargetTask {
+		_, err = h.tasks.Get(r.Context(), models.TaskID(targetID))
+	} else {
+		_, err = h.comments.Get(r.Context(), targetID)
+	}
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, ErrTaskNotFound), errors.Is(err, ErrCommentNotFound):
+		http.Error(w, string(targetType)+" not found", http.StatusNotFound)
+	default:
+		http.Error(w, "failed to get "+string(targetType), http.StatusInternalServerError)
+	}
+	return false
+}
+
+// add records the authenticated user's reaction and writes the updated
+// counts.
+func (h *ReactionHandler) add(w http.ResponseWriter, r *http.Request, targetType models.ReactionTarget, targetID, emoji string) {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	reaction, err := models.NewReaction(targetType, targetID, user.ID, emoji)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.reactions.Add(r.Context(), reaction); err != nil {
+		writeServerError(w, r, "failed to add reaction", err)
+		return
+	}
+
+	h.writeCounts(w, r, targetType, targetID)
+}
+
+// remove deletes the authenticated user's reaction and writes the
+// updated counts.
+func (h *ReactionHandler) remove(w http.ResponseWriter, r *http.Request, targetType models.ReactionTarget, targetID, emoji string) {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.reactions.Remove(r.Context(), targetType, targetID, user.ID, emoji); err != nil {
+		if errors.Is(err, ErrReactionNotFound) {
+			http.Error(w, "reaction not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to remove reaction", err)
+		return
+	}
+
+	h.writeCounts(w, r, targetType, targetID)
+}
+
+// writeCounts writes the aggregated reactions on a target.
+func (h *ReactionHandler) writeCounts(w http.ResponseWriter, r *http.Request, targetType models.ReactionTarget, targetID string) {
+	counts, err := reactionCounts(r.Context(), h.reactions, targetType, targetID)
+	if err != nil {
+		writeServerError(w, r, "failed to list reactions", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, counts)
+}
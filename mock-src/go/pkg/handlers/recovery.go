@@ -0,0 +1,114 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+// ErrorResponse is the response body for an unexpected server error.
+//
+// ErrorID identifies the logged failure, so a client can quote it in a
+// bug report.
+type ErrorResponse struct {
+	Error   string `json:"error"`
+	ErrorID string `json:"error_id"`
+}
+
+// Recoverer converts panics in downstream handlers into 500 responses.
+//
+// Each panic is logged with its stack under a fresh error ID, which is
+// also returned to the client in the response body and the X-Error-ID
+// header, counted, and passed to the error reporter, if any.
+type Recoverer struct {
+	logger   *log.Logger
+	reporter ErrorReporter
+	panics   atomic.Int64
+}
+
+// NewRecoverer creates a recoverer logging to logger, or to the
+// standard logger if logger is nil. reporter may be nil.
+func NewRecoverer(logger *log.Logger, reporter ErrorReporter) *Recoverer {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &Recoverer{logger: logger, reporter: reporter}
+}
+
+// Panics returns the number of panics recovered so far.
+func (rc *Recoverer) Panics() int64 {
+	return rc.panics.Load()
+}
+
+// Middleware recovers panics raised while serving a request.
+//
+// http.ErrAbortHandler is re-raised so net/http can abort the response
+// as intended. If the handler had already started its response, the
+// panic is logged but no error body can be sent.
+func (rc *Recoverer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tw := &trackingWriter{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			id := uuid.New().String()
+			stack := debug.Stack()
+			rc.panics.Add(1)
+			rc.logger.Printf("panic %s serving %s %s: %v\n%s", id, r.Method, r.URL.Path, v, stack)
+			if rc.reporter != nil {
+				err, ok := v.(error)
+				if !ok {
+					err = fmt.Errorf("%v", v)
+				}
+				report := newErrorReport(r, id, err)
+				report.Panic = true
+				report.Stack = stack
+				rc.reporter.Report(r.Context(), report)
+			}
+
+			if tw.wroteHeader {
+				return
+			}
+			w.Header().Set("X-Error-ID", id)
+			writeJSON(w, http.StatusInternalServerError, &ErrorResponse{
+				Error:   "internal server error",
+				ErrorID: id,
+			})
+		}()
+		next.ServeHTTP(tw, r)
+	})
+}
+
+// trackingWriter records whether a response has been started.
+type trackingWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+// WriteHeader records that the response has started.
+func (w *trackingWriter) WriteHeader(status int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write records that the response has started.
+func (w *trackingWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (w *trackingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,134 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+const (
+	// defaultRelatedLimit caps related tasks when no limit is given.
+	defaultRelatedLimit = 10
+	// maxRelatedLimit is the largest limit a client may request.
+	maxRelatedLimit = 50
+)
+
+// Related handles GET /tasks/{id}/related?limit= requests, surfacing
+// tasks that are likely related to, or duplicates of, the task, best
+// first.
+//
+// Tasks are scored by shared tags, similar title and description, and
+// shared dependencies: dependency links of the task, in either
+// direction, to tasks they are linked with too. Tasks already linked to
+// the task by a relation are left out, as are those scoring below
+// models.MinRelatedScore. Closed tasks are included, as prior work.
+// Dependencies the caller may not see are neither counted nor listed.
+func (h *RelationHandler) Related(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	limit := defaultRelatedLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > maxRelatedLimit {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	task, err := h.tasks.Get(r.Context(), taskID)
+	if err == nil && !visibleTo(r.Context(), task) {
+		err = ErrTaskNotFound
+	}
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			http.Error(w, "task not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get task", err)
+		return
+	}
+
+	tasks, err := h.tasks.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+	tasks = visibleTasks(r.Context(), tasks)
+	visible := make(map[models.TaskID]bool, len(tasks))
+	for _, t := range tasks {
+		visible[t.ID] = true
+	}
+	linked, dependencies, shared, err := h.dependencyOverlap(r.Context(), task.ID, visible)
+	if err != nil {
+		writeServerError(w, r, "failed to list relations", err)
+		return
+	}
+
+	scorer := models.NewRelatedScorer(task)
+	related := make([]*models.RelatedTask, 0)
+	for _, other := range tasks {
+		if other.ID == task.ID || linked[other.ID] {
+			continue
+		}
+		if match := scorer.Score(other, dependencies, shared[other.ID]); match.Score >= models.MinRelatedScore {
+			related = append(related, match)
+		}
+	}
+	sort.Slice(related, func(i, j int) bool {
+		if related[i].Score != related[j].Score {
+			return related[i].Score > related[j].Score
+		}
+		return related[i].TaskID < related[j].TaskID
+	})
+
+	writeJSON(w, http.StatusOK, related[:min(limit, len(related))])
+}
+
+// dependencyOverlap returns the tasks linked to a task by any relation,
+// the number of visible tasks it is linked with by dependencies, and for
+// each other task, those of them it is linked with by dependencies too.
+func (h *RelationHandler) dependencyOverlap(ctx context.Context, taskID models.TaskID, visible map[models.TaskID]bool) (map[models.TaskID]bool, int, map[models.TaskID][]models.TaskID, error) {
+	relations, err := h.relations.ListByTask(ctx, taskID)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	linked := make(map[models.TaskID]bool)
+	var dependencies []models.TaskID
+	for _, rel := range relations {
+		other := otherTask(rel, taskID)
+		if !linked[other] && rel.Type == models.RelationDependsOn && visible[other] {
+			dependencies = append(dependencies, other)
+		}
+		linked[other] = true
+	}
+
+	shared := make(map[models.TaskID][]models.TaskID)
+	for _, dep := range dependencies {
+		relations, err := h.relations.ListByTask(ctx, dep)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		seen := make(map[models.TaskID]bool)
+		for _, rel := range relations {
+			other := otherTask(rel, dep)
+			if rel.Type != models.RelationDependsOn || other == taskID || seen[other] {
+				continue
+			}
+			seen[other] = true
+			shared[other] = append(shared[other], dep)
+		}
+	}
+	return linked, len(dependencies), shared, nil
+}
+
+// otherTask returns the task a relation links to taskID.
+func otherTask(rel *models.TaskRelation, taskID models.TaskID) models.TaskID {
+	if rel.SourceID == taskID {
+		return rel.TargetID
+	}
+	return rel.SourceID
+}
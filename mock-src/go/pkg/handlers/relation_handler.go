@@ -0,0 +1,471 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// RelationStore defines the interface for task relation storage.
+type RelationStore interface {
+	// Get retrieves a relation by ID.
+	Get(ctx context.Context, id string) (*models.TaskRelation, error)
+	// ListByTask retrieves the relations in which a task is source or target.
+	ListByTask(ctx context.Context, taskID models.TaskID) ([]*models.TaskRelation, error)
+	// Create stores a new relation.
+	//
+	// Returns ErrRelationExists if the same link is already stored.
+	Create(ctx context.Context, relation *models.TaskRelation) error
+	// Delete removes a relation by ID.
+	Delete(ctx context.Context, id string) error
+}
+
+// ErrRelationNotFound is returned when a relation is not found.
+var ErrRelationNotFound = errors.New("relation not found")
+
+// ErrRelationExists is returned when creating a relation that already exists.
+var ErrRelationExists = errors.New("relation already exists")
+
+// InMemoryRelationStore is an in-memory implementation of RelationStore.
+type InMemoryRelationStore struct {
+	mu        sync.RWMutex
+	relations map[string]*models.TaskRelation
+}
+
+// NewInMemoryRelationStore creates a new in-memory relation store.
+func NewInMemoryRelationStore() *InMemoryRelationStore {
+	return &InMemoryRelationStore{
+		relations: make(map[string]*models.TaskRelation),
+	}
+}
+
+// Get retrieves a relation by ID.
+func (s *InMemoryRelationStore) Get(ctx context.Context, id string) (*models.TaskRelation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	relation, ok := s.relations[id]
+	if !ok {
+		return nil, ErrRelationNotFound
+	}
+	return relation, nil
+}
+
+// ListByTask retrieves the relations in which a task is source or target, oldest first.
+func (s *InMemoryRelationStore) ListByTask(ctx context.Context, taskID models.TaskID) ([]*models.TaskRelation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	relations := make([]*models.TaskRelation, 0)
+	for _, relation := range s.relations {
+		if relation.Involves(taskID) {
+			relations = append(relations, relation)
+		}
+	}
+	sort.Slice(relations, func(i, j int) bool {
+		return relations[i].CreatedAt.Before(relations[j].CreatedAt)
+	})
+	return relations, nil
+}
+
+// Create stores a new relation.
+func (s *InMemoryRelationStore) Create(ctx context.Context, relation *models.TaskRelation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.relations {
+		if existing.SameLink(relation) {
+			return ErrRelationExists
+		}
+	}
+	s.relations[relation.ID] = relation
+	return nil
+}
+
+// Delete removes a relation by ID.
+func (s *InMemoryRelationStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.relations[id]; !ok {
+		return ErrRelationNotFound
+	}
+	delete(s.relations, id)
+	return nil
+}
+
+// DuplicateClosingTaskStore is a TaskStore decorator that propagates
+// closure to duplicates: when a task is completed or cancelled, every
+// open task recorded as its duplicate is closed with the same status.
+type DuplicateClosingTaskStore struct {
+	next      TaskStore
+	relations RelationStore
+}
+
+// NewDuplicateClosingTaskStore wraps a task store with duplicate closure propagation.
+func NewDuplicateClosingTaskStore(next TaskStore, relations RelationStore) *DuplicateClosingTaskStore {
+	return &DuplicateClosingTaskStore{next: next, relations: relations}
+}
+
+// Get retrieves a task by ID.
+func (s *DuplicateClosingTaskStore) Get(ctx context.Context, id models.TaskID) (*models.Task, error) {
+	return s.next.Get(ctx, id)
+}
+
+// GetAll retrieves all tasks.
+func (s *DuplicateClosingTaskStore) GetAll(ctx context.Context) ([]*models.Task, error) {
+	return s.next.GetAll(ctx)
+}
+
+// Create stores a new task.
+func (s *DuplicateClosingTaskStore) Create(ctx context.Context, task *models.Task) error {
+	return s.next.Create(ctx, task)
+}
+
+// Update updates an existing task, closing its open duplicates if the
+// task is closed.
+func (s *DuplicateClosingTaskStore) Update(ctx context.Context, task *models.Task) error {
+	if err := s.next.Update(ctx, task); err != nil {
+		return err
+	}
+	if !task.IsOpen() {
+		return s.closeDuplicates(ctx, task, make(map[models.TaskID]bool))
+	}
+	return nil
+}
+
+// Delete removes a task by ID.
+func (s *DuplicateClosingTaskStore) Delete(ctx context.Context, id models.TaskID) error {
+	return s.next.Delete(ctx, id)
+}
+
+// closeDuplicates closes the open duplicates of original, and their
+// duplicates in turn. seen guards against cycles.
+func (s *DuplicateClosingTaskStore) closeDuplicates(ctx context.Context, original *models.Task, seen map[models.TaskID]bool) error {
+	seen[original.ID] = true
+
+	relations, err := s.relations.ListByTask(ctx, original.ID)
+	if err != nil {
+		return err
+	}
+	for _, relation := range relations {
+		if relation.Type != models.RelationDuplicateOf || relation.TargetID != original.ID || seen[relation.SourceID] {
+			continue
+		}
+		duplicate, err := s.next.Get(ctx, relation.SourceID)
+		if err != nil {
+			if errors.Is(err, ErrTaskNotFound) {
+				continue
+			}
+			return err
+		}
+		if !duplicate.IsOpen() {
+			continue
+		}
+
+		closed := duplicate.Clone()
+		closed.Status = original.Status
+		closed.UpdatedAt = original.UpdatedAt
+		closed.StatusChangedAt = original.UpdatedAt
+		if err := s.next.Update(ctx, closed); err != nil {
+			return err
+		}
+		if err := s.closeDuplicates(ctx, closed, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RelationHandler handles HTTP requests for task relations.
+type RelationHandler struct {
+	relations RelationStore
+	tasks     TaskStore
+}
+
+// NewRelationHandler creates a new relation handler.
+func NewRelationHandler(relations RelationStore, tasks TaskStore) *RelationHandler {
+	return &RelationHandler{relations: relations, tasks: tasks}
+}
+
+// CreateRelationRequest is the request body for creating a relation from a task.
+type CreateRelationRequest struct {
+	Type     models.RelationType `json:"type"`
+	TargetID models.TaskID       `json:"target_id"`
+}
+
+// List handles GET /tasks/{id}/relations requests.
+func (h *RelationHandler) List(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	relations, err := h.relations.ListByTask(r.Context(), taskID)
+	if err != nil {
+		writeServerError(w, r, "failed to list relations", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, relations)
+}
+
+// Create handles POST /tasks/{id}/relations requests.
+func (h *RelationHandler) Create(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	var req CreateRelationRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	relation, err := models.NewTaskRelation(req.Type, taskID, req.TargetID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if user, ok := UserFromContext(r.Context()); ok {
+		relation.CreatedBy = user.ID
+	}
+
+	for _, id := range []models.TaskID{taskID, req.TargetID} {
+		if _, err := h.tasks.Get(r.Context(), id); err != nil {
+			if errors.Is(err, ErrTaskNotFound) {
+				http.Error(w, "task not found: "+string(id), http.StatusNotFound)
+				return
+			}
+			writeServerError(w, r, "failed to get task", err)
+			return
+		}
+	}
+
+	if relation.Type == models.RelationDependsOn {
+		cyclic, err := h.dependsOn(r.Context(), req.TargetID, taskID)
+		if err != nil {
+			writeServerError(w, r, "failed to check dependencies", err)
+			return
+		}
+		if cyclic {
+			http.Error(w, models.ErrDependencyCycle.Error(), http.StatusConflict)
+			return
+		}
+	}
+
+	if err := h.relations.Create(r.Context(), relation); err != nil {
+		if errors.Is(err, ErrRelationExists) {
+			http.Error(w, "relation already exists", http.StatusConflict)
+			return
+		}
+		writeServerError(w, r, "failed to create relation", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, relation)
+}
+
+// Delete handles DELETE /tasks/{id}/relations/{relationID} requests.
+func (h *RelationHandler) Delete(w http.ResponseWriter, r *http.Request, taskID models.TaskID, relationID string) {
+	relation, err := h.relations.Get(r.Context(), relationID)
+	if err != nil || !relation.Involves(taskID) {
+		if err == nil || errors.Is(err, ErrRelationNotFound) {
+			http.Error(w, "relation not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "failed to get relation", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.relations.Delete(r.Context(), relationID); err != nil {
+		if errors.Is(err, ErrRelationNotFound) {
+			http.Error(w, "relation not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to delete relation", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// dependsOn reports whether task from depends, directly or transitively,
+// on task to.
+func (h *RelationHandler) dependsOn(ctx context.Context, from, to models.TaskID) (bool, error) {
+	visited := map[models.TaskID]bool{from: true}
+	stack := []models.TaskID{from}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == to {
+			return true, nil
+		}
+
+		relations, err := h.relations.ListByTask(ctx, id)
+		if err != nil {
+			return false, err
+		}
+		for _, rel := range relations {
+			if rel.Type == models.RelationDependsOn && rel.SourceID == id && !visited[rel.TargetID] {
+				visited[rel.TargetID] = true
+				stack = append(stack, rel.TargetID)
+			}
+		}
+	}
+	return false, nil
+}
+
+// Graph handles GET /projects/{id}/graph requests.
+//
+// The project's dependency DAG is returned as JSON, or in Graphviz DOT
+// format with ?format=dot or an Accept header of text/vnd.graphviz.
+func (h *RelationHandler) Graph(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	format := r.URL.Query().Get("format")
+	if format == "" && strings.Contains(r.Header.Get("Accept"), "text/vnd.graphviz") {
+		format = "dot"
+	}
+	if format != "" && format != "json" && format != "dot" {
+		http.Error(w, "format must be json or dot", http.StatusBadRequest)
+		return
+	}
+
+	_, graph, err := h.projectGraph(r.Context(), projectID)
+	if err != nil {
+		writeGraphError(w, err)
+		return
+	}
+
+	if format == "dot" {
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, graph.DOT())
+		return
+	}
+	writeJSON(w, http.StatusOK, graph)
+}
+
+// projectGraph loads a project's tasks and builds their dependency graph.
+func (h *RelationHandler) projectGraph(ctx context.Context, projectID models.ProjectID) ([]*models.Task, *models.DependencyGraph, error) {
+	all, err := h.tasks.GetAll(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tasks := make([]*models.Task, 0)
+	relations := make([]*models.TaskRelation, 0)
+	for _, task := range all {
+		if task.ProjectID != projectID {
+			continue
+		}
+		tasks = append(tasks, task)
+		rels, err := h.relations.ListByTask(ctx, task.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		relations = append(relations, rels...)
+	}
+
+	graph, err := models.BuildDependencyGraph(tasks, relations)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tasks, graph, nil
+}
+
+// writeGraphError writes the response for a failure to build a dependency graph.
+func writeGraphError(w http.ResponseWriter, err error) {
+	if errors.Is(err, models.ErrDependencyCycle) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	http.Error(w, "failed to build dependency graph", http.StatusInternalServerError)
+}
+
+// TimelineBar is a scheduled task in a project timeline.
+//
+// Start is the task's start date, or its creation time if it has none.
+// End is the due date and is nil for unscheduled tasks. Dependencies
+// lists the tasks that must finish first.
+type TimelineBar struct {
+	TaskID       models.TaskID     `json:"task_id"`
+	Title        string            `json:"title"`
+	Status       models.TaskStatus `json:"status"`
+	AssigneeID   *models.UserID    `json:"assignee_id,omitempty"`
+	Start        time.Time         `json:"start"`
+	End          *time.Time        `json:"end,omitempty"`
+	Progress     float64           `json:"progress"`
+	Dependencies []models.TaskID   `json:"dependencies"`
+	Critical     bool              `json:"critical"`
+}
+
+// Timeline is the Gantt data for a project.
+type Timeline struct {
+	ProjectID    models.ProjectID `json:"project_id"`
+	Start        *time.Time       `json:"start,omitempty"`
+	End          *time.Time       `json:"end,omitempty"`
+	Bars         []*TimelineBar   `json:"bars"`
+	CriticalPath []models.TaskID  `json:"critical_path"`
+}
+
+// Timeline handles GET /projects/{id}/timeline requests.
+//
+// Bars are ordered by start, then title.
+func (h *RelationHandler) Timeline(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	tasks, graph, err := h.projectGraph(r.Context(), projectID)
+	if err != nil {
+		writeGraphError(w, err)
+		return
+	}
+
+	critical := make(map[models.TaskID]bool, len(graph.Nodes))
+	for _, n := range graph.Nodes {
+		critical[n.ID] = n.Critical
+	}
+	deps := make(map[models.TaskID][]models.TaskID)
+	for _, e := range graph.Edges {
+		deps[e.To] = append(deps[e.To], e.From)
+	}
+
+	timeline := &Timeline{
+		ProjectID:    projectID,
+		Bars:         make([]*TimelineBar, 0, len(tasks)),
+		CriticalPath: graph.CriticalPath,
+	}
+	for _, task := range tasks {
+		bar := &TimelineBar{
+			TaskID:       task.ID,
+			Title:        task.Title,
+			Status:       task.Status,
+			AssigneeID:   task.AssigneeID,
+			Start:        task.CreatedAt,
+			End:          task.DueDate,
+			Progress:     task.Progress(),
+			Dependencies: deps[task.ID],
+			Critical:     critical[task.ID],
+		}
+		if task.StartDate != nil {
+			bar.Start = *task.StartDate
+		}
+		if bar.Dependencies == nil {
+			bar.Dependencies = []models.TaskID{}
+		}
+		if timeline.Start == nil || bar.Start.Before(*timeline.Start) {
+			start := bar.Start
+			timeline.Start = &start
+		}
+		if bar.End != nil && (timeline.End == nil || bar.End.After(*timeline.End)) {
+			end := *bar.End
+			timeline.End = &end
+		}
+		timeline.Bars = append(timeline.Bars, bar)
+	}
+	sort.Slice(timeline.Bars, func(i, j int) bool {
+		a, b := timeline.Bars[i], timeline.Bars[j]
+		if !a.Start.Equal(b.Start) {
+			return a.Start.Before(b.Start)
+		}
+		return a.Title < b.Title
+	})
+
+	writeJSON(w, http.StatusOK, timeline)
+}
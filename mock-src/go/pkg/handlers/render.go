@@ -0,0 +1,35 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// errUnsupportedRender is returned for a render query parameter other than html.
+var errUnsupportedRender = errors.New("render must be html")
+
+// renderHTML reports whether the request asks for rich text fields to be
+// rendered with ?render=html.
+func renderHTML(r *http.Request) (bool, error) {
+	switch r.URL.Query().Get("render") {
+	case "":
+		return false, nil
+	case "html":
+		return true, nil
+	default:
+		return false, errUnsupportedRender
+	}
+}
+
+// CommentResponse is a comment as returned by the API.
+//
+// BodyHTML is set only when ?render=html is requested, and Reactions
+// only when the comment has any.
+type CommentResponse struct {
+	*models.Comment
+	BodyHTML  string                 `json:"body_html,omitempty"`
+	Reactions []models.ReactionCount `json:"reactions,omitempty"`
+}
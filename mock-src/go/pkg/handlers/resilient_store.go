@@ -0,0 +1,311 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// ErrCircuitOpen is returned when the circuit breaker is rejecting calls.
+var ErrCircuitOpen = errors.New("store circuit breaker is open")
+
+// circuitState is the state of a circuit breaker.
+type circuitState int
+
+const (
+	// circuitClosed lets calls through and counts failures.
+	circuitClosed circuitState = iota
+	// circuitOpen rejects calls until the cooldown has passed.
+	circuitOpen
+	// circuitHalfOpen lets a single probe call through.
+	circuitHalfOpen
+)
+
+// CircuitBreaker fails fast after repeated transient failures.
+//
+// After FailureThreshold consecutive failures the breaker opens and
+// rejects calls for Cooldown, then lets one probe through. A successful
+// probe closes the breaker; a failed one reopens it. A probe abandoned
+// because its caller gave up says nothing about the backend, so the
+// breaker stays half-open and lets the next call probe instead.
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	state            circuitState
+	probing          bool
+	failures         int
+	openedAt         time.Time
+	failureThreshold int
+	cooldown         time.Duration
+	now              func() time.Time
+}
+
+// NewCircuitBreaker creates a closed circuit breaker.
+func NewCircuitBreaker(failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{failureThreshold: failureThreshold, cooldown: cooldown, now: time.Now}
+}
+
+// Allow reports whether a call may proceed.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = circuitHalfOpen
+		b.probing = true
+		return true
+	case circuitHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// Success records a successful call.
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = circuitClosed
+	b.probing = false
+	b.failures = 0
+}
+
+// Failure records a failed call.
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	b.failures++
+	if b.state == circuitHalfOpen || b.failures >= b.failureThreshold {
+		b.state = circuitOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Abandon records a call that ended without an answer from the backend,
+// such as one whose context was cancelled. It counts as neither a
+// success nor a failure; a half-open breaker lets another probe through.
+func (b *CircuitBreaker) Abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// Healthy returns ErrCircuitOpen while the breaker is rejecting calls.
+func (b *CircuitBreaker) Healthy(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == circuitOpen && b.now().Sub(b.openedAt) < b.cooldown {
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+// IsTransientStoreError is the default classifier for retryable errors.
+//
+// Not-found errors, duplicate IDs, version conflicts, context cancellation, and an open
+// circuit are permanent; anything else is assumed to be a transient
+// backend error.
+func IsTransientStoreError(err error) bool {
+	switch {
+	case err == nil,
+		errors.Is(err, ErrTaskNotFound),
+		errors.Is(err, ErrTaskExists),
+		errors.Is(err, ErrVersionConflict),
+		errors.Is(err, ErrCircuitOpen),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		return false
+	}
+	return true
+}
+
+// ResilientTaskStore is a TaskStore decorator for remote backends that
+// retries transient errors with jittered backoff and trips a circuit
+// breaker when the backend keeps failing.
+//
+// Only reads are retried by default: a write that failed with a
+// transient error, such as a timeout, may still have been applied, and
+// retrying it could apply it twice. WithWriteRetries retries writes too,
+// for backends whose writes are idempotent.
+type ResilientTaskStore struct {
+	next        TaskStore
+	breaker     *CircuitBreaker
+	maxAttempts int
+	retryWrites bool
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	isTransient func(error) bool
+}
+
+// ResilienceOption is a function that configures a ResilientTaskStore.
+type ResilienceOption func(*ResilientTaskStore)
+
+// WithMaxAttempts sets the total number of attempts per retried operation.
+func WithMaxAttempts(n int) ResilienceOption {
+	return func(s *ResilientTaskStore) {
+		if n > 0 {
+			s.maxAttempts = n
+		}
+	}
+}
+
+// WithWriteRetries retries Create, Update and Delete like reads. Use it
+// only with a backend that applies a repeated write at most once.
+func WithWriteRetries() ResilienceOption {
+	return func(s *ResilientTaskStore) {
+		s.retryWrites = true
+	}
+}
+
+// WithBackoff sets the base and maximum delay between retries.
+func WithBackoff(base, max time.Duration) ResilienceOption {
+	return func(s *ResilientTaskStore) {
+		s.baseDelay = base
+		s.maxDelay = max
+	}
+}
+
+// WithCircuitBreaker sets the circuit breaker, allowing it to be shared.
+func WithCircuitBreaker(breaker *CircuitBreaker) ResilienceOption {
+	return func(s *ResilientTaskStore) {
+		s.breaker = breaker
+	}
+}
+
+// WithTransientClassifier sets the function deciding which errors are retried.
+func WithTransientClassifier(fn func(error) bool) ResilienceOption {
+	return func(s *ResilientTaskStore) {
+		s.isTransient = fn
+	}
+}
+
+// NewResilientTaskStore wraps a task store with retries and a circuit breaker.
+//
+// Defaults are three attempts for reads and one for writes, 50ms base
+// backoff capped at 1s, and a breaker that opens after five failures
+// for 30s.
+func NewResilientTaskStore(next TaskStore, opts ...ResilienceOption) *ResilientTaskStore {
+	s := &ResilientTaskStore{
+		next:        next,
+		breaker:     NewCircuitBreaker(5, 30*time.Second),
+		maxAttempts: 3,
+		baseDelay:   50 * time.Millisecond,
+		maxDelay:    time.Second,
+		isTransient: IsTransientStoreError,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Healthy reports whether the backend is currently considered available.
+func (s *ResilientTaskStore) Healthy(ctx context.Context) error {
+	return s.breaker.Healthy(ctx)
+}
+
+// do runs op, consulting the circuit breaker before each attempt. op is
+// retried only if retry is set.
+func (s *ResilientTaskStore) do(ctx context.Context, retry bool, op func() error) error {
+	attempts := 1
+	if retry {
+		attempts = s.maxAttempts
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if werr := s.wait(ctx, attempt); werr != nil {
+				return err
+			}
+		}
+		if !s.breaker.Allow() {
+			return ErrCircuitOpen
+		}
+
+		err = op()
+		if err != nil && (ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+			s.breaker.Abandon()
+			return err
+		}
+		if !s.isTransient(err) {
+			s.breaker.Success()
+			return err
+		}
+		s.breaker.Failure()
+	}
+	return err
+}
+
+// wait sleeps for a jittered exponential backoff or until ctx is done.
+func (s *ResilientTaskStore) wait(ctx context.Context, attempt int) error {
+	delay := s.baseDelay << (attempt - 1)
+	if delay <= 0 || delay > s.maxDelay {
+		delay = s.maxDelay
+	}
+	if delay > 0 {
+		delay = time.Duration(rand.Int63n(int64(delay)) + 1)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Get retrieves a task by ID.
+func (s *ResilientTaskStore) Get(ctx context.Context, id models.TaskID) (*models.Task, error) {
+	var task *models.Task
+	err := s.do(ctx, true, func() error {
+		var err error
+		task, err = s.next.Get(ctx, id)
+		return err
+	})
+	return task, err
+}
+
+// GetAll retrieves all tasks.
+func (s *ResilientTaskStore) GetAll(ctx context.Context) ([]*models.Task, error) {
+	var tasks []*models.Task
+	err := s.do(ctx, true, func() error {
+		var err error
+		tasks, err = s.next.GetAll(ctx)
+		return err
+	})
+	return tasks, err
+}
+
+// Create stores a new task.
+func (s *ResilientTaskStore) Create(ctx context.Context, task *models.Task) error {
+	return s.do(ctx, s.retryWrites, func() error { return s.next.Create(ctx, task) })
+}
+
+// Update updates an existing task.
+func (s *ResilientTaskStore) Update(ctx context.Context, task *models.Task) error {
+	return s.do(ctx, s.retryWrites, func() error { return s.next.Update(ctx, task) })
+}
+
+// Delete removes a task by ID.
+func (s *ResilientTaskStore) Delete(ctx context.Context, id models.TaskID) error {
+	return s.do(ctx, s.retryWrites, func() error { return s.next.Delete(ctx, id) })
+}
@@ -0,0 +1,174 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// RetentionPolicy configures how long data is kept before it is purged.
+//
+// A zero value disables the corresponding rule. ProjectOverrides maps a
+// project ID to the number of days completed tasks are kept in that
+// project; an override of zero keeps them forever.
+type RetentionPolicy struct {
+	CompletedTaskDays int                      `json:"completed_task_days"`
+	AuditLogMonths    int                      `json:"audit_log_months"`
+	ProjectOverrides  map[models.ProjectID]int `json:"project_overrides,omitempty"`
+}
+
+// completedTaskDays returns the retention period for completed tasks in a project.
+func (p RetentionPolicy) completedTaskDays(projectID models.ProjectID) int {
+	if days, ok := p.ProjectOverrides[projectID]; ok {
+		return days
+	}
+	return p.CompletedTaskDays
+}
+
+// RetentionReport describes what a retention run purged or would purge.
+type RetentionReport struct {
+	RunAt              time.Time       `json:"run_at"`
+	DryRun             bool            `json:"dry_run"`
+	PurgedTasks        []models.TaskID `json:"purged_tasks"`
+	PurgedAuditEntries int             `json:"purged_audit_entries"`
+}
+
+// RetentionJob applies a retention policy to the task and audit stores.
+type RetentionJob struct {
+	policy RetentionPolicy
+	tasks  TaskStore
+	audit  AuditStore
+	now    func() time.Time
+}
+
+// NewRetentionJob creates a new retention job.
+func NewRetentionJob(policy RetentionPolicy, tasks TaskStore, audit AuditStore) *RetentionJob {
+	return &RetentionJob{policy: policy, tasks: tasks, audit: audit, now: time.Now}
+}
+
+// Run purges data that has outlived the policy.
+//
+// In dry-run mode nothing is deleted and the report lists what would be.
+func (j *RetentionJob) Run(ctx context.Context, dryRun bool) (*RetentionReport, error) {
+	now := j.now()
+	report := &RetentionReport{RunAt: now, DryRun: dryRun, PurgedTasks: make([]models.TaskID, 0)}
+
+	tasks, err := j.tasks.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		if task.Status != models.TaskStatusCompleted {
+			continue
+		}
+		days := j.policy.completedTaskDays(task.ProjectID)
+		if days <= 0 || !task.UpdatedAt.Before(now.AddDate(0, 0, -days)) {
+			continue
+		}
+		if !dryRun {
+			if err := j.tasks.Delete(ctx, task.ID); err != nil {
+				return report, err
+			}
+		}
+		report.PurgedTasks = append(report.PurgedTasks, task.ID)
+	}
+
+	if j.policy.AuditLogMonths > 0 {
+		cutoff := now.AddDate(0, -j.policy.AuditLogMonths, 0)
+		if dryRun {
+			entries, err := j.audit.List(ctx)
+			if err != nil {
+				return report, err
+			}
+			for _, entry := range entries {
+				if entry.CreatedAt.Before(cutoff) {
+					report.PurgedAuditEntries++
+				}
+			}
+		} else {
+			removed, err := j.audit.DeleteBefore(ctx, cutoff)
+			if err != nil {
+				return report, err
+			}
+			report.PurgedAuditEntries = removed
+		}
+	}
+
+	return report, nil
+}
+
+// Start runs the job every interval until ctx is cancelled, skipping runs
+// while mode, which may be nil, is enabled. It returns
+// ErrInvalidInterval, without starting, if interval is not positive.
+func (j *RetentionJob) Start(ctx context.Context, interval time.Duration, mode *MaintenanceMode) error {
+	if interval <= 0 {
+		return ErrInvalidInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if mode.Enabled() {
+					continue
+				}
+				report, err := j.Run(ctx, false)
+				if err != nil {
+					log.Printf("retention: run failed: %v", err)
+					continue
+				}
+				log.Printf("retention: purged %d tasks and %d audit entries",
+					len(report.PurgedTasks), report.PurgedAuditEntries)
+			}
+		}
+	}()
+	return nil
+}
+
+// RetentionHandler handles HTTP requests for data retention.
+type RetentionHandler struct {
+	job *RetentionJob
+}
+
+// NewRetentionHandler creates a new retention handler.
+func NewRetentionHandler(job *RetentionJob) *RetentionHandler {
+	return &RetentionHandler{job: job}
+}
+
+// Report handles GET /admin/retention/report requests with a dry run.
+func (h *RetentionHandler) Report(w http.ResponseWriter, r *http.Request) {
+	h.run(w, r, true)
+}
+
+// Run handles POST /admin/retention/run requests.
+func (h *RetentionHandler) Run(w http.ResponseWriter, r *http.Request) {
+	h.run(w, r, r.URL.Query().Get("dry_run") == "true")
+}
+
+// run executes the retention job for an authorized caller.
+func (h *RetentionHandler) run(w http.ResponseWriter, r *http.Request, dryRun bool) {
+	caller, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+	if !caller.HasPermission("manage") {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	report, err := h.job.Run(r.Context(), dryRun)
+	if err != nil {
+		writeServerError(w, r, "retention run failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
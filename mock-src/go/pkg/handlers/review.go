@@ -0,0 +1,197 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// notifyReviewer tells a task's reviewer that the task awaits their
+// review. Nobody is notified if the task has no reviewer or the
+// reviewer made the request.
+func notifyReviewer(ctx context.Context, notifications NotificationStore, task *models.Task) error {
+	if task.ReviewerID == nil {
+		return nil
+	}
+	var actorID models.UserID
+	if user, ok := UserFromContext(ctx); ok {
+		actorID = user.ID
+	}
+	if *task.ReviewerID == actorID {
+		return nil
+	}
+	n := models.NewNotification(*task.ReviewerID, models.NotificationReviewRequested, task.ID, actorID)
+	n.Message = "review requested: " + task.Title
+	return notifications.Create(ctx, n)
+}
+
+// requestReview notifies the reviewer of a task just submitted for
+// review. Notification is best effort: failures are logged.
+func (h *TaskHandler) requestReview(ctx context.Context, task *models.Task) {
+	if h.notifications == nil {
+		return
+	}
+	if err := notifyReviewer(ctx, h.notifications, task); err != nil {
+		log.Printf("tasks: notifying reviewer of %s: %v", task.ID, err)
+	}
+}
+
+// ReviewHandler handles HTTP requests for task reviewers.
+type ReviewHandler struct {
+	tasks         TaskStore
+	users         UserStore
+	notifications NotificationStore
+}
+
+// NewReviewHandler creates a new review handler.
+func NewReviewHandler(tasks TaskStore, users UserStore, notifications NotificationStore) *ReviewHandler {
+	return &ReviewHandler{tasks: tasks, users: users, notifications: notifications}
+}
+
+// SetReviewerRequest is the request body for setting a task's reviewer.
+type SetReviewerRequest struct {
+	UserID models.UserID `json:"user_id"`
+}
+
+// SetReviewer handles PUT /tasks/{id}/reviewer requests.
+//
+// The reviewer must be an active user other than the assignee, and
+// starts watching the task. A reviewer set on a task already awaiting
+// review is notified.
+func (h *ReviewHandler) SetReviewer(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	if _, ok := UserFromContext(r.Context()); !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req SetReviewerRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if req.UserID == "" {
+		http.Error(w, "user_id is required", http.StatusBadRequest)
+		return
+	}
+
+	reviewer, err := h.users.Get(r.Context(), req.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			http.Error(w, "unknown user "+string(req.UserID), http.StatusBadRequest)
+			return
+		}
+		writeServerError(w, r, "failed to get user", err)
+		return
+	}
+	if !reviewer.IsActive {
+		http.Error(w, "user "+string(req.UserID)+" is deactivated", http.StatusBadRequest)
+		return
+	}
+
+	isAssignee, changed := false, false
+	task, err := updateTask(r.Context(), h.tasks, taskID, func(task *models.Task) bool {
+		if task.AssigneeID != nil && *task.AssigneeID == req.UserID {
+			isAssignee = true
+			return false
+		}
+		changed = task.SetReviewer(req.UserID)
+		return changed
+	})
+	if err == nil && isAssignee {
+		http.Error(w, "the reviewer cannot be the task's assignee", http.StatusConflict)
+		return
+	}
+	if !h.respond(w, task, err) {
+		return
+	}
+
+	if changed && task.Status == models.TaskStatusAwaitingReview {
+		if err := notifyReviewer(r.Context(), h.notifications, task); err != nil {
+			log.Printf("reviews: notifying reviewer of %s: %v", task.ID, err)
+		}
+	}
+}
+
+// RemoveReviewer handles DELETE /tasks/{id}/reviewer requests.
+func (h *ReviewHandler) RemoveReviewer(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	if _, ok := UserFromContext(r.Context()); !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	task, err := updateTask(r.Context(), h.tasks, taskID, func(task *models.Task) bool {
+		return task.ClearReviewer()
+	})
+	h.respond(w, task, err)
+}
+
+// Mine handles GET /me/reviews requests, listing the open tasks the
+// current user reviews. Tasks awaiting review come first, longest
+// waiting first. An optional status parameter keeps only tasks in that
+// status, including closed ones.
+func (h *ReviewHandler) Mine(w http.ResponseWriter, r *http.Request) {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+	status := models.TaskStatus(r.URL.Query().Get("status"))
+
+	tasks, err := h.tasks.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+
+	reviews := make([]*models.Task, 0)
+	for _, task := range visibleTasks(r.Context(), tasks) {
+		if task.ReviewerID == nil || *task.ReviewerID != user.ID {
+			continue
+		}
+		if status != "" && task.Status != status {
+			continue
+		}
+		if status == "" && !task.IsOpen() {
+			continue
+		}
+		reviews = append(reviews, task)
+	}
+	sort.SliceStable(reviews, func(i, j int) bool {
+		a, b := reviews[i], reviews[j]
+		aWaiting := a.Status == models.TaskStatusAwaitingReview
+		bWaiting := b.Status == models.TaskStatusAwaitingReview
+		if aWaiting != bWaiting {
+			return aWaiting
+		}
+		return a.StatusSince().Before(b.StatusSince())
+	})
+
+	responses := make([]*TaskResponse, 0, len(reviews))
+	for _, task := range reviews {
+		responses = append(responses, toResponse(task))
+	}
+	writeJSON(w, http.StatusOK, responses)
+}
+
+// respond writes the task or maps err to a status code, reporting
+// whether it wrote the task.
+func (h *ReviewHandler) respond(w http.ResponseWriter, task *models.Task, err error) bool {
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrTaskNotFound):
+			http.Error(w, "task not found", http.StatusNotFound)
+		case errors.Is(err, ErrVersionConflict):
+			http.Error(w, "task was modified concurrently", http.StatusConflict)
+		default:
+			http.Error(w, "failed to update reviewer", http.StatusInternalServerError)
+		}
+		return false
+	}
+	writeJSON(w, http.StatusOK, toResponse(task))
+	return true
+}
@@ -0,0 +1,508 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"container/heap"
+	"context"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+const (
+	// defaultSearchSuggestLimit caps search suggestions when no limit is
+	// given.
+	defaultSearchSuggestLimit = 10
+	// maxSearchSuggestLimit is the largest limit a client may request.
+	maxSearchSuggestLimit = 50
+	// maxSearchCandidates caps the tasks a suggestion query examines,
+	// bounding the latency of very short queries.
+	maxSearchCandidates = 5000
+)
+
+// SearchKind is the kind of entity a search suggestion refers to.
+type SearchKind string
+
+const (
+	// SearchTask is a task, matched by title and key.
+	SearchTask SearchKind = "task"
+	// SearchProject is a project, matched by name, key and slug.
+	SearchProject SearchKind = "project"
+	// SearchUser is a user, matched by username and display name.
+	SearchUser SearchKind = "user"
+)
+
+// SearchSuggestion is one match of a search-as-you-type query. Detail
+// is a short secondary label: a task's or project's key, or a username.
+type SearchSuggestion struct {
+	Kind   SearchKind `json:"kind"`
+	ID     string     `json:"id"`
+	Label  string     `json:"label"`
+	Detail string     `json:"detail,omitempty"`
+}
+
+// searchRef identifies an indexed entity.
+type searchRef struct {
+	kind SearchKind
+	id   string
+}
+
+// searchDoc is an indexed entity. label and detail are lowercased for
+// matching. Tasks keep a snapshot so visibility can be checked without
+// going to the store.
+type searchDoc struct {
+	SearchSuggestion
+	label  string
+	detail string
+	terms  []string
+	task   *models.Task
+	hidden bool
+}
+
+// SearchIndex is an in-memory prefix index over task titles, project
+// names and user names, for suggestions as the user types.
+//
+// Text is split into lowercase words. A query matches an entity when
+// each of its words is a prefix of one of the entity's words, so "api
+// 14" finds "API-142". Lookups binary search a sorted list of the
+// distinct words, so they cost little more than the number of matches.
+// Tasks, which far outnumber projects and users, have words of their
+// own, so capping the tasks a query examines never hides the others.
+// Tasks of archived projects are not suggested; the index keeps track of
+// which projects are archived as they are indexed.
+type SearchIndex struct {
+	mu       sync.RWMutex
+	docs     map[searchRef]*searchDoc
+	tasks    *termIndex
+	others   *termIndex
+	archived map[models.ProjectID]bool
+}
+
+// NewSearchIndex creates an empty search index.
+func NewSearchIndex() *SearchIndex {
+	return &SearchIndex{
+		docs:     make(map[searchRef]*searchDoc),
+		tasks:    newTermIndex(),
+		others:   newTermIndex(),
+		archived: make(map[models.ProjectID]bool),
+	}
+}
+
+// termIndex maps words to the entities having them, keeping the
+// distinct words sorted for prefix lookups.
+type termIndex struct {
+	postings map[string]map[searchRef]struct{}
+	terms    []string
+}
+
+// newTermIndex creates an empty term index.
+func newTermIndex() *termIndex {
+	return &termIndex{postings: make(map[string]map[searchRef]struct{})}
+}
+
+// add records that an entity has the word term.
+func (t *termIndex) add(term string, ref searchRef) {
+	refs, ok := t.postings[term]
+	if !ok {
+		refs = make(map[searchRef]struct{})
+		t.postings[term] = refs
+		i := sort.SearchStrings(t.terms, term)
+		t.terms = slices.Insert(t.terms, i, term)
+	}
+	refs[ref] = struct{}{}
+}
+
+// drop forgets that an entity has the word term.
+func (t *termIndex) drop(term string, ref searchRef) {
+	refs := t.postings[term]
+	delete(refs, ref)
+	if len(refs) > 0 {
+		return
+	}
+	delete(t.postings, term)
+	if i := sort.SearchStrings(t.terms, term); i < len(t.terms) && t.terms[i] == term {
+		t.terms = slices.Delete(t.terms, i, i+1)
+	}
+}
+
+// prefixRange returns the words starting with prefix and how many
+// entities have them between them.
+func (t *termIndex) prefixRange(prefix string) ([]string, int) {
+	i := sort.SearchStrings(t.terms, prefix)
+	j, size := i, 0
+	for j < len(t.terms) && strings.HasPrefix(t.terms[j], prefix) {
+		size += len(t.postings[t.terms[j]])
+		j++
+	}
+	return t.terms[i:j], size
+}
+
+// searchTerms splits text into distinct lowercase words of letters and
+// digits.
+func searchTerms(text ...string) []string {
+	var terms []string
+	for _, t := range text {
+		for _, word := range strings.FieldsFunc(strings.ToLower(t), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if !slices.Contains(terms, word) {
+				terms = append(terms, word)
+			}
+		}
+	}
+	return terms
+}
+
+// PutTask indexes a task's title and key. Drafts and restricted tasks
+// are only suggested to the users who may see them.
+func (idx *SearchIndex) PutTask(task *models.Task) {
+	idx.put(&searchDoc{
+		SearchSuggestion: SearchSuggestion{Kind: SearchTask, ID: string(task.ID), Label: task.Title, Detail: task.Key},
+		terms:            searchTerms(task.Title, task.Key),
+		task:             task.Clone(),
+	})
+}
+
+// PutProject indexes a project's name, key and slug. Archived projects
+// are not suggested, and neither are their tasks.
+func (idx *SearchIndex) PutProject(project *models.Project) {
+	idx.put(&searchDoc{
+		SearchSuggestion: SearchSuggestion{Kind: SearchProject, ID: string(project.ID), Label: project.Name, Detail: project.Key},
+		terms:            searchTerms(project.Name, project.Key, project.Slug),
+		hidden:           project.IsArchived(),
+	})
+}
+
+// PutUser indexes a user's username and display name. Inactive users
+// are not suggested.
+func (idx *SearchIndex) PutUser(user *models.User) {
+	label := user.DisplayName
+	if label == "" {
+		label = user.Username
+	}
+	idx.put(&searchDoc{
+		SearchSuggestion: SearchSuggestion{Kind: SearchUser, ID: string(user.ID), Label: label, Detail: user.Username},
+		terms:            searchTerms(user.Username, user.DisplayName),
+		hidden:           !user.IsActive,
+	})
+}
+
+// Remove drops an entity from the index.
+func (idx *SearchIndex) Remove(kind SearchKind, id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(searchRef{kind: kind, id: id})
+}
+
+// put indexes a document, replacing what was indexed for its entity.
+func (idx *SearchIndex) put(doc *searchDoc) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	doc.label, doc.detail = strings.ToLower(doc.Label), strings.ToLower(doc.Detail)
+	ref := searchRef{kind: doc.Kind, id: doc.ID}
+	idx.remove(ref)
+	idx.docs[ref] = doc
+	if doc.Kind == SearchProject && doc.hidden {
+		idx.archived[models.ProjectID(doc.ID)] = true
+	}
+	terms := idx.termIndex(doc.Kind)
+	for _, term := range doc.terms {
+		terms.add(term, ref)
+	}
+}
+
+// remove drops an entity from the index. The caller must hold idx.mu.
+func (idx *SearchIndex) remove(ref searchRef) {
+	doc, ok := idx.docs[ref]
+	if !ok {
+		return
+	}
+	terms := idx.termIndex(doc.Kind)
+	for _, term := range doc.terms {
+		terms.drop(term, ref)
+	}
+	delete(idx.docs, ref)
+	if ref.kind == SearchProject {
+		delete(idx.archived, models.ProjectID(ref.id))
+	}
+}
+
+// termIndex returns the term index holding entities of a kind.
+func (idx *SearchIndex) termIndex(kind SearchKind) *termIndex {
+	if kind == SearchTask {
+		return idx.tasks
+	}
+	return idx.others
+}
+
+// Suggest returns up to limit entities matching query, best first, that
+// the user may see, leaving out tasks of archived projects. kinds, if
+// not empty, restricts the kinds suggested.
+//
+// An entity scores two points for each query word equal to one of its
+// words and one for each that is only a prefix, three more if its label
+// or detail starts with the query, and five more if its detail is the
+// query, as when searching for a task key. Ties go to the shorter label.
+//
+// Queries whose words are all very common stop after examining
+// maxSearchCandidates tasks, starting with those that have the least
+// common query word as a whole word, so the tasks suggested are good
+// rather than the very best. Projects and users are always examined.
+func (idx *SearchIndex) Suggest(user *models.User, query string, kinds []SearchKind, limit int) []SearchSuggestion {
+	words := searchTerms(query)
+	if len(words) == 0 || limit <= 0 {
+		return make([]SearchSuggestion, 0)
+	}
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	allowed := func(doc *searchDoc) bool {
+		if doc.hidden || len(kinds) > 0 && !slices.Contains(kinds, doc.Kind) {
+			return false
+		}
+		return doc.task == nil || !idx.archived[doc.task.ProjectID] && doc.task.IsVisibleTo(user)
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	top := &searchHeap{}
+	idx.collect(top, idx.others, words, query, allowed, limit, -1)
+	if len(kinds) == 0 || slices.Contains(kinds, SearchTask) {
+		idx.collect(top, idx.tasks, words, query, allowed, limit, maxSearchCandidates)
+	}
+
+	suggestions := make([]SearchSuggestion, top.Len())
+	for i := len(suggestions) - 1; i >= 0; i-- {
+		suggestions[i] = heap.Pop(top).(searchMatch).doc.SearchSuggestion
+	}
+	return suggestions
+}
+
+// collect adds the best matches in terms to top, keeping at most
+// limit. It stops after examining maxCandidates entities, unless
+// maxCandidates is negative. The caller must hold idx.mu.
+func (idx *SearchIndex) collect(top *searchHeap, terms *termIndex, words []string, query string, allowed func(*searchDoc) bool, limit, maxCandidates int) {
+	// Candidates come from the word with the fewest matches; the others
+	// are checked against each candidate's words.
+	var seed []string
+	var seedWord string
+	seedSize := -1
+	for _, word := range words {
+		if r, size := terms.prefixRange(word); seedSize < 0 || size < seedSize {
+			seed, seedWord, seedSize = r, word, size
+		}
+	}
+
+	examined := 0
+	for _, term := range seed {
+		for ref := range terms.postings[term] {
+			doc := idx.docs[ref]
+			// A document with several words starting with the seed is
+			// seen under each; only the first counts.
+			if doc.firstTerm(seedWord) != term {
+				continue
+			}
+			if examined++; maxCandidates >= 0 && examined > maxCandidates {
+				return
+			}
+			if !allowed(doc) {
+				continue
+			}
+			score, ok := doc.score(words)
+			if !ok {
+				continue
+			}
+			if strings.HasPrefix(doc.label, query) || strings.HasPrefix(doc.detail, query) {
+				score += 3
+			}
+			if doc.detail == query {
+				score += 5
+			}
+			m := searchMatch{doc: doc, score: score}
+			if top.Len() < limit {
+				heap.Push(top, m)
+			} else if m.better((*top)[0]) {
+				(*top)[0] = m
+				heap.Fix(top, 0)
+			}
+		}
+	}
+}
+
+// searchMatch is a scored suggestion candidate.
+type searchMatch struct {
+	doc   *searchDoc
+	score int
+}
+
+// better reports whether m ranks before o.
+func (m searchMatch) better(o searchMatch) bool {
+	if m.score != o.score {
+		return m.score > o.score
+	}
+	if len(m.doc.Label) != len(o.doc.Label) {
+		return len(m.doc.Label) < len(o.doc.Label)
+	}
+	if m.doc.Label != o.doc.Label {
+		return m.doc.Label < o.doc.Label
+	}
+	return m.doc.ID < o.doc.ID
+}
+
+// searchHeap keeps the best matches seen so far, worst on top.
+type searchHeap []searchMatch
+
+func (h searchHeap) Len() int           { return len(h) }
+func (h searchHeap) Less(i, j int) bool { return h[j].better(h[i]) }
+func (h searchHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *searchHeap) Push(x any)        { *h = append(*h, x.(searchMatch)) }
+func (h *searchHeap) Pop() any {
+	old := *h
+	m := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return m
+}
+
+// firstTerm returns the document's lowest word starting with prefix.
+func (doc *searchDoc) firstTerm(prefix string) string {
+	first := ""
+	for _, term := range doc.terms {
+		if strings.HasPrefix(term, prefix) && (first == "" || term < first) {
+			first = term
+		}
+	}
+	return first
+}
+
+// score scores the document against the query words, reporting false
+// if any word matches none of its terms.
+func (doc *searchDoc) score(words []string) (int, bool) {
+	total := 0
+	for _, word := range words {
+		best := 0
+		for _, term := range doc.terms {
+			if term == word {
+				best = 2
+				break
+			}
+			if strings.HasPrefix(term, word) {
+				best = 1
+			}
+		}
+		if best == 0 {
+			return 0, false
+		}
+		total += best
+	}
+	return total, true
+}
+
+// IndexTasks keeps the index up to date with the tasks written through
+// a HookedTaskStore using hooks.
+func (idx *SearchIndex) IndexTasks(hooks *Hooks[models.TaskID, *models.Task]) {
+	index := func(ctx context.Context, task *models.Task) { idx.PutTask(task) }
+	hooks.AfterCreate(index)
+	hooks.AfterUpdate(index)
+	hooks.AfterDelete(func(ctx context.Context, id models.TaskID) { idx.Remove(SearchTask, string(id)) })
+}
+
+// IndexProjects keeps the index up to date with the projects written
+// through a HookedStore using hooks.
+func (idx *SearchIndex) IndexProjects(hooks *Hooks[models.ProjectID, *models.Project]) {
+	index := func(ctx context.Context, project *models.Project) { idx.PutProject(project) }
+	hooks.AfterCreate(index)
+	hooks.AfterUpdate(index)
+	hooks.AfterDelete(func(ctx context.Context, id models.ProjectID) { idx.Remove(SearchProject, string(id)) })
+}
+
+// IndexUsers keeps the index up to date with the users written through
+// a HookedStore using hooks.
+func (idx *SearchIndex) IndexUsers(hooks *Hooks[models.UserID, *models.User]) {
+	index := func(ctx context.Context, user *models.User) { idx.PutUser(user) }
+	hooks.AfterCreate(index)
+	hooks.AfterUpdate(index)
+	hooks.AfterDelete(func(ctx context.Context, id models.UserID) { idx.Remove(SearchUser, string(id)) })
+}
+
+// Rebuild indexes every task, project and user already stored. Any of
+// the stores may be nil.
+func (idx *SearchIndex) Rebuild(ctx context.Context, tasks TaskStore, projects ProjectStore, users UserStore) error {
+	if tasks != nil {
+		all, err := tasks.GetAll(ctx)
+		if err != nil {
+			return err
+		}
+		for _, task := range all {
+			idx.PutTask(task)
+		}
+	}
+	if projects != nil {
+		all, err := projects.GetAll(ctx)
+		if err != nil {
+			return err
+		}
+		for _, project := range all {
+			idx.PutProject(project)
+		}
+	}
+	if users != nil {
+		all, err := users.GetAll(ctx)
+		if err != nil {
+			return err
+		}
+		for _, user := range all {
+			idx.PutUser(user)
+		}
+	}
+	return nil
+}
+
+// SearchHandler handles HTTP requests for search.
+type SearchHandler struct {
+	index *SearchIndex
+}
+
+// NewSearchHandler creates a new search handler.
+func NewSearchHandler(index *SearchIndex) *SearchHandler {
+	return &SearchHandler{index: index}
+}
+
+// Suggest handles GET /search/suggest?q=&types=&limit= requests, for
+// command palettes that search as the user types. types is a
+// comma-separated subset of task, project and user.
+func (h *SearchHandler) Suggest(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := defaultSearchSuggestLimit
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > maxSearchSuggestLimit {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	var kinds []SearchKind
+	if raw := query.Get("types"); raw != "" {
+		for _, kind := range strings.Split(raw, ",") {
+			switch k := SearchKind(strings.TrimSpace(kind)); k {
+			case SearchTask, SearchProject, SearchUser:
+				kinds = append(kinds, k)
+			default:
+				http.Error(w, "invalid types", http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	user, _ := UserFromContext(r.Context())
+	writeJSON(w, http.StatusOK, h.index.Suggest(user, query.Get("q"), kinds, limit))
+}
@@ -0,0 +1,161 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SecurityConfig configures the security headers and CSRF protection
+// applied by a deployment.
+//
+// Empty header values are not sent, and a zero HSTSMaxAge disables
+// Strict-Transport-Security. CSRF tokens are only checked on requests
+// that carry SessionCookie; an empty SessionCookie disables the check,
+// as for deployments that authenticate with bearer tokens only.
+type SecurityConfig struct {
+	ContentSecurityPolicy string
+	FrameOptions          string
+	ReferrerPolicy        string
+	HSTSMaxAge            time.Duration
+	SessionCookie         string
+	CSRFCookie            string
+	CSRFHeader            string
+	SecureCookies         bool
+}
+
+// DefaultSecurityConfig returns a strict configuration suitable for an
+// API served over HTTPS.
+func DefaultSecurityConfig() SecurityConfig {
+	return SecurityConfig{
+		ContentSecurityPolicy: "default-src 'none'; frame-ancestors 'none'",
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "no-referrer",
+		HSTSMaxAge:            365 * 24 * time.Hour,
+		SessionCookie:         "session",
+		CSRFCookie:            "csrf_token",
+		CSRFHeader:            "X-CSRF-Token",
+		SecureCookies:         true,
+	}
+}
+
+// csrfTokenBytes is the amount of randomness in a CSRF token.
+const csrfTokenBytes = 32
+
+// Security applies security headers and CSRF protection to requests.
+//
+// CSRF protection uses the double-submit pattern: safe requests are
+// issued a token cookie readable by the page's scripts, and mutating
+// requests made with the session cookie must echo that token in the
+// CSRF header. Requests to the exempt paths are never checked.
+type Security struct {
+	config SecurityConfig
+	exempt map[string]bool
+}
+
+// NewSecurity creates security middleware with the given configuration.
+func NewSecurity(config SecurityConfig, exempt ...string) *Security {
+	s := &Security{config: config, exempt: make(map[string]bool, len(exempt))}
+	for _, path := range exempt {
+		s.exempt[path] = true
+	}
+	return s
+}
+
+// Headers sets the configured security headers on every response.
+func (s *Security) Headers(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("X-Content-Type-Options", "nosniff")
+		if s.config.ContentSecurityPolicy != "" {
+			h.Set("Content-Security-Policy", s.config.ContentSecurityPolicy)
+		}
+		if s.config.FrameOptions != "" {
+			h.Set("X-Frame-Options", s.config.FrameOptions)
+		}
+		if s.config.ReferrerPolicy != "" {
+			h.Set("Referrer-Policy", s.config.ReferrerPolicy)
+		}
+		if s.config.HSTSMaxAge > 0 && isHTTPS(r) {
+			h.Set("Strict-Transport-Security", "max-age="+strconv.Itoa(int(s.config.HSTSMaxAge/time.Second))+"; includeSubDomains")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CSRF rejects mutating cookie-authenticated requests whose CSRF header
+// does not match their CSRF cookie, or whose Origin is another host,
+// with 403.
+func (s *Security) CSRF(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.config.SessionCookie == "" || s.exempt[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !isMutation(r.Method) {
+			if _, err := r.Cookie(s.config.CSRFCookie); err != nil {
+				if err := s.issueToken(w); err != nil {
+					http.Error(w, "failed to issue csrf token", http.StatusInternalServerError)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if _, err := r.Cookie(s.config.SessionCookie); err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !sameOrigin(r) {
+			http.Error(w, "cross-origin request rejected", http.StatusForbidden)
+			return
+		}
+		cookie, err := r.Cookie(s.config.CSRFCookie)
+		header := r.Header.Get(s.config.CSRFHeader)
+		if err != nil || cookie.Value == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(header)) != 1 {
+			http.Error(w, "invalid csrf token", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// issueToken sets a fresh CSRF token cookie.
+func (s *Security) issueToken(w http.ResponseWriter) error {
+	buf := make([]byte, csrfTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     s.config.CSRFCookie,
+		Value:    base64.RawURLEncoding.EncodeToString(buf),
+		Path:     "/",
+		Secure:   s.config.SecureCookies,
+		SameSite: http.SameSiteStrictMode,
+	})
+	return nil
+}
+
+// isHTTPS reports whether the request arrived over TLS, directly or
+// through a proxy that says so.
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
+
+// sameOrigin reports whether the request's Origin, if any, is the host
+// it was sent to.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
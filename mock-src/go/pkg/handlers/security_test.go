@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSecurityCSRF(t *testing.T) {
+	config := DefaultSecurityConfig()
+	security := NewSecurity(config, "/inbound/email")
+
+	session := &http.Cookie{Name: config.SessionCookie, Value: "s3ss10n"}
+	token := &http.Cookie{Name: config.CSRFCookie, Value: "t0k3n"}
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		cookies []*http.Cookie
+		header  string
+		origin  string
+		want    int
+	}{
+		{name: "matching header", method: http.MethodPost, path: "/tasks", cookies: []*http.Cookie{session, token}, header: "t0k3n", want: http.StatusOK},
+		{name: "missing header", method: http.MethodPost, path: "/tasks", cookies: []*http.Cookie{session, token}, want: http.StatusForbidden},
+		{name: "mismatched header", method: http.MethodPut, path: "/tasks/1", cookies: []*http.Cookie{session, token}, header: "other", want: http.StatusForbidden},
+		{name: "header prefix", method: http.MethodDelete, path: "/tasks/1", cookies: []*http.Cookie{session, token}, header: "t0k", want: http.StatusForbidden},
+		{name: "missing cookie", method: http.MethodPost, path: "/tasks", cookies: []*http.Cookie{session}, header: "t0k3n", want: http.StatusForbidden},
+		{name: "empty cookie and header", method: http.MethodPost, path: "/tasks", cookies: []*http.Cookie{session, {Name: config.CSRFCookie}}, want: http.StatusForbidden},
+		{name: "cross origin", method: http.MethodPost, path: "/tasks", cookies: []*http.Cookie{session, token}, header: "t0k3n", origin: "https://evil.example.com", want: http.StatusForbidden},
+		{name: "same origin", method: http.MethodPost, path: "/tasks", cookies: []*http.Cookie{session, token}, header: "t0k3n", origin: "http://example.com", want: http.StatusOK},
+		{name: "no session cookie", method: http.MethodPost, path: "/tasks", want: http.StatusOK},
+		{name: "exempt path", method: http.MethodPost, path: "/inbound/email", cookies: []*http.Cookie{session}, want: http.StatusOK},
+		{name: "safe method", method: http.MethodGet, path: "/tasks", cookies: []*http.Cookie{session}, want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			for _, c := range tt.cookies {
+				req.AddCookie(c)
+			}
+			if tt.header != "" {
+				req.Header.Set(config.CSRFHeader, tt.header)
+			}
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			w := httptest.NewRecorder()
+
+			security.CSRF(next).ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+		})
+	}
+}
+
+func TestSecurityCSRFIssuesToken(t *testing.T) {
+	config := DefaultSecurityConfig()
+	security := NewSecurity(config)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	w := httptest.NewRecorder()
+	security.CSRF(next).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tasks", nil))
+
+	var issued *http.Cookie
+	for _, c := range w.Result().Cookies() {
+		if c.Name == config.CSRFCookie {
+			issued = c
+		}
+	}
+	if issued == nil || issued.Value == "" {
+		t.Fatalf("no csrf cookie issued: %v", w.Result().Cookies())
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks", nil)
+	req.AddCookie(&http.Cookie{Name: config.SessionCookie, Value: "s3ss10n"})
+	req.AddCookie(&http.Cookie{Name: config.CSRFCookie, Value: issued.Value})
+	req.Header.Set(config.CSRFHeader, issued.Value)
+	w = httptest.NewRecorder()
+	security.CSRF(next).ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d with the issued token: %s", w.Code, w.Body)
+	}
+}
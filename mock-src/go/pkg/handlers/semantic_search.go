@@ -0,0 +1,530 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+const (
+	// defaultSemanticSearchLimit caps semantic search results when no
+	// limit is given.
+	defaultSemanticSearchLimit = 10
+	// maxSemanticSearchLimit is the largest limit a client may request.
+	maxSemanticSearchLimit = 50
+	// semanticEmbedTimeout bounds embedding a task in the background.
+	semanticEmbedTimeout = 30 * time.Second
+	// semanticEmbedBatch is how many tasks Rebuild embeds per call.
+	semanticEmbedBatch = 64
+	// semanticQueueSize is how many written tasks may wait to be
+	// embedded before further writes are dropped from the queue.
+	semanticQueueSize = 1024
+	// maxSemanticTextLength caps the task text sent to an embedder.
+	maxSemanticTextLength = 8000
+	// defaultHashingDimensions is the vector size of a HashingEmbedder
+	// created without one.
+	defaultHashingDimensions = 512
+)
+
+// ErrEmbedding is returned when an embedder fails or returns vectors
+// that do not match the texts.
+var ErrEmbedding = errors.New("embedding failed")
+
+// Embedder turns texts into vectors whose cosine similarity is high for
+// related texts. It returns one vector per text, in order.
+//
+// Implementations may run a model locally or call an embeddings API.
+// Vectors from different embedders, or models, are not comparable.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// HashingEmbedder is a dependency-free local Embedder. It hashes each
+// word and its three-letter fragments into a fixed-size vector, so
+// texts sharing words or word stems, such as "timeout" and "timeouts",
+// are similar.
+//
+// It does not know that different words can mean the same thing; use
+// an HTTPEmbedder with a real model for that.
+type HashingEmbedder struct {
+	dims int
+}
+
+// NewHashingEmbedder creates a hashing embedder producing vectors of
+// dims entries. A non-positive dims defaults to 512.
+func NewHashingEmbedder(dims int) *HashingEmbedder {
+	if dims <= 0 {
+		dims = defaultHashingDimensions
+	}
+	return &HashingEmbedder{dims: dims}
+}
+
+// Embed hashes each text into a vector.
+func (e *HashingEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vector := make([]float32, e.dims)
+		for _, word := range searchTerms(text) {
+			e.add(vector, word, 1)
+			padded := "^" + word + "$"
+			for j := 0; j+3 <= len(padded); j++ {
+				e.add(vector, padded[j:j+3], 0.5)
+			}
+		}
+		vectors[i] = vector
+	}
+	return vectors, nil
+}
+
+// add adds weight to the entry a feature hashes to, with a sign also
+// taken from the hash so collisions tend to cancel out.
+func (e *HashingEmbedder) add(vector []float32, feature string, weight float32) {
+	h := fnv.New32a()
+	h.Write([]byte(feature))
+	sum := h.Sum32()
+	if sum&(1<<31) != 0 {
+		weight = -weight
+	}
+	vector[int(sum&(1<<31-1))%e.dims] += weight
+}
+
+// HTTPEmbedderConfig configures an HTTPEmbedder.
+type HTTPEmbedderConfig struct {
+	// Endpoint is the URL of the embeddings endpoint, such as
+	// https://api.openai.com/v1/embeddings.
+	Endpoint string
+	// APIKey, if set, is sent as a bearer token.
+	APIKey string
+	// Model names the embedding model.
+	Model string
+	// Client sends requests; nil uses a client with a short timeout.
+	Client *http.Client
+}
+
+// HTTPEmbedder is an Embedder calling an OpenAI-compatible embeddings
+// endpoint, as offered by hosted APIs and by local model servers.
+type HTTPEmbedder struct {
+	endpoint string
+	apiKey   string
+	model    string
+	client   *http.Client
+}
+
+// NewHTTPEmbedder creates an embedder calling the configured endpoint.
+func NewHTTPEmbedder(config HTTPEmbedderConfig) *HTTPEmbedder {
+	client := config.Client
+	if client == nil {
+		client = &http.Client{Timeout: semanticEmbedTimeout}
+	}
+	return &HTTPEmbedder{
+		endpoint: config.Endpoint,
+		apiKey:   config.APIKey,
+		model:    config.Model,
+		client:   client,
+	}
+}
+
+// embeddingsRequest is the body sent to an embeddings endpoint.
+type embeddingsRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// embeddingsResponse is the subset of an embeddings endpoint's response
+// the embedder reads.
+type embeddingsResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed sends the texts to the endpoint in one request.
+func (e *HTTPEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	payload, err := json.Marshal(&embeddingsRequest{Model: e.model, Input: texts})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEmbedding, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("%w: endpoint responded %s", ErrEmbedding, resp.Status)
+	}
+
+	var body embeddingsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEmbedding, err)
+	}
+	vectors := make([][]float32, len(texts))
+	for _, d := range body.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("%w: unexpected index %d", ErrEmbedding, d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	for i, vector := range vectors {
+		if len(vector) == 0 {
+			return nil, fmt.Errorf("%w: no embedding for input %d", ErrEmbedding, i)
+		}
+	}
+	return vectors, nil
+}
+
+// semanticDoc is an indexed task. vector is unit length, or nil until
+// the task's text has been embedded.
+type semanticDoc struct {
+	task   *models.Task
+	text   string
+	vector []float32
+}
+
+// semanticText returns the text of a task that is embedded.
+func semanticText(task *models.Task) string {
+	text := task.Title
+	if task.Description != "" {
+		text += "\n\n" + task.Description
+	}
+	if len(task.Tags) > 0 {
+		text += "\n\n" + strings.Join(task.Tags, ", ")
+	}
+	if len(text) > maxSemanticTextLength {
+		text = strings.ToValidUTF8(text[:maxSemanticTextLength], "")
+	}
+	return text
+}
+
+// normalize scales a vector to unit length, returning nil for a zero
+// vector.
+func normalize(vector []float32) []float32 {
+	var sum float64
+	for _, v := range vector {
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		return nil
+	}
+	norm := float32(math.Sqrt(sum))
+	unit := make([]float32, len(vector))
+	for i, v := range vector {
+		unit[i] = v / norm
+	}
+	return unit
+}
+
+// SemanticIndex is an optional in-memory vector index over tasks, so
+// queries such as "login timeout problems" find related tasks even
+// when they share no keywords.
+//
+// Tasks are embedded from their title, description and tags. Tasks
+// written through hooked stores are recorded as they are written and
+// queued to be embedded by the workers Start runs, so writes never wait
+// for the embedder; they are searchable once that completes. When the
+// queue is full a write is not queued, and its task stays unsearchable
+// until a later write or Rebuild embeds it. A change that leaves the
+// text alone, such as a status change, is not embedded again. Search
+// compares the query with every task, which is fast enough for tens of
+// thousands of tasks.
+type SemanticIndex struct {
+	embedder Embedder
+	queue    chan semanticJob
+
+	mu   sync.RWMutex
+	docs map[models.TaskID]*semanticDoc
+}
+
+// semanticJob is a task text waiting to be embedded.
+type semanticJob struct {
+	id   models.TaskID
+	text string
+}
+
+// NewSemanticIndex creates an empty index using an embedder.
+func NewSemanticIndex(embedder Embedder) *SemanticIndex {
+	return &SemanticIndex{
+		embedder: embedder,
+		queue:    make(chan semanticJob, semanticQueueSize),
+		docs:     make(map[models.TaskID]*semanticDoc),
+	}
+}
+
+// stage records a copy of a task and returns the text still to embed
+// for it, or "" if its current vector is up to date.
+func (idx *SemanticIndex) stage(task *models.Task) string {
+	task = task.Clone()
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	text := semanticText(task)
+	doc, ok := idx.docs[task.ID]
+	if ok && doc.text == text {
+		doc.task = task
+		if doc.vector != nil {
+			return ""
+		}
+		return text
+	}
+	idx.docs[task.ID] = &semanticDoc{task: task, text: text}
+	return text
+}
+
+// store sets the vector of a task, unless its text changed since the
+// vector was requested.
+func (idx *SemanticIndex) store(id models.TaskID, text string, vector []float32) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if doc, ok := idx.docs[id]; ok && doc.text == text {
+		doc.vector = normalize(vector)
+	}
+}
+
+// Put embeds a task and indexes it, replacing what was indexed for it.
+func (idx *SemanticIndex) Put(ctx context.Context, task *models.Task) error {
+	text := idx.stage(task)
+	if text == "" {
+		return nil
+	}
+	vectors, err := idx.embedder.Embed(ctx, []string{text})
+	if err != nil {
+		return err
+	}
+	if len(vectors) != 1 {
+		return fmt.Errorf("%w: got %d vectors for 1 text", ErrEmbedding, len(vectors))
+	}
+	idx.store(task.ID, text, vectors[0])
+	return nil
+}
+
+// Remove drops a task from the index.
+func (idx *SemanticIndex) Remove(id models.TaskID) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	delete(idx.docs, id)
+}
+
+// putAsync records a task and queues its text to be embedded, if it
+// changed. The task stays out of search results until it is embedded.
+func (idx *SemanticIndex) putAsync(task *models.Task) {
+	text := idx.stage(task)
+	if text == "" {
+		return
+	}
+	select {
+	case idx.queue <- semanticJob{id: task.ID, text: text}:
+	default:
+		log.Printf("semantic search: queue full, not embedding task %s", task.ID)
+	}
+}
+
+// Start runs workers goroutines embedding queued tasks until ctx is
+// cancelled. A non-positive workers runs one. Failures are logged, and
+// the task stays out of search results until a later write embeds it.
+func (idx *SemanticIndex) Start(ctx context.Context, workers int) {
+	for i := 0; i < max(workers, 1); i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-idx.queue:
+					if err := idx.embed(ctx, job); err != nil {
+						log.Printf("semantic search: embedding task %s: %v", job.id, err)
+					}
+				}
+			}
+		}()
+	}
+}
+
+// embed embeds one queued task text and stores its vector.
+func (idx *SemanticIndex) embed(ctx context.Context, job semanticJob) error {
+	ctx, cancel := context.WithTimeout(ctx, semanticEmbedTimeout)
+	defer cancel()
+	vectors, err := idx.embedder.Embed(ctx, []string{job.text})
+	if err != nil {
+		return err
+	}
+	if len(vectors) != 1 {
+		return fmt.Errorf("%w: got %d vectors for 1 text", ErrEmbedding, len(vectors))
+	}
+	idx.store(job.id, job.text, vectors[0])
+	return nil
+}
+
+// IndexTasks keeps the index up to date with the tasks written through
+// a HookedTaskStore using hooks. Start must be running for written
+// tasks to be embedded.
+func (idx *SemanticIndex) IndexTasks(hooks *Hooks[models.TaskID, *models.Task]) {
+	hooks.AfterCreate(func(_ context.Context, task *models.Task) { idx.putAsync(task) })
+	hooks.AfterUpdate(func(_ context.Context, task *models.Task) { idx.putAsync(task) })
+	hooks.AfterDelete(func(_ context.Context, id models.TaskID) { idx.Remove(id) })
+}
+
+// Rebuild embeds every stored task whose text is not yet embedded, in
+// batches.
+func (idx *SemanticIndex) Rebuild(ctx context.Context, tasks TaskStore) error {
+	all, err := tasks.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	var pending []*models.Task
+	var texts []string
+	for _, task := range all {
+		if text := idx.stage(task); text != "" {
+			pending = append(pending, task)
+			texts = append(texts, text)
+		}
+	}
+
+	for start := 0; start < len(texts); start += semanticEmbedBatch {
+		end := min(start+semanticEmbedBatch, len(texts))
+		vectors, err := idx.embedder.Embed(ctx, texts[start:end])
+		if err != nil {
+			return err
+		}
+		if len(vectors) != end-start {
+			return fmt.Errorf("%w: got %d vectors for %d texts", ErrEmbedding, len(vectors), end-start)
+		}
+		for i, vector := range vectors {
+			idx.store(pending[start+i].ID, texts[start+i], vector)
+		}
+	}
+	return nil
+}
+
+// SemanticMatch is a task found by semantic search. Score is the cosine
+// similarity of the task and the query, at most 1; what counts as
+// related depends on the embedder.
+type SemanticMatch struct {
+	TaskID models.TaskID     `json:"task_id"`
+	Key    string            `json:"key,omitempty"`
+	Title  string            `json:"title"`
+	Status models.TaskStatus `json:"status"`
+	Score  float64           `json:"score"`
+}
+
+// Search returns up to limit tasks the user may see, most similar to
+// the query first, leaving out the tasks of the archived projects.
+// Tasks embedded with vectors of another size, as after switching
+// embedders, are skipped until re-embedded.
+func (idx *SemanticIndex) Search(ctx context.Context, user *models.User, query string, limit int, archived map[models.ProjectID]bool) ([]*SemanticMatch, error) {
+	matches := make([]*SemanticMatch, 0)
+	if strings.TrimSpace(query) == "" || limit <= 0 {
+		return matches, nil
+	}
+	vectors, err := idx.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("%w: got %d vectors for 1 text", ErrEmbedding, len(vectors))
+	}
+	q := normalize(vectors[0])
+	if q == nil {
+		return matches, nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	for _, doc := range idx.docs {
+		if len(doc.vector) != len(q) || archived[doc.task.ProjectID] || !doc.task.IsVisibleTo(user) {
+			continue
+		}
+		var dot float64
+		for i, v := range doc.vector {
+			dot += float64(v) * float64(q[i])
+		}
+		matches = append(matches, &SemanticMatch{
+			TaskID: doc.task.ID,
+			Key:    doc.task.Key,
+			Title:  doc.task.Title,
+			Status: doc.task.Status,
+			Score:  dot,
+		})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].TaskID < matches[j].TaskID
+	})
+	return matches[:min(limit, len(matches))], nil
+}
+
+// SemanticSearchHandler handles HTTP requests for semantic search.
+type SemanticSearchHandler struct {
+	index    *SemanticIndex
+	projects ProjectStore
+}
+
+// NewSemanticSearchHandler creates a new semantic search handler.
+// projects may be nil, in which case no project counts as archived.
+func NewSemanticSearchHandler(index *SemanticIndex, projects ProjectStore) *SemanticSearchHandler {
+	return &SemanticSearchHandler{index: index, projects: projects}
+}
+
+// Search handles GET /search/semantic?q=&limit= requests. The tasks of
+// archived projects are not searched.
+func (h *SemanticSearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	q := strings.TrimSpace(query.Get("q"))
+	if q == "" {
+		http.Error(w, "q is required", http.StatusBadRequest)
+		return
+	}
+	limit := defaultSemanticSearchLimit
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > maxSemanticSearchLimit {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	archived, err := archivedProjects(r.Context(), h.projects)
+	if err != nil {
+		writeServerError(w, r, "failed to list projects", err)
+		return
+	}
+	user, _ := UserFromContext(r.Context())
+	matches, err := h.index.Search(r.Context(), user, q, limit, archived)
+	if err != nil {
+		if errors.Is(err, ErrEmbedding) {
+			http.Error(w, "failed to embed query", http.StatusBadGateway)
+			return
+		}
+		writeServerError(w, r, "failed to search", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, matches)
+}
@@ -0,0 +1,242 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// sentryTimeout bounds each delivery of an event to Sentry.
+	sentryTimeout = 5 * time.Second
+	// sentryQueueSize is how many events may wait to be sent before
+	// further reports are dropped.
+	sentryQueueSize = 256
+)
+
+// ErrInvalidSentryDSN is returned for a DSN that is not of the form
+// https://<key>@<host>/<project>.
+var ErrInvalidSentryDSN = errors.New("invalid sentry dsn")
+
+// SentryConfig configures delivery of error reports to Sentry.
+type SentryConfig struct {
+	DSN         string
+	Environment string
+	Release     string
+	// Client sends events; nil uses a client with a short timeout.
+	Client *http.Client
+	// Workers is how many events are sent concurrently; non-positive
+	// values send one at a time.
+	Workers int
+}
+
+// SentryReporter is an ErrorReporter that sends reports to Sentry's
+// envelope endpoint.
+//
+// Events are queued and sent by background workers, so reporting never
+// delays a response. When the queue is full, as during an outage
+// that fails many requests at once, further reports are logged and
+// dropped; delivery failures are logged and dropped too.
+type SentryReporter struct {
+	endpoint    string
+	auth        string
+	dsn         string
+	environment string
+	release     string
+	client      *http.Client
+	queue       chan *sentryEvent
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+// NewSentryReporter creates a reporter for the project named by the DSN
+// and starts its workers, which run until Close is called.
+//
+// Returns ErrInvalidSentryDSN if the DSN cannot be parsed.
+func NewSentryReporter(config SentryConfig) (*SentryReporter, error) {
+	u, err := url.Parse(config.DSN)
+	if err != nil || u.User == nil || u.User.Username() == "" || u.Host == "" {
+		return nil, ErrInvalidSentryDSN
+	}
+	path := strings.Trim(u.Path, "/")
+	i := strings.LastIndex(path, "/")
+	project, prefix := path[i+1:], ""
+	if i >= 0 {
+		prefix = "/" + path[:i]
+	}
+	if project == "" {
+		return nil, ErrInvalidSentryDSN
+	}
+
+	client := config.Client
+	if client == nil {
+		client = &http.Client{Timeout: sentryTimeout}
+	}
+	s := &SentryReporter{
+		endpoint:    fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, prefix, project),
+		auth:        "Sentry sentry_version=7, sentry_client=tasktracker/1.0, sentry_key=" + u.User.Username(),
+		dsn:         config.DSN,
+		environment: config.Environment,
+		release:     config.Release,
+		client:      client,
+		queue:       make(chan *sentryEvent, sentryQueueSize),
+		done:        make(chan struct{}),
+	}
+	for i := 0; i < max(config.Workers, 1); i++ {
+		go s.work()
+	}
+	return s, nil
+}
+
+// sentryEvent is the subset of Sentry's event payload the reporter sends.
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Exception   sentryExceptions  `json:"exception"`
+	Request     sentryRequest     `json:"request"`
+	User        *sentryUser       `json:"user,omitempty"`
+	Tags        map[string]string `json:"tags"`
+	Extra       map[string]string `json:"extra,omitempty"`
+}
+
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryException struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sentryRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+type sentryUser struct {
+	ID string `json:"id"`
+}
+
+// Report queues a report to be sent to Sentry, dropping it if the
+// queue is full.
+func (s *SentryReporter) Report(ctx context.Context, report *ErrorReport) {
+	event := &sentryEvent{
+		EventID:     strings.ReplaceAll(report.ErrorID, "-", ""),
+		Timestamp:   report.At.UTC().Format(time.RFC3339),
+		Platform:    "go",
+		Level:       "error",
+		Environment: s.environment,
+		Release:     s.release,
+		Exception: sentryExceptions{Values: []sentryException{{
+			Type:  sentryErrorType(report.Err),
+			Value: report.Err.Error(),
+		}}},
+		Request: sentryRequest{Method: report.Method, URL: report.Path},
+		Tags: map[string]string{
+			"error_id": report.ErrorID,
+			"status":   fmt.Sprint(report.Status),
+		},
+	}
+	if report.Panic {
+		event.Level = "fatal"
+		event.Exception.Values[0].Type = "panic"
+		event.Extra = map[string]string{"stack": string(report.Stack)}
+	}
+	if report.RequestID != "" {
+		event.Tags["request_id"] = report.RequestID
+	}
+	if report.UserID != "" {
+		event.User = &sentryUser{ID: report.UserID}
+	}
+
+	select {
+	case s.queue <- event:
+	default:
+		log.Printf("sentry: queue full, dropping report %s", report.ErrorID)
+	}
+}
+
+// Close stops the workers. Events still queued are dropped.
+func (s *SentryReporter) Close() {
+	s.closeOnce.Do(func() { close(s.done) })
+}
+
+// work sends queued events until the reporter is closed.
+func (s *SentryReporter) work() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case event := <-s.queue:
+			if err := s.send(event); err != nil {
+				log.Printf("sentry: reporting %s: %v", event.Tags["error_id"], err)
+			}
+		}
+	}
+}
+
+// sentryErrorType names the type of the innermost error err wraps, so
+// events group by cause rather than by message.
+func sentryErrorType(err error) string {
+	for {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return fmt.Sprintf("%T", err)
+		}
+		err = next
+	}
+}
+
+// send delivers an event as a single-item envelope.
+func (s *SentryReporter) send(event *sentryEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	header, err := json.Marshal(map[string]string{
+		"event_id": event.EventID,
+		"dsn":      s.dsn,
+		"sent_at":  time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	body.Write(header)
+	fmt.Fprintf(&body, "\n{\"type\":\"event\",\"length\":%d}\n", len(payload))
+	body.Write(payload)
+	body.WriteByte('\n')
+
+	ctx, cancel := context.WithTimeout(context.Background(), sentryTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", s.auth)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("sentry responded %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+const (
+	// shareTokenHeader carries a share link token.
+	shareTokenHeader = "X-Share-Token"
+	// shareTokenParam carries a share link token in the query string, so
+	// links can be opened directly.
+	shareTokenParam = "share"
+	// maxShareLinkTTL caps how long a share link may last.
+	maxShareLinkTTL = 90 * 24 * time.Hour
+)
+
+// ShareLinkStore defines the interface for share link storage.
+type ShareLinkStore interface {
+	Store[string, *models.ShareLink]
+	// ListByResource retrieves the links to a project or task.
+	ListByResource(ctx context.Context, scope models.ShareScope, resourceID string) ([]*models.ShareLink, error)
+}
+
+// ErrShareLinkNotFound is returned when a share link is not found.
+var ErrShareLinkNotFound = errors.New("share link not found")
+
+// InMemoryShareLinkStore is an in-memory implementation of ShareLinkStore.
+type InMemoryShareLinkStore struct {
+	*InMemoryStore[string, *models.ShareLink]
+}
+
+// NewInMemoryShareLinkStore creates a new in-memory share link store.
+func NewInMemoryShareLinkStore() *InMemoryShareLinkStore {
+	return &InMemoryShareLinkStore{NewInMemoryStore[string, *models.ShareLink](ErrShareLinkNotFound)}
+}
+
+// ListByResource retrieves the links to a project or task, newest first.
+func (s *InMemoryShareLinkStore) ListByResource(ctx context.Context, scope models.ShareScope, resourceID string) ([]*models.ShareLink, error) {
+	return s.List(ctx, ListOptions[*models.ShareLink]{
+		Filter: func(l *models.ShareLink) bool { return l.Scope == scope && l.ResourceID == resourceID },
+		Less:   func(a, b *models.ShareLink) bool { return a.CreatedAt.After(b.CreatedAt) },
+	})
+}
+
+// ShareLinks signs share link tokens and enforces them on requests.
+//
+// A token is the link's ID and an HMAC-SHA256 of it under the signing
+// key, so forged tokens are rejected without a store lookup. Expiry and
+// revocation are checked against the stored link on every request.
+type ShareLinks struct {
+	key   []byte
+	links ShareLinkStore
+	tasks TaskStore
+	now   func() time.Time
+}
+
+// NewShareLinks creates share link middleware signing tokens with key.
+func NewShareLinks(key []byte, links ShareLinkStore, tasks TaskStore) *ShareLinks {
+	return &ShareLinks{key: key, links: links, tasks: tasks, now: time.Now}
+}
+
+// Token returns the signed token for a link.
+func (s *ShareLinks) Token(link *models.ShareLink) string {
+	return link.ID + "." + base64.RawURLEncoding.EncodeToString(s.sign(link.ID))
+}
+
+// sign returns the signature of a link ID.
+func (s *ShareLinks) sign(id string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(id))
+	return mac.Sum(nil)
+}
+
+// verify returns the link ID of a validly signed token.
+func (s *ShareLinks) verify(token string) (string, bool) {
+	id, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", false
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, s.sign(id)) {
+		return "", false
+	}
+	return id, true
+}
+
+// Middleware authenticates requests carrying a share token as a guest
+// viewer, in the X-Share-Token header or the share query parameter.
+//
+// Such requests may only read the linked project or task: mutations get
+// 403, as do paths outside the link's scope, and invalid, expired or
+// revoked tokens get 401. Requests without a token pass through.
+func (s *ShareLinks) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get(shareTokenHeader)
+		if token == "" {
+			token = r.URL.Query().Get(shareTokenParam)
+		}
+		if token == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		link, err := s.resolve(r.Context(), token)
+		if err != nil {
+			if errors.Is(err, ErrShareLinkNotFound) {
+				http.Error(w, "invalid or expired share link", http.StatusUnauthorized)
+				return
+			}
+			http.Error(w, "failed to check share link", http.StatusInternalServerError)
+			return
+		}
+		if isMutation(r.Method) {
+			http.Error(w, "share links are read-only", http.StatusForbidden)
+			return
+		}
+		ok, err := s.allows(r.Context(), link, r.URL.Path)
+		if err != nil {
+			http.Error(w, "failed to check share link", http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		guest := models.CreateGuest("Shared link")
+		next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), guest)))
+	})
+}
+
+// resolve returns the active link of a token, or ErrShareLinkNotFound.
+func (s *ShareLinks) resolve(ctx context.Context, token string) (*models.ShareLink, error) {
+	id, ok := s.verify(token)
+	if !ok {
+		return nil, ErrShareLinkNotFound
+	}
+	link, err := s.links.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !link.ActiveAt(s.now()) {
+		return nil, ErrShareLinkNotFound
+	}
+	return link, nil
+}
+
+// allows reports whether a link covers a request path: the linked
+// project or task and anything under it, or a task of the linked project.
+func (s *ShareLinks) allows(ctx context.Context, link *models.ShareLink, path string) (bool, error) {
+	collection, id, ok := resourcePath(path)
+	if !ok {
+		return false, nil
+	}
+	switch collection {
+	case "projects":
+		return link.Covers(models.ProjectID(id), ""), nil
+	case "tasks":
+		if link.Covers("", models.TaskID(id)) {
+			return true, nil
+		}
+		if link.Scope != models.ShareScopeProject {
+			return false, nil
+		}
+		task, err := s.tasks.Get(ctx, models.TaskID(id))
+		if err != nil {
+			if errors.Is(err, ErrTaskNotFound) {
+				return false, nil
+			}
+			return false, err
+		}
+		return link.Covers(task.ProjectID, task.ID), nil
+	}
+	return false, nil
+}
+
+// resourcePath splits a path such as /tasks/{id}/comments into its
+// collection and resource ID.
+func resourcePath(path string) (collection, id string, ok bool) {
+	parts := strings.SplitN(strings.Trim(path, "/"), "/", 3)
+	if len(parts) < 2 || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// ShareLinkHandler handles HTTP requests for managing share links.
+type ShareLinkHandler struct {
+	shares *ShareLinks
+	links  ShareLinkStore
+}
+
+// NewShareLinkHandler creates a new share link handler.
+func NewShareLinkHandler(shares *ShareLinks, links ShareLinkStore) *ShareLinkHandler {
+	return &ShareLinkHandler{shares: shares, links: links}
+}
+
+// CreateShareLinkRequest is the request body for creating a share link.
+type CreateShareLinkRequest struct {
+	Scope      models.ShareScope `json:"scope"`
+	ResourceID string            `json:"resource_id"`
+	TTLHours   int               `json:"ttl_hours"`
+}
+
+// ShareLinkResponse is a share link with its token. The token is only
+// returned when the link is created.
+type ShareLinkResponse struct {
+	*models.ShareLink
+	Token string `json:"token,omitempty"`
+}
+
+// Create handles POST /share-links requests.
+func (h *ShareLinkHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if !requireManage(w, r) {
+		return
+	}
+	caller, _ := UserFromContext(r.Context())
+
+	var req CreateShareLinkRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	ttl := time.Duration(req.TTLHours) * time.Hour
+	if ttl > maxShareLinkTTL {
+		http.Error(w, "ttl_hours exceeds the maximum of 2160", http.StatusBadRequest)
+		return
+	}
+
+	link, err := models.NewShareLink(req.Scope, req.ResourceID, caller.ID, ttl)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.links.Create(r.Context(), link); err != nil {
+		writeServerError(w, r, "failed to create share link", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, &ShareLinkResponse{ShareLink: link, Token: h.shares.Token(link)})
+}
+
+// List handles GET /share-links?scope=...&resource_id=... requests.
+func (h *ShareLinkHandler) List(w http.ResponseWriter, r *http.Request) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	q := r.URL.Query()
+	links, err := h.links.ListByResource(r.Context(), models.ShareScope(q.Get("scope")), q.Get("resource_id"))
+	if err != nil {
+		writeServerError(w, r, "failed to list share links", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, links)
+}
+
+// Revoke handles DELETE /share-links/{id} requests. Revoked links are
+// kept so their history can be audited.
+func (h *ShareLinkHandler) Revoke(w http.ResponseWriter, r *http.Request, id string) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	existing, err := h.links.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrShareLinkNotFound) {
+			http.Error(w, "share link not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get share link", err)
+		return
+	}
+
+	link := *existing
+	link.Revoke()
+	if err := h.links.Update(r.Context(), &link); err != nil {
+		writeServerError(w, r, "failed to revoke share link", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+func TestShareLinksMiddleware(t *testing.T) {
+	ctx := context.Background()
+	tasks := NewInMemoryTaskStore()
+	links := NewInMemoryShareLinkStore()
+	shares := NewShareLinks([]byte("test-key"), links, tasks)
+
+	inProject := models.NewTask("Shared", "web")
+	otherProject := models.NewTask("Private", "billing")
+	for _, task := range []*models.Task{inProject, otherProject} {
+		if err := tasks.Create(ctx, task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	newLink := func(scope models.ShareScope, resourceID string, change func(*models.ShareLink)) string {
+		t.Helper()
+		link, err := models.NewShareLink(scope, resourceID, "owner", time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if change != nil {
+			change(link)
+		}
+		if err := links.Create(ctx, link); err != nil {
+			t.Fatal(err)
+		}
+		return shares.Token(link)
+	}
+	projectToken := newLink(models.ShareScopeProject, "web", nil)
+	taskToken := newLink(models.ShareScopeTask, string(inProject.ID), nil)
+	expiredToken := newLink(models.ShareScopeProject, "web", func(l *models.ShareLink) {
+		l.ExpiresAt = time.Now().Add(-time.Minute)
+	})
+	revokedToken := newLink(models.ShareScopeProject, "web", func(l *models.ShareLink) { l.Revoke() })
+
+	unsaved, err := models.NewShareLink(models.ShareScopeProject, "web", "owner", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey := NewShareLinks([]byte("other-key"), links, tasks)
+	forgedToken := otherKey.Token(unsaved)
+	resignedToken := otherKey.Token(&models.ShareLink{ID: projectToken[:36]})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		query  bool
+		want   int
+	}{
+		{name: "no token passes through", method: http.MethodPost, path: "/tasks", want: http.StatusOK},
+		{name: "project link reads project", method: http.MethodGet, path: "/projects/web", token: projectToken, want: http.StatusOK},
+		{name: "project link reads its task", method: http.MethodGet, path: "/tasks/" + string(inProject.ID) + "/comments", token: projectToken, want: http.StatusOK},
+		{name: "token in query", method: http.MethodGet, path: "/projects/web", token: projectToken, query: true, want: http.StatusOK},
+		{name: "task link reads task", method: http.MethodGet, path: "/tasks/" + string(inProject.ID), token: taskToken, want: http.StatusOK},
+		{name: "project link escapes to other project", method: http.MethodGet, path: "/projects/billing", token: projectToken, want: http.StatusForbidden},
+		{name: "project link escapes to other project's task", method: http.MethodGet, path: "/tasks/" + string(otherProject.ID), token: projectToken, want: http.StatusForbidden},
+		{name: "project link reads unknown task", method: http.MethodGet, path: "/tasks/missing", token: projectToken, want: http.StatusForbidden},
+		{name: "task link escapes to other task", method: http.MethodGet, path: "/tasks/" + string(otherProject.ID), token: taskToken, want: http.StatusForbidden},
+		{name: "task link escapes to its project", method: http.MethodGet, path: "/projects/web", token: taskToken, want: http.StatusForbidden},
+		{name: "link escapes to other collection", method: http.MethodGet, path: "/users/owner", token: projectToken, want: http.StatusForbidden},
+		{name: "link escapes to collection root", method: http.MethodGet, path: "/tasks", token: projectToken, want: http.StatusForbidden},
+		{name: "mutation", method: http.MethodPost, path: "/tasks/" + string(inProject.ID) + "/comments", token: taskToken, want: http.StatusForbidden},
+		{name: "forged signature", method: http.MethodGet, path: "/projects/web", token: forgedToken, want: http.StatusUnauthorized},
+		{name: "stored link signed with other key", method: http.MethodGet, path: "/projects/web", token: resignedToken, want: http.StatusUnauthorized},
+		{name: "tampered signature", method: http.MethodGet, path: "/projects/web", token: projectToken + "x", want: http.StatusUnauthorized},
+		{name: "malformed token", method: http.MethodGet, path: "/projects/web", token: "not-a-token", want: http.StatusUnauthorized},
+		{name: "expired link", method: http.MethodGet, path: "/projects/web", token: expiredToken, want: http.StatusUnauthorized},
+		{name: "revoked link", method: http.MethodGet, path: "/projects/web", token: revokedToken, want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var guest *models.User
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				guest, _ = UserFromContext(r.Context())
+			})
+
+			target := tt.path
+			if tt.query {
+				target += "?" + shareTokenParam + "=" + tt.token
+			}
+			req := httptest.NewRequest(tt.method, target, nil)
+			if tt.token != "" && !tt.query {
+				req.Header.Set(shareTokenHeader, tt.token)
+			}
+			w := httptest.NewRecorder()
+
+			shares.Middleware(next).ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+			if tt.want == http.StatusOK && tt.token != "" && (guest == nil || guest.Role != models.UserRoleViewer) {
+				t.Fatalf("request not made as a guest: %+v", guest)
+			}
+		})
+	}
+}
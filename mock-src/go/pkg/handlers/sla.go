@@ -0,0 +1,287 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// SLAStore defines the interface for per-project SLA storage.
+type SLAStore interface {
+	// Get retrieves the SLA attached to a project.
+	Get(ctx context.Context, projectID models.ProjectID) (*models.SLA, error)
+	// Put attaches an SLA to its project, replacing any existing one.
+	Put(ctx context.Context, sla *models.SLA) error
+	// Delete detaches the SLA from a project.
+	Delete(ctx context.Context, projectID models.ProjectID) error
+}
+
+// ErrSLANotFound is returned when a project has no SLA.
+var ErrSLANotFound = errors.New("SLA not found")
+
+// InMemorySLAStore is an in-memory implementation of SLAStore.
+type InMemorySLAStore struct {
+	mu   sync.RWMutex
+	slas map[models.ProjectID]*models.SLA
+}
+
+// NewInMemorySLAStore creates a new in-memory SLA store.
+func NewInMemorySLAStore() *InMemorySLAStore {
+	return &InMemorySLAStore{
+		slas: make(map[models.ProjectID]*models.SLA),
+	}
+}
+
+// Get retrieves the SLA attached to a project.
+func (s *InMemorySLAStore) Get(ctx context.Context, projectID models.ProjectID) (*models.SLA, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sla, ok := s.slas[projectID]
+	if !ok {
+		return nil, ErrSLANotFound
+	}
+	return sla, nil
+}
+
+// Put attaches an SLA to its project, replacing any existing one.
+func (s *InMemorySLAStore) Put(ctx context.Context, sla *models.SLA) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.slas[sla.ProjectID] = sla
+	return nil
+}
+
+// Delete detaches the SLA from a project.
+func (s *InMemorySLAStore) Delete(ctx context.Context, projectID models.ProjectID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.slas[projectID]; !ok {
+		return ErrSLANotFound
+	}
+	delete(s.slas, projectID)
+	return nil
+}
+
+// evaluateSLA returns the SLA status of a task, or nil if its project has no SLA.
+//
+// An SLA without its own business hours uses the project's calendar, if
+// projects is non-nil and the project has one.
+func evaluateSLA(ctx context.Context, slas SLAStore, projects ProjectStore, task *models.Task, at time.Time) (*models.SLAStatus, error) {
+	sla, err := slas.Get(ctx, task.ProjectID)
+	if err != nil {
+		if errors.Is(err, ErrSLANotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if sla.BusinessHours == nil {
+		calendar, err := projectCalendar(ctx, projects, task.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if calendar != nil {
+			withCalendar := *sla
+			withCalendar.BusinessHours = calendar
+			sla = &withCalendar
+		}
+	}
+	return sla.Evaluate(task, at), nil
+}
+
+// SLAMonitor periodically evaluates SLAs and publishes a breach event the
+// first time each timer of a task is breached.
+type SLAMonitor struct {
+	slas     SLAStore
+	tasks    TaskStore
+	projects ProjectStore
+	bus      *EventBus
+	now      func() time.Time
+
+	mu       sync.Mutex
+	breached map[string]bool
+}
+
+// NewSLAMonitor creates a new SLA monitor publishing to bus.
+//
+// projects may be nil if project calendars are not used.
+func NewSLAMonitor(slas SLAStore, tasks TaskStore, projects ProjectStore, bus *EventBus) *SLAMonitor {
+	return &SLAMonitor{
+		slas:     slas,
+		tasks:    tasks,
+		projects: projects,
+		bus:      bus,
+		now:      time.Now,
+		breached: make(map[string]bool),
+	}
+}
+
+// Run evaluates every task once and returns the number of new breaches.
+func (m *SLAMonitor) Run(ctx context.Context) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tasks, err := m.tasks.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := m.now()
+	found := 0
+	for _, task := range tasks {
+		status, err := evaluateSLA(ctx, m.slas, m.projects, task, now)
+		if err != nil {
+			return found, err
+		}
+		if status == nil {
+			continue
+		}
+		for kind, timer := range map[string]*models.SLATimer{"respond": status.Respond, "resolve": status.Resolve} {
+			key := string(task.ID) + "/" + kind
+			if timer == nil || timer.State != models.SLAStateBreached || m.breached[key] {
+				continue
+			}
+			m.breached[key] = true
+			found++
+			m.bus.Publish(ctx, &Event{
+				Type:      EventSLABreached,
+				TaskID:    task.ID,
+				ProjectID: task.ProjectID,
+				Data:      map[string]any{"timer": kind, "due": timer.Due},
+			})
+		}
+	}
+	return found, nil
+}
+
+// Start runs the monitor every interval until ctx is cancelled, skipping
+// runs while mode, which may be nil, is enabled. It returns
+// ErrInvalidInterval, without starting, if interval is not positive.
+func (m *SLAMonitor) Start(ctx context.Context, interval time.Duration, mode *MaintenanceMode) error {
+	if interval <= 0 {
+		return ErrInvalidInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if mode.Enabled() {
+					continue
+				}
+				if _, err := m.Run(ctx); err != nil {
+					log.Printf("sla: run failed: %v", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// SLAHandler handles HTTP requests for project SLAs.
+type SLAHandler struct {
+	slas     SLAStore
+	tasks    TaskStore
+	projects ProjectStore
+}
+
+// NewSLAHandler creates a new SLA handler.
+//
+// projects may be nil if project calendars are not used.
+func NewSLAHandler(slas SLAStore, tasks TaskStore, projects ProjectStore) *SLAHandler {
+	return &SLAHandler{slas: slas, tasks: tasks, projects: projects}
+}
+
+// Get handles GET /projects/{id}/sla requests.
+func (h *SLAHandler) Get(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	sla, err := h.slas.Get(r.Context(), projectID)
+	if err != nil {
+		if errors.Is(err, ErrSLANotFound) {
+			http.Error(w, "project has no SLA", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get SLA", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sla)
+}
+
+// Put handles PUT /projects/{id}/sla requests.
+func (h *SLAHandler) Put(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var sla models.SLA
+	if err := decodeJSON(w, r, &sla); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	sla.ProjectID = projectID
+	if err := sla.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.slas.Put(r.Context(), &sla); err != nil {
+		writeServerError(w, r, "failed to save SLA", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &sla)
+}
+
+// Delete handles DELETE /projects/{id}/sla requests.
+func (h *SLAHandler) Delete(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	if err := h.slas.Delete(r.Context(), projectID); err != nil {
+		if errors.Is(err, ErrSLANotFound) {
+			http.Error(w, "project has no SLA", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to delete SLA", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TaskStatus handles GET /tasks/{id}/sla requests.
+func (h *SLAHandler) TaskStatus(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	task, err := h.tasks.Get(r.Context(), taskID)
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			http.Error(w, "task not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get task", err)
+		return
+	}
+
+	status, err := evaluateSLA(r.Context(), h.slas, h.projects, task, time.Now())
+	if err != nil {
+		writeServerError(w, r, "failed to evaluate SLA", err)
+		return
+	}
+	if status == nil {
+		http.Error(w, "project has no SLA", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
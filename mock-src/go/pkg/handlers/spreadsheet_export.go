@@ -0,0 +1,205 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+	"github.com/example/tasktracker/pkg/xlsx"
+)
+
+// xlsxContentType is the media type of .xlsx workbooks.
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// SpreadsheetExportHandler exports projects as Excel workbooks.
+type SpreadsheetExportHandler struct {
+	projects ProjectStore
+	tasks    TaskStore
+	users    UserStore
+	schemes  PrioritySchemeStore
+}
+
+// NewSpreadsheetExportHandler creates a new spreadsheet export handler.
+func NewSpreadsheetExportHandler(projects ProjectStore, tasks TaskStore, users UserStore, schemes PrioritySchemeStore) *SpreadsheetExportHandler {
+	return &SpreadsheetExportHandler{projects: projects, tasks: tasks, users: users, schemes: schemes}
+}
+
+// Export handles GET /projects/{id}/export.xlsx requests.
+//
+// The workbook has three sheets: every task, task counts per status,
+// and the open workload per assignee. Dates are in the caller's time
+// zone. Drafts are left out. Demo requests get the project and assignee
+// names faked.
+func (h *SpreadsheetExportHandler) Export(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	project, err := h.projects.Get(r.Context(), projectID)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			http.Error(w, "project not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get project", err)
+		return
+	}
+	all, err := h.tasks.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+	users, err := h.users.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list users", err)
+		return
+	}
+	scheme, err := projectPriorityScheme(r.Context(), h.schemes, projectID)
+	if err != nil {
+		writeServerError(w, r, "failed to get priority scheme", err)
+		return
+	}
+	if faker := demoFakerFromContext(r.Context()); faker != nil {
+		project, users = demoProject(faker, project), demoUsers(faker, users)
+	}
+
+	tasks := make([]*models.Task, 0, len(all))
+	for _, task := range publishedTasks(all) {
+		if task.ProjectID == projectID {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].CreatedAt.Before(tasks[j].CreatedAt) })
+
+	wb, err := buildProjectWorkbook(projectID, tasks, users, scheme, time.Now(), DateFormatterFromContext(r.Context()).Location())
+	if err != nil {
+		writeServerError(w, r, "failed to build workbook", err)
+		return
+	}
+
+	name := fmt.Sprintf("%s-%s.xlsx", exportFileName(project.Name), time.Now().UTC().Format("20060102"))
+	w.Header().Set("Content-Type", xlsxContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	w.WriteHeader(http.StatusOK)
+	if err := wb.Write(w); err != nil {
+		log.Printf("export: failed to write workbook: %v", err)
+	}
+}
+
+// buildProjectWorkbook lays out the tasks, status summary and workload
+// sheets for a project's tasks.
+func buildProjectWorkbook(projectID models.ProjectID, tasks []*models.Task, users []*models.User, scheme *models.PriorityScheme, at time.Time, loc *time.Location) (*xlsx.Workbook, error) {
+	names := make(map[models.UserID]string, len(users))
+	for _, u := range users {
+		names[u.ID] = u.DisplayName
+	}
+	userName := func(id models.UserID) string {
+		if name, ok := names[id]; ok && name != "" {
+			return name
+		}
+		return string(id)
+	}
+	priorities := make(map[models.TaskPriority]string, len(scheme.Levels))
+	for _, level := range scheme.Levels {
+		priorities[level.Rank] = level.Name
+	}
+
+	wb := xlsx.New()
+	sheet, err := wb.AddSheet("Tasks")
+	if err != nil {
+		return nil, err
+	}
+	sheet.SetHeader("Reference", "Title", "Status", "Priority", "Assignee", "Due", "Overdue", "Estimate", "Tags", "Created", "Updated")
+	for _, task := range tasks {
+		var assignee, due any
+		if task.AssigneeID != nil {
+			assignee = userName(*task.AssigneeID)
+		}
+		if task.DueDate != nil {
+			due = xlsx.Date(task.DueDate.In(loc))
+		}
+		priority, ok := priorities[task.Priority]
+		if !ok {
+			priority = fmt.Sprint(int(task.Priority))
+		}
+		sheet.AddRow(
+			task.Reference(),
+			task.Title,
+			statusLabel(task.Status),
+			priority,
+			assignee,
+			due,
+			task.IsOverdueAt(at),
+			task.Estimate,
+			strings.Join(task.Tags, ", "),
+			task.CreatedAt.In(loc),
+			task.UpdatedAt.In(loc),
+		)
+	}
+
+	sheet, err = wb.AddSheet("By status")
+	if err != nil {
+		return nil, err
+	}
+	sheet.SetHeader("Status", "Tasks", "Share", "Overdue", "Estimate")
+	type statusRow struct {
+		count, overdue int
+		estimate       float64
+	}
+	byStatus := make(map[models.TaskStatus]*statusRow)
+	var statuses []models.TaskStatus
+	for _, task := range tasks {
+		row, ok := byStatus[task.Status]
+		if !ok {
+			row = &statusRow{}
+			byStatus[task.Status] = row
+			statuses = append(statuses, task.Status)
+		}
+		row.count++
+		row.estimate += task.Estimate
+		if task.IsOverdueAt(at) {
+			row.overdue++
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return byStatus[statuses[i]].count > byStatus[statuses[j]].count })
+	for _, status := range statuses {
+		row := byStatus[status]
+		sheet.AddRow(statusLabel(status), row.count, xlsx.Percent(float64(row.count)/float64(len(tasks))), row.overdue, row.estimate)
+	}
+
+	sheet, err = wb.AddSheet("Workload")
+	if err != nil {
+		return nil, err
+	}
+	sheet.SetHeader("Assignee", "Open tasks", "Estimate", "Due soon", "Overdue", "Overloaded")
+	for _, load := range computeWorkload(projectID, tasks, users, defaultDueSoonDays*24*time.Hour, at) {
+		assignee := "Unassigned"
+		if load.UserID != "" {
+			assignee = userName(load.UserID)
+		}
+		sheet.AddRow(assignee, load.OpenTasks, load.Estimate, load.DueSoon, load.Overdue, load.Overloaded)
+	}
+	return wb, nil
+}
+
+// exportFileName reduces a name to characters safe in a download file
+// name, such as "q3-launch" for "Q3 Launch!".
+func exportFileName(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	if s := strings.TrimSuffix(b.String(), "-"); s != "" {
+		return s
+	}
+	return "project"
+}
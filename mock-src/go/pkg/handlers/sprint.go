@@ -0,0 +1,375 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// SprintStore defines the interface for sprint storage.
+type SprintStore interface {
+	Store[string, *models.Sprint]
+	// ListByProject retrieves the sprints of a project.
+	ListByProject(ctx context.Context, projectID models.ProjectID) ([]*models.Sprint, error)
+}
+
+// ErrSprintNotFound is returned when a sprint is not found.
+var ErrSprintNotFound = errors.New("sprint not found")
+
+// InMemorySprintStore is an in-memory implementation of SprintStore.
+type InMemorySprintStore struct {
+	*InMemoryStore[string, *models.Sprint]
+}
+
+// NewInMemorySprintStore creates a new in-memory sprint store.
+func NewInMemorySprintStore() *InMemorySprintStore {
+	return &InMemorySprintStore{NewInMemoryStore[string, *models.Sprint](ErrSprintNotFound)}
+}
+
+// ListByProject retrieves the sprints of a project in start order.
+func (s *InMemorySprintStore) ListByProject(ctx context.Context, projectID models.ProjectID) ([]*models.Sprint, error) {
+	return s.List(ctx, ListOptions[*models.Sprint]{
+		Filter: func(sp *models.Sprint) bool { return sp.ProjectID == projectID },
+		Less:   func(a, b *models.Sprint) bool { return a.StartDate.Before(b.StartDate) },
+	})
+}
+
+// MemberPlan is one member's share of a sprint plan. An empty UserID
+// collects the unassigned tasks, which have no capacity.
+//
+// Utilization is Committed over Capacity and is omitted when the member
+// has no capacity in the sprint.
+type MemberPlan struct {
+	UserID       models.UserID `json:"user_id"`
+	Capacity     float64       `json:"capacity"`
+	Committed    float64       `json:"committed"`
+	Remaining    float64       `json:"remaining"`
+	Tasks        int           `json:"tasks"`
+	Utilization  *float64      `json:"utilization,omitempty"`
+	OverCapacity bool          `json:"over_capacity"`
+}
+
+// SprintPlan is the response body for a sprint's planning view.
+//
+// Committed sums the estimates of every planned task that is not
+// cancelled; Unestimated counts the planned tasks without an estimate.
+// Warnings flag members planned beyond their capacity and other gaps
+// in the plan; they are also sent as Warning headers when the plan is
+// changed.
+type SprintPlan struct {
+	Sprint      *models.Sprint `json:"sprint"`
+	Capacity    float64        `json:"capacity"`
+	Committed   float64        `json:"committed"`
+	Unestimated int            `json:"unestimated"`
+	Members     []*MemberPlan  `json:"members"`
+	Warnings    []string       `json:"warnings"`
+}
+
+// computeSprintPlan sums a sprint's planned tasks against its members'
+// capacities. Drafts and tasks missing from tasks are left out.
+func computeSprintPlan(sprint *models.Sprint, tasks []*models.Task) *SprintPlan {
+	plan := &SprintPlan{Sprint: sprint, Members: make([]*MemberPlan, 0, len(sprint.Capacities)), Warnings: make([]string, 0)}
+	byUser := make(map[models.UserID]*MemberPlan, len(sprint.Capacities))
+	for _, c := range sprint.Capacities {
+		member := &MemberPlan{UserID: c.UserID, Capacity: c.Capacity}
+		byUser[c.UserID] = member
+		plan.Members = append(plan.Members, member)
+		plan.Capacity += c.Capacity
+	}
+
+	for _, task := range tasks {
+		if task.Draft || task.Status == models.TaskStatusCancelled || !sprint.HasTask(task.ID) {
+			continue
+		}
+		var userID models.UserID
+		if task.AssigneeID != nil {
+			userID = *task.AssigneeID
+		}
+		member, ok := byUser[userID]
+		if !ok {
+			member = &MemberPlan{UserID: userID}
+			byUser[userID] = member
+			plan.Members = append(plan.Members, member)
+			if userID != "" {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s has planned tasks but no capacity in the sprint", userID))
+			}
+		}
+		member.Tasks++
+		member.Committed += task.Estimate
+		plan.Committed += task.Estimate
+		if task.Estimate == 0 {
+			plan.Unestimated++
+		}
+	}
+
+	for _, member := range plan.Members {
+		if member.UserID == "" {
+			continue
+		}
+		member.Remaining = member.Capacity - member.Committed
+		if member.Capacity > 0 {
+			utilization := member.Committed / member.Capacity
+			member.Utilization = &utilization
+		}
+		if member.Committed > member.Capacity {
+			member.OverCapacity = true
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s is planned for %g %s but has capacity for %g",
+				member.UserID, member.Committed, sprint.Unit, member.Capacity))
+		}
+	}
+	if plan.Committed > plan.Capacity {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("sprint is planned for %g %s but has capacity for %g",
+			plan.Committed, sprint.Unit, plan.Capacity))
+	}
+	if plan.Unestimated > 0 {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("%d planned tasks have no estimate", plan.Unestimated))
+	}
+	sort.SliceStable(plan.Members, func(i, j int) bool {
+		return plan.Members[i].UserID != "" && plan.Members[j].UserID == ""
+	})
+	return plan
+}
+
+// SprintHandler handles HTTP requests for sprints and sprint planning.
+type SprintHandler struct {
+	sprints SprintStore
+	tasks   TaskStore
+}
+
+// NewSprintHandler creates a new sprint handler.
+func NewSprintHandler(sprints SprintStore, tasks TaskStore) *SprintHandler {
+	return &SprintHandler{sprints: sprints, tasks: tasks}
+}
+
+// SprintRequest is the request body for creating or updating a sprint.
+// An empty Unit keeps hours for a new sprint and the current unit on
+// update.
+type SprintRequest struct {
+	Name       string                  `json:"name"`
+	StartDate  time.Time               `json:"start_date"`
+	EndDate    time.Time               `json:"end_date"`
+	Unit       models.EstimateUnit     `json:"unit,omitempty"`
+	Capacities []models.MemberCapacity `json:"capacities"`
+}
+
+// apply copies the request's fields onto a sprint.
+func (req *SprintRequest) apply(sprint *models.Sprint) {
+	sprint.Name = req.Name
+	sprint.StartDate = req.StartDate
+	sprint.EndDate = req.EndDate
+	if req.Unit != "" {
+		sprint.Unit = req.Unit
+	}
+	sprint.Capacities = append(make([]models.MemberCapacity, 0, len(req.Capacities)), req.Capacities...)
+	sprint.UpdatedAt = time.Now()
+}
+
+// getSprint writes an error and returns nil unless the sprint exists.
+func (h *SprintHandler) getSprint(w http.ResponseWriter, r *http.Request, id string) *models.Sprint {
+	sprint, err := h.sprints.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrSprintNotFound) {
+			http.Error(w, "sprint not found", http.StatusNotFound)
+			return nil
+		}
+		writeServerError(w, r, "failed to get sprint", err)
+		return nil
+	}
+	return sprint
+}
+
+// List handles GET /projects/{id}/sprints requests.
+func (h *SprintHandler) List(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	sprints, err := h.sprints.ListByProject(r.Context(), projectID)
+	if err != nil {
+		writeServerError(w, r, "failed to list sprints", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sprints)
+}
+
+// Create handles POST /projects/{id}/sprints requests.
+func (h *SprintHandler) Create(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req SprintRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	sprint := models.NewSprint(projectID, req.Name, req.StartDate, req.EndDate)
+	req.apply(sprint)
+	if err := sprint.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.sprints.Create(r.Context(), sprint); err != nil {
+		writeServerError(w, r, "failed to create sprint", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, sprint)
+}
+
+// Update handles PUT /sprints/{id} requests. The planned tasks are
+// kept; change them through the plan.
+func (h *SprintHandler) Update(w http.ResponseWriter, r *http.Request, id string) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req SprintRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	existing := h.getSprint(w, r, id)
+	if existing == nil {
+		return
+	}
+	sprint := *existing
+	req.apply(&sprint)
+	if err := sprint.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.sprints.Update(r.Context(), &sprint); err != nil {
+		writeServerError(w, r, "failed to update sprint", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &sprint)
+}
+
+// Delete handles DELETE /sprints/{id} requests.
+func (h *SprintHandler) Delete(w http.ResponseWriter, r *http.Request, id string) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	if err := h.sprints.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, ErrSprintNotFound) {
+			http.Error(w, "sprint not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to delete sprint", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Plan handles GET /sprints/{id}/plan requests, comparing the planned
+// work with each member's capacity.
+func (h *SprintHandler) Plan(w http.ResponseWriter, r *http.Request, id string) {
+	sprint := h.getSprint(w, r, id)
+	if sprint == nil {
+		return
+	}
+	tasks, err := h.tasks.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, computeSprintPlan(sprint, tasks))
+}
+
+// SprintPlanRequest is the request body for planning a sprint. Nil
+// Capacities keeps the current ones.
+type SprintPlanRequest struct {
+	TaskIDs    []models.TaskID         `json:"task_ids"`
+	Capacities []models.MemberCapacity `json:"capacities,omitempty"`
+}
+
+// SetPlan handles PUT /sprints/{id}/plan requests, replacing the tasks
+// planned for the sprint and returning the new plan.
+//
+// Every task must belong to the sprint's project and may be planned in
+// only one sprint that has not ended; otherwise the request fails with
+// 400 or 409. Planning beyond capacity is allowed but answered with
+// Warning headers.
+func (h *SprintHandler) SetPlan(w http.ResponseWriter, r *http.Request, id string) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req SprintPlanRequest
+	if err := decodeJSONLimit(w, r, &req, maxBulkRequestBodyBytes); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	existing := h.getSprint(w, r, id)
+	if existing == nil {
+		return
+	}
+	sprint := *existing
+	sprint.TaskIDs = append(make([]models.TaskID, 0, len(req.TaskIDs)), req.TaskIDs...)
+	if req.Capacities != nil {
+		sprint.Capacities = append(make([]models.MemberCapacity, 0, len(req.Capacities)), req.Capacities...)
+	}
+	sprint.UpdatedAt = time.Now()
+	if err := sprint.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tasks, err := h.tasks.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+	inProject := make(map[models.TaskID]bool)
+	for _, task := range publishedTasks(tasks) {
+		if task.ProjectID == sprint.ProjectID {
+			inProject[task.ID] = true
+		}
+	}
+	for _, taskID := range sprint.TaskIDs {
+		if !inProject[taskID] {
+			http.Error(w, "task "+string(taskID)+" is not in the project", http.StatusBadRequest)
+			return
+		}
+	}
+
+	others, err := h.sprints.ListByProject(r.Context(), sprint.ProjectID)
+	if err != nil {
+		writeServerError(w, r, "failed to list sprints", err)
+		return
+	}
+	now := time.Now()
+	for _, other := range others {
+		if other.ID == sprint.ID || other.HasEnded(now) {
+			continue
+		}
+		for _, taskID := range sprint.TaskIDs {
+			if other.HasTask(taskID) {
+				http.Error(w, fmt.Sprintf("task %s is already planned in sprint %q", taskID, other.Name), http.StatusConflict)
+				return
+			}
+		}
+	}
+
+	if err := h.sprints.Update(r.Context(), &sprint); err != nil {
+		writeServerError(w, r, "failed to update sprint", err)
+		return
+	}
+
+	plan := computeSprintPlan(&sprint, tasks)
+	writeWarnings(w, plan.Warnings)
+	writeJSON(w, http.StatusOK, plan)
+}
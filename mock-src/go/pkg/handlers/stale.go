@@ -0,0 +1,257 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// ErrInvalidInterval is returned when a background job is started with
+// an interval that is not positive.
+var ErrInvalidInterval = errors.New("interval must be positive")
+
+// StaleReport summarizes one run of the stale task job.
+type StaleReport struct {
+	RunAt    time.Time       `json:"run_at"`
+	Stale    []models.TaskID `json:"stale"`
+	Notified int             `json:"notified"`
+	Moved    []models.TaskID `json:"moved"`
+}
+
+// StaleJob periodically flags open tasks that have gone without updates
+// under its StalePolicy, nudging assignees and optionally moving the
+// tasks to a triage status.
+//
+// Each task is nudged once per stale spell: an update to the task
+// starts a new spell. The job remembers nudges while their tasks stay
+// stale. Tasks are moved to triage only where their project's workflow
+// allows it, and tasks of archived projects are left alone.
+type StaleJob struct {
+	tasks         TaskStore
+	projects      ProjectStore
+	workflows     WorkflowStore
+	notifications NotificationStore
+	now           func() time.Time
+
+	mu      sync.Mutex
+	policy  *models.StalePolicy
+	nudged  map[models.TaskID]time.Time
+	running sync.Mutex
+}
+
+// NewStaleJob creates a new stale task job. A nil policy uses
+// models.DefaultStalePolicy. projects may be nil, in which case no
+// project counts as archived, and workflows may be nil, in which case
+// every project uses the default workflow.
+func NewStaleJob(policy *models.StalePolicy, tasks TaskStore, projects ProjectStore, workflows WorkflowStore, notifications NotificationStore) *StaleJob {
+	if policy == nil {
+		policy = models.DefaultStalePolicy()
+	}
+	return &StaleJob{
+		tasks:         tasks,
+		projects:      projects,
+		workflows:     workflows,
+		notifications: notifications,
+		now:           time.Now,
+		policy:        policy,
+		nudged:        make(map[models.TaskID]time.Time),
+	}
+}
+
+// Policy returns the current policy. It must not be modified.
+func (j *StaleJob) Policy() *models.StalePolicy {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.policy
+}
+
+// SetPolicy replaces the policy after validating it.
+func (j *StaleJob) SetPolicy(policy *models.StalePolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.policy = policy
+	return nil
+}
+
+// IsStale reports whether a task is stale under the current policy.
+func (j *StaleJob) IsStale(task *models.Task) bool {
+	return j.Policy().IsStale(task, j.now())
+}
+
+// Run checks every task once.
+func (j *StaleJob) Run(ctx context.Context) (*StaleReport, error) {
+	j.running.Lock()
+	defer j.running.Unlock()
+
+	policy := j.Policy()
+	now := j.now()
+	report := &StaleReport{RunAt: now, Stale: make([]models.TaskID, 0), Moved: make([]models.TaskID, 0)}
+
+	tasks, err := j.tasks.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	archived, err := archivedProjects(ctx, j.projects)
+	if err != nil {
+		return nil, err
+	}
+	stale := make(map[models.TaskID]bool)
+	for _, task := range tasks {
+		if archived[task.ProjectID] || !policy.IsStale(task, now) {
+			continue
+		}
+		report.Stale = append(report.Stale, task.ID)
+		stale[task.ID] = true
+
+		if policy.Notify && task.AssigneeID != nil && !j.nudged[task.ID].Equal(task.UpdatedAt) {
+			n := models.NewNotification(*task.AssigneeID, models.NotificationStale, task.ID, "")
+			n.Message = fmt.Sprintf("task %q has had no updates for %d days", task.Title, int(now.Sub(task.UpdatedAt).Hours()/24))
+			if err := j.notifications.Create(ctx, n); err != nil {
+				return report, err
+			}
+			j.nudged[task.ID] = task.UpdatedAt
+			report.Notified++
+		}
+
+		if policy.TriageStatus != "" {
+			moved, err := j.triage(ctx, task, policy, now)
+			if err != nil {
+				return report, err
+			}
+			if moved {
+				report.Moved = append(report.Moved, task.ID)
+			}
+		}
+	}
+	j.prune(stale)
+	return report, nil
+}
+
+// triage moves a stale task to the policy's triage status through the
+// store, if its project's workflow allows the move. It reports whether
+// the task moved.
+func (j *StaleJob) triage(ctx context.Context, task *models.Task, policy *models.StalePolicy, now time.Time) (bool, error) {
+	workflow := models.DefaultWorkflow(task.ProjectID)
+	if j.workflows != nil {
+		var err error
+		if workflow, err = projectWorkflow(ctx, j.workflows, task.ProjectID); err != nil {
+			return false, err
+		}
+	}
+	if workflow.CheckTransition(task.Status, policy.TriageStatus) != nil {
+		return false, nil
+	}
+
+	moved := false
+	_, err := updateTask(ctx, j.tasks, task.ID, func(t *models.Task) bool {
+		moved = policy.IsStale(t, now) && moveStatus(t, policy.TriageStatus, false)
+		return moved
+	})
+	switch {
+	case errors.Is(err, ErrTaskNotFound), errors.Is(err, models.ErrProjectArchived),
+		errors.Is(err, models.ErrUnknownStatus), errors.Is(err, models.ErrTransitionNotAllowed), errors.Is(err, models.ErrWIPLimitReached):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return moved, nil
+}
+
+// prune forgets the nudges of tasks that are no longer stale, so an
+// update ending a stale spell does not leave its nudge behind.
+func (j *StaleJob) prune(stale map[models.TaskID]bool) {
+	for id := range j.nudged {
+		if !stale[id] {
+			delete(j.nudged, id)
+		}
+	}
+}
+
+// Start runs the job every interval until ctx is cancelled. It returns
+// ErrInvalidInterval, without starting, if interval is not positive.
+func (j *StaleJob) Start(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return ErrInvalidInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				report, err := j.Run(ctx)
+				if err != nil {
+					log.Printf("stale: run failed: %v", err)
+					continue
+				}
+				log.Printf("stale: found %d stale tasks, sent %d nudges and moved %d to triage",
+					len(report.Stale), report.Notified, len(report.Moved))
+			}
+		}
+	}()
+	return nil
+}
+
+// StaleHandler handles HTTP requests for stale task detection.
+type StaleHandler struct {
+	job *StaleJob
+}
+
+// NewStaleHandler creates a new stale task handler.
+func NewStaleHandler(job *StaleJob) *StaleHandler {
+	return &StaleHandler{job: job}
+}
+
+// GetPolicy handles GET /admin/stale-policy requests.
+func (h *StaleHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	if !requireManage(w, r) {
+		return
+	}
+	writeJSON(w, http.StatusOK, h.job.Policy())
+}
+
+// SetPolicy handles PUT /admin/stale-policy requests.
+func (h *StaleHandler) SetPolicy(w http.ResponseWriter, r *http.Request) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var policy models.StalePolicy
+	if err := decodeJSON(w, r, &policy); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if err := h.job.SetPolicy(&policy); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &policy)
+}
+
+// Run handles POST /admin/stale/run requests.
+func (h *StaleHandler) Run(w http.ResponseWriter, r *http.Request) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	report, err := h.job.Run(r.Context())
+	if err != nil {
+		writeServerError(w, r, "stale run failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
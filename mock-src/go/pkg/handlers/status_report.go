@@ -0,0 +1,244 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/example/tasktracker/pkg/markdown"
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// Status report sections, in the order they are rendered.
+const (
+	reportSummary   = "summary"
+	reportCompleted = "completed"
+	reportUpcoming  = "upcoming"
+	reportBlocked   = "blocked"
+)
+
+var reportSections = []string{reportSummary, reportCompleted, reportUpcoming, reportBlocked}
+
+// Status report defaults.
+const (
+	defaultReportUpcomingDays = 14
+	defaultReportLimit        = 25
+	maxReportLimit            = 200
+)
+
+// StatusReportOptions configures a project status report.
+//
+// Sections selects which sections to include. UpcomingDays is how far
+// ahead the upcoming section looks; overdue tasks are always listed.
+// Limit caps the tasks listed per section.
+type StatusReportOptions struct {
+	Sections     map[string]bool
+	UpcomingDays int
+	Limit        int
+}
+
+// parseStatusReportOptions reads report options from the sections,
+// upcoming_days and limit query parameters.
+func parseStatusReportOptions(r *http.Request) (StatusReportOptions, error) {
+	q := r.URL.Query()
+	opts := StatusReportOptions{
+		Sections:     make(map[string]bool, len(reportSections)),
+		UpcomingDays: defaultReportUpcomingDays,
+		Limit:        defaultReportLimit,
+	}
+
+	if raw := q.Get("sections"); raw != "" {
+		for _, s := range strings.Split(raw, ",") {
+			s = strings.ToLower(strings.TrimSpace(s))
+			if !containsString(reportSections, s) {
+				return opts, fmt.Errorf("unknown report section %q", s)
+			}
+			opts.Sections[s] = true
+		}
+	} else {
+		for _, s := range reportSections {
+			opts.Sections[s] = true
+		}
+	}
+	if raw := q.Get("upcoming_days"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > 365 {
+			return opts, errors.New("upcoming_days must be between 1 and 365")
+		}
+		opts.UpcomingDays = n
+	}
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxReportLimit {
+			return opts, fmt.Errorf("limit must be between 1 and %d", maxReportLimit)
+		}
+		opts.Limit = n
+	}
+	return opts, nil
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Report handles GET /projects/{id}/report.md requests, rendering a
+// Markdown status report for pasting into a wiki or chat.
+//
+// Dates are written in the caller's time zone and date format, and
+// "this week" starts on the caller's most recent Monday. Demo requests
+// get the project name and blocked reasons faked.
+func (h *ProjectHandler) Report(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	opts, err := parseStatusReportOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	project, err := h.projects.Get(r.Context(), projectID)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			http.Error(w, "project not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get project", err)
+		return
+	}
+	all, err := h.tasks.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+	tasks := make([]*models.Task, 0, len(all))
+	for _, task := range all {
+		if task.ProjectID == projectID && !task.Draft {
+			tasks = append(tasks, task)
+		}
+	}
+	if faker := demoFakerFromContext(r.Context()); faker != nil {
+		project, tasks = demoProject(faker, project), demoTasks(faker, tasks)
+	}
+
+	report := renderStatusReport(project, tasks, opts, time.Now(), DateFormatterFromContext(r.Context()))
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(report))
+}
+
+// renderStatusReport renders the report for a project's tasks as of at.
+func renderStatusReport(project *models.Project, tasks []*models.Task, opts StatusReportOptions, at time.Time, dates *models.DateFormatter) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s status report\n\n", markdown.Escape(project.Name))
+	fmt.Fprintf(&b, "_Generated %s_\n", dates.DateTime(at))
+
+	if opts.Sections[reportSummary] {
+		stats := computeProjectStats(project.ID, tasks, at, project.Calendar)
+		b.WriteString("\n## Summary\n\n")
+		fmt.Fprintf(&b, "- Total tasks: %d\n", stats.Total)
+		for _, status := range []models.TaskStatus{
+			models.TaskStatusPending,
+			models.TaskStatusInProgress,
+			models.TaskStatusBlocked,
+			models.TaskStatusAwaitingReview,
+			models.TaskStatusCompleted,
+			models.TaskStatusCancelled,
+		} {
+			if n := stats.ByStatus[status]; n > 0 {
+				fmt.Fprintf(&b, "- %s: %d\n", statusLabel(status), n)
+			}
+		}
+		fmt.Fprintf(&b, "- Overdue: %d\n", stats.Overdue)
+		fmt.Fprintf(&b, "- Completion: %.0f%%\n", stats.CompletionRate*100)
+	}
+
+	if opts.Sections[reportCompleted] {
+		weekStart := startOfWeek(at, dates.Location())
+		var done []*models.Task
+		for _, task := range tasks {
+			if task.Status == models.TaskStatusCompleted && !task.StatusSince().Before(weekStart) {
+				done = append(done, task)
+			}
+		}
+		sort.Slice(done, func(i, j int) bool { return done[i].StatusSince().After(done[j].StatusSince()) })
+		writeReportSection(&b, "Completed this week", done, opts.Limit, func(t *models.Task) string {
+			return "completed " + dates.Date(t.StatusSince())
+		})
+	}
+
+	if opts.Sections[reportUpcoming] {
+		horizon := at.AddDate(0, 0, opts.UpcomingDays)
+		var upcoming []*models.Task
+		for _, task := range tasks {
+			if task.IsOpen() && task.DueDate != nil && task.DueDate.Before(horizon) {
+				upcoming = append(upcoming, task)
+			}
+		}
+		sort.Slice(upcoming, func(i, j int) bool { return upcoming[i].DueDate.Before(*upcoming[j].DueDate) })
+		writeReportSection(&b, fmt.Sprintf("Upcoming (next %d days)", opts.UpcomingDays), upcoming, opts.Limit, func(t *models.Task) string {
+			if t.IsOverdueAt(at) {
+				return "**overdue**, due " + dates.Date(*t.DueDate)
+			}
+			return "due " + dates.Date(*t.DueDate)
+		})
+	}
+
+	if opts.Sections[reportBlocked] {
+		var blocked []*models.Task
+		for _, task := range tasks {
+			if task.Status == models.TaskStatusBlocked {
+				blocked = append(blocked, task)
+			}
+		}
+		sort.Slice(blocked, func(i, j int) bool { return blocked[i].StatusSince().Before(blocked[j].StatusSince()) })
+		writeReportSection(&b, "Blocked", blocked, opts.Limit, func(t *models.Task) string {
+			note := "since " + dates.Date(t.StatusSince())
+			if t.BlockedReason != "" {
+				note += ": " + markdown.Escape(t.BlockedReason)
+			}
+			return note
+		})
+	}
+	return b.String()
+}
+
+// writeReportSection writes a titled list of at most limit tasks, each
+// followed by the note describe returns for it.
+func writeReportSection(b *strings.Builder, title string, tasks []*models.Task, limit int, describe func(*models.Task) string) {
+	fmt.Fprintf(b, "\n## %s\n\n", title)
+	if len(tasks) == 0 {
+		b.WriteString("_None._\n")
+		return
+	}
+	for i, task := range tasks {
+		if i == limit {
+			fmt.Fprintf(b, "- _…and %d more_\n", len(tasks)-limit)
+			break
+		}
+		fmt.Fprintf(b, "- **%s** %s — %s\n", task.Reference(), markdown.Escape(task.Title), describe(task))
+	}
+}
+
+// startOfWeek returns midnight on the Monday of at's week in loc.
+func startOfWeek(at time.Time, loc *time.Location) time.Time {
+	local := at.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc).
+		AddDate(0, 0, -((int(local.Weekday()) + 6) % 7))
+}
+
+// statusLabel returns a status as capitalized words, such as "In progress".
+func statusLabel(status models.TaskStatus) string {
+	s := strings.ReplaceAll(string(status), "_", " ")
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,158 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Entity is a model kept in a Store, identified by a string-based ID.
+type Entity[K ~string, T any] interface {
+	// EntityID returns the entity's ID.
+	EntityID() K
+	// Clone returns a deep copy of the entity.
+	Clone() T
+}
+
+// ListOptions selects, orders and pages the entities returned by List.
+//
+// A nil Filter matches everything and a nil Less orders by ID, so pages
+// are stable. A non-positive Limit returns every entity after Offset.
+type ListOptions[T any] struct {
+	Filter func(T) bool
+	Less   func(a, b T) bool
+	Offset int
+	Limit  int
+}
+
+// Store is the CRUD interface shared by entity stores. Entity-specific
+// stores embed it and add their own queries.
+type Store[K ~string, T Entity[K, T]] interface {
+	// Get retrieves an entity by ID.
+	Get(ctx context.Context, id K) (T, error)
+	// GetAll retrieves all entities.
+	GetAll(ctx context.Context) ([]T, error)
+	// List retrieves the entities selected by opts.
+	List(ctx context.Context, opts ListOptions[T]) ([]T, error)
+	// Create stores a new entity.
+	Create(ctx context.Context, entity T) error
+	// Update updates an existing entity.
+	Update(ctx context.Context, entity T) error
+	// Delete removes an entity by ID.
+	Delete(ctx context.Context, id K) error
+}
+
+// InMemoryStore is an in-memory implementation of Store.
+//
+// It stores and returns copies, so an entity changed in place by a
+// caller is not changed in the store, or seen by other callers, until
+// it is updated. List's Filter and Less see the stored entities and
+// must not change them.
+type InMemoryStore[K ~string, T Entity[K, T]] struct {
+	mu       sync.RWMutex
+	entities map[K]T
+	notFound error
+}
+
+// NewInMemoryStore creates a new in-memory store that returns notFound
+// for unknown IDs.
+func NewInMemoryStore[K ~string, T Entity[K, T]](notFound error) *InMemoryStore[K, T] {
+	return &InMemoryStore[K, T]{
+		entities: make(map[K]T),
+		notFound: notFound,
+	}
+}
+
+// Get retrieves an entity by ID.
+func (s *InMemoryStore[K, T]) Get(ctx context.Context, id K) (T, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entity, ok := s.entities[id]
+	if !ok {
+		var zero T
+		return zero, s.notFound
+	}
+	return entity.Clone(), nil
+}
+
+// GetAll retrieves all entities.
+func (s *InMemoryStore[K, T]) GetAll(ctx context.Context) ([]T, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entities := make([]T, 0, len(s.entities))
+	for _, entity := range s.entities {
+		entities = append(entities, entity.Clone())
+	}
+	return entities, nil
+}
+
+// List retrieves the entities selected by opts.
+func (s *InMemoryStore[K, T]) List(ctx context.Context, opts ListOptions[T]) ([]T, error) {
+	s.mu.RLock()
+	entities := make([]T, 0)
+	for _, entity := range s.entities {
+		if opts.Filter == nil || opts.Filter(entity) {
+			entities = append(entities, entity)
+		}
+	}
+	s.mu.RUnlock()
+
+	less := opts.Less
+	if less == nil {
+		less = func(a, b T) bool { return a.EntityID() < b.EntityID() }
+	}
+	sort.Slice(entities, func(i, j int) bool {
+		return less(entities[i], entities[j])
+	})
+
+	if opts.Offset > 0 {
+		if opts.Offset >= len(entities) {
+			return entities[:0], nil
+		}
+		entities = entities[opts.Offset:]
+	}
+	if opts.Limit > 0 && len(entities) > opts.Limit {
+		entities = entities[:opts.Limit]
+	}
+	for i, entity := range entities {
+		entities[i] = entity.Clone()
+	}
+	return entities, nil
+}
+
+// Create stores a new entity.
+func (s *InMemoryStore[K, T]) Create(ctx context.Context, entity T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entities[entity.EntityID()] = entity.Clone()
+	return nil
+}
+
+// Update updates an existing entity.
+func (s *InMemoryStore[K, T]) Update(ctx context.Context, entity T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := entity.EntityID()
+	if _, ok := s.entities[id]; !ok {
+		return s.notFound
+	}
+	s.entities[id] = entity.Clone()
+	return nil
+}
+
+// Delete removes an entity by ID.
+func (s *InMemoryStore[K, T]) Delete(ctx context.Context, id K) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entities[id]; !ok {
+		return s.notFound
+	}
+	delete(s.entities, id)
+	return nil
+}
@@ -0,0 +1,144 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// suggestActivityWindow is how far back tag activity counts towards a
+// suggestion.
+const suggestActivityWindow = 90 * 24 * time.Hour
+
+// defaultSuggestLimit caps the number of suggestions when none is given.
+const defaultSuggestLimit = 5
+
+// AssigneeSuggestion is a candidate assignee for a task.
+//
+// TagMatches counts the user's recently active tasks sharing a tag with
+// the task. Available is false when taking the task would put the user
+// over capacity.
+type AssigneeSuggestion struct {
+	UserID     models.UserID `json:"user_id"`
+	Username   string        `json:"username"`
+	Score      float64       `json:"score"`
+	OpenTasks  int           `json:"open_tasks"`
+	Estimate   float64       `json:"estimate"`
+	TagMatches int           `json:"tag_matches"`
+	Available  bool          `json:"available"`
+}
+
+// suggestAssignees ranks the active users who may write tasks as
+// assignees for task.
+//
+// Workload counts open tasks across all projects. A user scores higher
+// for each recently active task sharing one of the task's tags and lower
+// for each open task; unavailable users rank after available ones.
+func suggestAssignees(task *models.Task, tasks []*models.Task, users []*models.User, at time.Time) []*AssigneeSuggestion {
+	tags := make(map[string]bool, len(task.Tags))
+	for _, tag := range task.Tags {
+		tags[tag] = true
+	}
+
+	byUser := make(map[models.UserID]*AssigneeSuggestion)
+	suggestions := make([]*AssigneeSuggestion, 0)
+	for _, u := range users {
+		if !u.IsActive || !u.HasPermission("write") {
+			continue
+		}
+		s := &AssigneeSuggestion{UserID: u.ID, Username: u.Username}
+		byUser[u.ID] = s
+		suggestions = append(suggestions, s)
+	}
+
+	for _, t := range tasks {
+		if t.ID == task.ID || t.AssigneeID == nil {
+			continue
+		}
+		s, ok := byUser[*t.AssigneeID]
+		if !ok {
+			continue
+		}
+		if t.IsOpen() {
+			s.OpenTasks++
+			s.Estimate += t.Estimate
+		}
+		if at.Sub(t.UpdatedAt) > suggestActivityWindow {
+			continue
+		}
+		for _, tag := range t.Tags {
+			if tags[tag] {
+				s.TagMatches++
+				break
+			}
+		}
+	}
+
+	for _, u := range users {
+		s, ok := byUser[u.ID]
+		if !ok {
+			continue
+		}
+		s.Available = u.Capacity == nil || !u.Capacity.Exceeded(s.OpenTasks+1, s.Estimate+task.Estimate)
+		s.Score = float64(s.TagMatches) - 0.5*float64(s.OpenTasks)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.Available != b.Available {
+			return a.Available
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Username < b.Username
+	})
+	return suggestions
+}
+
+// SuggestAssignees handles GET /tasks/{id}/suggest-assignees requests.
+//
+// An optional limit parameter caps the number of suggestions.
+func (h *WorkloadHandler) SuggestAssignees(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	limit := defaultSuggestLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	task, err := h.tasks.Get(r.Context(), taskID)
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			http.Error(w, "task not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get task", err)
+		return
+	}
+
+	tasks, err := h.tasks.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+	users, err := h.users.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list users", err)
+		return
+	}
+
+	suggestions := suggestAssignees(task, tasks, users, time.Now())
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	writeJSON(w, http.StatusOK, suggestions)
+}
@@ -0,0 +1,284 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// SyncOp identifies the kind of mutation pushed by a client.
+type SyncOp string
+
+const (
+	// SyncOpUpsert creates a task or updates it from BaseVersion.
+	SyncOpUpsert SyncOp = "upsert"
+	// SyncOpDelete deletes a task at BaseVersion.
+	SyncOpDelete SyncOp = "delete"
+)
+
+// maxSyncMutations caps the number of mutations in a single push.
+const maxSyncMutations = 500
+
+// SyncMutation is a single change made by a client while offline.
+//
+// BaseVersion is the server version the client last saw; zero means the
+// client created the task locally with a client-generated TaskID. Only
+// the fields syncEditable copies are taken from Task; the rest are kept
+// or set by the server.
+type SyncMutation struct {
+	Op          SyncOp       `json:"op"`
+	TaskID      string       `json:"task_id"`
+	BaseVersion int          `json:"base_version"`
+	Task        *models.Task `json:"task,omitempty"`
+}
+
+// SyncPushRequest is the request body for pushing offline mutations.
+type SyncPushRequest struct {
+	Mutations []SyncMutation `json:"mutations"`
+}
+
+// SyncApplied describes a mutation accepted by the server.
+type SyncApplied struct {
+	TaskID  string `json:"task_id"`
+	Op      SyncOp `json:"op"`
+	Version int    `json:"version,omitempty"`
+}
+
+// SyncConflict describes a mutation rejected because the server state
+// diverged from the client's base version.
+//
+// Server is nil when the task no longer exists on the server.
+type SyncConflict struct {
+	TaskID      string       `json:"task_id"`
+	Op          SyncOp       `json:"op"`
+	BaseVersion int          `json:"base_version"`
+	Client      *models.Task `json:"client,omitempty"`
+	Server      *models.Task `json:"server,omitempty"`
+}
+
+// SyncRejected describes a mutation that was invalid.
+type SyncRejected struct {
+	TaskID string `json:"task_id"`
+	Reason string `json:"reason"`
+}
+
+// SyncPushResponse is the response body for a push.
+type SyncPushResponse struct {
+	Applied   []SyncApplied  `json:"applied"`
+	Conflicts []SyncConflict `json:"conflicts"`
+	Rejected  []SyncRejected `json:"rejected"`
+}
+
+// SyncHandler handles the offline sync protocol.
+//
+// Clients pull server changes from the change feed and push their local
+// mutations here. Each mutation is applied only if the server version
+// still matches its base version; otherwise both versions are returned
+// as a conflict for the client to resolve and push again.
+type SyncHandler struct {
+	store TaskStore
+}
+
+// NewSyncHandler creates a new sync handler.
+func NewSyncHandler(store TaskStore) *SyncHandler {
+	return &SyncHandler{store: store}
+}
+
+// Push handles POST /sync/push requests.
+//
+// Each mutation needs the caller to see the task and to have the delete
+// permission, for deletes, or the write permission, for upserts, on it;
+// others are rejected.
+func (h *SyncHandler) Push(w http.ResponseWriter, r *http.Request) {
+	caller, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req SyncPushRequest
+	if err := decodeJSONLimit(w, r, &req, maxBulkRequestBodyBytes); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if len(req.Mutations) > maxSyncMutations {
+		http.Error(w, "too many mutations in one push", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	resp := &SyncPushResponse{
+		Applied:   make([]SyncApplied, 0),
+		Conflicts: make([]SyncConflict, 0),
+		Rejected:  make([]SyncRejected, 0),
+	}
+	for _, m := range req.Mutations {
+		if err := h.apply(r.Context(), caller, m, resp); err != nil {
+			writeServerError(w, r, "failed to apply mutations", err)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// apply applies one mutation on behalf of caller, recording its outcome
+// in resp.
+func (h *SyncHandler) apply(ctx context.Context, caller *models.User, m SyncMutation, resp *SyncPushResponse) error {
+	if !models.ValidateID(m.TaskID) {
+		resp.Rejected = append(resp.Rejected, SyncRejected{TaskID: m.TaskID, Reason: "task_id must be a UUID"})
+		return nil
+	}
+	id := models.TaskID(m.TaskID)
+
+	current, err := h.store.Get(ctx, id)
+	if err != nil && !errors.Is(err, ErrTaskNotFound) {
+		return err
+	}
+	if errors.Is(err, ErrTaskNotFound) {
+		current = nil
+	} else {
+		// Compare and edit a private copy, never one shared with other
+		// readers of the store.
+		current = current.Clone()
+	}
+
+	// Tasks the caller may not see are refused without their contents,
+	// which a conflict would return.
+	if current != nil && !visibleTo(ctx, current) {
+		resp.Rejected = append(resp.Rejected, SyncRejected{TaskID: m.TaskID, Reason: "task not found"})
+		return nil
+	}
+	action := "write"
+	if m.Op == SyncOpDelete {
+		action = "delete"
+	}
+	if current != nil && !caller.Can(action, models.TaskResource(current)) {
+		resp.Rejected = append(resp.Rejected, SyncRejected{TaskID: m.TaskID, Reason: "forbidden"})
+		return nil
+	}
+
+	conflict := func() {
+		// current may have been fetched again after a lost race.
+		if current != nil && !visibleTo(ctx, current) {
+			resp.Rejected = append(resp.Rejected, SyncRejected{TaskID: m.TaskID, Reason: "task not found"})
+			return
+		}
+		resp.Conflicts = append(resp.Conflicts, SyncConflict{
+			TaskID:      m.TaskID,
+			Op:          m.Op,
+			BaseVersion: m.BaseVersion,
+			Client:      m.Task,
+			Server:      current,
+		})
+	}
+
+	switch m.Op {
+	case SyncOpDelete:
+		if current == nil {
+			// Already gone; deleting is idempotent.
+			resp.Applied = append(resp.Applied, SyncApplied{TaskID: m.TaskID, Op: m.Op})
+			return nil
+		}
+		if current.Version != m.BaseVersion {
+			conflict()
+			return nil
+		}
+		if err := h.store.Delete(ctx, id); err != nil && !errors.Is(err, ErrTaskNotFound) {
+			return err
+		}
+		resp.Applied = append(resp.Applied, SyncApplied{TaskID: m.TaskID, Op: m.Op})
+		return nil
+
+	case SyncOpUpsert:
+		if m.Task == nil || m.Task.Title == "" || m.Task.ProjectID == "" {
+			resp.Rejected = append(resp.Rejected, SyncRejected{TaskID: m.TaskID, Reason: "task with title and project_id is required"})
+			return nil
+		}
+
+		edits := &models.Task{}
+		syncEditable(edits, m.Task)
+		if err := edits.SanitizeContent(); err != nil {
+			resp.Rejected = append(resp.Rejected, SyncRejected{TaskID: m.TaskID, Reason: err.Error()})
+			return nil
+		}
+
+		if m.BaseVersion == 0 {
+			if current != nil {
+				conflict()
+				return nil
+			}
+			incoming := models.NewTask(edits.Title, m.Task.ProjectID)
+			incoming.ID = id
+			incoming.CreatedBy = caller.ID
+			syncEditable(incoming, edits)
+			if !caller.Can("write", models.TaskResource(incoming)) {
+				resp.Rejected = append(resp.Rejected, SyncRejected{TaskID: m.TaskID, Reason: "forbidden"})
+				return nil
+			}
+			if err := h.store.Create(ctx, incoming); err != nil {
+				if errors.Is(err, ErrTaskExists) {
+					current, _ = h.store.Get(ctx, id)
+					conflict()
+					return nil
+				}
+				if errors.Is(err, models.ErrUnknownStatus) || errors.Is(err, models.ErrWIPLimitReached) {
+					resp.Rejected = append(resp.Rejected, SyncRejected{TaskID: m.TaskID, Reason: err.Error()})
+					return nil
+				}
+				return err
+			}
+			resp.Applied = append(resp.Applied, SyncApplied{TaskID: m.TaskID, Op: m.Op, Version: incoming.Version})
+			return nil
+		}
+
+		if current == nil || current.Version != m.BaseVersion {
+			conflict()
+			return nil
+		}
+		incoming := current.Clone()
+		syncEditable(incoming, edits)
+		incoming.UpdatedAt = time.Now()
+		if err := h.store.Update(ctx, incoming); err != nil {
+			if errors.Is(err, ErrVersionConflict) || errors.Is(err, ErrTaskNotFound) {
+				current, _ = h.store.Get(ctx, id)
+				conflict()
+				return nil
+			}
+			if errors.Is(err, models.ErrUnknownStatus) || errors.Is(err, models.ErrTransitionNotAllowed) || errors.Is(err, models.ErrWIPLimitReached) {
+				resp.Rejected = append(resp.Rejected, SyncRejected{TaskID: m.TaskID, Reason: err.Error()})
+				return nil
+			}
+			return err
+		}
+		resp.Applied = append(resp.Applied, SyncApplied{TaskID: m.TaskID, Op: m.Op, Version: incoming.Version})
+		return nil
+	}
+
+	resp.Rejected = append(resp.Rejected, SyncRejected{TaskID: m.TaskID, Reason: "unknown op"})
+	return nil
+}
+
+// syncEditable copies the fields clients may edit offline from src to
+// dst, keeping dst's priority if src has none. Status, rank, assignment, drafts and visibility have endpoints of
+// their own enforcing the workflow, approval and access rules, so sync
+// leaves them to the server.
+func syncEditable(dst, src *models.Task) {
+	dst.Title = src.Title
+	dst.Description = src.Description
+	if src.Priority > 0 {
+		dst.Priority = src.Priority
+	}
+	dst.StartDate = src.StartDate
+	dst.DueDate = src.DueDate
+	dst.Tags = slices.Clone(src.Tags)
+	if dst.Tags == nil {
+		dst.Tags = make([]string, 0)
+	}
+	dst.Checklist = slices.Clone(src.Checklist)
+	dst.Estimate = src.Estimate
+}
@@ -0,0 +1,215 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+const (
+	// defaultTagSuggestLimit caps tag suggestions when no limit is given.
+	defaultTagSuggestLimit = 10
+	// maxTagSuggestLimit is the largest limit a client may request.
+	maxTagSuggestLimit = 100
+)
+
+// TagSuggestion is a tag and the number of tasks using it.
+type TagSuggestion struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// indexedTask records the project and tags a task was indexed under.
+type indexedTask struct {
+	projectID models.ProjectID
+	tags      []string
+}
+
+// TagIndex counts how many tasks use each tag, per project.
+type TagIndex struct {
+	mu     sync.RWMutex
+	counts map[models.ProjectID]map[string]int
+	tasks  map[models.TaskID]indexedTask
+}
+
+// NewTagIndex creates an empty tag index.
+func NewTagIndex() *TagIndex {
+	return &TagIndex{
+		counts: make(map[models.ProjectID]map[string]int),
+		tasks:  make(map[models.TaskID]indexedTask),
+	}
+}
+
+// Put indexes a task's current tags, replacing what was indexed for it.
+func (idx *TagIndex) Put(task *models.Task) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(task.ID)
+	entry := indexedTask{projectID: task.ProjectID, tags: append([]string(nil), task.Tags...)}
+	counts, ok := idx.counts[entry.projectID]
+	if !ok {
+		counts = make(map[string]int)
+		idx.counts[entry.projectID] = counts
+	}
+	for _, tag := range entry.tags {
+		counts[tag]++
+	}
+	idx.tasks[task.ID] = entry
+}
+
+// Remove drops a task from the index.
+func (idx *TagIndex) Remove(taskID models.TaskID) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(taskID)
+}
+
+// remove drops a task from the index. The caller must hold idx.mu.
+func (idx *TagIndex) remove(taskID models.TaskID) {
+	entry, ok := idx.tasks[taskID]
+	if !ok {
+		return
+	}
+	counts := idx.counts[entry.projectID]
+	for _, tag := range entry.tags {
+		if counts[tag]--; counts[tag] <= 0 {
+			delete(counts, tag)
+		}
+	}
+	if len(counts) == 0 {
+		delete(idx.counts, entry.projectID)
+	}
+	delete(idx.tasks, taskID)
+}
+
+// Suggest returns up to limit tags starting with prefix, most used
+// first. An empty projectID suggests tags across all projects.
+func (idx *TagIndex) Suggest(projectID models.ProjectID, prefix string, limit int) []TagSuggestion {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	totals := make(map[string]int)
+	for project, counts := range idx.counts {
+		if projectID != "" && project != projectID {
+			continue
+		}
+		for tag, n := range counts {
+			if strings.HasPrefix(tag, prefix) {
+				totals[tag] += n
+			}
+		}
+	}
+
+	suggestions := make([]TagSuggestion, 0, len(totals))
+	for tag, n := range totals {
+		suggestions = append(suggestions, TagSuggestion{Tag: tag, Count: n})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Count != suggestions[j].Count {
+			return suggestions[i].Count > suggestions[j].Count
+		}
+		return suggestions[i].Tag < suggestions[j].Tag
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// TagIndexedTaskStore is a TaskStore decorator that keeps a TagIndex up
+// to date with the tasks written through it.
+type TagIndexedTaskStore struct {
+	next  TaskStore
+	index *TagIndex
+}
+
+// NewTagIndexedTaskStore wraps a task store with tag indexing. Call
+// Rebuild to index tasks already in the store.
+func NewTagIndexedTaskStore(next TaskStore, index *TagIndex) *TagIndexedTaskStore {
+	return &TagIndexedTaskStore{next: next, index: index}
+}
+
+// Rebuild indexes every task in the underlying store.
+func (s *TagIndexedTaskStore) Rebuild(ctx context.Context) error {
+	tasks, err := s.next.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		s.index.Put(task)
+	}
+	return nil
+}
+
+// Get retrieves a task by ID.
+func (s *TagIndexedTaskStore) Get(ctx context.Context, id models.TaskID) (*models.Task, error) {
+	return s.next.Get(ctx, id)
+}
+
+// GetAll retrieves all tasks.
+func (s *TagIndexedTaskStore) GetAll(ctx context.Context) ([]*models.Task, error) {
+	return s.next.GetAll(ctx)
+}
+
+// Create stores a new task and indexes its tags.
+func (s *TagIndexedTaskStore) Create(ctx context.Context, task *models.Task) error {
+	if err := s.next.Create(ctx, task); err != nil {
+		return err
+	}
+	s.index.Put(task)
+	return nil
+}
+
+// Update updates an existing task and reindexes its tags.
+func (s *TagIndexedTaskStore) Update(ctx context.Context, task *models.Task) error {
+	if err := s.next.Update(ctx, task); err != nil {
+		return err
+	}
+	s.index.Put(task)
+	return nil
+}
+
+// Delete removes a task by ID and drops it from the index.
+func (s *TagIndexedTaskStore) Delete(ctx context.Context, id models.TaskID) error {
+	if err := s.next.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.index.Remove(id)
+	return nil
+}
+
+// TagHandler handles HTTP requests for tags.
+type TagHandler struct {
+	index *TagIndex
+}
+
+// NewTagHandler creates a new tag handler.
+func NewTagHandler(index *TagIndex) *TagHandler {
+	return &TagHandler{index: index}
+}
+
+// Suggest handles GET /tags/suggest?prefix=&project_id=&limit= requests.
+func (h *TagHandler) Suggest(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := defaultTagSuggestLimit
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 || n > maxTagSuggestLimit {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	writeJSON(w, http.StatusOK, h.index.Suggest(models.ProjectID(query.Get("project_id")), query.Get("prefix"), limit))
+}
@@ -0,0 +1,160 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// TaskDefaultsConfig holds the global task defaults administrators set.
+type TaskDefaultsConfig struct {
+	mu       sync.RWMutex
+	defaults *models.TaskDefaults
+}
+
+// NewTaskDefaultsConfig creates a task defaults config. Nil defaults use
+// models.DefaultTaskDefaults.
+func NewTaskDefaultsConfig(defaults *models.TaskDefaults) *TaskDefaultsConfig {
+	if defaults == nil {
+		defaults = models.DefaultTaskDefaults()
+	}
+	return &TaskDefaultsConfig{defaults: defaults}
+}
+
+// Defaults returns the current defaults. They must not be modified.
+func (c *TaskDefaultsConfig) Defaults() *models.TaskDefaults {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.defaults
+}
+
+// SetDefaults replaces the defaults after validating them.
+func (c *TaskDefaultsConfig) SetDefaults(defaults *models.TaskDefaults) error {
+	if err := defaults.Validate(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.defaults = defaults
+	return nil
+}
+
+// WithTaskDefaults makes Create give new tasks the configured global
+// defaults where their project's settings do not set their own.
+func WithTaskDefaults(config *TaskDefaultsConfig) TaskHandlerOption {
+	return func(h *TaskHandler) {
+		h.defaults = config
+	}
+}
+
+// taskDefaults returns the defaults for a new task in a project: the
+// global defaults with the project's settings laid over them. config
+// and settings may be nil.
+func taskDefaults(ctx context.Context, config *TaskDefaultsConfig, settings ProjectSettingsStore, projectID models.ProjectID) (*models.TaskDefaults, error) {
+	defaults := models.DefaultTaskDefaults()
+	if config != nil {
+		defaults = config.Defaults()
+	}
+	if settings == nil {
+		return defaults, nil
+	}
+	s, err := projectSettings(ctx, settings, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return defaults.Override(s), nil
+}
+
+// DefaultsTaskStore is a TaskStore decorator that gives every new task
+// the task defaults for what it leaves unset, as models.TaskDefaults
+// Apply does, so tasks created from email, templates, intake forms or
+// imports get them as well as those created through the task API.
+type DefaultsTaskStore struct {
+	next       TaskStore
+	config     *TaskDefaultsConfig
+	settings   ProjectSettingsStore
+	priorities PrioritySchemeStore
+}
+
+// NewDefaultsTaskStore wraps a task store with the task defaults.
+// settings and priorities may be nil, leaving out per-project defaults
+// and priority schemes.
+func NewDefaultsTaskStore(next TaskStore, config *TaskDefaultsConfig, settings ProjectSettingsStore, priorities PrioritySchemeStore) *DefaultsTaskStore {
+	return &DefaultsTaskStore{next: next, config: config, settings: settings, priorities: priorities}
+}
+
+// Get retrieves a task by ID.
+func (s *DefaultsTaskStore) Get(ctx context.Context, id models.TaskID) (*models.Task, error) {
+	return s.next.Get(ctx, id)
+}
+
+// GetAll retrieves all tasks.
+func (s *DefaultsTaskStore) GetAll(ctx context.Context) ([]*models.Task, error) {
+	return s.next.GetAll(ctx)
+}
+
+// Create applies the defaults of the task's project, then stores it.
+func (s *DefaultsTaskStore) Create(ctx context.Context, task *models.Task) error {
+	defaults, err := taskDefaults(ctx, s.config, s.settings, task.ProjectID)
+	if err != nil {
+		return err
+	}
+	var scheme *models.PriorityScheme
+	if s.priorities != nil {
+		if scheme, err = projectPriorityScheme(ctx, s.priorities, task.ProjectID); err != nil {
+			return err
+		}
+	}
+	defaults.Apply(task, scheme)
+	return s.next.Create(ctx, task)
+}
+
+// Update updates an existing task.
+func (s *DefaultsTaskStore) Update(ctx context.Context, task *models.Task) error {
+	return s.next.Update(ctx, task)
+}
+
+// Delete removes a task by ID.
+func (s *DefaultsTaskStore) Delete(ctx context.Context, id models.TaskID) error {
+	return s.next.Delete(ctx, id)
+}
+
+// TaskDefaultsHandler handles HTTP requests for the global task defaults.
+type TaskDefaultsHandler struct {
+	config *TaskDefaultsConfig
+}
+
+// NewTaskDefaultsHandler creates a new task defaults handler.
+func NewTaskDefaultsHandler(config *TaskDefaultsConfig) *TaskDefaultsHandler {
+	return &TaskDefaultsHandler{config: config}
+}
+
+// Get handles GET /admin/task-defaults requests.
+func (h *TaskDefaultsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	writeJSON(w, http.StatusOK, h.config.Defaults())
+}
+
+// Set handles PUT /admin/task-defaults requests.
+func (h *TaskDefaultsHandler) Set(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	var defaults models.TaskDefaults
+	if err := decodeJSON(w, r, &defaults); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if err := h.config.SetDefaults(&defaults); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &defaults)
+}
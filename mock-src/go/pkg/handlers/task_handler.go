@@ -5,44 +5,65 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/example/tasktracker/pkg/markdown"
 	"github.com/example/tasktracker/pkg/models"
 )
 
 // TaskStore defines the interface for task storage.
 type TaskStore interface {
 	// Get retrieves a task by ID.
-	Get(ctx context.Context, id string) (*models.Task, error)
+	Get(ctx context.Context, id models.TaskID) (*models.Task, error)
 	// GetAll retrieves all tasks.
 	GetAll(ctx context.Context) ([]*models.Task, error)
 	// Create stores a new task.
+	//
+	// Returns ErrTaskExists if a task with the same ID is already stored.
 	Create(ctx context.Context, task *models.Task) error
 	// Update updates an existing task.
+	//
+	// The task's Version must match the stored version; on success it
+	// is incremented. Returns ErrVersionConflict otherwise.
 	Update(ctx context.Context, task *models.Task) error
 	// Delete removes a task by ID.
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id models.TaskID) error
 }
 
 // ErrTaskNotFound is returned when a task is not found.
 var ErrTaskNotFound = errors.New("task not found")
 
+// ErrTaskExists is returned when creating a task whose ID is already taken.
+var ErrTaskExists = errors.New("task already exists")
+
+// ErrVersionConflict is returned when a task is updated from a stale version.
+var ErrVersionConflict = errors.New("task version conflict")
+
 // InMemoryTaskStore is an in-memory implementation of TaskStore.
+//
+// It stores and returns copies, so a task changed in place by a caller
+// is not changed in the store until it is updated, and stores wrapping
+// it can compare an update with what is stored.
 type InMemoryTaskStore struct {
 	mu    sync.RWMutex
-	tasks map[string]*models.Task
+	tasks map[models.TaskID]*models.Task
 }
 
 // NewInMemoryTaskStore creates a new in-memory task store.
 func NewInMemoryTaskStore() *InMemoryTaskStore {
 	return &InMemoryTaskStore{
-		tasks: make(map[string]*models.Task),
+		tasks: make(map[models.TaskID]*models.Task),
 	}
 }
 
 // Get retrieves a task by ID.
-func (s *InMemoryTaskStore) Get(ctx context.Context, id string) (*models.Task, error) {
+func (s *InMemoryTaskStore) Get(ctx context.Context, id models.TaskID) (*models.Task, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -50,7 +71,7 @@ func (s *InMemoryTaskStore) Get(ctx context.Context, id string) (*models.Task, e
 	if !ok {
 		return nil, ErrTaskNotFound
 	}
-	return task, nil
+	return task.Clone(), nil
 }
 
 // GetAll retrieves all tasks.
@@ -60,7 +81,7 @@ func (s *InMemoryTaskStore) GetAll(ctx context.Context) ([]*models.Task, error)
 
 	tasks := make([]*models.Task, 0, len(s.tasks))
 	for _, task := range s.tasks {
-		tasks = append(tasks, task)
+		tasks = append(tasks, task.Clone())
 	}
 	return tasks, nil
 }
@@ -70,7 +91,13 @@ func (s *InMemoryTaskStore) Create(ctx context.Context, task *models.Task) error
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.tasks[task.ID] = task
+	if _, ok := s.tasks[task.ID]; ok {
+		return ErrTaskExists
+	}
+	if task.Version == 0 {
+		task.Version = 1
+	}
+	s.tasks[task.ID] = task.Clone()
 	return nil
 }
 
@@ -79,15 +106,20 @@ func (s *InMemoryTaskStore) Update(ctx context.Context, task *models.Task) error
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, ok := s.tasks[task.ID]; !ok {
+	existing, ok := s.tasks[task.ID]
+	if !ok {
 		return ErrTaskNotFound
 	}
-	s.tasks[task.ID] = task
+	if existing.Version != task.Version {
+		return ErrVersionConflict
+	}
+	task.Version++
+	s.tasks[task.ID] = task.Clone()
 	return nil
 }
 
 // Delete removes a task by ID.
-func (s *InMemoryTaskStore) Delete(ctx context.Context, id string) error {
+func (s *InMemoryTaskStore) Delete(ctx context.Context, id models.TaskID) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -100,111 +132,639 @@ func (s *InMemoryTaskStore) Delete(ctx context.Context, id string) error {
 
 // TaskHandler handles HTTP requests for tasks.
 type TaskHandler struct {
-	store TaskStore
+	store         TaskStore
+	slas          SLAStore
+	projects      ProjectStore
+	policies      AssignmentPolicyStore
+	priorities    PrioritySchemeStore
+	settings      ProjectSettingsStore
+	defaults      *TaskDefaultsConfig
+	reactions     ReactionStore
+	locks         EditLockStore
+	bus           *EventBus
+	notifications NotificationStore
+	stale         *StaleJob
+}
+
+// TaskHandlerOption is a function that configures a TaskHandler.
+type TaskHandlerOption func(*TaskHandler)
+
+// WithSLAs makes the handler report each task's SLA status and accept
+// the sla filter on List.
+func WithSLAs(slas SLAStore) TaskHandlerOption {
+	return func(h *TaskHandler) {
+		h.slas = slas
+	}
+}
+
+// WithProjects lets the handler use project calendars for working-day
+// due dates and SLA timers.
+func WithProjects(projects ProjectStore) TaskHandlerOption {
+	return func(h *TaskHandler) {
+		h.projects = projects
+	}
+}
+
+// WithAssignmentPolicies makes Create assign new tasks according to
+// their project's assignment policy.
+func WithAssignmentPolicies(policies AssignmentPolicyStore) TaskHandlerOption {
+	return func(h *TaskHandler) {
+		h.policies = policies
+	}
+}
+
+// WithPrioritySchemes makes Create check priorities against the
+// project's priority scheme and default to the scheme's default rank.
+func WithPrioritySchemes(priorities PrioritySchemeStore) TaskHandlerOption {
+	return func(h *TaskHandler) {
+		h.priorities = priorities
+	}
+}
+
+// WithProjectSettings makes Create give new tasks their project's
+// default priority, tags and due date and, when no assignment policy
+// assigned them, its default assignee.
+func WithProjectSettings(settings ProjectSettingsStore) TaskHandlerOption {
+	return func(h *TaskHandler) {
+		h.settings = settings
+	}
+}
+
+// WithReactions makes Get and List report each task's aggregated
+// reactions.
+func WithReactions(reactions ReactionStore) TaskHandlerOption {
+	return func(h *TaskHandler) {
+		h.reactions = reactions
+	}
+}
+
+// WithEditLocks makes Get and List report who holds each task's edit
+// lock.
+func WithEditLocks(locks EditLockStore) TaskHandlerOption {
+	return func(h *TaskHandler) {
+		h.locks = locks
+	}
+}
+
+// WithStaleDetection makes List accept the stale filter, judged by the
+// job's stale policy.
+func WithStaleDetection(job *StaleJob) TaskHandlerOption {
+	return func(h *TaskHandler) {
+		h.stale = job
+	}
+}
+
+// WithEventBus makes the handler publish approval decisions on bus.
+func WithEventBus(bus *EventBus) TaskHandlerOption {
+	return func(h *TaskHandler) {
+		h.bus = bus
+	}
+}
+
+// WithNotifications makes the handler notify a task's reviewer when the
+// task is submitted for review.
+func WithNotifications(notifications NotificationStore) TaskHandlerOption {
+	return func(h *TaskHandler) {
+		h.notifications = notifications
+	}
 }
 
 // NewTaskHandler creates a new task handler.
-func NewTaskHandler(store TaskStore) *TaskHandler {
-	return &TaskHandler{store: store}
+func NewTaskHandler(store TaskStore, opts ...TaskHandlerOption) *TaskHandler {
+	h := &TaskHandler{store: store}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// workingDaysFromNow returns the due date n working days from now in the
+// project's calendar, or n calendar days from now if it has none.
+func (h *TaskHandler) workingDaysFromNow(ctx context.Context, projectID models.ProjectID, n int) (time.Time, error) {
+	now := time.Now()
+	calendar, err := projectCalendar(ctx, h.projects, projectID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if calendar == nil {
+		return now.AddDate(0, 0, n), nil
+	}
+	return calendar.AddWorkingDays(now, n), nil
+}
+
+// withSLA sets the SLA status on resp if SLAs are configured.
+func (h *TaskHandler) withSLA(ctx context.Context, resp *TaskResponse, task *models.Task) error {
+	if h.slas == nil {
+		return nil
+	}
+	status, err := evaluateSLA(ctx, h.slas, h.projects, task, time.Now())
+	resp.SLA = status
+	return err
+}
+
+// withReactions sets the reaction counts on resp if reactions are
+// configured.
+func (h *TaskHandler) withReactions(ctx context.Context, resp *TaskResponse) error {
+	if h.reactions == nil {
+		return nil
+	}
+	counts, err := reactionCounts(ctx, h.reactions, models.ReactionTargetTask, string(resp.ID))
+	if err != nil {
+		return err
+	}
+	if len(counts) > 0 {
+		resp.Reactions = counts
+	}
+	return nil
+}
+
+// withLock sets the edit lock on resp if locks are configured.
+func (h *TaskHandler) withLock(ctx context.Context, resp *TaskResponse) error {
+	if h.locks == nil {
+		return nil
+	}
+	lock, err := taskLock(ctx, h.locks, resp.ID)
+	resp.Lock = lock
+	return err
 }
 
 // CreateTaskRequest is the request body for creating a task.
+//
+// ID is optional; clients that create tasks offline may supply their
+// own UUID so references stay stable across the server round trip.
+// DueInWorkingDays sets the due date to the end of that many working
+// days from now, per the project's calendar, and overrides DueDate.
+// SkipAutoAssign leaves the task unassigned even if its project has an
+// assignment policy, and AllowDuplicate creates the task even if its
+// project blocks duplicates. Draft creates the task as a draft visible
+// only to the caller; drafts are not auto-assigned until published.
+// Visibility and AllowedUserIDs restrict who may see the task.
 type CreateTaskRequest struct {
-	Title       string `json:"title"`
-	ProjectID   string `json:"project_id"`
-	Description string `json:"description,omitempty"`
-	Priority    int    `json:"priority,omitempty"`
+	ID               string                `json:"id,omitempty"`
+	Title            string                `json:"title"`
+	ProjectID        models.ProjectID      `json:"project_id"`
+	Description      string                `json:"description,omitempty"`
+	Priority         int                   `json:"priority,omitempty"`
+	StartDate        *time.Time            `json:"start_date,omitempty"`
+	DueDate          *time.Time            `json:"due_date,omitempty"`
+	DueInWorkingDays *int                  `json:"due_in_working_days,omitempty"`
+	Tags             []string              `json:"tags,omitempty"`
+	SkipAutoAssign   bool                  `json:"skip_auto_assign,omitempty"`
+	AllowDuplicate   bool                  `json:"allow_duplicate,omitempty"`
+	Draft            bool                  `json:"draft,omitempty"`
+	Visibility       models.TaskVisibility `json:"visibility,omitempty"`
+	AllowedUserIDs   []models.UserID       `json:"allowed_user_ids,omitempty"`
 }
 
 // TaskResponse is the response body for a task.
+//
+// DescriptionHTML is set only when ?render=html is requested, SLA only
+// when the handler is configured with SLAs, Reactions only when the task
+// has any and the handler is configured with reactions, Lock only while
+// someone holds the task's edit lock, and
+// DuplicateCandidates only on create when the project's duplicate check
+// found any.
 type TaskResponse struct {
-	ID          string              `json:"id"`
-	Title       string              `json:"title"`
-	Description string              `json:"description"`
-	ProjectID   string              `json:"project_id"`
-	Status      models.TaskStatus   `json:"status"`
-	Priority    models.TaskPriority `json:"priority"`
-	CreatedAt   string              `json:"created_at"`
-	UpdatedAt   string              `json:"updated_at"`
+	ID                  models.TaskID          `json:"id"`
+	Key                 string                 `json:"key,omitempty"`
+	Title               string                 `json:"title"`
+	Description         string                 `json:"description"`
+	ProjectID           models.ProjectID       `json:"project_id"`
+	Status              models.TaskStatus      `json:"status"`
+	Priority            models.TaskPriority    `json:"priority"`
+	Rank                string                 `json:"rank,omitempty"`
+	AssigneeID          *models.UserID         `json:"assignee_id,omitempty"`
+	ReviewerID          *models.UserID         `json:"reviewer_id,omitempty"`
+	Tags                []string               `json:"tags"`
+	Votes               int                    `json:"votes"`
+	CreatedBy           models.UserID          `json:"created_by,omitempty"`
+	Draft               bool                   `json:"draft,omitempty"`
+	Visibility          models.TaskVisibility  `json:"visibility,omitempty"`
+	AllowedUserIDs      []models.UserID        `json:"allowed_user_ids,omitempty"`
+	CreatedAt           string                 `json:"created_at"`
+	UpdatedAt           string                 `json:"updated_at"`
+	Version             int                    `json:"version"`
+	StartDate           *time.Time             `json:"start_date,omitempty"`
+	DueDate             *time.Time             `json:"due_date,omitempty"`
+	BlockedReason       string                 `json:"blocked_reason,omitempty"`
+	BlockedByTaskID     *models.TaskID         `json:"blocked_by_task_id,omitempty"`
+	RejectionReason     string                 `json:"rejection_reason,omitempty"`
+	SLA                 *models.SLAStatus      `json:"sla,omitempty"`
+	DescriptionHTML     string                 `json:"description_html,omitempty"`
+	Reactions           []models.ReactionCount `json:"reactions,omitempty"`
+	Lock                *models.EditLock       `json:"lock,omitempty"`
+	DuplicateCandidates []models.TaskID        `json:"duplicate_candidates,omitempty"`
 }
 
 // toResponse converts a Task to a TaskResponse.
 func toResponse(task *models.Task) *TaskResponse {
 	return &TaskResponse{
-		ID:          task.ID,
-		Title:       task.Title,
-		Description: task.Description,
-		ProjectID:   task.ProjectID,
-		Status:      task.Status,
-		Priority:    task.Priority,
-		CreatedAt:   task.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:   task.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		ID:              task.ID,
+		Key:             task.Key,
+		Title:           task.Title,
+		Description:     task.Description,
+		ProjectID:       task.ProjectID,
+		Status:          task.Status,
+		Priority:        task.Priority,
+		Rank:            task.Rank,
+		AssigneeID:      task.AssigneeID,
+		ReviewerID:      task.ReviewerID,
+		Tags:            task.Tags,
+		Votes:           len(task.Voters),
+		CreatedBy:       task.CreatedBy,
+		Draft:           task.Draft,
+		Visibility:      task.Visibility,
+		AllowedUserIDs:  task.AllowedUserIDs,
+		CreatedAt:       task.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:       task.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		Version:         task.Version,
+		StartDate:       task.StartDate,
+		DueDate:         task.DueDate,
+		BlockedReason:   task.BlockedReason,
+		BlockedByTaskID: task.BlockedByTaskID,
+		RejectionReason: task.RejectionReason,
 	}
 }
 
 // Create handles POST /tasks requests.
 func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req CreateTaskRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
 		return
 	}
 
+	task, ok := h.newTask(w, r, &req)
+	if !ok {
+		return
+	}
+	h.createTask(w, r, task, &req)
+}
+
+// newTask builds and validates a task from a create request. It writes
+// an error response and returns false if the request is invalid.
+//
+// The priority, tags and due date the request leaves out come from the
+// task defaults. A default priority the project's priority scheme does
+// not define gives way to the scheme's default, and a default due date
+// before the start date is dropped.
+func (h *TaskHandler) newTask(w http.ResponseWriter, r *http.Request, req *CreateTaskRequest) (*models.Task, bool) {
 	if req.Title == "" {
 		http.Error(w, "title is required", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	if req.ProjectID == "" {
 		http.Error(w, "project_id is required", http.StatusBadRequest)
-		return
+		return nil, false
+	}
+
+	if req.ID != "" && !models.ValidateID(req.ID) {
+		http.Error(w, "id must be a lowercase UUID", http.StatusBadRequest)
+		return nil, false
 	}
 
 	task := models.NewTask(req.Title, req.ProjectID)
+	if req.ID != "" {
+		task.ID = models.TaskID(req.ID)
+	}
+	if user, ok := UserFromContext(r.Context()); ok {
+		task.CreatedBy = user.ID
+	}
+	if req.Draft {
+		if task.CreatedBy == "" {
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return nil, false
+		}
+		task.Draft = true
+	}
+	if err := task.SetVisibility(req.Visibility, req.AllowedUserIDs); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
 	if req.Description != "" {
 		task.Description = req.Description
 	}
+	defaults, err := taskDefaults(r.Context(), h.defaults, h.settings, req.ProjectID)
+	if err != nil {
+		writeServerError(w, r, "failed to get project settings", err)
+		return nil, false
+	}
+	task.Priority = 0
 	if req.Priority > 0 {
 		task.Priority = models.TaskPriority(req.Priority)
 	}
+	var scheme *models.PriorityScheme
+	if h.priorities != nil {
+		if scheme, err = projectPriorityScheme(r.Context(), h.priorities, req.ProjectID); err != nil {
+			writeServerError(w, r, "failed to get priority scheme", err)
+			return nil, false
+		}
+		if task.Priority > 0 && !scheme.Has(task.Priority) {
+			http.Error(w, models.ErrUnknownPriority.Error(), http.StatusBadRequest)
+			return nil, false
+		}
+	}
+	for _, tag := range req.Tags {
+		if strings.TrimSpace(tag) != "" {
+			task.AddTag(tag)
+		}
+	}
+	task.StartDate = req.StartDate
+	task.DueDate = req.DueDate
+	if req.DueInWorkingDays != nil {
+		if *req.DueInWorkingDays < 0 {
+			http.Error(w, "due_in_working_days cannot be negative", http.StatusBadRequest)
+			return nil, false
+		}
+		due, err := h.workingDaysFromNow(r.Context(), req.ProjectID, *req.DueInWorkingDays)
+		if err != nil {
+			writeServerError(w, r, "failed to get project calendar", err)
+			return nil, false
+		}
+		task.DueDate = &due
+	}
+	defaults.Apply(task, scheme)
+	if task.StartDate != nil && task.DueDate != nil && task.DueDate.Before(*task.StartDate) {
+		http.Error(w, "due date cannot be before start date", http.StatusBadRequest)
+		return nil, false
+	}
+	if err := task.SanitizeContent(); err != nil {
+		writeContentError(w, err)
+		return nil, false
+	}
+	if task.Title == "" {
+		http.Error(w, "title is required", http.StatusBadRequest)
+		return nil, false
+	}
+	return task, true
+}
 
-	if err := h.store.Create(r.Context(), task); err != nil {
-		http.Error(w, "failed to create task", http.StatusInternalServerError)
+// DuplicateConflict is the response body when a new task is rejected as
+// a likely duplicate.
+type DuplicateConflict struct {
+	Error      string          `json:"error"`
+	Candidates []models.TaskID `json:"candidates"`
+}
+
+// createTask checks a new task against its project's duplicate check,
+// assigns it per its project's policy, or else to its project's default
+// assignee, unless the request opts out, ranks it after the project's
+// other tasks, stores it and writes it as the response.
+func (h *TaskHandler) createTask(w http.ResponseWriter, r *http.Request, task *models.Task, req *CreateTaskRequest) {
+	duplicates, blocked, err := h.findDuplicates(r.Context(), task)
+	if err != nil {
+		writeServerError(w, r, "failed to check for duplicates", err)
+		return
+	}
+	if blocked && !req.AllowDuplicate {
+		writeJSON(w, http.StatusConflict, &DuplicateConflict{
+			Error:      "a similar open task already exists",
+			Candidates: duplicates,
+		})
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(toResponse(task))
+	if h.policies != nil && !req.SkipAutoAssign && !task.Draft {
+		if _, err := autoAssign(r.Context(), h.policies, h.store, task); err != nil {
+			writeServerError(w, r, "failed to assign task", err)
+			return
+		}
+	}
+	if h.settings != nil && !req.SkipAutoAssign && !task.Draft && task.AssigneeID == nil {
+		settings, err := projectSettings(r.Context(), h.settings, task.ProjectID)
+		if err != nil {
+			writeServerError(w, r, "failed to get project settings", err)
+			return
+		}
+		if settings.DefaultAssigneeID != nil {
+			task.AssignTo(*settings.DefaultAssigneeID)
+		}
+	}
+
+	rank, err := h.lastRank(r.Context(), task.ProjectID)
+	if err != nil {
+		writeServerError(w, r, "failed to rank task", err)
+		return
+	}
+	task.Rank = rank
+
+	ctx, warnings := withWarnings(r.Context())
+	if err := h.store.Create(ctx, task); err != nil {
+		if writeHookRejection(w, err) {
+			return
+		}
+		if errors.Is(err, ErrTaskExists) {
+			http.Error(w, "a task with this id already exists", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, models.ErrUnknownStatus) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		if errors.Is(err, models.ErrWIPLimitReached) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		writeServerError(w, r, "failed to create task", err)
+		return
+	}
+
+	resp := toResponse(task)
+	resp.DuplicateCandidates = duplicates
+	warnings.write(w)
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// findDuplicates returns the open tasks that look like duplicates of a
+// new task under its project's duplicate check, and whether the check
+// blocks creating it.
+func (h *TaskHandler) findDuplicates(ctx context.Context, task *models.Task) ([]models.TaskID, bool, error) {
+	if h.projects == nil {
+		return nil, false, nil
+	}
+	project, err := h.projects.Get(ctx, task.ProjectID)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	if !project.Duplicates.Enabled() {
+		return nil, false, nil
+	}
+
+	tasks, err := h.store.GetAll(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	duplicates := project.Duplicates.FindDuplicates(task, tasks)
+	if len(duplicates) == 0 {
+		return nil, false, nil
+	}
+	return duplicates, project.Duplicates.Mode == models.DuplicateModeBlock, nil
+}
+
+// toRenderedResponse converts a Task to a TaskResponse, rendering the
+// description to HTML if html is set.
+func toRenderedResponse(task *models.Task, html bool) *TaskResponse {
+	resp := toResponse(task)
+	if html {
+		resp.DescriptionHTML = markdown.Render(task.Description)
+	}
+	return resp
 }
 
 // Get handles GET /tasks/{id} requests.
-func (h *TaskHandler) Get(w http.ResponseWriter, r *http.Request, id string) {
-	task, err := h.store.Get(r.Context(), id)
+//
+// An optional as_of query parameter (RFC 3339) returns the task as it
+// was at that time, if the store keeps history. With ?render=html the
+// response also carries the description rendered as sanitized HTML.
+// Other users' drafts are reported as not found.
+func (h *TaskHandler) Get(w http.ResponseWriter, r *http.Request, id models.TaskID) {
+	html, err := renderHTML(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var task *models.Task
+	if raw := r.URL.Query().Get("as_of"); raw != "" {
+		at, perr := time.Parse(time.RFC3339, raw)
+		if perr != nil {
+			http.Error(w, "as_of must be an RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		temporal, ok := h.store.(TemporalTaskStore)
+		if !ok {
+			http.Error(w, "as_of is not supported by this store", http.StatusNotImplemented)
+			return
+		}
+		task, err = temporal.AsOf(r.Context(), id, at)
+	} else {
+		task, err = h.store.Get(r.Context(), id)
+	}
+	if err == nil && !visibleTo(r.Context(), task) {
+		err = ErrTaskNotFound
+	}
 	if err != nil {
 		if errors.Is(err, ErrTaskNotFound) {
 			http.Error(w, "task not found", http.StatusNotFound)
 			return
 		}
-		http.Error(w, "failed to get task", http.StatusInternalServerError)
+		writeServerError(w, r, "failed to get task", err)
+		return
+	}
+
+	resp := toRenderedResponse(task, html)
+	if err := h.withSLA(r.Context(), resp, task); err != nil {
+		writeServerError(w, r, "failed to evaluate SLA", err)
+		return
+	}
+	if err := h.withReactions(r.Context(), resp); err != nil {
+		writeServerError(w, r, "failed to list reactions", err)
+		return
+	}
+	if err := h.withLock(r.Context(), resp); err != nil {
+		writeServerError(w, r, "failed to get edit lock", err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(toResponse(task))
+	json.NewEncoder(w).Encode(resp)
 }
 
 // List handles GET /tasks requests.
+//
+// With ?render=html each description is also rendered as sanitized HTML.
+// When SLAs are configured, ?sla=ok|at_risk|breached|met keeps only
+// tasks whose worst SLA timer is in that state. ?sort=rank returns
+// tasks in their manual order and ?sort=votes the most voted first.
+// With stale detection, ?stale=true keeps only stale tasks and
+// ?stale=false only the rest. Other users' drafts are omitted, and so
+// are tasks of archived projects unless ?include_archived=true.
 func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
+	html, err := renderHTML(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sortBy := r.URL.Query().Get("sort")
+	if sortBy != "" && sortBy != "rank" && sortBy != "votes" {
+		http.Error(w, "invalid sort", http.StatusBadRequest)
+		return
+	}
+
+	slaFilter := models.SLAState(r.URL.Query().Get("sla"))
+	switch slaFilter {
+	case "":
+	case models.SLAStateOK, models.SLAStateAtRisk, models.SLAStateBreached, models.SLAStateMet:
+		if h.slas == nil {
+			http.Error(w, "sla filter is not supported", http.StatusNotImplemented)
+			return
+		}
+	default:
+		http.Error(w, "invalid sla filter", http.StatusBadRequest)
+		return
+	}
+
+	staleFilter := r.URL.Query().Get("stale")
+	switch staleFilter {
+	case "":
+	case "true", "false":
+		if h.stale == nil {
+			http.Error(w, "stale filter is not supported", http.StatusNotImplemented)
+			return
+		}
+	default:
+		http.Error(w, "invalid stale filter", http.StatusBadRequest)
+		return
+	}
+
+	archived, ok := h.archivedFilter(w, r)
+	if !ok {
+		return
+	}
+
 	tasks, err := h.store.GetAll(r.Context())
 	if err != nil {
-		http.Error(w, "failed to list tasks", http.StatusInternalServerError)
+		writeServerError(w, r, "failed to list tasks", err)
 		return
 	}
+	tasks = visibleTasks(r.Context(), tasks)
+	switch sortBy {
+	case "rank":
+		models.SortByRank(tasks)
+	case "votes":
+		models.SortByVotes(tasks)
+	}
 
-	responses := make([]*TaskResponse, len(tasks))
-	for i, task := range tasks {
-		responses[i] = toResponse(task)
+	responses := make([]*TaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		if archived[task.ProjectID] {
+			continue
+		}
+		if staleFilter != "" && h.stale.IsStale(task) != (staleFilter == "true") {
+			continue
+		}
+		resp := toRenderedResponse(task, html)
+		if err := h.withSLA(r.Context(), resp, task); err != nil {
+			writeServerError(w, r, "failed to evaluate SLA", err)
+			return
+		}
+		if slaFilter != "" && (resp.SLA == nil || resp.SLA.Summary() != slaFilter) {
+			continue
+		}
+		if err := h.withReactions(r.Context(), resp); err != nil {
+			writeServerError(w, r, "failed to list reactions", err)
+			return
+		}
+		if err := h.withLock(r.Context(), resp); err != nil {
+			writeServerError(w, r, "failed to get edit lock", err)
+			return
+		}
+		responses = append(responses, resp)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -212,38 +772,338 @@ func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 }
 
 // Complete handles POST /tasks/{id}/complete requests.
-func (h *TaskHandler) Complete(w http.ResponseWriter, r *http.Request, id string) {
+//
+// In projects that require approval the task moves to awaiting review
+// instead and its reviewer, if any, is notified. Completing a task
+// already awaiting review yields 409.
+func (h *TaskHandler) Complete(w http.ResponseWriter, r *http.Request, id models.TaskID) {
+	review, ok := h.approvalRequired(w, r, id)
+	if !ok {
+		return
+	}
+	task := h.transition(w, r, id, func(task *models.Task) bool {
+		if review {
+			return task.SubmitForReview()
+		}
+		if task.Status == models.TaskStatusAwaitingReview {
+			return false
+		}
+		task.MarkComplete()
+		return true
+	})
+	if review && task != nil {
+		h.requestReview(r.Context(), task)
+	}
+}
+
+// SetStatusRequest is the request body for changing a task's status.
+type SetStatusRequest struct {
+	Status models.TaskStatus `json:"status"`
+}
+
+// SetStatus handles POST /tasks/{id}/status requests, moving a task to
+// any status its project's workflow allows. Moving a task to the status
+// it already has, or into or out of review, yields 409. Moving a task
+// to completed in a project that requires approval submits it for
+// review, notifying its reviewer.
+func (h *TaskHandler) SetStatus(w http.ResponseWriter, r *http.Request, id models.TaskID) {
+	var req SetStatusRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if req.Status == "" {
+		http.Error(w, "status is required", http.StatusBadRequest)
+		return
+	}
+
+	review := false
+	if req.Status == models.TaskStatusCompleted {
+		var ok bool
+		if review, ok = h.approvalRequired(w, r, id); !ok {
+			return
+		}
+	}
+	task := h.transition(w, r, id, func(task *models.Task) bool {
+		if review {
+			return task.SubmitForReview()
+		}
+		return task.SetStatus(req.Status)
+	})
+	if review && task != nil {
+		h.requestReview(r.Context(), task)
+	}
+}
+
+// BlockTaskRequest is the request body for blocking a task.
+type BlockTaskRequest struct {
+	Reason         string         `json:"reason,omitempty"`
+	BlockingTaskID *models.TaskID `json:"blocking_task_id,omitempty"`
+}
+
+// Block handles POST /tasks/{id}/block requests.
+func (h *TaskHandler) Block(w http.ResponseWriter, r *http.Request, id models.TaskID) {
+	var req BlockTaskRequest
+	if err := decodeJSON(w, r, &req); err != nil && !errors.Is(err, io.EOF) {
+		writeDecodeError(w, err)
+		return
+	}
+
+	reason, err := models.SanitizeDescription(req.Reason)
+	if err != nil {
+		writeContentError(w, err)
+		return
+	}
+
+	if req.BlockingTaskID != nil {
+		if *req.BlockingTaskID == id {
+			http.Error(w, "a task cannot block itself", http.StatusBadRequest)
+			return
+		}
+		if _, err := h.store.Get(r.Context(), *req.BlockingTaskID); err != nil {
+			if errors.Is(err, ErrTaskNotFound) {
+				http.Error(w, "blocking task not found", http.StatusBadRequest)
+				return
+			}
+			writeServerError(w, r, "failed to get blocking task", err)
+			return
+		}
+	}
+
+	h.transition(w, r, id, func(task *models.Task) bool {
+		if task.Status == models.TaskStatusAwaitingReview {
+			return false
+		}
+		task.BlockOn(reason, req.BlockingTaskID)
+		return true
+	})
+}
+
+// Unblock handles POST /tasks/{id}/unblock requests.
+//
+// The blocked reason is cleared and the task returns to in progress.
+// Unblocking a task that is not blocked yields 409.
+func (h *TaskHandler) Unblock(w http.ResponseWriter, r *http.Request, id models.TaskID) {
+	h.transition(w, r, id, func(task *models.Task) bool {
+		return task.Unblock()
+	})
+}
+
+// transition applies change to a copy of the task and stores it. If
+// change returns false, or the project's workflow does not allow the
+// resulting status or its WIP limit is reached, the request fails with
+// 409 and nothing is stored. The caller needs the write permission on
+// the task. WIP limit warnings are sent as Warning headers.
+// It returns the stored task, or nil if the request failed.
+func (h *TaskHandler) transition(w http.ResponseWriter, r *http.Request, id models.TaskID, change func(*models.Task) bool) *models.Task {
 	task, err := h.store.Get(r.Context(), id)
+	if err == nil && !visibleTo(r.Context(), task) {
+		err = ErrTaskNotFound
+	}
 	if err != nil {
 		if errors.Is(err, ErrTaskNotFound) {
 			http.Error(w, "task not found", http.StatusNotFound)
-			return
+			return nil
 		}
-		http.Error(w, "failed to get task", http.StatusInternalServerError)
-		return
+		writeServerError(w, r, "failed to get task", err)
+		return nil
+	}
+	if !requireTaskAction(w, r, "write", task) {
+		return nil
 	}
 
-	task.MarkComplete()
+	task = task.Clone()
+	if !change(task) {
+		http.Error(w, "task is not in a state that allows this change", http.StatusConflict)
+		return nil
+	}
 
-	if err := h.store.Update(r.Context(), task); err != nil {
-		http.Error(w, "failed to update task", http.StatusInternalServerError)
-		return
+	ctx, warnings := withWarnings(r.Context())
+	if err := h.store.Update(ctx, task); err != nil {
+		if errors.Is(err, ErrVersionConflict) {
+			http.Error(w, "task was modified concurrently", http.StatusConflict)
+			return nil
+		}
+		if errors.Is(err, models.ErrUnknownStatus) || errors.Is(err, models.ErrTransitionNotAllowed) || errors.Is(err, models.ErrWIPLimitReached) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return nil
+		}
+		if writeHookRejection(w, err) {
+			return nil
+		}
+		writeServerError(w, r, "failed to update task", err)
+		return nil
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(toResponse(task))
+	warnings.write(w)
+	writeJSON(w, http.StatusOK, toResponse(task))
+	return task
 }
 
-// Delete handles DELETE /tasks/{id} requests.
-func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request, id string) {
+// Delete handles DELETE /tasks/{id} requests. The caller needs the
+// delete permission on the task.
+func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request, id models.TaskID) {
+	task, err := h.store.Get(r.Context(), id)
+	if err == nil && !visibleTo(r.Context(), task) {
+		err = ErrTaskNotFound
+	}
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			http.Error(w, "task not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get task", err)
+		return
+	}
+	if !requireTaskAction(w, r, "delete", task) {
+		return
+	}
+
 	if err := h.store.Delete(r.Context(), id); err != nil {
 		if errors.Is(err, ErrTaskNotFound) {
 			http.Error(w, "task not found", http.StatusNotFound)
 			return
 		}
-		http.Error(w, "failed to delete task", http.StatusInternalServerError)
+		if writeHookRejection(w, err) {
+			return
+		}
+		writeServerError(w, r, "failed to delete task", err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// maxBulkCloneTasks caps the number of tasks a bulk clone may create.
+const maxBulkCloneTasks = 500
+
+// CloneTaskRequest is the request body for cloning a task.
+//
+// An empty Title keeps the source task's title.
+type CloneTaskRequest struct {
+	models.DuplicateOptions
+	Title string `json:"title,omitempty"`
+}
+
+// Clone handles POST /tasks/{id}/clone requests.
+//
+// The body is optional; without it only the core fields are copied.
+// Cloning a task the caller may not see yields 404.
+func (h *TaskHandler) Clone(w http.ResponseWriter, r *http.Request, id models.TaskID) {
+	var req CloneTaskRequest
+	if err := decodeJSON(w, r, &req); err != nil && !errors.Is(err, io.EOF) {
+		writeDecodeError(w, err)
+		return
+	}
+
+	source, err := h.store.Get(r.Context(), id)
+	if err == nil && !visibleTo(r.Context(), source) {
+		err = ErrTaskNotFound
+	}
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			http.Error(w, "task not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get task", err)
+		return
+	}
+
+	task := source.Duplicate(req.DuplicateOptions)
+	if req.Title != "" {
+		task.Title = req.Title
+	}
+	if err := task.SanitizeContent(); err != nil {
+		writeContentError(w, err)
+		return
+	}
+
+	if err := h.store.Create(r.Context(), task); err != nil {
+		if writeHookRejection(w, err) {
+			return
+		}
+		writeServerError(w, r, "failed to create task", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toResponse(task))
+}
+
+// BulkCloneRequest is the request body for cloning several tasks.
+//
+// Each listed task is cloned Copies times (at least once). When
+// TitleSuffix is set, each copy's title gets the suffix with {n}
+// replaced by the copy's 1-based number.
+type BulkCloneRequest struct {
+	models.DuplicateOptions
+	TaskIDs     []models.TaskID `json:"task_ids"`
+	Copies      int             `json:"copies,omitempty"`
+	TitleSuffix string          `json:"title_suffix,omitempty"`
+}
+
+// BulkClone handles POST /tasks/clone requests.
+//
+// All source tasks are loaded before any copy is created, so an unknown
+// ID, or that of a task the caller may not see, fails the request
+// without side effects.
+func (h *TaskHandler) BulkClone(w http.ResponseWriter, r *http.Request) {
+	var req BulkCloneRequest
+	if err := decodeJSONLimit(w, r, &req, maxBulkRequestBodyBytes); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if len(req.TaskIDs) == 0 {
+		http.Error(w, "task_ids is required", http.StatusBadRequest)
+		return
+	}
+	copies := req.Copies
+	if copies < 1 {
+		copies = 1
+	}
+	if len(req.TaskIDs)*copies > maxBulkCloneTasks {
+		http.Error(w, fmt.Sprintf("at most %d tasks may be cloned at once", maxBulkCloneTasks), http.StatusBadRequest)
+		return
+	}
+
+	sources := make([]*models.Task, 0, len(req.TaskIDs))
+	for _, id := range req.TaskIDs {
+		task, err := h.store.Get(r.Context(), id)
+		if err == nil && !visibleTo(r.Context(), task) {
+			err = ErrTaskNotFound
+		}
+		if err != nil {
+			if errors.Is(err, ErrTaskNotFound) {
+				http.Error(w, "task not found: "+string(id), http.StatusNotFound)
+				return
+			}
+			writeServerError(w, r, "failed to get task", err)
+			return
+		}
+		sources = append(sources, task)
+	}
+
+	created := make([]*TaskResponse, 0, len(sources)*copies)
+	for _, source := range sources {
+		for n := 1; n <= copies; n++ {
+			task := source.Duplicate(req.DuplicateOptions)
+			if req.TitleSuffix != "" {
+				task.Title += strings.ReplaceAll(req.TitleSuffix, "{n}", strconv.Itoa(n))
+			}
+			if err := task.SanitizeContent(); err != nil {
+				writeContentError(w, err)
+				return
+			}
+			if err := h.store.Create(r.Context(), task); err != nil {
+				if writeHookRejection(w, err) {
+					return
+				}
+				writeServerError(w, r, "failed to create task", err)
+				return
+			}
+			created = append(created, toResponse(task))
+		}
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
@@ -0,0 +1,256 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// TaskKeyStore is a TaskStore decorator that gives each new task in a
+// project with a key a sequential, human-readable key, such as
+// "API-142", and lets tasks be read and deleted by key as well as ID.
+//
+// Keys are numbered per project key and never reused, even after a task
+// is deleted: the last number given out is kept on the project, so it
+// survives restarts. A task keeps its key for life, including when it
+// moves to another project. Tasks that already have a key when created,
+// such as restored ones, keep it.
+type TaskKeyStore struct {
+	next     TaskStore
+	projects ProjectStore
+
+	mu     sync.Mutex
+	loaded bool
+	seq    map[string]int
+	keys   map[string]models.TaskID
+}
+
+// NewTaskKeyStore wraps a task store with task keys.
+func NewTaskKeyStore(next TaskStore, projects ProjectStore) *TaskKeyStore {
+	return &TaskKeyStore{next: next, projects: projects}
+}
+
+// load indexes the keys of the stored tasks and the key numbers of the
+// projects on first use. Tasks are read without the caller's user, so
+// tasks hidden from them are indexed too. It must be called with s.mu
+// held.
+func (s *TaskKeyStore) load(ctx context.Context) error {
+	if s.loaded {
+		return nil
+	}
+	tasks, err := s.next.GetAll(WithUser(ctx, nil))
+	if err != nil {
+		return err
+	}
+	projects, err := s.projects.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	s.seq = make(map[string]int)
+	s.keys = make(map[string]models.TaskID, len(tasks))
+	for _, task := range tasks {
+		s.index(task)
+	}
+	for _, project := range projects {
+		if project.Key != "" {
+			s.seq[project.Key] = max(s.seq[project.Key], project.TaskKeySeq)
+		}
+	}
+	s.loaded = true
+	return nil
+}
+
+// index records a task's key. It must be called with s.mu held.
+func (s *TaskKeyStore) index(task *models.Task) {
+	prefix, n, ok := models.ParseTaskKey(task.Key)
+	if !ok {
+		return
+	}
+	s.keys[models.FormatTaskKey(prefix, n)] = task.ID
+	s.seq[prefix] = max(s.seq[prefix], n)
+}
+
+// Resolve returns the ID of the task a task ID or key names. IDs are
+// returned unchanged; unknown keys yield ErrTaskNotFound.
+//
+// Routers should resolve task path parameters with it, so endpoints
+// backed by other stores accept keys too.
+func (s *TaskKeyStore) Resolve(ctx context.Context, id models.TaskID) (models.TaskID, error) {
+	prefix, n, ok := models.ParseTaskKey(string(id))
+	if !ok {
+		return id, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(ctx); err != nil {
+		return "", err
+	}
+	taskID, ok := s.keys[models.FormatTaskKey(prefix, n)]
+	if !ok {
+		return "", ErrTaskNotFound
+	}
+	return taskID, nil
+}
+
+// Get retrieves a task by ID or key.
+func (s *TaskKeyStore) Get(ctx context.Context, id models.TaskID) (*models.Task, error) {
+	id, err := s.Resolve(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.next.Get(ctx, id)
+}
+
+// GetAll retrieves all tasks.
+func (s *TaskKeyStore) GetAll(ctx context.Context) ([]*models.Task, error) {
+	return s.next.GetAll(ctx)
+}
+
+// Create stores a new task, giving it the next key of its project if
+// the project has a key. Numbering is serialized, so concurrent creates
+// never share a key, and the number is saved on the project before the
+// task is stored.
+func (s *TaskKeyStore) Create(ctx context.Context, task *models.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(ctx); err != nil {
+		return err
+	}
+	assigned := false
+	if task.Key == "" {
+		project, err := s.projects.Get(ctx, task.ProjectID)
+		if err != nil && !errors.Is(err, ErrProjectNotFound) {
+			return err
+		}
+		if err == nil && project.Key != "" {
+			n := max(s.seq[project.Key], project.TaskKeySeq) + 1
+			numbered := *project
+			numbered.TaskKeySeq = n
+			if err := s.projects.Update(ctx, &numbered); err != nil {
+				return err
+			}
+			s.seq[project.Key] = n
+			task.Key = models.FormatTaskKey(project.Key, n)
+			assigned = true
+		}
+	}
+	if err := s.next.Create(ctx, task); err != nil {
+		if assigned {
+			task.Key = ""
+		}
+		return err
+	}
+	s.index(task)
+	return nil
+}
+
+// Update updates an existing task, keeping its key.
+func (s *TaskKeyStore) Update(ctx context.Context, task *models.Task) error {
+	current, err := s.next.Get(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+	task.Key = current.Key
+	return s.next.Update(ctx, task)
+}
+
+// Delete removes a task by ID or key. Its key is not reused.
+func (s *TaskKeyStore) Delete(ctx context.Context, id models.TaskID) error {
+	id, err := s.Resolve(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.next.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, taskID := range s.keys {
+		if taskID == id {
+			delete(s.keys, key)
+		}
+	}
+	return nil
+}
+
+// SetProjectKeyRequest is the request body for setting a project's key.
+type SetProjectKeyRequest struct {
+	Key string `json:"key"`
+}
+
+// SetKey handles PUT /projects/{id}/key requests. A project's key can be
+// set once and must not be used by another project; setting the key it
+// already has succeeds without changes.
+func (h *ProjectHandler) SetKey(w http.ResponseWriter, r *http.Request, id models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req SetProjectKeyRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if err := models.ValidateProjectKey(req.Key); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	project, err := h.projects.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			http.Error(w, "project not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get project", err)
+		return
+	}
+	if project.Key == req.Key {
+		writeJSON(w, http.StatusOK, project)
+		return
+	}
+	if project.Key != "" {
+		http.Error(w, "project key cannot be changed", http.StatusConflict)
+		return
+	}
+	if !h.checkKeyAvailable(w, r, req.Key) {
+		return
+	}
+
+	updated := *project
+	updated.Key = req.Key
+	if err := h.projects.Update(r.Context(), &updated); err != nil {
+		if writeHookRejection(w, err) {
+			return
+		}
+		writeServerError(w, r, "failed to update project", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &updated)
+}
+
+// checkKeyAvailable writes 409 and returns false if another project
+// already has the key.
+func (h *ProjectHandler) checkKeyAvailable(w http.ResponseWriter, r *http.Request, key string) bool {
+	projects, err := h.projects.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list projects", err)
+		return false
+	}
+	for _, project := range projects {
+		if project.Key == key {
+			http.Error(w, "project key is already in use", http.StatusConflict)
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,194 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// TemplateStore defines the interface for task template storage.
+type TemplateStore interface {
+	// Get retrieves a template by ID.
+	Get(ctx context.Context, id string) (*models.TaskTemplate, error)
+	// ListForProject retrieves the project's templates and all global templates.
+	ListForProject(ctx context.Context, projectID models.ProjectID) ([]*models.TaskTemplate, error)
+	// Create stores a new template.
+	Create(ctx context.Context, template *models.TaskTemplate) error
+	// Delete removes a template by ID.
+	Delete(ctx context.Context, id string) error
+}
+
+// ErrTemplateNotFound is returned when a template is not found.
+var ErrTemplateNotFound = errors.New("template not found")
+
+// InMemoryTemplateStore is an in-memory implementation of TemplateStore.
+type InMemoryTemplateStore struct {
+	*InMemoryStore[string, *models.TaskTemplate]
+}
+
+// NewInMemoryTemplateStore creates a new in-memory template store.
+func NewInMemoryTemplateStore() *InMemoryTemplateStore {
+	return &InMemoryTemplateStore{NewInMemoryStore[string, *models.TaskTemplate](ErrTemplateNotFound)}
+}
+
+// ListForProject retrieves the project's templates and all global templates, by name.
+func (s *InMemoryTemplateStore) ListForProject(ctx context.Context, projectID models.ProjectID) ([]*models.TaskTemplate, error) {
+	return s.List(ctx, ListOptions[*models.TaskTemplate]{
+		Filter: func(t *models.TaskTemplate) bool { return t.AppliesTo(projectID) },
+		Less:   func(a, b *models.TaskTemplate) bool { return a.Name < b.Name },
+	})
+}
+
+// TemplateHandler handles HTTP requests for task templates.
+type TemplateHandler struct {
+	templates TemplateStore
+	tasks     TaskStore
+}
+
+// NewTemplateHandler creates a new template handler.
+func NewTemplateHandler(templates TemplateStore, tasks TaskStore) *TemplateHandler {
+	return &TemplateHandler{templates: templates, tasks: tasks}
+}
+
+// CreateTemplateRequest is the request body for creating a template.
+//
+// An empty ProjectID creates a global template.
+type CreateTemplateRequest struct {
+	Name         string           `json:"name"`
+	ProjectID    models.ProjectID `json:"project_id,omitempty"`
+	TitlePattern string           `json:"title_pattern"`
+	Description  string           `json:"description,omitempty"`
+	Checklist    []string         `json:"checklist,omitempty"`
+	Tags         []string         `json:"tags,omitempty"`
+	Priority     int              `json:"priority,omitempty"`
+	Estimate     float64          `json:"estimate,omitempty"`
+}
+
+// Create handles POST /templates requests.
+func (h *TemplateHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateTemplateRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	titlePattern, err := models.SanitizeTitle(req.TitlePattern)
+	if err != nil {
+		writeContentError(w, err)
+		return
+	}
+	description, err := models.SanitizeDescription(req.Description)
+	if err != nil {
+		writeContentError(w, err)
+		return
+	}
+
+	template, err := models.NewTaskTemplate(req.Name, titlePattern)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Estimate < 0 {
+		http.Error(w, "estimate cannot be negative", http.StatusBadRequest)
+		return
+	}
+	template.ProjectID = req.ProjectID
+	template.Description = description
+	template.Checklist = req.Checklist
+	template.Estimate = req.Estimate
+	if req.Tags != nil {
+		template.Tags = req.Tags
+	}
+	if req.Priority > 0 {
+		template.Priority = models.TaskPriority(req.Priority)
+	}
+
+	if err := h.templates.Create(r.Context(), template); err != nil {
+		writeServerError(w, r, "failed to create template", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, template)
+}
+
+// ListForProject handles GET /projects/{id}/templates requests.
+func (h *TemplateHandler) ListForProject(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	templates, err := h.templates.ListForProject(r.Context(), projectID)
+	if err != nil {
+		writeServerError(w, r, "failed to list templates", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, templates)
+}
+
+// Delete handles DELETE /templates/{id} requests.
+func (h *TemplateHandler) Delete(w http.ResponseWriter, r *http.Request, id string) {
+	if err := h.templates.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, ErrTemplateNotFound) {
+			http.Error(w, "template not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to delete template", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// FromTemplateRequest is the request body for creating a task from a template.
+type FromTemplateRequest struct {
+	ProjectID models.ProjectID  `json:"project_id"`
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// CreateFromTemplate handles POST /tasks/from-template/{id} requests.
+func (h *TemplateHandler) CreateFromTemplate(w http.ResponseWriter, r *http.Request, id string) {
+	var req FromTemplateRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	template, err := h.templates.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrTemplateNotFound) {
+			http.Error(w, "template not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get template", err)
+		return
+	}
+
+	projectID := req.ProjectID
+	if projectID == "" {
+		projectID = template.ProjectID
+	}
+	if projectID == "" {
+		http.Error(w, "project_id is required", http.StatusBadRequest)
+		return
+	}
+	if !template.AppliesTo(projectID) {
+		http.Error(w, "template belongs to another project", http.StatusBadRequest)
+		return
+	}
+
+	task, err := template.Instantiate(projectID, req.Variables)
+	if err == nil {
+		err = task.SanitizeContent()
+	}
+	if err != nil {
+		writeContentError(w, err)
+		return
+	}
+
+	if err := h.tasks.Create(r.Context(), task); err != nil {
+		writeServerError(w, r, "failed to create task", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, task)
+}
@@ -0,0 +1,181 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultRequestTimeout applies to routes without a configured timeout.
+const defaultRequestTimeout = 30 * time.Second
+
+// routeTimeout is the deadline budget for requests matching a method
+// and path prefix. An empty method matches any method.
+type routeTimeout struct {
+	method  string
+	prefix  string
+	timeout time.Duration
+}
+
+// Timeouts enforces a per-route deadline on each request.
+//
+// The deadline is carried by the request context, so stores and
+// downstream calls give up once it passes. If the handler has not
+// finished by then the client receives 504 with a problem+json body
+// and anything the handler writes later is discarded. Responses are
+// buffered until the handler returns.
+type Timeouts struct {
+	mu       sync.RWMutex
+	fallback time.Duration
+	routes   []routeTimeout
+}
+
+// NewTimeouts creates timeouts with the given default, or
+// defaultRequestTimeout if fallback is not positive.
+func NewTimeouts(fallback time.Duration) *Timeouts {
+	if fallback <= 0 {
+		fallback = defaultRequestTimeout
+	}
+	return &Timeouts{fallback: fallback}
+}
+
+// Set configures the timeout for requests with the given method, or any
+// method if empty, whose path starts with prefix. The longest matching
+// prefix wins, and a rule naming the method beats one that does not.
+// A non-positive timeout disables the deadline for those routes.
+func (t *Timeouts) Set(method, prefix string, timeout time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, rt := range t.routes {
+		if rt.method == method && rt.prefix == prefix {
+			t.routes[i].timeout = timeout
+			return
+		}
+	}
+	t.routes = append(t.routes, routeTimeout{method: method, prefix: prefix, timeout: timeout})
+}
+
+// For returns the timeout applied to a request.
+func (t *Timeouts) For(method, path string) time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	timeout, best := t.fallback, -1
+	for _, rt := range t.routes {
+		if rt.method != "" && rt.method != method || !strings.HasPrefix(path, rt.prefix) {
+			continue
+		}
+		score := 2 * len(rt.prefix)
+		if rt.method != "" {
+			score++
+		}
+		if score > best {
+			timeout, best = rt.timeout, score
+		}
+	}
+	return timeout
+}
+
+// Middleware enforces the route's timeout.
+//
+// A panic in the handler is re-raised on the serving goroutine so
+// Recoverer still sees it.
+func (t *Timeouts) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeout := t.For(r.Method, r.URL.Path)
+		if timeout <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		tw := &timeoutWriter{header: make(http.Header)}
+		done := make(chan struct{})
+		panicked := make(chan any, 1)
+		go func() {
+			defer func() {
+				if v := recover(); v != nil {
+					panicked <- v
+				}
+			}()
+			next.ServeHTTP(tw, r.WithContext(ctx))
+			close(done)
+		}()
+
+		select {
+		case v := <-panicked:
+			panic(v)
+		case <-done:
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			for k, v := range tw.header {
+				w.Header()[k] = v
+			}
+			if tw.status == 0 {
+				tw.status = http.StatusOK
+			}
+			w.WriteHeader(tw.status)
+			w.Write(tw.body.Bytes())
+		case <-ctx.Done():
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			tw.timedOut = true
+			if r.Context().Err() != nil {
+				// The client went away; there is nobody to answer.
+				return
+			}
+			writeProblem(w, &Problem{
+				Type:   "about:blank",
+				Title:  http.StatusText(http.StatusGatewayTimeout),
+				Status: http.StatusGatewayTimeout,
+				Detail: "request exceeded its " + timeout.String() + " deadline",
+			})
+		}
+	})
+}
+
+// timeoutWriter buffers a response until the handler returns, and
+// discards writes made after the deadline.
+type timeoutWriter struct {
+	mu       sync.Mutex
+	header   http.Header
+	body     bytes.Buffer
+	status   int
+	timedOut bool
+}
+
+// Header returns the buffered response headers.
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader records the response status.
+func (w *timeoutWriter) WriteHeader(status int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.status == 0 && !w.timedOut {
+		w.status = status
+	}
+}
+
+// Write buffers the response body, failing once the deadline has passed.
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
@@ -0,0 +1,304 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// defaultRecentAssignedHours is how recently a task must have been
+// assigned to count as recently assigned when no window is given.
+const defaultRecentAssignedHours = 24
+
+// FocusStore defines the interface for per-user focus list storage.
+type FocusStore interface {
+	// Get retrieves a user's focus list, returning an empty list if the
+	// user has none.
+	Get(ctx context.Context, userID models.UserID) (*models.FocusList, error)
+	// Put stores a user's focus list, replacing any existing one.
+	Put(ctx context.Context, list *models.FocusList) error
+}
+
+// InMemoryFocusStore is an in-memory implementation of FocusStore.
+type InMemoryFocusStore struct {
+	mu    sync.RWMutex
+	lists map[models.UserID]*models.FocusList
+}
+
+// NewInMemoryFocusStore creates a new in-memory focus list store.
+func NewInMemoryFocusStore() *InMemoryFocusStore {
+	return &InMemoryFocusStore{
+		lists: make(map[models.UserID]*models.FocusList),
+	}
+}
+
+// Get retrieves a copy of a user's focus list, returning an empty list
+// if the user has none.
+func (s *InMemoryFocusStore) Get(ctx context.Context, userID models.UserID) (*models.FocusList, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list, ok := s.lists[userID]
+	if !ok {
+		return models.NewFocusList(userID), nil
+	}
+	return list.Clone(), nil
+}
+
+// Put stores a user's focus list, replacing any existing one.
+func (s *InMemoryFocusStore) Put(ctx context.Context, list *models.FocusList) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lists[list.UserID] = list.Clone()
+	return nil
+}
+
+// TodayList is the response body for a user's daily worklist.
+//
+// Each task appears only in the first section it qualifies for, in the
+// order the sections are listed.
+type TodayList struct {
+	Date             string          `json:"date"`
+	Timezone         string          `json:"timezone"`
+	Pinned           []*TaskResponse `json:"pinned"`
+	Overdue          []*TaskResponse `json:"overdue"`
+	DueToday         []*TaskResponse `json:"due_today"`
+	InProgress       []*TaskResponse `json:"in_progress"`
+	RecentlyAssigned []*TaskResponse `json:"recently_assigned"`
+}
+
+// buildTodayList sorts a user's open tasks into worklist sections.
+//
+// Pinned tasks are listed in pin order whoever they are assigned to;
+// the other sections hold the user's unsnoozed tasks, most urgent first.
+func buildTodayList(userID models.UserID, tasks []*models.Task, focus *models.FocusList, recent time.Duration, at time.Time, loc *time.Location) *TodayList {
+	local := at.In(loc)
+	startOfDay := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	endOfDay := startOfDay.AddDate(0, 0, 1)
+
+	list := &TodayList{
+		Date:             startOfDay.Format(calendarDateLayout),
+		Timezone:         loc.String(),
+		Pinned:           make([]*TaskResponse, 0),
+		Overdue:          make([]*TaskResponse, 0),
+		DueToday:         make([]*TaskResponse, 0),
+		InProgress:       make([]*TaskResponse, 0),
+		RecentlyAssigned: make([]*TaskResponse, 0),
+	}
+
+	byID := make(map[models.TaskID]*models.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	for _, id := range focus.Pinned {
+		if task, ok := byID[id]; ok && task.IsOpen() {
+			list.Pinned = append(list.Pinned, toResponse(task))
+		}
+	}
+
+	var overdue, dueToday, inProgress, recentlyAssigned []*models.Task
+	for _, task := range tasks {
+		if !task.IsOpen() || task.AssigneeID == nil || *task.AssigneeID != userID {
+			continue
+		}
+		if focus.IsPinned(task.ID) || focus.IsSnoozedAt(task.ID, at) {
+			continue
+		}
+		switch {
+		case task.IsOverdueAt(at):
+			overdue = append(overdue, task)
+		case task.DueDate != nil && task.DueDate.Before(endOfDay):
+			dueToday = append(dueToday, task)
+		case task.Status == models.TaskStatusInProgress:
+			inProgress = append(inProgress, task)
+		case !task.AssignedAt.IsZero() && at.Sub(task.AssignedAt) <= recent:
+			recentlyAssigned = append(recentlyAssigned, task)
+		}
+	}
+
+	for _, section := range []struct {
+		tasks []*models.Task
+		dst   *[]*TaskResponse
+	}{
+		{overdue, &list.Overdue},
+		{dueToday, &list.DueToday},
+		{inProgress, &list.InProgress},
+		{recentlyAssigned, &list.RecentlyAssigned},
+	} {
+		sortByUrgency(section.tasks)
+		for _, task := range section.tasks {
+			*section.dst = append(*section.dst, toResponse(task))
+		}
+	}
+	return list
+}
+
+// sortByUrgency sorts tasks by priority, highest first, then by due
+// date, earliest first, with undated tasks last.
+func sortByUrgency(tasks []*models.Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if a.DueDate == nil || b.DueDate == nil {
+			return a.DueDate != nil
+		}
+		return a.DueDate.Before(*b.DueDate)
+	})
+}
+
+// TodayHandler handles HTTP requests for the authenticated user's daily
+// worklist.
+type TodayHandler struct {
+	tasks    TaskStore
+	projects ProjectStore
+	focus    FocusStore
+}
+
+// NewTodayHandler creates a new daily worklist handler. projects may be
+// nil, in which case no project counts as archived.
+func NewTodayHandler(tasks TaskStore, projects ProjectStore, focus FocusStore) *TodayHandler {
+	return &TodayHandler{tasks: tasks, projects: projects, focus: focus}
+}
+
+// Get handles GET /me/today requests.
+//
+// "Today" is the current day in the user's preferred timezone. An
+// optional recent_hours parameter sets how recently a task must have
+// been assigned to be listed as recently assigned. Drafts and the tasks
+// of archived projects are not listed.
+func (h *TodayHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	hours := defaultRecentAssignedHours
+	if raw := r.URL.Query().Get("recent_hours"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			http.Error(w, "invalid recent_hours", http.StatusBadRequest)
+			return
+		}
+		hours = n
+	}
+
+	focus, err := h.focus.Get(r.Context(), user.ID)
+	if err != nil {
+		writeServerError(w, r, "failed to get focus list", err)
+		return
+	}
+	tasks, err := h.tasks.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+	archived, err := archivedProjects(r.Context(), h.projects)
+	if err != nil {
+		writeServerError(w, r, "failed to list projects", err)
+		return
+	}
+	tasks = slices.DeleteFunc(visibleTasks(r.Context(), publishedTasks(tasks)), func(task *models.Task) bool {
+		return archived[task.ProjectID]
+	})
+
+	list := buildTodayList(user.ID, tasks, focus, time.Duration(hours)*time.Hour, time.Now(), user.Preferences.Location())
+	writeJSON(w, http.StatusOK, list)
+}
+
+// SnoozeRequest is the request body for snoozing a task.
+type SnoozeRequest struct {
+	Until time.Time `json:"until"`
+}
+
+// Pin handles PUT /me/today/pins/{id} requests.
+func (h *TodayHandler) Pin(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	if !h.requireTask(w, r, taskID) {
+		return
+	}
+	h.updateFocus(w, r, taskID, func(focus *models.FocusList) {
+		focus.Pin(taskID)
+	})
+}
+
+// Unpin handles DELETE /me/today/pins/{id} requests.
+func (h *TodayHandler) Unpin(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	h.updateFocus(w, r, taskID, func(focus *models.FocusList) {
+		focus.Unpin(taskID)
+	})
+}
+
+// Snooze handles PUT /me/today/snoozes/{id} requests.
+func (h *TodayHandler) Snooze(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	var req SnoozeRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if !req.Until.After(time.Now()) {
+		http.Error(w, "until must be in the future", http.StatusBadRequest)
+		return
+	}
+	if !h.requireTask(w, r, taskID) {
+		return
+	}
+
+	h.updateFocus(w, r, taskID, func(focus *models.FocusList) {
+		focus.Snooze(taskID, req.Until)
+	})
+}
+
+// Unsnooze handles DELETE /me/today/snoozes/{id} requests.
+func (h *TodayHandler) Unsnooze(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	h.updateFocus(w, r, taskID, func(focus *models.FocusList) {
+		focus.Unsnooze(taskID)
+	})
+}
+
+// requireTask writes a 404 and returns false if the task does not exist.
+func (h *TodayHandler) requireTask(w http.ResponseWriter, r *http.Request, taskID models.TaskID) bool {
+	if _, err := h.tasks.Get(r.Context(), taskID); err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			http.Error(w, "task not found", http.StatusNotFound)
+			return false
+		}
+		writeServerError(w, r, "failed to get task", err)
+		return false
+	}
+	return true
+}
+
+// updateFocus applies fn to the authenticated user's focus list and
+// writes the updated list.
+func (h *TodayHandler) updateFocus(w http.ResponseWriter, r *http.Request, taskID models.TaskID, fn func(*models.FocusList)) {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	focus, err := h.focus.Get(r.Context(), user.ID)
+	if err != nil {
+		writeServerError(w, r, "failed to get focus list", err)
+		return
+	}
+	focus.Prune(time.Now())
+	fn(focus)
+	if err := h.focus.Put(r.Context(), focus); err != nil {
+		writeServerError(w, r, "failed to save focus list", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, focus)
+}
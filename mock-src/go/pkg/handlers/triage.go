@@ -0,0 +1,244 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// maxTriageTasks caps the number of tasks one bulk triage may change.
+const maxTriageTasks = 500
+
+// Reasons a task is in the triage queue.
+const (
+	triageUnprioritized = "unprioritized"
+	triageUnassigned    = "unassigned"
+	triageUntagged      = "untagged"
+	triageNeedsTriage   = "needs_triage"
+)
+
+// TriageItem is a task in the triage queue with the reasons it is there.
+type TriageItem struct {
+	Task    *TaskResponse `json:"task"`
+	Reasons []string      `json:"reasons"`
+}
+
+// triageReasons returns why an open task needs triage: it has no
+// priority from its project's scheme, no assignee, or no tags, or stale
+// detection moved it to the triage status. scheme may be nil.
+func (h *TaskHandler) triageReasons(task *models.Task, scheme *models.PriorityScheme) []string {
+	var reasons []string
+	if task.Priority == 0 || scheme != nil && !scheme.Has(task.Priority) {
+		reasons = append(reasons, triageUnprioritized)
+	}
+	if task.AssigneeID == nil {
+		reasons = append(reasons, triageUnassigned)
+	}
+	if len(task.Tags) == 0 {
+		reasons = append(reasons, triageUntagged)
+	}
+	if h.stale != nil {
+		if status := h.stale.Policy().TriageStatus; status != "" && task.Status == status {
+			reasons = append(reasons, triageNeedsTriage)
+		}
+	}
+	return reasons
+}
+
+// Triage handles GET /projects/{id}/triage requests, listing the open
+// tasks that need grooming, oldest first.
+//
+// An optional reason parameter (unprioritized, unassigned, untagged or
+// needs_triage) keeps only tasks queued for that reason. Drafts are
+// omitted.
+func (h *TaskHandler) Triage(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	reason := r.URL.Query().Get("reason")
+	switch reason {
+	case "", triageUnprioritized, triageUnassigned, triageUntagged, triageNeedsTriage:
+	default:
+		http.Error(w, "invalid reason", http.StatusBadRequest)
+		return
+	}
+
+	var scheme *models.PriorityScheme
+	if h.priorities != nil {
+		var err error
+		if scheme, err = projectPriorityScheme(r.Context(), h.priorities, projectID); err != nil {
+			writeServerError(w, r, "failed to get priority scheme", err)
+			return
+		}
+	}
+	tasks, err := h.store.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+	tasks = publishedTasks(tasks)
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].CreatedAt.Before(tasks[j].CreatedAt) })
+
+	items := make([]*TriageItem, 0)
+	for _, task := range tasks {
+		if task.ProjectID != projectID || !task.IsOpen() {
+			continue
+		}
+		reasons := h.triageReasons(task, scheme)
+		if len(reasons) == 0 || reason != "" && !containsString(reasons, reason) {
+			continue
+		}
+		items = append(items, &TriageItem{Task: toResponse(task), Reasons: reasons})
+	}
+
+	writeJSON(w, http.StatusOK, items)
+}
+
+// BulkTriageRequest is the request body for triaging several tasks.
+//
+// Each set field is applied to every listed task: Priority and
+// AssigneeID replace the task's, an empty AssigneeID unassigns, AddTags
+// are added to its tags, and Status moves it through SetStatus. Tasks of
+// a project that requires approval are submitted for review instead of
+// being completed.
+type BulkTriageRequest struct {
+	TaskIDs    []models.TaskID      `json:"task_ids"`
+	Priority   *models.TaskPriority `json:"priority,omitempty"`
+	AssigneeID *models.UserID       `json:"assignee_id,omitempty"`
+	AddTags    []string             `json:"add_tags,omitempty"`
+	Status     *models.TaskStatus   `json:"status,omitempty"`
+}
+
+// BulkTriage handles POST /projects/{id}/triage requests, returning the
+// updated tasks.
+//
+// Every listed task is checked to belong to the project before any is
+// changed. The store has no transactions, so a failure part way leaves
+// the tasks before it triaged.
+func (h *TaskHandler) BulkTriage(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	var req BulkTriageRequest
+	if err := decodeJSONLimit(w, r, &req, maxBulkRequestBodyBytes); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if len(req.TaskIDs) == 0 {
+		http.Error(w, "task_ids is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.TaskIDs) > maxTriageTasks {
+		http.Error(w, fmt.Sprintf("at most %d tasks may be triaged at once", maxTriageTasks), http.StatusBadRequest)
+		return
+	}
+	if req.Priority == nil && req.AssigneeID == nil && len(req.AddTags) == 0 && req.Status == nil {
+		http.Error(w, "nothing to change", http.StatusBadRequest)
+		return
+	}
+	if req.Status != nil && (*req.Status == models.TaskStatusAwaitingReview || *req.Status == "") {
+		http.Error(w, "invalid status", http.StatusBadRequest)
+		return
+	}
+	for _, tag := range req.AddTags {
+		if strings.TrimSpace(tag) == "" {
+			http.Error(w, "tags must not be blank", http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Priority != nil && h.priorities != nil {
+		scheme, err := projectPriorityScheme(r.Context(), h.priorities, projectID)
+		if err != nil {
+			writeServerError(w, r, "failed to get priority scheme", err)
+			return
+		}
+		if !scheme.Has(*req.Priority) {
+			http.Error(w, models.ErrUnknownPriority.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	approval := false
+	if req.Status != nil && *req.Status == models.TaskStatusCompleted {
+		var err error
+		if approval, err = projectRequiresApproval(r.Context(), h.projects, projectID); err != nil {
+			writeServerError(w, r, "failed to get project", err)
+			return
+		}
+	}
+
+	listed := make(map[models.TaskID]bool, len(req.TaskIDs))
+	for _, id := range req.TaskIDs {
+		if listed[id] {
+			http.Error(w, "task "+string(id)+" is listed twice", http.StatusBadRequest)
+			return
+		}
+		listed[id] = true
+		task, err := h.store.Get(r.Context(), id)
+		if err != nil || task.ProjectID != projectID || !visibleTo(r.Context(), task) {
+			if err == nil || errors.Is(err, ErrTaskNotFound) {
+				http.Error(w, "task "+string(id)+" is not in the project", http.StatusBadRequest)
+				return
+			}
+			http.Error(w, "failed to get task", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	updated := make([]*TaskResponse, 0, len(req.TaskIDs))
+	for _, id := range req.TaskIDs {
+		submitted := false
+		task, err := updateTask(r.Context(), h.store, id, func(t *models.Task) bool {
+			changed := applyTriage(t, &req, approval)
+			submitted = changed && t.Status == models.TaskStatusAwaitingReview
+			return changed
+		})
+		if err != nil {
+			if writeHookRejection(w, err) {
+				return
+			}
+			if errors.Is(err, models.ErrUnknownStatus) || errors.Is(err, models.ErrTransitionNotAllowed) || errors.Is(err, models.ErrWIPLimitReached) {
+				http.Error(w, "task "+string(id)+": "+err.Error(), http.StatusConflict)
+				return
+			}
+			writeServerError(w, r, "failed to update task", err)
+			return
+		}
+		if submitted {
+			h.requestReview(r.Context(), task)
+		}
+		updated = append(updated, toResponse(task))
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// applyTriage applies a bulk triage to one task, reporting whether it
+// changed. approval reports whether completing it needs approval.
+func applyTriage(t *models.Task, req *BulkTriageRequest, approval bool) bool {
+	changed := false
+	if req.Priority != nil && t.Priority != *req.Priority {
+		t.Priority = *req.Priority
+		t.UpdatedAt = time.Now()
+		changed = true
+	}
+	if req.AssigneeID != nil {
+		switch {
+		case *req.AssigneeID == "" && t.AssigneeID != nil:
+			t.Unassign()
+			changed = true
+		case *req.AssigneeID != "" && (t.AssigneeID == nil || *t.AssigneeID != *req.AssigneeID):
+			t.AssignTo(*req.AssigneeID)
+			changed = true
+		}
+	}
+	for _, tag := range req.AddTags {
+		if t.AddTag(tag) {
+			changed = true
+		}
+	}
+	if req.Status != nil && moveStatus(t, *req.Status, approval) {
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,217 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// ReadMarkerStore defines the interface for read marker storage.
+type ReadMarkerStore interface {
+	// ListByUser retrieves all of a user's read markers.
+	ListByUser(ctx context.Context, userID models.UserID) ([]*models.ReadMarker, error)
+	// Put records a read marker, keeping the later of it and any
+	// existing marker for the same target.
+	Put(ctx context.Context, marker *models.ReadMarker) error
+}
+
+// readMarkerKey identifies a marker within InMemoryReadMarkerStore.
+type readMarkerKey struct {
+	userID     models.UserID
+	targetType models.ReadTarget
+	targetID   string
+}
+
+// InMemoryReadMarkerStore is an in-memory implementation of ReadMarkerStore.
+type InMemoryReadMarkerStore struct {
+	mu      sync.RWMutex
+	markers map[readMarkerKey]*models.ReadMarker
+}
+
+// NewInMemoryReadMarkerStore creates a new in-memory read marker store.
+func NewInMemoryReadMarkerStore() *InMemoryReadMarkerStore {
+	return &InMemoryReadMarkerStore{
+		markers: make(map[readMarkerKey]*models.ReadMarker),
+	}
+}
+
+// ListByUser retrieves all of a user's read markers.
+func (s *InMemoryReadMarkerStore) ListByUser(ctx context.Context, userID models.UserID) ([]*models.ReadMarker, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	markers := make([]*models.ReadMarker, 0)
+	for key, marker := range s.markers {
+		if key.userID == userID {
+			markers = append(markers, marker)
+		}
+	}
+	return markers, nil
+}
+
+// Put records a read marker, keeping the later of it and any existing
+// marker for the same target.
+func (s *InMemoryReadMarkerStore) Put(ctx context.Context, marker *models.ReadMarker) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := readMarkerKey{userID: marker.UserID, targetType: marker.TargetType, targetID: marker.TargetID}
+	if existing, ok := s.markers[key]; ok && existing.SeenAt.After(marker.SeenAt) {
+		return nil
+	}
+	s.markers[key] = marker
+	return nil
+}
+
+// UnreadCounts is the response body for a user's unread counts.
+//
+// Comments counts unread comments by task and Activity unread activity
+// by project, omitting zero counts.
+type UnreadCounts struct {
+	Comments      map[models.TaskID]int    `json:"comments"`
+	Activity      map[models.ProjectID]int `json:"activity"`
+	TotalComments int                      `json:"total_comments"`
+	TotalActivity int                      `json:"total_activity"`
+}
+
+// MarkReadRequest is the request body for marking a task or project as
+// read. A zero SeenAt means now.
+type MarkReadRequest struct {
+	SeenAt time.Time `json:"seen_at,omitempty"`
+}
+
+// UnreadHandler tracks what users have read.
+//
+// Unread counts cover the tasks a user watches and the projects those
+// tasks belong to; a user's own comments and actions are never unread.
+type UnreadHandler struct {
+	markers  ReadMarkerStore
+	tasks    TaskStore
+	comments CommentStore
+	audit    AuditStore
+}
+
+// NewUnreadHandler creates a new unread tracking handler.
+func NewUnreadHandler(markers ReadMarkerStore, tasks TaskStore, comments CommentStore, audit AuditStore) *UnreadHandler {
+	return &UnreadHandler{markers: markers, tasks: tasks, comments: comments, audit: audit}
+}
+
+// Counts handles GET /me/unread requests.
+func (h *UnreadHandler) Counts(w http.ResponseWriter, r *http.Request) {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	counts, err := h.unreadCounts(r.Context(), user.ID)
+	if err != nil {
+		writeServerError(w, r, "failed to count unread items", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, counts)
+}
+
+// unreadCounts computes a user's unread counts.
+func (h *UnreadHandler) unreadCounts(ctx context.Context, userID models.UserID) (*UnreadCounts, error) {
+	markers, err := h.markers.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[models.ReadTarget]map[string]time.Time)
+	for _, m := range markers {
+		if seen[m.TargetType] == nil {
+			seen[m.TargetType] = make(map[string]time.Time)
+		}
+		seen[m.TargetType][m.TargetID] = m.SeenAt
+	}
+
+	tasks, err := h.tasks.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	watched := make(map[models.TaskID]bool)
+	projects := make(map[models.ProjectID]bool)
+	for _, t := range tasks {
+		if t.IsWatchedBy(userID) {
+			watched[t.ID] = true
+			projects[t.ProjectID] = true
+		}
+	}
+
+	counts := &UnreadCounts{Comments: make(map[models.TaskID]int), Activity: make(map[models.ProjectID]int)}
+	comments, err := h.comments.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range comments {
+		if watched[c.TaskID] && c.AuthorID != userID && c.CreatedAt.After(seen[models.ReadTargetTask][string(c.TaskID)]) {
+			counts.Comments[c.TaskID]++
+			counts.TotalComments++
+		}
+	}
+
+	activities, err := collectActivities(ctx, h.tasks, h.comments, h.audit)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range activities {
+		if projects[a.ProjectID] && a.ActorID != userID && a.OccurredAt.After(seen[models.ReadTargetProject][string(a.ProjectID)]) {
+			counts.Activity[a.ProjectID]++
+			counts.TotalActivity++
+		}
+	}
+	return counts, nil
+}
+
+// MarkTaskRead handles PUT /tasks/{id}/read requests.
+func (h *UnreadHandler) MarkTaskRead(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	if _, err := h.tasks.Get(r.Context(), taskID); err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			http.Error(w, "task not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get task", err)
+		return
+	}
+	h.markRead(w, r, models.ReadTargetTask, string(taskID))
+}
+
+// MarkProjectRead handles PUT /projects/{id}/read requests.
+func (h *UnreadHandler) MarkProjectRead(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	h.markRead(w, r, models.ReadTargetProject, string(projectID))
+}
+
+// markRead records that the authenticated user has read a target.
+func (h *UnreadHandler) markRead(w http.ResponseWriter, r *http.Request, targetType models.ReadTarget, targetID string) {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req MarkReadRequest
+	if err := decodeJSON(w, r, &req); err != nil && !errors.Is(err, io.EOF) {
+		writeDecodeError(w, err)
+		return
+	}
+	now := time.Now()
+	if req.SeenAt.IsZero() || req.SeenAt.After(now) {
+		req.SeenAt = now
+	}
+
+	marker := &models.ReadMarker{UserID: user.ID, TargetType: targetType, TargetID: targetID, SeenAt: req.SeenAt}
+	if err := h.markers.Put(r.Context(), marker); err != nil {
+		writeServerError(w, r, "failed to save read marker", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, marker)
+}
@@ -0,0 +1,163 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// UserStore defines the interface for user storage.
+type UserStore interface {
+	Store[models.UserID, *models.User]
+}
+
+// ErrUserNotFound is returned when a user is not found.
+var ErrUserNotFound = errors.New("user not found")
+
+// InMemoryUserStore is an in-memory implementation of UserStore.
+type InMemoryUserStore struct {
+	*InMemoryStore[models.UserID, *models.User]
+}
+
+// NewInMemoryUserStore creates a new in-memory user store.
+func NewInMemoryUserStore() *InMemoryUserStore {
+	return &InMemoryUserStore{NewInMemoryStore[models.UserID, *models.User](ErrUserNotFound)}
+}
+
+// UserHandler handles HTTP requests for users.
+type UserHandler struct {
+	store UserStore
+}
+
+// NewUserHandler creates a new user handler.
+func NewUserHandler(store UserStore) *UserHandler {
+	return &UserHandler{store: store}
+}
+
+// Assignable handles GET /users/assignable requests.
+//
+// Only active users are returned, so deactivated users never appear in
+// assignee pickers.
+func (h *UserHandler) Assignable(w http.ResponseWriter, r *http.Request) {
+	users, err := h.store.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list users", err)
+		return
+	}
+
+	active := make([]*models.User, 0, len(users))
+	for _, user := range users {
+		if user.IsActive {
+			active = append(active, user)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, active)
+}
+
+// UpdateProfileRequest is the request body for PATCH /me.
+//
+// Only fields that are present are changed.
+type UpdateProfileRequest struct {
+	DisplayName      *string                         `json:"display_name,omitempty"`
+	AvatarURL        *string                         `json:"avatar_url,omitempty"`
+	Timezone         *string                         `json:"timezone,omitempty"`
+	Locale           *string                         `json:"locale,omitempty"`
+	DateFormat       *models.DateFormat              `json:"date_format,omitempty"`
+	DefaultProjectID *models.ProjectID               `json:"default_project_id,omitempty"`
+	Notifications    *models.NotificationPreferences `json:"notifications,omitempty"`
+}
+
+// Me handles GET /me requests.
+func (h *UserHandler) Me(w http.ResponseWriter, r *http.Request) {
+	caller, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.store.Get(r.Context(), caller.ID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get user", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+// UpdateMe handles PATCH /me requests.
+func (h *UserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	caller, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	user, err := h.store.Get(r.Context(), caller.ID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get user", err)
+		return
+	}
+
+	updated := *user
+	if req.DisplayName != nil {
+		if *req.DisplayName == "" {
+			http.Error(w, "display_name cannot be empty", http.StatusBadRequest)
+			return
+		}
+		updated.DisplayName = *req.DisplayName
+	}
+	if req.AvatarURL != nil {
+		if !models.ValidateAvatarURL(*req.AvatarURL) {
+			http.Error(w, "invalid avatar_url", http.StatusBadRequest)
+			return
+		}
+		updated.AvatarURL = *req.AvatarURL
+	}
+	if req.Timezone != nil {
+		updated.Preferences.Timezone = *req.Timezone
+	}
+	if req.Locale != nil {
+		updated.Preferences.Locale = *req.Locale
+	}
+	if req.DateFormat != nil {
+		updated.Preferences.DateFormat = *req.DateFormat
+	}
+	if req.DefaultProjectID != nil {
+		updated.Preferences.DefaultProjectID = *req.DefaultProjectID
+	}
+	if req.Notifications != nil {
+		updated.Preferences.Notifications = *req.Notifications
+	}
+
+	if err := updated.Preferences.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.store.Update(r.Context(), &updated); err != nil {
+		if writeHookRejection(w, err) {
+			return
+		}
+		writeServerError(w, r, "failed to update user", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &updated)
+}
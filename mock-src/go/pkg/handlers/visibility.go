@@ -0,0 +1,156 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// visibleTo reports whether the user in ctx may see a task. Anonymous
+// requests see no drafts or restricted tasks.
+func visibleTo(ctx context.Context, task *models.Task) bool {
+	user, _ := UserFromContext(ctx)
+	return task.IsVisibleTo(user)
+}
+
+// visibleTasks returns the tasks the user in ctx may see.
+func visibleTasks(ctx context.Context, tasks []*models.Task) []*models.Task {
+	visible := make([]*models.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if visibleTo(ctx, task) {
+			visible = append(visible, task)
+		}
+	}
+	return visible
+}
+
+// VisibilityTaskStore is a TaskStore decorator that hides tasks the
+// user in the context may not see, as if they did not exist.
+//
+// Contexts without a user, such as those of background jobs, see every
+// task.
+type VisibilityTaskStore struct {
+	next TaskStore
+}
+
+// NewVisibilityTaskStore wraps a task store so it enforces task visibility.
+func NewVisibilityTaskStore(next TaskStore) *VisibilityTaskStore {
+	return &VisibilityTaskStore{next: next}
+}
+
+// hidden reports whether the user in ctx, if any, may not see task.
+func (s *VisibilityTaskStore) hidden(ctx context.Context, task *models.Task) bool {
+	user, ok := UserFromContext(ctx)
+	return ok && !task.IsVisibleTo(user)
+}
+
+// Get retrieves a task by ID.
+//
+// Returns ErrTaskNotFound if the user may not see it.
+func (s *VisibilityTaskStore) Get(ctx context.Context, id models.TaskID) (*models.Task, error) {
+	task, err := s.next.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if s.hidden(ctx, task) {
+		return nil, ErrTaskNotFound
+	}
+	return task, nil
+}
+
+// GetAll retrieves the tasks the user may see.
+func (s *VisibilityTaskStore) GetAll(ctx context.Context) ([]*models.Task, error) {
+	tasks, err := s.next.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := UserFromContext(ctx); !ok {
+		return tasks, nil
+	}
+	return visibleTasks(ctx, tasks), nil
+}
+
+// Create stores a new task.
+func (s *VisibilityTaskStore) Create(ctx context.Context, task *models.Task) error {
+	return s.next.Create(ctx, task)
+}
+
+// Update updates an existing task.
+//
+// Returns ErrTaskNotFound if the user may not see it.
+func (s *VisibilityTaskStore) Update(ctx context.Context, task *models.Task) error {
+	if _, err := s.Get(ctx, task.ID); err != nil {
+		return err
+	}
+	return s.next.Update(ctx, task)
+}
+
+// Delete removes a task by ID.
+//
+// Returns ErrTaskNotFound if the user may not see it.
+func (s *VisibilityTaskStore) Delete(ctx context.Context, id models.TaskID) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	return s.next.Delete(ctx, id)
+}
+
+// SetVisibilityRequest is the request body for changing who may see a task.
+type SetVisibilityRequest struct {
+	Visibility     models.TaskVisibility `json:"visibility"`
+	AllowedUserIDs []models.UserID       `json:"allowed_user_ids,omitempty"`
+}
+
+// SetVisibility handles PUT /tasks/{id}/visibility requests.
+//
+// Only the task's creator and users with the manage permission may
+// change its visibility.
+func (h *TaskHandler) SetVisibility(w http.ResponseWriter, r *http.Request, id models.TaskID) {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req SetVisibilityRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	hidden, forbidden, invalid := false, false, false
+	task, err := updateTask(r.Context(), h.store, id, func(task *models.Task) bool {
+		if !task.IsVisibleTo(user) {
+			hidden = true
+			return false
+		}
+		if task.CreatedBy != user.ID && !user.HasPermission("manage") {
+			forbidden = true
+			return false
+		}
+		if task.SetVisibility(req.Visibility, req.AllowedUserIDs) != nil {
+			invalid = true
+			return false
+		}
+		return true
+	})
+	switch {
+	case errors.Is(err, ErrTaskNotFound), hidden:
+		http.Error(w, "task not found", http.StatusNotFound)
+	case errors.Is(err, ErrVersionConflict):
+		http.Error(w, "task was modified concurrently", http.StatusConflict)
+	case err != nil:
+		if !writeHookRejection(w, err) {
+			http.Error(w, "failed to update task", http.StatusInternalServerError)
+		}
+	case forbidden:
+		http.Error(w, "forbidden", http.StatusForbidden)
+	case invalid:
+		http.Error(w, models.ErrInvalidVisibility.Error(), http.StatusBadRequest)
+	default:
+		writeJSON(w, http.StatusOK, toResponse(task))
+	}
+}
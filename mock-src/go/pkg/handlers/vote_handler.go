@@ -0,0 +1,100 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// VoteHandler handles HTTP requests for task votes.
+type VoteHandler struct {
+	tasks TaskStore
+}
+
+// NewVoteHandler creates a new vote handler.
+func NewVoteHandler(tasks TaskStore) *VoteHandler {
+	return &VoteHandler{tasks: tasks}
+}
+
+// VotesResponse is the response body for a task's votes.
+//
+// Voted reports whether the current user has voted for the task.
+type VotesResponse struct {
+	TaskID models.TaskID `json:"task_id"`
+	Votes  int           `json:"votes"`
+	Voted  bool          `json:"voted"`
+}
+
+// Vote handles POST /tasks/{id}/vote requests for the current user.
+//
+// Voting is idempotent. Closed tasks cannot be voted for.
+func (h *VoteHandler) Vote(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	closed := false
+	task, err := updateTask(r.Context(), h.tasks, taskID, func(task *models.Task) bool {
+		if !task.IsOpen() {
+			closed = true
+			return false
+		}
+		return task.Vote(user.ID)
+	})
+	if err == nil && closed {
+		http.Error(w, "cannot vote for a closed task", http.StatusConflict)
+		return
+	}
+	h.respond(w, user, task, err)
+}
+
+// Unvote handles DELETE /tasks/{id}/vote requests for the current user.
+func (h *VoteHandler) Unvote(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	task, err := updateTask(r.Context(), h.tasks, taskID, func(task *models.Task) bool {
+		return task.Unvote(user.ID)
+	})
+	h.respond(w, user, task, err)
+}
+
+// Get handles GET /tasks/{id}/votes requests.
+func (h *VoteHandler) Get(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	task, err := h.tasks.Get(r.Context(), taskID)
+	h.respond(w, user, task, err)
+}
+
+// respond writes the task's vote count or maps err to a status code.
+func (h *VoteHandler) respond(w http.ResponseWriter, user *models.User, task *models.Task, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrTaskNotFound):
+			http.Error(w, "task not found", http.StatusNotFound)
+		case errors.Is(err, ErrVersionConflict):
+			http.Error(w, "task was modified concurrently", http.StatusConflict)
+		default:
+			http.Error(w, "failed to update votes", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &VotesResponse{
+		TaskID: task.ID,
+		Votes:  len(task.Voters),
+		Voted:  task.HasVoted(user.ID),
+	})
+}
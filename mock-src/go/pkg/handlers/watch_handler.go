@@ -0,0 +1,116 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// maxUpdateAttempts bounds retries when a task update races another write.
+const maxUpdateAttempts = 3
+
+// addWatchers subscribes users to a task, retrying on version conflicts.
+func addWatchers(ctx context.Context, tasks TaskStore, taskID models.TaskID, userIDs ...models.UserID) (*models.Task, error) {
+	return updateTask(ctx, tasks, taskID, func(task *models.Task) bool {
+		changed := false
+		for _, id := range userIDs {
+			if task.Watch(id) {
+				changed = true
+			}
+		}
+		return changed
+	})
+}
+
+// updateTask applies change to a fresh copy of the task and stores
+// it if change reports a modification, retrying on version conflicts.
+func updateTask(ctx context.Context, tasks TaskStore, taskID models.TaskID, change func(*models.Task) bool) (*models.Task, error) {
+	var err error
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		var task *models.Task
+		task, err = tasks.Get(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+		task = task.Clone()
+		if !change(task) {
+			return task, nil
+		}
+		if err = tasks.Update(ctx, task); !errors.Is(err, ErrVersionConflict) {
+			return task, err
+		}
+	}
+	return nil, err
+}
+
+// WatchHandler handles HTTP requests for task watchers.
+type WatchHandler struct {
+	tasks TaskStore
+}
+
+// NewWatchHandler creates a new watch handler.
+func NewWatchHandler(tasks TaskStore) *WatchHandler {
+	return &WatchHandler{tasks: tasks}
+}
+
+// WatchersResponse is the response body listing a task's watchers.
+type WatchersResponse struct {
+	TaskID   models.TaskID   `json:"task_id"`
+	Watchers []models.UserID `json:"watchers"`
+}
+
+// Watch handles POST /tasks/{id}/watch requests for the current user.
+func (h *WatchHandler) Watch(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	task, err := addWatchers(r.Context(), h.tasks, taskID, user.ID)
+	h.respond(w, task, err)
+}
+
+// Unwatch handles DELETE /tasks/{id}/watch requests for the current user.
+func (h *WatchHandler) Unwatch(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	task, err := updateTask(r.Context(), h.tasks, taskID, func(task *models.Task) bool {
+		return task.Unwatch(user.ID)
+	})
+	h.respond(w, task, err)
+}
+
+// List handles GET /tasks/{id}/watchers requests.
+func (h *WatchHandler) List(w http.ResponseWriter, r *http.Request, taskID models.TaskID) {
+	task, err := h.tasks.Get(r.Context(), taskID)
+	h.respond(w, task, err)
+}
+
+// respond writes the task's watchers or maps err to a status code.
+func (h *WatchHandler) respond(w http.ResponseWriter, task *models.Task, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrTaskNotFound):
+			http.Error(w, "task not found", http.StatusNotFound)
+		case errors.Is(err, ErrVersionConflict):
+			http.Error(w, "task was modified concurrently", http.StatusConflict)
+		default:
+			http.Error(w, "failed to update watchers", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	watchers := task.Watchers
+	if watchers == nil {
+		watchers = []models.UserID{}
+	}
+	writeJSON(w, http.StatusOK, &WatchersResponse{TaskID: task.ID, Watchers: watchers})
+}
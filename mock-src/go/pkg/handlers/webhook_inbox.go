@@ -0,0 +1,444 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// WebhookSourceStore defines the interface for inbound webhook source storage.
+type WebhookSourceStore interface {
+	Store[string, *models.WebhookSource]
+	// ListByProject retrieves the sources of a project.
+	ListByProject(ctx context.Context, projectID models.ProjectID) ([]*models.WebhookSource, error)
+}
+
+// ErrWebhookSourceNotFound is returned when a webhook source is not found.
+var ErrWebhookSourceNotFound = errors.New("webhook source not found")
+
+// InMemoryWebhookSourceStore is an in-memory implementation of WebhookSourceStore.
+type InMemoryWebhookSourceStore struct {
+	*InMemoryStore[string, *models.WebhookSource]
+}
+
+// NewInMemoryWebhookSourceStore creates a new in-memory webhook source store.
+func NewInMemoryWebhookSourceStore() *InMemoryWebhookSourceStore {
+	return &InMemoryWebhookSourceStore{NewInMemoryStore[string, *models.WebhookSource](ErrWebhookSourceNotFound)}
+}
+
+// ListByProject retrieves the sources of a project, by name.
+func (s *InMemoryWebhookSourceStore) ListByProject(ctx context.Context, projectID models.ProjectID) ([]*models.WebhookSource, error) {
+	return s.List(ctx, ListOptions[*models.WebhookSource]{
+		Filter: func(src *models.WebhookSource) bool { return src.ProjectID == projectID },
+		Less:   func(a, b *models.WebhookSource) bool { return a.Name < b.Name },
+	})
+}
+
+// WebhookKeyStore remembers which task each deduplication key of a
+// webhook source created.
+type WebhookKeyStore interface {
+	// Lookup returns the task a source's key belongs to.
+	Lookup(ctx context.Context, sourceID, key string) (models.TaskID, bool, error)
+	// Link records that a source's key belongs to a task.
+	Link(ctx context.Context, sourceID, key string, taskID models.TaskID) error
+	// Claim links a source's key to taskID unless it already belongs to
+	// a task, in one step. It returns the task the key belongs to and
+	// whether it was claimed for taskID.
+	Claim(ctx context.Context, sourceID, key string, taskID models.TaskID) (models.TaskID, bool, error)
+}
+
+// InMemoryWebhookKeyStore is an in-memory implementation of WebhookKeyStore.
+type InMemoryWebhookKeyStore struct {
+	mu   sync.RWMutex
+	keys map[string]models.TaskID
+}
+
+// NewInMemoryWebhookKeyStore creates a new in-memory webhook key store.
+func NewInMemoryWebhookKeyStore() *InMemoryWebhookKeyStore {
+	return &InMemoryWebhookKeyStore{keys: make(map[string]models.TaskID)}
+}
+
+// Lookup returns the task a source's key belongs to.
+func (s *InMemoryWebhookKeyStore) Lookup(ctx context.Context, sourceID, key string) (models.TaskID, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	taskID, ok := s.keys[sourceID+"\x00"+key]
+	return taskID, ok, nil
+}
+
+// Link records that a source's key belongs to a task.
+func (s *InMemoryWebhookKeyStore) Link(ctx context.Context, sourceID, key string, taskID models.TaskID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[sourceID+"\x00"+key] = taskID
+	return nil
+}
+
+// Claim links a source's key to taskID unless it already belongs to a
+// task.
+func (s *InMemoryWebhookKeyStore) Claim(ctx context.Context, sourceID, key string, taskID models.TaskID) (models.TaskID, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.keys[sourceID+"\x00"+key]; ok {
+		return existing, false, nil
+	}
+	s.keys[sourceID+"\x00"+key] = taskID
+	return taskID, true, nil
+}
+
+// WebhookInboxHandler receives events from external systems on inbound
+// webhooks and turns them into tasks, as configured by each source.
+type WebhookInboxHandler struct {
+	sources  WebhookSourceStore
+	keys     WebhookKeyStore
+	tasks    TaskStore
+	projects ProjectStore
+}
+
+// NewWebhookInboxHandler creates a new webhook inbox handler. projects
+// may be nil, in which case no project requires approval.
+func NewWebhookInboxHandler(sources WebhookSourceStore, keys WebhookKeyStore, tasks TaskStore, projects ProjectStore) *WebhookInboxHandler {
+	return &WebhookInboxHandler{sources: sources, keys: keys, tasks: tasks, projects: projects}
+}
+
+// Receive handles POST /webhooks/inbound/{id} requests.
+//
+// A payload creates a task, answered with 201, unless its key matches
+// an earlier payload's task, which is updated and answered with 200.
+// Unknown and disabled sources get 404, bad signatures 401 and payloads
+// the source cannot map 422. A mapped status moves the task as the
+// project's workflow allows; completing a task of a project that
+// requires approval submits it for review.
+func (h *WebhookInboxHandler) Receive(w http.ResponseWriter, r *http.Request, id string) {
+	source, err := h.sources.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrWebhookSourceNotFound) {
+			http.Error(w, "webhook source not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get webhook source", err)
+		return
+	}
+	if !source.Enabled {
+		http.Error(w, "webhook source not found", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes))
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if !verifyWebhookSignature(source, r.Header.Get(source.SignatureHeader), body) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	mapping, err := source.Map(payload)
+	if err == nil {
+		mapping.Title, err = models.SanitizeTitle(mapping.Title)
+		if err == nil {
+			mapping.Description, err = models.SanitizeDescription(mapping.Description)
+		}
+		if err != nil {
+			writeContentError(w, err)
+			return
+		}
+		if mapping.Title == "" {
+			err = models.ErrWebhookPayload
+		}
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	task := models.NewTaskWithOptions(mapping.Title, source.ProjectID,
+		models.WithDescription(mapping.Description),
+		models.WithTags(source.Tags),
+	)
+	if mapping.Key != "" {
+		taskID, claimed, err := h.keys.Claim(r.Context(), source.ID, mapping.Key, task.ID)
+		if err != nil {
+			writeServerError(w, r, "failed to record webhook key", err)
+			return
+		}
+		if !claimed {
+			updated, err := h.update(r.Context(), taskID, mapping)
+			if err == nil {
+				writeJSON(w, http.StatusOK, toResponse(updated))
+				return
+			}
+			if !errors.Is(err, ErrTaskNotFound) {
+				h.writeError(w, err, "failed to update task")
+				return
+			}
+			// The task was deleted; the payload starts a new one.
+			if err := h.keys.Link(r.Context(), source.ID, mapping.Key, task.ID); err != nil {
+				writeServerError(w, r, "failed to record webhook key", err)
+				return
+			}
+		}
+	}
+
+	if mapping.Status != "" && mapping.Status != models.TaskStatusPending {
+		approval, err := h.approvalRequired(r.Context(), mapping, source.ProjectID)
+		if err != nil {
+			writeServerError(w, r, "failed to get project", err)
+			return
+		}
+		moveStatus(task, mapping.Status, approval)
+	}
+	if err := h.tasks.Create(r.Context(), task); err != nil {
+		h.writeError(w, err, "failed to create task")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toResponse(task))
+}
+
+// update applies a later payload to the task of its key.
+func (h *WebhookInboxHandler) update(ctx context.Context, taskID models.TaskID, mapping *models.WebhookMapping) (*models.Task, error) {
+	current, err := h.tasks.Get(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	approval, err := h.approvalRequired(ctx, mapping, current.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	return updateTask(ctx, h.tasks, taskID, func(t *models.Task) bool {
+		return applyWebhookMapping(t, mapping, approval)
+	})
+}
+
+// approvalRequired reports whether the status a payload maps to needs
+// approval in a project.
+func (h *WebhookInboxHandler) approvalRequired(ctx context.Context, mapping *models.WebhookMapping, projectID models.ProjectID) (bool, error) {
+	if mapping.Status != models.TaskStatusCompleted {
+		return false, nil
+	}
+	return projectRequiresApproval(ctx, h.projects, projectID)
+}
+
+// writeError writes the error of a task write.
+func (h *WebhookInboxHandler) writeError(w http.ResponseWriter, err error, msg string) {
+	if writeHookRejection(w, err) {
+		return
+	}
+	if errors.Is(err, models.ErrUnknownStatus) || errors.Is(err, models.ErrTransitionNotAllowed) || errors.Is(err, models.ErrWIPLimitReached) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	http.Error(w, msg, http.StatusInternalServerError)
+}
+
+// applyWebhookMapping updates a task from a later payload, reporting
+// whether anything changed. Empty fields of the mapping are ignored.
+// approval reports whether completing the task needs approval.
+func applyWebhookMapping(t *models.Task, m *models.WebhookMapping, approval bool) bool {
+	changed := false
+	if m.Title != t.Title {
+		t.Title = m.Title
+		changed = true
+	}
+	if m.Description != "" && m.Description != t.Description {
+		t.Description = m.Description
+		changed = true
+	}
+	if m.Status != "" && moveStatus(t, m.Status, approval) {
+		changed = true
+	}
+	return changed
+}
+
+// verifyWebhookSignature checks a hex HMAC-SHA256 of body under the
+// source's secret, after the source's signature prefix.
+func verifyWebhookSignature(source *models.WebhookSource, header string, body []byte) bool {
+	sig, ok := strings.CutPrefix(header, source.SignaturePrefix)
+	return ok && validHMACSHA256(source.Secret, sig, body)
+}
+
+// validHMACSHA256 reports whether sig is the hex HMAC-SHA256 of body
+// under secret.
+func validHMACSHA256(secret, sig string, body []byte) bool {
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// WebhookSourceHandler handles HTTP requests for managing inbound
+// webhook sources.
+type WebhookSourceHandler struct {
+	sources WebhookSourceStore
+}
+
+// NewWebhookSourceHandler creates a new webhook source handler.
+func NewWebhookSourceHandler(sources WebhookSourceStore) *WebhookSourceHandler {
+	return &WebhookSourceHandler{sources: sources}
+}
+
+// WebhookSourceRequest is the request body for creating or updating a
+// webhook source. Omitted signature settings keep their defaults.
+type WebhookSourceRequest struct {
+	Name                string                       `json:"name"`
+	SignatureHeader     string                       `json:"signature_header,omitempty"`
+	SignaturePrefix     *string                      `json:"signature_prefix,omitempty"`
+	TitleTemplate       string                       `json:"title_template"`
+	DescriptionTemplate string                       `json:"description_template,omitempty"`
+	KeyTemplate         string                       `json:"key_template,omitempty"`
+	StatusField         string                       `json:"status_field,omitempty"`
+	StatusMap           map[string]models.TaskStatus `json:"status_map,omitempty"`
+	Tags                []string                     `json:"tags,omitempty"`
+	Enabled             *bool                        `json:"enabled,omitempty"`
+}
+
+// apply copies the request's fields onto a source.
+func (req *WebhookSourceRequest) apply(source *models.WebhookSource) {
+	source.Name = req.Name
+	if req.SignatureHeader != "" {
+		source.SignatureHeader = req.SignatureHeader
+	}
+	if req.SignaturePrefix != nil {
+		source.SignaturePrefix = *req.SignaturePrefix
+	}
+	source.TitleTemplate = req.TitleTemplate
+	source.DescriptionTemplate = req.DescriptionTemplate
+	source.KeyTemplate = req.KeyTemplate
+	source.StatusField = req.StatusField
+	source.StatusMap = req.StatusMap
+	source.Tags = req.Tags
+	if req.Enabled != nil {
+		source.Enabled = *req.Enabled
+	}
+}
+
+// WebhookSourceCreated is the response body for a created source. It
+// is the only response that reveals the signing secret.
+type WebhookSourceCreated struct {
+	*models.WebhookSource
+	Secret string `json:"secret"`
+}
+
+// List handles GET /projects/{id}/webhook-sources requests.
+func (h *WebhookSourceHandler) List(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	sources, err := h.sources.ListByProject(r.Context(), projectID)
+	if err != nil {
+		writeServerError(w, r, "failed to list webhook sources", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sources)
+}
+
+// Create handles POST /projects/{id}/webhook-sources requests.
+func (h *WebhookSourceHandler) Create(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req WebhookSourceRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	source, err := models.NewWebhookSource(projectID, req.Name, req.TitleTemplate)
+	if err != nil {
+		writeServerError(w, r, "failed to create webhook source", err)
+		return
+	}
+	req.apply(source)
+	if err := source.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.sources.Create(r.Context(), source); err != nil {
+		writeServerError(w, r, "failed to create webhook source", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, &WebhookSourceCreated{WebhookSource: source, Secret: source.Secret})
+}
+
+// Update handles PUT /webhook-sources/{id} requests.
+func (h *WebhookSourceHandler) Update(w http.ResponseWriter, r *http.Request, id string) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var req WebhookSourceRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	existing, err := h.sources.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrWebhookSourceNotFound) {
+			http.Error(w, "webhook source not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get webhook source", err)
+		return
+	}
+
+	source := *existing
+	req.apply(&source)
+	if err := source.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.sources.Update(r.Context(), &source); err != nil {
+		writeServerError(w, r, "failed to update webhook source", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &source)
+}
+
+// Delete handles DELETE /webhook-sources/{id} requests.
+func (h *WebhookSourceHandler) Delete(w http.ResponseWriter, r *http.Request, id string) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	if err := h.sources.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, ErrWebhookSourceNotFound) {
+			http.Error(w, "webhook source not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to delete webhook source", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,157 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// warningsContextKey marks contexts that collect warnings for the
+// response, such as WIP limits exceeded under a warn-only workflow.
+type warningsContextKey struct{}
+
+// requestWarnings collects the warnings raised while serving a request.
+type requestWarnings struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+// withWarnings returns a copy of ctx that collects warnings, and the
+// collector to write them from.
+func withWarnings(ctx context.Context) (context.Context, *requestWarnings) {
+	warnings := &requestWarnings{}
+	return context.WithValue(ctx, warningsContextKey{}, warnings), warnings
+}
+
+// addWarning records a warning on ctx's collector, if it has one.
+func addWarning(ctx context.Context, message string) {
+	if warnings, ok := ctx.Value(warningsContextKey{}).(*requestWarnings); ok {
+		warnings.mu.Lock()
+		warnings.messages = append(warnings.messages, message)
+		warnings.mu.Unlock()
+	}
+}
+
+// write sets a Warning header for each collected warning. It must be
+// called before the response status is written.
+func (c *requestWarnings) write(w http.ResponseWriter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	writeWarnings(w, c.messages)
+}
+
+// writeWarnings sets a miscellaneous Warning header for each message.
+func writeWarnings(w http.ResponseWriter, messages []string) {
+	for _, message := range messages {
+		w.Header().Add("Warning", "199 - "+strconv.Quote(message))
+	}
+}
+
+// checkWIP checks a task entering its status against the status's WIP
+// limit, counting the project's other published tasks already there.
+//
+// The count and the write are not atomic, so concurrent moves may
+// overshoot a limit by a task or two.
+func (s *WorkflowTaskStore) checkWIP(ctx context.Context, workflow *models.Workflow, task *models.Task) error {
+	limit := workflow.WIPLimit(task.Status)
+	if limit == 0 {
+		return nil
+	}
+	tasks, err := s.next.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, other := range tasks {
+		if other.ProjectID == task.ProjectID && other.Status == task.Status && !other.Draft && other.ID != task.ID {
+			count++
+		}
+	}
+	if count < limit {
+		return nil
+	}
+	wipErr := &models.WIPLimitError{Status: task.Status, Limit: limit, Count: count}
+	if workflow.WIPEnforcement == models.WIPEnforcementWarn {
+		addWarning(ctx, wipErr.Error())
+		return nil
+	}
+	return wipErr
+}
+
+// BoardColumn is a status column of a project board.
+//
+// Utilization is Count over WIPLimit and is omitted for statuses
+// without a limit. Count includes tasks the caller cannot see.
+type BoardColumn struct {
+	Status      models.TaskStatus `json:"status"`
+	Label       string            `json:"label"`
+	WIPLimit    int               `json:"wip_limit,omitempty"`
+	Count       int               `json:"count"`
+	Utilization *float64          `json:"utilization,omitempty"`
+	OverLimit   bool              `json:"over_limit"`
+	Tasks       []*TaskResponse   `json:"tasks"`
+}
+
+// BoardResponse is the response body for a project board.
+type BoardResponse struct {
+	ProjectID      models.ProjectID      `json:"project_id"`
+	WIPEnforcement models.WIPEnforcement `json:"wip_enforcement"`
+	Columns        []*BoardColumn        `json:"columns"`
+}
+
+// Board handles GET /projects/{id}/board requests, listing the project's
+// tasks by status in workflow order with each column's WIP utilization.
+// Drafts are omitted and tasks are in rank order.
+func (h *WorkflowHandler) Board(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	workflow, err := projectWorkflow(r.Context(), h.workflows, projectID)
+	if err != nil {
+		writeServerError(w, r, "failed to get workflow", err)
+		return
+	}
+	tasks, err := h.tasks.GetAll(r.Context())
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+	tasks = publishedTasks(tasks)
+	models.SortByRank(tasks)
+
+	enforcement := workflow.WIPEnforcement
+	if enforcement == "" {
+		enforcement = models.WIPEnforcementReject
+	}
+	resp := &BoardResponse{ProjectID: projectID, WIPEnforcement: enforcement, Columns: make([]*BoardColumn, 0, len(workflow.Statuses))}
+	columns := make(map[models.TaskStatus]*BoardColumn, len(workflow.Statuses))
+	for _, s := range workflow.Statuses {
+		label := s.Label
+		if label == "" {
+			label = statusLabel(s.Name)
+		}
+		column := &BoardColumn{Status: s.Name, Label: label, WIPLimit: s.WIPLimit, Tasks: make([]*TaskResponse, 0)}
+		columns[s.Name] = column
+		resp.Columns = append(resp.Columns, column)
+	}
+	for _, task := range tasks {
+		column, ok := columns[task.Status]
+		if task.ProjectID != projectID || !ok {
+			continue
+		}
+		column.Count++
+		if visibleTo(r.Context(), task) {
+			column.Tasks = append(column.Tasks, toResponse(task))
+		}
+	}
+	for _, column := range resp.Columns {
+		if column.WIPLimit > 0 {
+			utilization := float64(column.Count) / float64(column.WIPLimit)
+			column.Utilization = &utilization
+			column.OverLimit = column.Count > column.WIPLimit
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,308 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// WorkflowStore defines the interface for per-project workflow storage.
+type WorkflowStore interface {
+	// Get retrieves the custom workflow of a project.
+	Get(ctx context.Context, projectID models.ProjectID) (*models.Workflow, error)
+	// Put sets a project's workflow, replacing any existing one.
+	Put(ctx context.Context, workflow *models.Workflow) error
+	// Delete removes a project's custom workflow.
+	Delete(ctx context.Context, projectID models.ProjectID) error
+}
+
+// ErrWorkflowNotFound is returned when a project has no custom workflow.
+var ErrWorkflowNotFound = errors.New("workflow not found")
+
+// InMemoryWorkflowStore is an in-memory implementation of WorkflowStore.
+type InMemoryWorkflowStore struct {
+	mu        sync.RWMutex
+	workflows map[models.ProjectID]*models.Workflow
+}
+
+// NewInMemoryWorkflowStore creates a new in-memory workflow store.
+func NewInMemoryWorkflowStore() *InMemoryWorkflowStore {
+	return &InMemoryWorkflowStore{
+		workflows: make(map[models.ProjectID]*models.Workflow),
+	}
+}
+
+// Get retrieves the custom workflow of a project.
+func (s *InMemoryWorkflowStore) Get(ctx context.Context, projectID models.ProjectID) (*models.Workflow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	workflow, ok := s.workflows[projectID]
+	if !ok {
+		return nil, ErrWorkflowNotFound
+	}
+	return workflow, nil
+}
+
+// Put sets a project's workflow, replacing any existing one.
+func (s *InMemoryWorkflowStore) Put(ctx context.Context, workflow *models.Workflow) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.workflows[workflow.ProjectID] = workflow
+	return nil
+}
+
+// Delete removes a project's custom workflow.
+func (s *InMemoryWorkflowStore) Delete(ctx context.Context, projectID models.ProjectID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.workflows[projectID]; !ok {
+		return ErrWorkflowNotFound
+	}
+	delete(s.workflows, projectID)
+	return nil
+}
+
+// projectWorkflow returns a project's custom workflow, or the default
+// workflow if it has none.
+func projectWorkflow(ctx context.Context, workflows WorkflowStore, projectID models.ProjectID) (*models.Workflow, error) {
+	workflow, err := workflows.Get(ctx, projectID)
+	if errors.Is(err, ErrWorkflowNotFound) {
+		return models.DefaultWorkflow(projectID), nil
+	}
+	return workflow, err
+}
+
+// WorkflowTaskStore is a TaskStore decorator that enforces each
+// project's workflow on the tasks written through it.
+//
+// New tasks must have a status the workflow defines; tasks created with
+// the default pending status start in the workflow's initial status
+// instead. Updates that change a task's status must follow an allowed
+// transition. A task entering a status at its WIP limit is refused with
+// a models.WIPLimitError, or let through with a request warning if the
+// workflow only warns.
+type WorkflowTaskStore struct {
+	next      TaskStore
+	workflows WorkflowStore
+}
+
+// NewWorkflowTaskStore wraps a task store with workflow enforcement.
+func NewWorkflowTaskStore(next TaskStore, workflows WorkflowStore) *WorkflowTaskStore {
+	return &WorkflowTaskStore{next: next, workflows: workflows}
+}
+
+// Get retrieves a task by ID.
+func (s *WorkflowTaskStore) Get(ctx context.Context, id models.TaskID) (*models.Task, error) {
+	return s.next.Get(ctx, id)
+}
+
+// GetAll retrieves all tasks.
+func (s *WorkflowTaskStore) GetAll(ctx context.Context) ([]*models.Task, error) {
+	return s.next.GetAll(ctx)
+}
+
+// Create stores a new task if the workflow defines its status.
+func (s *WorkflowTaskStore) Create(ctx context.Context, task *models.Task) error {
+	workflow, err := projectWorkflow(ctx, s.workflows, task.ProjectID)
+	if err != nil {
+		return err
+	}
+	if !workflow.HasStatus(task.Status) {
+		if task.Status != models.TaskStatusPending {
+			return models.ErrUnknownStatus
+		}
+		task.Status = workflow.Initial
+	}
+	if !task.Draft {
+		if err := s.checkWIP(ctx, workflow, task); err != nil {
+			return err
+		}
+	}
+	return s.next.Create(ctx, task)
+}
+
+// Update updates an existing task if its status change, if any, is an
+// allowed transition. Publishing a draft counts as entering its status
+// for WIP limits.
+//
+// Review stands in for completed: submitting a task for review is
+// checked as a move to completed, and leaving review by approval or
+// rejection is always allowed.
+func (s *WorkflowTaskStore) Update(ctx context.Context, task *models.Task) error {
+	current, err := s.next.Get(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+	moved := current.Status != task.Status
+	if !moved && !(current.Draft && !task.Draft) {
+		return s.next.Update(ctx, task)
+	}
+	workflow, err := projectWorkflow(ctx, s.workflows, task.ProjectID)
+	if err != nil {
+		return err
+	}
+	if moved && current.Status != models.TaskStatusAwaitingReview {
+		to := task.Status
+		if to == models.TaskStatusAwaitingReview {
+			to = models.TaskStatusCompleted
+		}
+		if err := workflow.CheckTransition(current.Status, to); err != nil {
+			return err
+		}
+	}
+	if !task.Draft {
+		if err := s.checkWIP(ctx, workflow, task); err != nil {
+			return err
+		}
+	}
+	return s.next.Update(ctx, task)
+}
+
+// Delete removes a task by ID.
+func (s *WorkflowTaskStore) Delete(ctx context.Context, id models.TaskID) error {
+	return s.next.Delete(ctx, id)
+}
+
+// WorkflowResponse is the response body for a project's workflow.
+//
+// Custom is false when the project uses the default workflow.
+type WorkflowResponse struct {
+	*models.Workflow
+	Custom bool `json:"custom"`
+}
+
+// StatusInUseError is the response body when a workflow would drop
+// statuses that tasks are still in.
+type StatusInUseError struct {
+	Error    string              `json:"error"`
+	Statuses []models.TaskStatus `json:"statuses"`
+}
+
+// WorkflowHandler handles HTTP requests for project workflows.
+type WorkflowHandler struct {
+	workflows WorkflowStore
+	tasks     TaskStore
+}
+
+// NewWorkflowHandler creates a new workflow handler.
+func NewWorkflowHandler(workflows WorkflowStore, tasks TaskStore) *WorkflowHandler {
+	return &WorkflowHandler{workflows: workflows, tasks: tasks}
+}
+
+// Get handles GET /projects/{id}/workflow requests.
+func (h *WorkflowHandler) Get(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	workflow, err := h.workflows.Get(r.Context(), projectID)
+	if err != nil {
+		if errors.Is(err, ErrWorkflowNotFound) {
+			writeJSON(w, http.StatusOK, &WorkflowResponse{Workflow: models.DefaultWorkflow(projectID)})
+			return
+		}
+		writeServerError(w, r, "failed to get workflow", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &WorkflowResponse{Workflow: workflow, Custom: true})
+}
+
+// Put handles PUT /projects/{id}/workflow requests.
+//
+// The workflow must still define every status the project's tasks are
+// in; otherwise the request fails with 409 listing those statuses.
+func (h *WorkflowHandler) Put(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var workflow models.Workflow
+	if err := decodeJSON(w, r, &workflow); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	workflow.ProjectID = projectID
+	workflow.UpdatedAt = time.Now()
+	if err := workflow.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	missing, err := h.statusesInUse(r.Context(), &workflow)
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+	if len(missing) > 0 {
+		writeJSON(w, http.StatusConflict, &StatusInUseError{
+			Error:    "tasks are in statuses the workflow does not define",
+			Statuses: missing,
+		})
+		return
+	}
+
+	if err := h.workflows.Put(r.Context(), &workflow); err != nil {
+		writeServerError(w, r, "failed to save workflow", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &WorkflowResponse{Workflow: &workflow, Custom: true})
+}
+
+// Delete handles DELETE /projects/{id}/workflow requests, returning the
+// project to the default workflow.
+func (h *WorkflowHandler) Delete(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	missing, err := h.statusesInUse(r.Context(), models.DefaultWorkflow(projectID))
+	if err != nil {
+		writeServerError(w, r, "failed to list tasks", err)
+		return
+	}
+	if len(missing) > 0 {
+		writeJSON(w, http.StatusConflict, &StatusInUseError{
+			Error:    "tasks are in custom statuses",
+			Statuses: missing,
+		})
+		return
+	}
+
+	if err := h.workflows.Delete(r.Context(), projectID); err != nil {
+		if errors.Is(err, ErrWorkflowNotFound) {
+			http.Error(w, "project has no custom workflow", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to delete workflow", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// statusesInUse returns the statuses of the project's tasks that the
+// workflow does not define.
+func (h *WorkflowHandler) statusesInUse(ctx context.Context, workflow *models.Workflow) ([]models.TaskStatus, error) {
+	tasks, err := h.tasks.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[models.TaskStatus]bool)
+	missing := make([]models.TaskStatus, 0)
+	for _, task := range tasks {
+		if task.ProjectID != workflow.ProjectID || seen[task.Status] {
+			continue
+		}
+		seen[task.Status] = true
+		if !workflow.HasStatus(task.Status) {
+			missing = append(missing, task.Status)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,173 @@
+// Package handlers provides HTTP handlers for the TaskTracker API.
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/example/tasktracker/pkg/models"
+)
+
+// defaultDueSoonDays is the window for due-soon counts when none is given.
+const defaultDueSoonDays = 7
+
+// AssigneeWorkload summarizes the open tasks assigned to one user in a
+// project. An empty UserID collects unassigned tasks.
+type AssigneeWorkload struct {
+	UserID     models.UserID        `json:"user_id"`
+	OpenTasks  int                  `json:"open_tasks"`
+	Estimate   float64              `json:"estimate"`
+	DueSoon    int                  `json:"due_soon"`
+	Overdue    int                  `json:"overdue"`
+	Capacity   *models.UserCapacity `json:"capacity,omitempty"`
+	Overloaded bool                 `json:"overloaded"`
+}
+
+// ProjectWorkload is the response body for a project's workload view.
+type ProjectWorkload struct {
+	ProjectID   models.ProjectID    `json:"project_id"`
+	DueSoonDays int                 `json:"due_soon_days"`
+	Assignees   []*AssigneeWorkload `json:"assignees"`
+}
+
+// computeWorkload sums the open tasks of a project per assignee.
+//
+// Capacities are taken from users; assignees missing from users have no
+// capacity and are never overloaded.
+func computeWorkload(projectID models.ProjectID, tasks []*models.Task, users []*models.User, dueSoon time.Duration, at time.Time) []*AssigneeWorkload {
+	capacities := make(map[models.UserID]*models.UserCapacity, len(users))
+	for _, u := range users {
+		capacities[u.ID] = u.Capacity
+	}
+
+	byUser := make(map[models.UserID]*AssigneeWorkload)
+	for _, task := range tasks {
+		if task.ProjectID != projectID || !task.IsOpen() {
+			continue
+		}
+		var userID models.UserID
+		if task.AssigneeID != nil {
+			userID = *task.AssigneeID
+		}
+		load, ok := byUser[userID]
+		if !ok {
+			load = &AssigneeWorkload{UserID: userID, Capacity: capacities[userID]}
+			byUser[userID] = load
+		}
+		load.OpenTasks++
+		load.Estimate += task.Estimate
+		if task.IsOverdueAt(at) {
+			load.Overdue++
+		} else if task.DueDate != nil && task.DueDate.Sub(at) <= dueSoon {
+			load.DueSoon++
+		}
+	}
+
+	loads := make([]*AssigneeWorkload, 0, len(byUser))
+	for _, load := range byUser {
+		if load.Capacity != nil {
+			load.Overloaded = load.Capacity.Exceeded(load.OpenTasks, load.Estimate)
+		}
+		loads = append(loads, load)
+	}
+	sort.Slice(loads, func(i, j int) bool {
+		if loads[i].Estimate != loads[j].Estimate {
+			return loads[i].Estimate > loads[j].Estimate
+		}
+		return loads[i].UserID < loads[j].UserID
+	})
+	return loads
+}
+
+// WorkloadHandler handles HTTP requests for workload and capacity.
+type WorkloadHandler struct {
+	tasks TaskStore
+	users UserStore
+}
+
+// NewWorkloadHandler creates a new workload handler.
+func NewWorkloadHandler(tasks TaskStore, users UserStore) *WorkloadHandler {
+	return &WorkloadHandler{tasks: tasks, users: users}
+}
+
+// Workload handles GET /projects/{id}/workload requests.
+//
+// An optional due_soon_days parameter sets the due-soon window. Drafts
+// are not counted.
+func (h *WorkloadHandler) Workload(w http.ResponseWriter, r *http.Request, projectID models.ProjectID) {
+	days := defaultDueSoonDays
+	if raw := r.URL.Query().Get("due_soon_days"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			http.Error(w, "invalid due_soon_days", http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+
+	loads, err := h.projectWorkload(r.Context(), projectID, time.Duration(days)*24*time.Hour)
+	if err != nil {
+		writeServerError(w, r, "failed to compute workload", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &ProjectWorkload{ProjectID: projectID, DueSoonDays: days, Assignees: loads})
+}
+
+// projectWorkload loads tasks and users and computes a project's workload.
+func (h *WorkloadHandler) projectWorkload(ctx context.Context, projectID models.ProjectID, dueSoon time.Duration) ([]*AssigneeWorkload, error) {
+	tasks, err := h.tasks.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users, err := h.users.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return computeWorkload(projectID, publishedTasks(tasks), users, dueSoon, time.Now()), nil
+}
+
+// SetCapacity handles PUT /users/{id}/capacity requests.
+//
+// An empty body or JSON null removes the user's capacity limits.
+func (h *WorkloadHandler) SetCapacity(w http.ResponseWriter, r *http.Request, userID models.UserID) {
+	if !requireManage(w, r) {
+		return
+	}
+
+	var capacity *models.UserCapacity
+	if err := decodeJSON(w, r, &capacity); err != nil && !errors.Is(err, io.EOF) {
+		writeDecodeError(w, err)
+		return
+	}
+	if capacity != nil {
+		if err := capacity.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	user, err := h.users.Get(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			http.Error(w, "user not found", http.StatusNotFound)
+			return
+		}
+		writeServerError(w, r, "failed to get user", err)
+		return
+	}
+
+	updated := *user
+	updated.Capacity = capacity
+	if err := h.users.Update(r.Context(), &updated); err != nil {
+		writeServerError(w, r, "failed to update user", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &updated)
+}
@@ -0,0 +1,75 @@
+package i18n
+
+// builtin holds the translations shipped with the tracker, for the most
+// common validation and error messages.
+var builtin = map[string]map[string]string{
+	"de": {
+		"authentication required":        "Anmeldung erforderlich",
+		"forbidden":                      "Zugriff verweigert",
+		"title is required":              "Titel ist erforderlich",
+		"project_id is required":         "project_id ist erforderlich",
+		"task not found":                 "Aufgabe nicht gefunden",
+		"project not found":              "Projekt nicht gefunden",
+		"user not found":                 "Benutzer nicht gefunden",
+		"template not found":             "Vorlage nicht gefunden",
+		"task was modified concurrently": "Aufgabe wurde gleichzeitig geändert",
+		"invalid limit":                  "Ungültiges Limit",
+		"invalid role":                   "Ungültige Rolle",
+		"invalid email format":           "Ungültiges E-Mail-Format",
+		"invalid username format":        "Ungültiges Benutzernamenformat",
+		"comment body is required":       "Kommentartext ist erforderlich",
+		"project name is required":       "Projektname ist erforderlich",
+		"content too large":              "Inhalt zu groß",
+		"rejected":                       "Abgelehnt",
+		"failed to get task":             "Aufgabe konnte nicht geladen werden",
+		"failed to list tasks":           "Aufgaben konnten nicht aufgelistet werden",
+		"failed to create task":          "Aufgabe konnte nicht erstellt werden",
+		"failed to update task":          "Aufgabe konnte nicht aktualisiert werden",
+	},
+	"es": {
+		"authentication required":        "Se requiere autenticación",
+		"forbidden":                      "Acceso denegado",
+		"title is required":              "El título es obligatorio",
+		"project_id is required":         "project_id es obligatorio",
+		"task not found":                 "Tarea no encontrada",
+		"project not found":              "Proyecto no encontrado",
+		"user not found":                 "Usuario no encontrado",
+		"template not found":             "Plantilla no encontrada",
+		"task was modified concurrently": "La tarea fue modificada simultáneamente",
+		"invalid limit":                  "Límite no válido",
+		"invalid role":                   "Rol no válido",
+		"invalid email format":           "Formato de correo electrónico no válido",
+		"invalid username format":        "Formato de nombre de usuario no válido",
+		"comment body is required":       "El texto del comentario es obligatorio",
+		"project name is required":       "El nombre del proyecto es obligatorio",
+		"content too large":              "Contenido demasiado grande",
+		"rejected":                       "Rechazado",
+		"failed to get task":             "No se pudo obtener la tarea",
+		"failed to list tasks":           "No se pudieron listar las tareas",
+		"failed to create task":          "No se pudo crear la tarea",
+		"failed to update task":          "No se pudo actualizar la tarea",
+	},
+	"fr": {
+		"authentication required":        "Authentification requise",
+		"forbidden":                      "Accès refusé",
+		"title is required":              "Le titre est obligatoire",
+		"project_id is required":         "project_id est obligatoire",
+		"task not found":                 "Tâche introuvable",
+		"project not found":              "Projet introuvable",
+		"user not found":                 "Utilisateur introuvable",
+		"template not found":             "Modèle introuvable",
+		"task was modified concurrently": "La tâche a été modifiée simultanément",
+		"invalid limit":                  "Limite invalide",
+		"invalid role":                   "Rôle invalide",
+		"invalid email format":           "Format d'adresse e-mail invalide",
+		"invalid username format":        "Format de nom d'utilisateur invalide",
+		"comment body is required":       "Le texte du commentaire est obligatoire",
+		"project name is required":       "Le nom du projet est obligatoire",
+		"content too large":              "Contenu trop volumineux",
+		"rejected":                       "Rejeté",
+		"failed to get task":             "Impossible de récupérer la tâche",
+		"failed to list tasks":           "Impossible de lister les tâches",
+		"failed to create task":          "Impossible de créer la tâche",
+		"failed to update task":          "Impossible de mettre à jour la tâche",
+	},
+}
@@ -0,0 +1,167 @@
+// Package i18n localizes API messages.
+//
+// Messages are keyed by their English text, so code keeps producing
+// plain English strings and a Catalog maps them to other languages.
+// Messages without a translation are returned unchanged, which makes
+// English the fallback for every language.
+package i18n
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage is the language messages are written in.
+const DefaultLanguage = "en"
+
+// Catalog holds message translations by language.
+type Catalog struct {
+	mu       sync.RWMutex
+	messages map[string]map[string]string
+}
+
+// NewCatalog creates an empty catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{messages: make(map[string]map[string]string)}
+}
+
+// Default returns a catalog with the built-in translations.
+func Default() *Catalog {
+	c := NewCatalog()
+	for lang, messages := range builtin {
+		c.Add(lang, messages)
+	}
+	return c
+}
+
+// Add adds translations for a language, keyed by English message,
+// replacing existing translations of the same messages.
+func (c *Catalog) Add(lang string, messages map[string]string) {
+	lang = normalize(lang)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m, ok := c.messages[lang]
+	if !ok {
+		m = make(map[string]string, len(messages))
+		c.messages[lang] = m
+	}
+	for k, v := range messages {
+		m[k] = v
+	}
+}
+
+// Load adds translations from JSON mapping languages to messages, such
+// as {"de": {"task not found": "Aufgabe nicht gefunden"}}.
+func (c *Catalog) Load(r io.Reader) error {
+	var catalogs map[string]map[string]string
+	if err := json.NewDecoder(r).Decode(&catalogs); err != nil {
+		return err
+	}
+	for lang, messages := range catalogs {
+		c.Add(lang, messages)
+	}
+	return nil
+}
+
+// Languages returns the languages with translations, plus the default
+// language, sorted.
+func (c *Catalog) Languages() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	langs := []string{DefaultLanguage}
+	for lang := range c.messages {
+		if lang != DefaultLanguage {
+			langs = append(langs, lang)
+		}
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Translate returns msg in a language.
+//
+// A message of the form "known: detail", as produced by wrapped errors,
+// has its known prefix translated and its detail kept. A regional
+// language such as pt-BR falls back to its base language.
+func (c *Catalog) Translate(lang, msg string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	lang = normalize(lang)
+	m, ok := c.messages[lang]
+	if !ok {
+		base, _, _ := strings.Cut(lang, "-")
+		if m, ok = c.messages[base]; !ok {
+			return msg
+		}
+	}
+	if t, ok := m[msg]; ok {
+		return t
+	}
+	if prefix, detail, ok := strings.Cut(msg, ": "); ok {
+		if t, ok := m[prefix]; ok {
+			return t + ": " + detail
+		}
+	}
+	return msg
+}
+
+// Negotiate picks the best of the supported languages for an
+// Accept-Language header, or returns "" if none is acceptable.
+//
+// Languages are tried in order of quality; a regional preference such
+// as fr-CA accepts its base language fr.
+func Negotiate(acceptLanguage string, supported []string) string {
+	type pref struct {
+		lang string
+		q    float64
+	}
+	var prefs []pref
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			prefs = append(prefs, pref{lang: normalize(tag), q: q})
+		}
+	}
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].q > prefs[j].q })
+
+	available := make(map[string]string, len(supported))
+	for _, lang := range supported {
+		available[normalize(lang)] = lang
+	}
+	for _, p := range prefs {
+		if p.lang == "*" {
+			return DefaultLanguage
+		}
+		if lang, ok := available[p.lang]; ok {
+			return lang
+		}
+		base, _, _ := strings.Cut(p.lang, "-")
+		if lang, ok := available[base]; ok {
+			return lang
+		}
+	}
+	return ""
+}
+
+// normalize lowercases a language tag and uses hyphens as separators.
+func normalize(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
@@ -0,0 +1,202 @@
+// Package markdown renders a safe subset of Markdown to HTML for task
+// descriptions and comments.
+//
+// Sanitization is by construction: raw HTML in the source is always
+// escaped, and links are emitted only for http, https, mailto and
+// relative URLs. The output can be embedded in a page without further
+// filtering.
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	headingRegex       = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	bulletRegex        = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	orderedRegex       = regexp.MustCompile(`^\s*\d{1,9}[.)]\s+(.*)$`)
+	blockquoteRegex    = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	fenceRegex         = regexp.MustCompile("^\\s*```")
+	horizontalRegex    = regexp.MustCompile(`^\s*(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	escapablePunct     = "\\`*_[]()#+-.!>"
+	allowedLinkSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+)
+
+// Render converts Markdown source to sanitized HTML.
+//
+// Supported syntax: ATX headings, paragraphs, fenced code blocks,
+// bulleted and numbered lists, blockquotes, horizontal rules, and the
+// inline forms **strong**, *emphasis*, `code` and [text](url).
+func Render(src string) string {
+	var b strings.Builder
+	renderBlocks(&b, strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n"))
+	return b.String()
+}
+
+// renderBlocks writes the block-level HTML for lines.
+func renderBlocks(b *strings.Builder, lines []string) {
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			b.WriteString("<p>")
+			b.WriteString(renderInline(strings.Join(para, "\n")))
+			b.WriteString("</p>\n")
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+		case fenceRegex.MatchString(line):
+			flush()
+			var code []string
+			for i++; i < len(lines) && !fenceRegex.MatchString(lines[i]); i++ {
+				code = append(code, lines[i])
+			}
+			b.WriteString("<pre><code>")
+			b.WriteString(html.EscapeString(strings.Join(code, "\n")))
+			b.WriteString("</code></pre>\n")
+		case headingRegex.MatchString(line):
+			flush()
+			m := headingRegex.FindStringSubmatch(line)
+			tag := "h" + string(rune('0'+len(m[1])))
+			b.WriteString("<" + tag + ">" + renderInline(m[2]) + "</" + tag + ">\n")
+		case horizontalRegex.MatchString(line):
+			flush()
+			b.WriteString("<hr>\n")
+		case blockquoteRegex.MatchString(line):
+			flush()
+			var quoted []string
+			for ; i < len(lines) && blockquoteRegex.MatchString(lines[i]); i++ {
+				quoted = append(quoted, blockquoteRegex.FindStringSubmatch(lines[i])[1])
+			}
+			i--
+			b.WriteString("<blockquote>\n")
+			renderBlocks(b, quoted)
+			b.WriteString("</blockquote>\n")
+		case bulletRegex.MatchString(line):
+			flush()
+			i = renderList(b, lines, i, bulletRegex, "ul")
+		case orderedRegex.MatchString(line):
+			flush()
+			i = renderList(b, lines, i, orderedRegex, "ol")
+		default:
+			para = append(para, strings.TrimSpace(line))
+		}
+	}
+	flush()
+}
+
+// renderList writes consecutive list items matching item starting at
+// lines[start] and returns the index of the last line consumed.
+func renderList(b *strings.Builder, lines []string, start int, item *regexp.Regexp, tag string) int {
+	b.WriteString("<" + tag + ">\n")
+	i := start
+	for ; i < len(lines) && item.MatchString(lines[i]); i++ {
+		b.WriteString("<li>" + renderInline(item.FindStringSubmatch(lines[i])[1]) + "</li>\n")
+	}
+	b.WriteString("</" + tag + ">\n")
+	return i - 1
+}
+
+// renderInline converts inline Markdown to HTML, escaping everything else.
+func renderInline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte(escapablePunct, s[i+1]) >= 0:
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+		case c == '`':
+			if end := strings.IndexByte(s[i+1:], '`'); end >= 0 {
+				b.WriteString("<code>" + html.EscapeString(s[i+1:i+1+end]) + "</code>")
+				i += end + 2
+				continue
+			}
+		case strings.HasPrefix(s[i:], "**"):
+			if end := strings.Index(s[i+2:], "**"); end > 0 {
+				b.WriteString("<strong>" + renderInline(s[i+2:i+2+end]) + "</strong>")
+				i += end + 4
+				continue
+			}
+		case c == '*' || (c == '_' && (i == 0 || !isWordByte(s[i-1]))):
+			if end := strings.IndexByte(s[i+1:], c); end > 0 {
+				b.WriteString("<em>" + renderInline(s[i+1:i+1+end]) + "</em>")
+				i += end + 2
+				continue
+			}
+		case c == '[':
+			if text, href, n, ok := parseLink(s[i:]); ok {
+				if safe, ok := safeURL(href); ok {
+					b.WriteString(`<a href="` + html.EscapeString(safe) + `" rel="nofollow noopener">` + renderInline(text) + "</a>")
+				} else {
+					b.WriteString(renderInline(text))
+				}
+				i += n
+				continue
+			}
+		}
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+// parseLink parses a [text](href) link at the start of s, returning the
+// number of bytes it spans.
+func parseLink(s string) (text, href string, n int, ok bool) {
+	closeText := strings.Index(s, "](")
+	if closeText < 0 {
+		return "", "", 0, false
+	}
+	closeHref := strings.IndexByte(s[closeText+2:], ')')
+	if closeHref < 0 {
+		return "", "", 0, false
+	}
+	href = strings.TrimSpace(s[closeText+2 : closeText+2+closeHref])
+	return s[1:closeText], href, closeText + 3 + closeHref, true
+}
+
+// safeURL reports whether href may be used as a link target, returning
+// it in normalized form. Only relative URLs and allowed schemes pass.
+func safeURL(href string) (string, bool) {
+	if href == "" || strings.ContainsAny(href, " \t\n\x00") {
+		return "", false
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return "", false
+	}
+	if u.Scheme != "" && !allowedLinkSchemes[strings.ToLower(u.Scheme)] {
+		return "", false
+	}
+	return u.String(), true
+}
+
+// isWordByte reports whether c is an ASCII letter, digit or underscore,
+// so that snake_case identifiers are not read as emphasis.
+func isWordByte(c byte) bool {
+	return c == '_' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// Escape backslash-escapes Markdown punctuation in s and folds newlines
+// into spaces, so text such as a task title renders literally inside a
+// single line or list item.
+func Escape(s string) string {
+	var b strings.Builder
+	for _, r := range strings.Join(strings.Fields(s), " ") {
+		if r < 0x80 && strings.IndexByte(escapablePunct, byte(r)) >= 0 {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,346 @@
+// Package migrate manages versioned schema migrations for the SQL stores.
+//
+// Migrations are plain SQL files named NNNN_description.up.sql and
+// NNNN_description.down.sql. The package ships the TaskTracker schema as
+// embedded migrations; applied versions are tracked in a
+// schema_migrations table.
+//
+// The schema covers the core stores only: users, tasks with every field
+// of models.Task, comments and the audit log. Other stores have no SQL
+// tables yet. Key and rank are stored as task_key and task_rank, since
+// both are reserved words in some databases; list fields are stored as
+// JSON text, like tags.
+//
+// No command is shipped, since the database driver is left to the
+// deployment: an entry point registers its driver, opens the database
+// and passes its arguments to Migrator.Run.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var embedded embed.FS
+
+var fileRegex = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// ErrPendingMigrations is returned by OnStart when the schema is behind
+// and automatic migration is disabled.
+var ErrPendingMigrations = errors.New("pending schema migrations")
+
+// ErrMissingDown is returned when rolling back a migration without a down script.
+var ErrMissingDown = errors.New("migration has no down script")
+
+// Migration is a single versioned schema change.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a migration has been applied.
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// Load reads migrations from the root of fsys.
+//
+// Returns an error if a file name is malformed or a version has no up script.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileRegex.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migrate: malformed file name %q", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migrate: version %d has no up script", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Default returns the migrations shipped with this package.
+func Default() ([]Migration, error) {
+	sub, err := fs.Sub(embedded, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return Load(sub)
+}
+
+// Migrator applies and rolls back migrations against a database.
+type Migrator struct {
+	db          *sql.DB
+	migrations  []Migration
+	table       string
+	placeholder func(n int) string
+}
+
+// Option is a function that configures a Migrator.
+type Option func(*Migrator)
+
+// WithTable sets the name of the version tracking table.
+func WithTable(table string) Option {
+	return func(m *Migrator) {
+		m.table = table
+	}
+}
+
+// WithDollarPlaceholders uses $1-style bind parameters, as PostgreSQL expects.
+func WithDollarPlaceholders() Option {
+	return func(m *Migrator) {
+		m.placeholder = func(n int) string { return "$" + strconv.Itoa(n) }
+	}
+}
+
+// New creates a migrator for the given migrations.
+//
+// By default versions are tracked in schema_migrations and bind
+// parameters use the ? style.
+func New(db *sql.DB, migrations []Migration, opts ...Option) *Migrator {
+	m := &Migrator{
+		db:          db,
+		migrations:  migrations,
+		table:       "schema_migrations",
+		placeholder: func(int) string { return "?" },
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// ensureTable creates the version tracking table if needed.
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+m.table+
+		" (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at TIMESTAMP NOT NULL)")
+	return err
+}
+
+// applied returns the applied versions and when they were applied.
+func (m *Migrator) applied(ctx context.Context) (map[int]time.Time, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, "SELECT version, applied_at FROM "+m.table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		versions[version] = at
+	}
+	return versions, rows.Err()
+}
+
+// Status lists every known migration and whether it has been applied.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, mig := range m.migrations {
+		statuses[i] = MigrationStatus{Version: mig.Version, Name: mig.Name}
+		if at, ok := applied[mig.Version]; ok {
+			statuses[i].AppliedAt = &at
+		}
+	}
+	return statuses, nil
+}
+
+// Up applies every pending migration in version order.
+//
+// Each migration runs in its own transaction together with its version
+// record. Returns the versions that were applied.
+func (m *Migrator) Up(ctx context.Context) ([]int, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make([]int, 0)
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+		insert := fmt.Sprintf("INSERT INTO %s (version, name, applied_at) VALUES (%s, %s, %s)",
+			m.table, m.placeholder(1), m.placeholder(2), m.placeholder(3))
+		if err := m.exec(ctx, mig.Up, insert, mig.Version, mig.Name, time.Now().UTC()); err != nil {
+			return done, fmt.Errorf("migrate: up %d_%s: %w", mig.Version, mig.Name, err)
+		}
+		done = append(done, mig.Version)
+	}
+	return done, nil
+}
+
+// Down rolls back the most recently applied migrations.
+//
+// Returns the versions that were rolled back.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]int, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make([]int, 0)
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		mig := m.migrations[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+		if mig.Down == "" {
+			return done, fmt.Errorf("migrate: down %d_%s: %w", mig.Version, mig.Name, ErrMissingDown)
+		}
+		remove := fmt.Sprintf("DELETE FROM %s WHERE version = %s", m.table, m.placeholder(1))
+		if err := m.exec(ctx, mig.Down, remove, mig.Version); err != nil {
+			return done, fmt.Errorf("migrate: down %d_%s: %w", mig.Version, mig.Name, err)
+		}
+		done = append(done, mig.Version)
+	}
+	return done, nil
+}
+
+// exec runs a migration script and its bookkeeping statement in one transaction.
+func (m *Migrator) exec(ctx context.Context, script, bookkeeping string, args ...any) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, bookkeeping, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// OnStart prepares the schema when the server starts.
+//
+// With auto enabled all pending migrations are applied; otherwise
+// ErrPendingMigrations is returned if the schema is behind.
+func (m *Migrator) OnStart(ctx context.Context, auto bool) error {
+	if auto {
+		_, err := m.Up(ctx)
+		return err
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			return ErrPendingMigrations
+		}
+	}
+	return nil
+}
+
+// Run executes a migrate command: "up", "down [steps]", or "status".
+//
+// It is meant to back a command-line entry point that has already
+// opened the database with its driver registered.
+func (m *Migrator) Run(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("migrate: usage: migrate up|down [steps]|status")
+	}
+
+	switch args[0] {
+	case "up":
+		versions, err := m.Up(ctx)
+		for _, v := range versions {
+			fmt.Fprintf(out, "applied %04d\n", v)
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("migrate: invalid step count %q", args[1])
+			}
+			steps = n
+		}
+		versions, err := m.Down(ctx, steps)
+		for _, v := range versions {
+			fmt.Fprintf(out, "rolled back %04d\n", v)
+		}
+		return err
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(out, "%04d_%s\t%s\n", s.Version, s.Name, state)
+		}
+		return nil
+	default:
+		return fmt.Errorf("migrate: unknown command %q", args[0])
+	}
+}
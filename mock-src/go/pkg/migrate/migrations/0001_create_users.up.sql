@@ -0,0 +1,12 @@
+CREATE TABLE users (
+    id            TEXT PRIMARY KEY,
+    username      TEXT NOT NULL UNIQUE,
+    email         TEXT NOT NULL UNIQUE,
+    display_name  TEXT NOT NULL,
+    avatar_url    TEXT NOT NULL DEFAULT '',
+    role          TEXT NOT NULL,
+    is_active     BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at    TIMESTAMP NOT NULL,
+    last_login    TIMESTAMP NULL,
+    preferences   TEXT NOT NULL DEFAULT '{}'
+);
@@ -0,0 +1,16 @@
+CREATE TABLE tasks (
+    id           TEXT PRIMARY KEY,
+    title        TEXT NOT NULL,
+    description  TEXT NOT NULL DEFAULT '',
+    project_id   TEXT NOT NULL,
+    assignee_id  TEXT NULL REFERENCES users (id),
+    status       TEXT NOT NULL,
+    priority     INTEGER NOT NULL,
+    created_at   TIMESTAMP NOT NULL,
+    updated_at   TIMESTAMP NOT NULL,
+    due_date     TIMESTAMP NULL,
+    tags         TEXT NOT NULL DEFAULT '[]'
+);
+
+CREATE INDEX tasks_project_id_idx ON tasks (project_id);
+CREATE INDEX tasks_assignee_id_idx ON tasks (assignee_id);
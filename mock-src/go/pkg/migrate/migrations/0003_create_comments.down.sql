@@ -0,0 +1,2 @@
+DROP INDEX comments_task_id_idx;
+DROP TABLE comments;
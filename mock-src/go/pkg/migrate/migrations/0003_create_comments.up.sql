@@ -0,0 +1,10 @@
+CREATE TABLE comments (
+    id          TEXT PRIMARY KEY,
+    task_id     TEXT NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    author_id   TEXT NOT NULL REFERENCES users (id),
+    body        TEXT NOT NULL,
+    created_at  TIMESTAMP NOT NULL,
+    updated_at  TIMESTAMP NOT NULL
+);
+
+CREATE INDEX comments_task_id_idx ON comments (task_id);
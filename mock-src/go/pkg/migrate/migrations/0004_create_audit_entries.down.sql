@@ -0,0 +1,2 @@
+DROP INDEX audit_entries_created_at_idx;
+DROP TABLE audit_entries;
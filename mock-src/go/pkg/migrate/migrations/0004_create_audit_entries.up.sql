@@ -0,0 +1,11 @@
+CREATE TABLE audit_entries (
+    id           TEXT PRIMARY KEY,
+    actor_id     TEXT NOT NULL,
+    action       TEXT NOT NULL,
+    target_type  TEXT NOT NULL,
+    target_id    TEXT NOT NULL,
+    details      TEXT NOT NULL DEFAULT '{}',
+    created_at   TIMESTAMP NOT NULL
+);
+
+CREATE INDEX audit_entries_created_at_idx ON audit_entries (created_at);
@@ -0,0 +1,20 @@
+DROP INDEX tasks_task_key_idx;
+
+ALTER TABLE tasks DROP COLUMN rejection_reason;
+ALTER TABLE tasks DROP COLUMN review_from;
+ALTER TABLE tasks DROP COLUMN reviewer_id;
+ALTER TABLE tasks DROP COLUMN allowed_user_ids;
+ALTER TABLE tasks DROP COLUMN visibility;
+ALTER TABLE tasks DROP COLUMN draft;
+ALTER TABLE tasks DROP COLUMN created_by;
+ALTER TABLE tasks DROP COLUMN voters;
+ALTER TABLE tasks DROP COLUMN watchers;
+ALTER TABLE tasks DROP COLUMN estimate;
+ALTER TABLE tasks DROP COLUMN checklist;
+ALTER TABLE tasks DROP COLUMN start_date;
+ALTER TABLE tasks DROP COLUMN blocked_by_task_id;
+ALTER TABLE tasks DROP COLUMN responded_at;
+ALTER TABLE tasks DROP COLUMN status_changed_at;
+ALTER TABLE tasks DROP COLUMN assigned_at;
+ALTER TABLE tasks DROP COLUMN task_rank;
+ALTER TABLE tasks DROP COLUMN task_key;
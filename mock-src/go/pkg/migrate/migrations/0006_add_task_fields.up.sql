@@ -0,0 +1,21 @@
+ALTER TABLE tasks ADD COLUMN task_key TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN task_rank TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN assigned_at TIMESTAMP NULL;
+ALTER TABLE tasks ADD COLUMN status_changed_at TIMESTAMP NULL;
+ALTER TABLE tasks ADD COLUMN responded_at TIMESTAMP NULL;
+ALTER TABLE tasks ADD COLUMN blocked_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN blocked_by_task_id TEXT NULL;
+ALTER TABLE tasks ADD COLUMN start_date TIMESTAMP NULL;
+ALTER TABLE tasks ADD COLUMN checklist TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE tasks ADD COLUMN estimate REAL NOT NULL DEFAULT 0;
+ALTER TABLE tasks ADD COLUMN watchers TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE tasks ADD COLUMN voters TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE tasks ADD COLUMN created_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN draft BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE tasks ADD COLUMN visibility TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN allowed_user_ids TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE tasks ADD COLUMN reviewer_id TEXT NULL REFERENCES users (id);
+ALTER TABLE tasks ADD COLUMN review_from TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN rejection_reason TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX tasks_task_key_idx ON tasks (task_key);
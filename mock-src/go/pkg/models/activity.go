@@ -0,0 +1,87 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Activity is an entry in an activity feed: an actor did something
+// (the verb) to a target.
+//
+// Verbs are namespaced by target type, such as task.status_changed,
+// comment.created or user.role_changed. ProjectID is set for targets
+// that belong to a project.
+type Activity struct {
+	ID         string            `json:"id"`
+	ActorID    UserID            `json:"actor_id,omitempty"`
+	Verb       string            `json:"verb"`
+	TargetType string            `json:"target_type"`
+	TargetID   string            `json:"target_id"`
+	ProjectID  ProjectID         `json:"project_id,omitempty"`
+	Details    map[string]string `json:"details,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+// ActivityFromTaskEvent describes a task event as an activity in a
+// project.
+func ActivityFromTaskEvent(e *TaskEvent, projectID ProjectID) *Activity {
+	a := &Activity{
+		ID:         fmt.Sprintf("task:%s:%d", e.TaskID, e.Version),
+		ActorID:    e.ActorID,
+		Verb:       "task." + string(e.Type),
+		TargetType: "task",
+		TargetID:   string(e.TaskID),
+		ProjectID:  projectID,
+		Details:    make(map[string]string),
+		OccurredAt: e.OccurredAt,
+	}
+	switch e.Type {
+	case TaskEventCreated:
+		a.Details["title"] = e.State.Title
+	case TaskEventRetitled:
+		a.Details["title"] = e.Title
+	case TaskEventStatusChanged:
+		a.Details["status"] = string(e.Status)
+	case TaskEventPriorityChanged:
+		a.Details["priority"] = strconv.Itoa(int(e.Priority))
+	case TaskEventAssigned:
+		if e.AssigneeID != nil {
+			a.Details["assignee_id"] = string(*e.AssigneeID)
+		}
+	case TaskEventDueDateChanged:
+		if e.DueDate != nil {
+			a.Details["due_date"] = e.DueDate.Format(time.RFC3339)
+		}
+	}
+	return a
+}
+
+// ActivityFromComment describes a new comment as an activity in a
+// project.
+func ActivityFromComment(c *Comment, projectID ProjectID) *Activity {
+	return &Activity{
+		ID:         "comment:" + c.ID,
+		ActorID:    c.AuthorID,
+		Verb:       "comment.created",
+		TargetType: "task",
+		TargetID:   string(c.TaskID),
+		ProjectID:  projectID,
+		Details:    map[string]string{"comment_id": c.ID},
+		OccurredAt: c.CreatedAt,
+	}
+}
+
+// ActivityFromAudit describes an audit entry as an activity.
+func ActivityFromAudit(e *AuditEntry) *Activity {
+	return &Activity{
+		ID:         "audit:" + e.ID,
+		ActorID:    e.ActorID,
+		Verb:       string(e.Action),
+		TargetType: e.TargetType,
+		TargetID:   e.TargetID,
+		Details:    e.Details,
+		OccurredAt: e.CreatedAt,
+	}
+}
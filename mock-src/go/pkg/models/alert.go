@@ -0,0 +1,147 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AlertSource identifies a monitoring or paging system.
+type AlertSource string
+
+const (
+	// AlertSourceAlertmanager is the Prometheus Alertmanager.
+	AlertSourceAlertmanager AlertSource = "alertmanager"
+	// AlertSourcePagerDuty is PagerDuty.
+	AlertSourcePagerDuty AlertSource = "pagerduty"
+)
+
+// ErrInvalidAlert is returned when an alert payload is malformed.
+var ErrInvalidAlert = errors.New("invalid alert payload")
+
+// DefaultSeverityPriorities maps common alert severities, including
+// PagerDuty priorities and urgencies, to task priorities.
+var DefaultSeverityPriorities = map[string]TaskPriority{
+	"critical": TaskPriorityCritical,
+	"p1":       TaskPriorityCritical,
+	"high":     TaskPriorityHigh,
+	"error":    TaskPriorityHigh,
+	"p2":       TaskPriorityHigh,
+	"warning":  TaskPriorityMedium,
+	"p3":       TaskPriorityMedium,
+	"low":      TaskPriorityLow,
+	"info":     TaskPriorityLow,
+	"p4":       TaskPriorityLow,
+	"p5":       TaskPriorityLow,
+}
+
+// Alert is a firing or resolved alert from a monitoring system.
+//
+// Fingerprint identifies the alert across notifications, so repeats
+// update one incident task and the recovery resolves it. Severity is
+// lowercase.
+type Alert struct {
+	Source      AlertSource
+	Fingerprint string
+	Title       string
+	Description string
+	Severity    string
+	URL         string
+	Resolved    bool
+}
+
+// Key identifies the alert across notifications.
+func (a *Alert) Key() string {
+	return string(a.Source) + ":" + a.Fingerprint
+}
+
+// ParseAlertmanager parses an Alertmanager webhook notification, which
+// may group several alerts.
+func ParseAlertmanager(body []byte) ([]*Alert, error) {
+	var payload struct {
+		Alerts []struct {
+			Status       string            `json:"status"`
+			Labels       map[string]string `json:"labels"`
+			Annotations  map[string]string `json:"annotations"`
+			GeneratorURL string            `json:"generatorURL"`
+			Fingerprint  string            `json:"fingerprint"`
+		} `json:"alerts"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAlert, err)
+	}
+
+	alerts := make([]*Alert, 0, len(payload.Alerts))
+	for _, a := range payload.Alerts {
+		if a.Fingerprint == "" {
+			return nil, fmt.Errorf("%w: alert without fingerprint", ErrInvalidAlert)
+		}
+		title := a.Annotations["summary"]
+		if title == "" {
+			title = a.Labels["alertname"]
+		}
+		if instance := a.Labels["instance"]; instance != "" && title == a.Labels["alertname"] {
+			title += " on " + instance
+		}
+		alerts = append(alerts, &Alert{
+			Source:      AlertSourceAlertmanager,
+			Fingerprint: a.Fingerprint,
+			Title:       title,
+			Description: a.Annotations["description"],
+			Severity:    strings.ToLower(a.Labels["severity"]),
+			URL:         a.GeneratorURL,
+			Resolved:    a.Status == "resolved",
+		})
+	}
+	return alerts, nil
+}
+
+// ParsePagerDuty parses a PagerDuty V3 incident webhook. Events that
+// neither trigger nor resolve an incident are returned as nil.
+func ParsePagerDuty(body []byte) (*Alert, error) {
+	var payload struct {
+		Event struct {
+			EventType string `json:"event_type"`
+			Data      struct {
+				ID       string `json:"id"`
+				Title    string `json:"title"`
+				HTMLURL  string `json:"html_url"`
+				Urgency  string `json:"urgency"`
+				Priority *struct {
+					Summary string `json:"summary"`
+				} `json:"priority"`
+			} `json:"data"`
+		} `json:"event"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAlert, err)
+	}
+
+	event := payload.Event
+	var resolved bool
+	switch event.EventType {
+	case "incident.triggered", "incident.reopened", "incident.priority_updated":
+	case "incident.resolved":
+		resolved = true
+	default:
+		return nil, nil
+	}
+	if event.Data.ID == "" {
+		return nil, fmt.Errorf("%w: incident without id", ErrInvalidAlert)
+	}
+
+	severity := event.Data.Urgency
+	if event.Data.Priority != nil && event.Data.Priority.Summary != "" {
+		severity = event.Data.Priority.Summary
+	}
+	return &Alert{
+		Source:      AlertSourcePagerDuty,
+		Fingerprint: event.Data.ID,
+		Title:       event.Data.Title,
+		Severity:    strings.ToLower(severity),
+		URL:         event.Data.HTMLURL,
+		Resolved:    resolved,
+	}, nil
+}
@@ -0,0 +1,87 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import "errors"
+
+// AssignmentStrategy selects how new tasks in a project are assigned.
+type AssignmentStrategy string
+
+const (
+	// AssignmentRoundRobin assigns team members in turn.
+	AssignmentRoundRobin AssignmentStrategy = "round_robin"
+	// AssignmentByTag assigns the user mapped to the task's first mapped tag.
+	AssignmentByTag AssignmentStrategy = "by_tag"
+	// AssignmentLeastLoaded assigns the team member with the fewest open tasks.
+	AssignmentLeastLoaded AssignmentStrategy = "least_loaded"
+)
+
+// ErrInvalidAssignmentPolicy is returned when an assignment policy is
+// malformed.
+var ErrInvalidAssignmentPolicy = errors.New("invalid assignment policy")
+
+// AssignmentPolicy assigns new tasks in a project automatically.
+//
+// Team is used by the round-robin and least-loaded strategies, and
+// TagAssignees maps tags to user IDs for the by-tag strategy.
+type AssignmentPolicy struct {
+	ProjectID    ProjectID          `json:"project_id"`
+	Strategy     AssignmentStrategy `json:"strategy"`
+	Team         []UserID           `json:"team,omitempty"`
+	TagAssignees map[string]UserID  `json:"tag_assignees,omitempty"`
+}
+
+// Validate checks that the policy has the users its strategy needs.
+func (p *AssignmentPolicy) Validate() error {
+	switch p.Strategy {
+	case AssignmentRoundRobin, AssignmentLeastLoaded:
+		if len(p.Team) == 0 {
+			return ErrInvalidAssignmentPolicy
+		}
+	case AssignmentByTag:
+		if len(p.TagAssignees) == 0 {
+			return ErrInvalidAssignmentPolicy
+		}
+	default:
+		return ErrInvalidAssignmentPolicy
+	}
+	return nil
+}
+
+// UserIDs returns every user the policy may assign.
+func (p *AssignmentPolicy) UserIDs() []UserID {
+	ids := append([]UserID(nil), p.Team...)
+	for _, id := range p.TagAssignees {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Choose returns the user to assign a new task to, or false if the
+// policy does not apply to it.
+//
+// turn is the number of tasks previously assigned round-robin, and
+// openTasks the number of open tasks per user.
+func (p *AssignmentPolicy) Choose(task *Task, turn int, openTasks map[UserID]int) (UserID, bool) {
+	switch p.Strategy {
+	case AssignmentRoundRobin:
+		if len(p.Team) == 0 {
+			return "", false
+		}
+		return p.Team[turn%len(p.Team)], true
+	case AssignmentByTag:
+		for _, tag := range task.Tags {
+			if id, ok := p.TagAssignees[tag]; ok {
+				return id, true
+			}
+		}
+	case AssignmentLeastLoaded:
+		var best UserID
+		for _, id := range p.Team {
+			if best == "" || openTasks[id] < openTasks[best] {
+				best = id
+			}
+		}
+		return best, best != ""
+	}
+	return "", false
+}
@@ -0,0 +1,67 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxAttachmentSize is the largest attachment accepted, in bytes.
+const MaxAttachmentSize = 10 << 20
+
+// ErrInvalidAttachment is returned when an attachment has no name or no
+// content, or exceeds MaxAttachmentSize.
+var ErrInvalidAttachment = errors.New("invalid attachment")
+
+// Attachment is a file attached to a task. Its content is not part of
+// its JSON form.
+type Attachment struct {
+	ID          string    `json:"id"`
+	TaskID      TaskID    `json:"task_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int       `json:"size"`
+	Data        []byte    `json:"-"`
+	UploadedBy  UserID    `json:"uploaded_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewAttachment creates an attachment on a task.
+//
+// The filename is reduced to its base name. An empty content type
+// defaults to application/octet-stream.
+func NewAttachment(taskID TaskID, filename, contentType string, data []byte) (*Attachment, error) {
+	filename = filepath.Base(strings.ReplaceAll(strings.TrimSpace(filename), "\\", "/"))
+	if filename == "." || filename == "/" || len(data) == 0 || len(data) > MaxAttachmentSize {
+		return nil, ErrInvalidAttachment
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &Attachment{
+		ID:          uuid.New().String(),
+		TaskID:      taskID,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        len(data),
+		Data:        data,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// EntityID returns the attachment's ID.
+func (a *Attachment) EntityID() string {
+	return a.ID
+}
+
+// Clone returns a deep copy of the attachment.
+func (a *Attachment) Clone() *Attachment {
+	c := *a
+	c.Data = slices.Clone(a.Data)
+	return &c
+}
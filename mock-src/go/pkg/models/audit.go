@@ -0,0 +1,50 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction identifies the kind of change recorded in an audit entry.
+type AuditAction string
+
+const (
+	// AuditActionRoleChanged records a change to a user's role.
+	AuditActionRoleChanged AuditAction = "user.role_changed"
+	// AuditActionUserDeactivated records the deactivation of a user.
+	AuditActionUserDeactivated AuditAction = "user.deactivated"
+	// AuditActionUserErased records the anonymization of a user's personal data.
+	AuditActionUserErased AuditAction = "user.erased"
+	// AuditActionRequestRejected records a request refused because of
+	// its origin.
+	AuditActionRequestRejected AuditAction = "request.rejected"
+)
+
+// AuditEntry records a privileged change made by a user.
+//
+// Details holds action-specific values such as the previous and new
+// role for a role change.
+type AuditEntry struct {
+	ID         string            `json:"id"`
+	ActorID    UserID            `json:"actor_id"`
+	Action     AuditAction       `json:"action"`
+	TargetType string            `json:"target_type"`
+	TargetID   string            `json:"target_id"`
+	Details    map[string]string `json:"details,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// NewAuditEntry creates a new audit entry for an action on a target.
+func NewAuditEntry(actorID UserID, action AuditAction, targetType, targetID string) *AuditEntry {
+	return &AuditEntry{
+		ID:         uuid.New().String(),
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Details:    make(map[string]string),
+		CreatedAt:  time.Now(),
+	}
+}
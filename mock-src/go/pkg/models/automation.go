@@ -0,0 +1,223 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AutomationActionType identifies what an automation action does.
+type AutomationActionType string
+
+const (
+	// AutomationNotify notifies the action's recipients: user IDs, or
+	// channels written with a leading #.
+	AutomationNotify AutomationActionType = "notify"
+	// AutomationAddTag adds the action's tag to the task.
+	AutomationAddTag AutomationActionType = "add_tag"
+	// AutomationRemoveTag removes the action's tag from the task.
+	AutomationRemoveTag AutomationActionType = "remove_tag"
+	// AutomationSetPriority sets the task's priority to the action's rank.
+	AutomationSetPriority AutomationActionType = "set_priority"
+)
+
+// maxAutomationActions caps the actions of a single rule.
+const maxAutomationActions = 10
+
+// ErrInvalidAutomationRule is returned when an automation rule is malformed.
+var ErrInvalidAutomationRule = errors.New("invalid automation rule")
+
+// AutomationFields are the identifiers an automation condition may use.
+//
+// priority is the task's rank and priority_name its name in the default
+// priority scheme. previous_status equals status unless the triggering
+// event changed it.
+var AutomationFields = []string{
+	"event", "status", "previous_status", "priority", "priority_name",
+	"title", "tags", "assignee_id", "project_id", "draft",
+}
+
+// AutomationAction is one step run when an automation rule matches.
+type AutomationAction struct {
+	Type       AutomationActionType `json:"type"`
+	Recipients []string             `json:"recipients,omitempty"`
+	Message    string               `json:"message,omitempty"`
+	Tag        string               `json:"tag,omitempty"`
+	Priority   TaskPriority         `json:"priority,omitempty"`
+}
+
+// AutomationRule runs actions on a project's tasks when a domain event
+// of type Trigger occurs and Condition, an Expr over AutomationFields,
+// holds. An empty Condition always holds.
+type AutomationRule struct {
+	ID        string             `json:"id"`
+	ProjectID ProjectID          `json:"project_id"`
+	Name      string             `json:"name"`
+	Trigger   string             `json:"trigger"`
+	Condition string             `json:"condition,omitempty"`
+	Actions   []AutomationAction `json:"actions"`
+	Enabled   bool               `json:"enabled"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// NewAutomationRule creates an enabled rule for a project.
+func NewAutomationRule(projectID ProjectID, name, trigger, condition string) *AutomationRule {
+	return &AutomationRule{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		Name:      name,
+		Trigger:   trigger,
+		Condition: condition,
+		Actions:   make([]AutomationAction, 0),
+		Enabled:   true,
+		CreatedAt: time.Now(),
+	}
+}
+
+// EntityID returns the rule's ID.
+func (r *AutomationRule) EntityID() string {
+	return r.ID
+}
+
+// Clone returns a deep copy of the automation rule.
+func (r *AutomationRule) Clone() *AutomationRule {
+	c := *r
+	c.Actions = slices.Clone(r.Actions)
+	for i := range c.Actions {
+		c.Actions[i].Recipients = slices.Clone(c.Actions[i].Recipients)
+	}
+	return &c
+}
+
+// Validate checks the rule's fields, checks that its trigger is one of
+// triggers, compiles its condition and checks that it only uses
+// AutomationFields.
+func (r *AutomationRule) Validate(triggers []string) error {
+	if r.ProjectID == "" || strings.TrimSpace(r.Name) == "" || r.Trigger == "" {
+		return fmt.Errorf("%w: project_id, name and trigger are required", ErrInvalidAutomationRule)
+	}
+	if !slices.Contains(triggers, r.Trigger) {
+		return fmt.Errorf("%w: unknown trigger %q", ErrInvalidAutomationRule, r.Trigger)
+	}
+	if len(r.Actions) == 0 || len(r.Actions) > maxAutomationActions {
+		return fmt.Errorf("%w: between 1 and %d actions are required", ErrInvalidAutomationRule, maxAutomationActions)
+	}
+	for i, a := range r.Actions {
+		if err := a.validate(); err != nil {
+			return fmt.Errorf("%w: action %d: %v", ErrInvalidAutomationRule, i, err)
+		}
+	}
+	_, err := r.Compile()
+	return err
+}
+
+// CheckPriorities checks that the priorities the rule's set_priority
+// actions set are ranks of scheme.
+func (r *AutomationRule) CheckPriorities(scheme *PriorityScheme) error {
+	for i, a := range r.Actions {
+		if a.Type == AutomationSetPriority && !scheme.Has(a.Priority) {
+			return fmt.Errorf("%w: action %d: priority %d is not in the project's priority scheme", ErrInvalidAutomationRule, i, a.Priority)
+		}
+	}
+	return nil
+}
+
+// Compile parses the rule's condition. It returns nil for an empty
+// condition.
+func (r *AutomationRule) Compile() (*Expr, error) {
+	if strings.TrimSpace(r.Condition) == "" {
+		return nil, nil
+	}
+	expr, err := ParseExpr(r.Condition)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAutomationRule, err)
+	}
+	for _, ident := range expr.Idents() {
+		if !isAutomationField(ident) {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidAutomationRule, ident)
+		}
+	}
+	return expr, nil
+}
+
+// Matches reports whether the rule applies to task for an event of the
+// given type. previousStatus is the task's status before the event.
+func (r *AutomationRule) Matches(event string, task *Task, previousStatus TaskStatus) (bool, error) {
+	if !r.Enabled || event != r.Trigger || task.ProjectID != r.ProjectID {
+		return false, nil
+	}
+	expr, err := r.Compile()
+	if err != nil {
+		return false, err
+	}
+	if expr == nil {
+		return true, nil
+	}
+	return expr.Eval(AutomationEnv(event, task, previousStatus))
+}
+
+// validate checks that the action has the fields its type needs.
+func (a *AutomationAction) validate() error {
+	switch a.Type {
+	case AutomationNotify:
+		if len(a.Recipients) == 0 {
+			return errors.New("notify needs recipients")
+		}
+	case AutomationAddTag, AutomationRemoveTag:
+		if strings.TrimSpace(a.Tag) == "" {
+			return errors.New("a tag is required")
+		}
+	case AutomationSetPriority:
+		if a.Priority <= 0 {
+			return errors.New("a positive priority is required")
+		}
+	default:
+		return fmt.Errorf("unknown action %q", a.Type)
+	}
+	return nil
+}
+
+// AutomationEnv returns the values of AutomationFields for a task.
+func AutomationEnv(event string, task *Task, previousStatus TaskStatus) map[string]any {
+	if previousStatus == "" {
+		previousStatus = task.Status
+	}
+	priorityName := ""
+	for _, level := range DefaultPriorityScheme(task.ProjectID).Levels {
+		if level.Rank == task.Priority {
+			priorityName = level.Name
+		}
+	}
+	assignee := ""
+	if task.AssigneeID != nil {
+		assignee = string(*task.AssigneeID)
+	}
+	tags := append([]string{}, task.Tags...)
+	return map[string]any{
+		"event":           event,
+		"status":          string(task.Status),
+		"previous_status": string(previousStatus),
+		"priority":        float64(task.Priority),
+		"priority_name":   priorityName,
+		"title":           task.Title,
+		"tags":            tags,
+		"assignee_id":     assignee,
+		"project_id":      string(task.ProjectID),
+		"draft":           task.Draft,
+	}
+}
+
+// isAutomationField reports whether name is one of AutomationFields.
+func isAutomationField(name string) bool {
+	for _, f := range AutomationFields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
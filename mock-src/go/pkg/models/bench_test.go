@@ -0,0 +1,110 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/example/tasktracker/pkg/benchgate"
+)
+
+// benchTasks returns n open tasks spread over four projects, a quarter
+// of them overdue and a quarter blocked.
+func benchTasks(n int) []*Task {
+	now := time.Now()
+	tasks := make([]*Task, n)
+	for i := range tasks {
+		task := NewTask(fmt.Sprintf("task %d", i), ProjectID(fmt.Sprintf("project-%d", i%4)))
+		task.CreatedAt = now.Add(-time.Duration(i) * time.Hour)
+		switch i % 4 {
+		case 0:
+			due := now.Add(-48 * time.Hour)
+			task.DueDate = &due
+		case 1:
+			task.MarkBlocked("waiting")
+			task.StatusChangedAt = now.Add(-72 * time.Hour)
+		}
+		tasks[i] = task
+	}
+	return tasks
+}
+
+func BenchmarkEscalationRuleMatches(b *testing.B) {
+	tasks := benchTasks(1000)
+	rules := []*EscalationRule{
+		NewEscalationRule("project-0", "overdue", EscalateWhenOverdue, 24, EscalationNotify),
+		NewEscalationRule("project-1", "blocked", EscalateWhenBlocked, 48, EscalationNotify),
+		NewEscalationRule("project-2", "due soon", EscalateWhenDueSoon, 24, EscalationNotify),
+	}
+	now := time.Now()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, task := range tasks {
+			for _, rule := range rules {
+				rule.Matches(task, now)
+			}
+		}
+	}
+}
+
+func BenchmarkPolicyEvaluate(b *testing.B) {
+	policy, err := NewPolicy(
+		PolicyRule{Role: UserRoleViewer, Action: "read", Effect: PolicyEffectAllow},
+		PolicyRule{Role: UserRoleMember, Action: PolicyWildcard, ResourceType: "task", Effect: PolicyEffectAllow},
+		PolicyRule{Role: UserRoleMember, Action: "delete", ResourceType: "task", OwnOnly: true, Effect: PolicyEffectAllow},
+		PolicyRule{Role: PolicyWildcard, Action: "delete", ProjectID: "project-3", Effect: PolicyEffectDeny},
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+	user, err := NewUserWithOptions("bench", "bench@example.com", WithRole(UserRoleMember))
+	if err != nil {
+		b.Fatal(err)
+	}
+	resources := make([]*Resource, 0, 1000)
+	for _, task := range benchTasks(1000) {
+		resources = append(resources, TaskResource(task))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, res := range resources {
+			policy.Evaluate(user, "delete", res)
+		}
+	}
+}
+
+func BenchmarkSLAEvaluate(b *testing.B) {
+	tasks := benchTasks(1000)
+	sla := &SLA{RespondWithinHours: 4, ResolveWithinHours: 72}
+	now := time.Now()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, task := range tasks {
+			sla.Evaluate(task, now)
+		}
+	}
+}
+
+func BenchmarkTaskClone(b *testing.B) {
+	task := benchTasks(1)[0]
+	task.Tags = []string{"backend", "urgent", "customer"}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		task.Clone()
+	}
+}
+
+// TestBenchmarkBaseline fails when a benchmark allocates more per
+// operation than testdata/bench/baseline.txt records.
+func TestBenchmarkBaseline(t *testing.T) {
+	benchgate.Check(t, "../../testdata/bench/baseline.txt", "github.com/example/tasktracker/pkg/models", map[string]func(*testing.B){
+		"BenchmarkEscalationRuleMatches": BenchmarkEscalationRuleMatches,
+		"BenchmarkPolicyEvaluate":        BenchmarkPolicyEvaluate,
+		"BenchmarkSLAEvaluate":           BenchmarkSLAEvaluate,
+		"BenchmarkTaskClone":             BenchmarkTaskClone,
+	})
+}
@@ -0,0 +1,158 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// BurndownPoint is the work remaining at the end of one day.
+type BurndownPoint struct {
+	Date      time.Time `json:"date"`
+	Remaining int       `json:"remaining"`
+	Ideal     float64   `json:"ideal"`
+}
+
+// FlowPoint is the number of tasks in each status at the end of one day.
+type FlowPoint struct {
+	Date   time.Time          `json:"date"`
+	Counts map[TaskStatus]int `json:"counts"`
+}
+
+// chartDays returns the local midnights from from to to, inclusive.
+func chartDays(from, to time.Time, loc *time.Location) []time.Time {
+	from, to = from.In(loc), to.In(loc)
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc)
+	var days []time.Time
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return days
+}
+
+// Burndown returns the open task count at the end of each day from
+// from to to, in loc, with the ideal line falling from the first day's
+// count to zero on the last.
+//
+// It works from current state alone: a task counts as open from its
+// creation until it entered a closed status, so tasks reopened since
+// are counted by their current state only. Prefer BurndownFromFlow
+// when event history is available.
+func Burndown(tasks []*Task, from, to time.Time, loc *time.Location) []BurndownPoint {
+	var points []BurndownPoint
+	for _, day := range chartDays(from, to, loc) {
+		dayEnd := day.AddDate(0, 0, 1)
+		remaining := 0
+		for _, t := range tasks {
+			if !t.CreatedAt.Before(dayEnd) {
+				continue
+			}
+			if !t.IsOpen() && t.StatusSince().Before(dayEnd) {
+				continue
+			}
+			remaining++
+		}
+		points = append(points, BurndownPoint{Date: day, Remaining: remaining})
+	}
+	setIdealBurndown(points)
+	return points
+}
+
+// BurndownFromFlow derives a burndown from cumulative flow points,
+// counting every status but completed and cancelled as remaining.
+func BurndownFromFlow(flow []FlowPoint) []BurndownPoint {
+	points := make([]BurndownPoint, len(flow))
+	for i, f := range flow {
+		points[i].Date = f.Date
+		for status, n := range f.Counts {
+			if status != TaskStatusCompleted && status != TaskStatusCancelled {
+				points[i].Remaining += n
+			}
+		}
+	}
+	setIdealBurndown(points)
+	return points
+}
+
+// setIdealBurndown fills in the ideal line, falling linearly from the
+// first point's remaining count to zero at the last point.
+func setIdealBurndown(points []BurndownPoint) {
+	n := len(points)
+	if n == 0 {
+		return
+	}
+	first := float64(points[0].Remaining)
+	for i := range points {
+		if n == 1 {
+			points[i].Ideal = first
+			continue
+		}
+		points[i].Ideal = first * float64(n-1-i) / float64(n-1)
+	}
+}
+
+// CumulativeFlow replays task events to count a project's tasks in
+// each status at the end of each day from from to to, in loc.
+//
+// Events must be ordered by when they occurred. Tasks are counted in
+// the project they belonged to on each day; drafts and deleted tasks
+// are not counted.
+func CumulativeFlow(events []*TaskEvent, projectID ProjectID, from, to time.Time, loc *time.Location) []FlowPoint {
+	days := chartDays(from, to, loc)
+	points := make([]FlowPoint, 0, len(days))
+	state := make(map[TaskID]*Task)
+	next := 0
+	for _, day := range days {
+		dayEnd := day.AddDate(0, 0, 1)
+		for ; next < len(events) && events[next].OccurredAt.Before(dayEnd); next++ {
+			e := events[next]
+			if task := e.Apply(state[e.TaskID]); task != nil {
+				state[e.TaskID] = task
+			} else {
+				delete(state, e.TaskID)
+			}
+		}
+		counts := make(map[TaskStatus]int)
+		for _, t := range state {
+			if t.ProjectID == projectID && !t.Draft {
+				counts[t.Status]++
+			}
+		}
+		points = append(points, FlowPoint{Date: day, Counts: counts})
+	}
+	return points
+}
+
+// FlowStatuses returns the statuses seen in flow points in board order:
+// the built-in statuses first, with awaiting review before completed,
+// then custom statuses by name. Charts stack the bands in this order.
+func FlowStatuses(flow []FlowPoint) []TaskStatus {
+	seen := make(map[TaskStatus]bool)
+	for _, f := range flow {
+		for status := range f.Counts {
+			seen[status] = true
+		}
+	}
+	order := []TaskStatus{
+		TaskStatusPending,
+		TaskStatusInProgress,
+		TaskStatusBlocked,
+		TaskStatusAwaitingReview,
+		TaskStatusCompleted,
+		TaskStatusCancelled,
+	}
+	statuses := make([]TaskStatus, 0, len(seen))
+	for _, status := range order {
+		if seen[status] {
+			statuses = append(statuses, status)
+			delete(seen, status)
+		}
+	}
+	custom := make([]TaskStatus, 0, len(seen))
+	for status := range seen {
+		custom = append(custom, status)
+	}
+	sort.Slice(custom, func(i, j int) bool { return custom[i] < custom[j] })
+	return append(statuses, custom...)
+}
@@ -0,0 +1,174 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// maxCalendarDays bounds how far calendar arithmetic searches for
+// working time, guarding against calendars with no working hours.
+const maxCalendarDays = 3660
+
+// ErrInvalidCalendar is returned when a business calendar is malformed.
+var ErrInvalidCalendar = errors.New("invalid business calendar")
+
+// holidayLayout is the date format of BusinessCalendar.Holidays.
+const holidayLayout = "2006-01-02"
+
+// BusinessCalendar describes the working hours used for time-based
+// calculations, such as SLA timers, due dates and cycle times, that
+// should not count nights, weekends or holidays.
+//
+// Holidays are dates in YYYY-MM-DD form, interpreted in Timezone.
+type BusinessCalendar struct {
+	Timezone  string         `json:"timezone"`
+	StartHour int            `json:"start_hour"`
+	EndHour   int            `json:"end_hour"`
+	Days      []time.Weekday `json:"days"`
+	Holidays  []string       `json:"holidays,omitempty"`
+}
+
+// DefaultBusinessCalendar returns a Monday to Friday, 9:00 to 17:00 UTC calendar.
+func DefaultBusinessCalendar() *BusinessCalendar {
+	return &BusinessCalendar{
+		Timezone:  "UTC",
+		StartHour: 9,
+		EndHour:   17,
+		Days:      []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	}
+}
+
+// Validate checks that the calendar has a known timezone, at least one
+// working day and a non-empty working window.
+func (c *BusinessCalendar) Validate() error {
+	if _, err := time.LoadLocation(c.Timezone); err != nil || c.Timezone == "" {
+		return ErrInvalidTimezone
+	}
+	if len(c.Days) == 0 || c.StartHour < 0 || c.EndHour > 24 || c.StartHour >= c.EndHour {
+		return ErrInvalidCalendar
+	}
+	for _, d := range c.Days {
+		if d < time.Sunday || d > time.Saturday {
+			return ErrInvalidCalendar
+		}
+	}
+	for _, h := range c.Holidays {
+		if _, err := time.Parse(holidayLayout, h); err != nil {
+			return ErrInvalidCalendar
+		}
+	}
+	return nil
+}
+
+// IsWorkingDay reports whether the day containing t, in the calendar's
+// timezone, is a working day that is not a holiday.
+func (c *BusinessCalendar) IsWorkingDay(t time.Time) bool {
+	_, _, working := c.workingWindow(t.In(c.location()))
+	return working
+}
+
+// location returns the calendar's timezone, defaulting to UTC.
+func (c *BusinessCalendar) location() *time.Location {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// workingWindow returns the working interval on the day containing t,
+// and whether that day is a working day.
+func (c *BusinessCalendar) workingWindow(t time.Time) (time.Time, time.Time, bool) {
+	working := false
+	for _, d := range c.Days {
+		if d == t.Weekday() {
+			working = true
+			break
+		}
+	}
+	if working {
+		date := t.Format(holidayLayout)
+		for _, h := range c.Holidays {
+			if h == date {
+				working = false
+				break
+			}
+		}
+	}
+	y, m, d := t.Date()
+	start := time.Date(y, m, d, c.StartHour, 0, 0, 0, t.Location())
+	end := time.Date(y, m, d, c.EndHour, 0, 0, 0, t.Location())
+	return start, end, working
+}
+
+// WorkingTime returns the working time elapsed between from and to.
+func (c *BusinessCalendar) WorkingTime(from, to time.Time) time.Duration {
+	loc := c.location()
+	from, to = from.In(loc), to.In(loc)
+
+	var total time.Duration
+	for day, i := from, 0; day.Before(to) && i < maxCalendarDays; i++ {
+		start, end, working := c.workingWindow(day)
+		if working {
+			if from.After(start) {
+				start = from
+			}
+			if to.Before(end) {
+				end = to
+			}
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+		y, m, d := day.Date()
+		day = time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+	}
+	return total
+}
+
+// AddWorkingTime returns the time at which d of working time will have
+// elapsed after from.
+func (c *BusinessCalendar) AddWorkingTime(from time.Time, d time.Duration) time.Time {
+	loc := c.location()
+	at := from.In(loc)
+
+	for i := 0; i < maxCalendarDays; i++ {
+		start, end, working := c.workingWindow(at)
+		if working && at.Before(end) {
+			if at.Before(start) {
+				at = start
+			}
+			remaining := end.Sub(at)
+			if d <= remaining {
+				return at.Add(d)
+			}
+			d -= remaining
+		}
+		y, m, day := at.Date()
+		at = time.Date(y, m, day+1, 0, 0, 0, 0, loc)
+	}
+	return at
+}
+
+// AddWorkingDays returns the end of the working day n working days
+// after the day containing from. With n of zero it is the end of the
+// current working day, or of the next one if from is not a working day
+// or is past its working hours.
+func (c *BusinessCalendar) AddWorkingDays(from time.Time, n int) time.Time {
+	loc := c.location()
+	day := from.In(loc)
+
+	for i := 0; i < maxCalendarDays; i++ {
+		_, end, working := c.workingWindow(day)
+		if working && day.Before(end) {
+			if n == 0 {
+				return end
+			}
+			n--
+		}
+		y, m, d := day.Date()
+		day = time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+	}
+	return day
+}
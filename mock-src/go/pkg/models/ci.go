@@ -0,0 +1,147 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// CIProvider identifies a continuous integration service.
+type CIProvider string
+
+const (
+	// CIProviderGitHub is GitHub Actions.
+	CIProviderGitHub CIProvider = "github"
+	// CIProviderGitLab is GitLab CI.
+	CIProviderGitLab CIProvider = "gitlab"
+)
+
+// CIOutcome is the result of a finished pipeline run.
+type CIOutcome string
+
+const (
+	// CIOutcomeFailed means the run failed.
+	CIOutcomeFailed CIOutcome = "failed"
+	// CIOutcomePassed means the run succeeded.
+	CIOutcomePassed CIOutcome = "passed"
+	// CIOutcomeOther covers runs that are unfinished, cancelled or skipped.
+	CIOutcomeOther CIOutcome = "other"
+)
+
+// ErrInvalidCIEvent is returned when a CI webhook payload is malformed.
+var ErrInvalidCIEvent = errors.New("invalid CI event")
+
+// PipelineEvent is a pipeline run reported by a CI provider.
+type PipelineEvent struct {
+	Provider   CIProvider
+	Repository string
+	Pipeline   string
+	Branch     string
+	RunID      int64
+	URL        string
+	Outcome    CIOutcome
+}
+
+// Key identifies the pipeline and branch a run belongs to; failures of
+// the same pipeline on the same branch share one task.
+func (e *PipelineEvent) Key() string {
+	return fmt.Sprintf("%s/%s/%s@%s", e.Provider, e.Repository, e.Pipeline, e.Branch)
+}
+
+// Title returns the title of a task tracking the pipeline's failure.
+func (e *PipelineEvent) Title() string {
+	return fmt.Sprintf("CI failing: %s on %s (%s)", e.Pipeline, e.Branch, e.Repository)
+}
+
+// ParseGitHubWorkflowRun parses a GitHub Actions workflow_run event.
+//
+// Only completed runs have an outcome other than CIOutcomeOther.
+func ParseGitHubWorkflowRun(body []byte) (*PipelineEvent, error) {
+	var payload struct {
+		Action      string `json:"action"`
+		WorkflowRun struct {
+			ID         int64  `json:"id"`
+			Name       string `json:"name"`
+			HeadBranch string `json:"head_branch"`
+			HTMLURL    string `json:"html_url"`
+			Conclusion string `json:"conclusion"`
+		} `json:"workflow_run"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCIEvent, err)
+	}
+	run := payload.WorkflowRun
+	if run.ID == 0 || run.HeadBranch == "" || payload.Repository.FullName == "" {
+		return nil, fmt.Errorf("%w: missing workflow run", ErrInvalidCIEvent)
+	}
+
+	outcome := CIOutcomeOther
+	if payload.Action == "completed" {
+		switch run.Conclusion {
+		case "failure", "timed_out", "startup_failure":
+			outcome = CIOutcomeFailed
+		case "success":
+			outcome = CIOutcomePassed
+		}
+	}
+	return &PipelineEvent{
+		Provider:   CIProviderGitHub,
+		Repository: payload.Repository.FullName,
+		Pipeline:   run.Name,
+		Branch:     run.HeadBranch,
+		RunID:      run.ID,
+		URL:        run.HTMLURL,
+		Outcome:    outcome,
+	}, nil
+}
+
+// ParseGitLabPipeline parses a GitLab pipeline hook event. GitLab does
+// not name pipelines, so runs are keyed by the project's default
+// pipeline for the ref.
+func ParseGitLabPipeline(body []byte) (*PipelineEvent, error) {
+	var payload struct {
+		ObjectKind       string `json:"object_kind"`
+		ObjectAttributes struct {
+			ID     int64  `json:"id"`
+			Ref    string `json:"ref"`
+			Status string `json:"status"`
+			URL    string `json:"url"`
+		} `json:"object_attributes"`
+		Project struct {
+			PathWithNamespace string `json:"path_with_namespace"`
+			WebURL            string `json:"web_url"`
+		} `json:"project"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCIEvent, err)
+	}
+	attrs := payload.ObjectAttributes
+	if payload.ObjectKind != "pipeline" || attrs.ID == 0 || attrs.Ref == "" || payload.Project.PathWithNamespace == "" {
+		return nil, fmt.Errorf("%w: missing pipeline", ErrInvalidCIEvent)
+	}
+
+	outcome := CIOutcomeOther
+	switch attrs.Status {
+	case "failed":
+		outcome = CIOutcomeFailed
+	case "success":
+		outcome = CIOutcomePassed
+	}
+	url := attrs.URL
+	if url == "" && payload.Project.WebURL != "" {
+		url = fmt.Sprintf("%s/-/pipelines/%d", payload.Project.WebURL, attrs.ID)
+	}
+	return &PipelineEvent{
+		Provider:   CIProviderGitLab,
+		Repository: payload.Project.PathWithNamespace,
+		Pipeline:   "pipeline",
+		Branch:     attrs.Ref,
+		RunID:      attrs.ID,
+		URL:        url,
+		Outcome:    outcome,
+	}, nil
+}
@@ -0,0 +1,93 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var mentionRegex = regexp.MustCompile(`(?:^|[^\w@])@([a-zA-Z][a-zA-Z0-9_]{2,29})\b`)
+
+// ErrEmptyComment is returned when a comment body is blank.
+var ErrEmptyComment = errors.New("comment body is required")
+
+// Comment represents a comment left on a task.
+type Comment struct {
+	ID        string    `json:"id"`
+	TaskID    TaskID    `json:"task_id"`
+	AuthorID  UserID    `json:"author_id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewComment creates a new comment by an author on a task.
+//
+// The body is sanitized with SanitizeComment. Returns an error if it is
+// blank, too long or contains an embedded script.
+func NewComment(taskID TaskID, authorID UserID, body string) (*Comment, error) {
+	body, err := SanitizeComment(body)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(body) == "" {
+		return nil, ErrEmptyComment
+	}
+
+	now := time.Now()
+	return &Comment{
+		ID:        uuid.New().String(),
+		TaskID:    taskID,
+		AuthorID:  authorID,
+		Body:      body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// EntityID returns the comment's ID.
+func (c *Comment) EntityID() string {
+	return c.ID
+}
+
+// Clone returns a deep copy of the comment.
+func (c *Comment) Clone() *Comment {
+	clone := *c
+	return &clone
+}
+
+// ReplaceMention rewrites @mentions of username, matched regardless of
+// case, to mention replacement instead. It reports whether the body
+// changed.
+func (c *Comment) ReplaceMention(username, replacement string) bool {
+	changed := false
+	c.Body = mentionRegex.ReplaceAllStringFunc(c.Body, func(m string) string {
+		i := strings.LastIndex(m, "@")
+		if !strings.EqualFold(m[i+1:], username) {
+			return m
+		}
+		changed = true
+		return m[:i+1] + replacement
+	})
+	return changed
+}
+
+// Mentions returns the distinct usernames @mentioned in the body, in
+// order of first appearance.
+func (c *Comment) Mentions() []string {
+	matches := mentionRegex.FindAllStringSubmatch(c.Body, -1)
+	seen := make(map[string]bool, len(matches))
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		name := strings.ToLower(m[1])
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
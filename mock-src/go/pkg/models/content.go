@@ -0,0 +1,95 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var scriptRegex = regexp.MustCompile(`(?i)<\s*/?\s*script\b|<[^>]*\son[a-z]+\s*=|javascript\s*:|vbscript\s*:|data\s*:\s*text/html`)
+
+// ErrContentTooLarge is returned when a text field exceeds its maximum length.
+var ErrContentTooLarge = errors.New("content too large")
+
+// ErrUnsafeContent is returned when a text field contains an embedded script.
+var ErrUnsafeContent = errors.New("content contains embedded script")
+
+// ContentLimits holds the maximum lengths, in characters, of user-supplied
+// text fields. A zero limit disables the check for that field.
+type ContentLimits struct {
+	MaxTitleLength       int `json:"max_title_length"`
+	MaxDescriptionLength int `json:"max_description_length"`
+	MaxCommentLength     int `json:"max_comment_length"`
+}
+
+// DefaultContentLimits returns the limits used unless configured otherwise.
+func DefaultContentLimits() ContentLimits {
+	return ContentLimits{
+		MaxTitleLength:       200,
+		MaxDescriptionLength: 20000,
+		MaxCommentLength:     10000,
+	}
+}
+
+// contentLimits are the limits applied by the Sanitize* functions.
+var contentLimits = DefaultContentLimits()
+
+// SetContentLimits replaces the limits applied by the Sanitize* functions.
+//
+// It is intended to be called once at startup, before serving requests.
+func SetContentLimits(limits ContentLimits) {
+	contentLimits = limits
+}
+
+// SanitizeTitle cleans a task title and checks it against the limits.
+//
+// Titles are single-line, so newlines and tabs are replaced by spaces.
+func SanitizeTitle(title string) (string, error) {
+	title = strings.Join(strings.Fields(stripControl(title, false)), " ")
+	return title, checkContent("title", title, contentLimits.MaxTitleLength)
+}
+
+// SanitizeDescription cleans a task description and checks it against the limits.
+func SanitizeDescription(description string) (string, error) {
+	description = stripControl(description, true)
+	return description, checkContent("description", description, contentLimits.MaxDescriptionLength)
+}
+
+// SanitizeComment cleans a comment body and checks it against the limits.
+func SanitizeComment(body string) (string, error) {
+	body = stripControl(body, true)
+	return body, checkContent("comment", body, contentLimits.MaxCommentLength)
+}
+
+// stripControl removes control characters and invalid UTF-8 from s,
+// keeping newlines and tabs if multiline is set.
+func stripControl(s string, multiline bool) string {
+	s = strings.ToValidUTF8(strings.ReplaceAll(s, "\r\n", "\n"), "")
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			if multiline {
+				return r
+			}
+			return ' '
+		}
+		if unicode.IsControl(r) || r == '\u2028' || r == '\u2029' || r == '\ufeff' {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// checkContent validates the length and safety of a sanitized field.
+func checkContent(field, value string, max int) error {
+	if max > 0 && utf8.RuneCountInString(value) > max {
+		return fmt.Errorf("%w: %s exceeds %d characters", ErrContentTooLarge, field, max)
+	}
+	if scriptRegex.MatchString(value) {
+		return fmt.Errorf("%w: %s", ErrUnsafeContent, field)
+	}
+	return nil
+}
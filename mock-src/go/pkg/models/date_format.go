@@ -0,0 +1,110 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// DateFormat is a user's preferred way of writing dates. The empty
+// format follows the user's locale.
+type DateFormat string
+
+const (
+	// DateFormatLocale writes dates the way the user's locale does.
+	DateFormatLocale DateFormat = ""
+	// DateFormatISO writes dates as 2006-01-31.
+	DateFormatISO DateFormat = "iso"
+	// DateFormatMonthFirst writes dates as 01/31/2006.
+	DateFormatMonthFirst DateFormat = "mdy"
+	// DateFormatDayFirst writes dates as 31/01/2006.
+	DateFormatDayFirst DateFormat = "dmy"
+)
+
+// dateLayouts maps explicit date formats to time layouts.
+var dateLayouts = map[DateFormat]string{
+	DateFormatISO:        "2006-01-02",
+	DateFormatMonthFirst: "01/02/2006",
+	DateFormatDayFirst:   "02/01/2006",
+}
+
+// localeDateLayouts maps locales and languages to their customary date
+// layouts. Locales not listed use ISO dates.
+var localeDateLayouts = map[string]string{
+	"en-US": "01/02/2006",
+	"en":    "02/01/2006",
+	"de":    "02.01.2006",
+	"fr":    "02/01/2006",
+	"es":    "02/01/2006",
+	"it":    "02/01/2006",
+	"pt":    "02/01/2006",
+	"nl":    "02-01-2006",
+	"ja":    "2006/01/02",
+	"zh":    "2006/01/02",
+	"ko":    "2006. 01. 02.",
+}
+
+// Valid reports whether the format is one of the known formats.
+func (f DateFormat) Valid() bool {
+	_, ok := dateLayouts[f]
+	return ok || f == DateFormatLocale
+}
+
+// DateFormatter writes dates and times for one user, in their time zone
+// and preferred format. Exporters producing text for people, such as
+// CSV files, digests or calendar feeds, format dates with it so they
+// read the same everywhere.
+type DateFormatter struct {
+	loc        *time.Location
+	dateLayout string
+	timeLayout string
+}
+
+// NewDateFormatter creates a formatter for a user's preferences.
+func NewDateFormatter(prefs UserPreferences) *DateFormatter {
+	layout, ok := dateLayouts[prefs.DateFormat]
+	if !ok {
+		layout = localeDateLayout(prefs.Locale)
+	}
+	timeLayout := "15:04"
+	if prefs.Locale == "en-US" {
+		timeLayout = "3:04 PM"
+	}
+	return &DateFormatter{loc: prefs.Location(), dateLayout: layout, timeLayout: timeLayout}
+}
+
+// localeDateLayout returns the customary date layout of a locale,
+// falling back to its language and then to ISO dates.
+func localeDateLayout(locale string) string {
+	if layout, ok := localeDateLayouts[locale]; ok {
+		return layout
+	}
+	lang, _, _ := strings.Cut(locale, "-")
+	if layout, ok := localeDateLayouts[lang]; ok {
+		return layout
+	}
+	return dateLayouts[DateFormatISO]
+}
+
+// Location returns the time zone dates are written in.
+func (f *DateFormatter) Location() *time.Location {
+	return f.loc
+}
+
+// Date writes the calendar date of t in the user's time zone.
+func (f *DateFormatter) Date(t time.Time) string {
+	return t.In(f.loc).Format(f.dateLayout)
+}
+
+// DateTime writes the date and time of t in the user's time zone.
+func (f *DateFormatter) DateTime(t time.Time) string {
+	return t.In(f.loc).Format(f.dateLayout + " " + f.timeLayout)
+}
+
+// OptionalDate writes *t as Date does, or "" if t is nil.
+func (f *DateFormatter) OptionalDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return f.Date(*t)
+}
@@ -0,0 +1,93 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+var (
+	demoFirstNames = []string{
+		"Ada", "Ben", "Chloe", "Dev", "Elena", "Felix", "Grace", "Hugo",
+		"Iris", "Jonah", "Kira", "Leo", "Maya", "Nico", "Olive", "Priya",
+		"Quinn", "Rosa", "Sam", "Tara", "Uma", "Victor", "Wren", "Yusuf",
+	}
+	demoLastNames = []string{
+		"Archer", "Brooks", "Castillo", "Dalton", "Ellis", "Fischer", "Garcia", "Hayes",
+		"Ito", "Jensen", "Khan", "Larsen", "Moreno", "Nakamura", "Okafor", "Patel",
+		"Quintero", "Reyes", "Silva", "Thompson", "Underwood", "Vance", "Weber", "Zhang",
+	}
+	demoWords = []string{
+		"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit",
+		"sed", "do", "eiusmod", "tempor", "incididunt", "ut", "labore", "et",
+		"dolore", "magna", "aliqua", "enim", "ad", "minim", "veniam", "quis",
+		"nostrud", "exercitation", "ullamco", "laboris", "nisi", "aliquip", "ex", "ea",
+	}
+)
+
+// DemoFaker replaces personal and free-text data with fake values, so
+// demos and screenshots can be made from real datasets.
+//
+// Fakes are deterministic: the same input always yields the same fake
+// under the same salt, so a person keeps one fake name throughout a
+// dataset. The salt keeps fakes from being reversed by hashing guesses.
+type DemoFaker struct {
+	salt []byte
+}
+
+// NewDemoFaker creates a faker with the given salt.
+func NewDemoFaker(salt []byte) *DemoFaker {
+	return &DemoFaker{salt: salt}
+}
+
+// seed returns a keyed hash of a value.
+func (f *DemoFaker) seed(kind, value string) uint64 {
+	mac := hmac.New(sha256.New, f.salt)
+	mac.Write([]byte(kind + "\x00" + value))
+	return binary.BigEndian.Uint64(mac.Sum(nil))
+}
+
+// Name returns a fake full name for value. Empty values stay empty.
+func (f *DemoFaker) Name(value string) string {
+	if value == "" {
+		return ""
+	}
+	s := f.seed("name", value)
+	first := demoFirstNames[s%uint64(len(demoFirstNames))]
+	last := demoLastNames[(s/uint64(len(demoFirstNames)))%uint64(len(demoLastNames))]
+	return first + " " + last
+}
+
+// Username returns a fake username for value, matching its fake name.
+func (f *DemoFaker) Username(value string) string {
+	if value == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s_%03d", strings.ToLower(strings.ReplaceAll(f.Name(value), " ", "_")), f.seed("username", value)%1000)
+}
+
+// Email returns a fake email address for value on example.com.
+func (f *DemoFaker) Email(value string) string {
+	if value == "" {
+		return ""
+	}
+	return f.Username(strings.ToLower(value)) + "@example.com"
+}
+
+// Text returns placeholder text with about as many words as value.
+func (f *DemoFaker) Text(value string) string {
+	n := len(strings.Fields(value))
+	if n == 0 {
+		return ""
+	}
+	s := f.seed("text", value)
+	words := make([]string, n)
+	for i := range words {
+		words[i] = demoWords[(s+uint64(i)*7)%uint64(len(demoWords))]
+	}
+	words[0] = strings.ToUpper(words[0][:1]) + words[0][1:]
+	return strings.Join(words, " ") + "."
+}
@@ -0,0 +1,126 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// DuplicateMode selects what happens when a new task looks like a
+// duplicate of an open task.
+type DuplicateMode string
+
+const (
+	// DuplicateModeOff disables duplicate checking.
+	DuplicateModeOff DuplicateMode = "off"
+	// DuplicateModeWarn creates the task and reports the candidates.
+	DuplicateModeWarn DuplicateMode = "warn"
+	// DuplicateModeBlock rejects the task.
+	DuplicateModeBlock DuplicateMode = "block"
+)
+
+// DefaultDuplicateThreshold is the title similarity at or above which
+// tasks are considered duplicates when no threshold is configured.
+const DefaultDuplicateThreshold = 0.85
+
+// ErrInvalidDuplicateCheck is returned when a duplicate check is
+// malformed.
+var ErrInvalidDuplicateCheck = errors.New("invalid duplicate check")
+
+// DuplicateCheck configures duplicate detection for a project.
+//
+// A zero Threshold uses DefaultDuplicateThreshold.
+type DuplicateCheck struct {
+	Mode      DuplicateMode `json:"mode"`
+	Threshold float64       `json:"threshold,omitempty"`
+}
+
+// Validate checks the mode and that the threshold is within [0, 1].
+func (c *DuplicateCheck) Validate() error {
+	switch c.Mode {
+	case DuplicateModeOff, DuplicateModeWarn, DuplicateModeBlock:
+	default:
+		return ErrInvalidDuplicateCheck
+	}
+	if c.Threshold < 0 || c.Threshold > 1 {
+		return ErrInvalidDuplicateCheck
+	}
+	return nil
+}
+
+// Enabled reports whether the check is configured to run.
+func (c *DuplicateCheck) Enabled() bool {
+	return c != nil && c.Mode != "" && c.Mode != DuplicateModeOff
+}
+
+// FindDuplicates returns the IDs of the open tasks in the project of
+// task whose titles are similar enough to its title.
+func (c *DuplicateCheck) FindDuplicates(task *Task, tasks []*Task) []TaskID {
+	threshold := c.Threshold
+	if threshold == 0 {
+		threshold = DefaultDuplicateThreshold
+	}
+	title := NormalizeTitle(task.Title)
+
+	ids := make([]TaskID, 0)
+	for _, t := range tasks {
+		if t.ID == task.ID || t.ProjectID != task.ProjectID || !t.IsOpen() {
+			continue
+		}
+		if titleSimilarity(title, NormalizeTitle(t.Title)) >= threshold {
+			ids = append(ids, t.ID)
+		}
+	}
+	return ids
+}
+
+// NormalizeTitle lowercases a title, drops punctuation and collapses
+// whitespace, for comparing titles.
+func NormalizeTitle(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// titleSimilarity returns the similarity of two normalized titles in
+// [0, 1], based on their edit distance relative to the longer title.
+func titleSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein returns the edit distance between two rune slices.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
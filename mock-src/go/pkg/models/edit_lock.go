@@ -0,0 +1,51 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultEditLockTTL is the lease length when none is requested.
+	DefaultEditLockTTL = 5 * time.Minute
+	// MaxEditLockTTL caps the lease length of an edit lock.
+	MaxEditLockTTL = time.Hour
+)
+
+// ErrInvalidLockTTL is returned for a lease length outside (0, MaxEditLockTTL].
+var ErrInvalidLockTTL = errors.New("lock ttl must be positive and at most one hour")
+
+// EditLock is a soft lock recording that a user is editing a task.
+//
+// The lock is advisory: it does not prevent updates, but lets editors
+// see each other before an optimistic concurrency conflict occurs. It
+// lapses at ExpiresAt unless renewed.
+type EditLock struct {
+	TaskID     TaskID    `json:"task_id"`
+	UserID     UserID    `json:"user_id"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// NewEditLock creates a lock on a task for a user, leased for ttl from now.
+//
+// Returns ErrInvalidLockTTL if ttl is not positive or exceeds
+// MaxEditLockTTL.
+func NewEditLock(taskID TaskID, userID UserID, ttl time.Duration) (*EditLock, error) {
+	if ttl <= 0 || ttl > MaxEditLockTTL {
+		return nil, ErrInvalidLockTTL
+	}
+	now := time.Now()
+	return &EditLock{
+		TaskID:     taskID,
+		UserID:     userID,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(ttl),
+	}, nil
+}
+
+// IsExpiredAt reports whether the lease has lapsed at the given time.
+func (l *EditLock) IsExpiredAt(at time.Time) bool {
+	return !at.Before(l.ExpiresAt)
+}
@@ -0,0 +1,118 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EscalationCondition identifies what triggers an escalation rule.
+type EscalationCondition string
+
+const (
+	// EscalateWhenOverdue triggers once a task is overdue by the threshold.
+	EscalateWhenOverdue EscalationCondition = "overdue"
+	// EscalateWhenBlocked triggers once a task has been blocked for the threshold.
+	EscalateWhenBlocked EscalationCondition = "blocked"
+	// EscalateWhenDueSoon triggers once a task's due date is within the threshold.
+	EscalateWhenDueSoon EscalationCondition = "due_soon"
+)
+
+// EscalationAction identifies what an escalation rule does when triggered.
+type EscalationAction string
+
+const (
+	// EscalationRaisePriority raises the task's priority to the rule's target.
+	EscalationRaisePriority EscalationAction = "raise_priority"
+	// EscalationNotify notifies the rule's recipients and the task's assignee.
+	EscalationNotify EscalationAction = "notify"
+)
+
+// ErrInvalidEscalationRule is returned when an escalation rule is malformed.
+var ErrInvalidEscalationRule = errors.New("invalid escalation rule")
+
+// EscalationRule escalates open tasks in a project that meet a condition.
+//
+// ThresholdHours is the overdue or blocked duration, or for due_soon the
+// time remaining before the due date.
+type EscalationRule struct {
+	ID             string              `json:"id"`
+	ProjectID      ProjectID           `json:"project_id"`
+	Name           string              `json:"name"`
+	Condition      EscalationCondition `json:"condition"`
+	ThresholdHours int                 `json:"threshold_hours"`
+	Action         EscalationAction    `json:"action"`
+	TargetPriority TaskPriority        `json:"target_priority,omitempty"`
+	NotifyUserIDs  []UserID            `json:"notify_user_ids,omitempty"`
+	Enabled        bool                `json:"enabled"`
+	CreatedAt      time.Time           `json:"created_at"`
+}
+
+// NewEscalationRule creates an enabled rule for a project.
+func NewEscalationRule(projectID ProjectID, name string, condition EscalationCondition, thresholdHours int, action EscalationAction) *EscalationRule {
+	return &EscalationRule{
+		ID:             uuid.New().String(),
+		ProjectID:      projectID,
+		Name:           name,
+		Condition:      condition,
+		ThresholdHours: thresholdHours,
+		Action:         action,
+		Enabled:        true,
+		CreatedAt:      time.Now(),
+	}
+}
+
+// EntityID returns the rule's ID.
+func (r *EscalationRule) EntityID() string {
+	return r.ID
+}
+
+// Clone returns a deep copy of the escalation rule.
+func (r *EscalationRule) Clone() *EscalationRule {
+	c := *r
+	c.NotifyUserIDs = slices.Clone(r.NotifyUserIDs)
+	return &c
+}
+
+// Validate checks that the rule's condition, threshold and action are well formed.
+func (r *EscalationRule) Validate() error {
+	if r.ProjectID == "" || r.Name == "" || r.ThresholdHours < 0 {
+		return ErrInvalidEscalationRule
+	}
+	switch r.Condition {
+	case EscalateWhenOverdue, EscalateWhenBlocked, EscalateWhenDueSoon:
+	default:
+		return ErrInvalidEscalationRule
+	}
+	switch r.Action {
+	case EscalationRaisePriority:
+		if r.TargetPriority <= 0 {
+			return ErrInvalidEscalationRule
+		}
+	case EscalationNotify:
+	default:
+		return ErrInvalidEscalationRule
+	}
+	return nil
+}
+
+// Matches reports whether an open task in the rule's project meets the
+// rule's condition at the given time.
+func (r *EscalationRule) Matches(task *Task, at time.Time) bool {
+	if !r.Enabled || task.ProjectID != r.ProjectID || !task.IsOpen() {
+		return false
+	}
+	threshold := time.Duration(r.ThresholdHours) * time.Hour
+	switch r.Condition {
+	case EscalateWhenOverdue:
+		return task.DueDate != nil && at.After(*task.DueDate) && at.Sub(*task.DueDate) >= threshold
+	case EscalateWhenBlocked:
+		return task.Status == TaskStatusBlocked && at.Sub(task.StatusSince()) >= threshold
+	case EscalateWhenDueSoon:
+		return task.DueDate != nil && !at.After(*task.DueDate) && task.DueDate.Sub(at) <= threshold
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errors returned by estimation round changes that the round's state
+// does not allow.
+var (
+	// ErrRoundRevealed is returned when estimates change after reveal.
+	ErrRoundRevealed = errors.New("estimates have already been revealed")
+	// ErrRoundNotRevealed is returned when a consensus is recorded
+	// before the estimates are revealed.
+	ErrRoundNotRevealed = errors.New("estimates have not been revealed")
+	// ErrRoundClosed is returned when a closed round is changed.
+	ErrRoundClosed = errors.New("estimation round is closed")
+	// ErrNoEstimates is returned when revealing a round nobody estimated.
+	ErrNoEstimates = errors.New("no estimates have been submitted")
+	// ErrInvalidEstimate is returned for negative or non-finite estimates.
+	ErrInvalidEstimate = errors.New("estimate must be a non-negative number")
+)
+
+// EstimationRound is a planning poker round on a task: members submit
+// estimates that stay hidden until the round is revealed, and the
+// agreed estimate is then recorded on the task, closing the round.
+type EstimationRound struct {
+	ID         string             `json:"id"`
+	TaskID     TaskID             `json:"task_id"`
+	OpenedBy   UserID             `json:"opened_by"`
+	Estimates  map[UserID]float64 `json:"estimates"`
+	Revealed   bool               `json:"revealed"`
+	Consensus  *float64           `json:"consensus,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+	RevealedAt *time.Time         `json:"revealed_at,omitempty"`
+	ClosedAt   *time.Time         `json:"closed_at,omitempty"`
+}
+
+// NewEstimationRound opens a round on a task.
+func NewEstimationRound(taskID TaskID, openedBy UserID) *EstimationRound {
+	return &EstimationRound{
+		ID:        uuid.New().String(),
+		TaskID:    taskID,
+		OpenedBy:  openedBy,
+		Estimates: make(map[UserID]float64),
+		CreatedAt: time.Now(),
+	}
+}
+
+// EntityID returns the round's ID.
+func (r *EstimationRound) EntityID() string {
+	return r.ID
+}
+
+// Clone returns a copy of the round that shares no state with it.
+func (r *EstimationRound) Clone() *EstimationRound {
+	c := *r
+	c.Estimates = make(map[UserID]float64, len(r.Estimates))
+	for user, value := range r.Estimates {
+		c.Estimates[user] = value
+	}
+	return &c
+}
+
+// IsClosed checks if the round's consensus has been recorded.
+func (r *EstimationRound) IsClosed() bool {
+	return r.ClosedAt != nil
+}
+
+// Submit records or replaces a member's estimate.
+func (r *EstimationRound) Submit(userID UserID, value float64) error {
+	switch {
+	case r.IsClosed():
+		return ErrRoundClosed
+	case r.Revealed:
+		return ErrRoundRevealed
+	case value < 0 || math.IsNaN(value) || math.IsInf(value, 0):
+		return ErrInvalidEstimate
+	}
+	r.Estimates[userID] = value
+	return nil
+}
+
+// Reveal makes the estimates visible.
+func (r *EstimationRound) Reveal() error {
+	switch {
+	case r.IsClosed():
+		return ErrRoundClosed
+	case r.Revealed:
+		return ErrRoundRevealed
+	case len(r.Estimates) == 0:
+		return ErrNoEstimates
+	}
+	now := time.Now()
+	r.Revealed = true
+	r.RevealedAt = &now
+	return nil
+}
+
+// Close records the agreed estimate, ending the round.
+func (r *EstimationRound) Close(consensus float64) error {
+	switch {
+	case r.IsClosed():
+		return ErrRoundClosed
+	case !r.Revealed:
+		return ErrRoundNotRevealed
+	case consensus < 0 || math.IsNaN(consensus) || math.IsInf(consensus, 0):
+		return ErrInvalidEstimate
+	}
+	now := time.Now()
+	r.Consensus = &consensus
+	r.ClosedAt = &now
+	return nil
+}
+
+// EstimateSummary describes the spread of a round's estimates.
+type EstimateSummary struct {
+	Count  int     `json:"count"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	// Agreed is true when every estimate is the same.
+	Agreed bool `json:"agreed"`
+}
+
+// Summary summarizes the estimates, or returns nil if there are none.
+func (r *EstimationRound) Summary() *EstimateSummary {
+	if len(r.Estimates) == 0 {
+		return nil
+	}
+	values := make([]float64, 0, len(r.Estimates))
+	sum := 0.0
+	for _, v := range r.Estimates {
+		values = append(values, v)
+		sum += v
+	}
+	sort.Float64s(values)
+	n := len(values)
+	median := values[n/2]
+	if n%2 == 0 {
+		median = (values[n/2-1] + values[n/2]) / 2
+	}
+	return &EstimateSummary{
+		Count:  n,
+		Min:    values[0],
+		Max:    values[n-1],
+		Mean:   sum / float64(n),
+		Median: median,
+		Agreed: values[0] == values[n-1],
+	}
+}
@@ -0,0 +1,492 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxExprLength caps the source length of an expression.
+const maxExprLength = 1024
+
+// maxExprDepth caps the nesting depth of an expression.
+const maxExprDepth = 32
+
+// ErrInvalidExpr is returned when an expression fails to parse.
+var ErrInvalidExpr = errors.New("invalid expression")
+
+// Expr is a compiled boolean expression over named values, such as
+//
+//	status == "blocked" and priority >= 4 and not ("escalated" in tags)
+//
+// The language has string, number and boolean literals, lists in
+// brackets, identifiers, the comparisons == != < <= > >=, membership
+// with in, and and/or/not (also written && || !). It has no loops,
+// calls or assignments, so evaluation always terminates and cannot
+// affect anything outside the expression.
+type Expr struct {
+	src    string
+	root   exprNode
+	idents []string
+}
+
+// ParseExpr compiles an expression.
+//
+// Returns an error wrapping ErrInvalidExpr if src is malformed, longer
+// than 1024 bytes or nested too deeply.
+func ParseExpr(src string) (*Expr, error) {
+	if len(src) > maxExprLength {
+		return nil, fmt.Errorf("%w: longer than %d bytes", ErrInvalidExpr, maxExprLength)
+	}
+	tokens, err := lexExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens, seen: make(map[string]bool)}
+	root, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, p.errorf("unexpected %q", p.peek().text)
+	}
+	return &Expr{src: src, root: root, idents: p.idents}, nil
+}
+
+// String returns the expression's source.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Idents returns the identifiers the expression refers to, in order of
+// first use.
+func (e *Expr) Idents() []string {
+	return append([]string(nil), e.idents...)
+}
+
+// Eval evaluates the expression against env, whose values must be
+// strings, float64s, bools or []strings.
+//
+// Returns an error for an unknown identifier, a non-boolean result or
+// operands of the wrong type.
+func (e *Expr) Eval(env map[string]any) (bool, error) {
+	v, err := e.root.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression yields %s, not a boolean", exprTypeName(v))
+	}
+	return b, nil
+}
+
+// tokenKind classifies a lexical token.
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+)
+
+// exprToken is one lexical token and its offset in the source.
+type exprToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// lexExpr splits src into tokens, normalizing and/or/not to && || !.
+func lexExpr(src string) ([]exprToken, error) {
+	var tokens []exprToken
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			j := i + 1
+			var b strings.Builder
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' && j+1 < len(src) {
+					j++
+				}
+				b.WriteByte(src[j])
+				j++
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("%w: unterminated string at %d", ErrInvalidExpr, i)
+			}
+			tokens = append(tokens, exprToken{tokString, b.String(), i})
+			i = j + 1
+		case c >= '0' && c <= '9' || c == '.':
+			j := i
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, exprToken{tokNumber, src[i:j], i})
+			i = j
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i
+			for j < len(src) && (src[j] == '_' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			word := src[i:j]
+			switch strings.ToLower(word) {
+			case "and":
+				tokens = append(tokens, exprToken{tokOp, "&&", i})
+			case "or":
+				tokens = append(tokens, exprToken{tokOp, "||", i})
+			case "not":
+				tokens = append(tokens, exprToken{tokOp, "!", i})
+			case "in":
+				tokens = append(tokens, exprToken{tokOp, "in", i})
+			default:
+				tokens = append(tokens, exprToken{tokIdent, word, i})
+			}
+			i = j
+		default:
+			op := ""
+			for _, candidate := range []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", "[", "]", ","} {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("%w: unexpected %q at %d", ErrInvalidExpr, c, i)
+			}
+			tokens = append(tokens, exprToken{tokOp, op, i})
+			i += len(op)
+		}
+	}
+	return append(tokens, exprToken{tokEOF, "end of expression", len(src)}), nil
+}
+
+// exprParser is a recursive descent parser over tokens.
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+	idents []string
+	seen   map[string]bool
+}
+
+// peek returns the next token without consuming it.
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+// next consumes and returns the next token.
+func (p *exprParser) next() exprToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is the operator op.
+func (p *exprParser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokOp && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// errorf returns a parse error at the next token.
+func (p *exprParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s at %d", ErrInvalidExpr, fmt.Sprintf(format, args...), p.peek().pos)
+}
+
+// parseOr parses a || b || ...
+func (p *exprParser) parseOr(depth int) (exprNode, error) {
+	if depth > maxExprDepth {
+		return nil, p.errorf("nested too deeply")
+	}
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseAnd parses a && b && ...
+func (p *exprParser) parseAnd(depth int) (exprNode, error) {
+	left, err := p.parseNot(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseNot(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseNot parses !a.
+func (p *exprParser) parseNot(depth int) (exprNode, error) {
+	if p.accept("!") {
+		if depth+1 > maxExprDepth {
+			return nil, p.errorf("nested too deeply")
+		}
+		operand, err := p.parseNot(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseComparison(depth)
+}
+
+// parseComparison parses a single, optional comparison.
+func (p *exprParser) parseComparison(depth int) (exprNode, error) {
+	left, err := p.parsePrimary(depth)
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t.kind != tokOp {
+		return left, nil
+	}
+	switch t.text {
+	case "==", "!=", "<", "<=", ">", ">=", "in":
+		p.next()
+		right, err := p.parsePrimary(depth)
+		if err != nil {
+			return nil, err
+		}
+		return &compareNode{op: t.text, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+// parsePrimary parses a literal, identifier, list or parenthesized expression.
+func (p *exprParser) parsePrimary(depth int) (exprNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString:
+		return literalNode{t.text}, nil
+	case tokNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad number %q at %d", ErrInvalidExpr, t.text, t.pos)
+		}
+		return literalNode{n}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return literalNode{true}, nil
+		case "false":
+			return literalNode{false}, nil
+		}
+		if !p.seen[t.text] {
+			p.seen[t.text] = true
+			p.idents = append(p.idents, t.text)
+		}
+		return identNode(t.text), nil
+	case tokOp:
+		switch t.text {
+		case "(":
+			inner, err := p.parseOr(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if !p.accept(")") {
+				return nil, p.errorf("expected )")
+			}
+			return inner, nil
+		case "[":
+			var items []string
+			for !p.accept("]") {
+				if len(items) > 0 && !p.accept(",") {
+					return nil, p.errorf("expected , or ]")
+				}
+				item := p.next()
+				if item.kind != tokString {
+					return nil, fmt.Errorf("%w: list items must be strings at %d", ErrInvalidExpr, item.pos)
+				}
+				items = append(items, item.text)
+			}
+			if items == nil {
+				items = []string{}
+			}
+			return literalNode{items}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unexpected %q at %d", ErrInvalidExpr, t.text, t.pos)
+}
+
+// exprNode is a node of a parsed expression.
+type exprNode interface {
+	eval(env map[string]any) (any, error)
+}
+
+// literalNode is a constant value.
+type literalNode struct{ value any }
+
+func (n literalNode) eval(map[string]any) (any, error) { return n.value, nil }
+
+// identNode looks up a value by name.
+type identNode string
+
+func (n identNode) eval(env map[string]any) (any, error) {
+	v, ok := env[string(n)]
+	if !ok {
+		return nil, fmt.Errorf("unknown identifier %q", string(n))
+	}
+	return v, nil
+}
+
+// notNode negates a boolean.
+type notNode struct{ operand exprNode }
+
+func (n *notNode) eval(env map[string]any) (any, error) {
+	b, err := evalBool(n.operand, env)
+	return !b, err
+}
+
+// logicalNode is a short-circuiting && or ||.
+type logicalNode struct {
+	or          bool
+	left, right exprNode
+}
+
+func (n *logicalNode) eval(env map[string]any) (any, error) {
+	left, err := evalBool(n.left, env)
+	if err != nil {
+		return nil, err
+	}
+	if left == n.or {
+		return left, nil
+	}
+	return evalBool(n.right, env)
+}
+
+// compareNode compares two values.
+type compareNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n *compareNode) eval(env map[string]any) (any, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.op == "in" {
+		list, ok := right.([]string)
+		s, isString := left.(string)
+		if !ok || !isString {
+			return nil, fmt.Errorf("in needs a string and a list, not %s and %s", exprTypeName(left), exprTypeName(right))
+		}
+		for _, item := range list {
+			if item == s {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			break
+		}
+		switch n.op {
+		case "==":
+			return l == r, nil
+		case "!=":
+			return l != r, nil
+		case "<":
+			return l < r, nil
+		case "<=":
+			return l <= r, nil
+		case ">":
+			return l > r, nil
+		case ">=":
+			return l >= r, nil
+		}
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			break
+		}
+		switch n.op {
+		case "==":
+			return l == r, nil
+		case "!=":
+			return l != r, nil
+		case "<":
+			return l < r, nil
+		case "<=":
+			return l <= r, nil
+		case ">":
+			return l > r, nil
+		case ">=":
+			return l >= r, nil
+		}
+	case bool:
+		r, ok := right.(bool)
+		if !ok {
+			break
+		}
+		switch n.op {
+		case "==":
+			return l == r, nil
+		case "!=":
+			return l != r, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot compare %s %s %s", exprTypeName(left), n.op, exprTypeName(right))
+}
+
+// evalBool evaluates n, which must yield a boolean.
+func evalBool(n exprNode, env map[string]any) (bool, error) {
+	v, err := n.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expected a boolean, not %s", exprTypeName(v))
+	}
+	return b, nil
+}
+
+// exprTypeName names the type of an expression value for errors.
+func exprTypeName(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []string:
+		return "list"
+	}
+	return fmt.Sprintf("%T", v)
+}
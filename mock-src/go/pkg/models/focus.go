@@ -0,0 +1,101 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import "time"
+
+// FocusList holds a user's pinned and snoozed tasks for their daily
+// worklist.
+//
+// Snoozed maps task IDs to the time the snooze ends.
+type FocusList struct {
+	UserID  UserID               `json:"user_id"`
+	Pinned  []TaskID             `json:"pinned"`
+	Snoozed map[TaskID]time.Time `json:"snoozed"`
+}
+
+// NewFocusList creates an empty focus list for a user.
+func NewFocusList(userID UserID) *FocusList {
+	return &FocusList{
+		UserID:  userID,
+		Pinned:  make([]TaskID, 0),
+		Snoozed: make(map[TaskID]time.Time),
+	}
+}
+
+// Pin pins a task to the top of the worklist and ends any snooze on it.
+//
+// Returns true if the task was not already pinned.
+func (f *FocusList) Pin(taskID TaskID) bool {
+	delete(f.Snoozed, taskID)
+	if f.IsPinned(taskID) {
+		return false
+	}
+	f.Pinned = append(f.Pinned, taskID)
+	return true
+}
+
+// Unpin removes a task from the pinned tasks.
+//
+// Returns true if the task was pinned.
+func (f *FocusList) Unpin(taskID TaskID) bool {
+	for i, id := range f.Pinned {
+		if id == taskID {
+			f.Pinned = append(f.Pinned[:i], f.Pinned[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// IsPinned checks if a task is pinned.
+func (f *FocusList) IsPinned(taskID TaskID) bool {
+	for _, id := range f.Pinned {
+		if id == taskID {
+			return true
+		}
+	}
+	return false
+}
+
+// Snooze hides a task from the worklist until the given time, unpinning
+// it.
+func (f *FocusList) Snooze(taskID TaskID, until time.Time) {
+	f.Unpin(taskID)
+	f.Snoozed[taskID] = until
+}
+
+// Unsnooze ends the snooze on a task.
+//
+// Returns true if the task was snoozed.
+func (f *FocusList) Unsnooze(taskID TaskID) bool {
+	if _, ok := f.Snoozed[taskID]; !ok {
+		return false
+	}
+	delete(f.Snoozed, taskID)
+	return true
+}
+
+// IsSnoozedAt checks if a task was snoozed at the given time.
+func (f *FocusList) IsSnoozedAt(taskID TaskID, at time.Time) bool {
+	until, ok := f.Snoozed[taskID]
+	return ok && at.Before(until)
+}
+
+// Prune drops snoozes that ended before the given time.
+func (f *FocusList) Prune(at time.Time) {
+	for id, until := range f.Snoozed {
+		if !at.Before(until) {
+			delete(f.Snoozed, id)
+		}
+	}
+}
+
+// Clone returns a deep copy of the focus list.
+func (f *FocusList) Clone() *FocusList {
+	c := NewFocusList(f.UserID)
+	c.Pinned = append(c.Pinned, f.Pinned...)
+	for id, until := range f.Snoozed {
+		c.Snoozed[id] = until
+	}
+	return c
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// FuzzParseQuickAdd checks that free-text task input never panics and
+// that every parsed result has a title made of the input's words.
+func FuzzParseQuickAdd(f *testing.F) {
+	f.Add("Fix login bug #backend !high due friday +web")
+	f.Add("Ship it due in 3 weeks")
+	f.Add("due next")
+	f.Add("due in 99999999999999999999 days")
+	f.Add("x due 2026-02-30 !5 # + !")
+
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	f.Fuzz(func(t *testing.T, text string) {
+		q, err := ParseQuickAdd(text, now, time.UTC)
+		if err != nil {
+			return
+		}
+		if q.Title == "" {
+			t.Fatalf("%q: parsed with an empty title", text)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// taskReferencePrefix starts the short reference of a task.
+const taskReferencePrefix = "TT-"
+
+// taskReferenceRegex matches task references in commit messages and
+// pull request titles, optionally preceded by a closing keyword: a
+// short reference such as TT-1a2b3c4d, a full task ID, or a task key
+// such as API-142. Task keys must be uppercase, so words such as
+// "utf-8" are not taken for them.
+var taskReferenceRegex = regexp.MustCompile(`(?i)\b(?:(fix(?:es|ed)?|close[sd]?|resolve[sd]?)\s*:?\s+)?(?:(TT-[0-9a-f]{8}|[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})|(?-i:([A-Z][A-Z0-9]{1,9}-[1-9][0-9]*)))\b`)
+
+// Reference returns the task's short reference, the first eight hex
+// digits of its ID after TT-, for use in commit messages.
+func (t *Task) Reference() string {
+	id := string(t.ID)
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	return taskReferencePrefix + id
+}
+
+// TaskReference is a mention of a task in text. Prefix is the lowercase
+// ID or ID prefix it names, or Key the task key; Closes is set when a
+// closing keyword such as "fixes" precedes it.
+type TaskReference struct {
+	Prefix string
+	Key    string
+	Closes bool
+}
+
+// ParseTaskReferences returns the task references in text, in order of
+// first mention. A task mentioned both with and without a closing
+// keyword is closed.
+func ParseTaskReferences(text string) []TaskReference {
+	var refs []TaskReference
+	index := make(map[string]int)
+	for _, m := range taskReferenceRegex.FindAllStringSubmatch(text, -1) {
+		ref := TaskReference{Key: m[3], Closes: m[1] != ""}
+		if ref.Key == "" {
+			ref.Prefix = strings.TrimPrefix(strings.ToLower(m[2]), strings.ToLower(taskReferencePrefix))
+		}
+		id := ref.Prefix + ref.Key
+		if i, ok := index[id]; ok {
+			refs[i].Closes = refs[i].Closes || ref.Closes
+			continue
+		}
+		index[id] = len(refs)
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// Matches reports whether the reference names a task.
+func (r TaskReference) Matches(task *Task) bool {
+	if r.Key != "" {
+		return task.Key == r.Key
+	}
+	return strings.HasPrefix(string(task.ID), r.Prefix)
+}
+
+// TaskLinkKind identifies what a task link points to.
+type TaskLinkKind string
+
+const (
+	// TaskLinkCommit links a commit.
+	TaskLinkCommit TaskLinkKind = "commit"
+	// TaskLinkPullRequest links a pull or merge request.
+	TaskLinkPullRequest TaskLinkKind = "pull_request"
+)
+
+// TaskLink connects a task to a commit or pull request that mentions it.
+type TaskLink struct {
+	ID         string       `json:"id"`
+	TaskID     TaskID       `json:"task_id"`
+	Kind       TaskLinkKind `json:"kind"`
+	URL        string       `json:"url"`
+	Title      string       `json:"title"`
+	Repository string       `json:"repository"`
+	Author     string       `json:"author,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// NewTaskLink creates a link from a task to a commit or pull request.
+func NewTaskLink(taskID TaskID, kind TaskLinkKind, url, title, repository string) *TaskLink {
+	return &TaskLink{
+		ID:         uuid.New().String(),
+		TaskID:     taskID,
+		Kind:       kind,
+		URL:        url,
+		Title:      title,
+		Repository: repository,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// EntityID returns the link's ID.
+func (l *TaskLink) EntityID() string {
+	return l.ID
+}
+
+// Clone returns a deep copy of the task link.
+func (l *TaskLink) Clone() *TaskLink {
+	c := *l
+	return &c
+}
@@ -0,0 +1,187 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrDependencyCycle is returned when task dependencies form a cycle.
+var ErrDependencyCycle = errors.New("task dependencies form a cycle")
+
+// GraphNode is a task in a dependency graph.
+//
+// Weight is the remaining work used for the critical path: the task's
+// estimate, 1 if it has none, or 0 once it is closed.
+type GraphNode struct {
+	ID       TaskID     `json:"id"`
+	Title    string     `json:"title"`
+	Status   TaskStatus `json:"status"`
+	Weight   float64    `json:"weight"`
+	Critical bool       `json:"critical"`
+}
+
+// GraphEdge is a dependency: From must be finished before To.
+type GraphEdge struct {
+	From TaskID `json:"from"`
+	To   TaskID `json:"to"`
+}
+
+// DependencyGraph is the dependency DAG of a set of tasks.
+type DependencyGraph struct {
+	Nodes              []*GraphNode `json:"nodes"`
+	Edges              []*GraphEdge `json:"edges"`
+	CriticalPath       []TaskID     `json:"critical_path"`
+	CriticalPathLength float64      `json:"critical_path_length"`
+}
+
+// BuildDependencyGraph builds the dependency graph of tasks from their
+// depends_on relations, ignoring relations to tasks outside the set.
+//
+// Returns ErrDependencyCycle if the dependencies are not acyclic.
+func BuildDependencyGraph(tasks []*Task, relations []*TaskRelation) (*DependencyGraph, error) {
+	g := &DependencyGraph{
+		Nodes:        make([]*GraphNode, 0, len(tasks)),
+		Edges:        make([]*GraphEdge, 0),
+		CriticalPath: make([]TaskID, 0),
+	}
+
+	nodes := make(map[TaskID]*GraphNode, len(tasks))
+	for _, task := range tasks {
+		weight := task.Estimate
+		if weight <= 0 {
+			weight = 1
+		}
+		if !task.IsOpen() {
+			weight = 0
+		}
+		node := &GraphNode{ID: task.ID, Title: task.Title, Status: task.Status, Weight: weight}
+		nodes[task.ID] = node
+		g.Nodes = append(g.Nodes, node)
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+
+	seen := make(map[GraphEdge]bool)
+	for _, rel := range relations {
+		if rel.Type != RelationDependsOn || nodes[rel.SourceID] == nil || nodes[rel.TargetID] == nil {
+			continue
+		}
+		edge := GraphEdge{From: rel.TargetID, To: rel.SourceID}
+		if !seen[edge] {
+			seen[edge] = true
+			g.Edges = append(g.Edges, &edge)
+		}
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+
+	order, err := g.topologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+	g.computeCriticalPath(order, nodes)
+	return g, nil
+}
+
+// topologicalOrder returns the node IDs with every dependency before its dependents.
+func (g *DependencyGraph) topologicalOrder() ([]TaskID, error) {
+	indegree := make(map[TaskID]int, len(g.Nodes))
+	next := make(map[TaskID][]TaskID, len(g.Nodes))
+	for _, e := range g.Edges {
+		indegree[e.To]++
+		next[e.From] = append(next[e.From], e.To)
+	}
+
+	queue := make([]TaskID, 0)
+	for _, n := range g.Nodes {
+		if indegree[n.ID] == 0 {
+			queue = append(queue, n.ID)
+		}
+	}
+	order := make([]TaskID, 0, len(g.Nodes))
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		order = append(order, id)
+		for _, to := range next[id] {
+			indegree[to]--
+			if indegree[to] == 0 {
+				queue = append(queue, to)
+			}
+		}
+	}
+	if len(order) != len(g.Nodes) {
+		return nil, ErrDependencyCycle
+	}
+	return order, nil
+}
+
+// computeCriticalPath finds the heaviest dependency chain and marks its nodes.
+func (g *DependencyGraph) computeCriticalPath(order []TaskID, nodes map[TaskID]*GraphNode) {
+	prev := make(map[TaskID][]TaskID, len(g.Nodes))
+	for _, e := range g.Edges {
+		prev[e.To] = append(prev[e.To], e.From)
+	}
+
+	finish := make(map[TaskID]float64, len(order))
+	via := make(map[TaskID]TaskID, len(order))
+	var end TaskID
+	for _, id := range order {
+		best := 0.0
+		for _, p := range prev[id] {
+			if via[id] == "" || finish[p] > best {
+				best, via[id] = finish[p], p
+			}
+		}
+		finish[id] = best + nodes[id].Weight
+		if end == "" || finish[id] > finish[end] {
+			end = id
+		}
+	}
+	if end == "" {
+		return
+	}
+
+	g.CriticalPathLength = finish[end]
+	for id := end; id != ""; id = via[id] {
+		g.CriticalPath = append([]TaskID{id}, g.CriticalPath...)
+		nodes[id].Critical = true
+	}
+}
+
+// DOT renders the graph in Graphviz DOT format, highlighting the critical path.
+func (g *DependencyGraph) DOT() string {
+	critical := make(map[GraphEdge]bool, len(g.CriticalPath))
+	for i := 1; i < len(g.CriticalPath); i++ {
+		critical[GraphEdge{From: g.CriticalPath[i-1], To: g.CriticalPath[i]}] = true
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n\trankdir=LR;\n")
+	for _, n := range g.Nodes {
+		attrs := fmt.Sprintf("label=%s", strconv.Quote(n.Title))
+		if n.Critical {
+			attrs += ", color=red"
+		}
+		if n.Status == TaskStatusCompleted || n.Status == TaskStatusCancelled {
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(&b, "\t%s [%s];\n", strconv.Quote(string(n.ID)), attrs)
+	}
+	for _, e := range g.Edges {
+		attrs := ""
+		if critical[*e] {
+			attrs = " [color=red]"
+		}
+		fmt.Fprintf(&b, "\t%s -> %s%s;\n", strconv.Quote(string(e.From)), strconv.Quote(string(e.To)), attrs)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,92 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidID is returned when an ID is not a UUID in canonical
+// lowercase form.
+var ErrInvalidID = errors.New("id must be a lowercase UUID")
+
+// TaskID identifies a task.
+//
+// The ID types are distinct so that passing, say, a project ID where a
+// task ID is expected fails to compile. An empty ID means unset and is
+// accepted when decoding; any other value must be a canonical UUID.
+type TaskID string
+
+// ProjectID identifies a project.
+type ProjectID string
+
+// UserID identifies a user.
+type UserID string
+
+// NewTaskID returns a fresh random task ID.
+func NewTaskID() TaskID {
+	return TaskID(uuid.New().String())
+}
+
+// NewProjectID returns a fresh random project ID.
+func NewProjectID() ProjectID {
+	return ProjectID(uuid.New().String())
+}
+
+// NewUserID returns a fresh random user ID.
+func NewUserID() UserID {
+	return UserID(uuid.New().String())
+}
+
+// String returns the ID as a plain string.
+func (id TaskID) String() string { return string(id) }
+
+// String returns the ID as a plain string.
+func (id ProjectID) String() string { return string(id) }
+
+// String returns the ID as a plain string.
+func (id UserID) String() string { return string(id) }
+
+// Validate returns ErrInvalidID unless the ID is a canonical UUID.
+func (id TaskID) Validate() error { return validateID(string(id)) }
+
+// Validate returns ErrInvalidID unless the ID is a canonical UUID.
+func (id ProjectID) Validate() error { return validateID(string(id)) }
+
+// Validate returns ErrInvalidID unless the ID is a canonical UUID.
+func (id UserID) Validate() error { return validateID(string(id)) }
+
+// UnmarshalText decodes an ID, rejecting malformed non-empty values.
+func (id *TaskID) UnmarshalText(text []byte) error {
+	return unmarshalID(text, (*string)(id))
+}
+
+// UnmarshalText decodes an ID, rejecting malformed non-empty values.
+func (id *ProjectID) UnmarshalText(text []byte) error {
+	return unmarshalID(text, (*string)(id))
+}
+
+// UnmarshalText decodes an ID, rejecting malformed non-empty values.
+func (id *UserID) UnmarshalText(text []byte) error {
+	return unmarshalID(text, (*string)(id))
+}
+
+// validateID returns ErrInvalidID unless id is a canonical UUID.
+func validateID(id string) error {
+	if !ValidateID(id) {
+		return ErrInvalidID
+	}
+	return nil
+}
+
+// unmarshalID stores text in dst if it is empty or a canonical UUID.
+func unmarshalID(text []byte, dst *string) error {
+	if len(text) > 0 {
+		if err := validateID(string(text)); err != nil {
+			return err
+		}
+	}
+	*dst = string(text)
+	return nil
+}
@@ -0,0 +1,118 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AsanaImporter imports an Asana project's JSON export, as produced by
+// "Export/Print > JSON", whose tasks carry their subtasks inline.
+type AsanaImporter struct{}
+
+// Source returns "asana".
+func (AsanaImporter) Source() string {
+	return "asana"
+}
+
+// asanaTask is the part of an exported Asana task that is imported.
+type asanaTask struct {
+	GID       string `json:"gid"`
+	Name      string `json:"name"`
+	Notes     string `json:"notes"`
+	Completed bool   `json:"completed"`
+	DueOn     string `json:"due_on"`
+	DueAt     string `json:"due_at"`
+	Assignee  *struct {
+		Email string `json:"email"`
+	} `json:"assignee"`
+	Memberships []struct {
+		Project struct {
+			GID  string `json:"gid"`
+			Name string `json:"name"`
+		} `json:"project"`
+		Section *struct {
+			Name string `json:"name"`
+		} `json:"section"`
+	} `json:"memberships"`
+	Tags []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+	Subtasks []asanaTask `json:"subtasks"`
+}
+
+// Parse reads an Asana export. Tasks are grouped into projects by their
+// first membership; subtasks follow their parent's project and section.
+// Asana has no priorities, so tasks get the default one.
+func (AsanaImporter) Parse(data []byte) (*ImportPlan, error) {
+	var export struct {
+		Data []asanaTask `json:"data"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+	}
+
+	plan := &ImportPlan{Source: "asana"}
+	projects := make(map[string]*ImportProject)
+	sectionSeen := make(map[string]bool)
+	var add func(t asanaTask, project *ImportProject, section, parentID string) error
+	add = func(t asanaTask, project *ImportProject, section, parentID string) error {
+		task := &ImportTask{
+			ExternalID:  t.GID,
+			ParentID:    parentID,
+			Title:       t.Name,
+			Description: t.Notes,
+			Section:     section,
+			Completed:   t.Completed,
+			Priority:    TaskPriorityMedium,
+		}
+		if t.Assignee != nil {
+			task.AssigneeEmail = t.Assignee.Email
+		}
+		for _, tag := range t.Tags {
+			task.Tags = append(task.Tags, tag.Name)
+		}
+		if due := t.DueAt; due != "" || t.DueOn != "" {
+			if due == "" {
+				due = t.DueOn
+			}
+			parsed, err := parseImportDate(due)
+			if err != nil {
+				return fmt.Errorf("%w: task %s: %w", ErrInvalidImport, t.GID, err)
+			}
+			task.DueDate = &parsed
+		}
+		project.Tasks = append(project.Tasks, task)
+		for _, sub := range t.Subtasks {
+			if err := add(sub, project, section, t.GID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, t := range export.Data {
+		if len(t.Memberships) == 0 {
+			return nil, fmt.Errorf("%w: task %s belongs to no project", ErrInvalidImport, t.GID)
+		}
+		m := t.Memberships[0]
+		project, ok := projects[m.Project.GID]
+		if !ok {
+			project = &ImportProject{ExternalID: m.Project.GID, Name: m.Project.Name, Tasks: make([]*ImportTask, 0)}
+			projects[m.Project.GID] = project
+			plan.Projects = append(plan.Projects, project)
+		}
+		section := ""
+		if m.Section != nil {
+			section = m.Section.Name
+			if key := project.ExternalID + "\x00" + section; !sectionSeen[key] {
+				sectionSeen[key] = true
+				project.Sections = append(project.Sections, section)
+			}
+		}
+		if err := add(t, project, section, ""); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
@@ -0,0 +1,118 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TodoistImporter imports Todoist data as returned by its Sync API with
+// all resource types, or saved from it as a backup.
+type TodoistImporter struct{}
+
+// Source returns "todoist".
+func (TodoistImporter) Source() string {
+	return "todoist"
+}
+
+// todoistExport is the part of a Todoist sync response that is imported.
+type todoistExport struct {
+	Projects []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"projects"`
+	Sections []struct {
+		ID        string `json:"id"`
+		ProjectID string `json:"project_id"`
+		Name      string `json:"name"`
+	} `json:"sections"`
+	Items []struct {
+		ID             string   `json:"id"`
+		ProjectID      string   `json:"project_id"`
+		SectionID      string   `json:"section_id"`
+		ParentID       string   `json:"parent_id"`
+		Content        string   `json:"content"`
+		Description    string   `json:"description"`
+		Checked        bool     `json:"checked"`
+		Priority       int      `json:"priority"`
+		ResponsibleUID string   `json:"responsible_uid"`
+		Labels         []string `json:"labels"`
+		Due            *struct {
+			Date string `json:"date"`
+		} `json:"due"`
+	} `json:"items"`
+	Collaborators []struct {
+		ID    string `json:"id"`
+		Email string `json:"email"`
+	} `json:"collaborators"`
+}
+
+// Parse reads a Todoist export. Todoist priorities run from 1 (normal)
+// to 4 (urgent) and map onto the default priority scheme.
+func (TodoistImporter) Parse(data []byte) (*ImportPlan, error) {
+	var export todoistExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+	}
+
+	emails := make(map[string]string, len(export.Collaborators))
+	for _, c := range export.Collaborators {
+		emails[c.ID] = c.Email
+	}
+	sections := make(map[string]string, len(export.Sections))
+	plan := &ImportPlan{Source: "todoist"}
+	projects := make(map[string]*ImportProject, len(export.Projects))
+	for _, p := range export.Projects {
+		project := &ImportProject{ExternalID: p.ID, Name: p.Name, Tasks: make([]*ImportTask, 0)}
+		projects[p.ID] = project
+		plan.Projects = append(plan.Projects, project)
+	}
+	for _, s := range export.Sections {
+		sections[s.ID] = s.Name
+		if project, ok := projects[s.ProjectID]; ok {
+			project.Sections = append(project.Sections, s.Name)
+		}
+	}
+
+	for _, item := range export.Items {
+		project, ok := projects[item.ProjectID]
+		if !ok {
+			return nil, fmt.Errorf("%w: item %s belongs to unknown project %s", ErrInvalidImport, item.ID, item.ProjectID)
+		}
+		task := &ImportTask{
+			ExternalID:    item.ID,
+			ParentID:      item.ParentID,
+			Title:         item.Content,
+			Description:   item.Description,
+			Section:       sections[item.SectionID],
+			AssigneeEmail: emails[item.ResponsibleUID],
+			Completed:     item.Checked,
+			Priority:      TaskPriority(item.Priority),
+			Tags:          item.Labels,
+		}
+		if task.Priority < TaskPriorityLow || task.Priority > TaskPriorityCritical {
+			task.Priority = TaskPriorityLow
+		}
+		if item.Due != nil && item.Due.Date != "" {
+			due, err := parseImportDate(item.Due.Date)
+			if err != nil {
+				return nil, fmt.Errorf("%w: item %s: %w", ErrInvalidImport, item.ID, err)
+			}
+			task.DueDate = &due
+		}
+		project.Tasks = append(project.Tasks, task)
+	}
+	return plan, nil
+}
+
+// parseImportDate parses a date, or a date and time, as exported by
+// other trackers.
+func parseImportDate(s string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", time.RFC3339, "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
@@ -0,0 +1,160 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidImport is returned when an export file cannot be parsed.
+var ErrInvalidImport = errors.New("invalid import file")
+
+// Importer parses another tracker's export into an ImportPlan. Sources
+// only translate their format; validation, dry runs and applying the
+// plan are shared by every source.
+type Importer interface {
+	// Source names the tracker, such as "todoist".
+	Source() string
+	// Parse reads an export. Returns an error wrapping ErrInvalidImport
+	// for malformed files.
+	Parse(data []byte) (*ImportPlan, error)
+}
+
+// ImportPlan is the source-neutral content of an export: projects with
+// their sections and tasks.
+type ImportPlan struct {
+	Source   string           `json:"source"`
+	Projects []*ImportProject `json:"projects"`
+}
+
+// ImportProject is a project to import. Sections become project labels.
+type ImportProject struct {
+	ExternalID  string        `json:"external_id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Sections    []string      `json:"sections,omitempty"`
+	Tasks       []*ImportTask `json:"tasks"`
+}
+
+// ImportTask is a task to import. ParentID is the external ID of the
+// task it is a subtask of, in the same project. AssigneeEmail is matched
+// against existing users.
+type ImportTask struct {
+	ExternalID    string       `json:"external_id"`
+	ParentID      string       `json:"parent_id,omitempty"`
+	Title         string       `json:"title"`
+	Description   string       `json:"description,omitempty"`
+	Section       string       `json:"section,omitempty"`
+	AssigneeEmail string       `json:"assignee_email,omitempty"`
+	Completed     bool         `json:"completed,omitempty"`
+	DueDate       *time.Time   `json:"due_date,omitempty"`
+	Priority      TaskPriority `json:"priority,omitempty"`
+	Tags          []string     `json:"tags,omitempty"`
+}
+
+// ImportIssue is a problem found in a plan, locating the project and
+// task by external ID.
+type ImportIssue struct {
+	Project string `json:"project,omitempty"`
+	Task    string `json:"task,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error formats the issue as text.
+func (i ImportIssue) Error() string {
+	switch {
+	case i.Task != "":
+		return fmt.Sprintf("project %s, task %s: %s", i.Project, i.Task, i.Message)
+	case i.Project != "":
+		return fmt.Sprintf("project %s: %s", i.Project, i.Message)
+	}
+	return i.Message
+}
+
+// TaskCount returns the number of tasks and subtasks in the plan.
+func (p *ImportPlan) TaskCount() (tasks, subtasks int) {
+	for _, project := range p.Projects {
+		for _, t := range project.Tasks {
+			if t.ParentID != "" {
+				subtasks++
+			} else {
+				tasks++
+			}
+		}
+	}
+	return tasks, subtasks
+}
+
+// Validate returns the problems that would prevent the plan from being
+// imported: missing names and titles, titles and descriptions the
+// content checks reject, duplicate external IDs, unknown sections,
+// subtasks whose parent is missing, and parent cycles.
+func (p *ImportPlan) Validate() []ImportIssue {
+	var issues []ImportIssue
+	if len(p.Projects) == 0 {
+		issues = append(issues, ImportIssue{Message: "export contains no projects"})
+	}
+	projectIDs := make(map[string]bool, len(p.Projects))
+	for _, project := range p.Projects {
+		if strings.TrimSpace(project.Name) == "" {
+			issues = append(issues, ImportIssue{Project: project.ExternalID, Message: "project name is required"})
+		}
+		if projectIDs[project.ExternalID] {
+			issues = append(issues, ImportIssue{Project: project.ExternalID, Message: "duplicate project id"})
+		}
+		projectIDs[project.ExternalID] = true
+		issues = append(issues, project.validateTasks()...)
+	}
+	return issues
+}
+
+// validateTasks checks the tasks of one project.
+func (p *ImportProject) validateTasks() []ImportIssue {
+	var issues []ImportIssue
+	sections := make(map[string]bool, len(p.Sections))
+	for _, s := range p.Sections {
+		sections[s] = true
+	}
+	parents := make(map[string]string, len(p.Tasks))
+	for _, t := range p.Tasks {
+		issue := func(msg string) {
+			issues = append(issues, ImportIssue{Project: p.ExternalID, Task: t.ExternalID, Message: msg})
+		}
+		if strings.TrimSpace(t.Title) == "" {
+			issue("title is required")
+		} else if _, err := SanitizeTitle(t.Title); err != nil {
+			issue(err.Error())
+		}
+		if _, err := SanitizeDescription(t.Description); err != nil {
+			issue(err.Error())
+		}
+		if _, ok := parents[t.ExternalID]; ok {
+			issue("duplicate task id")
+		}
+		if t.Section != "" && !sections[t.Section] {
+			issue(fmt.Sprintf("unknown section %q", t.Section))
+		}
+		parents[t.ExternalID] = t.ParentID
+	}
+
+	for _, t := range p.Tasks {
+		if t.ParentID == "" {
+			continue
+		}
+		if _, ok := parents[t.ParentID]; !ok {
+			issues = append(issues, ImportIssue{Project: p.ExternalID, Task: t.ExternalID, Message: "parent task not found"})
+			continue
+		}
+		seen := map[string]bool{t.ExternalID: true}
+		for id := t.ParentID; id != ""; id = parents[id] {
+			if seen[id] {
+				issues = append(issues, ImportIssue{Project: p.ExternalID, Task: t.ExternalID, Message: "subtasks form a cycle"})
+				break
+			}
+			seen[id] = true
+		}
+	}
+	return issues
+}
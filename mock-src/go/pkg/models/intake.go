@@ -0,0 +1,72 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidIntakeForm is returned when an intake form has no project
+// or template.
+var ErrInvalidIntakeForm = errors.New("intake form project and template are required")
+
+// IntakeForm lets people without an account file tasks into a project.
+//
+// The form is reached by its unguessable Token; submissions are turned
+// into tasks with the form's template, whose title variables the
+// submitter fills in. Disabling a form, or rotating its token, revokes
+// the public link.
+type IntakeForm struct {
+	ID         string    `json:"id"`
+	Token      string    `json:"token"`
+	ProjectID  ProjectID `json:"project_id"`
+	TemplateID string    `json:"template_id"`
+	Enabled    bool      `json:"enabled"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewIntakeForm creates an enabled intake form with a fresh token.
+//
+// Returns ErrInvalidIntakeForm if the project or template is empty.
+func NewIntakeForm(projectID ProjectID, templateID string) (*IntakeForm, error) {
+	if projectID == "" || templateID == "" {
+		return nil, ErrInvalidIntakeForm
+	}
+	token, err := NewIntakeToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &IntakeForm{
+		ID:         uuid.New().String(),
+		Token:      token,
+		ProjectID:  projectID,
+		TemplateID: templateID,
+		Enabled:    true,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// EntityID returns the form's ID.
+func (f *IntakeForm) EntityID() string {
+	return f.ID
+}
+
+// Clone returns a deep copy of the intake form.
+func (f *IntakeForm) Clone() *IntakeForm {
+	c := *f
+	return &c
+}
+
+// NewIntakeToken returns a random URL-safe token for an intake form.
+func NewIntakeToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,74 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"net/netip"
+	"strings"
+)
+
+// ErrInvalidAllowlistEntry is returned for an allowlist entry that is
+// neither an IP address nor a CIDR range.
+var ErrInvalidAllowlistEntry = errors.New("allowlist entries must be IP addresses or CIDR ranges")
+
+// IPAllowlist is a set of IP addresses and CIDR ranges requests may
+// come from. An empty allowlist allows every address.
+type IPAllowlist struct {
+	Entries  []string `json:"entries"`
+	prefixes []netip.Prefix
+}
+
+// NewIPAllowlist parses allowlist entries. A bare address is treated as
+// a single-address range.
+//
+// Returns ErrInvalidAllowlistEntry if any entry cannot be parsed.
+func NewIPAllowlist(entries []string) (*IPAllowlist, error) {
+	list := &IPAllowlist{Entries: make([]string, 0, len(entries))}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		prefix, err := parseAllowlistEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		list.Entries = append(list.Entries, prefix.String())
+		list.prefixes = append(list.prefixes, prefix)
+	}
+	return list, nil
+}
+
+// parseAllowlistEntry parses an address or CIDR range into a prefix.
+func parseAllowlistEntry(entry string) (netip.Prefix, error) {
+	if strings.Contains(entry, "/") {
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return netip.Prefix{}, ErrInvalidAllowlistEntry
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(entry)
+	if err != nil {
+		return netip.Prefix{}, ErrInvalidAllowlistEntry
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// IsEmpty reports whether the allowlist has no entries.
+func (l *IPAllowlist) IsEmpty() bool {
+	return l == nil || len(l.prefixes) == 0
+}
+
+// Allows reports whether an address is allowed. An empty allowlist
+// allows every address.
+func (l *IPAllowlist) Allows(addr netip.Addr) bool {
+	if l.IsEmpty() {
+		return true
+	}
+	addr = addr.Unmap()
+	for _, prefix := range l.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
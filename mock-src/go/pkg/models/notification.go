@@ -0,0 +1,57 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationType identifies why a user was notified.
+type NotificationType string
+
+const (
+	// NotificationMention is sent to a user @mentioned in a comment.
+	NotificationMention NotificationType = "mention"
+	// NotificationComment is sent to watchers when a task is commented on.
+	NotificationComment NotificationType = "comment"
+	// NotificationEscalation is sent when an escalation rule fires for a task.
+	NotificationEscalation NotificationType = "escalation"
+	// NotificationReviewRequested is sent to a task's reviewer when the
+	// task is submitted for review.
+	NotificationReviewRequested NotificationType = "review_requested"
+	// NotificationAutomation is sent by an automation rule's notify action.
+	NotificationAutomation NotificationType = "automation"
+	// NotificationStale nudges an assignee about a task that has gone
+	// without updates.
+	NotificationStale NotificationType = "stale"
+	// NotificationProjectRule is sent by a notification rule in a
+	// project's settings.
+	NotificationProjectRule NotificationType = "project_rule"
+)
+
+// Notification is a message delivered to a single user about activity
+// on a task.
+type Notification struct {
+	ID        string           `json:"id"`
+	UserID    UserID           `json:"user_id"`
+	Type      NotificationType `json:"type"`
+	TaskID    TaskID           `json:"task_id"`
+	CommentID string           `json:"comment_id,omitempty"`
+	ActorID   UserID           `json:"actor_id,omitempty"`
+	Message   string           `json:"message,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	Read      bool             `json:"read"`
+}
+
+// NewNotification creates an unread notification for a user.
+func NewNotification(userID UserID, typ NotificationType, taskID TaskID, actorID UserID) *Notification {
+	return &Notification{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Type:      typ,
+		TaskID:    taskID,
+		ActorID:   actorID,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,166 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidObjective is returned when an objective or one of its key
+// results is malformed.
+var ErrInvalidObjective = errors.New("invalid objective")
+
+// KeyResult is a measurable outcome of an objective. Its progress comes
+// from the work linked to it: tasks directly, and every task of linked
+// projects.
+type KeyResult struct {
+	ID         string      `json:"id"`
+	Title      string      `json:"title"`
+	TaskIDs    []TaskID    `json:"task_ids"`
+	ProjectIDs []ProjectID `json:"project_ids"`
+}
+
+// NewKeyResult creates a key result with no linked work.
+func NewKeyResult(title string) KeyResult {
+	return KeyResult{
+		ID:         uuid.New().String(),
+		Title:      title,
+		TaskIDs:    make([]TaskID, 0),
+		ProjectIDs: make([]ProjectID, 0),
+	}
+}
+
+// Objective is a goal measured by its key results. Objectives form a
+// tree: ParentID, if set, is the objective this one contributes to.
+type Objective struct {
+	ID          string      `json:"id"`
+	ParentID    string      `json:"parent_id,omitempty"`
+	Title       string      `json:"title"`
+	Description string      `json:"description,omitempty"`
+	OwnerID     *UserID     `json:"owner_id,omitempty"`
+	Period      string      `json:"period,omitempty"`
+	KeyResults  []KeyResult `json:"key_results"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// NewObjective creates an objective with no key results.
+func NewObjective(title string) *Objective {
+	now := time.Now()
+	return &Objective{
+		ID:         uuid.New().String(),
+		Title:      title,
+		KeyResults: make([]KeyResult, 0),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// EntityID returns the objective's ID.
+func (o *Objective) EntityID() string {
+	return o.ID
+}
+
+// Validate checks that the objective and its key results have titles,
+// that key result IDs are unique and that no link is listed twice.
+func (o *Objective) Validate() error {
+	if strings.TrimSpace(o.Title) == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidObjective)
+	}
+	if o.ParentID == o.ID {
+		return fmt.Errorf("%w: an objective cannot be its own parent", ErrInvalidObjective)
+	}
+	ids := make(map[string]bool, len(o.KeyResults))
+	for _, kr := range o.KeyResults {
+		if kr.ID == "" || ids[kr.ID] {
+			return fmt.Errorf("%w: key result ids must be unique", ErrInvalidObjective)
+		}
+		ids[kr.ID] = true
+		if strings.TrimSpace(kr.Title) == "" {
+			return fmt.Errorf("%w: key result title is required", ErrInvalidObjective)
+		}
+		tasks := make(map[TaskID]bool, len(kr.TaskIDs))
+		for _, id := range kr.TaskIDs {
+			if tasks[id] {
+				return fmt.Errorf("%w: task %s is linked twice", ErrInvalidObjective, id)
+			}
+			tasks[id] = true
+		}
+		projects := make(map[ProjectID]bool, len(kr.ProjectIDs))
+		for _, id := range kr.ProjectIDs {
+			if projects[id] {
+				return fmt.Errorf("%w: project %s is linked twice", ErrInvalidObjective, id)
+			}
+			projects[id] = true
+		}
+	}
+	return nil
+}
+
+// KeyResult returns the key result with an ID, or nil.
+func (o *Objective) KeyResult(id string) *KeyResult {
+	for i := range o.KeyResults {
+		if o.KeyResults[i].ID == id {
+			return &o.KeyResults[i]
+		}
+	}
+	return nil
+}
+
+// Clone returns a deep copy of the objective.
+func (o *Objective) Clone() *Objective {
+	c := *o
+	c.KeyResults = make([]KeyResult, len(o.KeyResults))
+	for i, kr := range o.KeyResults {
+		kr.TaskIDs = append(make([]TaskID, 0, len(kr.TaskIDs)), kr.TaskIDs...)
+		kr.ProjectIDs = append(make([]ProjectID, 0, len(kr.ProjectIDs)), kr.ProjectIDs...)
+		c.KeyResults[i] = kr
+	}
+	if o.OwnerID != nil {
+		owner := *o.OwnerID
+		c.OwnerID = &owner
+	}
+	return &c
+}
+
+// KeyResultProgress is the completion of the work linked to a key result.
+//
+// Cancelled tasks and drafts are not counted. Progress is Completed over
+// Total, or zero when nothing is linked.
+type KeyResultProgress struct {
+	Total     int     `json:"total"`
+	Completed int     `json:"completed"`
+	Progress  float64 `json:"progress"`
+}
+
+// Progress computes the key result's progress over tasks. A task linked
+// both directly and through its project counts once.
+func (kr *KeyResult) Progress(tasks []*Task) KeyResultProgress {
+	linked := make(map[TaskID]bool, len(kr.TaskIDs))
+	for _, id := range kr.TaskIDs {
+		linked[id] = true
+	}
+	projects := make(map[ProjectID]bool, len(kr.ProjectIDs))
+	for _, id := range kr.ProjectIDs {
+		projects[id] = true
+	}
+
+	var p KeyResultProgress
+	for _, t := range tasks {
+		if t.Draft || t.Status == TaskStatusCancelled || !linked[t.ID] && !projects[t.ProjectID] {
+			continue
+		}
+		p.Total++
+		if t.Status == TaskStatusCompleted {
+			p.Completed++
+		}
+	}
+	if p.Total > 0 {
+		p.Progress = float64(p.Completed) / float64(p.Total)
+	}
+	return p
+}
@@ -0,0 +1,197 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync/atomic"
+)
+
+// PolicyEffect is the outcome a policy rule applies when it matches.
+type PolicyEffect string
+
+const (
+	// PolicyEffectAllow grants the action when the rule matches.
+	PolicyEffectAllow PolicyEffect = "allow"
+	// PolicyEffectDeny refuses the action when the rule matches.
+	//
+	// Deny rules always take precedence over allow rules.
+	PolicyEffectDeny PolicyEffect = "deny"
+)
+
+// PolicyWildcard matches any role, action, or resource type in a rule.
+const PolicyWildcard = "*"
+
+// ErrInvalidPolicyRule is returned when a policy rule is malformed.
+var ErrInvalidPolicyRule = errors.New("invalid policy rule")
+
+// Resource describes the object an action is performed on.
+//
+// Only the fields relevant to a rule need to be set; a nil resource
+// matches rules that are not scoped to a resource.
+type Resource struct {
+	Type      string    `json:"type"`
+	ID        string    `json:"id,omitempty"`
+	OwnerID   UserID    `json:"owner_id,omitempty"`
+	ProjectID ProjectID `json:"project_id,omitempty"`
+}
+
+// TaskResource returns the resource descriptor for a task.
+//
+// The assignee is treated as the owner of the task.
+func TaskResource(task *Task) *Resource {
+	res := &Resource{Type: "task", ID: string(task.ID), ProjectID: task.ProjectID}
+	if task.AssigneeID != nil {
+		res.OwnerID = *task.AssigneeID
+	}
+	return res
+}
+
+// PolicyRule grants or denies an action to a role.
+//
+// Empty ResourceType and ProjectID match any resource. When OwnOnly is
+// set the rule only matches resources owned by the acting user.
+type PolicyRule struct {
+	Role         UserRole     `json:"role"`
+	Action       string       `json:"action"`
+	ResourceType string       `json:"resource_type,omitempty"`
+	ProjectID    ProjectID    `json:"project_id,omitempty"`
+	OwnOnly      bool         `json:"own_only,omitempty"`
+	Effect       PolicyEffect `json:"effect"`
+}
+
+// Validate checks that the rule has a role, an action, and a known effect.
+func (r PolicyRule) Validate() error {
+	if r.Role == "" || r.Action == "" {
+		return ErrInvalidPolicyRule
+	}
+	if r.Effect != PolicyEffectAllow && r.Effect != PolicyEffectDeny {
+		return ErrInvalidPolicyRule
+	}
+	return nil
+}
+
+// matches reports whether the rule applies to the user, action, and resource.
+func (r PolicyRule) matches(user *User, action string, res *Resource) bool {
+	if r.Role != PolicyWildcard && r.Role != user.Role {
+		return false
+	}
+	if r.Action != PolicyWildcard && r.Action != action {
+		return false
+	}
+	if r.ResourceType != "" && r.ResourceType != PolicyWildcard {
+		if res == nil || res.Type != r.ResourceType {
+			return false
+		}
+	}
+	if r.ProjectID != "" && (res == nil || res.ProjectID != r.ProjectID) {
+		return false
+	}
+	if r.OwnOnly && (res == nil || res.OwnerID != user.ID) {
+		return false
+	}
+	return true
+}
+
+// PolicyDecision is the result of evaluating a policy.
+type PolicyDecision struct {
+	Allowed bool        `json:"allowed"`
+	Rule    *PolicyRule `json:"rule,omitempty"`
+}
+
+// Policy is an ordered set of permission rules.
+type Policy struct {
+	Rules []PolicyRule `json:"rules"`
+}
+
+// NewPolicy creates a policy from the given rules.
+//
+// Returns an error if any rule is invalid.
+func NewPolicy(rules ...PolicyRule) (*Policy, error) {
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &Policy{Rules: rules}, nil
+}
+
+// LoadPolicy reads a JSON policy document of the form {"rules": [...]}.
+//
+// Returns an error if the document cannot be decoded or a rule is invalid.
+func LoadPolicy(r io.Reader) (*Policy, error) {
+	var doc Policy
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	return NewPolicy(doc.Rules...)
+}
+
+// Evaluate decides whether the user may perform the action on the resource.
+//
+// A matching deny rule wins over any allow rule. If no rule matches the
+// action is denied.
+func (p *Policy) Evaluate(user *User, action string, res *Resource) PolicyDecision {
+	var decision PolicyDecision
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if !rule.matches(user, action, res) {
+			continue
+		}
+		if rule.Effect == PolicyEffectDeny {
+			return PolicyDecision{Allowed: false, Rule: rule}
+		}
+		if !decision.Allowed {
+			decision = PolicyDecision{Allowed: true, Rule: rule}
+		}
+	}
+	return decision
+}
+
+// Allows reports whether the user may perform the action on the resource.
+func (p *Policy) Allows(user *User, action string, res *Resource) bool {
+	return p.Evaluate(user, action, res).Allowed
+}
+
+// allow builds an allow rule for each of the given actions.
+func allow(role UserRole, actions ...string) []PolicyRule {
+	rules := make([]PolicyRule, len(actions))
+	for i, action := range actions {
+		rules[i] = PolicyRule{Role: role, Action: action, Effect: PolicyEffectAllow}
+	}
+	return rules
+}
+
+// DefaultPolicy returns the built-in role-based policy.
+//
+// Viewers can read; members can also write and comment; admins can
+// also manage; owners can do everything including delete.
+func DefaultPolicy() *Policy {
+	var rules []PolicyRule
+	rules = append(rules, allow(UserRoleViewer, "read")...)
+	rules = append(rules, allow(UserRoleMember, "read", "write", "comment")...)
+	rules = append(rules, allow(UserRoleAdmin, "read", "write", "comment", "manage")...)
+	rules = append(rules, allow(UserRoleOwner, "read", "write", "comment", "manage", "delete")...)
+	return &Policy{Rules: rules}
+}
+
+// defaultPolicy holds the policy consulted by User.HasPermission and
+// User.Can.
+var defaultPolicy atomic.Pointer[Policy]
+
+func init() {
+	defaultPolicy.Store(DefaultPolicy())
+}
+
+// CurrentPolicy returns the policy consulted by User.HasPermission and
+// User.Can.
+func CurrentPolicy() *Policy {
+	return defaultPolicy.Load()
+}
+
+// SetDefaultPolicy replaces the policy consulted by User.HasPermission
+// and User.Can. It is safe to call while requests are being served.
+func SetDefaultPolicy(p *Policy) {
+	defaultPolicy.Store(p)
+}
@@ -0,0 +1,120 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidPortfolio is returned when a portfolio is malformed.
+var ErrInvalidPortfolio = errors.New("invalid portfolio")
+
+// PortfolioRole is a member's role in a portfolio.
+type PortfolioRole string
+
+const (
+	// PortfolioLead may view the portfolio and change it.
+	PortfolioLead PortfolioRole = "lead"
+	// PortfolioViewer may view the portfolio and its roll-ups.
+	PortfolioViewer PortfolioRole = "viewer"
+)
+
+// PortfolioMember is a user with a role in a portfolio.
+type PortfolioMember struct {
+	UserID UserID        `json:"user_id"`
+	Role   PortfolioRole `json:"role"`
+}
+
+// Portfolio groups projects for roll-up reporting. Portfolios nest: a
+// portfolio with a ParentID is a program within that portfolio, and its
+// projects roll up into the parent's figures.
+//
+// Besides its own Members, a portfolio can take members from elsewhere:
+// InheritMembers adds the parent's effective members, and
+// IncludeProjectOwners adds the owners of its projects as viewers.
+type Portfolio struct {
+	ID                   string            `json:"id"`
+	ParentID             string            `json:"parent_id,omitempty"`
+	Name                 string            `json:"name"`
+	Description          string            `json:"description,omitempty"`
+	ProjectIDs           []ProjectID       `json:"project_ids"`
+	Members              []PortfolioMember `json:"members"`
+	InheritMembers       bool              `json:"inherit_members"`
+	IncludeProjectOwners bool              `json:"include_project_owners"`
+	CreatedAt            time.Time         `json:"created_at"`
+	UpdatedAt            time.Time         `json:"updated_at"`
+}
+
+// NewPortfolio creates an empty portfolio.
+func NewPortfolio(name string) *Portfolio {
+	now := time.Now()
+	return &Portfolio{
+		ID:         uuid.New().String(),
+		Name:       name,
+		ProjectIDs: make([]ProjectID, 0),
+		Members:    make([]PortfolioMember, 0),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// EntityID returns the portfolio's ID.
+func (p *Portfolio) EntityID() string {
+	return p.ID
+}
+
+// Clone returns a deep copy of the portfolio.
+func (p *Portfolio) Clone() *Portfolio {
+	c := *p
+	c.ProjectIDs = slices.Clone(p.ProjectIDs)
+	c.Members = slices.Clone(p.Members)
+	return &c
+}
+
+// Validate checks that the portfolio has a name, is not its own parent,
+// and lists each project and member once with a known role.
+func (p *Portfolio) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPortfolio)
+	}
+	if p.ParentID == p.ID {
+		return fmt.Errorf("%w: a portfolio cannot be its own parent", ErrInvalidPortfolio)
+	}
+	if p.InheritMembers && p.ParentID == "" {
+		return fmt.Errorf("%w: only a program within a portfolio can inherit members", ErrInvalidPortfolio)
+	}
+	projects := make(map[ProjectID]bool, len(p.ProjectIDs))
+	for _, id := range p.ProjectIDs {
+		if projects[id] {
+			return fmt.Errorf("%w: project %s is listed twice", ErrInvalidPortfolio, id)
+		}
+		projects[id] = true
+	}
+	members := make(map[UserID]bool, len(p.Members))
+	for _, m := range p.Members {
+		if m.UserID == "" || members[m.UserID] {
+			return fmt.Errorf("%w: each member must be listed once", ErrInvalidPortfolio)
+		}
+		if m.Role != PortfolioLead && m.Role != PortfolioViewer {
+			return fmt.Errorf("%w: role must be lead or viewer", ErrInvalidPortfolio)
+		}
+		members[m.UserID] = true
+	}
+	return nil
+}
+
+// HasProject checks if a project is in the portfolio itself, not
+// counting its programs.
+func (p *Portfolio) HasProject(id ProjectID) bool {
+	for _, projectID := range p.ProjectIDs {
+		if projectID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+var localeRegex = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+// ErrInvalidTimezone is returned when a timezone is not a known IANA zone.
+var ErrInvalidTimezone = errors.New("invalid timezone")
+
+// ErrInvalidLocale is returned when a locale is not a valid language tag.
+var ErrInvalidLocale = errors.New("invalid locale")
+
+// ErrInvalidDateFormat is returned when a date format is not one of the
+// known formats.
+var ErrInvalidDateFormat = errors.New("invalid date format")
+
+// ErrInvalidAvatarURL is returned when an avatar URL is not an absolute http(s) URL.
+var ErrInvalidAvatarURL = errors.New("invalid avatar URL")
+
+// NotificationPreferences controls which notifications a user receives.
+type NotificationPreferences struct {
+	Email        bool `json:"email"`
+	Assignments  bool `json:"assignments"`
+	Mentions     bool `json:"mentions"`
+	DueReminders bool `json:"due_reminders"`
+}
+
+// UserPreferences holds per-user settings persisted on the server.
+type UserPreferences struct {
+	Timezone         string                  `json:"timezone"`
+	Locale           string                  `json:"locale"`
+	DateFormat       DateFormat              `json:"date_format,omitempty"`
+	DefaultProjectID ProjectID               `json:"default_project_id,omitempty"`
+	Notifications    NotificationPreferences `json:"notifications"`
+}
+
+// DefaultUserPreferences returns the preferences given to new users.
+func DefaultUserPreferences() UserPreferences {
+	return UserPreferences{
+		Timezone: "UTC",
+		Locale:   "en",
+		Notifications: NotificationPreferences{
+			Email:        true,
+			Assignments:  true,
+			Mentions:     true,
+			DueReminders: true,
+		},
+	}
+}
+
+// Validate checks that the timezone, locale and date format are well formed.
+func (p UserPreferences) Validate() error {
+	if _, err := time.LoadLocation(p.Timezone); err != nil || p.Timezone == "" {
+		return ErrInvalidTimezone
+	}
+	if !localeRegex.MatchString(p.Locale) {
+		return ErrInvalidLocale
+	}
+	if !p.DateFormat.Valid() {
+		return ErrInvalidDateFormat
+	}
+	return nil
+}
+
+// Location returns the preferred time zone, falling back to UTC.
+func (p UserPreferences) Location() *time.Location {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// ValidateAvatarURL checks if an avatar URL is an absolute http(s) URL.
+//
+// An empty URL is valid and clears the avatar.
+func ValidateAvatarURL(raw string) bool {
+	if raw == "" {
+		return true
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,133 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrInvalidPriorityScheme is returned when a priority scheme is
+// malformed.
+var ErrInvalidPriorityScheme = errors.New("invalid priority scheme")
+
+// ErrUnknownPriority is returned when a task is given a priority its
+// project's scheme does not define.
+var ErrUnknownPriority = errors.New("priority is not defined by the project priority scheme")
+
+// colorRegex matches #rrggbb colors.
+var colorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// PriorityLevel is a named priority. Higher ranks are more urgent.
+type PriorityLevel struct {
+	Rank  TaskPriority `json:"rank"`
+	Name  string       `json:"name"`
+	Color string       `json:"color,omitempty"`
+}
+
+// PriorityScheme defines the priorities available to a project's tasks.
+//
+// Tasks store the rank of their priority, so tasks in any project can
+// still be compared by rank. Levels are kept sorted by rank, lowest
+// first, and new tasks get the Default rank.
+type PriorityScheme struct {
+	ProjectID ProjectID       `json:"project_id"`
+	Levels    []PriorityLevel `json:"levels"`
+	Default   TaskPriority    `json:"default"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// DefaultPriorityScheme returns the scheme used by projects without a
+// custom one: the built-in low, medium, high and critical priorities.
+func DefaultPriorityScheme(projectID ProjectID) *PriorityScheme {
+	return &PriorityScheme{
+		ProjectID: projectID,
+		Levels: []PriorityLevel{
+			{Rank: TaskPriorityLow, Name: "low", Color: "#6b7280"},
+			{Rank: TaskPriorityMedium, Name: "medium", Color: "#2563eb"},
+			{Rank: TaskPriorityHigh, Name: "high", Color: "#f59e0b"},
+			{Rank: TaskPriorityCritical, Name: "critical", Color: "#dc2626"},
+		},
+		Default: TaskPriorityMedium,
+	}
+}
+
+// Validate sorts the levels by rank and checks that ranks are positive
+// and unique, names are present and unique, colors are well formed and
+// the default rank is defined.
+func (s *PriorityScheme) Validate() error {
+	if len(s.Levels) == 0 {
+		return ErrInvalidPriorityScheme
+	}
+	sort.Slice(s.Levels, func(i, j int) bool {
+		return s.Levels[i].Rank < s.Levels[j].Rank
+	})
+
+	names := make(map[string]bool, len(s.Levels))
+	for i, level := range s.Levels {
+		name := strings.ToLower(strings.TrimSpace(level.Name))
+		if level.Rank <= 0 || name == "" || names[name] {
+			return ErrInvalidPriorityScheme
+		}
+		if i > 0 && s.Levels[i-1].Rank == level.Rank {
+			return ErrInvalidPriorityScheme
+		}
+		if level.Color != "" && !colorRegex.MatchString(level.Color) {
+			return ErrInvalidPriorityScheme
+		}
+		names[name] = true
+	}
+	if !s.Has(s.Default) {
+		return ErrInvalidPriorityScheme
+	}
+	return nil
+}
+
+// Has checks if the scheme defines a rank.
+func (s *PriorityScheme) Has(rank TaskPriority) bool {
+	return s.index(rank) >= 0
+}
+
+// ByName returns the level with the given name, compared
+// case-insensitively.
+func (s *PriorityScheme) ByName(name string) (PriorityLevel, bool) {
+	for _, level := range s.Levels {
+		if strings.EqualFold(level.Name, strings.TrimSpace(name)) {
+			return level, true
+		}
+	}
+	return PriorityLevel{}, false
+}
+
+// index returns the position of a rank in Levels, or -1.
+func (s *PriorityScheme) index(rank TaskPriority) int {
+	for i, level := range s.Levels {
+		if level.Rank == rank {
+			return i
+		}
+	}
+	return -1
+}
+
+// Migrate maps a rank from the scheme old to this scheme.
+//
+// A level with the same name keeps its name; otherwise the rank maps to
+// the level at the same relative position, so the lowest stays lowest
+// and the highest stays highest. Ranks old does not define map to the
+// default.
+func (s *PriorityScheme) Migrate(rank TaskPriority, old *PriorityScheme) TaskPriority {
+	i := old.index(rank)
+	if i < 0 {
+		return s.Default
+	}
+	if level, ok := s.ByName(old.Levels[i].Name); ok {
+		return level.Rank
+	}
+	if len(old.Levels) == 1 {
+		return s.Default
+	}
+	pos := (i*(len(s.Levels)-1) + (len(old.Levels)-1)/2) / (len(old.Levels) - 1)
+	return s.Levels[pos].Rank
+}
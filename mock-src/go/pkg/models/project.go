@@ -0,0 +1,298 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrEmptyProjectName is returned when a project name is blank.
+var ErrEmptyProjectName = errors.New("project name is required")
+
+// ErrProjectArchived is returned when changing an archived project or
+// its tasks.
+var ErrProjectArchived = errors.New("project is archived")
+
+// ErrInvalidProjectState is returned for an unknown project state.
+var ErrInvalidProjectState = errors.New("state must be active, paused or archived")
+
+// ErrInvalidProjectKey is returned for a malformed project key.
+var ErrInvalidProjectKey = errors.New("key must be 2 to 10 uppercase letters or digits, starting with a letter")
+
+// projectKeyRegex matches project keys such as "API" or "WEB2".
+var projectKeyRegex = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
+// ValidateProjectKey returns ErrInvalidProjectKey unless key is a well
+// formed project key.
+func ValidateProjectKey(key string) error {
+	if !projectKeyRegex.MatchString(key) {
+		return ErrInvalidProjectKey
+	}
+	return nil
+}
+
+// ErrInvalidSlug is returned for a malformed project slug.
+var ErrInvalidSlug = errors.New("slug must be 1 to 64 lowercase letters, digits and single hyphens")
+
+// ErrSlugTaken is returned when a slug is, or was, another project's.
+var ErrSlugTaken = errors.New("slug is already in use")
+
+// slugRegex matches slugs such as "mobile-app-2".
+var slugRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// MaxSlugLength caps the length of a slug.
+const MaxSlugLength = 64
+
+// ValidateSlug returns ErrInvalidSlug unless slug is well formed.
+func ValidateSlug(slug string) error {
+	if len(slug) > MaxSlugLength || !slugRegex.MatchString(slug) {
+		return ErrInvalidSlug
+	}
+	return nil
+}
+
+// Slugify derives a slug from a project name: ASCII letters and digits
+// are kept in lowercase and every other run of characters becomes a
+// single hyphen. Names without any yield "project".
+func Slugify(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+			if b.Len() >= MaxSlugLength {
+				break
+			}
+			continue
+		}
+		hyphen = true
+	}
+	if b.Len() == 0 {
+		return "project"
+	}
+	return b.String()
+}
+
+// ProjectState is where a project is in its lifecycle.
+type ProjectState string
+
+const (
+	// ProjectActive is a project being worked in. It is the default.
+	ProjectActive ProjectState = "active"
+	// ProjectPaused is a project on hold. It can still be changed.
+	ProjectPaused ProjectState = "paused"
+	// ProjectArchived is a finished project kept for reference. It and
+	// its tasks are read-only, and its tasks are left out of queries
+	// across projects unless asked for.
+	ProjectArchived ProjectState = "archived"
+)
+
+// Label is a named, colored tag available within a project.
+type Label struct {
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+}
+
+// Milestone is a named checkpoint within a project.
+type Milestone struct {
+	ID      string     `json:"id"`
+	Name    string     `json:"name"`
+	DueDate *time.Time `json:"due_date,omitempty"`
+}
+
+// View is a saved task filter within a project.
+type View struct {
+	Name    string            `json:"name"`
+	Filters map[string]string `json:"filters,omitempty"`
+}
+
+// Project represents a project that groups tasks.
+//
+// A project marked as a template is not worked in directly; it serves
+// as the blueprint for new projects of a recurring type. An optional
+// calendar defines the project's working hours and holidays, and an
+// optional duplicate check guards against near-identical open tasks.
+// When RequiresApproval is set, completing a task submits it for review
+// by an admin instead. An empty State means active.
+//
+// Key is a short, unique name such as "API" that prefixes the keys of
+// the project's tasks. Once set it does not change, so task keys stay
+// valid; TaskKeySeq is the number of the last task key given out, so
+// the keys of deleted tasks are not given out again. Slug names the project in friendly URLs; it can change, and
+// PreviousSlugs keeps the old ones so links to them still resolve.
+type Project struct {
+	ID               ProjectID         `json:"id"`
+	Name             string            `json:"name"`
+	Key              string            `json:"key,omitempty"`
+	TaskKeySeq       int               `json:"task_key_seq,omitempty"`
+	Slug             string            `json:"slug,omitempty"`
+	PreviousSlugs    []string          `json:"previous_slugs,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	OwnerID          UserID            `json:"owner_id,omitempty"`
+	IsTemplate       bool              `json:"is_template"`
+	Calendar         *BusinessCalendar `json:"calendar,omitempty"`
+	Duplicates       *DuplicateCheck   `json:"duplicate_check,omitempty"`
+	RequiresApproval bool              `json:"requires_approval,omitempty"`
+	State            ProjectState      `json:"state,omitempty"`
+	ArchivedAt       *time.Time        `json:"archived_at,omitempty"`
+	Labels           []Label           `json:"labels"`
+	Milestones       []Milestone       `json:"milestones"`
+	Views            []View            `json:"views"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+// NewProject creates a new project with the given name.
+//
+// Returns an error if the name is blank.
+func NewProject(name string) (*Project, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, ErrEmptyProjectName
+	}
+
+	now := time.Now()
+	return &Project{
+		ID:         NewProjectID(),
+		Name:       name,
+		Labels:     make([]Label, 0),
+		Milestones: make([]Milestone, 0),
+		Views:      make([]View, 0),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// EntityID returns the project's ID.
+func (p *Project) EntityID() ProjectID {
+	return p.ID
+}
+
+// Clone returns a deep copy of the project.
+func (p *Project) Clone() *Project {
+	c := *p
+	c.PreviousSlugs = slices.Clone(p.PreviousSlugs)
+	if p.Calendar != nil {
+		calendar := *p.Calendar
+		calendar.Days = slices.Clone(p.Calendar.Days)
+		calendar.Holidays = slices.Clone(p.Calendar.Holidays)
+		c.Calendar = &calendar
+	}
+	c.Duplicates = copyPtr(p.Duplicates)
+	c.ArchivedAt = copyTimePtr(p.ArchivedAt)
+	c.Labels = slices.Clone(p.Labels)
+	c.Milestones = slices.Clone(p.Milestones)
+	for i := range c.Milestones {
+		c.Milestones[i].DueDate = copyTimePtr(c.Milestones[i].DueDate)
+	}
+	c.Views = slices.Clone(p.Views)
+	for i := range c.Views {
+		c.Views[i].Filters = maps.Clone(c.Views[i].Filters)
+	}
+	return &c
+}
+
+// HasSlug checks if slug is the project's slug, now or previously.
+func (p *Project) HasSlug(slug string) bool {
+	return p.Slug == slug || slices.Contains(p.PreviousSlugs, slug)
+}
+
+// SetSlug changes the project's slug, keeping the old one in
+// PreviousSlugs. Taking back a previous slug removes it from there. It
+// reports whether the slug changed.
+func (p *Project) SetSlug(slug string) (bool, error) {
+	if err := ValidateSlug(slug); err != nil {
+		return false, err
+	}
+	if p.Slug == slug {
+		return false, nil
+	}
+	previous := slices.DeleteFunc(slices.Clone(p.PreviousSlugs), func(s string) bool { return s == slug })
+	if p.Slug != "" {
+		previous = append(previous, p.Slug)
+	}
+	p.PreviousSlugs = previous
+	p.Slug = slug
+	p.UpdatedAt = time.Now()
+	return true, nil
+}
+
+// IsArchived checks if the project is archived.
+func (p *Project) IsArchived() bool {
+	return p.State == ProjectArchived
+}
+
+// SetState moves the project to a lifecycle state, recording when it
+// was archived. It reports whether the state changed.
+func (p *Project) SetState(state ProjectState) (bool, error) {
+	switch state {
+	case ProjectActive, ProjectPaused, ProjectArchived:
+	default:
+		return false, ErrInvalidProjectState
+	}
+	current := p.State
+	if current == "" {
+		current = ProjectActive
+	}
+	if current == state {
+		return false, nil
+	}
+	p.State = state
+	p.UpdatedAt = time.Now()
+	p.ArchivedAt = nil
+	if state == ProjectArchived {
+		archivedAt := p.UpdatedAt
+		p.ArchivedAt = &archivedAt
+	}
+	return true, nil
+}
+
+// CloneStructure creates a new, non-template project with the given name
+// that copies this project's labels, milestones and views.
+//
+// Milestones receive new IDs.
+func (p *Project) CloneStructure(name string) (*Project, error) {
+	clone, err := NewProject(name)
+	if err != nil {
+		return nil, err
+	}
+	clone.Description = p.Description
+	if p.Calendar != nil {
+		cal := *p.Calendar
+		cal.Days = append([]time.Weekday(nil), p.Calendar.Days...)
+		cal.Holidays = append([]string(nil), p.Calendar.Holidays...)
+		clone.Calendar = &cal
+	}
+	if p.Duplicates != nil {
+		check := *p.Duplicates
+		clone.Duplicates = &check
+	}
+	clone.RequiresApproval = p.RequiresApproval
+	clone.Labels = append(clone.Labels, p.Labels...)
+
+	for _, m := range p.Milestones {
+		clone.Milestones = append(clone.Milestones, Milestone{
+			ID:      uuid.New().String(),
+			Name:    m.Name,
+			DueDate: copyTimePtr(m.DueDate),
+		})
+	}
+
+	for _, v := range p.Views {
+		filters := make(map[string]string, len(v.Filters))
+		for k, val := range v.Filters {
+			filters[k] = val
+		}
+		clone.Views = append(clone.Views, View{Name: v.Name, Filters: filters})
+	}
+	return clone, nil
+}
@@ -0,0 +1,118 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidProjectSettings is returned when a project settings document
+// is malformed.
+var ErrInvalidProjectSettings = errors.New("invalid project settings")
+
+// maxNotificationRules caps the notification rules of a project.
+const maxNotificationRules = 20
+
+// NotificationRule notifies users when an event of type Event occurs on
+// one of the project's tasks. Recipients are user IDs; NotifyAssignee
+// also notifies the task's assignee, if any.
+type NotificationRule struct {
+	Event          string   `json:"event"`
+	Recipients     []UserID `json:"recipients"`
+	NotifyAssignee bool     `json:"notify_assignee,omitempty"`
+}
+
+// ProjectSettings holds a project's own configuration in place of the
+// global defaults.
+//
+// DefaultAssigneeID, DefaultPriority, DefaultTags and DefaultDueInDays
+// apply to new tasks that do not set them, in place of the global
+// TaskDefaults. WorkflowID names another project whose workflow this project
+// follows; empty means its own. WIPLimits override the workflow's limit
+// for individual statuses, with zero removing the limit.
+type ProjectSettings struct {
+	ProjectID         ProjectID          `json:"project_id"`
+	DefaultAssigneeID *UserID            `json:"default_assignee_id,omitempty"`
+	DefaultPriority   TaskPriority       `json:"default_priority,omitempty"`
+	DefaultTags       []string           `json:"default_tags,omitempty"`
+	DefaultDueInDays  *int               `json:"default_due_in_days,omitempty"`
+	WorkflowID        ProjectID          `json:"workflow_id,omitempty"`
+	WIPLimits         map[TaskStatus]int `json:"wip_limits,omitempty"`
+	NotificationRules []NotificationRule `json:"notification_rules"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+}
+
+// DefaultProjectSettings returns the settings of a project that has not
+// set any.
+func DefaultProjectSettings(projectID ProjectID) *ProjectSettings {
+	return &ProjectSettings{
+		ProjectID:         projectID,
+		NotificationRules: make([]NotificationRule, 0),
+	}
+}
+
+// Validate normalizes the default tags and checks that the default
+// priority and WIP limits are not negative, that tags are not blank,
+// that the due offset is in range, that the workflow is not the
+// project's own, and that each notification rule has an event and at
+// least one recipient listed once.
+func (s *ProjectSettings) Validate() error {
+	if s.DefaultAssigneeID != nil && *s.DefaultAssigneeID == "" {
+		return fmt.Errorf("%w: default_assignee_id cannot be empty", ErrInvalidProjectSettings)
+	}
+	if s.DefaultPriority < 0 {
+		return fmt.Errorf("%w: default_priority cannot be negative", ErrInvalidProjectSettings)
+	}
+	tags, err := normalizeDefaultTags(s.DefaultTags)
+	if err != nil {
+		return fmt.Errorf("%w: default_tags: %v", ErrInvalidProjectSettings, err)
+	}
+	if len(tags) > 0 {
+		s.DefaultTags = tags
+	}
+	if s.DefaultDueInDays != nil && (*s.DefaultDueInDays < 0 || *s.DefaultDueInDays > maxDefaultDueInDays) {
+		return fmt.Errorf("%w: default_due_in_days must be between 0 and %d", ErrInvalidProjectSettings, maxDefaultDueInDays)
+	}
+	if s.WorkflowID == s.ProjectID {
+		return fmt.Errorf("%w: workflow_id must name another project", ErrInvalidProjectSettings)
+	}
+	for status, limit := range s.WIPLimits {
+		if !statusNameRegex.MatchString(string(status)) || limit < 0 {
+			return fmt.Errorf("%w: wip limit for %q must be a non-negative count", ErrInvalidProjectSettings, status)
+		}
+	}
+	if len(s.NotificationRules) > maxNotificationRules {
+		return fmt.Errorf("%w: at most %d notification rules are allowed", ErrInvalidProjectSettings, maxNotificationRules)
+	}
+	for i, rule := range s.NotificationRules {
+		if rule.Event == "" {
+			return fmt.Errorf("%w: notification rule %d: event is required", ErrInvalidProjectSettings, i)
+		}
+		if len(rule.Recipients) == 0 && !rule.NotifyAssignee {
+			return fmt.Errorf("%w: notification rule %d: recipients are required", ErrInvalidProjectSettings, i)
+		}
+		seen := make(map[UserID]bool, len(rule.Recipients))
+		for _, id := range rule.Recipients {
+			if id == "" || seen[id] {
+				return fmt.Errorf("%w: notification rule %d: each recipient must be listed once", ErrInvalidProjectSettings, i)
+			}
+			seen[id] = true
+		}
+	}
+	return nil
+}
+
+// Apply returns a copy of workflow, made the project's own, with the
+// settings' WIP limits in place of the workflow's.
+func (s *ProjectSettings) Apply(workflow *Workflow) *Workflow {
+	w := *workflow
+	w.ProjectID = s.ProjectID
+	w.Statuses = append([]WorkflowStatus(nil), workflow.Statuses...)
+	for i, status := range w.Statuses {
+		if limit, ok := s.WIPLimits[status.Name]; ok {
+			w.Statuses[i].WIPLimit = limit
+		}
+	}
+	return &w
+}
@@ -0,0 +1,235 @@
+package models
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"testing/quick"
+)
+
+// propertyStatuses are the statuses operations draw from, including a
+// custom workflow status.
+var propertyStatuses = []TaskStatus{
+	TaskStatusPending,
+	TaskStatusInProgress,
+	TaskStatusBlocked,
+	TaskStatusCompleted,
+	TaskStatusCancelled,
+	TaskStatusAwaitingReview,
+	"qa",
+}
+
+// propertyUsers are the user IDs operations draw from.
+var propertyUsers = []UserID{"ann", "bob", "cy"}
+
+// taskOp is a validating task operation; it reports whether it applied.
+//
+// Unguarded operations such as MarkComplete and BlockOn are left out:
+// their preconditions are enforced by the handlers, not the model.
+type taskOp struct {
+	name  string
+	apply func(task *Task, arg int) bool
+}
+
+var taskOps = []taskOp{
+	{"SetStatus", func(t *Task, arg int) bool { return t.SetStatus(propertyStatuses[arg%len(propertyStatuses)]) }},
+	{"SubmitForReview", func(t *Task, arg int) bool { return t.SubmitForReview() }},
+	{"Approve", func(t *Task, arg int) bool { return t.Approve() }},
+	{"Reject", func(t *Task, arg int) bool { return t.Reject("needs work") }},
+	{"Unblock", func(t *Task, arg int) bool { return t.Unblock() }},
+	{"AddTag", func(t *Task, arg int) bool { return t.AddTag(string(propertyUsers[arg%len(propertyUsers)])) }},
+	{"RemoveTag", func(t *Task, arg int) bool { return t.RemoveTag(string(propertyUsers[arg%len(propertyUsers)])) }},
+	{"Vote", func(t *Task, arg int) bool { return t.Vote(propertyUsers[arg%len(propertyUsers)]) }},
+	{"Unvote", func(t *Task, arg int) bool { return t.Unvote(propertyUsers[arg%len(propertyUsers)]) }},
+	{"SetReviewer", func(t *Task, arg int) bool { return t.SetReviewer(propertyUsers[arg%len(propertyUsers)]) }},
+	{"ClearReviewer", func(t *Task, arg int) bool { return t.ClearReviewer() }},
+	{"Publish", func(t *Task, arg int) bool { return t.Publish() }},
+}
+
+// runOps applies the operations encoded by steps to task, calling check
+// with the state before and after each one. It stops at the first
+// failed check.
+func runOps(task *Task, steps []uint16, check func(op string, ok bool, before, after *Task) bool) bool {
+	for _, step := range steps {
+		op := taskOps[int(step)%len(taskOps)]
+		before := task.Clone()
+		ok := op.apply(task, int(step)/len(taskOps))
+		if !check(op.name, ok, before, task) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPropertyAddThenRemoveTagIsNoOp(t *testing.T) {
+	property := func(existing []string, tag string) bool {
+		task := NewTask("task", "project")
+		for _, e := range existing {
+			task.AddTag(e)
+		}
+		before := slices.Clone(task.Tags)
+
+		if !task.AddTag(tag) {
+			return true // already present; nothing to undo
+		}
+		return task.RemoveTag(tag) && reflect.DeepEqual(task.Tags, before)
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPropertyRejectedOperationsLeaveTaskUnchanged(t *testing.T) {
+	property := func(draft bool, steps []uint16) bool {
+		task := NewTask("task", "project")
+		task.Draft = draft
+		return runOps(task, steps, func(op string, ok bool, before, after *Task) bool {
+			if ok {
+				return true
+			}
+			if !reflect.DeepEqual(before, after) {
+				t.Logf("%s returned false but changed the task", op)
+				return false
+			}
+			return true
+		})
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPropertyReviewIsOnlyEnteredAndLeftThroughReview(t *testing.T) {
+	property := func(steps []uint16) bool {
+		task := NewTask("task", "project")
+		return runOps(task, steps, func(op string, ok bool, before, after *Task) bool {
+			switch {
+			case before.Status == after.Status:
+				return true
+			case after.Status == TaskStatusAwaitingReview:
+				if op != "SubmitForReview" || !before.IsOpen() {
+					t.Logf("%s moved %s into review", op, before.Status)
+					return false
+				}
+			case before.Status == TaskStatusAwaitingReview:
+				switch {
+				case op == "Approve" && after.Status == TaskStatusCompleted:
+				case op == "Reject" && after.Status != TaskStatusCompleted:
+				default:
+					t.Logf("%s moved a task out of review to %s", op, after.Status)
+					return false
+				}
+			}
+			return true
+		})
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPropertyUpdatedAtIsMonotonic(t *testing.T) {
+	property := func(steps []uint16) bool {
+		task := NewTask("task", "project")
+		return runOps(task, steps, func(op string, ok bool, before, after *Task) bool {
+			if after.UpdatedAt.Before(before.UpdatedAt) {
+				t.Logf("%s moved UpdatedAt backwards", op)
+				return false
+			}
+			if after.StatusChangedAt.Before(before.StatusChangedAt) {
+				t.Logf("%s moved StatusChangedAt backwards", op)
+				return false
+			}
+			return true
+		})
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPropertyCloneIsIndependent(t *testing.T) {
+	property := func(steps []uint16) bool {
+		task := NewTask("task", "project")
+		runOps(task, steps, func(string, bool, *Task, *Task) bool { return true })
+
+		clone := task.Clone()
+		snapshot := task.Clone()
+		runOps(clone, steps, func(string, bool, *Task, *Task) bool { return true })
+		clone.AddTag("clone-only")
+		clone.Vote("clone-only")
+		clone.SetReviewer("clone-only")
+		return reflect.DeepEqual(task, snapshot)
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPropertyDiffTaskEventsReplaysExactly(t *testing.T) {
+	property := func(steps []uint16) bool {
+		task := NewTask("task", "project")
+		return runOps(task, steps, func(op string, ok bool, before, after *Task) bool {
+			replayed := before.Clone()
+			for _, e := range DiffTaskEvents(before, after) {
+				replayed = e.Apply(replayed)
+			}
+			if !replayed.Equal(after) {
+				t.Logf("replaying the events of %s did not reproduce the task", op)
+				return false
+			}
+			return true
+		})
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPropertyWorkflowChecksAgree(t *testing.T) {
+	property := func(keep []bool, from, to uint8) bool {
+		w := DefaultWorkflow("project")
+		transitions := w.Transitions[:0]
+		for i, tr := range w.Transitions {
+			if i < len(keep) && keep[i] {
+				transitions = append(transitions, tr)
+			}
+		}
+		w.Transitions = transitions
+
+		f := propertyStatuses[int(from)%len(propertyStatuses)]
+		s := propertyStatuses[int(to)%len(propertyStatuses)]
+		allowed := w.CheckTransition(f, s) == nil
+		if allowed != (w.HasStatus(s) && w.Allows(f, s)) {
+			return false
+		}
+		inNext := false
+		for _, n := range w.Next(f) {
+			inNext = inNext || n == s
+		}
+		return inNext == (f != s && w.Allows(f, s))
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPropertyDiffTasksAgreesWithEqual(t *testing.T) {
+	property := func(steps []uint16) bool {
+		task := NewTask("task", "project")
+		return runOps(task, steps, func(op string, ok bool, before, after *Task) bool {
+			changes := DiffTasks(before, after)
+			if before.Equal(after) != (len(changes) == 0) || !after.Equal(after.Clone()) {
+				return false
+			}
+			if !ok && len(changes) > 0 {
+				t.Logf("%s returned false but changed %v", op, changes.Fields())
+				return false
+			}
+			return true
+		})
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
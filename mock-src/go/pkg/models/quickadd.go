@@ -0,0 +1,164 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrEmptyQuickAdd is returned when quick-add text has no title left
+// once its tokens are parsed.
+var ErrEmptyQuickAdd = errors.New("quick-add text has no title")
+
+// quickAddDateLayout is the format of explicit quick-add due dates.
+const quickAddDateLayout = "2006-01-02"
+
+// quickAddPriorities maps !priority tokens to priorities.
+var quickAddPriorities = map[string]TaskPriority{
+	"low":      TaskPriorityLow,
+	"medium":   TaskPriorityMedium,
+	"high":     TaskPriorityHigh,
+	"critical": TaskPriorityCritical,
+	"urgent":   TaskPriorityCritical,
+	"1":        TaskPriorityLow,
+	"2":        TaskPriorityMedium,
+	"3":        TaskPriorityHigh,
+	"4":        TaskPriorityCritical,
+}
+
+// QuickAdd is the result of parsing free-text task input.
+//
+// Priority is zero and DueDate nil when the text did not set them.
+// Project is the +shortcut named in the text, if any.
+type QuickAdd struct {
+	Title    string       `json:"title"`
+	Tags     []string     `json:"tags"`
+	Priority TaskPriority `json:"priority,omitempty"`
+	DueDate  *time.Time   `json:"due_date,omitempty"`
+	Project  string       `json:"project,omitempty"`
+}
+
+// ParseQuickAdd parses free text such as
+// "Fix login bug #backend !high due friday +web".
+//
+// Recognized tokens are #tag, !priority (low, medium, high, critical,
+// urgent or 1-4), +project and "due" followed by today, tomorrow, a
+// weekday, "next" and a weekday, "in N days" or "in N weeks", or a
+// YYYY-MM-DD date. Due dates are the end of the day in loc, relative to
+// now. Unrecognized tokens are kept in the title.
+func ParseQuickAdd(text string, now time.Time, loc *time.Location) (*QuickAdd, error) {
+	q := &QuickAdd{Tags: make([]string, 0)}
+	words := strings.Fields(text)
+	title := make([]string, 0, len(words))
+
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		switch {
+		case len(word) > 1 && word[0] == '#':
+			tag := strings.ToLower(word[1:])
+			if !containsString(q.Tags, tag) {
+				q.Tags = append(q.Tags, tag)
+			}
+			continue
+		case len(word) > 1 && word[0] == '!':
+			if p, ok := quickAddPriorities[strings.ToLower(word[1:])]; ok {
+				q.Priority = p
+				continue
+			}
+		case len(word) > 1 && word[0] == '+':
+			q.Project = word[1:]
+			continue
+		case strings.EqualFold(word, "due"):
+			if due, n := parseQuickAddDate(words[i+1:], now, loc); n > 0 {
+				q.DueDate = &due
+				i += n
+				continue
+			}
+		}
+		title = append(title, word)
+	}
+
+	q.Title = strings.Join(title, " ")
+	if q.Title == "" {
+		return nil, ErrEmptyQuickAdd
+	}
+	return q, nil
+}
+
+// parseQuickAddDate parses a due date expression at the start of words,
+// returning the end of that day and the number of words consumed, or
+// zero if words do not start with a date.
+func parseQuickAddDate(words []string, now time.Time, loc *time.Location) (time.Time, int) {
+	if len(words) == 0 {
+		return time.Time{}, 0
+	}
+	local := now.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	endOf := func(day time.Time) time.Time {
+		return day.AddDate(0, 0, 1).Add(-time.Second)
+	}
+
+	first := strings.ToLower(words[0])
+	switch first {
+	case "today":
+		return endOf(today), 1
+	case "tomorrow":
+		return endOf(today.AddDate(0, 0, 1)), 1
+	case "next":
+		if len(words) > 1 {
+			if wd, ok := parseWeekday(words[1]); ok {
+				days := (int(wd) - int(today.Weekday()) + 7) % 7
+				if days == 0 {
+					days = 7
+				}
+				return endOf(today.AddDate(0, 0, days)), 2
+			}
+		}
+	case "in":
+		if len(words) > 2 {
+			n, err := strconv.Atoi(words[1])
+			if err != nil || n < 0 {
+				return time.Time{}, 0
+			}
+			switch strings.ToLower(words[2]) {
+			case "day", "days":
+				return endOf(today.AddDate(0, 0, n)), 3
+			case "week", "weeks":
+				return endOf(today.AddDate(0, 0, 7*n)), 3
+			}
+		}
+	default:
+		if wd, ok := parseWeekday(first); ok {
+			days := (int(wd) - int(today.Weekday()) + 7) % 7
+			return endOf(today.AddDate(0, 0, days)), 1
+		}
+		if day, err := time.ParseInLocation(quickAddDateLayout, first, loc); err == nil {
+			return endOf(day), 1
+		}
+	}
+	return time.Time{}, 0
+}
+
+// parseWeekday parses a full or three-letter English weekday name.
+func parseWeekday(s string) (time.Weekday, bool) {
+	s = strings.ToLower(s)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// containsString reports whether s is in list.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
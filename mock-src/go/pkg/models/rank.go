@@ -0,0 +1,117 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// rankDigits are the digits of ranks, in order.
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// ErrInvalidRankRange is returned when no rank can lie between two ranks
+// because they are out of order or malformed.
+var ErrInvalidRankRange = errors.New("invalid rank range")
+
+// RankBetween returns a rank that sorts strictly between lo and hi.
+//
+// Ranks are strings compared lexicographically, so a task can always be
+// placed between two others without renumbering the rest. An empty lo
+// means before everything and an empty hi after everything. Generated
+// ranks never end in the lowest digit, which keeps room before them.
+//
+// Returns ErrInvalidRankRange if lo is not before hi, or if hi is lo
+// followed only by lowest digits, as with "a" and "a0", since no rank
+// that keeps that room lies between them.
+func RankBetween(lo, hi string) (string, error) {
+	if !validRank(lo) || !validRank(hi) || (hi != "" && lo >= hi) {
+		return "", ErrInvalidRankRange
+	}
+	if hi != "" && strings.HasPrefix(hi, lo) && strings.Trim(hi[len(lo):], rankDigits[:1]) == "" {
+		return "", ErrInvalidRankRange
+	}
+	return rankMidpoint(lo, hi), nil
+}
+
+// rankMidpoint returns a rank between lo and hi, where lo < hi, hi is
+// not lo followed only by lowest digits, and an empty hi is unbounded.
+func rankMidpoint(lo, hi string) string {
+	var prefix strings.Builder
+	for i := 0; ; i++ {
+		dlo := 0
+		if i < len(lo) {
+			dlo = strings.IndexByte(rankDigits, lo[i])
+		}
+		dhi := len(rankDigits)
+		if hi != "" {
+			dhi = strings.IndexByte(rankDigits, hi[i])
+		}
+
+		if dlo == dhi {
+			prefix.WriteByte(rankDigits[dlo])
+			continue
+		}
+		if dhi-dlo > 1 {
+			prefix.WriteByte(rankDigits[(dlo+dhi)/2])
+			return prefix.String()
+		}
+		prefix.WriteByte(rankDigits[dlo])
+		rest := ""
+		if i+1 < len(lo) {
+			rest = lo[i+1:]
+		}
+		return prefix.String() + rankMidpoint(rest, "")
+	}
+}
+
+// SpreadRanks returns n ranks in increasing order, evenly spaced and of
+// at most equal length, for renumbering a whole list at once.
+func SpreadRanks(n int) []string {
+	base := len(rankDigits)
+	width, capacity := 1, base
+	for capacity <= n {
+		width++
+		capacity *= base
+	}
+
+	ranks := make([]string, n)
+	digits := make([]byte, width)
+	for k := 0; k < n; k++ {
+		v := (k + 1) * capacity / (n + 1)
+		for i := width - 1; i >= 0; i-- {
+			digits[i] = rankDigits[v%base]
+			v /= base
+		}
+		ranks[k] = strings.TrimRight(string(digits), rankDigits[:1])
+	}
+	return ranks
+}
+
+// validRank checks that a rank uses only rank digits.
+func validRank(rank string) bool {
+	for i := 0; i < len(rank); i++ {
+		if strings.IndexByte(rankDigits, rank[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// SortByRank sorts tasks by rank. Unranked tasks sort after ranked ones,
+// oldest first.
+func SortByRank(tasks []*Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if a.Rank == "" || b.Rank == "" {
+			if a.Rank != b.Rank {
+				return a.Rank != ""
+			}
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+			return a.ID < b.ID
+		}
+		return a.Rank < b.Rank
+	})
+}
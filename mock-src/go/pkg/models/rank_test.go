@@ -0,0 +1,48 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestRankBetween checks that ranks fall strictly between their bounds
+// and that ranges with no room for a rank fail instead of panicking.
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		lo, hi  string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"", "1", false},
+		{"a", "", false},
+		{"a", "b", false},
+		{"a", "a1", false},
+		{"a", "a01", false},
+		{"a0", "a1", false},
+		{"azz", "b", false},
+		{"", "0", true},
+		{"", "00", true},
+		{"a", "a0", true},
+		{"a", "a00", true},
+		{"a0", "a00", true},
+		{"b", "a", true},
+		{"a", "a", true},
+		{"A", "b", true},
+	}
+	for _, tt := range tests {
+		got, err := RankBetween(tt.lo, tt.hi)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidRankRange) {
+				t.Errorf("RankBetween(%q, %q) = %q, %v; want ErrInvalidRankRange", tt.lo, tt.hi, got, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("RankBetween(%q, %q): %v", tt.lo, tt.hi, err)
+			continue
+		}
+		if got <= tt.lo || (tt.hi != "" && got >= tt.hi) {
+			t.Errorf("RankBetween(%q, %q) = %q, not strictly between", tt.lo, tt.hi, got)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ReactionTarget identifies what a reaction is on.
+type ReactionTarget string
+
+const (
+	// ReactionTargetTask is a reaction on a task.
+	ReactionTargetTask ReactionTarget = "task"
+	// ReactionTargetComment is a reaction on a comment.
+	ReactionTargetComment ReactionTarget = "comment"
+)
+
+// maxEmojiLength caps the length of a reaction emoji in bytes.
+const maxEmojiLength = 32
+
+// ErrInvalidEmoji is returned when a reaction is not a single emoji or
+// :shortcode:.
+var ErrInvalidEmoji = errors.New("reaction must be an emoji or :shortcode:")
+
+// shortcodeRegex matches emoji shortcodes such as :thumbsup:.
+var shortcodeRegex = regexp.MustCompile(`^:[a-z0-9_+-]{1,30}:$`)
+
+// Reaction is a user's emoji reaction on a task or comment.
+type Reaction struct {
+	TargetType ReactionTarget `json:"target_type"`
+	TargetID   string         `json:"target_id"`
+	UserID     UserID         `json:"user_id"`
+	Emoji      string         `json:"emoji"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// NewReaction creates a reaction by a user on a target.
+//
+// Returns ErrInvalidEmoji if emoji is not a valid reaction.
+func NewReaction(targetType ReactionTarget, targetID string, userID UserID, emoji string) (*Reaction, error) {
+	if !ValidateEmoji(emoji) {
+		return nil, ErrInvalidEmoji
+	}
+	return &Reaction{
+		TargetType: targetType,
+		TargetID:   targetID,
+		UserID:     userID,
+		Emoji:      emoji,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// ValidateEmoji checks that a reaction is a :shortcode: or made only of
+// emoji symbols, modifiers and joiners.
+func ValidateEmoji(emoji string) bool {
+	if emoji == "" || len(emoji) > maxEmojiLength || !utf8.ValidString(emoji) {
+		return false
+	}
+	if shortcodeRegex.MatchString(emoji) {
+		return true
+	}
+	for _, r := range emoji {
+		switch {
+		case unicode.In(r, unicode.So, unicode.Sk, unicode.Mn, unicode.Me):
+		case r == '\u200d':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ReactionCount aggregates the reactions with one emoji on a target.
+type ReactionCount struct {
+	Emoji   string   `json:"emoji"`
+	Count   int      `json:"count"`
+	UserIDs []UserID `json:"user_ids"`
+}
+
+// CountReactions aggregates reactions by emoji, most used first and
+// ties in the order the emoji was first used.
+func CountReactions(reactions []*Reaction) []ReactionCount {
+	sorted := append([]*Reaction(nil), reactions...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	index := make(map[string]int)
+	counts := make([]ReactionCount, 0)
+	for _, r := range sorted {
+		i, ok := index[r.Emoji]
+		if !ok {
+			i = len(counts)
+			index[r.Emoji] = i
+			counts = append(counts, ReactionCount{Emoji: r.Emoji})
+		}
+		counts[i].Count++
+		counts[i].UserIDs = append(counts[i].UserIDs, r.UserID)
+	}
+	sort.SliceStable(counts, func(i, j int) bool {
+		return counts[i].Count > counts[j].Count
+	})
+	return counts
+}
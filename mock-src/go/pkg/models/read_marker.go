@@ -0,0 +1,23 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import "time"
+
+// ReadTarget identifies what a read marker tracks.
+type ReadTarget string
+
+const (
+	// ReadTargetTask tracks the comments on a task.
+	ReadTargetTask ReadTarget = "task"
+	// ReadTargetProject tracks the activity in a project.
+	ReadTargetProject ReadTarget = "project"
+)
+
+// ReadMarker records when a user last caught up on a task or project.
+// Anything newer than SeenAt is unread.
+type ReadMarker struct {
+	UserID     UserID     `json:"user_id"`
+	TargetType ReadTarget `json:"target_type"`
+	TargetID   string     `json:"target_id"`
+	SeenAt     time.Time  `json:"seen_at"`
+}
@@ -0,0 +1,124 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import "strings"
+
+const (
+	// relatedTagWeight, relatedTextWeight and relatedDependencyWeight
+	// weigh the signals making up a related task's score.
+	relatedTagWeight        = 0.3
+	relatedTextWeight       = 0.5
+	relatedDependencyWeight = 0.2
+	// MinRelatedScore is the score below which a task is not suggested
+	// as related.
+	MinRelatedScore = 0.15
+)
+
+// RelatedTask is a task that may be related to, or duplicate, another,
+// with the evidence found for it. Score, TextSimilarity and each part
+// of it are in [0, 1].
+type RelatedTask struct {
+	TaskID             TaskID     `json:"task_id"`
+	Key                string     `json:"key,omitempty"`
+	Title              string     `json:"title"`
+	Status             TaskStatus `json:"status"`
+	Score              float64    `json:"score"`
+	SharedTags         []string   `json:"shared_tags"`
+	TextSimilarity     float64    `json:"text_similarity"`
+	SharedDependencies []TaskID   `json:"shared_dependencies"`
+	// LikelyDuplicate is set when the titles are similar enough for a
+	// duplicate check with the default threshold to flag them.
+	LikelyDuplicate bool `json:"likely_duplicate"`
+}
+
+// RelatedScorer scores how likely other tasks are related to one task,
+// preparing that task's words and tags once for all of them.
+type RelatedScorer struct {
+	task  *Task
+	tags  map[string]bool
+	words map[string]bool
+}
+
+// NewRelatedScorer creates a scorer comparing other tasks with task.
+func NewRelatedScorer(task *Task) *RelatedScorer {
+	tags := make(map[string]bool, len(task.Tags))
+	for _, tag := range task.Tags {
+		tags[tag] = true
+	}
+	return &RelatedScorer{task: task, tags: tags, words: relatedWords(task)}
+}
+
+// Score scores how likely other is related to the scorer's task, from
+// the overlap of their tags, the words their text shares, and how many
+// of the task's dependency links, in either direction, lead to tasks
+// other is linked with too. dependencies is the number of tasks the
+// task is linked with by dependencies, and shared those of them other
+// is linked with.
+func (s *RelatedScorer) Score(other *Task, dependencies int, shared []TaskID) *RelatedTask {
+	related := &RelatedTask{
+		TaskID:             other.ID,
+		Key:                other.Key,
+		Title:              other.Title,
+		Status:             other.Status,
+		SharedTags:         make([]string, 0),
+		SharedDependencies: make([]TaskID, 0, len(shared)),
+	}
+
+	otherTags := make(map[string]bool, len(other.Tags))
+	for _, tag := range other.Tags {
+		otherTags[tag] = true
+	}
+	for _, tag := range s.task.Tags {
+		if otherTags[tag] {
+			related.SharedTags = append(related.SharedTags, tag)
+		}
+	}
+	tagScore := 0.0
+	if union := len(s.tags) + len(otherTags) - len(related.SharedTags); union > 0 {
+		tagScore = float64(len(related.SharedTags)) / float64(union)
+	}
+
+	title := titleSimilarity(NormalizeTitle(s.task.Title), NormalizeTitle(other.Title))
+	related.LikelyDuplicate = title >= DefaultDuplicateThreshold
+	related.TextSimilarity = wordOverlap(s.words, relatedWords(other))
+	if related.LikelyDuplicate {
+		// Edit distance says little about short unrelated titles, so it
+		// only counts when it is high.
+		related.TextSimilarity = max(related.TextSimilarity, title)
+	}
+
+	related.SharedDependencies = append(related.SharedDependencies, shared...)
+	dependencyScore := 0.0
+	if dependencies > 0 {
+		dependencyScore = float64(len(shared)) / float64(dependencies)
+	}
+
+	related.Score = relatedTagWeight*tagScore + relatedTextWeight*related.TextSimilarity + relatedDependencyWeight*dependencyScore
+	return related
+}
+
+// relatedWords returns the distinct words of three letters or more in a
+// task's title and description, for comparing their text.
+func relatedWords(task *Task) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.Fields(NormalizeTitle(task.Title + " " + task.Description)) {
+		if len([]rune(word)) >= 3 {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+// wordOverlap returns the Jaccard similarity of two sets of words.
+func wordOverlap(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
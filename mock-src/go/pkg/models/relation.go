@@ -0,0 +1,88 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RelationType identifies the kind of link between two tasks.
+type RelationType string
+
+const (
+	// RelationDependsOn is a hard dependency: the source cannot finish
+	// before the target.
+	RelationDependsOn RelationType = "depends_on"
+	// RelationDuplicateOf links a duplicate task to the original it duplicates.
+	RelationDuplicateOf RelationType = "duplicate_of"
+	// RelationRelatesTo links two related tasks; it has no direction.
+	RelationRelatesTo RelationType = "relates_to"
+	// RelationCausedBy links a task to the task that caused it.
+	RelationCausedBy RelationType = "caused_by"
+	// RelationSubtaskOf links a subtask to its parent task.
+	RelationSubtaskOf RelationType = "subtask_of"
+)
+
+// ErrInvalidRelation is returned when a relation has an unknown type or
+// links a task to itself.
+var ErrInvalidRelation = errors.New("invalid task relation")
+
+// TaskRelation is a typed link from a source task to a target task,
+// read as "source <type> target", e.g. "A duplicate_of B".
+type TaskRelation struct {
+	ID        string       `json:"id"`
+	Type      RelationType `json:"type"`
+	SourceID  TaskID       `json:"source_id"`
+	TargetID  TaskID       `json:"target_id"`
+	CreatedBy UserID       `json:"created_by,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// ValidRelationType checks if a relation type is known.
+func ValidRelationType(t RelationType) bool {
+	switch t {
+	case RelationDependsOn, RelationDuplicateOf, RelationRelatesTo, RelationCausedBy, RelationSubtaskOf:
+		return true
+	}
+	return false
+}
+
+// NewTaskRelation creates a relation from source to target.
+//
+// Returns an error if the type is unknown or source equals target.
+func NewTaskRelation(typ RelationType, sourceID, targetID TaskID) (*TaskRelation, error) {
+	if !ValidRelationType(typ) || sourceID == "" || targetID == "" || sourceID == targetID {
+		return nil, ErrInvalidRelation
+	}
+	return &TaskRelation{
+		ID:        uuid.New().String(),
+		Type:      typ,
+		SourceID:  sourceID,
+		TargetID:  targetID,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Symmetric reports whether the relation reads the same in both directions.
+func (r *TaskRelation) Symmetric() bool {
+	return r.Type == RelationRelatesTo
+}
+
+// SameLink reports whether two relations link the same tasks with the
+// same type, taking symmetry into account.
+func (r *TaskRelation) SameLink(other *TaskRelation) bool {
+	if r.Type != other.Type {
+		return false
+	}
+	if r.SourceID == other.SourceID && r.TargetID == other.TargetID {
+		return true
+	}
+	return r.Symmetric() && r.SourceID == other.TargetID && r.TargetID == other.SourceID
+}
+
+// Involves reports whether the relation links the given task.
+func (r *TaskRelation) Involves(taskID TaskID) bool {
+	return r.SourceID == taskID || r.TargetID == taskID
+}
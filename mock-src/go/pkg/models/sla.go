@@ -0,0 +1,154 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// SLAState is the state of a single SLA timer on a task.
+type SLAState string
+
+const (
+	// SLAStateOK means the timer is running and within its target.
+	SLAStateOK SLAState = "ok"
+	// SLAStateAtRisk means the timer is running and past 80% of its target.
+	SLAStateAtRisk SLAState = "at_risk"
+	// SLAStateBreached means the target was missed.
+	SLAStateBreached SLAState = "breached"
+	// SLAStateMet means the target was achieved in time.
+	SLAStateMet SLAState = "met"
+)
+
+// slaAtRiskFraction is the fraction of a target after which a running
+// timer is reported as at risk.
+const slaAtRiskFraction = 0.8
+
+// ErrInvalidSLA is returned when an SLA definition is malformed.
+var ErrInvalidSLA = errors.New("invalid SLA definition")
+
+// SLA defines response and resolution targets for the tasks in a project.
+//
+// A zero target disables that timer. When BusinessHours is set, only
+// working time counts toward the targets.
+type SLA struct {
+	ProjectID          ProjectID         `json:"project_id"`
+	RespondWithinHours int               `json:"respond_within_hours,omitempty"`
+	ResolveWithinHours int               `json:"resolve_within_hours,omitempty"`
+	BusinessHours      *BusinessCalendar `json:"business_hours,omitempty"`
+}
+
+// Validate checks that the SLA has at least one non-negative target and
+// a valid calendar, if any.
+func (s *SLA) Validate() error {
+	if s.RespondWithinHours < 0 || s.ResolveWithinHours < 0 {
+		return ErrInvalidSLA
+	}
+	if s.RespondWithinHours == 0 && s.ResolveWithinHours == 0 {
+		return ErrInvalidSLA
+	}
+	if s.BusinessHours != nil {
+		return s.BusinessHours.Validate()
+	}
+	return nil
+}
+
+// SLATimer is the computed state of one SLA target on a task.
+type SLATimer struct {
+	State SLAState  `json:"state"`
+	Due   time.Time `json:"due"`
+}
+
+// SLAStatus is the computed SLA state of a task.
+type SLAStatus struct {
+	Respond *SLATimer `json:"respond,omitempty"`
+	Resolve *SLATimer `json:"resolve,omitempty"`
+}
+
+// Breached reports whether either timer was breached.
+func (s *SLAStatus) Breached() bool {
+	return (s.Respond != nil && s.Respond.State == SLAStateBreached) ||
+		(s.Resolve != nil && s.Resolve.State == SLAStateBreached)
+}
+
+// AtRisk reports whether either timer is at risk.
+func (s *SLAStatus) AtRisk() bool {
+	return (s.Respond != nil && s.Respond.State == SLAStateAtRisk) ||
+		(s.Resolve != nil && s.Resolve.State == SLAStateAtRisk)
+}
+
+// Summary returns the worst state across both timers.
+func (s *SLAStatus) Summary() SLAState {
+	switch {
+	case s.Breached():
+		return SLAStateBreached
+	case s.AtRisk():
+		return SLAStateAtRisk
+	}
+	for _, t := range []*SLATimer{s.Respond, s.Resolve} {
+		if t != nil && t.State == SLAStateOK {
+			return SLAStateOK
+		}
+	}
+	return SLAStateMet
+}
+
+// Evaluate computes the SLA status of a task at the given time.
+//
+// The response timer stops at the task's RespondedAt; the resolution
+// timer stops when the task is completed or cancelled.
+func (s *SLA) Evaluate(task *Task, at time.Time) *SLAStatus {
+	status := &SLAStatus{}
+	if s.RespondWithinHours > 0 {
+		status.Respond = s.timer(task.CreatedAt, task.RespondedAt, s.RespondWithinHours, at)
+	}
+	if s.ResolveWithinHours > 0 {
+		var resolvedAt *time.Time
+		if !task.IsOpen() {
+			t := task.StatusSince()
+			resolvedAt = &t
+		}
+		status.Resolve = s.timer(task.CreatedAt, resolvedAt, s.ResolveWithinHours, at)
+	}
+	return status
+}
+
+// timer computes one SLA timer started at start and stopped at stop, if set.
+func (s *SLA) timer(start time.Time, stop *time.Time, hours int, at time.Time) *SLATimer {
+	target := time.Duration(hours) * time.Hour
+	timer := &SLATimer{Due: s.deadline(start, target)}
+
+	if stop != nil {
+		timer.State = SLAStateMet
+		if stop.After(timer.Due) {
+			timer.State = SLAStateBreached
+		}
+		return timer
+	}
+
+	switch {
+	case at.After(timer.Due):
+		timer.State = SLAStateBreached
+	case float64(s.elapsed(start, at)) >= slaAtRiskFraction*float64(target):
+		timer.State = SLAStateAtRisk
+	default:
+		timer.State = SLAStateOK
+	}
+	return timer
+}
+
+// deadline returns when target of countable time will have passed after start.
+func (s *SLA) deadline(start time.Time, target time.Duration) time.Time {
+	if s.BusinessHours == nil {
+		return start.Add(target)
+	}
+	return s.BusinessHours.AddWorkingTime(start, target)
+}
+
+// elapsed returns the countable time between from and to.
+func (s *SLA) elapsed(from, to time.Time) time.Duration {
+	if s.BusinessHours == nil {
+		return to.Sub(from)
+	}
+	return s.BusinessHours.WorkingTime(from, to)
+}
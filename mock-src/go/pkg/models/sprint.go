@@ -0,0 +1,138 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidSprint is returned when a sprint is malformed.
+var ErrInvalidSprint = errors.New("invalid sprint")
+
+// EstimateUnit is the unit a sprint reads task estimates and member
+// capacities in.
+type EstimateUnit string
+
+const (
+	// EstimateHours reads estimates as hours of work. It is the default.
+	EstimateHours EstimateUnit = "hours"
+	// EstimatePoints reads estimates as story points.
+	EstimatePoints EstimateUnit = "points"
+)
+
+// MemberCapacity is how much work one member can take on in a sprint,
+// in the sprint's estimate unit.
+type MemberCapacity struct {
+	UserID   UserID  `json:"user_id"`
+	Capacity float64 `json:"capacity"`
+}
+
+// Sprint is a timeboxed iteration of a project with the tasks planned
+// for it and the capacity of each member taking part.
+type Sprint struct {
+	ID         string           `json:"id"`
+	ProjectID  ProjectID        `json:"project_id"`
+	Name       string           `json:"name"`
+	StartDate  time.Time        `json:"start_date"`
+	EndDate    time.Time        `json:"end_date"`
+	Unit       EstimateUnit     `json:"unit"`
+	Capacities []MemberCapacity `json:"capacities"`
+	TaskIDs    []TaskID         `json:"task_ids"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+// NewSprint creates an empty sprint for a project, estimated in hours.
+func NewSprint(projectID ProjectID, name string, start, end time.Time) *Sprint {
+	now := time.Now()
+	return &Sprint{
+		ID:         uuid.New().String(),
+		ProjectID:  projectID,
+		Name:       name,
+		StartDate:  start,
+		EndDate:    end,
+		Unit:       EstimateHours,
+		Capacities: make([]MemberCapacity, 0),
+		TaskIDs:    make([]TaskID, 0),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// EntityID returns the sprint's ID.
+func (s *Sprint) EntityID() string {
+	return s.ID
+}
+
+// Clone returns a deep copy of the sprint.
+func (s *Sprint) Clone() *Sprint {
+	c := *s
+	c.Capacities = slices.Clone(s.Capacities)
+	c.TaskIDs = slices.Clone(s.TaskIDs)
+	return &c
+}
+
+// Validate checks that the sprint has a name, ends after it starts, and
+// lists each member and task at most once with no negative capacity.
+func (s *Sprint) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidSprint)
+	}
+	if !s.EndDate.After(s.StartDate) {
+		return fmt.Errorf("%w: end_date must be after start_date", ErrInvalidSprint)
+	}
+	switch s.Unit {
+	case EstimateHours, EstimatePoints:
+	default:
+		return fmt.Errorf("%w: unit must be hours or points", ErrInvalidSprint)
+	}
+	members := make(map[UserID]bool, len(s.Capacities))
+	for _, c := range s.Capacities {
+		if c.UserID == "" || members[c.UserID] {
+			return fmt.Errorf("%w: each member must be listed once", ErrInvalidSprint)
+		}
+		if c.Capacity < 0 {
+			return fmt.Errorf("%w: capacity of %s must not be negative", ErrInvalidSprint, c.UserID)
+		}
+		members[c.UserID] = true
+	}
+	tasks := make(map[TaskID]bool, len(s.TaskIDs))
+	for _, id := range s.TaskIDs {
+		if tasks[id] {
+			return fmt.Errorf("%w: task %s is listed twice", ErrInvalidSprint, id)
+		}
+		tasks[id] = true
+	}
+	return nil
+}
+
+// CapacityOf returns a member's capacity in the sprint, and whether the
+// member takes part.
+func (s *Sprint) CapacityOf(userID UserID) (float64, bool) {
+	for _, c := range s.Capacities {
+		if c.UserID == userID {
+			return c.Capacity, true
+		}
+	}
+	return 0, false
+}
+
+// HasTask checks if a task is planned for the sprint.
+func (s *Sprint) HasTask(id TaskID) bool {
+	for _, t := range s.TaskIDs {
+		if t == id {
+			return true
+		}
+	}
+	return false
+}
+
+// HasEnded reports whether the sprint is over at t.
+func (s *Sprint) HasEnded(t time.Time) bool {
+	return !t.Before(s.EndDate)
+}
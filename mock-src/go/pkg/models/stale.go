@@ -0,0 +1,85 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidStalePolicy is returned when a stale policy is malformed.
+var ErrInvalidStalePolicy = errors.New("invalid stale policy")
+
+// StalePolicy decides when an open task has gone stale.
+//
+// A task is stale once it has gone without updates for the number of
+// days set for its status in Days; statuses missing from Days use
+// DefaultDays, and zero days never go stale. Notify sends the assignee a
+// nudge when a task goes stale. If TriageStatus is set, stale tasks are
+// also moved to it; it should be a status the projects' workflows
+// define, such as a custom "needs_triage".
+type StalePolicy struct {
+	Days         map[TaskStatus]int `json:"days"`
+	DefaultDays  int                `json:"default_days"`
+	Notify       bool               `json:"notify"`
+	TriageStatus TaskStatus         `json:"triage_status,omitempty"`
+}
+
+// DefaultStalePolicy returns the policy used until one is configured:
+// tasks in progress go stale after two weeks, others after thirty days,
+// and assignees are nudged.
+func DefaultStalePolicy() *StalePolicy {
+	return &StalePolicy{
+		Days: map[TaskStatus]int{
+			TaskStatusInProgress: 14,
+		},
+		DefaultDays: 30,
+		Notify:      true,
+	}
+}
+
+// Validate checks that day counts are not negative and that the triage
+// status is open and well formed.
+func (p *StalePolicy) Validate() error {
+	if p.DefaultDays < 0 {
+		return fmt.Errorf("%w: default_days must not be negative", ErrInvalidStalePolicy)
+	}
+	for status, days := range p.Days {
+		if days < 0 {
+			return fmt.Errorf("%w: days for %s must not be negative", ErrInvalidStalePolicy, status)
+		}
+	}
+	if p.TriageStatus != "" {
+		if !statusNameRegex.MatchString(string(p.TriageStatus)) {
+			return fmt.Errorf("%w: invalid triage status %q", ErrInvalidStalePolicy, p.TriageStatus)
+		}
+		if p.TriageStatus == TaskStatusCompleted || p.TriageStatus == TaskStatusCancelled || p.TriageStatus == TaskStatusAwaitingReview {
+			return fmt.Errorf("%w: triage status must be an open status", ErrInvalidStalePolicy)
+		}
+	}
+	return nil
+}
+
+// Threshold returns how long a task in status may go without updates,
+// or zero if tasks in that status never go stale.
+func (p *StalePolicy) Threshold(status TaskStatus) time.Duration {
+	days, ok := p.Days[status]
+	if !ok {
+		days = p.DefaultDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// IsStale reports whether a task has gone stale as of at. Closed tasks,
+// drafts and tasks awaiting review are never stale, nor are tasks
+// already in the triage status.
+func (p *StalePolicy) IsStale(t *Task, at time.Time) bool {
+	if !t.IsOpen() || t.Draft || t.Status == TaskStatusAwaitingReview {
+		return false
+	}
+	if p.TriageStatus != "" && t.Status == p.TriageStatus {
+		return false
+	}
+	threshold := p.Threshold(t.Status)
+	return threshold > 0 && at.Sub(t.UpdatedAt) >= threshold
+}
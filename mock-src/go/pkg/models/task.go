@@ -2,6 +2,8 @@
 package models
 
 import (
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -22,9 +24,14 @@ const (
 	TaskStatusCompleted TaskStatus = "completed"
 	// TaskStatusCancelled indicates the task has been cancelled.
 	TaskStatusCancelled TaskStatus = "cancelled"
+	// TaskStatusAwaitingReview indicates the task is done but its
+	// completion awaits approval.
+	TaskStatusAwaitingReview TaskStatus = "awaiting_review"
 )
 
-// TaskPriority represents the priority level of a task.
+// TaskPriority represents the priority level of a task as a rank;
+// higher ranks are more urgent. The constants below are the ranks of
+// the default priority scheme; projects may define their own.
 type TaskPriority int
 
 const (
@@ -38,61 +45,248 @@ const (
 	TaskPriorityCritical TaskPriority = 4
 )
 
+// ChecklistItem is a single step in a task's checklist.
+type ChecklistItem struct {
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
 // Task represents a task in the system.
 //
 // A task belongs to a project and can be assigned to a user.
 // Tasks have status and priority tracking with timestamps.
+// Version starts at 1 and is incremented by the store on every update,
+// for optimistic concurrency control. StatusChangedAt may be zero for
+// tasks whose status was set directly; see StatusSince. Rank orders
+// tasks manually within a project, independent of priority; see
+// RankBetween. A draft is visible only to CreatedBy until published; a
+// restricted task only to the users its visibility allows, see
+// IsVisibleTo.
+// ReviewerID is the user who checks the work, distinct from the
+// assignee who does it. ReviewFrom is the status a task awaiting review
+// returns to if its completion is rejected. Key is the task's
+// human-readable key, such as "API-142", if its project has a key.
 type Task struct {
-	ID          string       `json:"id"`
-	Title       string       `json:"title"`
-	Description string       `json:"description"`
-	ProjectID   string       `json:"project_id"`
-	AssigneeID  *string      `json:"assignee_id,omitempty"`
-	Status      TaskStatus   `json:"status"`
-	Priority    TaskPriority `json:"priority"`
-	CreatedAt   time.Time    `json:"created_at"`
-	UpdatedAt   time.Time    `json:"updated_at"`
-	DueDate     *time.Time   `json:"due_date,omitempty"`
-	Tags        []string     `json:"tags"`
+	ID              TaskID          `json:"id"`
+	Key             string          `json:"key,omitempty"`
+	Title           string          `json:"title"`
+	Description     string          `json:"description"`
+	ProjectID       ProjectID       `json:"project_id"`
+	AssigneeID      *UserID         `json:"assignee_id,omitempty"`
+	AssignedAt      time.Time       `json:"assigned_at,omitempty"`
+	Status          TaskStatus      `json:"status"`
+	Priority        TaskPriority    `json:"priority"`
+	Rank            string          `json:"rank,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+	StatusChangedAt time.Time       `json:"status_changed_at,omitempty"`
+	RespondedAt     *time.Time      `json:"responded_at,omitempty"`
+	BlockedReason   string          `json:"blocked_reason,omitempty"`
+	BlockedByTaskID *TaskID         `json:"blocked_by_task_id,omitempty"`
+	StartDate       *time.Time      `json:"start_date,omitempty"`
+	DueDate         *time.Time      `json:"due_date,omitempty"`
+	Tags            []string        `json:"tags"`
+	Checklist       []ChecklistItem `json:"checklist,omitempty"`
+	Estimate        float64         `json:"estimate,omitempty"`
+	Watchers        []UserID        `json:"watchers,omitempty"`
+	Voters          []UserID        `json:"voters,omitempty"`
+	CreatedBy       UserID          `json:"created_by,omitempty"`
+	Draft           bool            `json:"draft,omitempty"`
+	Visibility      TaskVisibility  `json:"visibility,omitempty"`
+	AllowedUserIDs  []UserID        `json:"allowed_user_ids,omitempty"`
+	ReviewerID      *UserID         `json:"reviewer_id,omitempty"`
+	ReviewFrom      TaskStatus      `json:"review_from,omitempty"`
+	RejectionReason string          `json:"rejection_reason,omitempty"`
+	Version         int             `json:"version"`
 }
 
 // NewTask creates a new task with the given title and project ID.
 //
 // The task is initialized with pending status, medium priority,
 // and current timestamps.
-func NewTask(title, projectID string) *Task {
+func NewTask(title string, projectID ProjectID) *Task {
 	now := time.Now()
 	return &Task{
-		ID:        uuid.New().String(),
-		Title:     title,
-		ProjectID: projectID,
-		Status:    TaskStatusPending,
-		Priority:  TaskPriorityMedium,
-		CreatedAt: now,
-		UpdatedAt: now,
-		Tags:      make([]string, 0),
+		ID:              NewTaskID(),
+		Title:           title,
+		ProjectID:       projectID,
+		Status:          TaskStatusPending,
+		Priority:        TaskPriorityMedium,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		Tags:            make([]string, 0),
+		Version:         1,
+		StatusChangedAt: now,
 	}
 }
 
 // MarkComplete marks the task as completed and updates the timestamp.
 func (t *Task) MarkComplete() {
 	t.Status = TaskStatusCompleted
+	t.BlockedReason = ""
+	t.BlockedByTaskID = nil
 	t.UpdatedAt = time.Now()
+	t.StatusChangedAt = t.UpdatedAt
+	t.MarkResponded(t.UpdatedAt)
 }
 
 // MarkBlocked marks the task as blocked with an optional reason.
 func (t *Task) MarkBlocked(reason string) {
+	t.BlockOn(reason, nil)
+}
+
+// BlockOn marks the task as blocked with an optional reason and an
+// optional reference to the task blocking it.
+func (t *Task) BlockOn(reason string, blockingTaskID *TaskID) {
 	t.Status = TaskStatusBlocked
+	t.BlockedReason = reason
+	t.BlockedByTaskID = copyPtr(blockingTaskID)
+	t.UpdatedAt = time.Now()
+	t.StatusChangedAt = t.UpdatedAt
+	t.MarkResponded(t.UpdatedAt)
+}
+
+// Unblock clears the blocked reason and returns the task to in progress.
+//
+// Returns false if the task was not blocked.
+func (t *Task) Unblock() bool {
+	if t.Status != TaskStatusBlocked {
+		return false
+	}
+	t.Status = TaskStatusInProgress
+	t.BlockedReason = ""
+	t.BlockedByTaskID = nil
+	t.UpdatedAt = time.Now()
+	t.StatusChangedAt = t.UpdatedAt
+	return true
+}
+
+// SetStatus moves the task to a status, such as a custom workflow
+// status, clearing the blocked reason when it leaves blocked.
+//
+// Returns false if the task already has the status, or if the move is
+// into or out of review; see SubmitForReview, Approve and Reject.
+func (t *Task) SetStatus(status TaskStatus) bool {
+	if t.Status == status || t.Status == TaskStatusAwaitingReview || status == TaskStatusAwaitingReview {
+		return false
+	}
+	if t.Status == TaskStatusBlocked {
+		t.BlockedReason = ""
+		t.BlockedByTaskID = nil
+	}
+	t.Status = status
+	t.UpdatedAt = time.Now()
+	t.StatusChangedAt = t.UpdatedAt
+	t.MarkResponded(t.UpdatedAt)
+	return true
+}
+
+// SubmitForReview moves an open task to awaiting review, remembering
+// its status for a rejection to return to. A blocked task returns to
+// in progress.
+//
+// Returns false if the task is closed or already awaiting review.
+func (t *Task) SubmitForReview() bool {
+	if !t.IsOpen() || t.Status == TaskStatusAwaitingReview {
+		return false
+	}
+	t.ReviewFrom = t.Status
+	if t.Status == TaskStatusBlocked {
+		t.ReviewFrom = TaskStatusInProgress
+		t.BlockedReason = ""
+		t.BlockedByTaskID = nil
+	}
+	t.RejectionReason = ""
+	t.Status = TaskStatusAwaitingReview
 	t.UpdatedAt = time.Now()
-	if reason != "" {
-		t.Description = t.Description + "\n\nBlocked: " + reason
+	t.StatusChangedAt = t.UpdatedAt
+	t.MarkResponded(t.UpdatedAt)
+	return true
+}
+
+// Approve completes a task awaiting review.
+//
+// Returns false if the task is not awaiting review.
+func (t *Task) Approve() bool {
+	if t.Status != TaskStatusAwaitingReview {
+		return false
+	}
+	t.ReviewFrom = ""
+	t.MarkComplete()
+	return true
+}
+
+// Reject returns a task awaiting review to the status it was submitted
+// from, recording why.
+//
+// Returns false if the task is not awaiting review.
+func (t *Task) Reject(reason string) bool {
+	if t.Status != TaskStatusAwaitingReview {
+		return false
+	}
+	to := t.ReviewFrom
+	if to == "" {
+		to = TaskStatusInProgress
+	}
+	t.Status = to
+	t.ReviewFrom = ""
+	t.RejectionReason = reason
+	t.UpdatedAt = time.Now()
+	t.StatusChangedAt = t.UpdatedAt
+	return true
+}
+
+// MarkResponded records the first response to the task, such as a
+// status change or comment, for SLA tracking.
+//
+// Returns true if this was the first response.
+func (t *Task) MarkResponded(at time.Time) bool {
+	if t.RespondedAt != nil {
+		return false
 	}
+	t.RespondedAt = &at
+	return true
 }
 
-// AssignTo assigns the task to a user.
-func (t *Task) AssignTo(userID string) {
+// AssignTo assigns the task to a user, who also starts watching it.
+func (t *Task) AssignTo(userID UserID) {
 	t.AssigneeID = &userID
+	t.Watch(userID)
 	t.UpdatedAt = time.Now()
+	t.AssignedAt = t.UpdatedAt
+}
+
+// Unassign clears the task's assignee.
+func (t *Task) Unassign() {
+	t.AssigneeID = nil
+	t.AssignedAt = time.Time{}
+	t.UpdatedAt = time.Now()
+}
+
+// SetReviewer makes a user the task's reviewer, who also starts
+// watching it.
+//
+// Returns false if the user is already the reviewer.
+func (t *Task) SetReviewer(userID UserID) bool {
+	if t.ReviewerID != nil && *t.ReviewerID == userID {
+		return false
+	}
+	t.ReviewerID = &userID
+	t.Watch(userID)
+	t.UpdatedAt = time.Now()
+	return true
+}
+
+// ClearReviewer removes the task's reviewer.
+//
+// Returns false if the task has no reviewer.
+func (t *Task) ClearReviewer() bool {
+	if t.ReviewerID == nil {
+		return false
+	}
+	t.ReviewerID = nil
+	t.UpdatedAt = time.Now()
+	return true
 }
 
 // AddTag adds a tag to the task.
@@ -125,12 +319,143 @@ func (t *Task) RemoveTag(tag string) bool {
 	return false
 }
 
+// Watch subscribes a user to the task's activity.
+//
+// Returns true if the user was added, false if already watching.
+func (t *Task) Watch(userID UserID) bool {
+	if t.IsWatchedBy(userID) {
+		return false
+	}
+	t.Watchers = append(t.Watchers, userID)
+	t.UpdatedAt = time.Now()
+	return true
+}
+
+// Unwatch unsubscribes a user from the task's activity.
+//
+// Returns true if the user was removed, false if not watching.
+func (t *Task) Unwatch(userID UserID) bool {
+	for i, id := range t.Watchers {
+		if id == userID {
+			t.Watchers = append(t.Watchers[:i], t.Watchers[i+1:]...)
+			t.UpdatedAt = time.Now()
+			return true
+		}
+	}
+	return false
+}
+
+// IsWatchedBy checks if a user is watching the task.
+func (t *Task) IsWatchedBy(userID UserID) bool {
+	for _, id := range t.Watchers {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Publish makes a draft visible to everyone.
+//
+// Returns true if the task was a draft, false otherwise.
+func (t *Task) Publish() bool {
+	if !t.Draft {
+		return false
+	}
+	t.Draft = false
+	t.UpdatedAt = time.Now()
+	return true
+}
+
+// Vote records a user's vote for the task.
+//
+// Returns true if the vote was added, false if the user already voted.
+func (t *Task) Vote(userID UserID) bool {
+	if t.HasVoted(userID) {
+		return false
+	}
+	t.Voters = append(t.Voters, userID)
+	t.UpdatedAt = time.Now()
+	return true
+}
+
+// Unvote withdraws a user's vote for the task.
+//
+// Returns true if the vote was removed, false if the user had not voted.
+func (t *Task) Unvote(userID UserID) bool {
+	for i, id := range t.Voters {
+		if id == userID {
+			t.Voters = append(t.Voters[:i], t.Voters[i+1:]...)
+			t.UpdatedAt = time.Now()
+			return true
+		}
+	}
+	return false
+}
+
+// HasVoted checks if a user has voted for the task.
+func (t *Task) HasVoted(userID UserID) bool {
+	for _, id := range t.Voters {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// SortByVotes orders tasks by descending vote count, breaking ties by
+// descending priority and then by creation time.
+func SortByVotes(tasks []*Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if len(a.Voters) != len(b.Voters) {
+			return len(a.Voters) > len(b.Voters)
+		}
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+}
+
 // IsOverdue checks if the task is past its due date.
 func (t *Task) IsOverdue() bool {
+	return t.IsOverdueAt(time.Now())
+}
+
+// IsOverdueAt checks if the task was past its due date at the given time.
+func (t *Task) IsOverdueAt(at time.Time) bool {
 	if t.DueDate == nil {
 		return false
 	}
-	return time.Now().After(*t.DueDate) && t.Status != TaskStatusCompleted
+	return at.After(*t.DueDate) && t.Status != TaskStatusCompleted
+}
+
+// StatusSince returns when the task entered its current status, falling
+// back to UpdatedAt if that was not recorded.
+func (t *Task) StatusSince() time.Time {
+	if t.StatusChangedAt.IsZero() {
+		return t.UpdatedAt
+	}
+	return t.StatusChangedAt
+}
+
+// Progress returns the fraction of the task that is done: 1 when
+// completed, otherwise the fraction of checklist items checked.
+func (t *Task) Progress() float64 {
+	if t.Status == TaskStatusCompleted {
+		return 1
+	}
+	if len(t.Checklist) == 0 {
+		return 0
+	}
+	done := 0
+	for _, item := range t.Checklist {
+		if item.Done {
+			done++
+		}
+	}
+	return float64(done) / float64(len(t.Checklist))
 }
 
 // IsActive checks if the task is in an active state.
@@ -138,9 +463,106 @@ func (t *Task) IsActive() bool {
 	return t.Status == TaskStatusPending || t.Status == TaskStatusInProgress
 }
 
+// IsOpen checks if the task has not been completed or cancelled.
+func (t *Task) IsOpen() bool {
+	return t.Status != TaskStatusCompleted && t.Status != TaskStatusCancelled
+}
+
+// ValidateID checks if an ID is a UUID in canonical lowercase form.
+func ValidateID(id string) bool {
+	parsed, err := uuid.Parse(id)
+	return err == nil && parsed.String() == id
+}
+
+// SanitizeContent cleans the title and description in place and checks
+// them against the configured content limits.
+func (t *Task) SanitizeContent() error {
+	title, err := SanitizeTitle(t.Title)
+	if err != nil {
+		return err
+	}
+	description, err := SanitizeDescription(t.Description)
+	if err != nil {
+		return err
+	}
+	t.Title, t.Description = title, description
+	return nil
+}
+
+// Clone returns a deep copy of the task.
+func (t *Task) Clone() *Task {
+	c := *t
+	c.AssigneeID = copyPtr(t.AssigneeID)
+	c.ReviewerID = copyPtr(t.ReviewerID)
+	c.StartDate = copyTimePtr(t.StartDate)
+	c.DueDate = copyTimePtr(t.DueDate)
+	c.RespondedAt = copyTimePtr(t.RespondedAt)
+	c.BlockedByTaskID = copyPtr(t.BlockedByTaskID)
+	// slices.Clone keeps empty slices empty rather than nil, so a copy
+	// serializes exactly like the original.
+	c.Tags = slices.Clone(t.Tags)
+	c.Checklist = slices.Clone(t.Checklist)
+	c.Watchers = slices.Clone(t.Watchers)
+	c.Voters = slices.Clone(t.Voters)
+	c.AllowedUserIDs = slices.Clone(t.AllowedUserIDs)
+	return &c
+}
+
+// DuplicateOptions selects which optional parts of a task are copied
+// by Duplicate.
+type DuplicateOptions struct {
+	ProjectID        ProjectID `json:"project_id,omitempty"`
+	IncludeChecklist bool      `json:"include_checklist,omitempty"`
+	IncludeTags      bool      `json:"include_tags,omitempty"`
+	IncludeAssignee  bool      `json:"include_assignee,omitempty"`
+}
+
+// Duplicate returns a new pending task with a fresh ID and timestamps
+// that copies the title, description, priority, schedule and estimate.
+//
+// Checklist items are copied unchecked. An empty opts.ProjectID keeps
+// the task's project.
+func (t *Task) Duplicate(opts DuplicateOptions) *Task {
+	projectID := opts.ProjectID
+	if projectID == "" {
+		projectID = t.ProjectID
+	}
+
+	dup := NewTask(t.Title, projectID)
+	dup.Description = t.Description
+	dup.Priority = t.Priority
+	dup.StartDate = copyTimePtr(t.StartDate)
+	dup.DueDate = copyTimePtr(t.DueDate)
+	dup.Estimate = t.Estimate
+	if opts.IncludeChecklist {
+		for _, item := range t.Checklist {
+			dup.Checklist = append(dup.Checklist, ChecklistItem{Text: item.Text})
+		}
+	}
+	if opts.IncludeTags {
+		dup.Tags = append(dup.Tags, t.Tags...)
+	}
+	if opts.IncludeAssignee {
+		dup.AssigneeID = copyPtr(t.AssigneeID)
+		if dup.AssigneeID != nil {
+			dup.AssignedAt = dup.CreatedAt
+		}
+	}
+	return dup
+}
+
 // TaskOption is a function that configures a Task.
 type TaskOption func(*Task)
 
+// WithID sets a caller-supplied task ID in place of a generated one.
+//
+// The caller is responsible for validating the ID with ValidateID.
+func WithID(id TaskID) TaskOption {
+	return func(t *Task) {
+		t.ID = id
+	}
+}
+
 // WithDescription sets the task description.
 func WithDescription(description string) TaskOption {
 	return func(t *Task) {
@@ -149,7 +571,7 @@ func WithDescription(description string) TaskOption {
 }
 
 // WithAssignee sets the task assignee.
-func WithAssignee(userID string) TaskOption {
+func WithAssignee(userID UserID) TaskOption {
 	return func(t *Task) {
 		t.AssigneeID = &userID
 	}
@@ -169,6 +591,30 @@ func WithDueDate(dueDate time.Time) TaskOption {
 	}
 }
 
+// WithStartDate sets the date work on the task is scheduled to start.
+func WithStartDate(startDate time.Time) TaskOption {
+	return func(t *Task) {
+		t.StartDate = &startDate
+	}
+}
+
+// WithEstimate sets the task estimate.
+func WithEstimate(estimate float64) TaskOption {
+	return func(t *Task) {
+		t.Estimate = estimate
+	}
+}
+
+// WithChecklist sets the task checklist from item texts.
+func WithChecklist(items []string) TaskOption {
+	return func(t *Task) {
+		t.Checklist = make([]ChecklistItem, len(items))
+		for i, text := range items {
+			t.Checklist[i] = ChecklistItem{Text: text}
+		}
+	}
+}
+
 // WithTags sets the task tags.
 func WithTags(tags []string) TaskOption {
 	return func(t *Task) {
@@ -180,7 +626,7 @@ func WithTags(tags []string) TaskOption {
 }
 
 // NewTaskWithOptions creates a new task with optional configurations.
-func NewTaskWithOptions(title, projectID string, opts ...TaskOption) *Task {
+func NewTaskWithOptions(title string, projectID ProjectID, opts ...TaskOption) *Task {
 	task := NewTask(title, projectID)
 	for _, opt := range opts {
 		opt(task)
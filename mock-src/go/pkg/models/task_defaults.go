@@ -0,0 +1,111 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidTaskDefaults is returned when task defaults are malformed.
+var ErrInvalidTaskDefaults = errors.New("invalid task defaults")
+
+// maxDefaultDueInDays caps how far out a default due date may be.
+const maxDefaultDueInDays = 3650
+
+// TaskDefaults are the values given to new tasks that do not set them.
+//
+// A zero Priority leaves the choice to the project's priority scheme,
+// or medium. DueInDays, if set, makes new tasks due that many calendar
+// days after they are created.
+type TaskDefaults struct {
+	Priority  TaskPriority `json:"priority,omitempty"`
+	Tags      []string     `json:"tags"`
+	DueInDays *int         `json:"due_in_days,omitempty"`
+}
+
+// DefaultTaskDefaults returns the defaults used until some are
+// configured: none beyond those of NewTask.
+func DefaultTaskDefaults() *TaskDefaults {
+	return &TaskDefaults{Tags: make([]string, 0)}
+}
+
+// Validate normalizes the tags and checks that the priority is not
+// negative, that tags are not blank and that the due offset is between
+// zero and ten years.
+func (d *TaskDefaults) Validate() error {
+	if d.Priority < 0 {
+		return fmt.Errorf("%w: priority cannot be negative", ErrInvalidTaskDefaults)
+	}
+	tags, err := normalizeDefaultTags(d.Tags)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTaskDefaults, err)
+	}
+	d.Tags = tags
+	if d.DueInDays != nil && (*d.DueInDays < 0 || *d.DueInDays > maxDefaultDueInDays) {
+		return fmt.Errorf("%w: due_in_days must be between 0 and %d", ErrInvalidTaskDefaults, maxDefaultDueInDays)
+	}
+	return nil
+}
+
+// normalizeDefaultTags lowercases and trims tags as Task.AddTag does,
+// dropping repeats. It fails on a blank tag.
+func normalizeDefaultTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			return nil, errors.New("tags cannot be blank")
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
+
+// Override returns the defaults with a project's settings laid over
+// them: each value the project sets replaces the global one.
+func (d *TaskDefaults) Override(settings *ProjectSettings) *TaskDefaults {
+	merged := *d
+	if settings.DefaultPriority > 0 {
+		merged.Priority = settings.DefaultPriority
+	}
+	if len(settings.DefaultTags) > 0 {
+		merged.Tags = settings.DefaultTags
+	}
+	if settings.DefaultDueInDays != nil {
+		merged.DueInDays = settings.DefaultDueInDays
+	}
+	return &merged
+}
+
+// Apply gives a new task the defaults for what it leaves unset: a zero
+// priority, no tags or no due date. A default priority scheme does not
+// define gives way to the scheme's default; without a scheme, a task
+// with neither gets medium. A default due date before the task's start
+// date is dropped. scheme may be nil.
+func (d *TaskDefaults) Apply(task *Task, scheme *PriorityScheme) {
+	if task.Priority == 0 {
+		task.Priority = d.Priority
+		switch {
+		case scheme != nil && (task.Priority == 0 || !scheme.Has(task.Priority)):
+			task.Priority = scheme.Default
+		case task.Priority == 0:
+			task.Priority = TaskPriorityMedium
+		}
+	}
+	if len(task.Tags) == 0 {
+		for _, tag := range d.Tags {
+			task.AddTag(tag)
+		}
+	}
+	if task.DueDate == nil && d.DueInDays != nil {
+		due := task.CreatedAt.AddDate(0, 0, *d.DueInDays)
+		if task.StartDate == nil || !due.Before(*task.StartDate) {
+			task.DueDate = &due
+		}
+	}
+}
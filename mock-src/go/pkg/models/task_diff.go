@@ -0,0 +1,113 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// TaskFieldChange is the change of a single task field, named by its
+// JSON key.
+//
+// Old and New share memory with the tasks they were taken from.
+type TaskFieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// TaskChanges is a field-level change set between two versions of a task.
+type TaskChanges []TaskFieldChange
+
+// Fields returns the names of the changed fields.
+func (c TaskChanges) Fields() []string {
+	fields := make([]string, len(c))
+	for i, change := range c {
+		fields[i] = change.Field
+	}
+	return fields
+}
+
+// Has reports whether a field changed.
+func (c TaskChanges) Has(field string) bool {
+	for _, change := range c {
+		if change.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// taskField is an exported Task field and its JSON name.
+type taskField struct {
+	index int
+	name  string
+}
+
+// taskFields lists the Task fields compared by DiffTasks, in
+// declaration order.
+var taskFields = func() []taskField {
+	typ := reflect.TypeOf(Task{})
+	fields := make([]taskField, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, taskField{index: i, name: name})
+	}
+	return fields
+}()
+
+// DiffTasks returns the fields that differ between before and after, in
+// declaration order.
+//
+// Times are compared as instants, and nil and empty slices are equal.
+func DiffTasks(before, after *Task) TaskChanges {
+	b, a := reflect.ValueOf(before).Elem(), reflect.ValueOf(after).Elem()
+	var changes TaskChanges
+	for _, f := range taskFields {
+		old, cur := b.Field(f.index), a.Field(f.index)
+		if !equalValue(old, cur) {
+			changes = append(changes, TaskFieldChange{Field: f.name, Old: old.Interface(), New: cur.Interface()})
+		}
+	}
+	return changes
+}
+
+// Equal reports whether two tasks have the same field values, as
+// compared by DiffTasks.
+func (t *Task) Equal(other *Task) bool {
+	if t == nil || other == nil {
+		return t == other
+	}
+	return len(DiffTasks(t, other)) == 0
+}
+
+// equalValue compares two values of the same type, treating times as
+// instants and nil and empty slices alike.
+func equalValue(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Slice:
+		if a.Len() == 0 && b.Len() == 0 {
+			return true
+		}
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return equalValue(a.Elem(), b.Elem())
+	}
+	if t, ok := a.Interface().(time.Time); ok {
+		return t.Equal(b.Interface().(time.Time))
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
@@ -0,0 +1,183 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"reflect"
+	"time"
+)
+
+// TaskEventType identifies the kind of change recorded by a task event.
+type TaskEventType string
+
+const (
+	// TaskEventCreated records the creation of a task with its initial state.
+	TaskEventCreated TaskEventType = "created"
+	// TaskEventRetitled records a change of title.
+	TaskEventRetitled TaskEventType = "retitled"
+	// TaskEventDescriptionChanged records a change of description.
+	TaskEventDescriptionChanged TaskEventType = "description_changed"
+	// TaskEventStatusChanged records a status transition.
+	TaskEventStatusChanged TaskEventType = "status_changed"
+	// TaskEventPriorityChanged records a change of priority.
+	TaskEventPriorityChanged TaskEventType = "priority_changed"
+	// TaskEventAssigned records a change of assignee, including unassignment.
+	TaskEventAssigned TaskEventType = "assigned"
+	// TaskEventDueDateChanged records a change of due date.
+	TaskEventDueDateChanged TaskEventType = "due_date_changed"
+	// TaskEventTagsChanged records a change to the tag set.
+	TaskEventTagsChanged TaskEventType = "tags_changed"
+	// TaskEventUpdated records any other change, carrying the full new state.
+	TaskEventUpdated TaskEventType = "updated"
+	// TaskEventDeleted records the deletion of a task.
+	TaskEventDeleted TaskEventType = "deleted"
+)
+
+// TaskEvent is an immutable record of a single change to a task.
+//
+// Only the fields relevant to the event type are set. Version numbers
+// start at 1 for the created event and increase by one per event;
+// TaskVersion is the task's own version after the change. ActorID is
+// the user who made the change, if known.
+type TaskEvent struct {
+	TaskID      TaskID        `json:"task_id"`
+	Version     int           `json:"version"`
+	TaskVersion int           `json:"task_version,omitempty"`
+	Type        TaskEventType `json:"type"`
+	ActorID     UserID        `json:"actor_id,omitempty"`
+	OccurredAt  time.Time     `json:"occurred_at"`
+	State       *Task         `json:"state,omitempty"`
+	Title       string        `json:"title,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Status      TaskStatus    `json:"status,omitempty"`
+	Priority    TaskPriority  `json:"priority,omitempty"`
+	AssigneeID  *UserID       `json:"assignee_id,omitempty"`
+	DueDate     *time.Time    `json:"due_date,omitempty"`
+	Tags        []string      `json:"tags,omitempty"`
+}
+
+// Apply returns the state of a task after the event.
+//
+// The given task is not modified. Applying a created or updated event
+// ignores the previous state; applying a deleted event returns nil.
+func (e *TaskEvent) Apply(task *Task) *Task {
+	switch e.Type {
+	case TaskEventCreated, TaskEventUpdated:
+		return e.State.Clone()
+	case TaskEventDeleted:
+		return nil
+	}
+	if task == nil {
+		return nil
+	}
+
+	next := task.Clone()
+	next.UpdatedAt = e.OccurredAt
+	if e.TaskVersion > 0 {
+		next.Version = e.TaskVersion
+	}
+	switch e.Type {
+	case TaskEventRetitled:
+		next.Title = e.Title
+	case TaskEventDescriptionChanged:
+		next.Description = e.Description
+	case TaskEventStatusChanged:
+		next.Status = e.Status
+		next.StatusChangedAt = e.OccurredAt
+	case TaskEventPriorityChanged:
+		next.Priority = e.Priority
+	case TaskEventAssigned:
+		next.AssigneeID = copyPtr(e.AssigneeID)
+	case TaskEventDueDateChanged:
+		next.DueDate = copyTimePtr(e.DueDate)
+	case TaskEventTagsChanged:
+		next.Tags = append([]string(nil), e.Tags...)
+	}
+	return next
+}
+
+// DiffTaskEvents returns the events that turn before into after.
+//
+// Changes to tracked fields produce one typed event each, stamped with
+// after.UpdatedAt. If any other field also changed, a single updated
+// event with the full state is emitted instead, so replay always
+// reproduces after exactly.
+func DiffTaskEvents(before, after *Task) []*TaskEvent {
+	var events []*TaskEvent
+	add := func(e *TaskEvent) {
+		e.TaskID = after.ID
+		e.TaskVersion = after.Version
+		e.OccurredAt = after.UpdatedAt
+		events = append(events, e)
+	}
+
+	if before.Title != after.Title {
+		add(&TaskEvent{Type: TaskEventRetitled, Title: after.Title})
+	}
+	if before.Description != after.Description {
+		add(&TaskEvent{Type: TaskEventDescriptionChanged, Description: after.Description})
+	}
+	if before.Status != after.Status {
+		add(&TaskEvent{Type: TaskEventStatusChanged, Status: after.Status})
+	}
+	if before.Priority != after.Priority {
+		add(&TaskEvent{Type: TaskEventPriorityChanged, Priority: after.Priority})
+	}
+	if !equalPtr(before.AssigneeID, after.AssigneeID) {
+		add(&TaskEvent{Type: TaskEventAssigned, AssigneeID: copyPtr(after.AssigneeID)})
+	}
+	if !equalTimePtr(before.DueDate, after.DueDate) {
+		add(&TaskEvent{Type: TaskEventDueDateChanged, DueDate: copyTimePtr(after.DueDate)})
+	}
+	if !equalValue(reflect.ValueOf(before.Tags), reflect.ValueOf(after.Tags)) {
+		add(&TaskEvent{Type: TaskEventTagsChanged, Tags: append([]string(nil), after.Tags...)})
+	}
+
+	// Replaying the typed events must reproduce after exactly; if some
+	// other field changed, record the whole state instead.
+	replayed := before
+	for _, e := range events {
+		replayed = e.Apply(replayed)
+	}
+	replayed = replayed.Clone()
+	replayed.UpdatedAt = after.UpdatedAt
+	replayed.Version = after.Version
+	if !replayed.Equal(after) {
+		events = nil
+		add(&TaskEvent{Type: TaskEventUpdated, State: after.Clone()})
+	}
+	return events
+}
+
+// equalPtr reports whether two optional values are equal.
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// equalTimePtr reports whether two optional times are equal.
+func equalTimePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// copyPtr returns a pointer to a copy of *p, or nil.
+func copyPtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// copyTimePtr returns a pointer to a copy of *t, or nil.
+func copyTimePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := *t
+	return &v
+}
@@ -0,0 +1,28 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatTaskKey returns the key of the nth task of a project, such as
+// "API-142".
+func FormatTaskKey(projectKey string, n int) string {
+	return fmt.Sprintf("%s-%d", projectKey, n)
+}
+
+// ParseTaskKey splits a task key into its project key and number. It
+// accepts lowercase keys, returning the project key in uppercase.
+func ParseTaskKey(key string) (string, int, bool) {
+	prefix, num, ok := strings.Cut(strings.ToUpper(key), "-")
+	if !ok || ValidateProjectKey(prefix) != nil || num == "" || num[0] == '0' {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil || n <= 0 {
+		return "", 0, false
+	}
+	return prefix, n, true
+}
@@ -0,0 +1,121 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var templateVarRegex = regexp.MustCompile(`\{([a-z][a-z0-9_]*)\}`)
+
+// ErrInvalidTemplate is returned when a template has no name or title pattern.
+var ErrInvalidTemplate = errors.New("template name and title pattern are required")
+
+// ErrMissingTemplateVar is returned when instantiating a template
+// without a value for one of its title variables.
+var ErrMissingTemplateVar = errors.New("missing template variable")
+
+// TaskTemplate describes a reusable blueprint for creating tasks.
+//
+// TitlePattern may contain {variable} placeholders that are filled in
+// when the template is instantiated; {date} defaults to today's date.
+// Templates with an empty ProjectID are global and can be used in any
+// project; others belong to their project's library.
+type TaskTemplate struct {
+	ID           string       `json:"id"`
+	Name         string       `json:"name"`
+	ProjectID    ProjectID    `json:"project_id,omitempty"`
+	TitlePattern string       `json:"title_pattern"`
+	Description  string       `json:"description,omitempty"`
+	Checklist    []string     `json:"checklist,omitempty"`
+	Tags         []string     `json:"tags"`
+	Priority     TaskPriority `json:"priority"`
+	Estimate     float64      `json:"estimate,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+// NewTaskTemplate creates a new template with the given name and title pattern.
+//
+// Returns an error if either is blank.
+func NewTaskTemplate(name, titlePattern string) (*TaskTemplate, error) {
+	if strings.TrimSpace(name) == "" || strings.TrimSpace(titlePattern) == "" {
+		return nil, ErrInvalidTemplate
+	}
+
+	return &TaskTemplate{
+		ID:           uuid.New().String(),
+		Name:         name,
+		TitlePattern: titlePattern,
+		Tags:         make([]string, 0),
+		Priority:     TaskPriorityMedium,
+		CreatedAt:    time.Now(),
+	}, nil
+}
+
+// EntityID returns the template's ID.
+func (tt *TaskTemplate) EntityID() string {
+	return tt.ID
+}
+
+// Clone returns a deep copy of the task template.
+func (tt *TaskTemplate) Clone() *TaskTemplate {
+	c := *tt
+	c.Checklist = slices.Clone(tt.Checklist)
+	c.Tags = slices.Clone(tt.Tags)
+	return &c
+}
+
+// Variables returns the placeholder names used in the title pattern.
+func (tt *TaskTemplate) Variables() []string {
+	matches := templateVarRegex.FindAllStringSubmatch(tt.TitlePattern, -1)
+	seen := make(map[string]bool, len(matches))
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// AppliesTo reports whether the template may be used in a project.
+func (tt *TaskTemplate) AppliesTo(projectID ProjectID) bool {
+	return tt.ProjectID == "" || tt.ProjectID == projectID
+}
+
+// Instantiate creates a new task in a project from the template.
+//
+// Returns an error wrapping ErrMissingTemplateVar if the title pattern
+// uses a variable that vars does not provide.
+func (tt *TaskTemplate) Instantiate(projectID ProjectID, vars map[string]string) (*Task, error) {
+	var missing []string
+	title := templateVarRegex.ReplaceAllStringFunc(tt.TitlePattern, func(match string) string {
+		name := match[1 : len(match)-1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		if name == "date" {
+			return time.Now().Format("2006-01-02")
+		}
+		missing = append(missing, name)
+		return match
+	})
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrMissingTemplateVar, strings.Join(missing, ", "))
+	}
+
+	return NewTaskWithOptions(title, projectID,
+		WithDescription(tt.Description),
+		WithPriority(tt.Priority),
+		WithTags(tt.Tags),
+		WithChecklist(tt.Checklist),
+		WithEstimate(tt.Estimate),
+	), nil
+}
@@ -34,19 +34,74 @@ var ErrInvalidEmail = errors.New("invalid email format")
 // ErrInvalidUsername is returned when a username is invalid.
 var ErrInvalidUsername = errors.New("invalid username format")
 
+// ErrUserInactive is returned when a deactivated user attempts to log in.
+var ErrUserInactive = errors.New("user is deactivated")
+
+// ErrInvalidRole is returned when a role is not one of the known roles.
+var ErrInvalidRole = errors.New("invalid role")
+
+// roleHierarchy orders roles from least to most privileged.
+var roleHierarchy = map[UserRole]int{
+	UserRoleViewer: 0,
+	UserRoleMember: 1,
+	UserRoleAdmin:  2,
+	UserRoleOwner:  3,
+}
+
+// ValidRole checks if a role is one of the known roles.
+func ValidRole(role UserRole) bool {
+	_, ok := roleHierarchy[role]
+	return ok
+}
+
 // User represents a user in the system.
 //
 // Users can be assigned to tasks and projects. They have roles
 // that determine their access level.
 type User struct {
-	ID          string     `json:"id"`
-	Username    string     `json:"username"`
-	Email       string     `json:"email"`
-	DisplayName string     `json:"display_name"`
-	Role        UserRole   `json:"role"`
-	IsActive    bool       `json:"is_active"`
-	CreatedAt   time.Time  `json:"created_at"`
-	LastLogin   *time.Time `json:"last_login,omitempty"`
+	ID          UserID          `json:"id"`
+	Username    string          `json:"username"`
+	Email       string          `json:"email"`
+	DisplayName string          `json:"display_name"`
+	AvatarURL   string          `json:"avatar_url,omitempty"`
+	Role        UserRole        `json:"role"`
+	IsActive    bool            `json:"is_active"`
+	CreatedAt   time.Time       `json:"created_at"`
+	LastLogin   *time.Time      `json:"last_login,omitempty"`
+	Preferences UserPreferences `json:"preferences"`
+	Capacity    *UserCapacity   `json:"capacity,omitempty"`
+}
+
+// UserCapacity is the workload a user can take on. A zero limit is unlimited.
+type UserCapacity struct {
+	MaxOpenTasks int     `json:"max_open_tasks,omitempty"`
+	MaxEstimate  float64 `json:"max_estimate,omitempty"`
+}
+
+// ErrInvalidCapacity is returned when a capacity limit is negative.
+var ErrInvalidCapacity = errors.New("capacity limits cannot be negative")
+
+// Validate checks that the capacity limits are not negative.
+func (c *UserCapacity) Validate() error {
+	if c.MaxOpenTasks < 0 || c.MaxEstimate < 0 {
+		return ErrInvalidCapacity
+	}
+	return nil
+}
+
+// Exceeded reports whether a workload of open tasks and total estimate
+// is over the capacity.
+func (c *UserCapacity) Exceeded(openTasks int, estimate float64) bool {
+	return (c.MaxOpenTasks > 0 && openTasks > c.MaxOpenTasks) ||
+		(c.MaxEstimate > 0 && estimate > c.MaxEstimate)
+}
+
+// Clone returns a deep copy of the user.
+func (u *User) Clone() *User {
+	c := *u
+	c.LastLogin = copyTimePtr(u.LastLogin)
+	c.Capacity = copyPtr(u.Capacity)
+	return &c
 }
 
 // NewUser creates a new user with the given username and email.
@@ -62,13 +117,14 @@ func NewUser(username, email string) (*User, error) {
 
 	now := time.Now()
 	return &User{
-		ID:          uuid.New().String(),
+		ID:          NewUserID(),
 		Username:    username,
 		Email:       email,
 		DisplayName: username,
 		Role:        UserRoleMember,
 		IsActive:    true,
 		CreatedAt:   now,
+		Preferences: DefaultUserPreferences(),
 	}, nil
 }
 
@@ -82,20 +138,23 @@ func ValidateUsername(username string) bool {
 	return usernameRegex.MatchString(username)
 }
 
+// EntityID returns the user's ID.
+func (u *User) EntityID() UserID {
+	return u.ID
+}
+
 // HasPermission checks if the user has a specific permission.
+//
+// The check is evaluated against the default policy without a resource,
+// so resource-scoped rules do not apply. Use Can for resource checks.
 func (u *User) HasPermission(permission string) bool {
-	permissions := map[UserRole]map[string]bool{
-		UserRoleViewer: {"read": true},
-		UserRoleMember: {"read": true, "write": true, "comment": true},
-		UserRoleAdmin:  {"read": true, "write": true, "comment": true, "manage": true},
-		UserRoleOwner:  {"read": true, "write": true, "comment": true, "manage": true, "delete": true},
-	}
+	return CurrentPolicy().Allows(u, permission, nil)
+}
 
-	rolePerms, ok := permissions[u.Role]
-	if !ok {
-		return false
-	}
-	return rolePerms[permission]
+// Can checks if the user may perform an action on a resource under the
+// default policy.
+func (u *User) Can(action string, res *Resource) bool {
+	return CurrentPolicy().Allows(u, action, res)
 }
 
 // PromoteTo promotes the user to a higher role.
@@ -103,13 +162,6 @@ func (u *User) HasPermission(permission string) bool {
 // Returns true if promotion was successful, false if the new role
 // is not higher than the current role.
 func (u *User) PromoteTo(newRole UserRole) bool {
-	roleHierarchy := map[UserRole]int{
-		UserRoleViewer: 0,
-		UserRoleMember: 1,
-		UserRoleAdmin:  2,
-		UserRoleOwner:  3,
-	}
-
 	currentLevel := roleHierarchy[u.Role]
 	newLevel := roleHierarchy[newRole]
 
@@ -120,15 +172,51 @@ func (u *User) PromoteTo(newRole UserRole) bool {
 	return false
 }
 
+// ChangeRole sets the user's role, promoting or demoting as needed.
+//
+// Returns an error if the role is unknown.
+func (u *User) ChangeRole(newRole UserRole) error {
+	if !ValidRole(newRole) {
+		return ErrInvalidRole
+	}
+	u.Role = newRole
+	return nil
+}
+
 // Deactivate deactivates the user account.
 func (u *User) Deactivate() {
 	u.IsActive = false
 }
 
+// Anonymize removes personal data from the user record.
+//
+// The ID is kept so that tasks, comments, and audit entries referring
+// to the user stay consistent; they now resolve to an anonymous user.
+// The account is also deactivated.
+func (u *User) Anonymize() {
+	short := string(u.ID)
+	if len(short) > 8 {
+		short = short[:8]
+	}
+	u.Username = "deleted_" + short
+	u.Email = "deleted_" + short + "@invalid.example.com"
+	u.DisplayName = "Deleted user"
+	u.AvatarURL = ""
+	u.LastLogin = nil
+	u.Preferences = DefaultUserPreferences()
+	u.IsActive = false
+}
+
 // RecordLogin records a login event.
-func (u *User) RecordLogin() {
+//
+// Returns ErrUserInactive if the account has been deactivated.
+func (u *User) RecordLogin() error {
+	if !u.IsActive {
+		return ErrUserInactive
+	}
 	now := time.Now()
 	u.LastLogin = &now
+	return nil
 }
 
 // IsAdmin checks if the user is an admin or owner.
@@ -146,13 +234,14 @@ func CreateGuest(displayName string) *User {
 	now := time.Now()
 
 	return &User{
-		ID:          uuid.New().String(),
+		ID:          NewUserID(),
 		Username:    "guest_" + id,
 		Email:       "guest_" + id + "@example.com",
 		DisplayName: displayName,
 		Role:        UserRoleViewer,
 		IsActive:    true,
 		CreatedAt:   now,
+		Preferences: DefaultUserPreferences(),
 	}
 }
 
@@ -0,0 +1,87 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"slices"
+)
+
+// TaskVisibility controls who may see a task.
+type TaskVisibility string
+
+const (
+	// TaskVisibilityProject makes a task visible to everyone who can see
+	// its project. It is the default.
+	TaskVisibilityProject TaskVisibility = "project"
+	// TaskVisibilityRestricted makes a task visible only to its allowed
+	// users, its creator, assignee and reviewer, and admins.
+	TaskVisibilityRestricted TaskVisibility = "restricted"
+)
+
+// ErrInvalidVisibility is returned for an unknown visibility, or a
+// restricted visibility without any allowed users.
+var ErrInvalidVisibility = errors.New("visibility must be project, or restricted with at least one user")
+
+// SetVisibility sets who may see the task. userIDs lists the users
+// allowed to see a restricted task and must be empty otherwise.
+//
+// Returns ErrInvalidVisibility if the combination is not valid.
+func (t *Task) SetVisibility(visibility TaskVisibility, userIDs []UserID) error {
+	switch visibility {
+	case "", TaskVisibilityProject:
+		if len(userIDs) > 0 {
+			return ErrInvalidVisibility
+		}
+		t.Visibility = ""
+		t.AllowedUserIDs = nil
+	case TaskVisibilityRestricted:
+		if len(userIDs) == 0 {
+			return ErrInvalidVisibility
+		}
+		allowed := make([]UserID, 0, len(userIDs))
+		for _, id := range userIDs {
+			if id != "" && !slices.Contains(allowed, id) {
+				allowed = append(allowed, id)
+			}
+		}
+		if len(allowed) == 0 {
+			return ErrInvalidVisibility
+		}
+		t.Visibility = visibility
+		t.AllowedUserIDs = allowed
+	default:
+		return ErrInvalidVisibility
+	}
+	return nil
+}
+
+// IsRestricted reports whether the task is visible only to some users.
+func (t *Task) IsRestricted() bool {
+	return t.Visibility == TaskVisibilityRestricted
+}
+
+// IsVisibleTo reports whether a user may see the task. A nil user is
+// an anonymous caller.
+//
+// Drafts are visible only to their creator. Restricted tasks are
+// visible to their allowed users, creator, assignee and reviewer, and
+// to admins.
+func (t *Task) IsVisibleTo(user *User) bool {
+	if t.Draft {
+		return user != nil && t.CreatedBy == user.ID
+	}
+	if !t.IsRestricted() {
+		return true
+	}
+	if user == nil {
+		return false
+	}
+	switch {
+	case user.IsAdmin(),
+		t.CreatedBy == user.ID,
+		t.AssigneeID != nil && *t.AssigneeID == user.ID,
+		t.ReviewerID != nil && *t.ReviewerID == user.ID:
+		return true
+	}
+	return slices.Contains(t.AllowedUserIDs, user.ID)
+}
@@ -0,0 +1,205 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// webhookFieldRegex matches {path.to.field} placeholders in webhook
+// source templates.
+var webhookFieldRegex = regexp.MustCompile(`\{([A-Za-z0-9_\-]+(?:\.[A-Za-z0-9_\-]+)*)\}`)
+
+// ErrInvalidWebhookSource is returned when a webhook source is malformed.
+var ErrInvalidWebhookSource = errors.New("invalid webhook source")
+
+// ErrWebhookPayload is returned when a payload lacks a field a webhook
+// source's title or key template needs.
+var ErrWebhookPayload = errors.New("payload does not match webhook source")
+
+// WebhookSource maps the payloads an external system, such as CI or
+// monitoring, posts to an inbound webhook onto tasks in a project.
+//
+// TitleTemplate, DescriptionTemplate and KeyTemplate may contain
+// {path.to.field} placeholders naming fields of the JSON payload. A
+// payload whose rendered key matches an earlier payload's updates that
+// task instead of creating another, so an alert and its resolution land
+// on one task. StatusField names a payload field whose values
+// StatusMap turns into task statuses.
+//
+// Payloads must be signed with an HMAC-SHA256 of the body under Secret,
+// hex encoded in SignatureHeader, optionally after SignaturePrefix, as
+// GitHub does with X-Hub-Signature-256 and "sha256=".
+type WebhookSource struct {
+	ID                  string                `json:"id"`
+	Name                string                `json:"name"`
+	ProjectID           ProjectID             `json:"project_id"`
+	Secret              string                `json:"-"`
+	SignatureHeader     string                `json:"signature_header"`
+	SignaturePrefix     string                `json:"signature_prefix,omitempty"`
+	TitleTemplate       string                `json:"title_template"`
+	DescriptionTemplate string                `json:"description_template,omitempty"`
+	KeyTemplate         string                `json:"key_template,omitempty"`
+	StatusField         string                `json:"status_field,omitempty"`
+	StatusMap           map[string]TaskStatus `json:"status_map,omitempty"`
+	Tags                []string              `json:"tags,omitempty"`
+	Enabled             bool                  `json:"enabled"`
+	CreatedAt           time.Time             `json:"created_at"`
+}
+
+// NewWebhookSource creates an enabled source with a fresh secret,
+// signed with X-Hub-Signature-256 by default.
+func NewWebhookSource(projectID ProjectID, name, titleTemplate string) (*WebhookSource, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+
+	return &WebhookSource{
+		ID:              uuid.New().String(),
+		Name:            name,
+		ProjectID:       projectID,
+		Secret:          hex.EncodeToString(buf),
+		SignatureHeader: "X-Hub-Signature-256",
+		SignaturePrefix: "sha256=",
+		TitleTemplate:   titleTemplate,
+		Enabled:         true,
+		CreatedAt:       time.Now(),
+	}, nil
+}
+
+// EntityID returns the source's ID.
+func (s *WebhookSource) EntityID() string {
+	return s.ID
+}
+
+// Clone returns a deep copy of the webhook source.
+func (s *WebhookSource) Clone() *WebhookSource {
+	c := *s
+	c.StatusMap = maps.Clone(s.StatusMap)
+	c.Tags = slices.Clone(s.Tags)
+	return &c
+}
+
+// Validate checks that the source can sign and map payloads, and that
+// its status map names well-formed statuses. Tasks reach awaiting
+// review only through approval, so no payload may map to it.
+//
+// Returns an error wrapping ErrInvalidWebhookSource.
+func (s *WebhookSource) Validate() error {
+	switch {
+	case strings.TrimSpace(s.Name) == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidWebhookSource)
+	case s.ProjectID == "":
+		return fmt.Errorf("%w: project is required", ErrInvalidWebhookSource)
+	case strings.TrimSpace(s.TitleTemplate) == "":
+		return fmt.Errorf("%w: title template is required", ErrInvalidWebhookSource)
+	case s.SignatureHeader == "":
+		return fmt.Errorf("%w: signature header is required", ErrInvalidWebhookSource)
+	case len(s.StatusMap) > 0 && s.StatusField == "":
+		return fmt.Errorf("%w: status map needs a status field", ErrInvalidWebhookSource)
+	}
+	for value, status := range s.StatusMap {
+		if !statusNameRegex.MatchString(string(status)) || status == TaskStatusAwaitingReview {
+			return fmt.Errorf("%w: invalid status %q for %q", ErrInvalidWebhookSource, status, value)
+		}
+	}
+	return nil
+}
+
+// WebhookMapping is what a payload maps to under a webhook source.
+// Key is empty if the source does not deduplicate, and Status is empty
+// if the payload's status is not mapped.
+type WebhookMapping struct {
+	Title       string
+	Description string
+	Key         string
+	Status      TaskStatus
+}
+
+// Map renders the source's templates against a decoded JSON payload.
+//
+// Returns an error wrapping ErrWebhookPayload if the title or key
+// template names a field the payload lacks; missing description fields
+// render as empty.
+func (s *WebhookSource) Map(payload map[string]any) (*WebhookMapping, error) {
+	title, err := renderWebhookTemplate(s.TitleTemplate, payload, true)
+	if err != nil {
+		return nil, err
+	}
+	key, err := renderWebhookTemplate(s.KeyTemplate, payload, true)
+	if err != nil {
+		return nil, err
+	}
+	description, _ := renderWebhookTemplate(s.DescriptionTemplate, payload, false)
+
+	m := &WebhookMapping{Title: strings.TrimSpace(title), Description: description, Key: key}
+	if s.StatusField != "" {
+		if v, ok := lookupPayloadField(payload, s.StatusField); ok {
+			m.Status = s.StatusMap[v]
+		}
+	}
+	return m, nil
+}
+
+// renderWebhookTemplate fills a template's placeholders from payload.
+// With strict set, a missing field is an error.
+func renderWebhookTemplate(template string, payload map[string]any, strict bool) (string, error) {
+	var missing []string
+	out := webhookFieldRegex.ReplaceAllStringFunc(template, func(match string) string {
+		path := match[1 : len(match)-1]
+		v, ok := lookupPayloadField(payload, path)
+		if !ok {
+			missing = append(missing, path)
+		}
+		return v
+	})
+	if strict && len(missing) > 0 {
+		return "", fmt.Errorf("%w: missing %s", ErrWebhookPayload, strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+// lookupPayloadField returns the scalar at a dotted path in payload,
+// formatted as text. Array elements are addressed by index.
+func lookupPayloadField(payload map[string]any, path string) (string, bool) {
+	var v any = payload
+	for _, part := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			child, ok := node[part]
+			if !ok {
+				return "", false
+			}
+			v = child
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return "", false
+			}
+			v = node[i]
+		default:
+			return "", false
+		}
+	}
+
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
@@ -0,0 +1,196 @@
+// Package models provides data models for the TaskTracker application.
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// ErrInvalidWorkflow is returned when a workflow definition is malformed.
+var ErrInvalidWorkflow = errors.New("invalid workflow definition")
+
+// ErrUnknownStatus is returned when a task is given a status its
+// project's workflow does not define.
+var ErrUnknownStatus = errors.New("status is not defined by the project workflow")
+
+// ErrTransitionNotAllowed is returned when a task's status changes in a
+// way its project's workflow does not allow.
+var ErrTransitionNotAllowed = errors.New("status transition is not allowed by the project workflow")
+
+// ErrWIPLimitReached is returned when a task would enter a status that
+// already holds as many tasks as its work-in-progress limit allows.
+var ErrWIPLimitReached = errors.New("work-in-progress limit reached")
+
+// WIPLimitError reports which limit a task would exceed. It wraps
+// ErrWIPLimitReached.
+type WIPLimitError struct {
+	Status TaskStatus
+	Limit  int
+	Count  int
+}
+
+func (e *WIPLimitError) Error() string {
+	return fmt.Sprintf("%s: %s already has %d of %d tasks", ErrWIPLimitReached, e.Status, e.Count, e.Limit)
+}
+
+func (e *WIPLimitError) Unwrap() error {
+	return ErrWIPLimitReached
+}
+
+// WIPEnforcement decides what happens when a task would exceed a
+// status's work-in-progress limit.
+type WIPEnforcement string
+
+const (
+	// WIPEnforcementReject refuses the change. It is the default.
+	WIPEnforcementReject WIPEnforcement = "reject"
+	// WIPEnforcementWarn allows the change with a warning.
+	WIPEnforcementWarn WIPEnforcement = "warn"
+)
+
+// statusNameRegex constrains custom status names to lowercase
+// identifiers, like the built-in statuses.
+var statusNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// WorkflowStatus is a status a task in a project can have.
+//
+// WIPLimit caps how many of the project's tasks may be in the status at
+// once; zero means no limit.
+type WorkflowStatus struct {
+	Name     TaskStatus `json:"name"`
+	Label    string     `json:"label,omitempty"`
+	WIPLimit int        `json:"wip_limit,omitempty"`
+}
+
+// WorkflowTransition allows tasks to move from one status to another.
+type WorkflowTransition struct {
+	From TaskStatus `json:"from"`
+	To   TaskStatus `json:"to"`
+}
+
+// Workflow defines the statuses of a project's tasks and the
+// transitions between them.
+//
+// New tasks start in Initial. Custom statuses count as open; tasks are
+// closed only by moving to completed or cancelled, so every workflow
+// must define completed. WIPEnforcement applies to every status with a
+// WIPLimit; empty means reject.
+type Workflow struct {
+	ProjectID      ProjectID            `json:"project_id"`
+	Initial        TaskStatus           `json:"initial"`
+	Statuses       []WorkflowStatus     `json:"statuses"`
+	Transitions    []WorkflowTransition `json:"transitions"`
+	WIPEnforcement WIPEnforcement       `json:"wip_enforcement,omitempty"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+}
+
+// builtinStatuses lists the built-in statuses in board order.
+var builtinStatuses = []TaskStatus{
+	TaskStatusPending,
+	TaskStatusInProgress,
+	TaskStatusBlocked,
+	TaskStatusCompleted,
+	TaskStatusCancelled,
+}
+
+// DefaultWorkflow returns the workflow used by projects without a custom
+// one: the built-in statuses, with any change between them allowed.
+func DefaultWorkflow(projectID ProjectID) *Workflow {
+	w := &Workflow{ProjectID: projectID, Initial: TaskStatusPending}
+	for _, from := range builtinStatuses {
+		w.Statuses = append(w.Statuses, WorkflowStatus{Name: from})
+		for _, to := range builtinStatuses {
+			if from != to {
+				w.Transitions = append(w.Transitions, WorkflowTransition{From: from, To: to})
+			}
+		}
+	}
+	return w
+}
+
+// Validate checks that status names are well formed and unique, that
+// completed is defined, that the initial status and transitions refer
+// to defined statuses, and that WIP limits are not negative.
+func (w *Workflow) Validate() error {
+	seen := make(map[TaskStatus]bool, len(w.Statuses))
+	for _, s := range w.Statuses {
+		if !statusNameRegex.MatchString(string(s.Name)) || seen[s.Name] || s.WIPLimit < 0 {
+			return ErrInvalidWorkflow
+		}
+		seen[s.Name] = true
+	}
+	switch w.WIPEnforcement {
+	case "", WIPEnforcementReject, WIPEnforcementWarn:
+	default:
+		return ErrInvalidWorkflow
+	}
+	if !seen[TaskStatusCompleted] || !seen[w.Initial] {
+		return ErrInvalidWorkflow
+	}
+	for _, t := range w.Transitions {
+		if !seen[t.From] || !seen[t.To] || t.From == t.To {
+			return ErrInvalidWorkflow
+		}
+	}
+	return nil
+}
+
+// HasStatus checks if the workflow defines a status.
+func (w *Workflow) HasStatus(status TaskStatus) bool {
+	for _, s := range w.Statuses {
+		if s.Name == status {
+			return true
+		}
+	}
+	return false
+}
+
+// WIPLimit returns the work-in-progress limit of a status, or zero if
+// it has none.
+func (w *Workflow) WIPLimit(status TaskStatus) int {
+	for _, s := range w.Statuses {
+		if s.Name == status {
+			return s.WIPLimit
+		}
+	}
+	return 0
+}
+
+// Allows checks if a task may move from one status to another. Keeping
+// the same status is always allowed.
+func (w *Workflow) Allows(from, to TaskStatus) bool {
+	if from == to {
+		return w.HasStatus(to)
+	}
+	for _, t := range w.Transitions {
+		if t.From == from && t.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the statuses a task may move to from a status.
+func (w *Workflow) Next(from TaskStatus) []TaskStatus {
+	next := make([]TaskStatus, 0)
+	for _, t := range w.Transitions {
+		if t.From == from {
+			next = append(next, t.To)
+		}
+	}
+	return next
+}
+
+// CheckTransition returns ErrUnknownStatus if to is not defined and
+// ErrTransitionNotAllowed if the move from from is not allowed.
+func (w *Workflow) CheckTransition(from, to TaskStatus) error {
+	if !w.HasStatus(to) {
+		return ErrUnknownStatus
+	}
+	if !w.Allows(from, to) {
+		return ErrTransitionNotAllowed
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+// Package pdf writes simple PDF documents for server-side reports.
+//
+// Pages are drawn with text in the standard Helvetica fonts, lines,
+// polylines and filled rectangles. Coordinates are in points from the
+// bottom-left corner of the page, as in PDF itself. Text is encoded as
+// WinAnsi; characters outside it print as "?". No fonts or images are
+// embedded, which keeps documents small and the package dependency free.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page size in points.
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// Color is an RGB color with components from 0 to 1.
+type Color struct {
+	R, G, B float64
+}
+
+// Common colors.
+var (
+	Black = Color{0, 0, 0}
+	Gray  = Color{0.5, 0.5, 0.5}
+	Red   = Color{0.8, 0.15, 0.15}
+	Blue  = Color{0.15, 0.39, 0.92}
+)
+
+// Font selects one of the standard fonts.
+type Font int
+
+const (
+	// Regular is Helvetica.
+	Regular Font = iota
+	// Bold is Helvetica-Bold.
+	Bold
+)
+
+// Document is a PDF being built.
+type Document struct {
+	Title  string
+	pages  []*Page
+	width  float64
+	height float64
+}
+
+// New creates an empty document with pages of the given size.
+func New(width, height float64) *Document {
+	return &Document{width: width, height: height}
+}
+
+// Page is a page being drawn.
+type Page struct {
+	content bytes.Buffer
+}
+
+// AddPage appends a blank page.
+func (d *Document) AddPage() *Page {
+	p := &Page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Size returns the page width and height.
+func (d *Document) Size() (width, height float64) {
+	return d.width, d.height
+}
+
+// Text draws s with its baseline starting at x, y.
+func (p *Page) Text(x, y float64, font Font, size float64, color Color, s string) {
+	fmt.Fprintf(&p.content, "BT %s rg /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		color.components(), font+1, num(size), num(x), num(y), escapeText(s))
+}
+
+// Line draws a straight line.
+func (p *Page) Line(x1, y1, x2, y2, width float64, color Color) {
+	fmt.Fprintf(&p.content, "%s RG %s w %s %s m %s %s l S\n",
+		color.components(), num(width), num(x1), num(y1), num(x2), num(y2))
+}
+
+// Polyline draws connected line segments through points given as
+// x, y pairs.
+func (p *Page) Polyline(points [][2]float64, width float64, color Color) {
+	if len(points) < 2 {
+		return
+	}
+	fmt.Fprintf(&p.content, "%s RG %s w 1 j ", color.components(), num(width))
+	for i, pt := range points {
+		op := "l"
+		if i == 0 {
+			op = "m"
+		}
+		fmt.Fprintf(&p.content, "%s %s %s ", num(pt[0]), num(pt[1]), op)
+	}
+	p.content.WriteString("S\n")
+}
+
+// Rect fills a rectangle whose bottom-left corner is at x, y.
+func (p *Page) Rect(x, y, width, height float64, color Color) {
+	fmt.Fprintf(&p.content, "%s rg %s %s %s %s re f\n",
+		color.components(), num(x), num(y), num(width), num(height))
+}
+
+// TextWidth estimates the width of s in points. It uses average glyph
+// widths by character class, close enough for wrapping and alignment.
+func TextWidth(s string, font Font, size float64) float64 {
+	var em float64
+	for _, r := range s {
+		switch {
+		case r == ' ' || strings.ContainsRune(".,;:!|'il", r):
+			em += 0.278
+		case r >= '0' && r <= '9':
+			em += 0.556
+		case r >= 'A' && r <= 'Z':
+			em += 0.667
+		case r == 'm' || r == 'w':
+			em += 0.833
+		default:
+			em += 0.52
+		}
+	}
+	if font == Bold {
+		em *= 1.06
+	}
+	return em * size
+}
+
+// Truncate shortens s with an ellipsis to fit within width points.
+func Truncate(s string, font Font, size, width float64) string {
+	if TextWidth(s, font, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && TextWidth(string(runes)+"...", font, size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// Write writes the document. A document without pages gets one blank page.
+func (d *Document) Write(w io.Writer) error {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-5 are the catalog, page tree, fonts and info; each page
+	// then takes two objects, the page and its content stream.
+	const firstPage = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	obj(fmt.Sprintf("<< /Title (%s) /Producer (TaskTracker) >>", escapeText(d.Title)))
+	for i, page := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			num(d.width), num(d.height), firstPage+2*i+1))
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		if _, err := zw.Write(page.content.Bytes()); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		obj(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", z.Len(), z.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// components formats the color for a color operator.
+func (c Color) components() string {
+	return num(c.R) + " " + num(c.G) + " " + num(c.B)
+}
+
+// num formats a number compactly with at most two decimals.
+func num(f float64) string {
+	s := strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", f), "0"), ".")
+	if s == "-0" || s == "" {
+		return "0"
+	}
+	return s
+}
+
+// escapeText encodes s as a WinAnsi PDF string body.
+func escapeText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n' || r == '\t':
+			b.WriteByte(' ')
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			if c, ok := winAnsiExtras[r]; ok {
+				fmt.Fprintf(&b, "\\%03o", c)
+			} else {
+				b.WriteByte('?')
+			}
+		}
+	}
+	return b.String()
+}
+
+// winAnsiExtras maps the printable characters WinAnsi places in the
+// 0x80-0x9f range.
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
@@ -0,0 +1,410 @@
+// Package xlsx writes simple Office Open XML spreadsheets.
+//
+// It covers what exports need and no more: several sheets of rows with
+// strings, numbers, booleans, dates and percentages, a bold frozen
+// header row with an autofilter, and column widths fitted to content.
+// Strings are written inline, so a cell is never read as a formula.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Limits imposed by spreadsheet applications.
+const (
+	MaxSheetNameLength = 31
+	MaxCellLength      = 32767
+	maxColumnWidth     = 60
+)
+
+// ErrDuplicateSheet is returned when two sheets would have the same name.
+var ErrDuplicateSheet = errors.New("duplicate sheet name")
+
+// style indexes the cell formats written to styles.xml.
+type style int
+
+const (
+	styleDefault style = iota
+	styleHeader
+	styleDate
+	styleDateTime
+	stylePercent
+	styleDecimal
+)
+
+// Cell is a value with an explicit format, for values whose Go type
+// does not determine how they display.
+type Cell struct {
+	value any
+	style style
+}
+
+// Date formats t's calendar date, as it reads in t's location.
+func Date(t time.Time) Cell {
+	return Cell{value: t, style: styleDate}
+}
+
+// DateTime formats t's date and time, as they read in t's location.
+func DateTime(t time.Time) Cell {
+	return Cell{value: t, style: styleDateTime}
+}
+
+// Percent formats a fraction, such as 0.25, as a percentage.
+func Percent(f float64) Cell {
+	return Cell{value: f, style: stylePercent}
+}
+
+// Workbook is a spreadsheet being built.
+type Workbook struct {
+	sheets []*Sheet
+	names  map[string]bool
+}
+
+// New creates an empty workbook.
+func New() *Workbook {
+	return &Workbook{names: make(map[string]bool)}
+}
+
+// Sheet is a worksheet being built.
+type Sheet struct {
+	name   string
+	header bool
+	rows   [][]Cell
+	widths []int
+}
+
+// AddSheet appends a sheet. Characters spreadsheet applications reject
+// are replaced and the name is truncated to MaxSheetNameLength.
+//
+// Returns ErrDuplicateSheet if the name, ignoring case, is taken.
+func (wb *Workbook) AddSheet(name string) (*Sheet, error) {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if utf8.RuneCountInString(name) > MaxSheetNameLength {
+		name = string([]rune(name)[:MaxSheetNameLength])
+	}
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", len(wb.sheets)+1)
+	}
+	key := strings.ToLower(name)
+	if wb.names[key] {
+		return nil, fmt.Errorf("%w: %s", ErrDuplicateSheet, name)
+	}
+	wb.names[key] = true
+	sheet := &Sheet{name: name}
+	wb.sheets = append(wb.sheets, sheet)
+	return sheet, nil
+}
+
+// SetHeader writes the header row, which is bold, stays in view while
+// scrolling and carries filter buttons. It must be called before rows
+// are added.
+func (s *Sheet) SetHeader(titles ...string) {
+	row := make([]Cell, len(titles))
+	for i, title := range titles {
+		row[i] = Cell{value: title, style: styleHeader}
+	}
+	s.header = true
+	s.rows = append([][]Cell{row}, s.rows...)
+	s.fit(row)
+}
+
+// AddRow appends a row. Values may be strings, integers, floats, bools,
+// time.Time (written as DateTime), Cells, or nil for an empty cell.
+func (s *Sheet) AddRow(values ...any) {
+	row := make([]Cell, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case Cell:
+			row[i] = v
+		case time.Time:
+			row[i] = DateTime(v)
+		case float32, float64:
+			row[i] = Cell{value: v, style: styleDecimal}
+		default:
+			row[i] = Cell{value: v}
+		}
+	}
+	s.rows = append(s.rows, row)
+	s.fit(row)
+}
+
+// fit widens columns to the text length of row's cells.
+func (s *Sheet) fit(row []Cell) {
+	for i, c := range row {
+		for len(s.widths) <= i {
+			s.widths = append(s.widths, 8)
+		}
+		n := 0
+		switch v := c.value.(type) {
+		case string:
+			n = utf8.RuneCountInString(v)
+		case time.Time:
+			n = 16
+		case nil:
+		default:
+			n = len(fmt.Sprint(v))
+		}
+		if c.style == styleHeader {
+			n += 4 // room for the filter button
+		}
+		if n+2 > s.widths[i] {
+			s.widths[i] = min(n+2, maxColumnWidth)
+		}
+	}
+}
+
+// Write writes the workbook as an .xlsx file.
+func (wb *Workbook) Write(w io.Writer) error {
+	if len(wb.sheets) == 0 {
+		if _, err := wb.AddSheet("Sheet1"); err != nil {
+			return err
+		}
+	}
+
+	z := zip.NewWriter(w)
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", wb.contentTypes()},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", wb.workbook()},
+		{"xl/_rels/workbook.xml.rels", wb.workbookRels()},
+		{"xl/styles.xml", stylesXML},
+	}
+	for _, f := range files {
+		if err := writeZipFile(z, f.name, f.content); err != nil {
+			return err
+		}
+	}
+	for i, sheet := range wb.sheets {
+		if err := writeZipFile(z, fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), sheet.xml()); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
+
+// writeZipFile adds one compressed file to the archive.
+func writeZipFile(z *zip.Writer, name, content string) error {
+	f, err := z.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(f, content)
+	return err
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+const rootRels = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// stylesXML defines the cell formats in style order: default, header,
+// date, date and time, percent and decimal.
+const stylesXML = xmlHeader + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="2"><numFmt numFmtId="164" formatCode="yyyy-mm-dd"/><numFmt numFmtId="165" formatCode="yyyy-mm-dd hh:mm"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="3"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill>` +
+	`<fill><patternFill patternType="solid"><fgColor rgb="FFD9E1F2"/><bgColor indexed="64"/></patternFill></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="6">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="2" borderId="0" xfId="0" applyFont="1" applyFill="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="9" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="2" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
+
+// contentTypes lists the part types of the package.
+func (wb *Workbook) contentTypes() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := range wb.sheets {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+// workbook lists the sheets, defining each header's filter range.
+func (wb *Workbook) workbook() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, sheet := range wb.sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(sheet.name), i+1, i+1)
+	}
+	b.WriteString(`</sheets>`)
+	var names strings.Builder
+	for i, sheet := range wb.sheets {
+		if ref := sheet.filterRef(); ref != "" {
+			quoted := "'" + strings.ReplaceAll(sheet.name, "'", "''") + "'"
+			fmt.Fprintf(&names, `<definedName name="_xlnm._FilterDatabase" localSheetId="%d" hidden="1">%s!%s</definedName>`,
+				i, escape(quoted), absoluteRef(ref))
+		}
+	}
+	if names.Len() > 0 {
+		b.WriteString(`<definedNames>` + names.String() + `</definedNames>`)
+	}
+	b.WriteString(`</workbook>`)
+	return b.String()
+}
+
+// workbookRels links the workbook to its sheets and styles.
+func (wb *Workbook) workbookRels() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := range wb.sheets {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(wb.sheets)+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// filterRef returns the range covered by the header's filter, or "" for
+// a sheet without a header.
+func (s *Sheet) filterRef() string {
+	if !s.header || len(s.widths) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("A1:%s%d", columnName(len(s.widths)-1), len(s.rows))
+}
+
+// xml renders the worksheet.
+func (s *Sheet) xml() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if s.header {
+		b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	}
+	if len(s.widths) > 0 {
+		b.WriteString(`<cols>`)
+		for i, width := range s.widths {
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width)
+		}
+		b.WriteString(`</cols>`)
+	}
+	b.WriteString(`<sheetData>`)
+	for r, row := range s.rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, cell := range row {
+			writeCell(&b, columnName(c)+strconv.Itoa(r+1), cell)
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData>`)
+	if ref := s.filterRef(); ref != "" {
+		fmt.Fprintf(&b, `<autoFilter ref="%s"/>`, ref)
+	}
+	b.WriteString(`</worksheet>`)
+	return b.String()
+}
+
+// writeCell renders one cell. Empty cells are omitted.
+func writeCell(b *strings.Builder, ref string, cell Cell) {
+	attrs := fmt.Sprintf(`r="%s"`, ref)
+	if cell.style != styleDefault {
+		attrs += fmt.Sprintf(` s="%d"`, cell.style)
+	}
+	switch v := cell.value.(type) {
+	case nil:
+		if cell.style == styleHeader {
+			fmt.Fprintf(b, `<c %s/>`, attrs)
+		}
+	case string:
+		if utf8.RuneCountInString(v) > MaxCellLength {
+			v = string([]rune(v)[:MaxCellLength])
+		}
+		fmt.Fprintf(b, `<c %s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, attrs, escape(v))
+	case bool:
+		n := 0
+		if v {
+			n = 1
+		}
+		fmt.Fprintf(b, `<c %s t="b"><v>%d</v></c>`, attrs, n)
+	case time.Time:
+		fmt.Fprintf(b, `<c %s><v>%s</v></c>`, attrs, strconv.FormatFloat(serialDate(v), 'f', -1, 64))
+	case float64:
+		fmt.Fprintf(b, `<c %s><v>%s</v></c>`, attrs, strconv.FormatFloat(v, 'f', -1, 64))
+	case float32:
+		fmt.Fprintf(b, `<c %s><v>%s</v></c>`, attrs, strconv.FormatFloat(float64(v), 'f', -1, 32))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		fmt.Fprintf(b, `<c %s><v>%d</v></c>`, attrs, v)
+	default:
+		fmt.Fprintf(b, `<c %s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, attrs, escape(fmt.Sprint(v)))
+	}
+}
+
+// excelEpoch is day zero of spreadsheet date serials.
+var excelEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+// serialDate converts t's wall-clock time to a date serial, the number
+// of days since excelEpoch.
+func serialDate(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	return wall.Sub(excelEpoch).Hours() / 24
+}
+
+// columnName returns the letters naming a zero-based column: A, ..., Z, AA.
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// absoluteRef turns a range such as A1:C9 into $A$1:$C$9.
+func absoluteRef(ref string) string {
+	var b strings.Builder
+	letters := false
+	for _, r := range ref {
+		isLetter := r >= 'A' && r <= 'Z'
+		if isLetter && !letters || !isLetter && letters {
+			b.WriteByte('$')
+		}
+		if r == ':' {
+			letters = false
+			b.WriteRune(r)
+			continue
+		}
+		letters = isLetter
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// escape escapes text for XML content and attributes. Characters XML
+// cannot carry are replaced.
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}